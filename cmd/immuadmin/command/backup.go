@@ -91,7 +91,11 @@ func newCommandlineBck(os immuos.OS) (*commandlineBck, error) {
 
 func (clb *commandlineBck) Register(rootCmd *cobra.Command) *cobra.Command {
 	clb.dumpToFile(rootCmd)
+	clb.dumpKeyHistory(rootCmd)
+	clb.verifyKeyHistory(rootCmd)
 	clb.backup(rootCmd)
+	clb.hotBackup(rootCmd)
+	clb.hotRestore(rootCmd)
 	clb.restore(rootCmd)
 	return rootCmd
 }
@@ -150,6 +154,55 @@ func (cl *commandlineBck) dumpToFile(cmd *cobra.Command) {
 	cmd.AddCommand(ccmd)
 }
 
+func (cl *commandlineBck) hotBackup(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:   "hot-backup",
+		Short: "Take a consistent snapshot of the current database without stopping writes",
+		Long: "Take a consistent snapshot of the current database while it keeps accepting writes. The snapshot is " +
+			"saved on the server machine, in the directory set by the backup-dir option of immudb, along with the root " +
+			"the database had when it was taken: the entries written meanwhile are left out.",
+		PersistentPreRunE: cl.ConfigChain(cl.checkLoggedInAndConnect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info, err := cl.immuClient.Backup(cl.context)
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "SUCCESS: database %s backed up to %s (%d bytes)\n", info.DatabaseName, info.File, info.Size)
+			fmt.Fprintf(cmd.OutOrStdout(), "Root at index %d: %x\n", info.Root.GetIndex(), info.Root.GetRoot())
+			return nil
+		},
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(ccmd)
+}
+
+func (cl *commandlineBck) hotRestore(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:   "hot-restore snapshot database",
+		Short: "Create a new database from a snapshot taken by hot-backup, verifying it against its root",
+		Long: "Create a new database from a snapshot taken by hot-backup, without stopping the server. The snapshot " +
+			"is the path of its file on the server machine, within the directory set by the backup-dir option of immudb. " +
+			"The merkle tree is rebuilt from the entries of the snapshot, and the database is made available only if " +
+			"the recomputed root matches the one recorded when the snapshot was taken.",
+		PersistentPreRunE: cl.ConfigChain(cl.checkLoggedInAndConnect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info, err := cl.immuClient.RestoreBackup(cl.context, args[0], args[1])
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "SUCCESS: database %s restored from %s\n", info.DatabaseName, info.File)
+			fmt.Fprintf(cmd.OutOrStdout(), "Root verified at index %d: %x\n", info.Root.GetIndex(), info.Root.GetRoot())
+			return nil
+		},
+		Args: cobra.ExactArgs(2),
+	}
+	cmd.AddCommand(ccmd)
+}
+
 func (cl *commandlineBck) backup(cmd *cobra.Command) {
 	defaultDbDir := server.DefaultOptions().Dir
 	ccmd := &cobra.Command{
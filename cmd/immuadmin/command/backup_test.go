@@ -17,6 +17,7 @@ limitations under the License.
 package immuadmin
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -219,6 +220,92 @@ func TestDumpToFile(t *testing.T) {
 	require.Equal(t, fmt.Sprintf("SUCCESS: 1 key-value entries were backed-up to file %s\n", dumpFile), dumpLog)
 }
 
+func TestHotBackup(t *testing.T) {
+	clb, err := newCommandlineBck(immuos.NewStandardOS())
+	require.NoError(t, err)
+	clb.options = client.DefaultOptions()
+
+	immuClientMock := &clienttest.ImmuClientMock{}
+	clb.immuClient = immuClientMock
+	immuClientMock.DisconnectF = func() error {
+		return nil
+	}
+	immuClientMock.BackupF = func(ctx context.Context) (*schema.BackupInfo, error) {
+		return &schema.BackupInfo{
+			DatabaseName: "defaultdb",
+			File:         "backups/defaultdb/20201113T005342Z_41.snapshot",
+			Root:         &schema.Root{Payload: &schema.RootIndex{Index: 41, Root: []byte{0xab, 0xcd}}},
+			Size:         1024,
+		}, nil
+	}
+
+	cl := commandline{}
+	cmd, _ := cl.NewCmd()
+	clb.hotBackup(cmd)
+	cmd.PersistentPreRunE = nil
+	cmd.Commands()[0].PersistentPreRunE = nil
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"hot-backup"})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "SUCCESS: database defaultdb backed up to backups/defaultdb/20201113T005342Z_41.snapshot (1024 bytes)\n"+
+		"Root at index 41: abcd\n", b.String())
+
+	errBackup := errors.New("backup error")
+	immuClientMock.BackupF = func(ctx context.Context) (*schema.BackupInfo, error) {
+		return nil, errBackup
+	}
+	clb.onError = func(msg interface{}) {
+		require.Equal(t, errBackup, msg)
+	}
+	require.NoError(t, cmd.Execute())
+}
+
+func TestHotRestore(t *testing.T) {
+	clb, err := newCommandlineBck(immuos.NewStandardOS())
+	require.NoError(t, err)
+	clb.options = client.DefaultOptions()
+
+	immuClientMock := &clienttest.ImmuClientMock{}
+	clb.immuClient = immuClientMock
+	immuClientMock.DisconnectF = func() error {
+		return nil
+	}
+	immuClientMock.RestoreBackupF = func(ctx context.Context, file string, databaseName string) (*schema.BackupInfo, error) {
+		require.Equal(t, "defaultdb/20201113T005342Z_41.snapshot", file)
+		require.Equal(t, "restored", databaseName)
+		return &schema.BackupInfo{
+			DatabaseName: databaseName,
+			File:         "/backups/" + file,
+			Root:         &schema.Root{Payload: &schema.RootIndex{Index: 41, Root: []byte{0xab, 0xcd}}},
+			Size:         1024,
+		}, nil
+	}
+
+	cl := commandline{}
+	cmd, _ := cl.NewCmd()
+	clb.hotRestore(cmd)
+	cmd.PersistentPreRunE = nil
+	cmd.Commands()[0].PersistentPreRunE = nil
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"hot-restore", "defaultdb/20201113T005342Z_41.snapshot", "restored"})
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "SUCCESS: database restored restored from /backups/defaultdb/20201113T005342Z_41.snapshot\n"+
+		"Root verified at index 41: abcd\n", b.String())
+
+	errRestore := errors.New("restore error")
+	immuClientMock.RestoreBackupF = func(ctx context.Context, file string, databaseName string) (*schema.BackupInfo, error) {
+		return nil, errRestore
+	}
+	clb.onError = func(msg interface{}) {
+		require.Equal(t, errRestore, msg)
+	}
+	require.NoError(t, cmd.Execute())
+}
+
 func deleteBackupFiles(prefix string) {
 	files, _ := filepath.Glob(fmt.Sprintf("./%s_bkp_*", prefix))
 	for _, f := range files {
@@ -35,6 +35,7 @@ type Commandline interface {
 	stats(cmd *cobra.Command)
 	serverConfig(cmd *cobra.Command)
 	printTree(rootCmd *cobra.Command)
+	logs(cmd *cobra.Command)
 	database(cmd *cobra.Command)
 	ConfigChain(post func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) (err error)
 }
@@ -93,6 +94,9 @@ func (cl *commandline) Register(rootCmd *cobra.Command) *cobra.Command {
 	cl.serverConfig(rootCmd)
 	cl.database(rootCmd)
 	cl.printTree(rootCmd)
+	cl.logs(rootCmd)
+	cl.storage(rootCmd)
+	cl.setup(rootCmd)
 	return rootCmd
 }
 
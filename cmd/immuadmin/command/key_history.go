@@ -0,0 +1,95 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/golang/protobuf/proto"
+	"github.com/spf13/cobra"
+)
+
+func (cl *commandlineBck) dumpKeyHistory(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "dump-key-history key [file]",
+		Short:             "Dump all the revisions of a key, along with their inclusion proofs, to a file",
+		Long:              "Dump all the revisions of a key to a portable file which includes, for each revision, the inclusion proof against the same (signed, if the server has a signing key) root. The file can be verified offline with verify-key-history.",
+		PersistentPreRunE: cl.ConfigChain(cl.checkLoggedInAndConnect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filename := fmt.Sprint("immudb_key_history_" + time.Now().Format("2006-01-02_15-04-05") + ".dump")
+			if len(args) > 1 {
+				filename = args[1]
+			}
+			dump, err := cl.immuClient.DumpKeyHistory(cl.context, []byte(args[0]))
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			dumpBytes, err := proto.Marshal(dump)
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			if err = cl.os.WriteFile(filename, dumpBytes, 0644); err != nil {
+				cl.quit(err)
+				return nil
+			}
+			fmt.Printf("SUCCESS: %d revisions of key %s were dumped to file %s\n", len(dump.Entries), args[0], filename)
+			return nil
+		},
+		Args: cobra.RangeArgs(1, 2),
+	}
+	cmd.AddCommand(ccmd)
+}
+
+func (cl *commandlineBck) verifyKeyHistory(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:   "verify-key-history file",
+		Short: "Verify offline a key history file created with dump-key-history",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dumpBytes, err := cl.os.ReadFile(args[0])
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			dump := &schema.KeyHistoryDump{}
+			if err = proto.Unmarshal(dumpBytes, dump); err != nil {
+				cl.quit(err)
+				return nil
+			}
+			if err = dump.Verify(); err != nil {
+				cl.quit(err)
+				return nil
+			}
+			fmt.Printf("SUCCESS: %d revisions of key %s verified against root %x at index %d\n",
+				len(dump.Entries), dump.Key, dump.Root.GetRoot(), dump.Root.GetIndex())
+			if dump.Root.GetSignature() != nil {
+				if ok, err := dump.Root.CheckSignature(); err != nil || !ok {
+					fmt.Println("WARNING: root signature is not valid")
+				} else {
+					fmt.Printf("Root signed by public key %x\n", dump.Root.Signature.PublicKey)
+				}
+			}
+			return nil
+		},
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.AddCommand(ccmd)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdos "os"
+	"testing"
+
+	"github.com/codenotary/immudb/cmd/cmdtest"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/immuos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpAndVerifyKeyHistory(t *testing.T) {
+	os := immuos.NewStandardOS()
+	clb, err := newCommandlineBck(os)
+	require.NoError(t, err)
+	clb.options = client.DefaultOptions()
+
+	immuClientMock := &clienttest.ImmuClientMock{}
+	clb.immuClient = immuClientMock
+	immuClientMock.DisconnectF = func() error {
+		return nil
+	}
+	clb.context = context.Background()
+
+	item := &schema.Item{Key: []byte(`key`), Value: []byte(`value`), Index: 0}
+	leaf := item.Hash()
+	dump := &schema.KeyHistoryDump{
+		Key: []byte(`key`),
+		Entries: []*schema.KeyHistoryEntry{
+			{Item: item, Proof: &schema.InclusionProof{At: 0, Index: 0, Root: leaf, Leaf: leaf}},
+		},
+		Root: &schema.Root{Payload: &schema.RootIndex{Index: 0, Root: leaf}},
+	}
+
+	dumpFile := "key_history_test_dump_output.dump"
+	defer stdos.Remove(dumpFile)
+
+	errDump := errors.New("dump key history error")
+	immuClientMock.DumpKeyHistoryF = func(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error) {
+		return nil, errDump
+	}
+	clb.onError = func(msg interface{}) {
+		require.Equal(t, errDump, msg.(error))
+	}
+
+	cl := commandline{}
+	cmd, _ := cl.NewCmd()
+	clb.dumpKeyHistory(cmd)
+	clb.verifyKeyHistory(cmd)
+	cmd.PersistentPreRunE = nil
+	for _, c := range cmd.Commands() {
+		c.PersistentPreRunE = nil
+	}
+
+	cmd.SetArgs([]string{"dump-key-history", "key", dumpFile})
+	require.NoError(t, cmd.Execute())
+
+	immuClientMock.DumpKeyHistoryF = func(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error) {
+		return dump, nil
+	}
+	collector := new(cmdtest.StdOutCollector)
+	require.NoError(t, collector.Start())
+	require.NoError(t, cmd.Execute())
+	out, err := collector.Stop()
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("SUCCESS: 1 revisions of key key were dumped to file %s\n", dumpFile), out)
+
+	cmd.SetArgs([]string{"verify-key-history", dumpFile})
+	require.NoError(t, collector.Start())
+	require.NoError(t, cmd.Execute())
+	out, err = collector.Stop()
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("SUCCESS: 1 revisions of key key verified against root %x at index 0\n", leaf), out)
+
+	item.Value = []byte(`tampered`)
+	immuClientMock.DumpKeyHistoryF = func(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error) {
+		return dump, nil
+	}
+	cmd.SetArgs([]string{"dump-key-history", "key", dumpFile})
+	require.NoError(t, cmd.Execute())
+
+	clb.onError = func(msg interface{}) {
+		require.Equal(t, schema.ErrCorruptedKeyHistoryDump, msg.(error))
+	}
+	cmd.SetArgs([]string{"verify-key-history", dumpFile})
+	require.NoError(t, cmd.Execute())
+}
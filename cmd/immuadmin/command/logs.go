@@ -0,0 +1,67 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/spf13/cobra"
+)
+
+func (cl *commandline) logs(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "logs",
+		Short:             "Show the recent server log entries",
+		Long:              "Show the recent server log entries. With --follow the command keeps running and prints new entries as they are logged. Requires the sysadmin user.",
+		PersistentPreRunE: cl.ConfigChain(cl.checkLoggedInAndConnect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			follow, err := cmd.Flags().GetBool("follow")
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			level, err := cmd.Flags().GetString("level")
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			components, err := cmd.Flags().GetStringSlice("component")
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			req := &schema.LogRequest{Level: level, Components: components, Follow: follow}
+			if err = cl.immuClient.Logs(cl.context, req, func(e *schema.LogEntry) error {
+				_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s %-5s [%s] %s\n",
+					time.Unix(0, e.Timestamp).Format(time.RFC3339), strings.ToUpper(e.Level), e.Component, e.Message)
+				return err
+			}); err != nil {
+				cl.quit(err)
+			}
+			return nil
+		},
+		Args: cobra.NoArgs,
+	}
+	ccmd.Flags().BoolP("follow", "f", false, "keep streaming new log entries")
+	ccmd.Flags().String("level", "info", "minimum level of the entries to show: debug, info, warn or error")
+	ccmd.Flags().StringSlice("component", nil, "show only entries of the given components, e.g. server, consistency-checker or a database name")
+	cmd.AddCommand(ccmd)
+}
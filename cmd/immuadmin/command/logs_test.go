@@ -0,0 +1,77 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogs(t *testing.T) {
+	immuClientMock := &clienttest.ImmuClientMock{}
+	immuClientMock.DisconnectF = func() error {
+		return nil
+	}
+	var req *schema.LogRequest
+	immuClientMock.LogsF = func(ctx context.Context, r *schema.LogRequest, handler func(*schema.LogEntry) error) error {
+		req = r
+		return handler(&schema.LogEntry{
+			Timestamp: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+			Level:     "warn",
+			Component: "server",
+			Message:   "something happened",
+		})
+	}
+
+	cl := commandline{
+		options:    client.DefaultOptions(),
+		immuClient: immuClientMock,
+		context:    context.Background(),
+	}
+	cmd, _ := cl.NewCmd()
+	cl.logs(cmd)
+	cmd.PersistentPreRunE = nil
+	cmd.Commands()[0].PersistentPreRunE = nil
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"logs", "--follow", "--level", "warn", "--component", "server,defaultdb"})
+	require.NoError(t, cmd.Execute())
+
+	require.True(t, req.Follow)
+	require.Equal(t, "warn", req.Level)
+	require.Equal(t, []string{"server", "defaultdb"}, req.Components)
+	require.Contains(t, b.String(), "WARN  [server] something happened")
+
+	errLogs := errors.New("logs error")
+	immuClientMock.LogsF = func(ctx context.Context, r *schema.LogRequest, handler func(*schema.LogEntry) error) error {
+		return errLogs
+	}
+	cl.onError = func(msg interface{}) {
+		require.Equal(t, errLogs, msg)
+	}
+	cmd.SetArgs([]string{"logs"})
+	require.NoError(t, cmd.Execute())
+}
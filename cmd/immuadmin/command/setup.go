@@ -0,0 +1,500 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	c "github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+// setupOptions are the answers of the init wizard, given as flags or asked interactively
+type setupOptions struct {
+	dir           string
+	dataDir       string
+	tls           bool
+	hosts         []string
+	signingKey    bool
+	adminPassword string
+	databases     []string
+	users         []string
+	skipGenerate  bool
+	skipProvision bool
+	// nonInteractive makes the wizard fail instead of asking what the flags don't answer
+	nonInteractive bool
+}
+
+// setupUser is a user created by the wizard, whose password is generated when not asked interactively
+type setupUser struct {
+	username   string
+	permission uint32
+	database   string
+	password   []byte
+	generated  bool
+}
+
+func (cl *commandline) setup(cmd *cobra.Command) {
+	opts := &setupOptions{}
+	ccmd := &cobra.Command{
+		Use:   "init",
+		Short: "Guided first-run setup of an immudb server",
+		Long: `Guided first-run setup of an immudb server. It generates the TLS material for mutual TLS and the key signing
+the roots, writes the immudb config file using them, then, once immudb is running with it, sets the admin password,
+creates the initial databases and users and prints the verification anchors to be handed over to the auditors.
+Questions not answered by flags are asked interactively, unless --non-interactive is set.`,
+		Example: `immuadmin init
+immuadmin init --non-interactive --dir ./immudb-setup --skip-provision
+immuadmin init --non-interactive --dir ./immudb-setup --skip-generate --mtls --servername localhost \
+	--certificate ./immudb-setup/client.cert.pem --pkey ./immudb-setup/client.key.pem --clientcas ./immudb-setup/ca.cert.pem \
+	--database mydb --user app:readwrite:mydb`,
+		PersistentPreRunE: cl.ConfigChain(nil),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := &setupWizard{cl: cl, cmd: cmd, opts: opts, in: bufio.NewReader(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+			return w.run()
+		},
+		Args: cobra.NoArgs,
+	}
+	ccmd.Flags().StringVar(&opts.dir, "dir", "./immudb-setup", "directory the generated keys, certificates and config file are written into")
+	ccmd.Flags().StringVar(&opts.dataDir, "data-dir", "./data", "data directory of the immudb server, written into the config file")
+	ccmd.Flags().BoolVar(&opts.tls, "tls", true, "generate a private CA with the server and client certificates for mutual TLS")
+	ccmd.Flags().StringSliceVar(&opts.hosts, "host", []string{"localhost", "127.0.0.1"}, "names and addresses the server certificate is valid for")
+	ccmd.Flags().BoolVar(&opts.signingKey, "signing-key", true, "generate the key signing the roots")
+	ccmd.Flags().StringVar(&opts.adminPassword, "admin-password", "", "new password of the admin user (asked interactively if not set, required with --non-interactive)")
+	ccmd.Flags().StringSliceVar(&opts.databases, "database", nil, "database to create, can be repeated")
+	ccmd.Flags().StringSliceVar(&opts.users, "user", nil, "user to create as username:read|readwrite|admin:database, can be repeated. Passwords are asked interactively, or generated and printed once with --non-interactive")
+	ccmd.Flags().BoolVar(&opts.skipGenerate, "skip-generate", false, "don't generate any file, only set up the running server")
+	ccmd.Flags().BoolVar(&opts.skipProvision, "skip-provision", false, "only generate the files, without setting up the server")
+	ccmd.Flags().BoolVar(&opts.nonInteractive, "non-interactive", false, "never ask anything, fail instead")
+	cmd.AddCommand(ccmd)
+}
+
+// setupWizard walks through the first-run setup: the files are generated first, then the server started with them
+// is provisioned
+type setupWizard struct {
+	cl   *commandline
+	cmd  *cobra.Command
+	opts *setupOptions
+	in   *bufio.Reader
+	out  io.Writer
+
+	tls        *tlsMaterial
+	signingKey *signingKeyMaterial
+}
+
+func (w *setupWizard) run() (err error) {
+	if w.opts.skipGenerate && w.opts.skipProvision {
+		return errors.New("nothing to do: --skip-generate and --skip-provision are both set")
+	}
+	if !w.opts.skipGenerate {
+		if err = w.generate(); err != nil {
+			return err
+		}
+	}
+	if !w.opts.skipProvision {
+		if err = w.provision(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *setupWizard) generate() (err error) {
+	if !w.opts.nonInteractive {
+		if w.opts.dir, err = w.ask("dir", "Directory of the generated files", w.opts.dir); err != nil {
+			return err
+		}
+		if w.opts.dataDir, err = w.ask("data-dir", "Data directory of immudb", w.opts.dataDir); err != nil {
+			return err
+		}
+		if w.opts.tls, err = w.confirm("tls", "Generate the certificates for mutual TLS?", w.opts.tls); err != nil {
+			return err
+		}
+		if w.opts.tls && !w.cmd.Flags().Changed("host") {
+			hosts, err := w.ask("host", "Names and addresses of the server, comma separated", strings.Join(w.opts.hosts, ","))
+			if err != nil {
+				return err
+			}
+			w.opts.hosts = splitList(hosts)
+		}
+		if w.opts.signingKey, err = w.confirm("signing-key", "Generate the key signing the roots?", w.opts.signingKey); err != nil {
+			return err
+		}
+	}
+	if w.opts.tls && len(w.opts.hosts) == 0 {
+		return errors.New("the server certificate needs at least a host")
+	}
+
+	if w.opts.dir, err = filepath.Abs(w.opts.dir); err != nil {
+		return err
+	}
+	if w.opts.dataDir, err = filepath.Abs(w.opts.dataDir); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(w.opts.dir, 0700); err != nil {
+		return err
+	}
+	if w.opts.tls {
+		if w.tls, err = generateTLSMaterial(w.opts.dir, w.opts.hosts); err != nil {
+			return err
+		}
+		fmt.Fprintf(w.out, "generated the CA %s, the server certificate %s and the client certificate %s\n",
+			w.tls.CACert, w.tls.ServerCert, w.tls.ClientCert)
+	}
+	if w.opts.signingKey {
+		if w.signingKey, err = generateSigningKey(w.opts.dir); err != nil {
+			return err
+		}
+		fmt.Fprintf(w.out, "generated the signing key %s and its public key %s\n", w.signingKey.PrivateKey, w.signingKey.PublicKey)
+	}
+	configFile := filepath.Join(w.opts.dir, "immudb.toml")
+	if err = writeServerConfig(configFile, w.opts.dataDir, w.tls, w.signingKey); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "written the config file %s\n", configFile)
+
+	if w.opts.skipProvision {
+		fmt.Fprintf(w.out, "start immudb with: immudb --config %s\n", configFile)
+		w.printAnchors(nil)
+		return nil
+	}
+	if w.tls != nil {
+		// the server is reached with the generated client certificate from now on
+		w.cl.options.MTLs = true
+		w.cl.options.MTLsOptions = client.DefaultMTLsOptions().
+			WithServername(w.opts.hosts[0]).
+			WithCertificate(w.tls.ClientCert).
+			WithPkey(w.tls.ClientKey).
+			WithClientCAs(w.tls.CACert)
+	}
+	if !w.opts.nonInteractive {
+		fmt.Fprintf(w.out, "start immudb with: immudb --config %s\n", configFile)
+		_, err = w.ask("", "Press Enter once immudb is running", "")
+	}
+	return err
+}
+
+func (w *setupWizard) provision() (err error) {
+	users, err := w.parseUsers()
+	if err != nil {
+		return err
+	}
+	if err = w.cl.connect(w.cmd, nil); err != nil {
+		return err
+	}
+	defer func() {
+		w.cl.immuClient.Disconnect()
+	}()
+
+	if err = w.setAdminPassword(); err != nil {
+		return err
+	}
+	for _, db := range w.opts.databases {
+		existing, err := dbExists(w.cl.context, w.cl.immuClient, db)
+		if err != nil {
+			return err
+		}
+		if existing {
+			fmt.Fprintf(w.out, "database %s already exists\n", db)
+			continue
+		}
+		if err = w.cl.immuClient.CreateDatabase(w.cl.context, &schema.Database{Databasename: db}); err != nil {
+			return err
+		}
+		fmt.Fprintf(w.out, "created database %s\n", db)
+	}
+	for _, u := range users {
+		existing, err := userExists(w.cl.context, w.cl.immuClient, u.username)
+		if err != nil {
+			return err
+		}
+		if existing {
+			fmt.Fprintf(w.out, "user %s already exists\n", u.username)
+			continue
+		}
+		if u.password, u.generated, err = w.userPassword(u.username); err != nil {
+			return err
+		}
+		if err = w.cl.immuClient.CreateUser(w.cl.context, []byte(u.username), u.password, u.permission, u.database); err != nil {
+			return err
+		}
+		fmt.Fprintf(w.out, "created user %s\n", u.username)
+		if u.generated {
+			c.PrintfColorW(w.out, c.Yellow, "password of %s, shown only once: %s\n", u.username, u.password)
+		}
+	}
+
+	roots := make(map[string]*schema.Root)
+	// the session is left on the default database
+	for _, db := range append(append([]string{}, w.opts.databases...), server.DefaultdbName) {
+		if roots[db], err = w.currentRoot(db); err != nil {
+			return err
+		}
+	}
+	w.printAnchors(roots)
+	return nil
+}
+
+// setAdminPassword logs in as admin, replacing the default password if it's still set
+func (w *setupWizard) setAdminPassword() error {
+	ctx := w.cl.context
+	user := []byte(auth.SysAdminUsername)
+	warning, err := w.cl.loginAndRenewClient(ctx, user, []byte(auth.SysAdminPassword))
+	if err != nil {
+		// the password has already been changed, e.g. by a previous run
+		var current []byte
+		if !w.opts.nonInteractive {
+			if current, err = w.cl.passwordReader.Read("Current admin password:"); err != nil {
+				return err
+			}
+		} else if w.opts.adminPassword != "" {
+			current = []byte(w.opts.adminPassword)
+		} else {
+			return errors.New("the admin password is not the default one: set it with --admin-password")
+		}
+		if _, err = w.cl.loginAndRenewClient(ctx, user, current); err != nil {
+			return err
+		}
+		fmt.Fprintf(w.out, "logged in as %s\n", auth.SysAdminUsername)
+		return nil
+	}
+	if warning != auth.WarnDefaultAdminPassword {
+		return nil
+	}
+
+	var password []byte
+	if w.opts.adminPassword != "" {
+		password = []byte(w.opts.adminPassword)
+		if err = auth.IsStrongPassword(w.opts.adminPassword); err != nil {
+			return err
+		}
+	} else if !w.opts.nonInteractive {
+		if password, err = w.readNewPassword(auth.SysAdminUsername); err != nil {
+			return err
+		}
+	} else {
+		return errors.New("the admin password must be set with --admin-password")
+	}
+	if err = w.cl.immuClient.ChangePassword(ctx, user, []byte(auth.SysAdminPassword), password); err != nil {
+		return err
+	}
+	if _, err = w.cl.loginAndRenewClient(ctx, user, password); err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "the admin password has been set\n")
+	return nil
+}
+
+func (w *setupWizard) parseUsers() ([]*setupUser, error) {
+	if !w.opts.nonInteractive && !w.cmd.Flags().Changed("database") {
+		databases, err := w.ask("database", "Databases to create, comma separated", "")
+		if err != nil {
+			return nil, err
+		}
+		w.opts.databases = splitList(databases)
+	}
+	if !w.opts.nonInteractive && !w.cmd.Flags().Changed("user") {
+		users, err := w.ask("user", "Users to create as username:read|readwrite|admin:database, comma separated", "")
+		if err != nil {
+			return nil, err
+		}
+		w.opts.users = splitList(users)
+	}
+	users := make([]*setupUser, 0, len(w.opts.users))
+	for _, spec := range w.opts.users {
+		parts := strings.Split(spec, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid user %s: expected username:read|readwrite|admin:database", spec)
+		}
+		permission, err := permissionFromString(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, &setupUser{username: parts[0], permission: permission, database: parts[2]})
+	}
+	return users, nil
+}
+
+// userPassword asks the password of a new user, or generates it when not interactive
+func (w *setupWizard) userPassword(username string) ([]byte, bool, error) {
+	if !w.opts.nonInteractive {
+		password, err := w.readNewPassword(username)
+		return password, false, err
+	}
+	password, err := generatePassword()
+	return password, true, err
+}
+
+func (w *setupWizard) readNewPassword(username string) ([]byte, error) {
+	password, err := w.cl.passwordReader.Read(fmt.Sprintf("Choose a password for %s:", username))
+	if err != nil {
+		return nil, errors.New("Error Reading Password")
+	}
+	if err = auth.IsStrongPassword(string(password)); err != nil {
+		return nil, err
+	}
+	confirmed, err := w.cl.passwordReader.Read("Confirm password:")
+	if err != nil {
+		return nil, errors.New("Error Reading Password")
+	}
+	if !bytes.Equal(password, confirmed) {
+		return nil, errors.New("Passwords don't match")
+	}
+	return password, nil
+}
+
+// currentRoot switches the session to db and returns its current root
+func (w *setupWizard) currentRoot(db string) (*schema.Root, error) {
+	resp, err := w.cl.immuClient.UseDatabase(w.cl.context, &schema.Database{Databasename: db})
+	if err != nil {
+		return nil, err
+	}
+	if err = w.cl.ts.SetToken(db, resp.Token); err != nil {
+		return nil, err
+	}
+	options := w.cl.immuClient.GetOptions()
+	options.CurrentDatabase = db
+	if w.cl.immuClient, err = w.cl.newImmuClient(options); err != nil {
+		return nil, err
+	}
+	return w.cl.immuClient.CurrentRoot(w.cl.context)
+}
+
+// printAnchors prints what the auditors and the clients pin to verify the server from now on
+func (w *setupWizard) printAnchors(roots map[string]*schema.Root) {
+	fmt.Fprintf(w.out, "\nverification anchors, to be handed over out of band to the auditors and the clients:\n")
+	if w.tls != nil {
+		fmt.Fprintf(w.out, "  CA certificate SHA-256: %s\n", w.tls.CAFingerprint)
+	}
+	if w.signingKey != nil {
+		fmt.Fprintf(w.out, "  signing public key: %s\n", w.signingKey.PublicKey)
+		fmt.Fprintf(w.out, "  signing public key SHA-256: %s\n", w.signingKey.Fingerprint)
+	}
+	dbs := make([]string, 0, len(roots))
+	for db := range roots {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+	for _, db := range dbs {
+		if root := roots[db]; len(root.GetRoot()) > 0 {
+			fmt.Fprintf(w.out, "  root of %s: %x at index %d\n", db, root.GetRoot(), root.GetIndex())
+		} else {
+			fmt.Fprintf(w.out, "  root of %s: empty\n", db)
+		}
+	}
+}
+
+// ask prints question and returns the answer, or def if the answer is empty. flag is the flag answering the
+// question: if it has been set the question isn't asked.
+func (w *setupWizard) ask(flag string, question string, def string) (string, error) {
+	if flag != "" && w.cmd.Flags().Changed(flag) {
+		return def, nil
+	}
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	answer, err := w.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+func (w *setupWizard) confirm(flag string, question string, def bool) (bool, error) {
+	if w.cmd.Flags().Changed(flag) {
+		return def, nil
+	}
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := w.ask("", fmt.Sprintf("%s (%s)", question, hint), "")
+		if err != nil {
+			return def, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// generatePassword returns a random password meeting the strength requirements
+func generatePassword() ([]byte, error) {
+	const (
+		upper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+		lower   = "abcdefghijkmnopqrstuvwxyz"
+		digits  = "23456789"
+		symbols = "!#%+-=?@^_"
+	)
+	classes := []string{upper, lower, digits, symbols}
+	all := strings.Join(classes, "")
+	password := make([]byte, 20)
+	for i := range password {
+		charset := all
+		if i < len(classes) {
+			// at least a character of every class
+			charset = classes[i]
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return nil, err
+		}
+		password[i] = charset[n.Int64()]
+	}
+	// the position of the characters of every class must not be predictable
+	for i := len(password) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		j := n.Int64()
+		password[i], password[j] = password[j], password[i]
+	}
+	return password, nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	certValidity = 2 * 365 * 24 * time.Hour
+)
+
+// tlsMaterial are the files of a private CA and of the server and client certificates it issued for mutual TLS
+type tlsMaterial struct {
+	CACert     string
+	CAKey      string
+	ServerCert string
+	ServerKey  string
+	ClientCert string
+	ClientKey  string
+	// CAFingerprint is the SHA-256 of the CA certificate, to be checked out of band by whoever trusts it
+	CAFingerprint string
+}
+
+// generateTLSMaterial creates into dir a private CA, a server certificate valid for hosts (names or addresses)
+// and a client certificate for the administration tools, all of them with P-256 keys
+func generateTLSMaterial(dir string, hosts []string) (*tlsMaterial, error) {
+	m := &tlsMaterial{
+		CACert:     filepath.Join(dir, "ca.cert.pem"),
+		CAKey:      filepath.Join(dir, "ca.key.pem"),
+		ServerCert: filepath.Join(dir, "server.cert.pem"),
+		ServerKey:  filepath.Join(dir, "server.key.pem"),
+		ClientCert: filepath.Join(dir, "client.cert.pem"),
+		ClientKey:  filepath.Join(dir, "client.key.pem"),
+	}
+	if err := checkNotExist(m.CACert, m.CAKey, m.ServerCert, m.ServerKey, m.ClientCert, m.ClientKey); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		Subject:               pkix.Name{Organization: []string{"immudb"}, CommonName: "immudb CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := issueCertificate(caTemplate, caTemplate, caKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+	if err = writeCertificateAndKey(m.CACert, caDER, m.CAKey, caKey); err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(caDER)
+	m.CAFingerprint = hex.EncodeToString(fingerprint[:])
+
+	serverTemplate := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{"immudb"}, CommonName: hosts[0]},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	clientTemplate := &x509.Certificate{
+		Subject:     pkix.Name{Organization: []string{"immudb"}, CommonName: "immuadmin"},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, leaf := range []struct {
+		template  *x509.Certificate
+		cert, key string
+	}{
+		{serverTemplate, m.ServerCert, m.ServerKey},
+		{clientTemplate, m.ClientCert, m.ClientKey},
+	} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		der, err := issueCertificate(leaf.template, ca, key, caKey)
+		if err != nil {
+			return nil, err
+		}
+		if err = writeCertificateAndKey(leaf.cert, der, leaf.key, key); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func issueCertificate(template, parent *x509.Certificate, key, parentKey *ecdsa.PrivateKey) ([]byte, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	return x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+}
+
+func writeCertificateAndKey(certPath string, der []byte, keyPath string, key *ecdsa.PrivateKey) error {
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return err
+	}
+	return writePrivateKey(keyPath, key)
+}
+
+func writePrivateKey(path string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+}
+
+// signingKeyMaterial are the files of the key signing the roots and of its public key, given to the auditors
+type signingKeyMaterial struct {
+	PrivateKey string
+	PublicKey  string
+	// Fingerprint is the SHA-256 of the public key, to be checked out of band by whoever verifies the signatures
+	Fingerprint string
+}
+
+// generateSigningKey creates into dir a P-256 key signing the roots, in the format expected by the signingKey
+// option of immudb, along with its public key in the format expected by the auditor
+func generateSigningKey(dir string) (*signingKeyMaterial, error) {
+	m := &signingKeyMaterial{
+		PrivateKey: filepath.Join(dir, "signing.key.pem"),
+		PublicKey:  filepath.Join(dir, "signing.pub.pem"),
+	}
+	if err := checkNotExist(m.PrivateKey, m.PublicKey); err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err = writePrivateKey(m.PrivateKey, key); err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(m.PublicKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644); err != nil {
+		return nil, err
+	}
+	fingerprint := sha256.Sum256(elliptic.Marshal(key.Curve, key.X, key.Y))
+	m.Fingerprint = hex.EncodeToString(fingerprint[:])
+	return m, nil
+}
+
+// writeServerConfig writes the immudb configuration file serving the data directory dataDir with the generated
+// material, nil material being left out
+func writeServerConfig(path string, dataDir string, tls *tlsMaterial, signingKey *signingKeyMaterial) error {
+	if err := checkNotExist(path); err != nil {
+		return err
+	}
+	lines := []string{
+		"# generated by immuadmin init",
+		"dir = " + strconv.Quote(dataDir),
+		"auth = true",
+		"mtls = " + strconv.FormatBool(tls != nil),
+	}
+	if tls != nil {
+		lines = append(lines,
+			"certificate = "+strconv.Quote(tls.ServerCert),
+			"pkey = "+strconv.Quote(tls.ServerKey),
+			"clientcas = "+strconv.Quote(tls.CACert))
+	}
+	if signingKey != nil {
+		lines = append(lines, "signingKey = "+strconv.Quote(signingKey.PrivateKey))
+	}
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+}
+
+// checkNotExist makes sure that no generated file overwrites an existing one, as keys can't be recovered
+func checkNotExist(paths ...string) error {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists: move it away or choose another directory", path)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestSetupMaterial(t *testing.T) {
+	dir, err := ioutil.TempDir("", "immuadmin_init")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m, err := generateTLSMaterial(dir, []string{"localhost", "127.0.0.1"})
+	require.NoError(t, err)
+	caPEM, err := ioutil.ReadFile(m.CACert)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caPEM))
+	for _, pair := range []struct {
+		cert, key string
+		opts      x509.VerifyOptions
+	}{
+		{m.ServerCert, m.ServerKey, x509.VerifyOptions{Roots: roots, DNSName: "localhost"}},
+		{m.ServerCert, m.ServerKey, x509.VerifyOptions{Roots: roots, DNSName: "127.0.0.1"}},
+		{m.ClientCert, m.ClientKey, x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}},
+	} {
+		keyPair, err := tls.LoadX509KeyPair(pair.cert, pair.key)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+		require.NoError(t, err)
+		_, err = cert.Verify(pair.opts)
+		require.NoError(t, err)
+	}
+	require.Len(t, m.CAFingerprint, 64)
+
+	sk, err := generateSigningKey(dir)
+	require.NoError(t, err)
+	_, err = signer.NewSigner(sk.PrivateKey)
+	require.NoError(t, err)
+	_, err = signer.LoadPublicKey(sk.PublicKey)
+	require.NoError(t, err)
+
+	configFile := filepath.Join(dir, "immudb.toml")
+	require.NoError(t, writeServerConfig(configFile, "/var/lib/immudb", m, sk))
+	config, err := ioutil.ReadFile(configFile)
+	require.NoError(t, err)
+	require.Contains(t, string(config), `dir = "/var/lib/immudb"`)
+	require.Contains(t, string(config), "mtls = true")
+	require.Contains(t, string(config), `clientcas = "`+m.CACert+`"`)
+	require.Contains(t, string(config), `signingKey = "`+sk.PrivateKey+`"`)
+
+	// keys are never overwritten
+	_, err = generateTLSMaterial(dir, []string{"localhost"})
+	require.Error(t, err)
+	_, err = generateSigningKey(dir)
+	require.Error(t, err)
+	require.Error(t, writeServerConfig(configFile, "/var/lib/immudb", nil, nil))
+}
+
+func TestSetupInteractiveGenerate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "immuadmin_init")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cl := commandline{options: Options(), context: context.Background()}
+	cmd, _ := cl.NewCmd()
+	cl.setup(cmd)
+	cmd.PersistentPreRunE = nil
+	cmd.Commands()[0].PersistentPreRunE = nil
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	// directory, default data directory, no TLS, default signing key
+	cmd.SetIn(strings.NewReader(dir + "\n\nn\n\n"))
+	cmd.SetArgs([]string{"init", "--skip-provision"})
+	require.NoError(t, cmd.Execute())
+
+	require.Contains(t, b.String(), "generated the signing key")
+	require.NotContains(t, b.String(), "generated the CA")
+	require.Contains(t, b.String(), "signing public key SHA-256")
+	config, err := ioutil.ReadFile(filepath.Join(dir, "immudb.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(config), "mtls = false")
+	require.Contains(t, string(config), "signingKey")
+}
+
+func TestSetupProvision(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	cliopt := Options().WithDialOptions(&dialOptions)
+	cliopt.Tkns = client.NewTokenService().WithHds(client.NewHomedirService()).WithTokenFileName("token_admin")
+	cliopt.TokenFileName = "token_admin"
+	cl := commandline{
+		config:        helper.Config{Name: "immuadmin"},
+		options:       cliopt,
+		context:       context.Background(),
+		ts:            client.NewTokenService().WithHds(client.NewHomedirService()).WithTokenFileName("token_admin"),
+		newImmuClient: client.NewImmuClient,
+	}
+	cmd, _ := cl.NewCmd()
+	cl.setup(cmd)
+	// remove ConfigChain method to avoid override options
+	cmd.PersistentPreRunE = nil
+	cmd.Commands()[0].PersistentPreRunE = nil
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"init", "--non-interactive", "--skip-generate", "--admin-password", "Passw0rd!-",
+		"--database", "mydb", "--user", "app:readwrite:mydb"})
+	require.NoError(t, cmd.Execute())
+
+	out := b.String()
+	require.Contains(t, out, "the admin password has been set")
+	require.Contains(t, out, "created database mydb")
+	require.Contains(t, out, "created user app")
+	require.Contains(t, out, "password of app, shown only once")
+	require.Contains(t, out, "root of mydb: empty")
+	require.Contains(t, out, "root of defaultdb:")
+
+	immuClient, err := client.NewImmuClient(cliopt)
+	require.NoError(t, err)
+	defer immuClient.Disconnect()
+	_, err = immuClient.Login(context.Background(), []byte(auth.SysAdminUsername), []byte(auth.SysAdminPassword))
+	require.Error(t, err)
+	_, err = immuClient.Login(context.Background(), []byte(auth.SysAdminUsername), []byte("Passw0rd!-"))
+	require.NoError(t, err)
+
+	// running it again only logs in with the password already set
+	b.Reset()
+	cmd.SetArgs([]string{"init", "--non-interactive", "--skip-generate", "--admin-password", "Passw0rd!-", "--database", "mydb"})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, b.String(), "database mydb already exists")
+	require.Contains(t, b.String(), "user app already exists")
+}
+
+func TestGeneratePassword(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		password, err := generatePassword()
+		require.NoError(t, err)
+		require.NoError(t, auth.IsStrongPassword(string(password)))
+	}
+}
@@ -24,42 +24,56 @@ import (
 )
 
 var readers = map[string]bool{
-	"ByIndex":     true,
-	"ByIndexSV":   true,
-	"Consistency": true,
-	"Count":       true,
-	"CurrentRoot": true,
-	"Dump":        true,
-	"Get":         true,
-	"GetBatch":    true,
-	"GetBatchSV":  true,
-	"GetSV":       true,
-	"Health":      true,
-	"History":     true,
-	"HistorySV":   true,
-	"IScan":       true,
-	"IScanSV":     true,
-	"Inclusion":   true,
-	"Login":       true,
-	"SafeGet":     true,
-	"SafeGetSV":   true,
-	"Scan":        true,
-	"ScanSV":      true,
-	"ZScan":       true,
-	"ZScanSV":     true,
+	"Backup":        true,
+	"ByIndex":       true,
+	"ByIndexSV":     true,
+	"Consistency":   true,
+	"Count":         true,
+	"CurrentRoot":   true,
+	"Dump":          true,
+	"Get":           true,
+	"GetAsOf":       true,
+	"GetAt":         true,
+	"GetBatch":      true,
+	"GetBatchSV":    true,
+	"GetReferences": true,
+	"GetSV":         true,
+	"Health":        true,
+	"History":       true,
+	"HistorySV":     true,
+	"IScan":         true,
+	"IScanSV":       true,
+	"Inclusion":     true,
+	"Login":         true,
+	"SafeGet":       true,
+	"SafeGetBatch":  true,
+	"SafeGetSV":     true,
+	"SafeZScan":     true,
+	"Scan":          true,
+	"ScanSV":        true,
+	"Stats":         true,
+	"Subscribe":     true,
+	"Tail":          true,
+	"ZScan":         true,
+	"ZScanSV":       true,
 }
 
 var writers = map[string]bool{
-	"Reference":     true,
-	"SafeReference": true,
-	"SafeSet":       true,
-	"SafeSetSV":     true,
-	"SafeZAdd":      true,
-	"Set":           true,
-	"SetBatch":      true,
-	"SetBatchSV":    true,
-	"SetSV":         true,
-	"ZAdd":          true,
+	"CompareAndExecAllTx": true,
+	"Delete":              true,
+	"ExecAllTx":           true,
+	"Reference":           true,
+	"SafeExecAllTx":       true,
+	"SafeReference":       true,
+	"SafeSet":             true,
+	"SafeSetSV":           true,
+	"SafeZAdd":            true,
+	"Set":                 true,
+	"SetBatch":            true,
+	"SetBatchSV":          true,
+	"SetSV":               true,
+	"Unreference":         true,
+	"ZAdd":                true,
 }
 
 type rpcDuration struct {
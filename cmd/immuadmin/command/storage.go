@@ -0,0 +1,89 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"fmt"
+	"io"
+
+	c "github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/spf13/cobra"
+)
+
+func (cl *commandline) storage(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:   "storage command",
+		Short: "Analyze how the current database is stored",
+	}
+	reportCmd := &cobra.Command{
+		Use:               "report",
+		Short:             "Show the storage efficiency report of the current database",
+		Long:              "Show the storage efficiency report of the current database: LSM levels, value log utilization, Merkle tree size and recommended maintenance actions. The analysis scans the whole database.",
+		PersistentPreRunE: cl.ConfigChain(cl.checkLoggedInAndConnect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := cl.immuClient.AnalyzeStorage(cl.context)
+			if err != nil {
+				cl.quit(err)
+				return nil
+			}
+			printStorageReport(cmd.OutOrStdout(), report)
+			return nil
+		},
+		Args: cobra.NoArgs,
+	}
+	ccmd.AddCommand(reportCmd)
+	cmd.AddCommand(ccmd)
+}
+
+func printStorageReport(w io.Writer, report *schema.StorageReport) {
+	fmt.Fprintf(w, "Database:            %s\n", report.Database)
+	fmt.Fprintf(w, "LSM size:            %d\n", report.LsmSize)
+	fmt.Fprintf(w, "Value log size:      %d\n", report.VlogSize)
+	fmt.Fprintf(w, "Value log live size: %d (%.1f%%)\n", report.VlogLiveSize, report.VlogUtilization*100)
+	fmt.Fprintf(w, "Entries:             %d (%d bytes)\n", report.Entries, report.EntriesSize)
+	fmt.Fprintf(w, "Tree nodes:          %d, %d leaves (%d bytes)\n", report.TreeNodes, report.TreeLeaves, report.TreeSize)
+	fmt.Fprintf(w, "Space amplification: %.2f\n", report.SpaceAmplification)
+	if len(report.Levels) > 0 {
+		fmt.Fprintln(w)
+		c.PrintTable(
+			w,
+			[]string{"Level", "Tables", "Keys", "Size"},
+			len(report.Levels),
+			func(i int) []string {
+				l := report.Levels[i]
+				return []string{
+					fmt.Sprintf("%d", l.Level),
+					fmt.Sprintf("%d", l.Tables),
+					fmt.Sprintf("%d", l.Keys),
+					fmt.Sprintf("%d", l.Size),
+				}
+			},
+			fmt.Sprintf("%d level(s)", len(report.Levels)),
+		)
+	}
+	fmt.Fprintln(w)
+	if len(report.Recommendations) == 0 {
+		fmt.Fprintln(w, "No maintenance action is recommended")
+		return
+	}
+	fmt.Fprintln(w, "Recommendations:")
+	for _, r := range report.Recommendations {
+		fmt.Fprintf(w, "  - %s\n", r)
+	}
+}
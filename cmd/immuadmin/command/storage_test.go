@@ -0,0 +1,83 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immuadmin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageReport(t *testing.T) {
+	immuClientMock := &clienttest.ImmuClientMock{}
+	immuClientMock.DisconnectF = func() error {
+		return nil
+	}
+	report := &schema.StorageReport{
+		Database:        "defaultdb",
+		LsmSize:         1000,
+		VlogSize:        4000,
+		VlogLiveSize:    1000,
+		VlogUtilization: 0.25,
+		Levels:          []*schema.StorageLevel{{Level: 0, Tables: 2, Keys: 30, Size: 1000}},
+		Entries:         10,
+		EntriesSize:     2000,
+		Recommendations: []string{"run the value log garbage collection"},
+	}
+	immuClientMock.AnalyzeStorageF = func(ctx context.Context) (*schema.StorageReport, error) {
+		return report, nil
+	}
+
+	cl := commandline{
+		options:    client.DefaultOptions(),
+		immuClient: immuClientMock,
+		context:    context.Background(),
+	}
+	cmd, _ := cl.NewCmd()
+	cl.storage(cmd)
+	cmd.PersistentPreRunE = nil
+	cmd.Commands()[0].Commands()[0].PersistentPreRunE = nil
+
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmd.SetArgs([]string{"storage", "report"})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, b.String(), "Database:            defaultdb")
+	require.Contains(t, b.String(), "Value log live size: 1000 (25.0%)")
+	require.Contains(t, b.String(), "1 level(s)")
+	require.Contains(t, b.String(), "  - run the value log garbage collection")
+
+	report.Recommendations = nil
+	b.Reset()
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, b.String(), "No maintenance action is recommended")
+
+	errAnalyze := errors.New("analyze storage error")
+	immuClientMock.AnalyzeStorageF = func(ctx context.Context) (*schema.StorageReport, error) {
+		return nil, errAnalyze
+	}
+	cl.onError = func(msg interface{}) {
+		require.Equal(t, errAnalyze, msg)
+	}
+	require.NoError(t, cmd.Execute())
+}
@@ -22,6 +22,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	c "github.com/codenotary/immudb/cmd/helper"
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -126,7 +128,21 @@ immuadmin user create user1 admin mydb`,
 		},
 		Args: cobra.ExactValidArgs(4),
 	}
+	userSessions := &cobra.Command{
+		Use:   "sessions",
+		Short: "List when, from where and for which RPC the tokens in use were last used",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := cl.userSessions(args)
+			if err != nil {
+				c.QuitToStdErr(err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), resp)
+			return nil
+		},
+		Args: cobra.MaximumNArgs(0),
+	}
 	ccmd.AddCommand(userListCmd)
+	ccmd.AddCommand(userSessions)
 	ccmd.AddCommand(userCreate)
 	ccmd.AddCommand(userChangePassword)
 	ccmd.AddCommand(userActivate)
@@ -208,6 +224,48 @@ func (cl *commandline) userList(args []string) (string, error) {
 	return b.String(), nil
 }
 
+func (cl *commandline) userSessions(args []string) (string, error) {
+	sessionList, err := cl.immuClient.ListSessions(cl.context)
+	if err != nil {
+		return "", err
+	}
+	sessions := sessionList.GetSessions()
+	rows := make([][]string, 0, len(sessions))
+	maxColWidths := make([]int, 7)
+	for _, session := range sessions {
+		row := []string{
+			session.GetId(),
+			session.GetUser(),
+			time.Unix(session.GetLastSeen(), 0).Format(time.RFC3339),
+			session.GetLastAddress(),
+			session.GetLastMethod(),
+			fmt.Sprintf("%d", session.GetCalls()),
+			strings.Join(session.GetAddresses(), ", "),
+		}
+		updateMaxLen(maxColWidths, row)
+		rows = append(rows, row)
+	}
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	c.PrintTable(
+		w,
+		[]string{
+			fmt.Sprintf("% -*s", maxColWidths[0], "Session"),
+			fmt.Sprintf("% -*s", maxColWidths[1], "User"),
+			fmt.Sprintf("% -*s", maxColWidths[2], "Last Seen"),
+			fmt.Sprintf("% -*s", maxColWidths[3], "Last Address"),
+			fmt.Sprintf("% -*s", maxColWidths[4], "Last RPC"),
+			fmt.Sprintf("% -*s", maxColWidths[5], "Calls"),
+			fmt.Sprintf("% -*s", maxColWidths[6], "Addresses"),
+		},
+		len(rows),
+		func(i int) []string { return rows[i] },
+		fmt.Sprintf("%d session(s)", len(sessions)),
+	)
+	w.Flush()
+	return b.String(), nil
+}
+
 func updateMaxLen(maxs []int, strs []string) {
 	for i, str := range strs {
 		if len(str) > maxs[i] {
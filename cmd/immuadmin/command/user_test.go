@@ -137,6 +137,41 @@ func TestUserListErrors(t *testing.T) {
 	require.Contains(t, resp, "unknown: 999")
 }
 
+func TestUserSessions(t *testing.T) {
+	immuClientMock := &clienttest.ImmuClientMock{}
+	cl := &commandline{
+		immuClient: immuClientMock,
+	}
+
+	errListSessions := errors.New("list sessions error")
+	immuClientMock.ListSessionsF = func(context.Context) (*schema.SessionList, error) {
+		return nil, errListSessions
+	}
+	_, err := cl.userSessions(nil)
+	require.Equal(t, errListSessions, err)
+
+	immuClientMock.ListSessionsF = func(context.Context) (*schema.SessionList, error) {
+		return &schema.SessionList{
+			Sessions: []*schema.Session{
+				&schema.Session{
+					Id:          "0123456789abcdef",
+					User:        "immudb",
+					LastSeen:    time.Now().Unix(),
+					LastAddress: "10.0.0.2",
+					LastMethod:  "/immudb.schema.ImmuService/Get",
+					Calls:       3,
+					Addresses:   []string{"10.0.0.1", "10.0.0.2"},
+				},
+			},
+		}, nil
+	}
+	resp, err := cl.userSessions(nil)
+	require.NoError(t, err)
+	require.Contains(t, resp, "0123456789abcdef")
+	require.Contains(t, resp, "10.0.0.1, 10.0.0.2")
+	require.Contains(t, resp, "1 session(s)")
+}
+
 func TestUserChangePassword(t *testing.T) {
 	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
 	bs.Start()
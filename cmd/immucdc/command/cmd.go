@@ -0,0 +1,149 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immucdc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/codenotary/immudb/cmd/docs/man"
+	c "github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/cmd/version"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/connector"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/metadata"
+)
+
+// NewCmd creates a new immucdc command
+func NewCmd() *cobra.Command {
+	config := c.Config{Name: "immucdc"}
+	connectorOpts := connector.DefaultOptions(server.DefaultdbName)
+	kafkaOpts := connector.DefaultKafkaOptions("immudb")
+
+	cmd := &cobra.Command{
+		Use:   "immucdc",
+		Short: "Stream the entries of an immudb database to a Kafka topic, along with their proofs",
+		Long: `Stream every entry of an immudb database to a Kafka topic as it's committed, one record per entry keyed by
+the key of the entry. Records hold the JSON encoding of the entry, with its index, its hash and the root it has been
+verified against, along with the inclusion path proving it. Every entry is verified against the roots verified before,
+the first one being trusted on first use.
+
+The position in the database is saved in the state file after every publication, so that a restarted immucdc resumes
+where it stopped. Entries are published at least once: consumers can deduplicate them by index.
+  Environment variables:
+    IMMUCDC_IMMUDB_ADDRESS=127.0.0.1
+    IMMUCDC_IMMUDB_PORT=3322
+    IMMUCDC_DATABASE=defaultdb
+    IMMUCDC_USER=immudb
+    IMMUCDC_PASSWORD=immudb
+    IMMUCDC_KAFKA_BROKER=127.0.0.1:9092
+    IMMUCDC_KAFKA_TOPIC=immudb`,
+		Example: `  immucdc --kafka-broker kafka:9092 --kafka-topic immudb --state-file /var/lib/immucdc/state.json`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return config.LoadConfig(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-signals
+				cancel()
+			}()
+			return run(ctx)
+		},
+		Args:              cobra.NoArgs,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&config.CfgFn, "config", "", "config file (default path are configs or $HOME. Default filename is immucdc.toml)")
+	cmd.Flags().IntP("immudb-port", "p", client.DefaultOptions().Port, "immudb port number")
+	cmd.Flags().StringP("immudb-address", "a", client.DefaultOptions().Address, "immudb host address")
+	cmd.Flags().StringP("database", "d", connectorOpts.Database, "database whose entries are streamed")
+	cmd.Flags().StringP("user", "u", auth.SysAdminUsername, "database user")
+	cmd.Flags().String("password", auth.SysAdminPassword, "password of the user, better set with the IMMUCDC_PASSWORD environment variable")
+	cmd.Flags().Uint64("from", connectorOpts.From, "index of the first entry streamed, unless a state has been saved")
+	cmd.Flags().Int("batch-size", connectorOpts.BatchSize, "maximum number of entries published at once")
+	cmd.Flags().Duration("poll-interval", connectorOpts.PollInterval, "longest time an entry waits for its batch to fill up before being published")
+	cmd.Flags().String("state-file", "immucdc.state", "file saving the position in the database (empty to start from --from every time)")
+	cmd.Flags().String("kafka-broker", kafkaOpts.Broker, "address of the Kafka broker leading the partition")
+	cmd.Flags().String("kafka-topic", kafkaOpts.Topic, "Kafka topic the entries are published to")
+	cmd.Flags().Int32("kafka-partition", kafkaOpts.Partition, "partition of the topic the entries are published to")
+	cmd.Flags().Int16("kafka-acks", kafkaOpts.Acks, "number of replicas acknowledging the records, -1 for all the in-sync ones")
+	cmd.Flags().Duration("kafka-timeout", kafkaOpts.Timeout, "timeout of the requests to the Kafka broker")
+	if err := viper.BindPFlags(cmd.Flags()); err != nil {
+		c.QuitToStdErr(err)
+	}
+
+	cmd.AddCommand(man.Generate(cmd, "immucdc", "./cmd/docs/man/immucdc"))
+	cmd.AddCommand(version.VersionCmd())
+	return cmd
+}
+
+func run(ctx context.Context) error {
+	cli, err := client.NewImmuClient(client.DefaultOptions().
+		WithAddress(viper.GetString("immudb-address")).
+		WithPort(viper.GetInt("immudb-port")))
+	if err != nil {
+		return err
+	}
+	defer cli.Disconnect()
+
+	login, err := cli.Login(ctx, []byte(viper.GetString("user")), []byte(viper.GetString("password")))
+	if err != nil {
+		return err
+	}
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", login.GetToken()))
+	database := viper.GetString("database")
+	db, err := cli.UseDatabase(ctx, &schema.Database{Databasename: database})
+	if err != nil {
+		return err
+	}
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", db.GetToken()))
+
+	sink := connector.NewKafkaSink(connector.KafkaOptions{
+		Broker:    viper.GetString("kafka-broker"),
+		Topic:     viper.GetString("kafka-topic"),
+		Partition: viper.GetInt32("kafka-partition"),
+		ClientID:  "immucdc",
+		Acks:      int16(viper.GetInt("kafka-acks")),
+		Timeout:   viper.GetDuration("kafka-timeout"),
+	})
+	defer sink.Close()
+
+	opts := connector.DefaultOptions(database)
+	opts.From = viper.GetUint64("from")
+	opts.BatchSize = viper.GetInt("batch-size")
+	opts.PollInterval = viper.GetDuration("poll-interval")
+	opts.StateFile = viper.GetString("state-file")
+	cn, err := connector.New(*cli.GetServiceClient(), sink, opts, logger.NewSimpleLogger("immucdc ", os.Stderr))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "streaming database %s from index %d to topic %s\n", database, cn.State().Next, viper.GetString("kafka-topic"))
+	return cn.Run(ctx)
+}
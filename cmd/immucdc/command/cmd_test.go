@@ -0,0 +1,40 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immucdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCmd(t *testing.T) {
+	cmd := NewCmd()
+	for flag, value := range map[string]string{
+		"database":     "defaultdb",
+		"batch-size":   "100",
+		"kafka-broker": "127.0.0.1:9092",
+		"kafka-topic":  "immudb",
+		"kafka-acks":   "-1",
+	} {
+		f := cmd.Flags().Lookup(flag)
+		require.NotNil(t, f, flag)
+		require.Equal(t, value, f.DefValue, flag)
+	}
+	cmd.SetArgs([]string{"unexpected"})
+	require.Error(t, cmd.Execute())
+}
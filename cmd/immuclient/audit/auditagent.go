@@ -18,6 +18,8 @@ package audit
 
 import (
 	"fmt"
+	"io"
+
 	"github.com/codenotary/immudb/pkg/client/rootservice"
 	"github.com/spf13/cobra"
 	"os"
@@ -51,7 +53,9 @@ type auditAgent struct {
 	opts           *client.Options
 	logger         logger.Logger
 	Pid            server.PIDFile
-	logfile        *os.File
+	logfile        io.Closer
+	gossip         *auditor.Gossip
+	gossipAddress  string
 }
 
 func (a *auditAgent) Manage(args []string, cmd *cobra.Command) (string, error) {
@@ -93,6 +97,9 @@ func (a *auditAgent) Manage(args []string, cmd *cobra.Command) (string, error) {
 				return "", err
 			}
 			fmt.Println(msg)
+			if err = setRestartPolicy(name); err != nil {
+				return "", err
+			}
 
 			if msg, err = a.Daemon.Start(); err != nil {
 				return "", err
@@ -145,11 +152,12 @@ func (a *auditAgent) Manage(args []string, cmd *cobra.Command) (string, error) {
 
 	a.logger = logger.NewSimpleLogger("immuclientd", os.Stdout)
 	if a.opts.LogFileName != "" {
-		a.logger, a.logfile, err = logger.NewFileLogger("immuclientd", a.opts.LogFileName)
-		defer a.logfile.Close()
+		a.logger, a.logfile, err = logger.NewRotatingFileLogger("immuclientd", a.opts.LogFileName,
+			viper.GetInt64("logfile-max-size")<<20, viper.GetInt("logfile-max-backups"))
 		if err != nil {
 			return "", err
 		}
+		defer a.logfile.Close()
 	}
 	if _, err := a.InitAgent(); err != nil {
 		return "", err
@@ -18,12 +18,15 @@ package audit
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/codenotary/immudb/pkg/client/rootservice"
@@ -31,10 +34,13 @@ import (
 	"github.com/codenotary/immudb/pkg/auth"
 	"github.com/codenotary/immudb/pkg/immuos"
 	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/signer"
 
 	"github.com/codenotary/immudb/pkg/client"
 	"github.com/codenotary/immudb/pkg/client/auditor"
 	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/viper"
 )
 
@@ -48,6 +54,12 @@ var ErrAgentNotActive = errors.New("agent not active")
 
 func (cAgent *auditAgent) InitAgent() (AuditAgent, error) {
 	var err error
+	var tracer tracing.Tracer
+	if viper.GetBool("audit-trace") {
+		// the calls to the server are traced as children of the audit steps they belong to
+		tracer = tracing.NewLogTracer(cAgent.logger, viper.GetDuration("audit-trace-threshold"))
+		cAgent.opts.WithTracer(tracer)
+	}
 	if cAgent.immuc, err = client.NewImmuClient(cAgent.opts); err != nil || cAgent.immuc == nil {
 		return nil, fmt.Errorf("Initialization failed: %s \n", err.Error())
 	}
@@ -93,15 +105,161 @@ func (cAgent *auditAgent) InitAgent() (AuditAgent, error) {
 			auditDatabases = append(auditDatabases, dbPrefix)
 		}
 	}
+	var auditorOptions []auditor.Option
+	for _, filter := range []struct {
+		flag   string
+		option func(...*regexp.Regexp) auditor.Option
+	}{
+		{"audit-databases-include", auditor.WithIncludeDatabases},
+		{"audit-databases-exclude", auditor.WithExcludeDatabases},
+	} {
+		if pattern := viper.GetString(filter.flag); len(pattern) > 0 {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s pattern %s: %v", filter.flag, pattern, err)
+			}
+			auditorOptions = append(auditorOptions, filter.option(re))
+		}
+	}
+	var deniedDatabases []string
+	for _, db := range strings.Split(viper.GetString("audit-databases-deny"), ",") {
+		if db = strings.TrimSpace(db); len(db) > 0 {
+			deniedDatabases = append(deniedDatabases, db)
+		}
+	}
+	auditorOptions = append(auditorOptions, auditor.WithDeniedDatabases(deniedDatabases...))
 	auditSignature := viper.GetString("audit-signature")
+	var pinnedRoots []auditor.PinnedRoot
+	for _, pinStr := range strings.Split(viper.GetString("audit-pinned-roots"), ",") {
+		if len(strings.TrimSpace(pinStr)) == 0 {
+			continue
+		}
+		pin, err := auditor.ParsePinnedRoot(pinStr)
+		if err != nil {
+			return nil, err
+		}
+		pinnedRoots = append(pinnedRoots, pin)
+	}
+	var trustedPublicKeys [][]byte
+	for _, path := range strings.Split(viper.GetString("audit-public-keys"), ",") {
+		if path = strings.TrimSpace(path); len(path) == 0 {
+			continue
+		}
+		publicKey, err := signer.LoadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("error loading trusted public key %s: %v", path, err)
+		}
+		trustedPublicKeys = append(trustedPublicKeys, publicKey)
+	}
+	auditorOptions = append(auditorOptions, auditor.WithTrustedPublicKeys(trustedPublicKeys...))
+	var notifiers []auditor.Notifier
+	for _, spec := range strings.Split(viper.GetString("audit-notifiers"), ";") {
+		if len(strings.TrimSpace(spec)) == 0 {
+			continue
+		}
+		notifier, err := auditor.ParseNotifier(spec)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	if digest := viper.GetString("audit-digest"); len(digest) > 0 {
+		period, err := auditor.ParseDigestPeriod(digest)
+		if err != nil {
+			return nil, err
+		}
+		var digestNotifiers []auditor.DigestNotifier
+		for _, notifier := range notifiers {
+			if digestNotifier, ok := notifier.(auditor.DigestNotifier); ok {
+				digestNotifiers = append(digestNotifiers, digestNotifier)
+			}
+		}
+		auditorOptions = append(auditorOptions, auditor.WithDigest(period, digestNotifiers...))
+	}
+	if archive := viper.GetString("audit-proof-archive"); len(archive) > 0 {
+		auditorOptions = append(auditorOptions, auditor.WithProofArchive(auditor.NewFileProofArchive(archive)))
+	}
+	if tracer != nil {
+		auditorOptions = append(auditorOptions, auditor.WithTracer(tracer))
+	}
+	if viper.GetBool("audit-access-control") {
+		auditorOptions = append(auditorOptions, auditor.WithAccessControlAudit())
+	}
+	var gossipPeers []string
+	for _, peer := range strings.Split(viper.GetString("audit-gossip-peers"), ",") {
+		if peer = strings.TrimSpace(peer); len(peer) > 0 {
+			gossipPeers = append(gossipPeers, peer)
+		}
+	}
+	cAgent.gossipAddress = viper.GetString("audit-gossip-address")
+	if len(cAgent.gossipAddress) > 0 || len(gossipPeers) > 0 {
+		if cAgent.gossip, err = auditor.NewGossip(auditor.GossipConfig{
+			ID:             viper.GetString("audit-gossip-id"),
+			Peers:          gossipPeers,
+			HMACKey:        []byte(viper.GetString("audit-gossip-hmac-key")),
+			RequestTimeout: time.Duration(5) * time.Second,
+		}); err != nil {
+			return nil, err
+		}
+		auditorOptions = append(auditorOptions, auditor.WithGossip(cAgent.gossip))
+	}
+	var intervalRules []auditor.IntervalRule
+	for _, spec := range strings.Split(viper.GetString("audit-database-intervals"), ";") {
+		if len(strings.TrimSpace(spec)) == 0 {
+			continue
+		}
+		rule, err := auditor.ParseIntervalRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		intervalRules = append(intervalRules, rule)
+	}
+	if len(intervalRules) > 0 {
+		auditorOptions = append(auditorOptions, auditor.WithDatabaseIntervals(auditor.IntervalsByPattern(intervalRules...)))
+	}
 	auditNotificationURL := viper.GetString("audit-notification-url")
 	auditNotificationUsername := viper.GetString("audit-notification-username")
 	auditNotificationPassword := viper.GetString("audit-notification-password")
+	var auditNotificationSigner crypto.Signer
+	if signingKey := viper.GetString("audit-notification-signing-key"); len(signingKey) > 0 {
+		if auditNotificationSigner, err = auditor.LoadNotificationSigningKey(signingKey); err != nil {
+			return nil, err
+		}
+	}
+	var auditNotificationTemplate *template.Template
+	if nameOrPath := viper.GetString("audit-notification-template"); len(nameOrPath) > 0 {
+		if auditNotificationTemplate, err = auditor.LoadNotificationTemplate(nameOrPath); err != nil {
+			return nil, err
+		}
+	}
 	if len(auditUsername) > 0 || len(auditPassword) > 0 {
 		if _, err = cAgent.immuc.Login(ctx, []byte(auditUsername), []byte(auditPassword)); err != nil {
 			return nil, fmt.Errorf("Invalid login operation: %v", err)
 		}
 	}
+	historyDir, err := stateDir()
+	if err != nil {
+		return nil, err
+	}
+	history := cache.NewHistoryFileCache(historyDir)
+	historyKey, err := cache.LoadCacheKey(viper.GetString("audit-history-key-file"))
+	if err != nil {
+		return nil, err
+	}
+	if backend := viper.GetString("audit-history-backend"); backend != "" {
+		store, err := cache.ParseRemoteStore(backend)
+		if err != nil {
+			return nil, err
+		}
+		if history, err = cache.NewRemoteHistoryCache(store, "immudb-auditor", historyKey); err != nil {
+			return nil, err
+		}
+	} else if historyKey != nil {
+		// kept apart from the plaintext roots, which an encrypted cache would reject as tampered
+		if history, err = cache.NewEncryptedHistoryFileCache(filepath.Join(historyDir, "encrypted"), historyKey); err != nil {
+			return nil, err
+		}
+	}
 	cAgent.ImmuAudit, err = auditor.DefaultAuditor(time.Duration(cAgent.cycleFrequency)*time.Second,
 		fmt.Sprintf("%s:%v", options().Address, options().Port),
 		cliOpts.DialOptions,
@@ -114,11 +272,30 @@ func (cAgent *auditAgent) InitAgent() (AuditAgent, error) {
 			Username:       auditNotificationUsername,
 			Password:       auditNotificationPassword,
 			RequestTimeout: time.Duration(5) * time.Second,
+			HMACKey:        []byte(viper.GetString("audit-notification-hmac-key")),
+			Signer:         auditNotificationSigner,
+			MaxRetries:     viper.GetInt("audit-notification-retries"),
+			RetryBackoff:   viper.GetDuration("audit-notification-retry-backoff"),
+			DeadLetterDir:  filepath.Join(historyDir, "notifications"),
+			Template:       auditNotificationTemplate,
+			ContentType:    viper.GetString("audit-notification-content-type"),
+			MinInterval:    viper.GetDuration("audit-notification-min-interval"),
+			BatchSize:      viper.GetInt("audit-notification-batch-size"),
+			BatchWait:      viper.GetDuration("audit-notification-batch-wait"),
 		},
 		*cAgent.immuc.GetServiceClient(),
 		cAgent.uuidProvider,
-		cache.NewHistoryFileCache(filepath.Join(os.TempDir(), "auditor")),
-		cAgent.metrics.updateMetrics, cAgent.logger)
+		history,
+		cAgent.metrics.updateMetrics, cAgent.logger,
+		append(
+			auditorOptions,
+			auditor.WithPinnedRoots(pinnedRoots...),
+			auditor.WithNotifiers(notifiers...),
+			auditor.WithWorkers(viper.GetInt("audit-workers")),
+			auditor.WithIntervalJitter(viper.GetFloat64("audit-interval-jitter")),
+			auditor.WithAdaptiveInterval(viper.GetDuration("audit-adaptive-min-interval"), viper.GetInt("audit-adaptive-successes")),
+			auditor.WithMetrics(auditor.NewMetrics(prometheus.DefaultRegisterer)),
+			auditor.WithStateStore(auditor.NewFileStateStore(historyDir)))...)
 	if err != nil {
 		return nil, err
 	}
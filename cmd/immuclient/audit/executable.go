@@ -19,6 +19,8 @@ package audit
 import (
 	"fmt"
 	"time"
+
+	"github.com/codenotary/immudb/pkg/client/auditor"
 )
 
 type executable struct {
@@ -50,6 +52,14 @@ func (e *executable) Run() {
 			fmt.Println(err.Error())
 		}
 	}()
+	if e.a.gossip != nil && len(e.a.gossipAddress) > 0 {
+		srv, err := auditor.StartGossipServer(e.a.gossipAddress, e.a.gossip)
+		if err != nil {
+			fmt.Println(err.Error())
+		} else {
+			defer srv.Close()
+		}
+	}
 	fmt.Println(time.Duration(e.a.cycleFrequency) * time.Second)
 	e.a.ImmuAudit.Run(time.Duration(e.a.cycleFrequency)*time.Second, false, e.stop, e.stop)
 }
@@ -0,0 +1,35 @@
+// +build windows
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// setRestartPolicy configures the service control manager to restart the service when it fails: after 5 seconds the
+// first two times and after a minute afterwards, the failure count being reset after a day without failures
+func setRestartPolicy(serviceName string) error {
+	output, err := exec.Command("sc.exe", "failure", serviceName,
+		"reset=", "86400", "actions=", "restart/5000/restart/5000/restart/60000").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error setting the restart policy of %s: %v %s", serviceName, err, output)
+	}
+	return nil
+}
@@ -16,6 +16,14 @@ limitations under the License.
 
 package audit
 
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/codenotary/immudb/cmd/helper"
+	"github.com/spf13/viper"
+)
+
 func stringInSlice(a string, list []string) bool {
 	for _, b := range list {
 		if b == a {
@@ -24,3 +32,17 @@ func stringInSlice(a string, list []string) bool {
 	}
 	return false
 }
+
+// stateDir returns the folder keeping the state of the auditor (root caches, queued notifications) across restarts.
+// It is located in the main directory, /var/lib/immuclient when installed as a service.
+func stateDir() (string, error) {
+	dir := viper.GetString("dir")
+	if len(dir) == 0 {
+		dir = os.TempDir()
+	}
+	dir, err := helper.ResolvePath(filepath.FromSlash(dir), false)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "auditor"), nil
+}
@@ -17,7 +17,12 @@ limitations under the License.
 package audit
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStringInSlice(t *testing.T) {
@@ -29,3 +34,11 @@ func TestStringInSlice(t *testing.T) {
 		t.Fatal("stringInSlice failed, expected false, returned true")
 	}
 }
+
+func TestStateDir(t *testing.T) {
+	viper.Set("dir", filepath.Join("data", "immuclient"))
+	defer viper.Set("dir", os.TempDir())
+	dir, err := stateDir()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("data", "immuclient", "auditor"), dir)
+}
@@ -59,6 +59,7 @@ func (cli *cli) initCommands() {
 	cli.Register(&command{"scan", "Iterate over keys having the specified prefix", cli.scan, []string{"prefix"}, false})
 	cli.Register(&command{"zscan", "Iterate over a sorted set", cli.zScan, []string{"prefix"}, false})
 	cli.Register(&command{"iscan", "Iterate over all elements by insertion order", cli.iScan, []string{"pagenumber", "pagesize"}, false})
+	cli.Register(&command{"diff", "List the entries appended between two indexes, optionally having the specified prefix", cli.diff, []string{"from", "to"}, true})
 	cli.Register(&command{"count", "Count keys having the specified prefix", cli.count, []string{"prefix"}, false})
 
 	// Misc commands
@@ -24,6 +24,10 @@ func (cli *cli) iScan(args []string) (string, error) {
 	return cli.immucl.IScan(args)
 }
 
+func (cli *cli) diff(args []string) (string, error) {
+	return cli.immucl.Diff(args)
+}
+
 func (cli *cli) scan(args []string) (string, error) {
 	return cli.immucl.Scan(args)
 }
@@ -88,6 +88,39 @@ func TestIScan(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true).WithInMemoryStore(true)
+	bs := servertest.NewBufconnServer(options)
+	bs.Start()
+
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts)
+	ic.Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	cli := new(cli)
+	cli.immucl = ic.Imc
+	_, err := cli.set([]string{"key", "val"})
+	if err != nil {
+		t.Fatal("Set fail", err)
+	}
+	_, err = cli.set([]string{"key", "val2"})
+	if err != nil {
+		t.Fatal("Set fail", err)
+	}
+
+	msg, err := cli.diff([]string{"0", "1"})
+
+	if err != nil {
+		t.Fatal("Diff fail", err)
+	}
+	if !strings.Contains(msg, "entries:\t1\n") {
+		t.Fatalf("Diff failed: %s", msg)
+	}
+}
+
 func TestScan(t *testing.T) {
 	options := server.DefaultOptions().WithAuth(true).WithInMemoryStore(true)
 	bs := servertest.NewBufconnServer(options)
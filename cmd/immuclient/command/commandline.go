@@ -57,6 +57,7 @@ func (cl *commandline) Register(rootCmd *cobra.Command) *cobra.Command {
 	// scanners
 	cl.zScan(rootCmd)
 	cl.iScan(rootCmd)
+	cl.diff(rootCmd)
 	cl.scan(rootCmd)
 	cl.count(rootCmd)
 	// references
@@ -19,8 +19,11 @@ package immuclient
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/auditor"
+	"github.com/codenotary/immudb/pkg/client/cache"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -37,6 +40,8 @@ func (cl *commandline) configureFlags(cmd *cobra.Command) error {
 			client.DefaultOptions().TokenFileName))
 	cmd.PersistentFlags().BoolP("mtls", "m", client.DefaultOptions().MTLs, "enable mutual tls")
 	cmd.PersistentFlags().Int("max-recv-msg-size", client.DefaultOptions().MaxRecvMsgSize, "max message size in bytes the client can receive")
+	cmd.PersistentFlags().String("compression", client.DefaultOptions().Compression, "compress the calls to the server: gzip or zstd (if built with cgo). Disabled if empty")
+	cmd.PersistentFlags().Int("compression-threshold", client.DefaultOptions().CompressionThreshold, "size in bytes from which messages are compressed, 0 compresses every call")
 	cmd.PersistentFlags().String("servername", client.DefaultMTLsOptions().Servername, "used to verify the hostname on the returned certificates")
 	cmd.PersistentFlags().String("certificate", client.DefaultMTLsOptions().Certificate, "server certificate file path")
 	cmd.PersistentFlags().String("pkey", client.DefaultMTLsOptions().Pkey, "server private key path")
@@ -45,20 +50,55 @@ func (cl *commandline) configureFlags(cmd *cobra.Command) error {
 	cmd.PersistentFlags().String("roots-filepath", "/tmp/", "Filepath for storing root hashes after every successful audit loop. Default is tempdir of every OS.")
 	cmd.PersistentFlags().String("prometheus-port", "9477", "Launch port of the Prometheus exporter.")
 	cmd.PersistentFlags().String("prometheus-host", "0.0.0.0", "Launch host of the Prometheus exporter.")
-	cmd.PersistentFlags().String("dir", os.TempDir(), "Main directory for audit process tool to initialize")
+	cmd.PersistentFlags().String("dir", os.TempDir(), "Main directory for audit process tool to initialize, the auditor state (root caches, queued notifications) being kept in its 'auditor' subfolder")
+	cmd.PersistentFlags().Int64("logfile-max-size", 0, "Size in megabytes from which the audit process log file is rotated, 0 disables the rotation")
+	cmd.PersistentFlags().Int("logfile-max-backups", 5, "Number of rotated audit process log files kept")
 	cmd.PersistentFlags().String("audit-username", "", "immudb username used to login during audit")
 	cmd.PersistentFlags().String("audit-password", "", "immudb password used to login during audit; can be plain-text or base64 encoded (must be prefixed with 'enc:' if it is encoded)")
 	cmd.PersistentFlags().String("audit-databases", "", "Optional comma-separated list of databases (names) to be audited. Can be full name(s) or just name prefix(es).")
+	cmd.PersistentFlags().String("audit-databases-include", "", "Optional regular expression selecting databases to be audited, in addition to the ones listed in 'audit-databases'.")
+	cmd.PersistentFlags().String("audit-databases-exclude", "", "Optional regular expression of the databases never to be audited, e.g. scratch or test databases.")
+	cmd.PersistentFlags().String("audit-databases-deny", "", "Optional comma-separated list of the names of the databases never to be audited.")
 	cmd.PersistentFlags().String("audit-signature", "", "Audit signature mode. ignore|validate. If 'ignore' is set auditor doesn't check for the root server signature. If 'validate' is set auditor verify that the root is signed properly by immudb server. Default value is 'ignore'")
+	cmd.PersistentFlags().String("audit-public-keys", "", "Optional comma-separated list of PEM files with the trusted public keys of the server. Roots not signed by any of them are rejected, whatever the public key they embed.")
+	cmd.PersistentFlags().String("audit-pinned-roots", "", "Optional comma-separated list of externally known roots in the serverID:database:index:hash format. The auditor must prove consistency with a pinned root before trusting the server for that database.")
 	cmd.PersistentFlags().String("audit-notification-url", "", "If set, auditor will send a POST request at this URL with audit result details.")
 	cmd.PersistentFlags().String("audit-notification-username", "", "Username used to authenticate when publishing audit result to 'audit-notification-url'.")
 	cmd.PersistentFlags().String("audit-notification-password", "", "Password used to authenticate when publishing audit result to 'audit-notification-url'.")
+	cmd.PersistentFlags().String("audit-notification-hmac-key", "", "If set, the body of the audit notifications published to 'audit-notification-url' is authenticated with an HMAC-SHA256 using this key, sent in the X-Immudb-Hmac-Sha256 header.")
+	cmd.PersistentFlags().String("audit-notification-signing-key", "", "Optional path of a PEM encoded Ed25519 or ECDSA private key used to sign the body of the audit notifications published to 'audit-notification-url'. The signature is sent in the X-Immudb-Signature header.")
+	cmd.PersistentFlags().Int("audit-notification-retries", 3, "Number of times an audit notification is sent again if 'audit-notification-url' is unavailable. Notifications still not published are queued on disk and sent after the next successful publish.")
+	cmd.PersistentFlags().Duration("audit-notification-retry-backoff", time.Second, "Wait before the first retry of an audit notification, doubled at every further retry.")
+	cmd.PersistentFlags().String("audit-notification-template", "", "Optional Go template formatting the body of the audit notifications published to 'audit-notification-url', instead of the default JSON: either the path of a template file or the name of a built-in template (slack). The template is executed with the db, the server address, the run time, the tampered flag and the previous and current roots.")
+	cmd.PersistentFlags().String("audit-notification-content-type", "application/json", "Content type of the audit notifications published to 'audit-notification-url'.")
+	cmd.PersistentFlags().Duration("audit-notification-min-interval", 0, "Minimum time between two audit notifications published to 'audit-notification-url': the notifications exceeding the rate are held and published at the end of the following audit runs. 0 disables the rate limiting.")
+	cmd.PersistentFlags().Int("audit-notification-batch-size", 0, "If greater than 0, up to this number of audit notifications are aggregated in a single request to 'audit-notification-url', as a JSON array.")
+	cmd.PersistentFlags().Duration("audit-notification-batch-wait", 0, "Maximum time an audit notification waits for its batch to be full. If 0, partial batches are published at the end of every audit run.")
+	cmd.PersistentFlags().String("audit-proof-archive", "", "Optional path of a file where every consistency proof fetched by the auditor is appended, along with the roots and the server UUID, to verify the audit trail again offline. If it is an existing directory, a file per audited server is created inside it.")
+	cmd.PersistentFlags().Bool("audit-access-control", true, "Compare at every audit a hash of the users and permissions of the server with the previous one, notifying any change")
+	cmd.PersistentFlags().String("audit-database-intervals", "", "Optional semicolon-separated list of pattern=duration rules, e.g. '^logs=1h;^payments=0s'. Databases matching a pattern are audited at most once per duration, the first matching rule applies; the others at every turn.")
+	cmd.PersistentFlags().Float64("audit-interval-jitter", 0, "Fraction of the audit interval, between 0 and 1, by which every wait between two audits is randomly lengthened or shortened")
+	cmd.PersistentFlags().Duration("audit-adaptive-min-interval", 0, "If lower than the audit interval, the interval is halved down to this value after every audit meeting an error or a verification failure, and doubled back after audit-adaptive-successes successful audits in a row")
+	cmd.PersistentFlags().Int("audit-adaptive-successes", auditor.DefaultAdaptiveSuccesses, "Number of successful audits in a row after which an audit interval shortened by audit-adaptive-min-interval is doubled back")
+	cmd.PersistentFlags().String("audit-history-key-file", "", "Optional file holding the base64 encoded 32 bytes key the roots trusted by the auditor are encrypted and authenticated with, so that they cannot be rewritten to hide a tampering. If not set, the key is read from the "+cache.CacheKeyEnv+" environment variable, if any.")
+	cmd.PersistentFlags().String("audit-gossip-address", "", "If set, the address, e.g. :9478, where the auditor receives the roots trusted by its peers, to detect servers showing different views of their history to different auditors.")
+	cmd.PersistentFlags().String("audit-gossip-peers", "", "Comma separated base URLs, e.g. http://auditor-2:9478, of the other auditors the trusted roots are published to. Divergent roots are notified as tampering.")
+	cmd.PersistentFlags().String("audit-gossip-id", "", "Name identifying the auditor to its gossip peers, the host name is used if empty.")
+	cmd.PersistentFlags().String("audit-gossip-hmac-key", "", "If set, the roots exchanged with the gossip peers are authenticated with an HMAC-SHA256 using this key, which all the peers must share.")
+	cmd.PersistentFlags().String("audit-history-backend", "", "Optional remote store keeping the roots trusted by the auditor, so that they survive restarts and are shared between auditor replicas, in the kind?key=value&key=value format. Supported kinds are redis (address, password, db, timeout, tls), s3 (endpoint, region, bucket, path-style, access-key-id, secret-access-key, session-token, timeout) and etcd (endpoint, username, password, timeout).")
+	cmd.PersistentFlags().Bool("audit-trace", false, "Log a span, identified by its W3C trace context, for every audit step (login, use-database, current-root, consistency, publish-notification) and every call to the server, to see where slow audits spend their time. The trace context is forwarded to the server in the traceparent gRPC header.")
+	cmd.PersistentFlags().Duration("audit-trace-threshold", 0, "Minimum duration of the spans logged with audit-trace, e.g. 500ms to only log the slow steps")
+	cmd.PersistentFlags().Int("audit-workers", 0, "Number of databases audited in parallel. If greater than 1, all databases are audited at every run, otherwise a single database is audited at every run")
+	cmd.PersistentFlags().String("audit-notifiers", "", "Optional semicolon-separated list of additional notifiers publishing audit results, each in the kind?key=value&key=value format. Built-in kinds are kafka (proxy-url, topic, timeout), smtp (address, username, password, from, to) and syslog (network, address, tag).")
+	cmd.PersistentFlags().String("audit-digest", "", "Optional period (daily, weekly or a duration such as 12h) of a digest of the audits performed, databases covered, results and root progression, published to the audit-notifiers at the end of every period")
 
 	viper.BindPFlag("immudb-port", cmd.PersistentFlags().Lookup("immudb-port"))
 	viper.BindPFlag("immudb-address", cmd.PersistentFlags().Lookup("immudb-address"))
 	viper.BindPFlag("tokenfile", cmd.PersistentFlags().Lookup("tokenfile"))
 	viper.BindPFlag("mtls", cmd.PersistentFlags().Lookup("mtls"))
 	viper.BindPFlag("max-recv-msg-size", cmd.PersistentFlags().Lookup("max-recv-msg-size"))
+	viper.BindPFlag("compression", cmd.PersistentFlags().Lookup("compression"))
+	viper.BindPFlag("compression-threshold", cmd.PersistentFlags().Lookup("compression-threshold"))
 	viper.BindPFlag("servername", cmd.PersistentFlags().Lookup("servername"))
 	viper.BindPFlag("certificate", cmd.PersistentFlags().Lookup("certificate"))
 	viper.BindPFlag("pkey", cmd.PersistentFlags().Lookup("pkey"))
@@ -68,19 +108,54 @@ func (cl *commandline) configureFlags(cmd *cobra.Command) error {
 	viper.BindPFlag("prometheus-port", cmd.PersistentFlags().Lookup("prometheus-port"))
 	viper.BindPFlag("prometheus-host", cmd.PersistentFlags().Lookup("prometheus-host"))
 	viper.BindPFlag("dir", cmd.PersistentFlags().Lookup("dir"))
+	viper.BindPFlag("logfile-max-size", cmd.PersistentFlags().Lookup("logfile-max-size"))
+	viper.BindPFlag("logfile-max-backups", cmd.PersistentFlags().Lookup("logfile-max-backups"))
 	viper.BindPFlag("audit-username", cmd.PersistentFlags().Lookup("audit-username"))
 	viper.BindPFlag("audit-password", cmd.PersistentFlags().Lookup("audit-password"))
 	viper.BindPFlag("audit-databases", cmd.PersistentFlags().Lookup("audit-databases"))
+	viper.BindPFlag("audit-databases-include", cmd.PersistentFlags().Lookup("audit-databases-include"))
+	viper.BindPFlag("audit-databases-exclude", cmd.PersistentFlags().Lookup("audit-databases-exclude"))
+	viper.BindPFlag("audit-databases-deny", cmd.PersistentFlags().Lookup("audit-databases-deny"))
 	viper.BindPFlag("audit-signature", cmd.PersistentFlags().Lookup("audit-signature"))
+	viper.BindPFlag("audit-pinned-roots", cmd.PersistentFlags().Lookup("audit-pinned-roots"))
+	viper.BindPFlag("audit-public-keys", cmd.PersistentFlags().Lookup("audit-public-keys"))
 	viper.BindPFlag("audit-notification-url", cmd.PersistentFlags().Lookup("audit-notification-url"))
 	viper.BindPFlag("audit-notification-username", cmd.PersistentFlags().Lookup("audit-notification-username"))
 	viper.BindPFlag("audit-notification-password", cmd.PersistentFlags().Lookup("audit-notification-password"))
+	viper.BindPFlag("audit-notification-hmac-key", cmd.PersistentFlags().Lookup("audit-notification-hmac-key"))
+	viper.BindPFlag("audit-notification-signing-key", cmd.PersistentFlags().Lookup("audit-notification-signing-key"))
+	viper.BindPFlag("audit-notification-retries", cmd.PersistentFlags().Lookup("audit-notification-retries"))
+	viper.BindPFlag("audit-notification-retry-backoff", cmd.PersistentFlags().Lookup("audit-notification-retry-backoff"))
+	viper.BindPFlag("audit-notification-template", cmd.PersistentFlags().Lookup("audit-notification-template"))
+	viper.BindPFlag("audit-notification-content-type", cmd.PersistentFlags().Lookup("audit-notification-content-type"))
+	viper.BindPFlag("audit-notification-min-interval", cmd.PersistentFlags().Lookup("audit-notification-min-interval"))
+	viper.BindPFlag("audit-notification-batch-size", cmd.PersistentFlags().Lookup("audit-notification-batch-size"))
+	viper.BindPFlag("audit-notification-batch-wait", cmd.PersistentFlags().Lookup("audit-notification-batch-wait"))
+	viper.BindPFlag("audit-notifiers", cmd.PersistentFlags().Lookup("audit-notifiers"))
+	viper.BindPFlag("audit-digest", cmd.PersistentFlags().Lookup("audit-digest"))
+	viper.BindPFlag("audit-proof-archive", cmd.PersistentFlags().Lookup("audit-proof-archive"))
+	viper.BindPFlag("audit-database-intervals", cmd.PersistentFlags().Lookup("audit-database-intervals"))
+	viper.BindPFlag("audit-workers", cmd.PersistentFlags().Lookup("audit-workers"))
+	viper.BindPFlag("audit-history-key-file", cmd.PersistentFlags().Lookup("audit-history-key-file"))
+	viper.BindPFlag("audit-history-backend", cmd.PersistentFlags().Lookup("audit-history-backend"))
+	viper.BindPFlag("audit-gossip-address", cmd.PersistentFlags().Lookup("audit-gossip-address"))
+	viper.BindPFlag("audit-gossip-peers", cmd.PersistentFlags().Lookup("audit-gossip-peers"))
+	viper.BindPFlag("audit-gossip-id", cmd.PersistentFlags().Lookup("audit-gossip-id"))
+	viper.BindPFlag("audit-gossip-hmac-key", cmd.PersistentFlags().Lookup("audit-gossip-hmac-key"))
+	viper.BindPFlag("audit-trace", cmd.PersistentFlags().Lookup("audit-trace"))
+	viper.BindPFlag("audit-trace-threshold", cmd.PersistentFlags().Lookup("audit-trace-threshold"))
+	viper.BindPFlag("audit-interval-jitter", cmd.PersistentFlags().Lookup("audit-interval-jitter"))
+	viper.BindPFlag("audit-adaptive-min-interval", cmd.PersistentFlags().Lookup("audit-adaptive-min-interval"))
+	viper.BindPFlag("audit-adaptive-successes", cmd.PersistentFlags().Lookup("audit-adaptive-successes"))
+	viper.BindPFlag("audit-access-control", cmd.PersistentFlags().Lookup("audit-access-control"))
 
 	viper.SetDefault("immudb-port", client.DefaultOptions().Port)
 	viper.SetDefault("immudb-address", client.DefaultOptions().Address)
 	viper.SetDefault("tokenfile", client.DefaultOptions().TokenFileName)
 	viper.SetDefault("mtls", client.DefaultOptions().MTLs)
 	viper.SetDefault("max-recv-msg-size", client.DefaultOptions().MaxRecvMsgSize)
+	viper.SetDefault("compression", client.DefaultOptions().Compression)
+	viper.SetDefault("compression-threshold", client.DefaultOptions().CompressionThreshold)
 	viper.SetDefault("servername", client.DefaultMTLsOptions().Servername)
 	viper.SetDefault("certificate", client.DefaultMTLsOptions().Certificate)
 	viper.SetDefault("pkey", client.DefaultMTLsOptions().Pkey)
@@ -93,9 +168,42 @@ func (cl *commandline) configureFlags(cmd *cobra.Command) error {
 	viper.SetDefault("audit-username", "")
 	viper.SetDefault("audit-signature", "ignore")
 	viper.SetDefault("audit-databases", "")
+	viper.SetDefault("audit-databases-include", "")
+	viper.SetDefault("audit-databases-exclude", "")
+	viper.SetDefault("audit-databases-deny", "")
+	viper.SetDefault("audit-pinned-roots", "")
+	viper.SetDefault("audit-public-keys", "")
 	viper.SetDefault("audit-notification-url", "")
 	viper.SetDefault("audit-notification-username", "")
+	viper.SetDefault("audit-notifiers", "")
+	viper.SetDefault("audit-digest", "")
+	viper.SetDefault("audit-workers", 0)
+	viper.SetDefault("audit-database-intervals", "")
+	viper.SetDefault("audit-history-key-file", "")
+	viper.SetDefault("audit-history-backend", "")
+	viper.SetDefault("audit-gossip-address", "")
+	viper.SetDefault("audit-gossip-peers", "")
+	viper.SetDefault("audit-gossip-id", "")
+	viper.SetDefault("audit-gossip-hmac-key", "")
+	viper.SetDefault("audit-trace", false)
+	viper.SetDefault("audit-trace-threshold", 0)
+	viper.SetDefault("audit-interval-jitter", 0)
+	viper.SetDefault("audit-adaptive-min-interval", 0)
+	viper.SetDefault("audit-adaptive-successes", auditor.DefaultAdaptiveSuccesses)
+	viper.SetDefault("audit-access-control", true)
+	viper.SetDefault("audit-proof-archive", "")
 	viper.SetDefault("audit-notification-password", "")
+	viper.SetDefault("audit-notification-hmac-key", "")
+	viper.SetDefault("audit-notification-signing-key", "")
+	viper.SetDefault("audit-notification-retries", 3)
+	viper.SetDefault("audit-notification-retry-backoff", time.Second)
+	viper.SetDefault("audit-notification-template", "")
+	viper.SetDefault("audit-notification-content-type", "application/json")
+	viper.SetDefault("audit-notification-min-interval", time.Duration(0))
+	viper.SetDefault("audit-notification-batch-size", 0)
+	viper.SetDefault("audit-notification-batch-wait", time.Duration(0))
 	viper.SetDefault("dir", os.TempDir())
+	viper.SetDefault("logfile-max-size", 0)
+	viper.SetDefault("logfile-max-backups", 5)
 	return nil
 }
@@ -32,7 +32,7 @@ import (
 
 func TestInit(t *testing.T) {
 	cm := NewCommand()
-	require.Len(t, cm.Commands(), 28, "fail immuclient commands, wrong number of expected commands")
+	require.Len(t, cm.Commands(), 29, "fail immuclient commands, wrong number of expected commands")
 }
 
 func TestConnect(t *testing.T) {
@@ -18,6 +18,7 @@ package immuclient
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/codenotary/immudb/cmd/immuclient/audit"
 	"github.com/codenotary/immudb/cmd/immuclient/cli"
@@ -30,10 +31,24 @@ func (cl *commandline) history(cmd *cobra.Command) {
 		Use:               "history key",
 		Short:             "Fetch history for the item having the specified key",
 		Aliases:           []string{"h"},
+		Example:           "history mykey --timeline --verify",
 		PersistentPreRunE: cl.ConfigChain(cl.connect),
 		PersistentPostRun: cl.disconnect,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			resp, err := cl.immucl.History(args)
+			timeline, err := cmd.Flags().GetBool("timeline")
+			if err != nil {
+				cl.quit(err)
+			}
+			verify, err := cmd.Flags().GetBool("verify")
+			if err != nil {
+				cl.quit(err)
+			}
+			var resp string
+			if timeline || verify {
+				resp, err = cl.immucl.HistoryTimeline([]string{args[0], strconv.FormatBool(verify)})
+			} else {
+				resp, err = cl.immucl.History(args)
+			}
 			if err != nil {
 				cl.quit(err)
 			}
@@ -42,6 +57,8 @@ func (cl *commandline) history(cmd *cobra.Command) {
 		},
 		Args: cobra.ExactArgs(1),
 	}
+	ccmd.Flags().Bool("timeline", false, "show one line per revision, oldest first, with its index, time, value size, hash and whether it is covered by the locally cached root")
+	ccmd.Flags().Bool("verify", false, "verify the inclusion of every revision of the timeline (implies --timeline)")
 	cmd.AddCommand(ccmd)
 }
 
@@ -18,6 +18,7 @@ package immuclient
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 )
@@ -62,6 +63,42 @@ func (cl *commandline) iScan(cmd *cobra.Command) {
 	cmd.AddCommand(ccmd)
 }
 
+func (cl *commandline) diff(cmd *cobra.Command) {
+	ccmd := &cobra.Command{
+		Use:               "diff",
+		Short:             "List the entries appended between two indexes, e.g. the ones of two audited roots",
+		Example:           "diff --from 10 --to 20 --prefix user",
+		PersistentPreRunE: cl.ConfigChain(cl.connect),
+		PersistentPostRun: cl.disconnect,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := cmd.Flags().GetUint64("from")
+			if err != nil {
+				cl.quit(err)
+			}
+			to, err := cmd.Flags().GetUint64("to")
+			if err != nil {
+				cl.quit(err)
+			}
+			prefix, err := cmd.Flags().GetString("prefix")
+			if err != nil {
+				cl.quit(err)
+			}
+			resp, err := cl.immucl.Diff([]string{strconv.FormatUint(from, 10), strconv.FormatUint(to, 10), prefix})
+			if err != nil {
+				cl.quit(err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), resp+"\n")
+			return nil
+		},
+		Args: cobra.NoArgs,
+	}
+	ccmd.Flags().Uint64("from", 0, "index after which entries are listed")
+	ccmd.Flags().Uint64("to", 0, "index of the last listed entry")
+	ccmd.Flags().String("prefix", "", "list only the entries having keys with this prefix")
+	ccmd.MarkFlagRequired("to")
+	cmd.AddCommand(ccmd)
+}
+
 func (cl *commandline) scan(cmd *cobra.Command) {
 	ccmd := &cobra.Command{
 		Use:               "scan prefix",
@@ -122,6 +122,52 @@ func TestIScan(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	defer os.Remove(".root-")
+	options := server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword)
+	bs := servertest.NewBufconnServer(options)
+	bs.Start()
+
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts)
+	ic.Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	cmdl := commandline{
+		config: helper.Config{Name: "immuclient"},
+		immucl: ic.Imc,
+	}
+	cmd, _ := cmdl.NewCmd()
+	cmdl.diff(cmd)
+	b := bytes.NewBufferString("")
+	cmd.SetOut(b)
+	cmdl.immucl.Set([]string{"key1", "value"})
+	cmdl.immucl.Set([]string{"other", "value"})
+	cmdl.immucl.Set([]string{"key2", "value"})
+
+	cmd.SetArgs([]string{"diff", "--from", "0", "--to", "2", "--prefix", "key"})
+
+	// remove ConfigChain method to avoid options override
+	cmd.PersistentPreRunE = nil
+	innercmd := cmd.Commands()[0]
+	innercmd.PersistentPreRunE = nil
+
+	err := cmd.Execute()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := ioutil.ReadAll(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(msg), "key2") || !strings.Contains(string(msg), "entries:\t1\n") {
+		t.Fatal(string(msg))
+	}
+}
+
 func TestScan(t *testing.T) {
 	defer os.Remove(".root-")
 	options := server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword)
@@ -44,11 +44,13 @@ type Client interface {
 	Login(args []string) (string, error)
 	Logout(args []string) (string, error)
 	History(args []string) (string, error)
+	HistoryTimeline(args []string) (string, error)
 	HealthCheck(args []string) (string, error)
 	Reference(args []string) (string, error)
 	SafeReference(args []string) (string, error)
 	ZScan(args []string) (string, error)
 	IScan(args []string) (string, error)
+	Diff(args []string) (string, error)
 	Scan(args []string) (string, error)
 	Count(args []string) (string, error)
 	RawSafeSet(args []string) (string, error)
@@ -122,6 +124,8 @@ func Options() *client.Options {
 		WithAddress(viper.GetString("immudb-address")).
 		WithTokenFileName(viper.GetString("tokenfile")).
 		WithMTLs(viper.GetBool("mtls")).
+		WithCompression(viper.GetString("compression")).
+		WithCompressionThreshold(viper.GetInt("compression-threshold")).
 		WithTokenService(client.NewTokenService().WithTokenFileName(viper.GetString("tokenfile")).WithHds(client.NewHomedirService()))
 	if viper.GetBool("mtls") {
 		// todo https://golang.org/src/crypto/x509/root_linux.go
@@ -19,6 +19,7 @@ package immuc
 import (
 	"context"
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"strconv"
 	"strings"
 )
 
@@ -47,6 +48,29 @@ func (i *immuc) History(args []string) (string, error) {
 	return str.String(), nil
 }
 
+// HistoryTimeline prints the revisions of the key args[0], oldest first, telling whether each one is covered
+// by the locally cached root. If args[1] is true, the inclusion of every revision is verified as well.
+func (i *immuc) HistoryTimeline(args []string) (string, error) {
+	key := []byte(args[0])
+	verify := false
+	if len(args) > 1 {
+		var err error
+		if verify, err = strconv.ParseBool(args[1]); err != nil {
+			return "", err
+		}
+	}
+	ctx := context.Background()
+	timeline, err := i.ImmuClient.HistoryTimeline(ctx, key, verify)
+	if err != nil {
+		rpcerrors := strings.SplitAfter(err.Error(), "=")
+		if len(rpcerrors) > 1 {
+			return rpcerrors[len(rpcerrors)-1], nil
+		}
+		return "", err
+	}
+	return PrintTimeline(timeline, verify), nil
+}
+
 func (i *immuc) HealthCheck(args []string) (string, error) {
 	ctx := context.Background()
 	if err := i.ImmuClient.HealthCheck(ctx); err != nil {
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
 	"github.com/codenotary/immudb/pkg/client/clienttest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
@@ -34,7 +35,7 @@ func TestMiscErrors(t *testing.T) {
 
 	// History errors
 	args := []string{"key1"}
-	immuClientMock.HistoryF = func(context.Context, *schema.HistoryOptions) (*schema.StructuredItemList, error) {
+	immuClientMock.HistoryF = func(context.Context, *schema.HistoryOptions) (*client.StructuredItemPage, error) {
 		return nil, status.New(codes.Internal, "history RPC error").Err()
 	}
 	resp, err := ic.History(args)
@@ -42,7 +43,7 @@ func TestMiscErrors(t *testing.T) {
 	require.Equal(t, " history RPC error", resp)
 
 	errHistory := errors.New("history error")
-	immuClientMock.HistoryF = func(context.Context, *schema.HistoryOptions) (*schema.StructuredItemList, error) {
+	immuClientMock.HistoryF = func(context.Context, *schema.HistoryOptions) (*client.StructuredItemPage, error) {
 		return nil, errHistory
 	}
 	_, err = ic.History(args)
@@ -18,6 +18,7 @@ package immuc_test
 
 import (
 	"github.com/codenotary/immudb/pkg/client"
+	"os"
 	"strings"
 	"testing"
 
@@ -98,3 +99,42 @@ func TestHistory(t *testing.T) {
 		t.Fatalf("History fail %s", msg)
 	}
 }
+
+func TestHistoryTimeline(t *testing.T) {
+	defer os.Remove(".root-")
+	options := server.DefaultOptions().WithAuth(true).WithInMemoryStore(true)
+	bs := servertest.NewBufconnServer(options)
+	bs.Start()
+
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts)
+	ic.Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	msg, err := ic.Imc.HistoryTimeline([]string{"key"})
+	if err != nil {
+		t.Fatal("HistoryTimeline fail", err)
+	}
+	if !strings.Contains(msg, "No item found") {
+		t.Fatalf("HistoryTimeline fail %s", msg)
+	}
+
+	for _, value := range []string{"first", "second"} {
+		if _, err = ic.Imc.Set([]string{"key", value}); err != nil {
+			t.Fatal("HistoryTimeline fail", err)
+		}
+	}
+	msg, err = ic.Imc.HistoryTimeline([]string{"key", "true"})
+	if err != nil {
+		t.Fatal("HistoryTimeline fail", err)
+	}
+	if !strings.Contains(msg, "VERIFIED") || strings.Count(msg, "true") != 2 {
+		t.Fatalf("HistoryTimeline fail %s", msg)
+	}
+
+	if _, err = ic.Imc.HistoryTimeline([]string{"key", "maybe"}); err == nil {
+		t.Fatal("HistoryTimeline fail, an invalid verify flag is expected to fail")
+	}
+}
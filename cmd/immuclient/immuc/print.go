@@ -18,7 +18,9 @@ package immuc
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/codenotary/immudb/pkg/api"
@@ -141,6 +143,41 @@ func PrintByIndex(item *schema.StructuredItem, valueOnly bool) string {
 	return str.String()
 }
 
+// PrintTimeline prints the revisions of a key, one per line, as a chain of custody of its value
+func PrintTimeline(timeline *client.KeyTimeline, verify bool) string {
+	str := strings.Builder{}
+	str.WriteString(fmt.Sprintf("key:		%s\n", timeline.Key))
+	str.WriteString(fmt.Sprintf("local root:	%d %x\n", timeline.LocalRoot.GetIndex(), timeline.LocalRoot.GetRoot()))
+	if len(timeline.Entries) == 0 {
+		str.WriteString("No item found \n")
+		return str.String()
+	}
+	w := tabwriter.NewWriter(&str, 0, 0, 2, ' ', 0)
+	header := "#\tINDEX\tTIME\tSIZE\tHASH\tLOCAL ROOT"
+	if verify {
+		header += "\tVERIFIED"
+	}
+	fmt.Fprintln(w, header)
+	for n, entry := range timeline.Entries {
+		covered := "not covered"
+		if entry.Covered {
+			covered = "covered"
+		}
+		// tombstones have neither a value nor its timestamp
+		tm, size := time.Unix(int64(entry.Time), 0).Format(time.RFC3339), strconv.Itoa(entry.ValueSize)
+		if entry.Deleted {
+			tm, size = "-", "deleted"
+		}
+		line := fmt.Sprintf("%d\t%d\t%s\t%s\t%x\t%s", n+1, entry.Index, tm, size, entry.Hash, covered)
+		if verify {
+			line += fmt.Sprintf("\t%t", entry.Verified)
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+	return str.String()
+}
+
 // PadRight ...
 func PadRight(str, pad string, length int) string {
 	for {
@@ -17,6 +17,7 @@ limitations under the License.
 package immuc
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -24,6 +25,9 @@ import (
 	"strings"
 )
 
+// diffPageSize is the number of entries fetched at once by Diff
+const diffPageSize = 100
+
 func (i *immuc) ZScan(args []string) (string, error) {
 	set := []byte(args[0])
 	ctx := context.Background()
@@ -78,6 +82,59 @@ func (i *immuc) IScan(args []string) (string, error) {
 	return str.String(), nil
 }
 
+// Diff lists the entries appended after index args[0] up to index args[1] included, optionally restricted
+// to the keys having the prefix args[2], followed by their count and size.
+func (i *immuc) Diff(args []string) (string, error) {
+	from, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return "", err
+	}
+	to, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return "", err
+	}
+	if to <= from {
+		return "", fmt.Errorf("the to index %d must be greater than the from index %d", to, from)
+	}
+	var prefix []byte
+	if len(args) > 2 {
+		prefix = []byte(args[2])
+	}
+
+	ctx := context.Background()
+	str := strings.Builder{}
+	var count, keysSize, valuesSize uint64
+
+	// pages are aligned to multiples of their size, the entries out of (from, to] are skipped
+	for page := (from + 1) / diffPageSize; page <= to/diffPageSize; page++ {
+		response, err := i.ImmuClient.IScan(ctx, page+1, diffPageSize)
+		if err != nil {
+			rpcerrors := strings.SplitAfter(err.Error(), "=")
+			if len(rpcerrors) > 1 {
+				return rpcerrors[len(rpcerrors)-1], nil
+			}
+			return "", err
+		}
+		for _, item := range response.Items {
+			if item.Index <= from || item.Index > to || !bytes.HasPrefix(item.Key, prefix) {
+				continue
+			}
+			count++
+			keysSize += uint64(len(item.Key))
+			valuesSize += uint64(len(item.Value.GetPayload()))
+			str.WriteString(fmt.Sprintf("index:\t\t%d\nkey:\t\t%s\nvalue size:\t%d\n\n", item.Index, item.Key, len(item.Value.GetPayload())))
+		}
+		if !response.More {
+			break
+		}
+	}
+
+	str.WriteString(fmt.Sprintf("entries:\t%d\n", count))
+	str.WriteString(fmt.Sprintf("keys size:\t%d bytes\n", keysSize))
+	str.WriteString(fmt.Sprintf("values size:\t%d bytes", valuesSize))
+	return str.String(), nil
+}
+
 func (i *immuc) Scan(args []string) (string, error) {
 	prefix := []byte(args[0])
 	ctx := context.Background()
@@ -25,6 +25,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
 	"github.com/codenotary/immudb/pkg/client/clienttest"
 	"github.com/stretchr/testify/require"
 )
@@ -35,7 +36,7 @@ func TestScannersErrors(t *testing.T) {
 
 	// ZScan errors
 	args := []string{"set1"}
-	immuClientMock.ZScanF = func(context.Context, *schema.ZScanOptions) (*schema.ZStructuredItemList, error) {
+	immuClientMock.ZScanF = func(context.Context, *schema.ZScanOptions) (*client.ZStructuredItemPage, error) {
 		return nil, status.New(codes.Internal, "zscan RPC error").Err()
 	}
 	resp, err := ic.ZScan(args)
@@ -43,14 +44,14 @@ func TestScannersErrors(t *testing.T) {
 	require.Equal(t, " zscan RPC error", resp)
 
 	errZScan := errors.New("zscan error")
-	immuClientMock.ZScanF = func(context.Context, *schema.ZScanOptions) (*schema.ZStructuredItemList, error) {
+	immuClientMock.ZScanF = func(context.Context, *schema.ZScanOptions) (*client.ZStructuredItemPage, error) {
 		return nil, errZScan
 	}
 	_, err = ic.ZScan(args)
 	require.Equal(t, errZScan, err)
 
-	immuClientMock.ZScanF = func(context.Context, *schema.ZScanOptions) (*schema.ZStructuredItemList, error) {
-		return &schema.ZStructuredItemList{}, nil
+	immuClientMock.ZScanF = func(context.Context, *schema.ZScanOptions) (*client.ZStructuredItemPage, error) {
+		return &client.ZStructuredItemPage{ZStructuredItemList: &schema.ZStructuredItemList{}}, nil
 	}
 	resp, err = ic.ZScan(args)
 	require.NoError(t, err)
@@ -87,7 +88,7 @@ func TestScannersErrors(t *testing.T) {
 
 	// Scan errors
 	args = []string{"prefix1"}
-	immuClientMock.ScanF = func(context.Context, *schema.ScanOptions) (*schema.StructuredItemList, error) {
+	immuClientMock.ScanF = func(context.Context, *schema.ScanOptions) (*client.StructuredItemPage, error) {
 		return nil, status.New(codes.Internal, "scan RPC error").Err()
 	}
 	resp, err = ic.Scan(args)
@@ -95,14 +96,14 @@ func TestScannersErrors(t *testing.T) {
 	require.Equal(t, " scan RPC error", resp)
 
 	errScan := errors.New("scan error")
-	immuClientMock.ScanF = func(context.Context, *schema.ScanOptions) (*schema.StructuredItemList, error) {
+	immuClientMock.ScanF = func(context.Context, *schema.ScanOptions) (*client.StructuredItemPage, error) {
 		return nil, errScan
 	}
 	_, err = ic.Scan(args)
 	require.Equal(t, errScan, err)
 
-	immuClientMock.ScanF = func(context.Context, *schema.ScanOptions) (*schema.StructuredItemList, error) {
-		return &schema.StructuredItemList{}, nil
+	immuClientMock.ScanF = func(context.Context, *schema.ScanOptions) (*client.StructuredItemPage, error) {
+		return &client.StructuredItemPage{StructuredItemList: &schema.StructuredItemList{}}, nil
 	}
 	resp, err = ic.Scan(args)
 	require.NoError(t, err)
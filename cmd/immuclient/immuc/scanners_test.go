@@ -18,6 +18,7 @@ package immuc_test
 
 import (
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -132,3 +133,46 @@ func TestCount(t *testing.T) {
 		t.Fatalf("Count failed: %s", msg)
 	}
 }
+
+func TestDiff(t *testing.T) {
+	options := server.DefaultOptions().WithAuth(true).WithInMemoryStore(true)
+	bs := servertest.NewBufconnServer(options)
+	bs.Start()
+	ts := client.NewTokenService().WithTokenFileName("testTokenFile").WithHds(&test.HomedirServiceMock{})
+	ic := test.NewClientTest(&test.PasswordReader{
+		Pass: []string{"immudb"},
+	}, ts)
+	ic.Connect(bs.Dialer)
+	ic.Login("immudb")
+
+	// entries spanning several pages
+	for i := 0; i < 150; i++ {
+		key := "key"
+		if i%2 == 0 {
+			key = "other"
+		}
+		if _, err := ic.Imc.Set([]string{key + strconv.Itoa(i), "val"}); err != nil {
+			t.Fatal("Set fail", err)
+		}
+	}
+
+	msg, err := ic.Imc.Diff([]string{"90", "120"})
+	if err != nil {
+		t.Fatal("Diff fail", err)
+	}
+	if !strings.Contains(msg, "entries:\t30\n") || strings.Contains(msg, "other90\n") || !strings.Contains(msg, "other120\n") {
+		t.Fatalf("Diff failed: %s", msg)
+	}
+
+	msg, err = ic.Imc.Diff([]string{"90", "120", "key"})
+	if err != nil {
+		t.Fatal("Diff fail", err)
+	}
+	if !strings.Contains(msg, "entries:\t15\n") || strings.Contains(msg, "other") {
+		t.Fatalf("Diff failed: %s", msg)
+	}
+
+	if _, err = ic.Imc.Diff([]string{"120", "90"}); err == nil {
+		t.Fatal("Diff expected error")
+	}
+}
@@ -25,6 +25,8 @@ immudb-address = "127.0.0.1"
 immudb-port = 3322
 pidfile = "/var/lib/immuclient/immuclient.pid"
 logfile = "/var/log/immuclient/immuclient.log"
+logfile-max-size = 100
+logfile-max-backups = 5
 mtls = false
 detached = false
 servername = "localhost"
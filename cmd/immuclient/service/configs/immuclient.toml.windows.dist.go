@@ -25,6 +25,8 @@ immudb-address = "127.0.0.1"
 immudb-port = 3322
 pidfile = "%programdata%\\Immuclient\\config\\immuclient.pid"
 logfile = "%programdata%\\Immuclient\\config\\immuclient.log"
+logfile-max-size = 100
+logfile-max-backups = 5
 mtls = false
 detached = false
 servername = "localhost"
@@ -29,11 +29,15 @@ var StartUpConfig = fmt.Sprintf(`[Unit]
 Description={{.Description}}
 Requires={{.Dependencies}}
 After={{.Dependencies}}
+StartLimitIntervalSec=0
 [Service]
 PIDFile=/var/lib/immuclient/{{.Name}}.pid
 ExecStartPre=/bin/rm -f /var/lib/immuclient/{{.Name}}.pid
 ExecStart={{.Path}} {{.Args}}
 Restart=on-failure
+RestartSec=5
+StateDirectory=immuclient
+LogsDirectory=immuclient
 User=%s
 Group=%s
 [Install]
@@ -41,7 +45,9 @@ WantedBy=multi-user.target
 `, OSUser, OSGroup)
 
 // UsageDet details on config and log file on specific os
-var UsageDet = fmt.Sprintf(`Config file is present in %s. Log file is in /var/log/immuclient`, ConfigPath)
+var UsageDet = fmt.Sprintf(`Config file is present in %s. Log file is in /var/log/immuclient, rotated according to logfile-max-size and logfile-max-backups.
+The auditor state (root caches, queued notifications) is kept in /var/lib/immuclient/auditor and preserved on uninstall.
+The daemon is restarted 5 seconds after a failure`, ConfigPath)
 
 // UsageExamples usage examples for linux
 var UsageExamples = `
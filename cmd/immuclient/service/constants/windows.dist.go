@@ -28,7 +28,9 @@ const OSGroup = ""
 var StartUpConfig = ""
 
 // UsageDet details on config and log file on specific os
-var UsageDet = fmt.Sprintf(`Config and log files are present in C:\ProgramData\Immudb folder`)
+var UsageDet = fmt.Sprintf(`Config and log files are present in C:\ProgramData\Immuclient\config folder, the log file being rotated according to logfile-max-size and logfile-max-backups.
+The auditor state (root caches, queued notifications) is kept in C:\ProgramData\Immuclient\auditor.
+The service is restarted after a failure`)
 
 // UsageExamples examples
 var UsageExamples = `
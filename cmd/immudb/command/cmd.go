@@ -46,6 +46,8 @@ func newCommand(immudbServer server.ImmuServerIf) (*cobra.Command, error) {
 
 	cmd.AddCommand(man.Generate(cmd, "immudb", "./cmd/docs/man/immudb"))
 	cmd.AddCommand(version.VersionCmd())
+	cmd.AddCommand(rotateKeyCmd())
+	cmd.AddCommand(recallSegmentsCmd())
 
 	scl := service.NewCommandLine()
 	scl.Register(cmd)
@@ -19,6 +19,7 @@ package immudb
 import (
 	c "github.com/codenotary/immudb/cmd/helper"
 	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/store"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -47,6 +48,10 @@ func parseOptions() (options server.Options, err error) {
 	noHistograms := viper.GetBool("no-histograms")
 	detached := viper.GetBool("detached")
 	consistencyCheck := viper.GetBool("consistency-check")
+	tamperPolicy, err := server.ParseTamperPolicy(viper.GetString("tamper-policy"))
+	if err != nil {
+		return options, err
+	}
 	certificate, err := c.ResolvePath(viper.GetString("certificate"), true)
 	if err != nil {
 		return options, err
@@ -63,6 +68,52 @@ func parseOptions() (options server.Options, err error) {
 	adminPassword := viper.GetString("admin-password")
 	maintenance := viper.GetBool("maintenance")
 	signingKey := viper.GetString("signingKey")
+	strictAppendOnly := viper.GetBool("strict-append-only")
+	sequencer := viper.GetBool("sequencer")
+	checkpointInterval := viper.GetUint64("tree-checkpoint-interval")
+	syncWrites := viper.GetBool("sync-writes")
+	treeSync := viper.GetBool("tree-sync")
+	reconcileInterval := viper.GetDuration("reconcile-interval")
+	retention, err := server.ParseRetentionPolicies(viper.GetString("retention"))
+	if err != nil {
+		return options, err
+	}
+	retentionInterval := viper.GetDuration("retention-interval")
+	tieringDir := viper.GetString("tiering-dir")
+	tieringMinAge := viper.GetDuration("tiering-min-age")
+	tieringInterval := viper.GetDuration("tiering-interval")
+	backupDir := viper.GetString("backup-dir")
+	valueCompression, err := store.ParseValueCompression(viper.GetString("value-compression"))
+	if err != nil {
+		return options, err
+	}
+	encryptionKey := viper.GetString("encryption-key")
+	dataKeyRotation := viper.GetDuration("data-key-rotation")
+	faultInjection := server.FaultInjection{
+		MaxCommitDelay:      viper.GetDuration("fault-max-commit-delay"),
+		DropSyncs:           viper.GetBool("fault-drop-syncs"),
+		ProofCorruptionRate: viper.GetFloat64("fault-proof-corruption-rate"),
+		Seed:                viper.GetInt64("fault-seed"),
+	}
+	ntpServer := viper.GetString("ntp-server")
+	alertNewTokenIP := viper.GetBool("alert-new-token-ip")
+	usagePerUser := viper.GetBool("usage-per-user")
+	requireChecksums := viper.GetBool("require-checksums")
+	metricsCertificate, err := c.ResolvePath(viper.GetString("metrics-certificate"), true)
+	if err != nil {
+		return options, err
+	}
+	metricsPkey, err := c.ResolvePath(viper.GetString("metrics-pkey"), true)
+	if err != nil {
+		return options, err
+	}
+	metricsAuth := server.MetricsAuthOptions{
+		Certificate: metricsCertificate,
+		Pkey:        metricsPkey,
+		Username:    viper.GetString("metrics-username"),
+		Password:    viper.GetString("metrics-password"),
+		BearerToken: viper.GetString("metrics-bearer-token"),
+	}
 	options = server.
 		DefaultOptions().
 		WithDir(dir).
@@ -76,10 +127,32 @@ func parseOptions() (options server.Options, err error) {
 		WithNoHistograms(noHistograms).
 		WithDetached(detached).
 		WithCorruptionCheck(consistencyCheck).
+		WithTamperPolicy(tamperPolicy).
 		WithDevMode(devMode).
 		WithAdminPassword(adminPassword).
 		WithMaintenance(maintenance).
-		WithSigningKey(signingKey)
+		WithSigningKey(signingKey).
+		WithStrictAppendOnly(strictAppendOnly).
+		WithSequencer(sequencer).
+		WithCheckpointInterval(checkpointInterval).
+		WithSyncWrites(syncWrites).
+		WithTreeSync(treeSync).
+		WithReconcileInterval(reconcileInterval).
+		WithRetention(retention).
+		WithRetentionInterval(retentionInterval).
+		WithTieringDir(tieringDir).
+		WithTieringMinAge(tieringMinAge).
+		WithTieringInterval(tieringInterval).
+		WithBackupDir(backupDir).
+		WithValueCompression(valueCompression).
+		WithEncryptionKey(encryptionKey).
+		WithDataKeyRotation(dataKeyRotation).
+		WithFaultInjection(faultInjection).
+		WithNTPServer(ntpServer).
+		WithAlertNewTokenIP(alertNewTokenIP).
+		WithUsagePerUser(usagePerUser).
+		WithRequireChecksums(requireChecksums).
+		WithMetricsAuth(metricsAuth)
 	if mtls {
 		// todo https://golang.org/src/crypto/x509/root_linux.go
 		options.MTLsOptions = server.DefaultMTLsOptions().
@@ -102,6 +175,7 @@ func (cl *Commandline) setupFlags(cmd *cobra.Command, options server.Options, mt
 	cmd.Flags().Int("max-recv-msg-size", options.MaxRecvMsgSize, "max message size in bytes the server can receive")
 	cmd.Flags().Bool("no-histograms", options.MTLs, "disable collection of histogram metrics like query durations")
 	cmd.Flags().Bool("consistency-check", options.CorruptionCheck, "enable consistency check monitor routine. To disable: --consistency-check=false")
+	cmd.Flags().String("tamper-policy", string(options.TamperPolicy), "what the server does once the startup check or the consistency checker detect a possible tampering: refuse (all traffic), read-only (refuse writes) or alert (only log it and count it in the immudb_tamper_detections_total metric)")
 	cmd.Flags().BoolP(c.DetachedFlag, c.DetachedShortFlag, options.Detached, "run immudb in background")
 	cmd.Flags().String("certificate", mtlsOptions.Certificate, "server certificate file path")
 	cmd.Flags().String("pkey", mtlsOptions.Pkey, "server private key path")
@@ -109,6 +183,34 @@ func (cl *Commandline) setupFlags(cmd *cobra.Command, options server.Options, mt
 	cmd.Flags().Bool("devmode", options.DevMode, "enable dev mode: accept remote connections without auth")
 	cmd.Flags().String("admin-password", options.AdminPassword, "admin password (default is 'immudb') as plain-text or base64 encoded (must be prefixed with 'enc:' if it is encoded)")
 	cmd.Flags().Bool("maintenance", options.GetMaintenance(), "override the authentication flag")
+	cmd.Flags().Bool("strict-append-only", options.StrictAppendOnly, "reject any operation that could alter the appearance of history order (e.g. references to not yet committed indexes)")
+	cmd.Flags().Bool("sequencer", options.Sequencer, "assign a gap-free sequence number to every write, in commit order, so that writes can be read back by sequence number")
+	cmd.Flags().Uint64("tree-checkpoint-interval", options.CheckpointInterval, "number of entries after which the Merkle tree is checkpointed, bounding the entries replayed at startup after a crash (0 = default and maximum of 375000)")
+	cmd.Flags().Bool("sync-writes", options.SyncWrites, "sync the data to disk at every write, so that no acknowledged write is lost on a crash, at the cost of write throughput")
+	cmd.Flags().Bool("tree-sync", options.TreeSync, "sync the Merkle tree to disk at every checkpoint (see tree-checkpoint-interval). The tree is rebuilt from the data at startup, so an unsynced tree never loses data, it only makes the replay after a crash longer. Checkpoints are always synced if writes are")
+	cmd.Flags().Duration("reconcile-interval", options.ReconcileInterval, "period between checks that the number of stored entries matches the number of tree leaves, each of them reading every stored entry (0 to disable)")
+	cmd.Flags().String("retention", "", "retention policies of the databases, e.g. \"defaultdb:max-age=720h,max-revisions=10;*:max-revisions=100\" where * stands for the other databases. The values the policies don't retain are pruned, their entries and proofs are kept, and every enforcement is recorded, signed if a signing key is set, under the IMMUDB.METADATA.RETENTION. prefix")
+	cmd.Flags().Duration("retention-interval", options.RetentionInterval, "period between enforcements of the retention policies. To disable: --retention-interval=0")
+	cmd.Flags().String("tiering-dir", options.TieringDir, "cold storage directory the old value log segments of the databases are moved to, e.g. a cheaper disk or a mounted object store. The segments are replaced by links and read in place from there, without local caching, once the databases are opened again, see the recall-segments command to copy them back. The segments collected by the value log garbage collection are deleted from there")
+	cmd.Flags().Duration("tiering-min-age", options.TieringMinAge, "how long after their last write the value log segments are moved to cold storage")
+	cmd.Flags().Duration("tiering-interval", options.TieringInterval, "period between moves of the value log segments to cold storage")
+	cmd.Flags().String("backup-dir", options.BackupDir, "directory, outside of the data directory, the consistent snapshots of the databases taken without stopping writes (see the immuadmin hot-backup command) are written into. Empty disables them")
+	cmd.Flags().String("value-compression", options.ValueCompression.String(), "algorithm compressing the values before they are persisted: none, snappy or zstd (only if built with cgo). Only values large enough to be worth it are compressed, and values written with any compression stay readable")
+	cmd.Flags().String("encryption-key", options.EncryptionKey, "file of the AES-128, AES-192 or AES-256 key (raw or hex encoded) encrypting the data at rest. Databases written with a key can only be opened with it, see the rotate-key command")
+	cmd.Flags().Duration("data-key-rotation", options.DataKeyRotation, "how often the data keys encrypting the data at rest are rotated (0 for every 10 days)")
+	cmd.Flags().Duration("fault-max-commit-delay", options.FaultInjection.MaxCommitDelay, "(resilience testing, faultinjection builds only) delay every write by a random duration up to this one")
+	cmd.Flags().Bool("fault-drop-syncs", options.FaultInjection.DropSyncs, "(resilience testing, faultinjection builds only) skip every sync to disk while still acknowledging the writes")
+	cmd.Flags().Float64("fault-proof-corruption-rate", options.FaultInjection.ProofCorruptionRate, "(resilience testing, faultinjection builds only) probability, between 0 and 1, that the proof carried by a response is corrupted")
+	cmd.Flags().Int64("fault-seed", options.FaultInjection.Seed, "(resilience testing, faultinjection builds only) seed of the random generator of the injected faults, for reproducible runs (0 = current time)")
+	cmd.Flags().String("ntp-server", options.NTPServer, "NTP server (host:port) used to timestamp entries instead of the local clock. E.g. pool.ntp.org:123")
+	cmd.Flags().Bool("alert-new-token-ip", options.AlertNewTokenIP, "log a warning when a token is used from an address it was never used from, as it may have been leaked")
+	cmd.Flags().Bool("usage-per-user", options.UsagePerUser, "record the usage of the databases (operations and written bytes, exposed as metrics and by the GetUsage RPC) also per user, not only per database")
+	cmd.Flags().Bool("require-checksums", options.RequireChecksums, "refuse the requests without checksum, to detect the payloads corrupted in transit when TLS is terminated by a proxy in front of the server")
+	cmd.Flags().String("metrics-certificate", options.MetricsAuth.Certificate, "certificate file path of the metrics and diagnostics endpoints, served in HTTPS if set along with metrics-pkey")
+	cmd.Flags().String("metrics-pkey", options.MetricsAuth.Pkey, "private key file path of the metrics and diagnostics endpoints")
+	cmd.Flags().String("metrics-username", options.MetricsAuth.Username, "username required with HTTP basic auth to read the metrics and diagnostics endpoints")
+	cmd.Flags().String("metrics-password", options.MetricsAuth.Password, "password required with HTTP basic auth to read the metrics and diagnostics endpoints")
+	cmd.Flags().String("metrics-bearer-token", options.MetricsAuth.BearerToken, "bearer token required to read the metrics and diagnostics endpoints, accepted as an alternative to basic auth if both are set")
 	cmd.Flags().String("signingKey", options.SigningKey, "signature private key path. If a valid one is provided, it enables the cryptographic signature of the root. E.g. \"./../test/signer/ec3.key\"")
 }
 
@@ -123,6 +225,7 @@ func setupDefaults(options server.Options, mtlsOptions server.MTLsOptions) {
 	viper.SetDefault("max-recv-msg-size", options.MaxRecvMsgSize)
 	viper.SetDefault("no-histograms", options.NoHistograms)
 	viper.SetDefault("consistency-check", options.CorruptionCheck)
+	viper.SetDefault("tamper-policy", string(options.TamperPolicy))
 	viper.SetDefault("detached", options.Detached)
 	viper.SetDefault("certificate", mtlsOptions.Certificate)
 	viper.SetDefault("pkey", mtlsOptions.Pkey)
@@ -130,4 +233,32 @@ func setupDefaults(options server.Options, mtlsOptions server.MTLsOptions) {
 	viper.SetDefault("devmode", options.DevMode)
 	viper.SetDefault("admin-password", options.AdminPassword)
 	viper.SetDefault("maintenance", options.GetMaintenance())
+	viper.SetDefault("strict-append-only", options.StrictAppendOnly)
+	viper.SetDefault("sequencer", options.Sequencer)
+	viper.SetDefault("tree-checkpoint-interval", options.CheckpointInterval)
+	viper.SetDefault("sync-writes", options.SyncWrites)
+	viper.SetDefault("tree-sync", options.TreeSync)
+	viper.SetDefault("reconcile-interval", options.ReconcileInterval)
+	viper.SetDefault("retention", "")
+	viper.SetDefault("retention-interval", options.RetentionInterval)
+	viper.SetDefault("tiering-dir", options.TieringDir)
+	viper.SetDefault("tiering-min-age", options.TieringMinAge)
+	viper.SetDefault("tiering-interval", options.TieringInterval)
+	viper.SetDefault("backup-dir", options.BackupDir)
+	viper.SetDefault("value-compression", options.ValueCompression.String())
+	viper.SetDefault("encryption-key", options.EncryptionKey)
+	viper.SetDefault("data-key-rotation", options.DataKeyRotation)
+	viper.SetDefault("fault-max-commit-delay", options.FaultInjection.MaxCommitDelay)
+	viper.SetDefault("fault-drop-syncs", options.FaultInjection.DropSyncs)
+	viper.SetDefault("fault-proof-corruption-rate", options.FaultInjection.ProofCorruptionRate)
+	viper.SetDefault("fault-seed", options.FaultInjection.Seed)
+	viper.SetDefault("ntp-server", options.NTPServer)
+	viper.SetDefault("alert-new-token-ip", options.AlertNewTokenIP)
+	viper.SetDefault("usage-per-user", options.UsagePerUser)
+	viper.SetDefault("require-checksums", options.RequireChecksums)
+	viper.SetDefault("metrics-certificate", options.MetricsAuth.Certificate)
+	viper.SetDefault("metrics-pkey", options.MetricsAuth.Pkey)
+	viper.SetDefault("metrics-username", options.MetricsAuth.Username)
+	viper.SetDefault("metrics-password", options.MetricsAuth.Password)
+	viper.SetDefault("metrics-bearer-token", options.MetricsAuth.BearerToken)
 }
@@ -0,0 +1,57 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immudb
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// recallSegmentsCmd returns the command copying back to local disk the value log segments moved to cold storage,
+// while immudb is stopped
+func recallSegmentsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recall-segments [segment...]",
+		Short: "Copy back to local disk the value log segments of a database moved to cold storage",
+		Long: `Copy back to local disk the value log segments of a database moved to cold storage, all of them unless some
+are named, see the tiering-dir option. The copies in cold storage are kept: the segments are moved again, at no cost,
+once they have been local for tiering-min-age. immudb must be stopped, and started again to read the local segments.`,
+		Example: "immudb recall-segments --dir ./data --database defaultdb 000003.vlog",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("dir")
+			if err != nil {
+				return err
+			}
+			database, err := cmd.Flags().GetString("database")
+			if err != nil {
+				return err
+			}
+			recalled, err := store.RecallSegments(filepath.Join(dir, database), args...)
+			for _, s := range recalled {
+				fmt.Fprintf(cmd.OutOrStdout(), "segment %s recalled from %s\n", s.Name, s.Path)
+			}
+			return err
+		},
+	}
+	cmd.Flags().String("dir", server.DefaultOptions().Dir, "data directory of immudb")
+	cmd.Flags().String("database", server.DefaultdbName, "database whose segments are recalled")
+	return cmd
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immudb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecallSegments(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recallsegments")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dataDir := filepath.Join(dir, "data")
+	require.NoError(t, os.MkdirAll(dataDir, 0755))
+
+	opts, badgerOpts := store.DefaultOptions(filepath.Join(dataDir, "db1"), logger.NewSimpleLogger("immudb ", os.Stderr))
+	badgerOpts = badgerOpts.WithValueLogFileSize(1 << 20).WithValueThreshold(1024)
+	st, err := store.Open(opts, badgerOpts)
+	require.NoError(t, err)
+	for i := 0; i < 40; i++ {
+		_, err = st.Set(schema.KeyValue{Key: []byte{'k', byte(i)}, Value: bytes.Repeat([]byte{'v'}, 64*1024)})
+		require.NoError(t, err)
+	}
+	tiered, err := st.TierSegments(store.TieringOptions{ColdDir: filepath.Join(dir, "cold")})
+	require.NoError(t, err)
+	require.NotEmpty(t, tiered)
+	require.NoError(t, st.Close())
+
+	_, err = executeCommand(recallSegmentsCmd(), "--dir", dataDir, "--database", "db1", "999999.vlog")
+	assert.Error(t, err)
+
+	output, err := executeCommand(recallSegmentsCmd(), "--dir", dataDir, "--database", "db1", tiered[0].Name)
+	require.NoError(t, err)
+	assert.Equal(t, "segment "+tiered[0].Name+" recalled from "+tiered[0].Path+"\n", output)
+
+	segments, err := store.ReadTieredSegments(filepath.Join(dataDir, "db1"))
+	require.NoError(t, err)
+	assert.True(t, segments[tiered[0].Name].Local())
+}
@@ -44,13 +44,38 @@ Environment variables:
   IMMUDB_MAX_RECV_MSG_SIZE=4194304
   IMMUDB_DETACHED=false
   IMMUDB_CONSISTENCY_CHECK=true
+  IMMUDB_TAMPER_POLICY=refuse
   IMMUDB_PKEY=./tools/mtls/3_application/private/localhost.key.pem
   IMMUDB_CERTIFICATE=./tools/mtls/3_application/certs/localhost.cert.pem
   IMMUDB_CLIENTCAS=./tools/mtls/2_intermediate/certs/ca-chain.cert.pem
   IMMUDB_DEVMODE=true
   IMMUDB_MAINTENANCE=false
   IMMUDB_ADMIN_PASSWORD=immudb
-  IMMUDB_SIGNING_KEY=`,
+  IMMUDB_SIGNING_KEY=
+  IMMUDB_STRICT_APPEND_ONLY=false
+  IMMUDB_SEQUENCER=false
+  IMMUDB_TREE_CHECKPOINT_INTERVAL=0
+  IMMUDB_SYNC_WRITES=false
+  IMMUDB_TREE_SYNC=false
+  IMMUDB_RECONCILE_INTERVAL=0s
+  IMMUDB_RETENTION=
+  IMMUDB_RETENTION_INTERVAL=1h0m0s
+  IMMUDB_TIERING_DIR=
+  IMMUDB_TIERING_MIN_AGE=720h0m0s
+  IMMUDB_TIERING_INTERVAL=1h0m0s
+  IMMUDB_BACKUP_DIR=
+  IMMUDB_VALUE_COMPRESSION=none
+  IMMUDB_ENCRYPTION_KEY=
+  IMMUDB_DATA_KEY_ROTATION=0s
+  IMMUDB_NTP_SERVER=
+  IMMUDB_ALERT_NEW_TOKEN_IP=false
+  IMMUDB_USAGE_PER_USER=false
+  IMMUDB_REQUIRE_CHECKSUMS=false
+  IMMUDB_METRICS_CERTIFICATE=
+  IMMUDB_METRICS_PKEY=
+  IMMUDB_METRICS_USERNAME=
+  IMMUDB_METRICS_PASSWORD=
+  IMMUDB_METRICS_BEARER_TOKEN=`,
 		DisableAutoGenTag: true,
 		RunE:              cl.Immudb(immudbServer),
 		PersistentPreRunE: cl.ConfigChain(nil),
@@ -0,0 +1,111 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immudb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// rotateKeyCmd returns the command replacing the key encrypting the databases at rest, while immudb is stopped
+func rotateKeyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Replace the key encrypting the databases at rest",
+		Long: `Replace the key encrypting the databases at rest, see the encryption-key option.
+Only the data keys, which are encrypted with the key, are encrypted again: the data itself is left as it is.
+immudb must be stopped, and started again with the new key.`,
+		Example: "immudb rotate-key --dir ./data --old-key ./old.key --new-key ./new.key",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := cmd.Flags().GetString("dir")
+			if err != nil {
+				return err
+			}
+			oldKeyFile, err := cmd.Flags().GetString("old-key")
+			if err != nil {
+				return err
+			}
+			newKeyFile, err := cmd.Flags().GetString("new-key")
+			if err != nil {
+				return err
+			}
+			oldKey, err := store.ReadEncryptionKey(oldKeyFile)
+			if err != nil {
+				return err
+			}
+			newKey, err := store.ReadEncryptionKey(newKeyFile)
+			if err != nil {
+				return err
+			}
+			databases, err := rotateEncryptionKeys(dir, oldKey, newKey)
+			if err != nil {
+				return err
+			}
+			for _, db := range databases {
+				fmt.Fprintf(cmd.OutOrStdout(), "key rotated for database %s\n", db)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("dir", server.DefaultOptions().Dir, "data directory of immudb")
+	cmd.Flags().String("old-key", "", "file of the key the databases are currently encrypted with")
+	cmd.Flags().String("new-key", "", "file of the key to encrypt the databases with from now on")
+	cmd.MarkFlagRequired("old-key")
+	cmd.MarkFlagRequired("new-key")
+	return cmd
+}
+
+// rotateEncryptionKeys rotates the key of every encrypted database of dataDir, returning their names. Either all the
+// databases are rotated or none is: the databases already rotated are rotated back if one fails.
+func rotateEncryptionKeys(dataDir string, oldKey, newKey []byte) ([]string, error) {
+	entries, err := ioutil.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	var databases []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		m, err := store.ReadManifest(filepath.Join(dataDir, entry.Name()))
+		if err != nil || m.Options["encryption"] != "true" {
+			continue
+		}
+		databases = append(databases, entry.Name())
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("no encrypted database found in %s", dataDir)
+	}
+
+	for i, db := range databases {
+		if err = store.RotateEncryptionKey(filepath.Join(dataDir, db), oldKey, newKey); err != nil {
+			for _, rotated := range databases[:i] {
+				if rerr := store.RotateEncryptionKey(filepath.Join(dataDir, rotated), newKey, oldKey); rerr != nil {
+					return nil, fmt.Errorf("rotating database %s failed (%v) and rotating back database %s failed too: %v", db, err, rotated, rerr)
+				}
+			}
+			return nil, fmt.Errorf("rotating database %s failed, no database has been rotated: %v", db, err)
+		}
+	}
+	return databases, nil
+}
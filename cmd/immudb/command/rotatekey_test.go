@@ -0,0 +1,89 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immudb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotatekey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	oldKeyFile, newKeyFile := filepath.Join(dir, "old.key"), filepath.Join(dir, "new.key")
+	require.NoError(t, ioutil.WriteFile(oldKeyFile, oldKey, 0600))
+	require.NoError(t, ioutil.WriteFile(newKeyFile, newKey, 0600))
+	dataDir := filepath.Join(dir, "data")
+
+	log := logger.NewSimpleLogger("immudb ", os.Stderr)
+	for _, name := range []string{"defaultdb", "db1"} {
+		db, err := server.NewDb(server.DefaultOption().WithDbName(name).WithDbRootPath(dataDir).WithEncryptionKey(oldKey), log)
+		require.NoError(t, err)
+		require.NoError(t, db.Store.Close())
+	}
+
+	_, err = executeCommand(rotateKeyCmd(), "--dir", dataDir, "--old-key", newKeyFile, "--new-key", oldKeyFile)
+	assert.Error(t, err)
+	_, err = executeCommand(rotateKeyCmd(), "--dir", dir, "--old-key", oldKeyFile, "--new-key", newKeyFile)
+	assert.Error(t, err)
+
+	output, err := executeCommand(rotateKeyCmd(), "--dir", dataDir, "--old-key", oldKeyFile, "--new-key", newKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "key rotated for database db1\nkey rotated for database defaultdb\n", output)
+
+	for _, name := range []string{"defaultdb", "db1"} {
+		_, err = server.OpenDb(server.DefaultOption().WithDbName(name).WithDbRootPath(dataDir).WithEncryptionKey(oldKey), log)
+		assert.Error(t, err)
+		db, err := server.OpenDb(server.DefaultOption().WithDbName(name).WithDbRootPath(dataDir).WithEncryptionKey(newKey), log)
+		require.NoError(t, err)
+		require.NoError(t, db.Store.Close())
+	}
+}
+
+func TestRotateKeyRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotatekey")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	log := logger.NewSimpleLogger("immudb ", os.Stderr)
+	db1, err := server.NewDb(server.DefaultOption().WithDbName("db1").WithDbRootPath(dir).WithEncryptionKey(oldKey), log)
+	require.NoError(t, err)
+	require.NoError(t, db1.Store.Close())
+	// db2 is still open, so its key can't be rotated and the one of db1 is rotated back
+	db2, err := server.NewDb(server.DefaultOption().WithDbName("db2").WithDbRootPath(dir).WithEncryptionKey(oldKey), log)
+	require.NoError(t, err)
+	defer db2.Store.Close()
+
+	_, err = rotateEncryptionKeys(dir, oldKey, newKey)
+	require.Error(t, err)
+
+	db1, err = server.OpenDb(server.DefaultOption().WithDbName("db1").WithDbRootPath(dir).WithEncryptionKey(oldKey), log)
+	require.NoError(t, err)
+	require.NoError(t, db1.Store.Close())
+}
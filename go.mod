@@ -3,11 +3,13 @@ module github.com/codenotary/immudb
 go 1.13
 
 require (
+	github.com/DataDog/zstd v1.4.1
 	github.com/codenotary/merkletree v0.1.2-0.20200720105344-68d95395a656
 	github.com/dgraph-io/badger/v2 v2.0.0-20200408100755-2e708d968e94
 	github.com/fatih/color v1.9.0
 	github.com/gizak/termui/v3 v3.1.0
 	github.com/golang/protobuf v1.4.0
+	github.com/golang/snappy v0.0.1
 	github.com/grpc-ecosystem/go-grpc-middleware v1.0.0
 	github.com/grpc-ecosystem/go-grpc-prometheus v1.2.0
 	github.com/grpc-ecosystem/grpc-gateway v1.14.4
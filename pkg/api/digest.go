@@ -19,18 +19,73 @@ package api
 import (
 	"crypto/sha256"
 	"encoding/binary"
+	"hash"
+	"sync"
 
 	"github.com/codenotary/merkletree"
 )
 
+// hasher is a reusable sha256 state along with a scratch buffer for the fixed size headers of the digests,
+// pooled since a digest is computed for every entry written
+type hasher struct {
+	h   hash.Hash
+	hdr [1 + 8 + 8]byte
+	out [sha256.Size]byte
+}
+
+var hasherPool = sync.Pool{
+	New: func() interface{} {
+		return &hasher{h: sha256.New()}
+	},
+}
+
+func getHasher() *hasher {
+	h := hasherPool.Get().(*hasher)
+	h.h.Reset()
+	return h
+}
+
+// sum returns the digest of the data written so far, releasing the hasher to the pool
+func (h *hasher) sum() (d [sha256.Size]byte) {
+	h.h.Sum(h.out[:0])
+	d = h.out
+	hasherPool.Put(h)
+	return
+}
+
 // Digest returns the hash computed from the union of item's members.
 func Digest(index uint64, key, value []byte) [sha256.Size]byte {
-	kl, vl := len(key), len(value)
-	c := make([]byte, 1+8+8+kl+vl)
-	c[0] = merkletree.LeafPrefix
-	binary.BigEndian.PutUint64(c[1:1+8], index)
-	binary.BigEndian.PutUint64(c[1+8:1+8+8], uint64(kl))
-	copy(c[1+8+8:], key)
-	copy(c[1+8+8+kl:], value)
-	return sha256.Sum256(c)
+	h := getHasher()
+	h.hdr[0] = merkletree.LeafPrefix
+	binary.BigEndian.PutUint64(h.hdr[1:1+8], index)
+	binary.BigEndian.PutUint64(h.hdr[1+8:1+8+8], uint64(len(key)))
+	h.h.Write(h.hdr[:])
+	h.h.Write(key)
+	h.h.Write(value)
+	return h.sum()
+}
+
+// TombstonePrefix is the first byte hashed by TombstoneDigest, so that tombstones can't be mistaken for the
+// entries, whose digests start with the merkle tree leaf prefix
+const TombstonePrefix = byte(2)
+
+// TombstoneDigest returns the hash of the tombstone marking key as deleted at index.
+func TombstoneDigest(index uint64, key []byte) [sha256.Size]byte {
+	h := getHasher()
+	h.hdr[0] = TombstonePrefix
+	binary.BigEndian.PutUint64(h.hdr[1:1+8], index)
+	binary.BigEndian.PutUint64(h.hdr[1+8:1+8+8], uint64(len(key)))
+	h.h.Write(h.hdr[:])
+	h.h.Write(key)
+	return h.sum()
+}
+
+// TxDigest returns the hash of the leaves of the entries written by a transaction, in order.
+// It's the value of the entry committing the transaction.
+func TxDigest(leaves [][sha256.Size]byte) [sha256.Size]byte {
+	h := getHasher()
+	for i := range leaves {
+		h.h.Write(leaves[i][:])
+	}
+	return h.sum()
 }
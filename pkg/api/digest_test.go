@@ -17,6 +17,7 @@ limitations under the License.
 package api
 
 import (
+	"crypto/sha256"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +33,32 @@ func TestDigest(t *testing.T) {
 	assert.Equal(t, emptyLeaf, Digest(0, []byte{}, []byte{}))
 	assert.Equal(t, testLeaf, Digest(1, []byte(`key`), []byte(`value`)))
 }
+
+func TestTombstoneDigest(t *testing.T) {
+	assert.NotEqual(t, Digest(1, []byte(`key`), nil), TombstoneDigest(1, []byte(`key`)))
+	assert.NotEqual(t, TombstoneDigest(1, []byte(`key`)), TombstoneDigest(2, []byte(`key`)))
+	assert.Equal(t, TombstoneDigest(1, []byte(`key`)), TombstoneDigest(1, []byte(`key`)))
+}
+
+func TestTxDigest(t *testing.T) {
+	a, b := Digest(1, []byte(`a`), []byte(`1`)), Digest(2, []byte(`b`), []byte(`2`))
+	assert.Equal(t, TxDigest([][sha256.Size]byte{a, b}), TxDigest([][sha256.Size]byte{a, b}))
+	assert.NotEqual(t, TxDigest([][sha256.Size]byte{a, b}), TxDigest([][sha256.Size]byte{b, a}))
+	assert.NotEqual(t, TxDigest([][sha256.Size]byte{a, b}), TxDigest([][sha256.Size]byte{a}))
+}
+
+func BenchmarkDigest(b *testing.B) {
+	key, value := make([]byte, 32), make([]byte, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Digest(uint64(i), key, value)
+	}
+}
+
+func BenchmarkTxDigest(b *testing.B) {
+	leaves := make([][sha256.Size]byte, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		TxDigest(leaves)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+)
+
+// Verify checks offline that every entry contained in the _VerificationBundle_ is included into the history of
+// the tree and that the roots collected while building the bundle form a consistent chain, starting from _b.Base_
+// (if any) up to _b.Root_.
+// It's up to the caller to compare _b.Base_ with a locally trusted root.
+func (b *VerificationBundle) Verify() error {
+	if b == nil || b.Root == nil || len(b.Entries) == 0 {
+		return ErrEmptyVerificationBundle
+	}
+
+	prev := Root{}
+	if b.Base != nil {
+		prev = *b.Base
+	}
+	for _, e := range b.Entries {
+		if e.GetItem() == nil || e.GetProof() == nil || e.Proof.Index != e.Item.Index {
+			return ErrCorruptedVerificationBundle
+		}
+		h, err := e.Hash()
+		if err != nil {
+			return ErrCorruptedVerificationBundle
+		}
+		if !e.Proof.Verify(h, prev) {
+			return ErrCorruptedVerificationBundle
+		}
+		prev = *e.Proof.NewRoot()
+	}
+
+	if prev.GetIndex() != b.Root.GetIndex() || !bytes.Equal(prev.GetRoot(), b.Root.GetRoot()) {
+		return ErrCorruptedVerificationBundle
+	}
+	return nil
+}
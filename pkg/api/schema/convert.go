@@ -36,9 +36,10 @@ func (item *Item) ToSItem() (*StructuredItem, error) {
 	c := Content{}
 	err := proto.Unmarshal(item.Value, &c)
 	return &StructuredItem{
-		Index: item.Index,
-		Key:   item.Key,
-		Value: &c,
+		Index:   item.Index,
+		Key:     item.Key,
+		Value:   &c,
+		Deleted: item.Deleted,
 	}, err
 }
 
@@ -46,9 +47,10 @@ func (item *Item) ToSItem() (*StructuredItem, error) {
 func (item *StructuredItem) ToItem() (*Item, error) {
 	m, err := Merge(item.Value.Payload, item.Value.Timestamp)
 	return &Item{
-		Key:   item.Key,
-		Value: m,
-		Index: item.Index,
+		Key:     item.Key,
+		Value:   m,
+		Index:   item.Index,
+		Deleted: item.Deleted,
 	}, err
 }
 
@@ -118,7 +120,7 @@ func (skvl *SKVList) ToKVList() (*KVList, error) {
 
 // ToSPage converts a page of items to a page of structured items
 func (list *Page) ToSPage() (*SPage, error) {
-	slist := &SPage{}
+	slist := &SPage{More: list.More}
 	for _, item := range list.Items {
 		i, err := item.ToSItem()
 		if err != nil {
@@ -11,4 +11,10 @@ var (
 	ErrDuplicatedKeysNotSupported       = status.New(codes.InvalidArgument, "duplicated keys are not supported in single batch transaction").Err()
 	ErrDuplicatedZAddNotSupported       = status.New(codes.InvalidArgument, "duplicated index inside zAdd insertions are not supported in single batch transaction").Err()
 	ErrDuplicatedReferencesNotSupported = status.New(codes.InvalidArgument, "duplicated references insertions are not supported in single batch transaction").Err()
+	ErrEmptyKeyHistoryDump              = status.New(codes.InvalidArgument, "key history dump is empty").Err()
+	ErrCorruptedKeyHistoryDump          = status.New(codes.DataLoss, "key history dump does not verify against its root").Err()
+	ErrEmptyVerificationBundle          = status.New(codes.InvalidArgument, "verification bundle is empty").Err()
+	ErrCorruptedVerificationBundle      = status.New(codes.DataLoss, "verification bundle does not verify against its roots").Err()
+	ErrEmptyKeySample                   = status.New(codes.InvalidArgument, "key sample is empty").Err()
+	ErrCorruptedKeySample               = status.New(codes.DataLoss, "key sample does not verify against its seed and root").Err()
 )
@@ -23,11 +23,15 @@ import (
 	"github.com/codenotary/immudb/pkg/api"
 )
 
-// Hash returns the computed hash of _Item_.
+// Hash returns the computed hash of _Item_, the tombstone digest if the item marks its key as deleted.
 func (i *Item) Hash() []byte {
 	if i == nil {
 		return nil
 	}
+	if i.Deleted {
+		d := api.TombstoneDigest(i.Index, i.Key)
+		return d[:]
+	}
 	d := api.Digest(i.Index, i.Key, i.Value)
 	return d[:]
 }
@@ -41,8 +45,7 @@ func (si *StructuredItem) Hash() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	d := api.Digest(i.Index, i.Key, i.Value)
-	return d[:], nil
+	return i.Hash(), nil
 }
 
 // Hash computes and returns the hash of the safe item
@@ -50,8 +53,7 @@ func (s *SafeItem) Hash() ([]byte, error) {
 	if s == nil {
 		return nil, errors.New("Empty pointer receive")
 	}
-	d := api.Digest(s.Item.Index, s.Item.Key, s.Item.Value)
-	return d[:], nil
+	return s.Item.Hash(), nil
 }
 
 // MarshalJSON marshals the item to JSON
@@ -17,8 +17,10 @@ limitations under the License.
 package schema
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestItem_Hash(t *testing.T) {
@@ -40,6 +42,20 @@ func TestItem_Hash(t *testing.T) {
 
 }
 
+func TestItem_HashDeleted(t *testing.T) {
+	i := Item{Key: []byte(`key`), Index: 1, Deleted: true}
+	tombstone := api.TombstoneDigest(1, []byte(`key`))
+	assert.Equal(t, tombstone[:], i.Hash())
+	assert.NotEqual(t, (&Item{Key: []byte(`key`), Index: 1}).Hash(), i.Hash())
+
+	si, err := i.ToSItem()
+	assert.Nil(t, err)
+	assert.True(t, si.Deleted)
+	h, err := si.Hash()
+	assert.Nil(t, err)
+	assert.Equal(t, tombstone[:], h)
+}
+
 func TestStructuredItem_Hash(t *testing.T) {
 	i := StructuredItem{
 		Key: []byte(`key`),
@@ -0,0 +1,45 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+)
+
+// Verify checks offline that every revision contained in the _KeyHistoryDump_ belongs to the dumped key
+// and is included into the history of the covering _d.Root_.
+// The root signature, if any, is not checked: it's up to the caller to decide whether to trust the signer.
+func (d *KeyHistoryDump) Verify() error {
+	if d == nil || d.Root == nil || len(d.Entries) == 0 {
+		return ErrEmptyKeyHistoryDump
+	}
+	for _, e := range d.Entries {
+		if e.GetItem() == nil || e.GetProof() == nil {
+			return ErrCorruptedKeyHistoryDump
+		}
+		if !bytes.Equal(e.Item.Key, d.Key) {
+			return ErrCorruptedKeyHistoryDump
+		}
+		if e.Proof.At != d.Root.GetIndex() || !bytes.Equal(e.Proof.Root, d.Root.GetRoot()) {
+			return ErrCorruptedKeyHistoryDump
+		}
+		if !e.Proof.Verify(e.Item.Index, e.Item.Hash()) {
+			return ErrCorruptedKeyHistoryDump
+		}
+	}
+	return nil
+}
@@ -36,6 +36,8 @@ func (m *Ops) Validate() error {
 				return ErrDuplicatedReferencesNotSupported
 			}
 			mops[mk] = struct{}{}
+		case *Op_Delete, *Op_Unreference, *Op_Freeze:
+			return status.Newf(codes.InvalidArgument, "batch operation of type %T is not supported", x).Err()
 		case nil:
 			return status.New(codes.InvalidArgument, "operation is not set").Err()
 		default:
@@ -135,6 +135,17 @@ func TestOps_ValidateUnexpectedType(t *testing.T) {
 	err := aOps.Validate()
 	assert.Equal(t, status.Error(codes.InvalidArgument, "batch operation has unexpected type *schema.Op_Unexpected"), err)
 }
+
+func TestOps_ValidatePluginOnlyOperations(t *testing.T) {
+	for _, op := range []isOp_Operation{
+		&Op_Delete{Delete: &Key{Key: []byte(`key`)}},
+		&Op_Unreference{Unreference: &Key{Key: []byte(`ref`)}},
+		&Op_Freeze{Freeze: &KeyPrefix{Prefix: []byte(`key`)}},
+	} {
+		err := (&Ops{Operations: []*Op{{Operation: op}}}).Validate()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
 func TestExecAllOpsNilElementFound(t *testing.T) {
 	bOps := make([]*Op, 2)
 	op := &Op{
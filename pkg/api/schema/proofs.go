@@ -19,6 +19,8 @@ package schema
 import (
 	"bytes"
 	"crypto/sha256"
+	"math/bits"
+	"sort"
 
 	"github.com/codenotary/merkletree"
 )
@@ -93,6 +95,120 @@ func (p *Proof) Verify(leaf []byte, prevRoot Root) bool {
 	return path.VerifyConsistency(p.At, prevRoot.GetIndex(), secondRoot, firstRoot)
 }
 
+// Verify returns true iff the _HistoricalItem_ proves that _h.Item_ is included into the history of the historical
+// _h.Proof.Root_, and that the histories of the historical root and of the provided _prevRoot_ are consistent,
+// whichever of them comes first. Providing a zerovalue for _prevRoot_ signals that no previous root is available,
+// thus consistency proof will be skipped.
+func (h *HistoricalItem) Verify(prevRoot Root) bool {
+	if h == nil || h.Item == nil || !h.Proof.Verify(h.Item.Index, h.Item.Hash()) {
+		return false
+	}
+
+	// we cannot check consistency when the previous root is not provided
+	if prevRoot.GetIndex() == 0 && len(prevRoot.GetRoot()) == 0 {
+		return true
+	}
+
+	at := h.Proof.At
+	if prevRoot.GetIndex() == at {
+		return bytes.Equal(prevRoot.GetRoot(), h.Proof.Root)
+	}
+
+	c := h.ConsistencyProof
+	if c == nil {
+		return false
+	}
+	if prevRoot.GetIndex() < at {
+		return c.Second == at && bytes.Equal(c.SecondRoot, h.Proof.Root) && c.Verify(prevRoot)
+	}
+	if c.First != at || c.Second != prevRoot.GetIndex() || !bytes.Equal(c.SecondRoot, prevRoot.GetRoot()) {
+		return false
+	}
+	return c.Verify(Root{Payload: &RootIndex{Index: at, Root: h.Proof.Root}})
+}
+
+// Verify returns true iff the _MultiProof_ proves that each of _p.Leaves_ is included into _p.Root_'s history at the
+// position in _p.Indexes_ having the same offset, and that the provided _prevRoot_ is included into _p.Root_'s history.
+// Providing a zerovalue for _prevRoot_ signals that no previous root is available, thus consistency proof will be skipped.
+func (p *MultiProof) Verify(prevRoot Root) bool {
+	if p == nil || len(p.Indexes) == 0 || len(p.Indexes) != len(p.Leaves) {
+		return false
+	}
+	for i, index := range p.Indexes {
+		if index > p.At || (i > 0 && index <= p.Indexes[i-1]) || len(p.Leaves[i]) != sha256.Size {
+			return false
+		}
+	}
+
+	v := multiProofVerifier{p: p}
+	root, ok := v.subtree(0, p.At, 0, len(p.Indexes))
+	if !ok || v.next != len(p.Nodes) || !bytes.Equal(root[:], p.Root) {
+		return false
+	}
+
+	// we cannot check consistency when the previous root is not provided
+	if prevRoot.GetIndex() == 0 && len(prevRoot.GetRoot()) == 0 {
+		return true
+	}
+
+	var path merkletree.Path
+	path.FromSlice(p.ConsistencyPath)
+
+	var firstRoot, secondRoot [sha256.Size]byte
+	copy(firstRoot[:], prevRoot.GetRoot())
+	copy(secondRoot[:], p.Root)
+	return path.VerifyConsistency(p.At, prevRoot.GetIndex(), secondRoot, firstRoot)
+}
+
+// Includes returns true iff _leaf_ is one of the leaves proved by _p_, at the given _index_.
+// It does not verify the proof itself, see Verify.
+func (p *MultiProof) Includes(index uint64, leaf []byte) bool {
+	if p == nil {
+		return false
+	}
+	i := sort.Search(len(p.Indexes), func(i int) bool { return p.Indexes[i] >= index })
+	return i < len(p.Indexes) && i < len(p.Leaves) && p.Indexes[i] == index && bytes.Equal(p.Leaves[i], leaf)
+}
+
+// multiProofVerifier rebuilds the root of a _MultiProof_, consuming its nodes in left to right order
+type multiProofVerifier struct {
+	p    *MultiProof
+	next int
+}
+
+// subtree returns the root of the subtree made of the leaves from _l_ to _r_, holding the proved leaves from _first_
+// (included) to _last_ (excluded). Subtrees are split as by the Merkle Tree Hash of RFC 6962.
+func (v *multiProofVerifier) subtree(l, r uint64, first, last int) (h [sha256.Size]byte, ok bool) {
+	if first == last {
+		if v.next == len(v.p.Nodes) || len(v.p.Nodes[v.next]) != sha256.Size {
+			return h, false
+		}
+		copy(h[:], v.p.Nodes[v.next])
+		v.next++
+		return h, true
+	}
+	if l == r {
+		copy(h[:], v.p.Leaves[first])
+		return h, true
+	}
+
+	k := uint64(1) << (bits.Len64(r-l) - 1)
+	split := first + sort.Search(last-first, func(i int) bool { return v.p.Indexes[first+i] >= l+k })
+	left, ok := v.subtree(l, l+k-1, first, split)
+	if !ok {
+		return h, false
+	}
+	right, ok := v.subtree(l+k, r, split, last)
+	if !ok {
+		return h, false
+	}
+
+	c := [sha256.Size*2 + 1]byte{merkletree.NodePrefix}
+	copy(c[1:sha256.Size+1], left[:])
+	copy(c[sha256.Size+1:], right[:])
+	return sha256.Sum256(c[:]), true
+}
+
 // NewRoot returns a new _Root_ object which holds values referenced by the proof _p_.
 func (p *Proof) NewRoot() *Root {
 	if p != nil {
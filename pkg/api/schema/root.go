@@ -41,6 +41,24 @@ func (r *Root) CheckSignature() (ok bool, err error) {
 	return signer.Verify(m, r.Signature.Signature, r.Signature.PublicKey)
 }
 
+// CheckSignatureWith verifies the signature of the root against the trusted public keys,
+// ignoring the public key embedded in the root. It succeeds if any of the keys verifies the signature.
+func (r *Root) CheckSignatureWith(publicKeys ...[]byte) (ok bool, err error) {
+	if r.GetSignature().GetSignature() == nil {
+		return false, errors.New("no signature found")
+	}
+	var m []byte
+	if m, err = proto.Marshal(r.Payload); err != nil {
+		return ok, err
+	}
+	for _, publicKey := range publicKeys {
+		if ok, err = signer.Verify(m, r.Signature.Signature, publicKey); ok {
+			return ok, nil
+		}
+	}
+	return false, err
+}
+
 func (r *Root) GetIndex() uint64 {
 	return r.GetPayload().GetIndex()
 }
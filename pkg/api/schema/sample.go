@@ -0,0 +1,68 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// SampleRank returns the rank of key in a sample drawn with the given seed: a sample of size n is made of the n keys
+// having the lowest ranks. Ranks only depend on the seed and on the key, so that anyone can reproduce the
+// selection and check that a known key with a lower rank than a sampled one has not been left out.
+func SampleRank(seed int64, key []byte) []byte {
+	h := sha256.New()
+	var s [8]byte
+	binary.BigEndian.PutUint64(s[:], uint64(seed))
+	h.Write(s[:])
+	h.Write(key)
+	return h.Sum(nil)
+}
+
+// Verify checks offline that the _KeySample_ is a selection drawn with its seed: every key matches the prefix,
+// entries are sorted by increasing rank and every entry is included into the history of the covering _s.Root_.
+// The root signature, if any, is not checked: it's up to the caller to decide whether to trust the signer.
+func (s *KeySample) Verify() error {
+	if s == nil || s.Root == nil || len(s.Entries) == 0 {
+		return ErrEmptyKeySample
+	}
+	if uint64(len(s.Entries)) > s.Population {
+		return ErrCorruptedKeySample
+	}
+	var prevRank []byte
+	for _, e := range s.Entries {
+		if e.GetItem() == nil || e.GetProof() == nil {
+			return ErrCorruptedKeySample
+		}
+		if !bytes.HasPrefix(e.Item.Key, s.Prefix) {
+			return ErrCorruptedKeySample
+		}
+		rank := SampleRank(s.Seed, e.Item.Key)
+		if prevRank != nil && bytes.Compare(prevRank, rank) >= 0 {
+			return ErrCorruptedKeySample
+		}
+		prevRank = rank
+		if e.Proof.At != s.Root.GetIndex() || !bytes.Equal(e.Proof.Root, s.Root.GetRoot()) {
+			return ErrCorruptedKeySample
+		}
+		if !e.Proof.Verify(e.Item.Index, e.Item.Hash()) {
+			return ErrCorruptedKeySample
+		}
+	}
+	return nil
+}
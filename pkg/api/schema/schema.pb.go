@@ -249,6 +249,390 @@ func (m *UserList) GetUsers() []*User {
 	return nil
 }
 
+// Session reports the usage of an authentication token, identified by a digest of the token itself
+type Session struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	FirstSeen            int64    `protobuf:"varint,3,opt,name=firstSeen,proto3" json:"firstSeen,omitempty"`
+	LastSeen             int64    `protobuf:"varint,4,opt,name=lastSeen,proto3" json:"lastSeen,omitempty"`
+	LastAddress          string   `protobuf:"bytes,5,opt,name=lastAddress,proto3" json:"lastAddress,omitempty"`
+	LastMethod           string   `protobuf:"bytes,6,opt,name=lastMethod,proto3" json:"lastMethod,omitempty"`
+	Calls                uint64   `protobuf:"varint,7,opt,name=calls,proto3" json:"calls,omitempty"`
+	Addresses            []string `protobuf:"bytes,8,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Expiration           int64    `protobuf:"varint,9,opt,name=expiration,proto3" json:"expiration,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{4}
+}
+
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (m *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(m, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Session) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Session) GetFirstSeen() int64 {
+	if m != nil {
+		return m.FirstSeen
+	}
+	return 0
+}
+
+func (m *Session) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+func (m *Session) GetLastAddress() string {
+	if m != nil {
+		return m.LastAddress
+	}
+	return ""
+}
+
+func (m *Session) GetLastMethod() string {
+	if m != nil {
+		return m.LastMethod
+	}
+	return ""
+}
+
+func (m *Session) GetCalls() uint64 {
+	if m != nil {
+		return m.Calls
+	}
+	return 0
+}
+
+func (m *Session) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *Session) GetExpiration() int64 {
+	if m != nil {
+		return m.Expiration
+	}
+	return 0
+}
+
+type SessionList struct {
+	Sessions             []*Session `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *SessionList) Reset()         { *m = SessionList{} }
+func (m *SessionList) String() string { return proto.CompactTextString(m) }
+func (*SessionList) ProtoMessage()    {}
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{5}
+}
+
+func (m *SessionList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionList.Unmarshal(m, b)
+}
+func (m *SessionList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionList.Marshal(b, m, deterministic)
+}
+func (m *SessionList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionList.Merge(m, src)
+}
+func (m *SessionList) XXX_Size() int {
+	return xxx_messageInfo_SessionList.Size(m)
+}
+func (m *SessionList) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionList proto.InternalMessageInfo
+
+func (m *SessionList) GetSessions() []*Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+// UsageRequest selects the usage to report: a database (all of them if empty) and a time window, in unix seconds
+type UsageRequest struct {
+	Database             string   `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	Since                int64    `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,3,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageRequest) Reset()         { *m = UsageRequest{} }
+func (m *UsageRequest) String() string { return proto.CompactTextString(m) }
+func (*UsageRequest) ProtoMessage()    {}
+func (*UsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{6}
+}
+
+func (m *UsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageRequest.Unmarshal(m, b)
+}
+func (m *UsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageRequest.Marshal(b, m, deterministic)
+}
+func (m *UsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageRequest.Merge(m, src)
+}
+func (m *UsageRequest) XXX_Size() int {
+	return xxx_messageInfo_UsageRequest.Size(m)
+}
+func (m *UsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageRequest proto.InternalMessageInfo
+
+func (m *UsageRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *UsageRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *UsageRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+// Usage counts the operations run against a database, by a user if per-user usage is enabled.
+// Write operations also count the bytes of the written requests
+type Usage struct {
+	Database             string   `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Reads                uint64   `protobuf:"varint,3,opt,name=reads,proto3" json:"reads,omitempty"`
+	Writes               uint64   `protobuf:"varint,4,opt,name=writes,proto3" json:"writes,omitempty"`
+	Admin                uint64   `protobuf:"varint,5,opt,name=admin,proto3" json:"admin,omitempty"`
+	WrittenBytes         uint64   `protobuf:"varint,6,opt,name=writtenBytes,proto3" json:"writtenBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Usage) Reset()         { *m = Usage{} }
+func (m *Usage) String() string { return proto.CompactTextString(m) }
+func (*Usage) ProtoMessage()    {}
+func (*Usage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{7}
+}
+
+func (m *Usage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Usage.Unmarshal(m, b)
+}
+func (m *Usage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Usage.Marshal(b, m, deterministic)
+}
+func (m *Usage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Usage.Merge(m, src)
+}
+func (m *Usage) XXX_Size() int {
+	return xxx_messageInfo_Usage.Size(m)
+}
+func (m *Usage) XXX_DiscardUnknown() {
+	xxx_messageInfo_Usage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Usage proto.InternalMessageInfo
+
+func (m *Usage) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *Usage) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Usage) GetReads() uint64 {
+	if m != nil {
+		return m.Reads
+	}
+	return 0
+}
+
+func (m *Usage) GetWrites() uint64 {
+	if m != nil {
+		return m.Writes
+	}
+	return 0
+}
+
+func (m *Usage) GetAdmin() uint64 {
+	if m != nil {
+		return m.Admin
+	}
+	return 0
+}
+
+func (m *Usage) GetWrittenBytes() uint64 {
+	if m != nil {
+		return m.WrittenBytes
+	}
+	return 0
+}
+
+// UsageReport is the usage recorded in a time window, whose bounds are rounded to the recording granularity
+type UsageReport struct {
+	Since                int64    `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,2,opt,name=until,proto3" json:"until,omitempty"`
+	Usage                []*Usage `protobuf:"bytes,3,rep,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UsageReport) Reset()         { *m = UsageReport{} }
+func (m *UsageReport) String() string { return proto.CompactTextString(m) }
+func (*UsageReport) ProtoMessage()    {}
+func (*UsageReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{8}
+}
+
+func (m *UsageReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UsageReport.Unmarshal(m, b)
+}
+func (m *UsageReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UsageReport.Marshal(b, m, deterministic)
+}
+func (m *UsageReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UsageReport.Merge(m, src)
+}
+func (m *UsageReport) XXX_Size() int {
+	return xxx_messageInfo_UsageReport.Size(m)
+}
+func (m *UsageReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_UsageReport.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UsageReport proto.InternalMessageInfo
+
+func (m *UsageReport) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *UsageReport) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *UsageReport) GetUsage() []*Usage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+// AccessControlState summarizes the users and their permissions, so that auditors can detect changes to them
+type AccessControlState struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Users                uint32   `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccessControlState) Reset()         { *m = AccessControlState{} }
+func (m *AccessControlState) String() string { return proto.CompactTextString(m) }
+func (*AccessControlState) ProtoMessage()    {}
+func (*AccessControlState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{9}
+}
+
+func (m *AccessControlState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccessControlState.Unmarshal(m, b)
+}
+func (m *AccessControlState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccessControlState.Marshal(b, m, deterministic)
+}
+func (m *AccessControlState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccessControlState.Merge(m, src)
+}
+func (m *AccessControlState) XXX_Size() int {
+	return xxx_messageInfo_AccessControlState.Size(m)
+}
+func (m *AccessControlState) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccessControlState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccessControlState proto.InternalMessageInfo
+
+func (m *AccessControlState) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *AccessControlState) GetUsers() uint32 {
+	if m != nil {
+		return m.Users
+	}
+	return 0
+}
+
 type CreateUserRequest struct {
 	User                 []byte   `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	Password             []byte   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
@@ -263,7 +647,7 @@ func (m *CreateUserRequest) Reset()         { *m = CreateUserRequest{} }
 func (m *CreateUserRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateUserRequest) ProtoMessage()    {}
 func (*CreateUserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{4}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{10}
 }
 
 func (m *CreateUserRequest) XXX_Unmarshal(b []byte) error {
@@ -323,7 +707,7 @@ func (m *UserRequest) Reset()         { *m = UserRequest{} }
 func (m *UserRequest) String() string { return proto.CompactTextString(m) }
 func (*UserRequest) ProtoMessage()    {}
 func (*UserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{5}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{11}
 }
 
 func (m *UserRequest) XXX_Unmarshal(b []byte) error {
@@ -364,7 +748,7 @@ func (m *ChangePasswordRequest) Reset()         { *m = ChangePasswordRequest{} }
 func (m *ChangePasswordRequest) String() string { return proto.CompactTextString(m) }
 func (*ChangePasswordRequest) ProtoMessage()    {}
 func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{6}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{12}
 }
 
 func (m *ChangePasswordRequest) XXX_Unmarshal(b []byte) error {
@@ -418,7 +802,7 @@ func (m *LoginRequest) Reset()         { *m = LoginRequest{} }
 func (m *LoginRequest) String() string { return proto.CompactTextString(m) }
 func (*LoginRequest) ProtoMessage()    {}
 func (*LoginRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{7}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{13}
 }
 
 func (m *LoginRequest) XXX_Unmarshal(b []byte) error {
@@ -465,7 +849,7 @@ func (m *LoginResponse) Reset()         { *m = LoginResponse{} }
 func (m *LoginResponse) String() string { return proto.CompactTextString(m) }
 func (*LoginResponse) ProtoMessage()    {}
 func (*LoginResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{8}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{14}
 }
 
 func (m *LoginResponse) XXX_Unmarshal(b []byte) error {
@@ -511,7 +895,7 @@ func (m *AuthConfig) Reset()         { *m = AuthConfig{} }
 func (m *AuthConfig) String() string { return proto.CompactTextString(m) }
 func (*AuthConfig) ProtoMessage()    {}
 func (*AuthConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{9}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{15}
 }
 
 func (m *AuthConfig) XXX_Unmarshal(b []byte) error {
@@ -550,7 +934,7 @@ func (m *MTLSConfig) Reset()         { *m = MTLSConfig{} }
 func (m *MTLSConfig) String() string { return proto.CompactTextString(m) }
 func (*MTLSConfig) ProtoMessage()    {}
 func (*MTLSConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{10}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{16}
 }
 
 func (m *MTLSConfig) XXX_Unmarshal(b []byte) error {
@@ -594,7 +978,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{11}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{17}
 }
 
 func (m *Node) XXX_Unmarshal(b []byte) error {
@@ -668,7 +1052,7 @@ func (m *Layer) Reset()         { *m = Layer{} }
 func (m *Layer) String() string { return proto.CompactTextString(m) }
 func (*Layer) ProtoMessage()    {}
 func (*Layer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{12}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{18}
 }
 
 func (m *Layer) XXX_Unmarshal(b []byte) error {
@@ -707,7 +1091,7 @@ func (m *Tree) Reset()         { *m = Tree{} }
 func (m *Tree) String() string { return proto.CompactTextString(m) }
 func (*Tree) ProtoMessage()    {}
 func (*Tree) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{13}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{19}
 }
 
 func (m *Tree) XXX_Unmarshal(b []byte) error {
@@ -747,7 +1131,7 @@ func (m *KeyValue) Reset()         { *m = KeyValue{} }
 func (m *KeyValue) String() string { return proto.CompactTextString(m) }
 func (*KeyValue) ProtoMessage()    {}
 func (*KeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{14}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{20}
 }
 
 func (m *KeyValue) XXX_Unmarshal(b []byte) error {
@@ -787,6 +1171,9 @@ type Op struct {
 	//	*Op_KVs
 	//	*Op_ZOpts
 	//	*Op_ROpts
+	//	*Op_Delete
+	//	*Op_Unreference
+	//	*Op_Freeze
 	Operation            isOp_Operation `protobuf_oneof:"operation"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
@@ -797,7 +1184,7 @@ func (m *Op) Reset()         { *m = Op{} }
 func (m *Op) String() string { return proto.CompactTextString(m) }
 func (*Op) ProtoMessage()    {}
 func (*Op) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{15}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{21}
 }
 
 func (m *Op) XXX_Unmarshal(b []byte) error {
@@ -834,12 +1221,30 @@ type Op_ROpts struct {
 	ROpts *ReferenceOptions `protobuf:"bytes,3,opt,name=ROpts,proto3,oneof"`
 }
 
+type Op_Delete struct {
+	Delete *Key `protobuf:"bytes,4,opt,name=Delete,proto3,oneof"`
+}
+
+type Op_Unreference struct {
+	Unreference *Key `protobuf:"bytes,5,opt,name=Unreference,proto3,oneof"`
+}
+
+type Op_Freeze struct {
+	Freeze *KeyPrefix `protobuf:"bytes,6,opt,name=Freeze,proto3,oneof"`
+}
+
 func (*Op_KVs) isOp_Operation() {}
 
 func (*Op_ZOpts) isOp_Operation() {}
 
 func (*Op_ROpts) isOp_Operation() {}
 
+func (*Op_Delete) isOp_Operation() {}
+
+func (*Op_Unreference) isOp_Operation() {}
+
+func (*Op_Freeze) isOp_Operation() {}
+
 func (m *Op) GetOperation() isOp_Operation {
 	if m != nil {
 		return m.Operation
@@ -868,12 +1273,36 @@ func (m *Op) GetROpts() *ReferenceOptions {
 	return nil
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*Op) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*Op_KVs)(nil),
-		(*Op_ZOpts)(nil),
-		(*Op_ROpts)(nil),
+func (m *Op) GetDelete() *Key {
+	if x, ok := m.GetOperation().(*Op_Delete); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+func (m *Op) GetUnreference() *Key {
+	if x, ok := m.GetOperation().(*Op_Unreference); ok {
+		return x.Unreference
+	}
+	return nil
+}
+
+func (m *Op) GetFreeze() *KeyPrefix {
+	if x, ok := m.GetOperation().(*Op_Freeze); ok {
+		return x.Freeze
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Op) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Op_KVs)(nil),
+		(*Op_ZOpts)(nil),
+		(*Op_ROpts)(nil),
+		(*Op_Delete)(nil),
+		(*Op_Unreference)(nil),
+		(*Op_Freeze)(nil),
 	}
 }
 
@@ -888,7 +1317,7 @@ func (m *Ops) Reset()         { *m = Ops{} }
 func (m *Ops) String() string { return proto.CompactTextString(m) }
 func (*Ops) ProtoMessage()    {}
 func (*Ops) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{16}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{22}
 }
 
 func (m *Ops) XXX_Unmarshal(b []byte) error {
@@ -928,7 +1357,7 @@ func (m *StructuredKeyValue) Reset()         { *m = StructuredKeyValue{} }
 func (m *StructuredKeyValue) String() string { return proto.CompactTextString(m) }
 func (*StructuredKeyValue) ProtoMessage()    {}
 func (*StructuredKeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{17}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{23}
 }
 
 func (m *StructuredKeyValue) XXX_Unmarshal(b []byte) error {
@@ -975,7 +1404,7 @@ func (m *Content) Reset()         { *m = Content{} }
 func (m *Content) String() string { return proto.CompactTextString(m) }
 func (*Content) ProtoMessage()    {}
 func (*Content) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{18}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{24}
 }
 
 func (m *Content) XXX_Unmarshal(b []byte) error {
@@ -1011,7 +1440,9 @@ func (m *Content) GetPayload() []byte {
 }
 
 type Index struct {
-	Index                uint64   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Index uint64 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// sequence is the gap-free number assigned to the write by databases running in sequencer mode
+	Sequence             uint64   `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1021,7 +1452,7 @@ func (m *Index) Reset()         { *m = Index{} }
 func (m *Index) String() string { return proto.CompactTextString(m) }
 func (*Index) ProtoMessage()    {}
 func (*Index) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{19}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{25}
 }
 
 func (m *Index) XXX_Unmarshal(b []byte) error {
@@ -1049,10 +1480,121 @@ func (m *Index) GetIndex() uint64 {
 	return 0
 }
 
+func (m *Index) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+type Sequence struct {
+	Sequence             uint64   `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Sequence) Reset()         { *m = Sequence{} }
+func (m *Sequence) String() string { return proto.CompactTextString(m) }
+func (*Sequence) ProtoMessage()    {}
+func (*Sequence) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{26}
+}
+
+func (m *Sequence) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Sequence.Unmarshal(m, b)
+}
+func (m *Sequence) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Sequence.Marshal(b, m, deterministic)
+}
+func (m *Sequence) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Sequence.Merge(m, src)
+}
+func (m *Sequence) XXX_Size() int {
+	return xxx_messageInfo_Sequence.Size(m)
+}
+func (m *Sequence) XXX_DiscardUnknown() {
+	xxx_messageInfo_Sequence.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Sequence proto.InternalMessageInfo
+
+func (m *Sequence) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// SequencedWrite holds the entries written by the write having the given sequence number
+type SequencedWrite struct {
+	Sequence             uint64   `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	FirstIndex           uint64   `protobuf:"varint,2,opt,name=firstIndex,proto3" json:"firstIndex,omitempty"`
+	LastIndex            uint64   `protobuf:"varint,3,opt,name=lastIndex,proto3" json:"lastIndex,omitempty"`
+	Items                []*Item  `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SequencedWrite) Reset()         { *m = SequencedWrite{} }
+func (m *SequencedWrite) String() string { return proto.CompactTextString(m) }
+func (*SequencedWrite) ProtoMessage()    {}
+func (*SequencedWrite) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{27}
+}
+
+func (m *SequencedWrite) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SequencedWrite.Unmarshal(m, b)
+}
+func (m *SequencedWrite) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SequencedWrite.Marshal(b, m, deterministic)
+}
+func (m *SequencedWrite) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SequencedWrite.Merge(m, src)
+}
+func (m *SequencedWrite) XXX_Size() int {
+	return xxx_messageInfo_SequencedWrite.Size(m)
+}
+func (m *SequencedWrite) XXX_DiscardUnknown() {
+	xxx_messageInfo_SequencedWrite.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SequencedWrite proto.InternalMessageInfo
+
+func (m *SequencedWrite) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *SequencedWrite) GetFirstIndex() uint64 {
+	if m != nil {
+		return m.FirstIndex
+	}
+	return 0
+}
+
+func (m *SequencedWrite) GetLastIndex() uint64 {
+	if m != nil {
+		return m.LastIndex
+	}
+	return 0
+}
+
+func (m *SequencedWrite) GetItems() []*Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
 type Item struct {
 	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Index                uint64   `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Deleted              bool     `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1062,7 +1604,7 @@ func (m *Item) Reset()         { *m = Item{} }
 func (m *Item) String() string { return proto.CompactTextString(m) }
 func (*Item) ProtoMessage()    {}
 func (*Item) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{20}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{28}
 }
 
 func (m *Item) XXX_Unmarshal(b []byte) error {
@@ -1104,10 +1646,18 @@ func (m *Item) GetIndex() uint64 {
 	return 0
 }
 
+func (m *Item) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
 type StructuredItem struct {
 	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value                *Content `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Index                uint64   `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Deleted              bool     `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1117,7 +1667,7 @@ func (m *StructuredItem) Reset()         { *m = StructuredItem{} }
 func (m *StructuredItem) String() string { return proto.CompactTextString(m) }
 func (*StructuredItem) ProtoMessage()    {}
 func (*StructuredItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{21}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{29}
 }
 
 func (m *StructuredItem) XXX_Unmarshal(b []byte) error {
@@ -1159,6 +1709,13 @@ func (m *StructuredItem) GetIndex() uint64 {
 	return 0
 }
 
+func (m *StructuredItem) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
 type KVList struct {
 	KVs                  []*KeyValue `protobuf:"bytes,1,rep,name=KVs,proto3" json:"KVs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
@@ -1170,7 +1727,7 @@ func (m *KVList) Reset()         { *m = KVList{} }
 func (m *KVList) String() string { return proto.CompactTextString(m) }
 func (*KVList) ProtoMessage()    {}
 func (*KVList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{22}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{30}
 }
 
 func (m *KVList) XXX_Unmarshal(b []byte) error {
@@ -1209,7 +1766,7 @@ func (m *SKVList) Reset()         { *m = SKVList{} }
 func (m *SKVList) String() string { return proto.CompactTextString(m) }
 func (*SKVList) ProtoMessage()    {}
 func (*SKVList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{23}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{31}
 }
 
 func (m *SKVList) XXX_Unmarshal(b []byte) error {
@@ -1248,7 +1805,7 @@ func (m *KeyList) Reset()         { *m = KeyList{} }
 func (m *KeyList) String() string { return proto.CompactTextString(m) }
 func (*KeyList) ProtoMessage()    {}
 func (*KeyList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{24}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{32}
 }
 
 func (m *KeyList) XXX_Unmarshal(b []byte) error {
@@ -1277,7 +1834,9 @@ func (m *KeyList) GetKeys() []*Key {
 }
 
 type ItemList struct {
-	Items                []*Item  `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Items []*Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// cursor, set by Scan only if more items follow, resumes the scan after the last item
+	Cursor               []byte   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1287,7 +1846,7 @@ func (m *ItemList) Reset()         { *m = ItemList{} }
 func (m *ItemList) String() string { return proto.CompactTextString(m) }
 func (*ItemList) ProtoMessage()    {}
 func (*ItemList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{25}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{33}
 }
 
 func (m *ItemList) XXX_Unmarshal(b []byte) error {
@@ -1315,6 +1874,13 @@ func (m *ItemList) GetItems() []*Item {
 	return nil
 }
 
+func (m *ItemList) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
 type ZItem struct {
 	Item                 *Item    `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
 	Score                float64  `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
@@ -1329,7 +1895,7 @@ func (m *ZItem) Reset()         { *m = ZItem{} }
 func (m *ZItem) String() string { return proto.CompactTextString(m) }
 func (*ZItem) ProtoMessage()    {}
 func (*ZItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{26}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{34}
 }
 
 func (m *ZItem) XXX_Unmarshal(b []byte) error {
@@ -1389,7 +1955,7 @@ func (m *ZItemList) Reset()         { *m = ZItemList{} }
 func (m *ZItemList) String() string { return proto.CompactTextString(m) }
 func (*ZItemList) ProtoMessage()    {}
 func (*ZItemList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{27}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{35}
 }
 
 func (m *ZItemList) XXX_Unmarshal(b []byte) error {
@@ -1428,7 +1994,7 @@ func (m *StructuredItemList) Reset()         { *m = StructuredItemList{} }
 func (m *StructuredItemList) String() string { return proto.CompactTextString(m) }
 func (*StructuredItemList) ProtoMessage()    {}
 func (*StructuredItemList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{28}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{36}
 }
 
 func (m *StructuredItemList) XXX_Unmarshal(b []byte) error {
@@ -1467,7 +2033,7 @@ func (m *ZStructuredItemList) Reset()         { *m = ZStructuredItemList{} }
 func (m *ZStructuredItemList) String() string { return proto.CompactTextString(m) }
 func (*ZStructuredItemList) ProtoMessage()    {}
 func (*ZStructuredItemList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{29}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{37}
 }
 
 func (m *ZStructuredItemList) XXX_Unmarshal(b []byte) error {
@@ -1509,7 +2075,7 @@ func (m *ZStructuredItem) Reset()         { *m = ZStructuredItem{} }
 func (m *ZStructuredItem) String() string { return proto.CompactTextString(m) }
 func (*ZStructuredItem) ProtoMessage()    {}
 func (*ZStructuredItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{30}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{38}
 }
 
 func (m *ZStructuredItem) XXX_Unmarshal(b []byte) error {
@@ -1570,7 +2136,7 @@ func (m *Root) Reset()         { *m = Root{} }
 func (m *Root) String() string { return proto.CompactTextString(m) }
 func (*Root) ProtoMessage()    {}
 func (*Root) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{31}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{39}
 }
 
 func (m *Root) XXX_Unmarshal(b []byte) error {
@@ -1617,7 +2183,7 @@ func (m *RootIndex) Reset()         { *m = RootIndex{} }
 func (m *RootIndex) String() string { return proto.CompactTextString(m) }
 func (*RootIndex) ProtoMessage()    {}
 func (*RootIndex) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{32}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{40}
 }
 
 func (m *RootIndex) XXX_Unmarshal(b []byte) error {
@@ -1664,7 +2230,7 @@ func (m *Signature) Reset()         { *m = Signature{} }
 func (m *Signature) String() string { return proto.CompactTextString(m) }
 func (*Signature) ProtoMessage()    {}
 func (*Signature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{33}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{41}
 }
 
 func (m *Signature) XXX_Unmarshal(b []byte) error {
@@ -1700,11 +2266,13 @@ func (m *Signature) GetPublicKey() []byte {
 }
 
 type ScanOptions struct {
-	Prefix               []byte   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
-	Offset               []byte   `protobuf:"bytes,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit                uint64   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Reverse              bool     `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
-	Deep                 bool     `protobuf:"varint,5,opt,name=deep,proto3" json:"deep,omitempty"`
+	Prefix  []byte `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Offset  []byte `protobuf:"bytes,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit   uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse bool   `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	Deep    bool   `protobuf:"varint,5,opt,name=deep,proto3" json:"deep,omitempty"`
+	// cursor returned by the previous page of the scan, which is resumed after its last item. The offset is ignored
+	Cursor               []byte   `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1714,7 +2282,7 @@ func (m *ScanOptions) Reset()         { *m = ScanOptions{} }
 func (m *ScanOptions) String() string { return proto.CompactTextString(m) }
 func (*ScanOptions) ProtoMessage()    {}
 func (*ScanOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{34}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{42}
 }
 
 func (m *ScanOptions) XXX_Unmarshal(b []byte) error {
@@ -1770,6 +2338,13 @@ func (m *ScanOptions) GetDeep() bool {
 	return false
 }
 
+func (m *ScanOptions) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
 type KeyPrefix struct {
 	Prefix               []byte   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1781,7 +2356,7 @@ func (m *KeyPrefix) Reset()         { *m = KeyPrefix{} }
 func (m *KeyPrefix) String() string { return proto.CompactTextString(m) }
 func (*KeyPrefix) ProtoMessage()    {}
 func (*KeyPrefix) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{35}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{43}
 }
 
 func (m *KeyPrefix) XXX_Unmarshal(b []byte) error {
@@ -1820,7 +2395,7 @@ func (m *ItemsCount) Reset()         { *m = ItemsCount{} }
 func (m *ItemsCount) String() string { return proto.CompactTextString(m) }
 func (*ItemsCount) ProtoMessage()    {}
 func (*ItemsCount) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{36}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{44}
 }
 
 func (m *ItemsCount) XXX_Unmarshal(b []byte) error {
@@ -1863,7 +2438,7 @@ func (m *InclusionProof) Reset()         { *m = InclusionProof{} }
 func (m *InclusionProof) String() string { return proto.CompactTextString(m) }
 func (*InclusionProof) ProtoMessage()    {}
 func (*InclusionProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{37}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{45}
 }
 
 func (m *InclusionProof) XXX_Unmarshal(b []byte) error {
@@ -1934,7 +2509,7 @@ func (m *ConsistencyProof) Reset()         { *m = ConsistencyProof{} }
 func (m *ConsistencyProof) String() string { return proto.CompactTextString(m) }
 func (*ConsistencyProof) ProtoMessage()    {}
 func (*ConsistencyProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{38}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{46}
 }
 
 func (m *ConsistencyProof) XXX_Unmarshal(b []byte) error {
@@ -1991,12 +2566,14 @@ func (m *ConsistencyProof) GetPath() [][]byte {
 }
 
 type Proof struct {
-	Leaf                 []byte   `protobuf:"bytes,1,opt,name=leaf,proto3" json:"leaf,omitempty"`
-	Index                uint64   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
-	Root                 []byte   `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
-	At                   uint64   `protobuf:"varint,4,opt,name=at,proto3" json:"at,omitempty"`
-	InclusionPath        [][]byte `protobuf:"bytes,5,rep,name=inclusionPath,proto3" json:"inclusionPath,omitempty"`
-	ConsistencyPath      [][]byte `protobuf:"bytes,6,rep,name=consistencyPath,proto3" json:"consistencyPath,omitempty"`
+	Leaf            []byte   `protobuf:"bytes,1,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	Index           uint64   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Root            []byte   `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	At              uint64   `protobuf:"varint,4,opt,name=at,proto3" json:"at,omitempty"`
+	InclusionPath   [][]byte `protobuf:"bytes,5,rep,name=inclusionPath,proto3" json:"inclusionPath,omitempty"`
+	ConsistencyPath [][]byte `protobuf:"bytes,6,rep,name=consistencyPath,proto3" json:"consistencyPath,omitempty"`
+	// sequence is the gap-free number assigned to the write by databases running in sequencer mode
+	Sequence             uint64   `protobuf:"varint,7,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2006,7 +2583,7 @@ func (m *Proof) Reset()         { *m = Proof{} }
 func (m *Proof) String() string { return proto.CompactTextString(m) }
 func (*Proof) ProtoMessage()    {}
 func (*Proof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{39}
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{47}
 }
 
 func (m *Proof) XXX_Unmarshal(b []byte) error {
@@ -2069,957 +2646,1772 @@ func (m *Proof) GetConsistencyPath() [][]byte {
 	return nil
 }
 
-type SafeItem struct {
-	Item                 *Item    `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
-	Proof                *Proof   `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Proof) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
 }
 
-func (m *SafeItem) Reset()         { *m = SafeItem{} }
-func (m *SafeItem) String() string { return proto.CompactTextString(m) }
-func (*SafeItem) ProtoMessage()    {}
-func (*SafeItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{40}
+type KeyHistoryEntry struct {
+	Item                 *Item           `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Proof                *InclusionProof `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *SafeItem) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeItem.Unmarshal(m, b)
+func (m *KeyHistoryEntry) Reset()         { *m = KeyHistoryEntry{} }
+func (m *KeyHistoryEntry) String() string { return proto.CompactTextString(m) }
+func (*KeyHistoryEntry) ProtoMessage()    {}
+func (*KeyHistoryEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{48}
 }
-func (m *SafeItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeItem.Marshal(b, m, deterministic)
+
+func (m *KeyHistoryEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyHistoryEntry.Unmarshal(m, b)
 }
-func (m *SafeItem) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeItem.Merge(m, src)
+func (m *KeyHistoryEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyHistoryEntry.Marshal(b, m, deterministic)
 }
-func (m *SafeItem) XXX_Size() int {
-	return xxx_messageInfo_SafeItem.Size(m)
+func (m *KeyHistoryEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyHistoryEntry.Merge(m, src)
 }
-func (m *SafeItem) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeItem.DiscardUnknown(m)
+func (m *KeyHistoryEntry) XXX_Size() int {
+	return xxx_messageInfo_KeyHistoryEntry.Size(m)
+}
+func (m *KeyHistoryEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyHistoryEntry.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeItem proto.InternalMessageInfo
+var xxx_messageInfo_KeyHistoryEntry proto.InternalMessageInfo
 
-func (m *SafeItem) GetItem() *Item {
+func (m *KeyHistoryEntry) GetItem() *Item {
 	if m != nil {
 		return m.Item
 	}
 	return nil
 }
 
-func (m *SafeItem) GetProof() *Proof {
+func (m *KeyHistoryEntry) GetProof() *InclusionProof {
 	if m != nil {
 		return m.Proof
 	}
 	return nil
 }
 
-type SafeStructuredItem struct {
-	Item                 *StructuredItem `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
-	Proof                *Proof          `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+type KeyHistoryDump struct {
+	Key                  []byte             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Entries              []*KeyHistoryEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Root                 *Root              `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-func (m *SafeStructuredItem) Reset()         { *m = SafeStructuredItem{} }
-func (m *SafeStructuredItem) String() string { return proto.CompactTextString(m) }
-func (*SafeStructuredItem) ProtoMessage()    {}
-func (*SafeStructuredItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{41}
+func (m *KeyHistoryDump) Reset()         { *m = KeyHistoryDump{} }
+func (m *KeyHistoryDump) String() string { return proto.CompactTextString(m) }
+func (*KeyHistoryDump) ProtoMessage()    {}
+func (*KeyHistoryDump) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{49}
 }
 
-func (m *SafeStructuredItem) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeStructuredItem.Unmarshal(m, b)
+func (m *KeyHistoryDump) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyHistoryDump.Unmarshal(m, b)
 }
-func (m *SafeStructuredItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeStructuredItem.Marshal(b, m, deterministic)
+func (m *KeyHistoryDump) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyHistoryDump.Marshal(b, m, deterministic)
 }
-func (m *SafeStructuredItem) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeStructuredItem.Merge(m, src)
+func (m *KeyHistoryDump) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyHistoryDump.Merge(m, src)
 }
-func (m *SafeStructuredItem) XXX_Size() int {
-	return xxx_messageInfo_SafeStructuredItem.Size(m)
+func (m *KeyHistoryDump) XXX_Size() int {
+	return xxx_messageInfo_KeyHistoryDump.Size(m)
 }
-func (m *SafeStructuredItem) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeStructuredItem.DiscardUnknown(m)
+func (m *KeyHistoryDump) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyHistoryDump.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeStructuredItem proto.InternalMessageInfo
+var xxx_messageInfo_KeyHistoryDump proto.InternalMessageInfo
 
-func (m *SafeStructuredItem) GetItem() *StructuredItem {
+func (m *KeyHistoryDump) GetKey() []byte {
 	if m != nil {
-		return m.Item
+		return m.Key
 	}
 	return nil
 }
 
-func (m *SafeStructuredItem) GetProof() *Proof {
+func (m *KeyHistoryDump) GetEntries() []*KeyHistoryEntry {
 	if m != nil {
-		return m.Proof
+		return m.Entries
 	}
 	return nil
 }
 
-type SafeSetOptions struct {
-	Kv                   *KeyValue `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
-	RootIndex            *Index    `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+func (m *KeyHistoryDump) GetRoot() *Root {
+	if m != nil {
+		return m.Root
+	}
+	return nil
 }
 
-func (m *SafeSetOptions) Reset()         { *m = SafeSetOptions{} }
-func (m *SafeSetOptions) String() string { return proto.CompactTextString(m) }
-func (*SafeSetOptions) ProtoMessage()    {}
-func (*SafeSetOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{42}
+type SampleOptions struct {
+	Size                 uint64   `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Prefix               []byte   `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Seed                 int64    `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SafeSetOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeSetOptions.Unmarshal(m, b)
+func (m *SampleOptions) Reset()         { *m = SampleOptions{} }
+func (m *SampleOptions) String() string { return proto.CompactTextString(m) }
+func (*SampleOptions) ProtoMessage()    {}
+func (*SampleOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{50}
 }
-func (m *SafeSetOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeSetOptions.Marshal(b, m, deterministic)
+
+func (m *SampleOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SampleOptions.Unmarshal(m, b)
 }
-func (m *SafeSetOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeSetOptions.Merge(m, src)
+func (m *SampleOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SampleOptions.Marshal(b, m, deterministic)
 }
-func (m *SafeSetOptions) XXX_Size() int {
-	return xxx_messageInfo_SafeSetOptions.Size(m)
+func (m *SampleOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SampleOptions.Merge(m, src)
 }
-func (m *SafeSetOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeSetOptions.DiscardUnknown(m)
+func (m *SampleOptions) XXX_Size() int {
+	return xxx_messageInfo_SampleOptions.Size(m)
+}
+func (m *SampleOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SampleOptions.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeSetOptions proto.InternalMessageInfo
+var xxx_messageInfo_SampleOptions proto.InternalMessageInfo
 
-func (m *SafeSetOptions) GetKv() *KeyValue {
+func (m *SampleOptions) GetSize() uint64 {
 	if m != nil {
-		return m.Kv
+		return m.Size
 	}
-	return nil
+	return 0
 }
 
-func (m *SafeSetOptions) GetRootIndex() *Index {
+func (m *SampleOptions) GetPrefix() []byte {
 	if m != nil {
-		return m.RootIndex
+		return m.Prefix
 	}
 	return nil
 }
 
-type SafeSetSVOptions struct {
-	Skv                  *StructuredKeyValue `protobuf:"bytes,1,opt,name=skv,proto3" json:"skv,omitempty"`
-	RootIndex            *Index              `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+func (m *SampleOptions) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
 }
 
-func (m *SafeSetSVOptions) Reset()         { *m = SafeSetSVOptions{} }
-func (m *SafeSetSVOptions) String() string { return proto.CompactTextString(m) }
-func (*SafeSetSVOptions) ProtoMessage()    {}
-func (*SafeSetSVOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{43}
+type KeySample struct {
+	Seed                 int64              `protobuf:"varint,1,opt,name=seed,proto3" json:"seed,omitempty"`
+	Prefix               []byte             `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Population           uint64             `protobuf:"varint,3,opt,name=population,proto3" json:"population,omitempty"`
+	Entries              []*KeyHistoryEntry `protobuf:"bytes,4,rep,name=entries,proto3" json:"entries,omitempty"`
+	Root                 *Root              `protobuf:"bytes,5,opt,name=root,proto3" json:"root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
-func (m *SafeSetSVOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeSetSVOptions.Unmarshal(m, b)
+func (m *KeySample) Reset()         { *m = KeySample{} }
+func (m *KeySample) String() string { return proto.CompactTextString(m) }
+func (*KeySample) ProtoMessage()    {}
+func (*KeySample) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{51}
 }
-func (m *SafeSetSVOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeSetSVOptions.Marshal(b, m, deterministic)
+
+func (m *KeySample) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeySample.Unmarshal(m, b)
 }
-func (m *SafeSetSVOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeSetSVOptions.Merge(m, src)
+func (m *KeySample) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeySample.Marshal(b, m, deterministic)
 }
-func (m *SafeSetSVOptions) XXX_Size() int {
-	return xxx_messageInfo_SafeSetSVOptions.Size(m)
+func (m *KeySample) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySample.Merge(m, src)
 }
-func (m *SafeSetSVOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeSetSVOptions.DiscardUnknown(m)
+func (m *KeySample) XXX_Size() int {
+	return xxx_messageInfo_KeySample.Size(m)
+}
+func (m *KeySample) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySample.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeSetSVOptions proto.InternalMessageInfo
+var xxx_messageInfo_KeySample proto.InternalMessageInfo
 
-func (m *SafeSetSVOptions) GetSkv() *StructuredKeyValue {
+func (m *KeySample) GetSeed() int64 {
 	if m != nil {
-		return m.Skv
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *KeySample) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
 	}
 	return nil
 }
 
-func (m *SafeSetSVOptions) GetRootIndex() *Index {
+func (m *KeySample) GetPopulation() uint64 {
 	if m != nil {
-		return m.RootIndex
+		return m.Population
+	}
+	return 0
+}
+
+func (m *KeySample) GetEntries() []*KeyHistoryEntry {
+	if m != nil {
+		return m.Entries
 	}
 	return nil
 }
 
-type SafeGetOptions struct {
-	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	RootIndex            *Index   `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+func (m *KeySample) GetRoot() *Root {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+// StorageLevel reports the tables of a level of the LSM tree of a database store
+type StorageLevel struct {
+	Level                uint32   `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Tables               uint32   `protobuf:"varint,2,opt,name=tables,proto3" json:"tables,omitempty"`
+	Keys                 uint64   `protobuf:"varint,3,opt,name=keys,proto3" json:"keys,omitempty"`
+	Size                 uint64   `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SafeGetOptions) Reset()         { *m = SafeGetOptions{} }
-func (m *SafeGetOptions) String() string { return proto.CompactTextString(m) }
-func (*SafeGetOptions) ProtoMessage()    {}
-func (*SafeGetOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{44}
+func (m *StorageLevel) Reset()         { *m = StorageLevel{} }
+func (m *StorageLevel) String() string { return proto.CompactTextString(m) }
+func (*StorageLevel) ProtoMessage()    {}
+func (*StorageLevel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{52}
 }
 
-func (m *SafeGetOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeGetOptions.Unmarshal(m, b)
+func (m *StorageLevel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageLevel.Unmarshal(m, b)
 }
-func (m *SafeGetOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeGetOptions.Marshal(b, m, deterministic)
+func (m *StorageLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageLevel.Marshal(b, m, deterministic)
 }
-func (m *SafeGetOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeGetOptions.Merge(m, src)
+func (m *StorageLevel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageLevel.Merge(m, src)
 }
-func (m *SafeGetOptions) XXX_Size() int {
-	return xxx_messageInfo_SafeGetOptions.Size(m)
+func (m *StorageLevel) XXX_Size() int {
+	return xxx_messageInfo_StorageLevel.Size(m)
 }
-func (m *SafeGetOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeGetOptions.DiscardUnknown(m)
+func (m *StorageLevel) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageLevel.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeGetOptions proto.InternalMessageInfo
+var xxx_messageInfo_StorageLevel proto.InternalMessageInfo
 
-func (m *SafeGetOptions) GetKey() []byte {
+func (m *StorageLevel) GetLevel() uint32 {
 	if m != nil {
-		return m.Key
+		return m.Level
 	}
-	return nil
+	return 0
 }
 
-func (m *SafeGetOptions) GetRootIndex() *Index {
+func (m *StorageLevel) GetTables() uint32 {
 	if m != nil {
-		return m.RootIndex
+		return m.Tables
 	}
-	return nil
+	return 0
 }
 
-type SafeReferenceOptions struct {
-	Ro                   *ReferenceOptions `protobuf:"bytes,1,opt,name=ro,proto3" json:"ro,omitempty"`
-	RootIndex            *Index            `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+func (m *StorageLevel) GetKeys() uint64 {
+	if m != nil {
+		return m.Keys
+	}
+	return 0
 }
 
-func (m *SafeReferenceOptions) Reset()         { *m = SafeReferenceOptions{} }
-func (m *SafeReferenceOptions) String() string { return proto.CompactTextString(m) }
-func (*SafeReferenceOptions) ProtoMessage()    {}
-func (*SafeReferenceOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{45}
+func (m *StorageLevel) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
 }
 
-func (m *SafeReferenceOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeReferenceOptions.Unmarshal(m, b)
+// StorageReport analyzes how efficiently the data entries and the Merkle tree of a database are stored
+type StorageReport struct {
+	Database             string          `protobuf:"bytes,1,opt,name=database,proto3" json:"database,omitempty"`
+	LsmSize              int64           `protobuf:"varint,2,opt,name=lsmSize,proto3" json:"lsmSize,omitempty"`
+	VlogSize             int64           `protobuf:"varint,3,opt,name=vlogSize,proto3" json:"vlogSize,omitempty"`
+	Levels               []*StorageLevel `protobuf:"bytes,4,rep,name=levels,proto3" json:"levels,omitempty"`
+	Entries              uint64          `protobuf:"varint,5,opt,name=entries,proto3" json:"entries,omitempty"`
+	EntriesSize          uint64          `protobuf:"varint,6,opt,name=entriesSize,proto3" json:"entriesSize,omitempty"`
+	TreeNodes            uint64          `protobuf:"varint,7,opt,name=treeNodes,proto3" json:"treeNodes,omitempty"`
+	TreeLeaves           uint64          `protobuf:"varint,8,opt,name=treeLeaves,proto3" json:"treeLeaves,omitempty"`
+	TreeSize             uint64          `protobuf:"varint,9,opt,name=treeSize,proto3" json:"treeSize,omitempty"`
+	VlogLiveSize         uint64          `protobuf:"varint,10,opt,name=vlogLiveSize,proto3" json:"vlogLiveSize,omitempty"`
+	VlogUtilization      float64         `protobuf:"fixed64,11,opt,name=vlogUtilization,proto3" json:"vlogUtilization,omitempty"`
+	SpaceAmplification   float64         `protobuf:"fixed64,12,opt,name=spaceAmplification,proto3" json:"spaceAmplification,omitempty"`
+	Recommendations      []string        `protobuf:"bytes,13,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
-func (m *SafeReferenceOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeReferenceOptions.Marshal(b, m, deterministic)
+
+func (m *StorageReport) Reset()         { *m = StorageReport{} }
+func (m *StorageReport) String() string { return proto.CompactTextString(m) }
+func (*StorageReport) ProtoMessage()    {}
+func (*StorageReport) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{53}
 }
-func (m *SafeReferenceOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeReferenceOptions.Merge(m, src)
+
+func (m *StorageReport) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageReport.Unmarshal(m, b)
 }
-func (m *SafeReferenceOptions) XXX_Size() int {
-	return xxx_messageInfo_SafeReferenceOptions.Size(m)
+func (m *StorageReport) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageReport.Marshal(b, m, deterministic)
 }
-func (m *SafeReferenceOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeReferenceOptions.DiscardUnknown(m)
+func (m *StorageReport) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageReport.Merge(m, src)
+}
+func (m *StorageReport) XXX_Size() int {
+	return xxx_messageInfo_StorageReport.Size(m)
+}
+func (m *StorageReport) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageReport.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeReferenceOptions proto.InternalMessageInfo
+var xxx_messageInfo_StorageReport proto.InternalMessageInfo
 
-func (m *SafeReferenceOptions) GetRo() *ReferenceOptions {
+func (m *StorageReport) GetDatabase() string {
 	if m != nil {
-		return m.Ro
+		return m.Database
 	}
-	return nil
+	return ""
 }
 
-func (m *SafeReferenceOptions) GetRootIndex() *Index {
+func (m *StorageReport) GetLsmSize() int64 {
 	if m != nil {
-		return m.RootIndex
+		return m.LsmSize
 	}
-	return nil
+	return 0
 }
 
-type HealthResponse struct {
-	Status               bool     `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
-	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *StorageReport) GetVlogSize() int64 {
+	if m != nil {
+		return m.VlogSize
+	}
+	return 0
 }
 
-func (m *HealthResponse) Reset()         { *m = HealthResponse{} }
-func (m *HealthResponse) String() string { return proto.CompactTextString(m) }
-func (*HealthResponse) ProtoMessage()    {}
-func (*HealthResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{46}
+func (m *StorageReport) GetLevels() []*StorageLevel {
+	if m != nil {
+		return m.Levels
+	}
+	return nil
 }
 
-func (m *HealthResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_HealthResponse.Unmarshal(m, b)
+func (m *StorageReport) GetEntries() uint64 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
 }
-func (m *HealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_HealthResponse.Marshal(b, m, deterministic)
+
+func (m *StorageReport) GetEntriesSize() uint64 {
+	if m != nil {
+		return m.EntriesSize
+	}
+	return 0
 }
-func (m *HealthResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_HealthResponse.Merge(m, src)
+
+func (m *StorageReport) GetTreeNodes() uint64 {
+	if m != nil {
+		return m.TreeNodes
+	}
+	return 0
 }
-func (m *HealthResponse) XXX_Size() int {
-	return xxx_messageInfo_HealthResponse.Size(m)
+
+func (m *StorageReport) GetTreeLeaves() uint64 {
+	if m != nil {
+		return m.TreeLeaves
+	}
+	return 0
 }
-func (m *HealthResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_HealthResponse.DiscardUnknown(m)
+
+func (m *StorageReport) GetTreeSize() uint64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
 }
 
-var xxx_messageInfo_HealthResponse proto.InternalMessageInfo
+func (m *StorageReport) GetVlogLiveSize() uint64 {
+	if m != nil {
+		return m.VlogLiveSize
+	}
+	return 0
+}
 
-func (m *HealthResponse) GetStatus() bool {
+func (m *StorageReport) GetVlogUtilization() float64 {
 	if m != nil {
-		return m.Status
+		return m.VlogUtilization
 	}
-	return false
+	return 0
 }
 
-func (m *HealthResponse) GetVersion() string {
+func (m *StorageReport) GetSpaceAmplification() float64 {
 	if m != nil {
-		return m.Version
+		return m.SpaceAmplification
 	}
-	return ""
+	return 0
 }
 
-type ReferenceOptions struct {
-	Reference            []byte   `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
-	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Index                *Index   `protobuf:"bytes,3,opt,name=index,proto3" json:"index,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *StorageReport) GetRecommendations() []string {
+	if m != nil {
+		return m.Recommendations
+	}
+	return nil
 }
 
-func (m *ReferenceOptions) Reset()         { *m = ReferenceOptions{} }
-func (m *ReferenceOptions) String() string { return proto.CompactTextString(m) }
-func (*ReferenceOptions) ProtoMessage()    {}
-func (*ReferenceOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{47}
+type VerificationBundle struct {
+	Base                 *Root       `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Entries              []*SafeItem `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Root                 *Root       `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
-func (m *ReferenceOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ReferenceOptions.Unmarshal(m, b)
+func (m *VerificationBundle) Reset()         { *m = VerificationBundle{} }
+func (m *VerificationBundle) String() string { return proto.CompactTextString(m) }
+func (*VerificationBundle) ProtoMessage()    {}
+func (*VerificationBundle) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{54}
 }
-func (m *ReferenceOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ReferenceOptions.Marshal(b, m, deterministic)
+
+func (m *VerificationBundle) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerificationBundle.Unmarshal(m, b)
 }
-func (m *ReferenceOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ReferenceOptions.Merge(m, src)
+func (m *VerificationBundle) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerificationBundle.Marshal(b, m, deterministic)
 }
-func (m *ReferenceOptions) XXX_Size() int {
-	return xxx_messageInfo_ReferenceOptions.Size(m)
+func (m *VerificationBundle) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerificationBundle.Merge(m, src)
 }
-func (m *ReferenceOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_ReferenceOptions.DiscardUnknown(m)
+func (m *VerificationBundle) XXX_Size() int {
+	return xxx_messageInfo_VerificationBundle.Size(m)
+}
+func (m *VerificationBundle) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerificationBundle.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ReferenceOptions proto.InternalMessageInfo
+var xxx_messageInfo_VerificationBundle proto.InternalMessageInfo
 
-func (m *ReferenceOptions) GetReference() []byte {
+func (m *VerificationBundle) GetBase() *Root {
 	if m != nil {
-		return m.Reference
+		return m.Base
 	}
 	return nil
 }
 
-func (m *ReferenceOptions) GetKey() []byte {
+func (m *VerificationBundle) GetEntries() []*SafeItem {
 	if m != nil {
-		return m.Key
+		return m.Entries
 	}
 	return nil
 }
 
-func (m *ReferenceOptions) GetIndex() *Index {
+func (m *VerificationBundle) GetRoot() *Root {
 	if m != nil {
-		return m.Index
+		return m.Root
 	}
 	return nil
 }
 
-//
-//Why use double as score type?
-//Because it is not purely about the storage size, but also use cases.
-//64-bit floating point double gives a lot of flexibility and dynamic range, at the expense of having only 53-bits of integer.
-type ZAddOptions struct {
-	Set                  []byte   `protobuf:"bytes,1,opt,name=set,proto3" json:"set,omitempty"`
-	Score                *Score   `protobuf:"bytes,2,opt,name=score,proto3" json:"score,omitempty"`
-	Key                  []byte   `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
-	Index                *Index   `protobuf:"bytes,4,opt,name=index,proto3" json:"index,omitempty"`
+type LogRequest struct {
+	Level                string   `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Components           []string `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
+	Follow               bool     `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ZAddOptions) Reset()         { *m = ZAddOptions{} }
-func (m *ZAddOptions) String() string { return proto.CompactTextString(m) }
-func (*ZAddOptions) ProtoMessage()    {}
-func (*ZAddOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{48}
+func (m *LogRequest) Reset()         { *m = LogRequest{} }
+func (m *LogRequest) String() string { return proto.CompactTextString(m) }
+func (*LogRequest) ProtoMessage()    {}
+func (*LogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{55}
 }
 
-func (m *ZAddOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ZAddOptions.Unmarshal(m, b)
+func (m *LogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRequest.Unmarshal(m, b)
 }
-func (m *ZAddOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ZAddOptions.Marshal(b, m, deterministic)
+func (m *LogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRequest.Marshal(b, m, deterministic)
 }
-func (m *ZAddOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ZAddOptions.Merge(m, src)
+func (m *LogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRequest.Merge(m, src)
 }
-func (m *ZAddOptions) XXX_Size() int {
-	return xxx_messageInfo_ZAddOptions.Size(m)
+func (m *LogRequest) XXX_Size() int {
+	return xxx_messageInfo_LogRequest.Size(m)
 }
-func (m *ZAddOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_ZAddOptions.DiscardUnknown(m)
+func (m *LogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ZAddOptions proto.InternalMessageInfo
+var xxx_messageInfo_LogRequest proto.InternalMessageInfo
 
-func (m *ZAddOptions) GetSet() []byte {
+func (m *LogRequest) GetLevel() string {
 	if m != nil {
-		return m.Set
+		return m.Level
 	}
-	return nil
+	return ""
 }
 
-func (m *ZAddOptions) GetScore() *Score {
+func (m *LogRequest) GetComponents() []string {
 	if m != nil {
-		return m.Score
+		return m.Components
 	}
 	return nil
 }
 
-func (m *ZAddOptions) GetKey() []byte {
-	if m != nil {
-		return m.Key
-	}
-	return nil
-}
-
-func (m *ZAddOptions) GetIndex() *Index {
+func (m *LogRequest) GetFollow() bool {
 	if m != nil {
-		return m.Index
+		return m.Follow
 	}
-	return nil
+	return false
 }
 
-type ZScanOptions struct {
-	Set                  []byte   `protobuf:"bytes,1,opt,name=set,proto3" json:"set,omitempty"`
-	Offset               []byte   `protobuf:"bytes,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit                uint64   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Reverse              bool     `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
-	Min                  *Score   `protobuf:"bytes,5,opt,name=min,proto3" json:"min,omitempty"`
-	Max                  *Score   `protobuf:"bytes,6,opt,name=max,proto3" json:"max,omitempty"`
+type LogEntry struct {
+	Timestamp            int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level                string   `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Component            string   `protobuf:"bytes,3,opt,name=component,proto3" json:"component,omitempty"`
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ZScanOptions) Reset()         { *m = ZScanOptions{} }
-func (m *ZScanOptions) String() string { return proto.CompactTextString(m) }
-func (*ZScanOptions) ProtoMessage()    {}
-func (*ZScanOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{49}
+func (m *LogEntry) Reset()         { *m = LogEntry{} }
+func (m *LogEntry) String() string { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()    {}
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{56}
 }
 
-func (m *ZScanOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ZScanOptions.Unmarshal(m, b)
-}
-func (m *ZScanOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ZScanOptions.Marshal(b, m, deterministic)
+func (m *LogEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogEntry.Unmarshal(m, b)
 }
-func (m *ZScanOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ZScanOptions.Merge(m, src)
+func (m *LogEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogEntry.Marshal(b, m, deterministic)
 }
-func (m *ZScanOptions) XXX_Size() int {
-	return xxx_messageInfo_ZScanOptions.Size(m)
+func (m *LogEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogEntry.Merge(m, src)
 }
-func (m *ZScanOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_ZScanOptions.DiscardUnknown(m)
+func (m *LogEntry) XXX_Size() int {
+	return xxx_messageInfo_LogEntry.Size(m)
 }
-
-var xxx_messageInfo_ZScanOptions proto.InternalMessageInfo
-
-func (m *ZScanOptions) GetSet() []byte {
-	if m != nil {
-		return m.Set
-	}
-	return nil
+func (m *LogEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogEntry.DiscardUnknown(m)
 }
 
-func (m *ZScanOptions) GetOffset() []byte {
-	if m != nil {
-		return m.Offset
-	}
-	return nil
-}
+var xxx_messageInfo_LogEntry proto.InternalMessageInfo
 
-func (m *ZScanOptions) GetLimit() uint64 {
+func (m *LogEntry) GetTimestamp() int64 {
 	if m != nil {
-		return m.Limit
+		return m.Timestamp
 	}
 	return 0
 }
 
-func (m *ZScanOptions) GetReverse() bool {
+func (m *LogEntry) GetLevel() string {
 	if m != nil {
-		return m.Reverse
+		return m.Level
 	}
-	return false
+	return ""
 }
 
-func (m *ZScanOptions) GetMin() *Score {
+func (m *LogEntry) GetComponent() string {
 	if m != nil {
-		return m.Min
+		return m.Component
 	}
-	return nil
+	return ""
 }
 
-func (m *ZScanOptions) GetMax() *Score {
+func (m *LogEntry) GetMessage() string {
 	if m != nil {
-		return m.Max
+		return m.Message
 	}
-	return nil
+	return ""
 }
 
-type Score struct {
-	Score                float64  `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+type SafeItem struct {
+	Item                 *Item    `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Proof                *Proof   `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Score) Reset()         { *m = Score{} }
-func (m *Score) String() string { return proto.CompactTextString(m) }
-func (*Score) ProtoMessage()    {}
-func (*Score) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{50}
+func (m *SafeItem) Reset()         { *m = SafeItem{} }
+func (m *SafeItem) String() string { return proto.CompactTextString(m) }
+func (*SafeItem) ProtoMessage()    {}
+func (*SafeItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{57}
 }
 
-func (m *Score) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Score.Unmarshal(m, b)
+func (m *SafeItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeItem.Unmarshal(m, b)
 }
-func (m *Score) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Score.Marshal(b, m, deterministic)
+func (m *SafeItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeItem.Marshal(b, m, deterministic)
 }
-func (m *Score) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Score.Merge(m, src)
+func (m *SafeItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeItem.Merge(m, src)
 }
-func (m *Score) XXX_Size() int {
-	return xxx_messageInfo_Score.Size(m)
+func (m *SafeItem) XXX_Size() int {
+	return xxx_messageInfo_SafeItem.Size(m)
 }
-func (m *Score) XXX_DiscardUnknown() {
-	xxx_messageInfo_Score.DiscardUnknown(m)
+func (m *SafeItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeItem.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Score proto.InternalMessageInfo
+var xxx_messageInfo_SafeItem proto.InternalMessageInfo
 
-func (m *Score) GetScore() float64 {
+func (m *SafeItem) GetItem() *Item {
 	if m != nil {
-		return m.Score
+		return m.Item
 	}
-	return 0
+	return nil
 }
 
-type IScanOptions struct {
-	PageSize             uint64   `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
-	PageNumber           uint64   `protobuf:"varint,2,opt,name=pageNumber,proto3" json:"pageNumber,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *SafeItem) GetProof() *Proof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
 }
 
-func (m *IScanOptions) Reset()         { *m = IScanOptions{} }
-func (m *IScanOptions) String() string { return proto.CompactTextString(m) }
-func (*IScanOptions) ProtoMessage()    {}
-func (*IScanOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{51}
+type SafeStructuredItem struct {
+	Item                 *StructuredItem `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Proof                *Proof          `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
-func (m *IScanOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_IScanOptions.Unmarshal(m, b)
+func (m *SafeStructuredItem) Reset()         { *m = SafeStructuredItem{} }
+func (m *SafeStructuredItem) String() string { return proto.CompactTextString(m) }
+func (*SafeStructuredItem) ProtoMessage()    {}
+func (*SafeStructuredItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{58}
 }
-func (m *IScanOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_IScanOptions.Marshal(b, m, deterministic)
+
+func (m *SafeStructuredItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeStructuredItem.Unmarshal(m, b)
 }
-func (m *IScanOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_IScanOptions.Merge(m, src)
+func (m *SafeStructuredItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeStructuredItem.Marshal(b, m, deterministic)
 }
-func (m *IScanOptions) XXX_Size() int {
-	return xxx_messageInfo_IScanOptions.Size(m)
+func (m *SafeStructuredItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeStructuredItem.Merge(m, src)
 }
-func (m *IScanOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_IScanOptions.DiscardUnknown(m)
+func (m *SafeStructuredItem) XXX_Size() int {
+	return xxx_messageInfo_SafeStructuredItem.Size(m)
+}
+func (m *SafeStructuredItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeStructuredItem.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_IScanOptions proto.InternalMessageInfo
+var xxx_messageInfo_SafeStructuredItem proto.InternalMessageInfo
 
-func (m *IScanOptions) GetPageSize() uint64 {
+func (m *SafeStructuredItem) GetItem() *StructuredItem {
 	if m != nil {
-		return m.PageSize
+		return m.Item
 	}
-	return 0
+	return nil
 }
 
-func (m *IScanOptions) GetPageNumber() uint64 {
+func (m *SafeStructuredItem) GetProof() *Proof {
 	if m != nil {
-		return m.PageNumber
+		return m.Proof
 	}
-	return 0
+	return nil
 }
 
-type Page struct {
-	Items                []*Item  `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	More                 bool     `protobuf:"varint,3,opt,name=more,proto3" json:"more,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type SafeSetOptions struct {
+	Kv                   *KeyValue `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	RootIndex            *Index    `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
-func (m *Page) Reset()         { *m = Page{} }
-func (m *Page) String() string { return proto.CompactTextString(m) }
-func (*Page) ProtoMessage()    {}
-func (*Page) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{52}
+func (m *SafeSetOptions) Reset()         { *m = SafeSetOptions{} }
+func (m *SafeSetOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeSetOptions) ProtoMessage()    {}
+func (*SafeSetOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{59}
 }
 
-func (m *Page) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Page.Unmarshal(m, b)
+func (m *SafeSetOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeSetOptions.Unmarshal(m, b)
 }
-func (m *Page) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Page.Marshal(b, m, deterministic)
+func (m *SafeSetOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeSetOptions.Marshal(b, m, deterministic)
 }
-func (m *Page) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Page.Merge(m, src)
+func (m *SafeSetOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeSetOptions.Merge(m, src)
 }
-func (m *Page) XXX_Size() int {
-	return xxx_messageInfo_Page.Size(m)
+func (m *SafeSetOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeSetOptions.Size(m)
 }
-func (m *Page) XXX_DiscardUnknown() {
-	xxx_messageInfo_Page.DiscardUnknown(m)
+func (m *SafeSetOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeSetOptions.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Page proto.InternalMessageInfo
+var xxx_messageInfo_SafeSetOptions proto.InternalMessageInfo
 
-func (m *Page) GetItems() []*Item {
+func (m *SafeSetOptions) GetKv() *KeyValue {
 	if m != nil {
-		return m.Items
+		return m.Kv
 	}
 	return nil
 }
 
-func (m *Page) GetMore() bool {
+func (m *SafeSetOptions) GetRootIndex() *Index {
 	if m != nil {
-		return m.More
+		return m.RootIndex
 	}
-	return false
+	return nil
 }
 
-type SPage struct {
-	Items                []*StructuredItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	PageNum              uint64            `protobuf:"varint,2,opt,name=pageNum,proto3" json:"pageNum,omitempty"`
-	More                 bool              `protobuf:"varint,3,opt,name=more,proto3" json:"more,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+type SafeSetSVOptions struct {
+	Skv                  *StructuredKeyValue `protobuf:"bytes,1,opt,name=skv,proto3" json:"skv,omitempty"`
+	RootIndex            *Index              `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
-func (m *SPage) Reset()         { *m = SPage{} }
-func (m *SPage) String() string { return proto.CompactTextString(m) }
-func (*SPage) ProtoMessage()    {}
-func (*SPage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{53}
+func (m *SafeSetSVOptions) Reset()         { *m = SafeSetSVOptions{} }
+func (m *SafeSetSVOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeSetSVOptions) ProtoMessage()    {}
+func (*SafeSetSVOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{60}
 }
 
-func (m *SPage) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SPage.Unmarshal(m, b)
+func (m *SafeSetSVOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeSetSVOptions.Unmarshal(m, b)
 }
-func (m *SPage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SPage.Marshal(b, m, deterministic)
+func (m *SafeSetSVOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeSetSVOptions.Marshal(b, m, deterministic)
 }
-func (m *SPage) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SPage.Merge(m, src)
+func (m *SafeSetSVOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeSetSVOptions.Merge(m, src)
 }
-func (m *SPage) XXX_Size() int {
-	return xxx_messageInfo_SPage.Size(m)
+func (m *SafeSetSVOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeSetSVOptions.Size(m)
 }
-func (m *SPage) XXX_DiscardUnknown() {
-	xxx_messageInfo_SPage.DiscardUnknown(m)
+func (m *SafeSetSVOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeSetSVOptions.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SPage proto.InternalMessageInfo
+var xxx_messageInfo_SafeSetSVOptions proto.InternalMessageInfo
 
-func (m *SPage) GetItems() []*StructuredItem {
+func (m *SafeSetSVOptions) GetSkv() *StructuredKeyValue {
 	if m != nil {
-		return m.Items
+		return m.Skv
 	}
 	return nil
 }
 
-func (m *SPage) GetPageNum() uint64 {
-	if m != nil {
-		return m.PageNum
-	}
-	return 0
-}
-
-func (m *SPage) GetMore() bool {
+func (m *SafeSetSVOptions) GetRootIndex() *Index {
 	if m != nil {
-		return m.More
+		return m.RootIndex
 	}
-	return false
+	return nil
 }
 
-type HistoryOptions struct {
+type SafeGetOptions struct {
 	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Offset               uint64   `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit                uint64   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Reverse              bool     `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	RootIndex            *Index   `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *HistoryOptions) Reset()         { *m = HistoryOptions{} }
-func (m *HistoryOptions) String() string { return proto.CompactTextString(m) }
-func (*HistoryOptions) ProtoMessage()    {}
-func (*HistoryOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{54}
+func (m *SafeGetOptions) Reset()         { *m = SafeGetOptions{} }
+func (m *SafeGetOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeGetOptions) ProtoMessage()    {}
+func (*SafeGetOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{61}
 }
 
-func (m *HistoryOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_HistoryOptions.Unmarshal(m, b)
+func (m *SafeGetOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeGetOptions.Unmarshal(m, b)
 }
-func (m *HistoryOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_HistoryOptions.Marshal(b, m, deterministic)
+func (m *SafeGetOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeGetOptions.Marshal(b, m, deterministic)
 }
-func (m *HistoryOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_HistoryOptions.Merge(m, src)
+func (m *SafeGetOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeGetOptions.Merge(m, src)
 }
-func (m *HistoryOptions) XXX_Size() int {
-	return xxx_messageInfo_HistoryOptions.Size(m)
+func (m *SafeGetOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeGetOptions.Size(m)
 }
-func (m *HistoryOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_HistoryOptions.DiscardUnknown(m)
+func (m *SafeGetOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeGetOptions.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_HistoryOptions proto.InternalMessageInfo
+var xxx_messageInfo_SafeGetOptions proto.InternalMessageInfo
 
-func (m *HistoryOptions) GetKey() []byte {
+func (m *SafeGetOptions) GetKey() []byte {
 	if m != nil {
 		return m.Key
 	}
 	return nil
 }
 
-func (m *HistoryOptions) GetOffset() uint64 {
-	if m != nil {
-		return m.Offset
-	}
-	return 0
-}
-
-func (m *HistoryOptions) GetLimit() uint64 {
+func (m *SafeGetOptions) GetRootIndex() *Index {
 	if m != nil {
-		return m.Limit
+		return m.RootIndex
 	}
-	return 0
+	return nil
 }
 
-func (m *HistoryOptions) GetReverse() bool {
-	if m != nil {
-		return m.Reverse
-	}
-	return false
+// ValueHashOptions asks the server to compare a locally computed SHA-256 of a value with the stored one
+type ValueHashOptions struct {
+	Key       []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ValueHash []byte `protobuf:"bytes,2,opt,name=valueHash,proto3" json:"valueHash,omitempty"`
+	// index selects the entry to check; when missing, the current entry of the key is checked
+	Index     *Index `protobuf:"bytes,3,opt,name=index,proto3" json:"index,omitempty"`
+	RootIndex *Index `protobuf:"bytes,4,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	// structured tells that the hash is computed over the payload of a structured value rather than over the stored bytes
+	Structured           bool     `protobuf:"varint,5,opt,name=structured,proto3" json:"structured,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type SafeZAddOptions struct {
-	Zopts                *ZAddOptions `protobuf:"bytes,1,opt,name=zopts,proto3" json:"zopts,omitempty"`
-	RootIndex            *Index       `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+func (m *ValueHashOptions) Reset()         { *m = ValueHashOptions{} }
+func (m *ValueHashOptions) String() string { return proto.CompactTextString(m) }
+func (*ValueHashOptions) ProtoMessage()    {}
+func (*ValueHashOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{62}
 }
 
-func (m *SafeZAddOptions) Reset()         { *m = SafeZAddOptions{} }
-func (m *SafeZAddOptions) String() string { return proto.CompactTextString(m) }
-func (*SafeZAddOptions) ProtoMessage()    {}
-func (*SafeZAddOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{55}
+func (m *ValueHashOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValueHashOptions.Unmarshal(m, b)
 }
-
-func (m *SafeZAddOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeZAddOptions.Unmarshal(m, b)
+func (m *ValueHashOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValueHashOptions.Marshal(b, m, deterministic)
 }
-func (m *SafeZAddOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeZAddOptions.Marshal(b, m, deterministic)
+func (m *ValueHashOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValueHashOptions.Merge(m, src)
 }
-func (m *SafeZAddOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeZAddOptions.Merge(m, src)
+func (m *ValueHashOptions) XXX_Size() int {
+	return xxx_messageInfo_ValueHashOptions.Size(m)
 }
-func (m *SafeZAddOptions) XXX_Size() int {
-	return xxx_messageInfo_SafeZAddOptions.Size(m)
+func (m *ValueHashOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValueHashOptions.DiscardUnknown(m)
 }
-func (m *SafeZAddOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeZAddOptions.DiscardUnknown(m)
+
+var xxx_messageInfo_ValueHashOptions proto.InternalMessageInfo
+
+func (m *ValueHashOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
 }
 
-var xxx_messageInfo_SafeZAddOptions proto.InternalMessageInfo
+func (m *ValueHashOptions) GetValueHash() []byte {
+	if m != nil {
+		return m.ValueHash
+	}
+	return nil
+}
 
-func (m *SafeZAddOptions) GetZopts() *ZAddOptions {
+func (m *ValueHashOptions) GetIndex() *Index {
 	if m != nil {
-		return m.Zopts
+		return m.Index
 	}
 	return nil
 }
 
-func (m *SafeZAddOptions) GetRootIndex() *Index {
+func (m *ValueHashOptions) GetRootIndex() *Index {
 	if m != nil {
 		return m.RootIndex
 	}
 	return nil
 }
 
-type SafeIndexOptions struct {
-	Index                uint64   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	RootIndex            *Index   `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+func (m *ValueHashOptions) GetStructured() bool {
+	if m != nil {
+		return m.Structured
+	}
+	return false
+}
+
+// ValueHashVerification tells whether the hash matched the stored value and proves the inclusion of the checked entry
+type ValueHashVerification struct {
+	Matches              bool     `protobuf:"varint,1,opt,name=matches,proto3" json:"matches,omitempty"`
+	Proof                *Proof   `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SafeIndexOptions) Reset()         { *m = SafeIndexOptions{} }
-func (m *SafeIndexOptions) String() string { return proto.CompactTextString(m) }
-func (*SafeIndexOptions) ProtoMessage()    {}
-func (*SafeIndexOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{56}
+func (m *ValueHashVerification) Reset()         { *m = ValueHashVerification{} }
+func (m *ValueHashVerification) String() string { return proto.CompactTextString(m) }
+func (*ValueHashVerification) ProtoMessage()    {}
+func (*ValueHashVerification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{63}
 }
 
-func (m *SafeIndexOptions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SafeIndexOptions.Unmarshal(m, b)
+func (m *ValueHashVerification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValueHashVerification.Unmarshal(m, b)
 }
-func (m *SafeIndexOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SafeIndexOptions.Marshal(b, m, deterministic)
+func (m *ValueHashVerification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValueHashVerification.Marshal(b, m, deterministic)
 }
-func (m *SafeIndexOptions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SafeIndexOptions.Merge(m, src)
+func (m *ValueHashVerification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValueHashVerification.Merge(m, src)
 }
-func (m *SafeIndexOptions) XXX_Size() int {
-	return xxx_messageInfo_SafeIndexOptions.Size(m)
+func (m *ValueHashVerification) XXX_Size() int {
+	return xxx_messageInfo_ValueHashVerification.Size(m)
 }
-func (m *SafeIndexOptions) XXX_DiscardUnknown() {
-	xxx_messageInfo_SafeIndexOptions.DiscardUnknown(m)
+func (m *ValueHashVerification) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValueHashVerification.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SafeIndexOptions proto.InternalMessageInfo
+var xxx_messageInfo_ValueHashVerification proto.InternalMessageInfo
 
-func (m *SafeIndexOptions) GetIndex() uint64 {
+func (m *ValueHashVerification) GetMatches() bool {
 	if m != nil {
-		return m.Index
+		return m.Matches
 	}
-	return 0
+	return false
 }
 
-func (m *SafeIndexOptions) GetRootIndex() *Index {
+func (m *ValueHashVerification) GetProof() *Proof {
 	if m != nil {
-		return m.RootIndex
+		return m.Proof
 	}
 	return nil
 }
 
-type Database struct {
-	Databasename         string   `protobuf:"bytes,1,opt,name=databasename,proto3" json:"databasename,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type SafeReferenceOptions struct {
+	Ro                   *ReferenceOptions `protobuf:"bytes,1,opt,name=ro,proto3" json:"ro,omitempty"`
+	RootIndex            *Index            `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func (m *Database) Reset()         { *m = Database{} }
-func (m *Database) String() string { return proto.CompactTextString(m) }
-func (*Database) ProtoMessage()    {}
-func (*Database) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{57}
+func (m *SafeReferenceOptions) Reset()         { *m = SafeReferenceOptions{} }
+func (m *SafeReferenceOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeReferenceOptions) ProtoMessage()    {}
+func (*SafeReferenceOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{64}
 }
 
-func (m *Database) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Database.Unmarshal(m, b)
+func (m *SafeReferenceOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeReferenceOptions.Unmarshal(m, b)
 }
-func (m *Database) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Database.Marshal(b, m, deterministic)
+func (m *SafeReferenceOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeReferenceOptions.Marshal(b, m, deterministic)
 }
-func (m *Database) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Database.Merge(m, src)
+func (m *SafeReferenceOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeReferenceOptions.Merge(m, src)
 }
-func (m *Database) XXX_Size() int {
-	return xxx_messageInfo_Database.Size(m)
+func (m *SafeReferenceOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeReferenceOptions.Size(m)
 }
-func (m *Database) XXX_DiscardUnknown() {
-	xxx_messageInfo_Database.DiscardUnknown(m)
+func (m *SafeReferenceOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeReferenceOptions.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Database proto.InternalMessageInfo
+var xxx_messageInfo_SafeReferenceOptions proto.InternalMessageInfo
 
-func (m *Database) GetDatabasename() string {
+func (m *SafeReferenceOptions) GetRo() *ReferenceOptions {
 	if m != nil {
-		return m.Databasename
+		return m.Ro
 	}
-	return ""
+	return nil
 }
 
-type UseDatabaseReply struct {
-	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+func (m *SafeReferenceOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+type HealthResponse struct {
+	Status               bool     `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UseDatabaseReply) Reset()         { *m = UseDatabaseReply{} }
-func (m *UseDatabaseReply) String() string { return proto.CompactTextString(m) }
-func (*UseDatabaseReply) ProtoMessage()    {}
-func (*UseDatabaseReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{58}
+func (m *HealthResponse) Reset()         { *m = HealthResponse{} }
+func (m *HealthResponse) String() string { return proto.CompactTextString(m) }
+func (*HealthResponse) ProtoMessage()    {}
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{65}
 }
 
-func (m *UseDatabaseReply) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UseDatabaseReply.Unmarshal(m, b)
+func (m *HealthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HealthResponse.Unmarshal(m, b)
 }
-func (m *UseDatabaseReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UseDatabaseReply.Marshal(b, m, deterministic)
+func (m *HealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HealthResponse.Marshal(b, m, deterministic)
 }
-func (m *UseDatabaseReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UseDatabaseReply.Merge(m, src)
+func (m *HealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HealthResponse.Merge(m, src)
 }
-func (m *UseDatabaseReply) XXX_Size() int {
-	return xxx_messageInfo_UseDatabaseReply.Size(m)
+func (m *HealthResponse) XXX_Size() int {
+	return xxx_messageInfo_HealthResponse.Size(m)
 }
-func (m *UseDatabaseReply) XXX_DiscardUnknown() {
-	xxx_messageInfo_UseDatabaseReply.DiscardUnknown(m)
+func (m *HealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HealthResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UseDatabaseReply proto.InternalMessageInfo
+var xxx_messageInfo_HealthResponse proto.InternalMessageInfo
 
-func (m *UseDatabaseReply) GetToken() string {
+func (m *HealthResponse) GetStatus() bool {
 	if m != nil {
-		return m.Token
+		return m.Status
 	}
-	return ""
+	return false
+}
+
+func (m *HealthResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+type ReferenceOptions struct {
+	Reference            []byte   `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Index                *Index   `protobuf:"bytes,3,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReferenceOptions) Reset()         { *m = ReferenceOptions{} }
+func (m *ReferenceOptions) String() string { return proto.CompactTextString(m) }
+func (*ReferenceOptions) ProtoMessage()    {}
+func (*ReferenceOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{66}
+}
+
+func (m *ReferenceOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReferenceOptions.Unmarshal(m, b)
+}
+func (m *ReferenceOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReferenceOptions.Marshal(b, m, deterministic)
+}
+func (m *ReferenceOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReferenceOptions.Merge(m, src)
+}
+func (m *ReferenceOptions) XXX_Size() int {
+	return xxx_messageInfo_ReferenceOptions.Size(m)
+}
+func (m *ReferenceOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReferenceOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReferenceOptions proto.InternalMessageInfo
+
+func (m *ReferenceOptions) GetReference() []byte {
+	if m != nil {
+		return m.Reference
+	}
+	return nil
+}
+
+func (m *ReferenceOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ReferenceOptions) GetIndex() *Index {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+type CompareAndReferenceOptions struct {
+	Kv                   *KeyValue `protobuf:"bytes,1,opt,name=kv,proto3" json:"kv,omitempty"`
+	Reference            []byte    `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	ExpectedIndex        *Index    `protobuf:"bytes,3,opt,name=expectedIndex,proto3" json:"expectedIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CompareAndReferenceOptions) Reset()         { *m = CompareAndReferenceOptions{} }
+func (m *CompareAndReferenceOptions) String() string { return proto.CompactTextString(m) }
+func (*CompareAndReferenceOptions) ProtoMessage()    {}
+func (*CompareAndReferenceOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{67}
+}
+
+func (m *CompareAndReferenceOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndReferenceOptions.Unmarshal(m, b)
+}
+func (m *CompareAndReferenceOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndReferenceOptions.Marshal(b, m, deterministic)
+}
+func (m *CompareAndReferenceOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndReferenceOptions.Merge(m, src)
+}
+func (m *CompareAndReferenceOptions) XXX_Size() int {
+	return xxx_messageInfo_CompareAndReferenceOptions.Size(m)
+}
+func (m *CompareAndReferenceOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndReferenceOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndReferenceOptions proto.InternalMessageInfo
+
+func (m *CompareAndReferenceOptions) GetKv() *KeyValue {
+	if m != nil {
+		return m.Kv
+	}
+	return nil
+}
+
+func (m *CompareAndReferenceOptions) GetReference() []byte {
+	if m != nil {
+		return m.Reference
+	}
+	return nil
+}
+
+func (m *CompareAndReferenceOptions) GetExpectedIndex() *Index {
+	if m != nil {
+		return m.ExpectedIndex
+	}
+	return nil
+}
+
+type ZAddOptions struct {
+	Set                  []byte   `protobuf:"bytes,1,opt,name=set,proto3" json:"set,omitempty"`
+	Score                *Score   `protobuf:"bytes,2,opt,name=score,proto3" json:"score,omitempty"`
+	Key                  []byte   `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Index                *Index   `protobuf:"bytes,4,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ZAddOptions) Reset()         { *m = ZAddOptions{} }
+func (m *ZAddOptions) String() string { return proto.CompactTextString(m) }
+func (*ZAddOptions) ProtoMessage()    {}
+func (*ZAddOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{68}
+}
+
+func (m *ZAddOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ZAddOptions.Unmarshal(m, b)
+}
+func (m *ZAddOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ZAddOptions.Marshal(b, m, deterministic)
+}
+func (m *ZAddOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ZAddOptions.Merge(m, src)
+}
+func (m *ZAddOptions) XXX_Size() int {
+	return xxx_messageInfo_ZAddOptions.Size(m)
+}
+func (m *ZAddOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ZAddOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ZAddOptions proto.InternalMessageInfo
+
+func (m *ZAddOptions) GetSet() []byte {
+	if m != nil {
+		return m.Set
+	}
+	return nil
+}
+
+func (m *ZAddOptions) GetScore() *Score {
+	if m != nil {
+		return m.Score
+	}
+	return nil
+}
+
+func (m *ZAddOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ZAddOptions) GetIndex() *Index {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+type ZScanOptions struct {
+	Set                  []byte   `protobuf:"bytes,1,opt,name=set,proto3" json:"set,omitempty"`
+	Offset               []byte   `protobuf:"bytes,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit                uint64   `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse              bool     `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	Min                  *Score   `protobuf:"bytes,5,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  *Score   `protobuf:"bytes,6,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ZScanOptions) Reset()         { *m = ZScanOptions{} }
+func (m *ZScanOptions) String() string { return proto.CompactTextString(m) }
+func (*ZScanOptions) ProtoMessage()    {}
+func (*ZScanOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{69}
+}
+
+func (m *ZScanOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ZScanOptions.Unmarshal(m, b)
+}
+func (m *ZScanOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ZScanOptions.Marshal(b, m, deterministic)
+}
+func (m *ZScanOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ZScanOptions.Merge(m, src)
+}
+func (m *ZScanOptions) XXX_Size() int {
+	return xxx_messageInfo_ZScanOptions.Size(m)
+}
+func (m *ZScanOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ZScanOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ZScanOptions proto.InternalMessageInfo
+
+func (m *ZScanOptions) GetSet() []byte {
+	if m != nil {
+		return m.Set
+	}
+	return nil
+}
+
+func (m *ZScanOptions) GetOffset() []byte {
+	if m != nil {
+		return m.Offset
+	}
+	return nil
+}
+
+func (m *ZScanOptions) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ZScanOptions) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+func (m *ZScanOptions) GetMin() *Score {
+	if m != nil {
+		return m.Min
+	}
+	return nil
+}
+
+func (m *ZScanOptions) GetMax() *Score {
+	if m != nil {
+		return m.Max
+	}
+	return nil
+}
+
+type Score struct {
+	Score                float64  `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Score) Reset()         { *m = Score{} }
+func (m *Score) String() string { return proto.CompactTextString(m) }
+func (*Score) ProtoMessage()    {}
+func (*Score) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{70}
+}
+
+func (m *Score) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Score.Unmarshal(m, b)
+}
+func (m *Score) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Score.Marshal(b, m, deterministic)
+}
+func (m *Score) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Score.Merge(m, src)
+}
+func (m *Score) XXX_Size() int {
+	return xxx_messageInfo_Score.Size(m)
+}
+func (m *Score) XXX_DiscardUnknown() {
+	xxx_messageInfo_Score.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Score proto.InternalMessageInfo
+
+func (m *Score) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+type IScanOptions struct {
+	PageSize             uint64   `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageNumber           uint64   `protobuf:"varint,2,opt,name=pageNumber,proto3" json:"pageNumber,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IScanOptions) Reset()         { *m = IScanOptions{} }
+func (m *IScanOptions) String() string { return proto.CompactTextString(m) }
+func (*IScanOptions) ProtoMessage()    {}
+func (*IScanOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{71}
+}
+
+func (m *IScanOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IScanOptions.Unmarshal(m, b)
+}
+func (m *IScanOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IScanOptions.Marshal(b, m, deterministic)
+}
+func (m *IScanOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IScanOptions.Merge(m, src)
+}
+func (m *IScanOptions) XXX_Size() int {
+	return xxx_messageInfo_IScanOptions.Size(m)
+}
+func (m *IScanOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_IScanOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IScanOptions proto.InternalMessageInfo
+
+func (m *IScanOptions) GetPageSize() uint64 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *IScanOptions) GetPageNumber() uint64 {
+	if m != nil {
+		return m.PageNumber
+	}
+	return 0
+}
+
+type Page struct {
+	Items                []*Item  `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	More                 bool     `protobuf:"varint,3,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Page) Reset()         { *m = Page{} }
+func (m *Page) String() string { return proto.CompactTextString(m) }
+func (*Page) ProtoMessage()    {}
+func (*Page) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{72}
+}
+
+func (m *Page) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Page.Unmarshal(m, b)
+}
+func (m *Page) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Page.Marshal(b, m, deterministic)
+}
+func (m *Page) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Page.Merge(m, src)
+}
+func (m *Page) XXX_Size() int {
+	return xxx_messageInfo_Page.Size(m)
+}
+func (m *Page) XXX_DiscardUnknown() {
+	xxx_messageInfo_Page.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Page proto.InternalMessageInfo
+
+func (m *Page) GetItems() []*Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *Page) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+type SPage struct {
+	Items                []*StructuredItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	PageNum              uint64            `protobuf:"varint,2,opt,name=pageNum,proto3" json:"pageNum,omitempty"`
+	More                 bool              `protobuf:"varint,3,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SPage) Reset()         { *m = SPage{} }
+func (m *SPage) String() string { return proto.CompactTextString(m) }
+func (*SPage) ProtoMessage()    {}
+func (*SPage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{73}
+}
+
+func (m *SPage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SPage.Unmarshal(m, b)
+}
+func (m *SPage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SPage.Marshal(b, m, deterministic)
+}
+func (m *SPage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SPage.Merge(m, src)
+}
+func (m *SPage) XXX_Size() int {
+	return xxx_messageInfo_SPage.Size(m)
+}
+func (m *SPage) XXX_DiscardUnknown() {
+	xxx_messageInfo_SPage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SPage proto.InternalMessageInfo
+
+func (m *SPage) GetItems() []*StructuredItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *SPage) GetPageNum() uint64 {
+	if m != nil {
+		return m.PageNum
+	}
+	return 0
+}
+
+func (m *SPage) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+type HistoryOptions struct {
+	Key     []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Offset  uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit   uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse bool   `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	// fromIndex and toIndex are the inclusive range of indexes of the returned entries, a zero toIndex meaning no upper bound
+	FromIndex uint64 `protobuf:"varint,5,opt,name=fromIndex,proto3" json:"fromIndex,omitempty"`
+	ToIndex   uint64 `protobuf:"varint,6,opt,name=toIndex,proto3" json:"toIndex,omitempty"`
+	// since and until are the window of commit times, in unix seconds, of the returned entries: from since included to until excluded, zero meaning no bound
+	Since                int64    `protobuf:"varint,7,opt,name=since,proto3" json:"since,omitempty"`
+	Until                int64    `protobuf:"varint,8,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryOptions) Reset()         { *m = HistoryOptions{} }
+func (m *HistoryOptions) String() string { return proto.CompactTextString(m) }
+func (*HistoryOptions) ProtoMessage()    {}
+func (*HistoryOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{74}
+}
+
+func (m *HistoryOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryOptions.Unmarshal(m, b)
+}
+func (m *HistoryOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryOptions.Marshal(b, m, deterministic)
+}
+func (m *HistoryOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryOptions.Merge(m, src)
+}
+func (m *HistoryOptions) XXX_Size() int {
+	return xxx_messageInfo_HistoryOptions.Size(m)
+}
+func (m *HistoryOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryOptions proto.InternalMessageInfo
+
+func (m *HistoryOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *HistoryOptions) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *HistoryOptions) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *HistoryOptions) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+func (m *HistoryOptions) GetFromIndex() uint64 {
+	if m != nil {
+		return m.FromIndex
+	}
+	return 0
+}
+
+func (m *HistoryOptions) GetToIndex() uint64 {
+	if m != nil {
+		return m.ToIndex
+	}
+	return 0
+}
+
+func (m *HistoryOptions) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *HistoryOptions) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+type SafeZAddOptions struct {
+	Zopts                *ZAddOptions `protobuf:"bytes,1,opt,name=zopts,proto3" json:"zopts,omitempty"`
+	RootIndex            *Index       `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SafeZAddOptions) Reset()         { *m = SafeZAddOptions{} }
+func (m *SafeZAddOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeZAddOptions) ProtoMessage()    {}
+func (*SafeZAddOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{75}
+}
+
+func (m *SafeZAddOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeZAddOptions.Unmarshal(m, b)
+}
+func (m *SafeZAddOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeZAddOptions.Marshal(b, m, deterministic)
+}
+func (m *SafeZAddOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeZAddOptions.Merge(m, src)
+}
+func (m *SafeZAddOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeZAddOptions.Size(m)
+}
+func (m *SafeZAddOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeZAddOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeZAddOptions proto.InternalMessageInfo
+
+func (m *SafeZAddOptions) GetZopts() *ZAddOptions {
+	if m != nil {
+		return m.Zopts
+	}
+	return nil
+}
+
+func (m *SafeZAddOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+type SafeIndexOptions struct {
+	Index                uint64   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	RootIndex            *Index   `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SafeIndexOptions) Reset()         { *m = SafeIndexOptions{} }
+func (m *SafeIndexOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeIndexOptions) ProtoMessage()    {}
+func (*SafeIndexOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{76}
+}
+
+func (m *SafeIndexOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeIndexOptions.Unmarshal(m, b)
+}
+func (m *SafeIndexOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeIndexOptions.Marshal(b, m, deterministic)
+}
+func (m *SafeIndexOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeIndexOptions.Merge(m, src)
+}
+func (m *SafeIndexOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeIndexOptions.Size(m)
+}
+func (m *SafeIndexOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeIndexOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeIndexOptions proto.InternalMessageInfo
+
+func (m *SafeIndexOptions) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *SafeIndexOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+type Database struct {
+	Databasename         string   `protobuf:"bytes,1,opt,name=databasename,proto3" json:"databasename,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Database) Reset()         { *m = Database{} }
+func (m *Database) String() string { return proto.CompactTextString(m) }
+func (*Database) ProtoMessage()    {}
+func (*Database) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{77}
+}
+
+func (m *Database) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Database.Unmarshal(m, b)
+}
+func (m *Database) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Database.Marshal(b, m, deterministic)
+}
+func (m *Database) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Database.Merge(m, src)
+}
+func (m *Database) XXX_Size() int {
+	return xxx_messageInfo_Database.Size(m)
+}
+func (m *Database) XXX_DiscardUnknown() {
+	xxx_messageInfo_Database.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Database proto.InternalMessageInfo
+
+func (m *Database) GetDatabasename() string {
+	if m != nil {
+		return m.Databasename
+	}
+	return ""
+}
+
+type UseDatabaseReply struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UseDatabaseReply) Reset()         { *m = UseDatabaseReply{} }
+func (m *UseDatabaseReply) String() string { return proto.CompactTextString(m) }
+func (*UseDatabaseReply) ProtoMessage()    {}
+func (*UseDatabaseReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{78}
+}
+
+func (m *UseDatabaseReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UseDatabaseReply.Unmarshal(m, b)
+}
+func (m *UseDatabaseReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UseDatabaseReply.Marshal(b, m, deterministic)
+}
+func (m *UseDatabaseReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UseDatabaseReply.Merge(m, src)
+}
+func (m *UseDatabaseReply) XXX_Size() int {
+	return xxx_messageInfo_UseDatabaseReply.Size(m)
+}
+func (m *UseDatabaseReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UseDatabaseReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UseDatabaseReply proto.InternalMessageInfo
+
+func (m *UseDatabaseReply) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
 }
 
 type ChangePermissionRequest struct {
@@ -3032,151 +4424,1351 @@ type ChangePermissionRequest struct {
 	XXX_sizecache        int32            `json:"-"`
 }
 
-func (m *ChangePermissionRequest) Reset()         { *m = ChangePermissionRequest{} }
-func (m *ChangePermissionRequest) String() string { return proto.CompactTextString(m) }
-func (*ChangePermissionRequest) ProtoMessage()    {}
-func (*ChangePermissionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{59}
+func (m *ChangePermissionRequest) Reset()         { *m = ChangePermissionRequest{} }
+func (m *ChangePermissionRequest) String() string { return proto.CompactTextString(m) }
+func (*ChangePermissionRequest) ProtoMessage()    {}
+func (*ChangePermissionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{79}
+}
+
+func (m *ChangePermissionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChangePermissionRequest.Unmarshal(m, b)
+}
+func (m *ChangePermissionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChangePermissionRequest.Marshal(b, m, deterministic)
+}
+func (m *ChangePermissionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangePermissionRequest.Merge(m, src)
+}
+func (m *ChangePermissionRequest) XXX_Size() int {
+	return xxx_messageInfo_ChangePermissionRequest.Size(m)
+}
+func (m *ChangePermissionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangePermissionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChangePermissionRequest proto.InternalMessageInfo
+
+func (m *ChangePermissionRequest) GetAction() PermissionAction {
+	if m != nil {
+		return m.Action
+	}
+	return PermissionAction_GRANT
+}
+
+func (m *ChangePermissionRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *ChangePermissionRequest) GetDatabase() string {
+	if m != nil {
+		return m.Database
+	}
+	return ""
+}
+
+func (m *ChangePermissionRequest) GetPermission() uint32 {
+	if m != nil {
+		return m.Permission
+	}
+	return 0
+}
+
+type SetActiveUserRequest struct {
+	Active               bool     `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetActiveUserRequest) Reset()         { *m = SetActiveUserRequest{} }
+func (m *SetActiveUserRequest) String() string { return proto.CompactTextString(m) }
+func (*SetActiveUserRequest) ProtoMessage()    {}
+func (*SetActiveUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{80}
+}
+
+func (m *SetActiveUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetActiveUserRequest.Unmarshal(m, b)
+}
+func (m *SetActiveUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetActiveUserRequest.Marshal(b, m, deterministic)
+}
+func (m *SetActiveUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetActiveUserRequest.Merge(m, src)
+}
+func (m *SetActiveUserRequest) XXX_Size() int {
+	return xxx_messageInfo_SetActiveUserRequest.Size(m)
+}
+func (m *SetActiveUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetActiveUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetActiveUserRequest proto.InternalMessageInfo
+
+func (m *SetActiveUserRequest) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *SetActiveUserRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type DatabaseListResponse struct {
+	Databases            []*Database `protobuf:"bytes,1,rep,name=databases,proto3" json:"databases,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *DatabaseListResponse) Reset()         { *m = DatabaseListResponse{} }
+func (m *DatabaseListResponse) String() string { return proto.CompactTextString(m) }
+func (*DatabaseListResponse) ProtoMessage()    {}
+func (*DatabaseListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{81}
+}
+
+func (m *DatabaseListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DatabaseListResponse.Unmarshal(m, b)
+}
+func (m *DatabaseListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DatabaseListResponse.Marshal(b, m, deterministic)
+}
+func (m *DatabaseListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DatabaseListResponse.Merge(m, src)
+}
+func (m *DatabaseListResponse) XXX_Size() int {
+	return xxx_messageInfo_DatabaseListResponse.Size(m)
+}
+func (m *DatabaseListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DatabaseListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DatabaseListResponse proto.InternalMessageInfo
+
+func (m *DatabaseListResponse) GetDatabases() []*Database {
+	if m != nil {
+		return m.Databases
+	}
+	return nil
+}
+
+type SafeExecAllTxOptions struct {
+	Ops                  *Ops     `protobuf:"bytes,1,opt,name=ops,proto3" json:"ops,omitempty"`
+	RootIndex            *Index   `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SafeExecAllTxOptions) Reset()         { *m = SafeExecAllTxOptions{} }
+func (m *SafeExecAllTxOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeExecAllTxOptions) ProtoMessage()    {}
+func (*SafeExecAllTxOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{82}
+}
+
+func (m *SafeExecAllTxOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeExecAllTxOptions.Unmarshal(m, b)
+}
+func (m *SafeExecAllTxOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeExecAllTxOptions.Marshal(b, m, deterministic)
+}
+func (m *SafeExecAllTxOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeExecAllTxOptions.Merge(m, src)
+}
+func (m *SafeExecAllTxOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeExecAllTxOptions.Size(m)
+}
+func (m *SafeExecAllTxOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeExecAllTxOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeExecAllTxOptions proto.InternalMessageInfo
+
+func (m *SafeExecAllTxOptions) GetOps() *Ops {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+func (m *SafeExecAllTxOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+type TxProof struct {
+	Commit               *Item    `protobuf:"bytes,1,opt,name=commit,proto3" json:"commit,omitempty"`
+	Entries              []*Item  `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	Proof                *Proof   `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxProof) Reset()         { *m = TxProof{} }
+func (m *TxProof) String() string { return proto.CompactTextString(m) }
+func (*TxProof) ProtoMessage()    {}
+func (*TxProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{83}
+}
+
+func (m *TxProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxProof.Unmarshal(m, b)
+}
+func (m *TxProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxProof.Marshal(b, m, deterministic)
+}
+func (m *TxProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxProof.Merge(m, src)
+}
+func (m *TxProof) XXX_Size() int {
+	return xxx_messageInfo_TxProof.Size(m)
+}
+func (m *TxProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxProof proto.InternalMessageInfo
+
+func (m *TxProof) GetCommit() *Item {
+	if m != nil {
+		return m.Commit
+	}
+	return nil
+}
+
+func (m *TxProof) GetEntries() []*Item {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *TxProof) GetProof() *Proof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+// ExpectedIndex is the index a key is expected to be at, a missing index means that the key is expected not to exist
+type ExpectedIndex struct {
+	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Index                *Index   `protobuf:"bytes,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExpectedIndex) Reset()         { *m = ExpectedIndex{} }
+func (m *ExpectedIndex) String() string { return proto.CompactTextString(m) }
+func (*ExpectedIndex) ProtoMessage()    {}
+func (*ExpectedIndex) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{84}
+}
+
+func (m *ExpectedIndex) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpectedIndex.Unmarshal(m, b)
+}
+func (m *ExpectedIndex) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpectedIndex.Marshal(b, m, deterministic)
+}
+func (m *ExpectedIndex) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpectedIndex.Merge(m, src)
+}
+func (m *ExpectedIndex) XXX_Size() int {
+	return xxx_messageInfo_ExpectedIndex.Size(m)
+}
+func (m *ExpectedIndex) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpectedIndex.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpectedIndex proto.InternalMessageInfo
+
+func (m *ExpectedIndex) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ExpectedIndex) GetIndex() *Index {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+type CompareAndExecAllTxOptions struct {
+	Ops                  *Ops             `protobuf:"bytes,1,opt,name=ops,proto3" json:"ops,omitempty"`
+	Expected             []*ExpectedIndex `protobuf:"bytes,2,rep,name=expected,proto3" json:"expected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CompareAndExecAllTxOptions) Reset()         { *m = CompareAndExecAllTxOptions{} }
+func (m *CompareAndExecAllTxOptions) String() string { return proto.CompactTextString(m) }
+func (*CompareAndExecAllTxOptions) ProtoMessage()    {}
+func (*CompareAndExecAllTxOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{85}
+}
+
+func (m *CompareAndExecAllTxOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndExecAllTxOptions.Unmarshal(m, b)
+}
+func (m *CompareAndExecAllTxOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndExecAllTxOptions.Marshal(b, m, deterministic)
+}
+func (m *CompareAndExecAllTxOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndExecAllTxOptions.Merge(m, src)
+}
+func (m *CompareAndExecAllTxOptions) XXX_Size() int {
+	return xxx_messageInfo_CompareAndExecAllTxOptions.Size(m)
+}
+func (m *CompareAndExecAllTxOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndExecAllTxOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndExecAllTxOptions proto.InternalMessageInfo
+
+func (m *CompareAndExecAllTxOptions) GetOps() *Ops {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+func (m *CompareAndExecAllTxOptions) GetExpected() []*ExpectedIndex {
+	if m != nil {
+		return m.Expected
+	}
+	return nil
+}
+
+type ReferencesOptions struct {
+	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Index                *Index   `protobuf:"bytes,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReferencesOptions) Reset()         { *m = ReferencesOptions{} }
+func (m *ReferencesOptions) String() string { return proto.CompactTextString(m) }
+func (*ReferencesOptions) ProtoMessage()    {}
+func (*ReferencesOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{86}
+}
+
+func (m *ReferencesOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReferencesOptions.Unmarshal(m, b)
+}
+func (m *ReferencesOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReferencesOptions.Marshal(b, m, deterministic)
+}
+func (m *ReferencesOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReferencesOptions.Merge(m, src)
+}
+func (m *ReferencesOptions) XXX_Size() int {
+	return xxx_messageInfo_ReferencesOptions.Size(m)
+}
+func (m *ReferencesOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReferencesOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReferencesOptions proto.InternalMessageInfo
+
+func (m *ReferencesOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *ReferencesOptions) GetIndex() *Index {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+// ReferenceItem is a reference resolving to a key, index is the one of the entry of the reference itself
+type ReferenceItem struct {
+	Reference            *ReferenceOptions `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	Index                uint64            `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ReferenceItem) Reset()         { *m = ReferenceItem{} }
+func (m *ReferenceItem) String() string { return proto.CompactTextString(m) }
+func (*ReferenceItem) ProtoMessage()    {}
+func (*ReferenceItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{87}
+}
+
+func (m *ReferenceItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReferenceItem.Unmarshal(m, b)
+}
+func (m *ReferenceItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReferenceItem.Marshal(b, m, deterministic)
+}
+func (m *ReferenceItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReferenceItem.Merge(m, src)
+}
+func (m *ReferenceItem) XXX_Size() int {
+	return xxx_messageInfo_ReferenceItem.Size(m)
+}
+func (m *ReferenceItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReferenceItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReferenceItem proto.InternalMessageInfo
+
+func (m *ReferenceItem) GetReference() *ReferenceOptions {
+	if m != nil {
+		return m.Reference
+	}
+	return nil
+}
+
+func (m *ReferenceItem) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type ReferenceList struct {
+	Items                []*ReferenceItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ReferenceList) Reset()         { *m = ReferenceList{} }
+func (m *ReferenceList) String() string { return proto.CompactTextString(m) }
+func (*ReferenceList) ProtoMessage()    {}
+func (*ReferenceList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{88}
+}
+
+func (m *ReferenceList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReferenceList.Unmarshal(m, b)
+}
+func (m *ReferenceList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReferenceList.Marshal(b, m, deterministic)
+}
+func (m *ReferenceList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReferenceList.Merge(m, src)
+}
+func (m *ReferenceList) XXX_Size() int {
+	return xxx_messageInfo_ReferenceList.Size(m)
+}
+func (m *ReferenceList) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReferenceList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReferenceList proto.InternalMessageInfo
+
+func (m *ReferenceList) GetItems() []*ReferenceItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+// PrefixStats are the statistics of the keys having a prefix: the number of live keys, the ones neither deleted nor
+// expired, and the number of entries of every version of the keys, with the size in bytes of their keys and values
+type PrefixStats struct {
+	Prefix               []byte   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	LiveKeys             uint64   `protobuf:"varint,2,opt,name=liveKeys,proto3" json:"liveKeys,omitempty"`
+	Entries              uint64   `protobuf:"varint,3,opt,name=entries,proto3" json:"entries,omitempty"`
+	LiveSize             uint64   `protobuf:"varint,4,opt,name=liveSize,proto3" json:"liveSize,omitempty"`
+	EntriesSize          uint64   `protobuf:"varint,5,opt,name=entriesSize,proto3" json:"entriesSize,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PrefixStats) Reset()         { *m = PrefixStats{} }
+func (m *PrefixStats) String() string { return proto.CompactTextString(m) }
+func (*PrefixStats) ProtoMessage()    {}
+func (*PrefixStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{89}
+}
+
+func (m *PrefixStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrefixStats.Unmarshal(m, b)
+}
+func (m *PrefixStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrefixStats.Marshal(b, m, deterministic)
+}
+func (m *PrefixStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrefixStats.Merge(m, src)
+}
+func (m *PrefixStats) XXX_Size() int {
+	return xxx_messageInfo_PrefixStats.Size(m)
+}
+func (m *PrefixStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrefixStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrefixStats proto.InternalMessageInfo
+
+func (m *PrefixStats) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+func (m *PrefixStats) GetLiveKeys() uint64 {
+	if m != nil {
+		return m.LiveKeys
+	}
+	return 0
+}
+
+func (m *PrefixStats) GetEntries() uint64 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
+}
+
+func (m *PrefixStats) GetLiveSize() uint64 {
+	if m != nil {
+		return m.LiveSize
+	}
+	return 0
+}
+
+func (m *PrefixStats) GetEntriesSize() uint64 {
+	if m != nil {
+		return m.EntriesSize
+	}
+	return 0
+}
+
+type SafeGetBatchOptions struct {
+	Keys      []*Key `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	RootIndex *Index `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	// multiProof asks for a single multiproof covering all the items instead of an inclusion proof per item
+	MultiProof           bool     `protobuf:"varint,3,opt,name=multiProof,proto3" json:"multiProof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SafeGetBatchOptions) Reset()         { *m = SafeGetBatchOptions{} }
+func (m *SafeGetBatchOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeGetBatchOptions) ProtoMessage()    {}
+func (*SafeGetBatchOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{90}
+}
+
+func (m *SafeGetBatchOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeGetBatchOptions.Unmarshal(m, b)
+}
+func (m *SafeGetBatchOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeGetBatchOptions.Marshal(b, m, deterministic)
+}
+func (m *SafeGetBatchOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeGetBatchOptions.Merge(m, src)
+}
+func (m *SafeGetBatchOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeGetBatchOptions.Size(m)
+}
+func (m *SafeGetBatchOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeGetBatchOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeGetBatchOptions proto.InternalMessageInfo
+
+func (m *SafeGetBatchOptions) GetKeys() []*Key {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *SafeGetBatchOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+func (m *SafeGetBatchOptions) GetMultiProof() bool {
+	if m != nil {
+		return m.MultiProof
+	}
+	return false
+}
+
+type SafeItemList struct {
+	Items []*SafeItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// multiProof proves the inclusion of all the items, if requested, in which case the items carry no proof
+	MultiProof           *MultiProof `protobuf:"bytes,2,opt,name=multiProof,proto3" json:"multiProof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SafeItemList) Reset()         { *m = SafeItemList{} }
+func (m *SafeItemList) String() string { return proto.CompactTextString(m) }
+func (*SafeItemList) ProtoMessage()    {}
+func (*SafeItemList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{91}
+}
+
+func (m *SafeItemList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeItemList.Unmarshal(m, b)
+}
+func (m *SafeItemList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeItemList.Marshal(b, m, deterministic)
+}
+func (m *SafeItemList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeItemList.Merge(m, src)
+}
+func (m *SafeItemList) XXX_Size() int {
+	return xxx_messageInfo_SafeItemList.Size(m)
+}
+func (m *SafeItemList) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeItemList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeItemList proto.InternalMessageInfo
+
+func (m *SafeItemList) GetItems() []*SafeItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *SafeItemList) GetMultiProof() *MultiProof {
+	if m != nil {
+		return m.MultiProof
+	}
+	return nil
+}
+
+// MultiProof proves that many leaves are included in the same root, sharing the nodes their inclusion paths have in common
+type MultiProof struct {
+	At   uint64 `protobuf:"varint,1,opt,name=at,proto3" json:"at,omitempty"`
+	Root []byte `protobuf:"bytes,2,opt,name=root,proto3" json:"root,omitempty"`
+	// indexes of the proved leaves, in ascending order
+	Indexes []uint64 `protobuf:"varint,3,rep,packed,name=indexes,proto3" json:"indexes,omitempty"`
+	// leaves at the proved indexes
+	Leaves [][]byte `protobuf:"bytes,4,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	// roots of the subtrees holding no proved leaf, in left to right order
+	Nodes                [][]byte `protobuf:"bytes,5,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	ConsistencyPath      [][]byte `protobuf:"bytes,6,rep,name=consistencyPath,proto3" json:"consistencyPath,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MultiProof) Reset()         { *m = MultiProof{} }
+func (m *MultiProof) String() string { return proto.CompactTextString(m) }
+func (*MultiProof) ProtoMessage()    {}
+func (*MultiProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{92}
+}
+
+func (m *MultiProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiProof.Unmarshal(m, b)
+}
+func (m *MultiProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiProof.Marshal(b, m, deterministic)
+}
+func (m *MultiProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiProof.Merge(m, src)
+}
+func (m *MultiProof) XXX_Size() int {
+	return xxx_messageInfo_MultiProof.Size(m)
+}
+func (m *MultiProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiProof proto.InternalMessageInfo
+
+func (m *MultiProof) GetAt() uint64 {
+	if m != nil {
+		return m.At
+	}
+	return 0
+}
+
+func (m *MultiProof) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+func (m *MultiProof) GetIndexes() []uint64 {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+func (m *MultiProof) GetLeaves() [][]byte {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *MultiProof) GetNodes() [][]byte {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *MultiProof) GetConsistencyPath() [][]byte {
+	if m != nil {
+		return m.ConsistencyPath
+	}
+	return nil
+}
+
+// SafeZScanOptions asks for a page of a sorted set along with the proofs of its entries
+type SafeZScanOptions struct {
+	Zopts                *ZScanOptions `protobuf:"bytes,1,opt,name=zopts,proto3" json:"zopts,omitempty"`
+	RootIndex            *Index        `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *SafeZScanOptions) Reset()         { *m = SafeZScanOptions{} }
+func (m *SafeZScanOptions) String() string { return proto.CompactTextString(m) }
+func (*SafeZScanOptions) ProtoMessage()    {}
+func (*SafeZScanOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{93}
+}
+
+func (m *SafeZScanOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeZScanOptions.Unmarshal(m, b)
+}
+func (m *SafeZScanOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeZScanOptions.Marshal(b, m, deterministic)
+}
+func (m *SafeZScanOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeZScanOptions.Merge(m, src)
+}
+func (m *SafeZScanOptions) XXX_Size() int {
+	return xxx_messageInfo_SafeZScanOptions.Size(m)
+}
+func (m *SafeZScanOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeZScanOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeZScanOptions proto.InternalMessageInfo
+
+func (m *SafeZScanOptions) GetZopts() *ZScanOptions {
+	if m != nil {
+		return m.Zopts
+	}
+	return nil
+}
+
+func (m *SafeZScanOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+// SafeZItemList is a page of a sorted set along with a multiproof of the inclusion of both its entries and the items they reference
+type SafeZItemList struct {
+	Items                []*ZItem    `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	MultiProof           *MultiProof `protobuf:"bytes,2,opt,name=multiProof,proto3" json:"multiProof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SafeZItemList) Reset()         { *m = SafeZItemList{} }
+func (m *SafeZItemList) String() string { return proto.CompactTextString(m) }
+func (*SafeZItemList) ProtoMessage()    {}
+func (*SafeZItemList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{94}
+}
+
+func (m *SafeZItemList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SafeZItemList.Unmarshal(m, b)
+}
+func (m *SafeZItemList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SafeZItemList.Marshal(b, m, deterministic)
+}
+func (m *SafeZItemList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SafeZItemList.Merge(m, src)
+}
+func (m *SafeZItemList) XXX_Size() int {
+	return xxx_messageInfo_SafeZItemList.Size(m)
+}
+func (m *SafeZItemList) XXX_DiscardUnknown() {
+	xxx_messageInfo_SafeZItemList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SafeZItemList proto.InternalMessageInfo
+
+func (m *SafeZItemList) GetItems() []*ZItem {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *SafeZItemList) GetMultiProof() *MultiProof {
+	if m != nil {
+		return m.MultiProof
+	}
+	return nil
 }
 
-func (m *ChangePermissionRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ChangePermissionRequest.Unmarshal(m, b)
+// SubscribeRequest selects the keys whose changes are streamed by Subscribe: either a single key or the keys starting
+// // with prefix, every key if both are empty
+type SubscribeRequest struct {
+	Key    []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Prefix []byte `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// proof asks for the inclusion proof of every change
+	Proof                bool     `protobuf:"varint,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *ChangePermissionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ChangePermissionRequest.Marshal(b, m, deterministic)
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{95}
 }
-func (m *ChangePermissionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ChangePermissionRequest.Merge(m, src)
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
 }
-func (m *ChangePermissionRequest) XXX_Size() int {
-	return xxx_messageInfo_ChangePermissionRequest.Size(m)
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
 }
-func (m *ChangePermissionRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ChangePermissionRequest.DiscardUnknown(m)
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ChangePermissionRequest proto.InternalMessageInfo
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
 
-func (m *ChangePermissionRequest) GetAction() PermissionAction {
+func (m *SubscribeRequest) GetKey() []byte {
 	if m != nil {
-		return m.Action
+		return m.Key
 	}
-	return PermissionAction_GRANT
+	return nil
 }
 
-func (m *ChangePermissionRequest) GetUsername() string {
+func (m *SubscribeRequest) GetPrefix() []byte {
 	if m != nil {
-		return m.Username
+		return m.Prefix
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetProof() bool {
+	if m != nil {
+		return m.Proof
+	}
+	return false
+}
+
+// KeyChange is a change of a key streamed by Subscribe: the item written, flagged as deleted for deletions, along
+// // with its inclusion proof if requested
+type KeyChange struct {
+	Item                 *Item           `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Proof                *InclusionProof `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *KeyChange) Reset()         { *m = KeyChange{} }
+func (m *KeyChange) String() string { return proto.CompactTextString(m) }
+func (*KeyChange) ProtoMessage()    {}
+func (*KeyChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{96}
+}
+
+func (m *KeyChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyChange.Unmarshal(m, b)
+}
+func (m *KeyChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyChange.Marshal(b, m, deterministic)
+}
+func (m *KeyChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyChange.Merge(m, src)
+}
+func (m *KeyChange) XXX_Size() int {
+	return xxx_messageInfo_KeyChange.Size(m)
+}
+func (m *KeyChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyChange proto.InternalMessageInfo
+
+func (m *KeyChange) GetItem() *Item {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+func (m *KeyChange) GetProof() *InclusionProof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+type GetAtOptions struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// index is the position of the tree the key is read at
+	Index                uint64   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	RootIndex            *Index   `protobuf:"bytes,3,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAtOptions) Reset()         { *m = GetAtOptions{} }
+func (m *GetAtOptions) String() string { return proto.CompactTextString(m) }
+func (*GetAtOptions) ProtoMessage()    {}
+func (*GetAtOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{97}
+}
+
+func (m *GetAtOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAtOptions.Unmarshal(m, b)
+}
+func (m *GetAtOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAtOptions.Marshal(b, m, deterministic)
+}
+func (m *GetAtOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAtOptions.Merge(m, src)
+}
+func (m *GetAtOptions) XXX_Size() int {
+	return xxx_messageInfo_GetAtOptions.Size(m)
+}
+func (m *GetAtOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAtOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAtOptions proto.InternalMessageInfo
+
+func (m *GetAtOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *GetAtOptions) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *GetAtOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+type GetAsOfOptions struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// timestamp is the point in time, in unix nanoseconds, the key is read at
+	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	RootIndex            *Index   `protobuf:"bytes,3,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAsOfOptions) Reset()         { *m = GetAsOfOptions{} }
+func (m *GetAsOfOptions) String() string { return proto.CompactTextString(m) }
+func (*GetAsOfOptions) ProtoMessage()    {}
+func (*GetAsOfOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{98}
+}
+
+func (m *GetAsOfOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAsOfOptions.Unmarshal(m, b)
+}
+func (m *GetAsOfOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAsOfOptions.Marshal(b, m, deterministic)
+}
+func (m *GetAsOfOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAsOfOptions.Merge(m, src)
+}
+func (m *GetAsOfOptions) XXX_Size() int {
+	return xxx_messageInfo_GetAsOfOptions.Size(m)
+}
+func (m *GetAsOfOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAsOfOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAsOfOptions proto.InternalMessageInfo
+
+func (m *GetAsOfOptions) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *GetAsOfOptions) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *GetAsOfOptions) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
+}
+
+// HistoricalItem is the entry a key had at a past position of the tree, along with its inclusion proof against the
+// // root the tree had at that position, and the consistency proof linking that root with the requested one, if any
+type HistoricalItem struct {
+	Item                 *Item             `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Proof                *InclusionProof   `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	ConsistencyProof     *ConsistencyProof `protobuf:"bytes,3,opt,name=consistencyProof,proto3" json:"consistencyProof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *HistoricalItem) Reset()         { *m = HistoricalItem{} }
+func (m *HistoricalItem) String() string { return proto.CompactTextString(m) }
+func (*HistoricalItem) ProtoMessage()    {}
+func (*HistoricalItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{99}
+}
+
+func (m *HistoricalItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoricalItem.Unmarshal(m, b)
+}
+func (m *HistoricalItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoricalItem.Marshal(b, m, deterministic)
+}
+func (m *HistoricalItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoricalItem.Merge(m, src)
+}
+func (m *HistoricalItem) XXX_Size() int {
+	return xxx_messageInfo_HistoricalItem.Size(m)
+}
+func (m *HistoricalItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoricalItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoricalItem proto.InternalMessageInfo
+
+func (m *HistoricalItem) GetItem() *Item {
+	if m != nil {
+		return m.Item
+	}
+	return nil
+}
+
+func (m *HistoricalItem) GetProof() *InclusionProof {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *HistoricalItem) GetConsistencyProof() *ConsistencyProof {
+	if m != nil {
+		return m.ConsistencyProof
+	}
+	return nil
+}
+
+// BackupInfo describes a consistent snapshot of a database, taken without stopping writes, and the root the tree
+// // had when it was taken
+type BackupInfo struct {
+	DatabaseName string `protobuf:"bytes,1,opt,name=databaseName,proto3" json:"databaseName,omitempty"`
+	// file is the path of the snapshot on the server
+	File string `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Root *Root  `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`
+	// timestamp is the time, in unix nanoseconds, the snapshot was taken at
+	Timestamp            int64    `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Size                 uint64   `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupInfo) Reset()         { *m = BackupInfo{} }
+func (m *BackupInfo) String() string { return proto.CompactTextString(m) }
+func (*BackupInfo) ProtoMessage()    {}
+func (*BackupInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{100}
+}
+
+func (m *BackupInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupInfo.Unmarshal(m, b)
+}
+func (m *BackupInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupInfo.Marshal(b, m, deterministic)
+}
+func (m *BackupInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupInfo.Merge(m, src)
+}
+func (m *BackupInfo) XXX_Size() int {
+	return xxx_messageInfo_BackupInfo.Size(m)
+}
+func (m *BackupInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupInfo proto.InternalMessageInfo
+
+func (m *BackupInfo) GetDatabaseName() string {
+	if m != nil {
+		return m.DatabaseName
 	}
 	return ""
 }
 
-func (m *ChangePermissionRequest) GetDatabase() string {
+func (m *BackupInfo) GetFile() string {
 	if m != nil {
-		return m.Database
+		return m.File
 	}
 	return ""
 }
 
-func (m *ChangePermissionRequest) GetPermission() uint32 {
+func (m *BackupInfo) GetRoot() *Root {
 	if m != nil {
-		return m.Permission
+		return m.Root
+	}
+	return nil
+}
+
+func (m *BackupInfo) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
 	}
 	return 0
 }
 
-type SetActiveUserRequest struct {
-	Active               bool     `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
-	Username             string   `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+func (m *BackupInfo) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+// RestoreBackupRequest selects the snapshot, written by Backup, a new database is rebuilt from
+type RestoreBackupRequest struct {
+	// file is the path of the snapshot on the server, within its backup directory
+	File                 string   `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	DatabaseName         string   `protobuf:"bytes,2,opt,name=databaseName,proto3" json:"databaseName,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetActiveUserRequest) Reset()         { *m = SetActiveUserRequest{} }
-func (m *SetActiveUserRequest) String() string { return proto.CompactTextString(m) }
-func (*SetActiveUserRequest) ProtoMessage()    {}
-func (*SetActiveUserRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{60}
+func (m *RestoreBackupRequest) Reset()         { *m = RestoreBackupRequest{} }
+func (m *RestoreBackupRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreBackupRequest) ProtoMessage()    {}
+func (*RestoreBackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{101}
 }
 
-func (m *SetActiveUserRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetActiveUserRequest.Unmarshal(m, b)
+func (m *RestoreBackupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreBackupRequest.Unmarshal(m, b)
 }
-func (m *SetActiveUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetActiveUserRequest.Marshal(b, m, deterministic)
+func (m *RestoreBackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreBackupRequest.Marshal(b, m, deterministic)
 }
-func (m *SetActiveUserRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetActiveUserRequest.Merge(m, src)
+func (m *RestoreBackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreBackupRequest.Merge(m, src)
 }
-func (m *SetActiveUserRequest) XXX_Size() int {
-	return xxx_messageInfo_SetActiveUserRequest.Size(m)
+func (m *RestoreBackupRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreBackupRequest.Size(m)
 }
-func (m *SetActiveUserRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetActiveUserRequest.DiscardUnknown(m)
+func (m *RestoreBackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreBackupRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetActiveUserRequest proto.InternalMessageInfo
+var xxx_messageInfo_RestoreBackupRequest proto.InternalMessageInfo
 
-func (m *SetActiveUserRequest) GetActive() bool {
+func (m *RestoreBackupRequest) GetFile() string {
 	if m != nil {
-		return m.Active
+		return m.File
 	}
-	return false
+	return ""
 }
 
-func (m *SetActiveUserRequest) GetUsername() string {
+func (m *RestoreBackupRequest) GetDatabaseName() string {
 	if m != nil {
-		return m.Username
+		return m.DatabaseName
 	}
 	return ""
 }
 
-type DatabaseListResponse struct {
-	Databases            []*Database `protobuf:"bytes,1,rep,name=databases,proto3" json:"databases,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+// TailRequest selects the entries streamed by Tail: every entry from index from on, in index order, proven against
+// the root at rootIndex
+type TailRequest struct {
+	From                 uint64   `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	RootIndex            *Index   `protobuf:"bytes,2,opt,name=rootIndex,proto3" json:"rootIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailRequest) Reset()         { *m = TailRequest{} }
+func (m *TailRequest) String() string { return proto.CompactTextString(m) }
+func (*TailRequest) ProtoMessage()    {}
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{102}
+}
+
+func (m *TailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailRequest.Unmarshal(m, b)
+}
+func (m *TailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailRequest.Marshal(b, m, deterministic)
+}
+func (m *TailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailRequest.Merge(m, src)
+}
+func (m *TailRequest) XXX_Size() int {
+	return xxx_messageInfo_TailRequest.Size(m)
+}
+func (m *TailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailRequest proto.InternalMessageInfo
+
+func (m *TailRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *TailRequest) GetRootIndex() *Index {
+	if m != nil {
+		return m.RootIndex
+	}
+	return nil
 }
 
-func (m *DatabaseListResponse) Reset()         { *m = DatabaseListResponse{} }
-func (m *DatabaseListResponse) String() string { return proto.CompactTextString(m) }
-func (*DatabaseListResponse) ProtoMessage()    {}
-func (*DatabaseListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_1c5fb4d8cc22d66a, []int{61}
+// TailItem is an entry streamed by Tail, with the proof of its inclusion in the current root and the consistency of
+// that root with the root of the previous item, or with the requested root for the first one. The value of a pruned
+// entry is gone: its item holds the key and the index only, and its leaf is the one of the proof.
+type TailItem struct {
+	Item                 *Item    `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Proof                *Proof   `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	Pruned               bool     `protobuf:"varint,3,opt,name=pruned,proto3" json:"pruned,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DatabaseListResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DatabaseListResponse.Unmarshal(m, b)
+func (m *TailItem) Reset()         { *m = TailItem{} }
+func (m *TailItem) String() string { return proto.CompactTextString(m) }
+func (*TailItem) ProtoMessage()    {}
+func (*TailItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1c5fb4d8cc22d66a, []int{103}
 }
-func (m *DatabaseListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DatabaseListResponse.Marshal(b, m, deterministic)
+
+func (m *TailItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailItem.Unmarshal(m, b)
 }
-func (m *DatabaseListResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DatabaseListResponse.Merge(m, src)
+func (m *TailItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailItem.Marshal(b, m, deterministic)
 }
-func (m *DatabaseListResponse) XXX_Size() int {
-	return xxx_messageInfo_DatabaseListResponse.Size(m)
+func (m *TailItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailItem.Merge(m, src)
 }
-func (m *DatabaseListResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_DatabaseListResponse.DiscardUnknown(m)
+func (m *TailItem) XXX_Size() int {
+	return xxx_messageInfo_TailItem.Size(m)
+}
+func (m *TailItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailItem.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DatabaseListResponse proto.InternalMessageInfo
+var xxx_messageInfo_TailItem proto.InternalMessageInfo
 
-func (m *DatabaseListResponse) GetDatabases() []*Database {
+func (m *TailItem) GetItem() *Item {
 	if m != nil {
-		return m.Databases
+		return m.Item
+	}
+	return nil
+}
+
+func (m *TailItem) GetProof() *Proof {
+	if m != nil {
+		return m.Proof
 	}
 	return nil
 }
 
+func (m *TailItem) GetPruned() bool {
+	if m != nil {
+		return m.Pruned
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterEnum("immudb.schema.PermissionAction", PermissionAction_name, PermissionAction_value)
 	proto.RegisterType((*Key)(nil), "immudb.schema.Key")
 	proto.RegisterType((*Permission)(nil), "immudb.schema.Permission")
 	proto.RegisterType((*User)(nil), "immudb.schema.User")
 	proto.RegisterType((*UserList)(nil), "immudb.schema.UserList")
+	proto.RegisterType((*Session)(nil), "immudb.schema.Session")
+	proto.RegisterType((*SessionList)(nil), "immudb.schema.SessionList")
+	proto.RegisterType((*UsageRequest)(nil), "immudb.schema.UsageRequest")
+	proto.RegisterType((*Usage)(nil), "immudb.schema.Usage")
+	proto.RegisterType((*UsageReport)(nil), "immudb.schema.UsageReport")
+	proto.RegisterType((*AccessControlState)(nil), "immudb.schema.AccessControlState")
 	proto.RegisterType((*CreateUserRequest)(nil), "immudb.schema.CreateUserRequest")
 	proto.RegisterType((*UserRequest)(nil), "immudb.schema.UserRequest")
 	proto.RegisterType((*ChangePasswordRequest)(nil), "immudb.schema.ChangePasswordRequest")
@@ -3193,6 +5785,8 @@ func init() {
 	proto.RegisterType((*StructuredKeyValue)(nil), "immudb.schema.StructuredKeyValue")
 	proto.RegisterType((*Content)(nil), "immudb.schema.Content")
 	proto.RegisterType((*Index)(nil), "immudb.schema.Index")
+	proto.RegisterType((*Sequence)(nil), "immudb.schema.Sequence")
+	proto.RegisterType((*SequencedWrite)(nil), "immudb.schema.SequencedWrite")
 	proto.RegisterType((*Item)(nil), "immudb.schema.Item")
 	proto.RegisterType((*StructuredItem)(nil), "immudb.schema.StructuredItem")
 	proto.RegisterType((*KVList)(nil), "immudb.schema.KVList")
@@ -3213,14 +5807,26 @@ func init() {
 	proto.RegisterType((*InclusionProof)(nil), "immudb.schema.InclusionProof")
 	proto.RegisterType((*ConsistencyProof)(nil), "immudb.schema.ConsistencyProof")
 	proto.RegisterType((*Proof)(nil), "immudb.schema.Proof")
+	proto.RegisterType((*KeyHistoryEntry)(nil), "immudb.schema.KeyHistoryEntry")
+	proto.RegisterType((*KeyHistoryDump)(nil), "immudb.schema.KeyHistoryDump")
+	proto.RegisterType((*SampleOptions)(nil), "immudb.schema.SampleOptions")
+	proto.RegisterType((*KeySample)(nil), "immudb.schema.KeySample")
+	proto.RegisterType((*StorageLevel)(nil), "immudb.schema.StorageLevel")
+	proto.RegisterType((*StorageReport)(nil), "immudb.schema.StorageReport")
+	proto.RegisterType((*VerificationBundle)(nil), "immudb.schema.VerificationBundle")
+	proto.RegisterType((*LogRequest)(nil), "immudb.schema.LogRequest")
+	proto.RegisterType((*LogEntry)(nil), "immudb.schema.LogEntry")
 	proto.RegisterType((*SafeItem)(nil), "immudb.schema.SafeItem")
 	proto.RegisterType((*SafeStructuredItem)(nil), "immudb.schema.SafeStructuredItem")
 	proto.RegisterType((*SafeSetOptions)(nil), "immudb.schema.SafeSetOptions")
 	proto.RegisterType((*SafeSetSVOptions)(nil), "immudb.schema.SafeSetSVOptions")
 	proto.RegisterType((*SafeGetOptions)(nil), "immudb.schema.SafeGetOptions")
+	proto.RegisterType((*ValueHashOptions)(nil), "immudb.schema.ValueHashOptions")
+	proto.RegisterType((*ValueHashVerification)(nil), "immudb.schema.ValueHashVerification")
 	proto.RegisterType((*SafeReferenceOptions)(nil), "immudb.schema.SafeReferenceOptions")
 	proto.RegisterType((*HealthResponse)(nil), "immudb.schema.HealthResponse")
 	proto.RegisterType((*ReferenceOptions)(nil), "immudb.schema.ReferenceOptions")
+	proto.RegisterType((*CompareAndReferenceOptions)(nil), "immudb.schema.CompareAndReferenceOptions")
 	proto.RegisterType((*ZAddOptions)(nil), "immudb.schema.ZAddOptions")
 	proto.RegisterType((*ZScanOptions)(nil), "immudb.schema.ZScanOptions")
 	proto.RegisterType((*Score)(nil), "immudb.schema.Score")
@@ -3235,201 +5841,356 @@ func init() {
 	proto.RegisterType((*ChangePermissionRequest)(nil), "immudb.schema.ChangePermissionRequest")
 	proto.RegisterType((*SetActiveUserRequest)(nil), "immudb.schema.SetActiveUserRequest")
 	proto.RegisterType((*DatabaseListResponse)(nil), "immudb.schema.DatabaseListResponse")
+	proto.RegisterType((*SafeExecAllTxOptions)(nil), "immudb.schema.SafeExecAllTxOptions")
+	proto.RegisterType((*TxProof)(nil), "immudb.schema.TxProof")
+	proto.RegisterType((*ExpectedIndex)(nil), "immudb.schema.ExpectedIndex")
+	proto.RegisterType((*CompareAndExecAllTxOptions)(nil), "immudb.schema.CompareAndExecAllTxOptions")
+	proto.RegisterType((*ReferencesOptions)(nil), "immudb.schema.ReferencesOptions")
+	proto.RegisterType((*ReferenceItem)(nil), "immudb.schema.ReferenceItem")
+	proto.RegisterType((*ReferenceList)(nil), "immudb.schema.ReferenceList")
+	proto.RegisterType((*PrefixStats)(nil), "immudb.schema.PrefixStats")
+	proto.RegisterType((*SafeGetBatchOptions)(nil), "immudb.schema.SafeGetBatchOptions")
+	proto.RegisterType((*SafeItemList)(nil), "immudb.schema.SafeItemList")
+	proto.RegisterType((*MultiProof)(nil), "immudb.schema.MultiProof")
+	proto.RegisterType((*SafeZScanOptions)(nil), "immudb.schema.SafeZScanOptions")
+	proto.RegisterType((*SafeZItemList)(nil), "immudb.schema.SafeZItemList")
+	proto.RegisterType((*SubscribeRequest)(nil), "immudb.schema.SubscribeRequest")
+	proto.RegisterType((*KeyChange)(nil), "immudb.schema.KeyChange")
+	proto.RegisterType((*GetAtOptions)(nil), "immudb.schema.GetAtOptions")
+	proto.RegisterType((*GetAsOfOptions)(nil), "immudb.schema.GetAsOfOptions")
+	proto.RegisterType((*HistoricalItem)(nil), "immudb.schema.HistoricalItem")
+	proto.RegisterType((*BackupInfo)(nil), "immudb.schema.BackupInfo")
+	proto.RegisterType((*RestoreBackupRequest)(nil), "immudb.schema.RestoreBackupRequest")
+	proto.RegisterType((*TailRequest)(nil), "immudb.schema.TailRequest")
+	proto.RegisterType((*TailItem)(nil), "immudb.schema.TailItem")
 }
 
 func init() { proto.RegisterFile("schema.proto", fileDescriptor_1c5fb4d8cc22d66a) }
 
 var fileDescriptor_1c5fb4d8cc22d66a = []byte{
-	// 3016 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0x4b, 0x73, 0x1b, 0xc7,
-	0xf1, 0xe7, 0xe2, 0x41, 0x02, 0x0d, 0x92, 0xa2, 0xc7, 0xb2, 0x04, 0x43, 0x0f, 0x42, 0x43, 0x9a,
-	0xa2, 0x28, 0x8a, 0xb0, 0x28, 0xcb, 0x76, 0xe9, 0xcf, 0xe2, 0x3f, 0x24, 0xad, 0xa2, 0x68, 0x4a,
-	0x22, 0x6b, 0x41, 0xcb, 0x15, 0x26, 0x2e, 0xd7, 0x62, 0x31, 0x00, 0xd6, 0x04, 0x76, 0x37, 0xbb,
-	0xb3, 0x24, 0x21, 0x95, 0x2a, 0x65, 0xdf, 0x52, 0xb9, 0x39, 0x55, 0xf9, 0x06, 0xb9, 0x24, 0x5f,
-	0x20, 0xdf, 0x23, 0x37, 0x9f, 0x73, 0xce, 0x67, 0x48, 0xcd, 0x63, 0x1f, 0xd8, 0x07, 0x28, 0x31,
-	0xc9, 0x89, 0xdb, 0x33, 0x3d, 0xfd, 0xeb, 0xee, 0x99, 0xe9, 0xe9, 0x6e, 0x02, 0xa6, 0x5d, 0xbd,
-	0x47, 0x06, 0xda, 0x9a, 0xed, 0x58, 0xd4, 0x42, 0x33, 0xc6, 0x60, 0xe0, 0xb5, 0x5b, 0x6b, 0x62,
-	0xb0, 0x76, 0xb3, 0x6b, 0x59, 0xdd, 0x3e, 0x69, 0x68, 0xb6, 0xd1, 0xd0, 0x4c, 0xd3, 0xa2, 0x1a,
-	0x35, 0x2c, 0xd3, 0x15, 0xcc, 0xb5, 0x1b, 0x72, 0x96, 0x53, 0x2d, 0xaf, 0xd3, 0x20, 0x03, 0x9b,
-	0x0e, 0xe5, 0xe4, 0x2a, 0xff, 0xa3, 0x3f, 0xe8, 0x12, 0xf3, 0x81, 0x7b, 0xa6, 0x75, 0xbb, 0xc4,
-	0x69, 0x58, 0x36, 0x5f, 0x9e, 0x22, 0xaa, 0x62, 0xb7, 0x1a, 0x76, 0x4b, 0x10, 0xf8, 0x3a, 0xe4,
-	0xf7, 0xc9, 0x10, 0xcd, 0x41, 0xfe, 0x84, 0x0c, 0xab, 0x4a, 0x5d, 0x59, 0x9e, 0x56, 0xd9, 0x27,
-	0x7e, 0x06, 0x70, 0x48, 0x9c, 0x81, 0xe1, 0xba, 0x86, 0x65, 0xa2, 0x1a, 0x94, 0xda, 0x1a, 0xd5,
-	0x5a, 0x9a, 0x4b, 0x38, 0x53, 0x59, 0x0d, 0x68, 0x74, 0x1b, 0xc0, 0x0e, 0x38, 0xab, 0xb9, 0xba,
-	0xb2, 0x3c, 0xa3, 0x46, 0x46, 0xf0, 0xdf, 0x14, 0x28, 0x7c, 0xe3, 0x12, 0x07, 0x21, 0x28, 0x78,
-	0x2e, 0x71, 0x24, 0x0a, 0xff, 0x46, 0xff, 0x07, 0x95, 0x90, 0xd5, 0xad, 0xe6, 0xeb, 0xf9, 0xe5,
-	0xca, 0xfa, 0xc7, 0x6b, 0x23, 0xae, 0x59, 0x0b, 0x15, 0x51, 0xa3, 0xdc, 0xe8, 0x26, 0x94, 0x75,
-	0x87, 0x68, 0x94, 0xb4, 0x5b, 0xc3, 0x6a, 0x81, 0xab, 0x15, 0x0e, 0x44, 0x66, 0x35, 0x5a, 0x2d,
-	0x8e, 0xcc, 0x6a, 0x14, 0x5d, 0x83, 0x49, 0x4d, 0xa7, 0xc6, 0x29, 0xa9, 0x4e, 0xd6, 0x95, 0xe5,
-	0x92, 0x2a, 0x29, 0xfc, 0x18, 0x4a, 0x4c, 0xd9, 0xe7, 0x86, 0x4b, 0xd1, 0x3d, 0x28, 0x32, 0x25,
-	0xdd, 0xaa, 0xc2, 0xd5, 0xfa, 0x30, 0xa6, 0x16, 0xe3, 0x53, 0x05, 0x07, 0xfe, 0x3d, 0x7c, 0xb0,
-	0xc3, 0x65, 0xf3, 0x41, 0xf2, 0x3b, 0x8f, 0xb8, 0x34, 0xd5, 0xe0, 0x1a, 0x94, 0x6c, 0xcd, 0x75,
-	0xcf, 0x2c, 0xa7, 0xcd, 0x7d, 0x35, 0xad, 0x06, 0x74, 0xcc, 0x93, 0xf9, 0xb8, 0x27, 0x47, 0x76,
-	0xa1, 0x30, 0xba, 0x0b, 0xf8, 0x0e, 0x54, 0x2e, 0x80, 0xc6, 0x16, 0x7c, 0xb4, 0xd3, 0xd3, 0xcc,
-	0x2e, 0x39, 0x94, 0x80, 0xe3, 0xf4, 0xac, 0x43, 0xc5, 0xea, 0xb7, 0x0f, 0x47, 0x55, 0x8d, 0x0e,
-	0x31, 0x0e, 0x93, 0x9c, 0x05, 0x1c, 0x79, 0xc1, 0x11, 0x19, 0xc2, 0x9b, 0x30, 0xfd, 0xdc, 0xea,
-	0x1a, 0xe6, 0x25, 0xfd, 0x81, 0xff, 0x1f, 0x66, 0xe4, 0x7a, 0xd7, 0xb6, 0x4c, 0x97, 0xa0, 0xab,
-	0x50, 0xa4, 0xd6, 0x09, 0x31, 0xe5, 0x19, 0x14, 0x04, 0xaa, 0xc2, 0xd4, 0x99, 0xe6, 0x98, 0x86,
-	0xd9, 0x95, 0x12, 0x7c, 0x12, 0xd7, 0x01, 0xb6, 0x3c, 0xda, 0xdb, 0xb1, 0xcc, 0x8e, 0xd1, 0x65,
-	0xf0, 0x27, 0x86, 0xd9, 0xe6, 0x8b, 0x67, 0x54, 0xfe, 0x8d, 0x97, 0x00, 0x5e, 0x1c, 0x3d, 0x6f,
-	0x4a, 0x8e, 0x2a, 0x4c, 0x11, 0x53, 0x6b, 0xf5, 0x89, 0x60, 0x2a, 0xa9, 0x3e, 0x89, 0x1d, 0x28,
-	0xbc, 0xb4, 0xda, 0x04, 0x4d, 0x83, 0x62, 0x48, 0xfd, 0x15, 0x83, 0x51, 0x3d, 0x89, 0xa9, 0xf4,
-	0x98, 0x7c, 0x87, 0x74, 0x4e, 0xa4, 0x27, 0xf8, 0x37, 0xbb, 0x58, 0x0e, 0xe9, 0xf0, 0xdd, 0x2a,
-	0xa9, 0xec, 0x93, 0xd9, 0xa0, 0x6b, 0x7a, 0x8f, 0xf0, 0x23, 0x59, 0x52, 0x05, 0xc1, 0xd7, 0x5a,
-	0x16, 0x95, 0x87, 0x91, 0x7f, 0xe3, 0x15, 0x28, 0x3e, 0xd7, 0x86, 0xc4, 0x41, 0x77, 0x40, 0xe9,
-	0x67, 0x9c, 0x41, 0xa6, 0x94, 0xaa, 0xf4, 0xf1, 0x0a, 0x14, 0x8e, 0x1c, 0x42, 0x10, 0x06, 0x85,
-	0x4a, 0xd6, 0xab, 0x31, 0x56, 0x2e, 0x4b, 0x55, 0x28, 0x5e, 0x87, 0xd2, 0x3e, 0x19, 0xbe, 0xd2,
-	0xfa, 0x1e, 0x49, 0x5e, 0x7c, 0xa6, 0xdf, 0x29, 0x9b, 0x92, 0x76, 0x09, 0x82, 0x5d, 0xe2, 0xdc,
-	0x81, 0x8d, 0xee, 0x43, 0x7e, 0xff, 0x95, 0xcb, 0xd9, 0x2b, 0xeb, 0xd7, 0x63, 0x00, 0xbe, 0xd0,
-	0x67, 0x13, 0x2a, 0xe3, 0x42, 0xeb, 0x50, 0x3c, 0x3e, 0xb0, 0xa9, 0xcb, 0x25, 0x55, 0xd6, 0x6b,
-	0x31, 0xf6, 0xe3, 0xad, 0x76, 0xfb, 0x40, 0x44, 0xa9, 0x67, 0x13, 0xaa, 0x60, 0x45, 0x5f, 0x40,
-	0x51, 0xe5, 0x6b, 0xf2, 0x7c, 0xcd, 0x7c, 0x6c, 0x8d, 0x4a, 0x3a, 0xc4, 0x21, 0xa6, 0x4e, 0x22,
-	0x0b, 0x39, 0xff, 0x76, 0x05, 0xca, 0x96, 0x4d, 0x1c, 0x1e, 0xe9, 0xf0, 0x97, 0x90, 0x3f, 0xb0,
-	0x5d, 0xf4, 0x10, 0xe0, 0xc0, 0x1f, 0xf3, 0x2f, 0xf1, 0x07, 0x31, 0x89, 0x07, 0xb6, 0x1a, 0x61,
-	0xc2, 0x47, 0x80, 0x9a, 0xd4, 0xf1, 0x74, 0xea, 0x39, 0xa4, 0x3d, 0xc6, 0x4b, 0xab, 0x51, 0x2f,
-	0x55, 0xd6, 0xaf, 0xc5, 0xa4, 0xee, 0x58, 0x26, 0x25, 0x26, 0xf5, 0xbd, 0xb7, 0x05, 0x53, 0x72,
-	0x84, 0x45, 0x25, 0x6a, 0x0c, 0x88, 0x4b, 0xb5, 0x81, 0xcd, 0x05, 0x16, 0xd4, 0x70, 0x80, 0x1d,
-	0x40, 0x5b, 0x1b, 0xf6, 0x2d, 0xcd, 0xbf, 0x0c, 0x3e, 0x89, 0x6f, 0x41, 0x71, 0xcf, 0x6c, 0x93,
-	0x73, 0xb6, 0x3f, 0x06, 0xfb, 0x90, 0x8b, 0x05, 0x81, 0xbf, 0x82, 0xc2, 0x1e, 0x25, 0x83, 0x77,
-	0xdd, 0xcf, 0x50, 0x4a, 0x3e, 0x2a, 0xa5, 0x03, 0xb3, 0xa1, 0xf5, 0x19, 0xf2, 0xde, 0xcb, 0xf2,
-	0x0c, 0x9c, 0x47, 0x30, 0xb9, 0xff, 0x4a, 0x86, 0x58, 0x79, 0xa0, 0xf2, 0x63, 0x0e, 0x14, 0x3f,
-	0x4e, 0xf8, 0x57, 0x30, 0xd5, 0x94, 0xab, 0x1e, 0x43, 0xa1, 0x19, 0x2e, 0xbb, 0x13, 0x5b, 0x96,
-	0xdc, 0x40, 0x95, 0xb3, 0xe3, 0x87, 0x30, 0xb5, 0x4f, 0x86, 0x5c, 0xc2, 0x12, 0x14, 0x4e, 0xc8,
-	0xd0, 0x97, 0x80, 0x92, 0xc0, 0x2a, 0x9f, 0x67, 0xcf, 0x01, 0xf3, 0x83, 0xff, 0x1c, 0x18, 0x94,
-	0x0c, 0xb2, 0x9e, 0x03, 0xc6, 0xa7, 0x0a, 0x0e, 0xfc, 0x93, 0x02, 0xc5, 0x63, 0xee, 0xc0, 0xbb,
-	0x50, 0x60, 0x43, 0xf2, 0xca, 0xa4, 0xae, 0xe1, 0x0c, 0xcc, 0x53, 0xae, 0x6e, 0x39, 0xc2, 0xaf,
-	0x8a, 0x2a, 0x08, 0xb4, 0x08, 0x33, 0xba, 0xe7, 0x38, 0xc4, 0xa4, 0x07, 0x9d, 0x8e, 0x4b, 0xa8,
-	0x0c, 0x2e, 0xa3, 0x83, 0xa1, 0x97, 0x0b, 0x51, 0x2f, 0x7f, 0x01, 0xe5, 0xe3, 0x40, 0xf9, 0x95,
-	0x51, 0xe5, 0xe3, 0xc1, 0xe1, 0x38, 0xaa, 0xfd, 0x5e, 0xf4, 0x12, 0x04, 0x12, 0x1e, 0x8d, 0x4a,
-	0xb8, 0x95, 0xe9, 0xf5, 0xa8, 0xa8, 0x7d, 0xf8, 0xf0, 0x38, 0x45, 0xd6, 0x67, 0xa3, 0xb2, 0x6e,
-	0xc7, 0xb5, 0x49, 0x17, 0xf6, 0x67, 0x05, 0xae, 0xc4, 0xa6, 0xd0, 0xc3, 0x11, 0xff, 0x5e, 0xa0,
-	0xd4, 0xff, 0xca, 0xd3, 0x0e, 0x14, 0x54, 0xcb, 0xa2, 0x68, 0x3d, 0xbc, 0xbe, 0x42, 0x9f, 0x6a,
-	0x3c, 0x7e, 0x59, 0x16, 0xe5, 0xd7, 0x38, 0xb8, 0xd8, 0xe8, 0x73, 0x28, 0xbb, 0x46, 0xd7, 0xd4,
-	0x98, 0x96, 0xf2, 0x4e, 0xc5, 0x57, 0x35, 0xfd, 0x79, 0x35, 0x64, 0xc5, 0x8f, 0xa1, 0x1c, 0x48,
-	0x4b, 0x0f, 0x0a, 0xc1, 0xa3, 0x92, 0x93, 0x0f, 0x12, 0x7b, 0x54, 0x76, 0xa1, 0x1c, 0x88, 0x63,
-	0xc1, 0x28, 0xc4, 0x16, 0x77, 0x3c, 0x1c, 0x60, 0xb3, 0xb6, 0xd7, 0xea, 0x1b, 0xfa, 0x3e, 0x19,
-	0x4a, 0x19, 0xe1, 0x00, 0xfe, 0x51, 0x81, 0x4a, 0x53, 0xd7, 0x4c, 0x19, 0x89, 0x59, 0x42, 0x65,
-	0x3b, 0xa4, 0x63, 0x9c, 0x4b, 0x41, 0x92, 0x62, 0xe3, 0x96, 0x70, 0xa8, 0x10, 0x21, 0x29, 0xa6,
-	0x72, 0xdf, 0x18, 0x18, 0xd4, 0x8f, 0x0c, 0x9c, 0x60, 0x01, 0xd0, 0x21, 0xa7, 0xc4, 0x91, 0x19,
-	0x4e, 0x49, 0xf5, 0x49, 0x66, 0x4c, 0x9b, 0x10, 0x5b, 0x3e, 0x9b, 0xfc, 0x1b, 0x2f, 0x40, 0x79,
-	0x9f, 0x0c, 0x0f, 0x03, 0xa0, 0x34, 0x05, 0x30, 0x06, 0x60, 0x9b, 0xef, 0xee, 0x58, 0x9e, 0xc9,
-	0x61, 0x75, 0xf6, 0xe1, 0x7b, 0x8a, 0x13, 0xd8, 0x81, 0xd9, 0x3d, 0x53, 0xef, 0x7b, 0x2c, 0xcd,
-	0x3a, 0x74, 0x2c, 0xab, 0x83, 0x66, 0x21, 0xa7, 0xf9, 0x4c, 0x39, 0x2d, 0xb2, 0xf1, 0xb9, 0x34,
-	0x0f, 0xe7, 0x43, 0x0f, 0xb3, 0xb1, 0x3e, 0xd1, 0xc4, 0x9b, 0x3f, 0xad, 0xf2, 0x6f, 0x36, 0x66,
-	0x6b, 0xb4, 0x57, 0x2d, 0xd6, 0xf3, 0x6c, 0x8c, 0x7d, 0xe3, 0x9f, 0x15, 0x98, 0xdb, 0xb1, 0x4c,
-	0xd7, 0x70, 0x29, 0x31, 0xf5, 0xa1, 0x80, 0xbd, 0x0a, 0xc5, 0x8e, 0xe1, 0xb8, 0x81, 0x7a, 0x9c,
-	0x60, 0xa6, 0xb9, 0x44, 0xb7, 0xcc, 0xb6, 0x44, 0x97, 0x14, 0xdb, 0x21, 0xce, 0xa0, 0x86, 0x3a,
-	0x84, 0x03, 0x2c, 0x9d, 0x14, 0x7c, 0x7c, 0x5a, 0xa8, 0x13, 0x19, 0x49, 0x55, 0xea, 0x2f, 0x0a,
-	0x14, 0x85, 0x26, 0xbe, 0x19, 0x4a, 0xc4, 0x8c, 0x77, 0x77, 0x82, 0x70, 0x5f, 0x21, 0x70, 0xdf,
-	0x22, 0xcc, 0x18, 0x81, 0x83, 0x43, 0xd0, 0xd1, 0x41, 0xb4, 0x0c, 0x57, 0xf4, 0x88, 0x47, 0x18,
-	0xdf, 0x24, 0xe7, 0x8b, 0x0f, 0xe3, 0xef, 0xa1, 0xd4, 0xd4, 0x3a, 0xe4, 0xfd, 0x42, 0xec, 0x0a,
-	0x14, 0x6d, 0x66, 0x9b, 0xbc, 0x66, 0xf1, 0x18, 0xc8, 0xed, 0x56, 0x05, 0x0b, 0x76, 0x01, 0x31,
-	0x80, 0xff, 0x3c, 0xda, 0xbc, 0x0f, 0xe8, 0x00, 0x66, 0x39, 0x28, 0xa1, 0xfe, 0xad, 0xba, 0x0b,
-	0xb9, 0x93, 0xd3, 0x0b, 0xf2, 0x2d, 0x35, 0x77, 0x72, 0x8a, 0xd6, 0xa1, 0xec, 0xf8, 0xe1, 0x20,
-	0x03, 0x4a, 0x04, 0x9e, 0x90, 0x0d, 0xbf, 0x81, 0x39, 0x09, 0xd7, 0x7c, 0xe5, 0x03, 0x3e, 0x82,
-	0xbc, 0x1b, 0x20, 0xbe, 0xc3, 0xcb, 0xca, 0xb8, 0x2f, 0x05, 0xfe, 0x4a, 0xd8, 0xba, 0x1b, 0xda,
-	0x9a, 0xcc, 0x35, 0x2e, 0x67, 0xd4, 0x55, 0x26, 0x37, 0x9e, 0x29, 0xa2, 0x06, 0xe4, 0x1c, 0x4b,
-	0xda, 0x75, 0x51, 0x5a, 0xa9, 0xe6, 0x1c, 0xeb, 0x52, 0xe0, 0xdb, 0x30, 0xfb, 0x8c, 0x68, 0x7d,
-	0xda, 0x0b, 0x4a, 0x16, 0x76, 0x75, 0xa9, 0x46, 0x3d, 0x57, 0x56, 0x14, 0x92, 0x62, 0x81, 0x8e,
-	0xc5, 0x35, 0xbf, 0x64, 0x2e, 0xab, 0x3e, 0x89, 0x4d, 0x98, 0x4b, 0x28, 0x7f, 0x13, 0xca, 0x8e,
-	0x3f, 0xe6, 0x07, 0xea, 0x60, 0xc0, 0x77, 0x5c, 0x2e, 0x74, 0xdc, 0x4a, 0x34, 0xed, 0xca, 0xd2,
-	0x5b, 0x3e, 0x5e, 0x7f, 0x50, 0xa0, 0x12, 0xc9, 0xc5, 0x99, 0x34, 0x16, 0xad, 0xe5, 0x36, 0xb0,
-	0x50, 0xbd, 0x12, 0x7d, 0x30, 0x93, 0xd2, 0x9a, 0x6c, 0xce, 0x7f, 0x46, 0xa5, 0x2e, 0xf9, 0x14,
-	0x5d, 0x0a, 0x17, 0xeb, 0xf2, 0x77, 0x05, 0xa6, 0x8f, 0xa3, 0xaf, 0x4a, 0x52, 0x99, 0xff, 0xd6,
-	0x7b, 0xb2, 0x04, 0xf9, 0x81, 0x61, 0xf2, 0xe7, 0x24, 0xcb, 0x24, 0xc6, 0xc0, 0xf9, 0xb4, 0x73,
-	0x5e, 0x98, 0x65, 0xf3, 0x69, 0xe7, 0x2c, 0x41, 0xe7, 0x54, 0x98, 0x5e, 0x28, 0x91, 0xf4, 0x02,
-	0x7f, 0x0d, 0xd3, 0x7b, 0x51, 0xc3, 0x78, 0xdd, 0xdb, 0x25, 0x4d, 0xe3, 0x35, 0x91, 0xb1, 0x3e,
-	0xa0, 0x79, 0x1f, 0x40, 0xeb, 0x92, 0x97, 0xde, 0xa0, 0x45, 0x1c, 0x19, 0x6b, 0x23, 0x23, 0xf8,
-	0x29, 0x14, 0x0e, 0xb5, 0x2e, 0x79, 0x8f, 0x84, 0x94, 0xc5, 0xe8, 0x01, 0xd3, 0x29, 0x2f, 0x5e,
-	0x4f, 0xf6, 0x8d, 0x7f, 0x80, 0x62, 0x93, 0xcb, 0xb9, 0x4c, 0x66, 0x27, 0x4a, 0x15, 0xae, 0x92,
-	0xd4, 0xd0, 0x27, 0x33, 0xb0, 0x66, 0x9f, 0x19, 0x2e, 0xb5, 0x9c, 0x61, 0xf6, 0x6d, 0x1f, 0xdd,
-	0xd9, 0xc2, 0x65, 0x77, 0x16, 0x9f, 0xc1, 0x15, 0x16, 0x01, 0xa2, 0x67, 0xfa, 0x53, 0x28, 0xbe,
-	0xb6, 0x58, 0x59, 0xa9, 0x5c, 0x54, 0x8a, 0xaa, 0x82, 0xf1, 0x52, 0xb7, 0xff, 0xb7, 0x22, 0x9e,
-	0x72, 0xc2, 0x47, 0x4e, 0xcf, 0xcc, 0x2e, 0x23, 0x7d, 0x0d, 0x4a, 0x5f, 0xf9, 0x3d, 0x37, 0x0c,
-	0xd3, 0x7e, 0xe7, 0xc7, 0xd4, 0x06, 0x7e, 0x4f, 0x6e, 0x64, 0x0c, 0x2f, 0xc3, 0xdc, 0x37, 0x2e,
-	0xf1, 0x97, 0xa8, 0xc4, 0xee, 0x0f, 0xd3, 0x1b, 0x28, 0xf8, 0xaf, 0x0a, 0x5c, 0x97, 0x9d, 0xa1,
-	0xb0, 0xd3, 0x26, 0x7b, 0x36, 0x5f, 0x88, 0x3e, 0x99, 0x25, 0x96, 0xcc, 0x26, 0x42, 0x67, 0xb8,
-	0x62, 0x8b, 0xb3, 0xa9, 0x92, 0x9d, 0x1d, 0x70, 0xcf, 0x25, 0x0e, 0x57, 0x4f, 0x44, 0xb8, 0x80,
-	0x1e, 0x69, 0x64, 0xe5, 0xc7, 0xb6, 0x13, 0x0b, 0x89, 0x76, 0xe2, 0xd7, 0x70, 0xb5, 0x49, 0xe8,
-	0x16, 0xef, 0xd6, 0x45, 0x3b, 0x5e, 0x61, 0x43, 0x4f, 0x89, 0x36, 0xf4, 0xc6, 0xe9, 0x81, 0x5f,
-	0xc0, 0x55, 0xdf, 0x3f, 0xac, 0x2c, 0x09, 0x82, 0xf6, 0x63, 0x28, 0xfb, 0xfa, 0x64, 0xd5, 0xa6,
-	0x81, 0x5f, 0x43, 0xce, 0x95, 0x7b, 0x30, 0x17, 0x77, 0x07, 0x2a, 0x43, 0x71, 0x57, 0xdd, 0x7a,
-	0x79, 0x34, 0x37, 0x81, 0x00, 0x26, 0xd5, 0xa7, 0xaf, 0x0e, 0xf6, 0x9f, 0xce, 0x29, 0xeb, 0x3f,
-	0xce, 0x43, 0x65, 0x6f, 0x30, 0xf0, 0x9a, 0xc4, 0x39, 0x35, 0x74, 0x82, 0x34, 0x28, 0x33, 0x0d,
-	0x98, 0x41, 0x2e, 0xba, 0xb6, 0x26, 0xba, 0xbd, 0x6b, 0x7e, 0xb7, 0x77, 0xed, 0xe9, 0xc0, 0xa6,
-	0xc3, 0xda, 0xf5, 0x94, 0x06, 0x24, 0x5b, 0x85, 0x17, 0x7e, 0xfa, 0xc7, 0x3f, 0xff, 0x94, 0xbb,
-	0x85, 0x6e, 0x34, 0x4e, 0x1f, 0x36, 0x18, 0x8f, 0x43, 0x5c, 0x6a, 0x3b, 0xd6, 0xf9, 0xb0, 0xc1,
-	0x6c, 0x6d, 0xf4, 0x59, 0xcd, 0x65, 0x00, 0x84, 0x2d, 0x4a, 0x54, 0x8f, 0xd7, 0xed, 0xf1, 0xee,
-	0x65, 0x2d, 0x43, 0x0b, 0x7c, 0x87, 0x83, 0xdd, 0xc0, 0xd7, 0xd2, 0xc1, 0x9e, 0x28, 0x2b, 0xe8,
-	0x47, 0x05, 0x66, 0x47, 0x5b, 0x8d, 0x68, 0x31, 0x8e, 0x97, 0xd6, 0x89, 0xcc, 0xc4, 0x7c, 0xc8,
-	0x31, 0xef, 0xe3, 0xa5, 0x0c, 0x03, 0xfd, 0x96, 0x61, 0x43, 0xe7, 0x62, 0x99, 0x0e, 0xbb, 0x30,
-	0xf7, 0x8d, 0xdd, 0xd6, 0x28, 0x89, 0x74, 0x00, 0xe3, 0x8d, 0xe5, 0x70, 0x2a, 0x13, 0x79, 0x22,
-	0x14, 0x14, 0x69, 0x14, 0xc6, 0x05, 0x85, 0x53, 0x63, 0x04, 0x3d, 0x81, 0xf2, 0xa1, 0x63, 0x98,
-	0x94, 0x37, 0xea, 0xb2, 0xf6, 0x38, 0x1e, 0xc4, 0x19, 0x33, 0x9e, 0x40, 0x27, 0x50, 0xe4, 0xad,
-	0x50, 0x74, 0x23, 0xde, 0xd5, 0x8b, 0x34, 0x58, 0x6b, 0x37, 0xd3, 0x27, 0xc5, 0xa9, 0xc6, 0x77,
-	0x7f, 0xde, 0xca, 0xb5, 0x26, 0xb8, 0x27, 0x6f, 0xe2, 0xeb, 0x49, 0x4f, 0xf6, 0x19, 0x37, 0x73,
-	0xdd, 0x77, 0x30, 0xf9, 0xdc, 0xea, 0x5a, 0x1e, 0xcd, 0xd4, 0x32, 0xcb, 0x48, 0x79, 0x10, 0x71,
-	0x35, 0x55, 0xba, 0xe5, 0x51, 0x26, 0xfe, 0x5b, 0xc8, 0x37, 0x09, 0x45, 0x59, 0xe9, 0x6c, 0x2d,
-	0x35, 0x12, 0x8e, 0x3b, 0x76, 0xec, 0x41, 0x62, 0x82, 0x3b, 0x30, 0x25, 0xf3, 0x59, 0x94, 0x78,
-	0xc3, 0x46, 0xd2, 0xea, 0x5a, 0x6a, 0x16, 0x8e, 0x97, 0x38, 0x44, 0x1d, 0xdf, 0x48, 0x87, 0x68,
-	0xb8, 0x5a, 0x87, 0x1f, 0xad, 0x23, 0xc8, 0xef, 0x12, 0x8a, 0x52, 0xba, 0x46, 0xb5, 0xb4, 0x37,
-	0x18, 0x2f, 0x72, 0xb9, 0xb7, 0xd1, 0xcd, 0x0c, 0xb9, 0x6f, 0x4e, 0xc8, 0xf0, 0x2d, 0x1a, 0x08,
-	0xed, 0x77, 0x33, 0xb4, 0x0f, 0x13, 0xe5, 0x44, 0x1c, 0xf0, 0x2b, 0x21, 0xbc, 0xc2, 0x81, 0x16,
-	0xf1, 0xfc, 0x18, 0x03, 0x1a, 0x5d, 0xc2, 0x77, 0x81, 0x55, 0x50, 0x84, 0x6e, 0x6b, 0x54, 0xef,
-	0xa1, 0x8f, 0xe2, 0x96, 0xf0, 0x36, 0x5b, 0xc6, 0x46, 0x8c, 0xf1, 0x52, 0x8b, 0x49, 0x6b, 0xb8,
-	0x02, 0x40, 0x87, 0xd2, 0xae, 0x0f, 0x70, 0x2d, 0xe9, 0x2a, 0x8e, 0x70, 0x3d, 0xc5, 0x5d, 0x3c,
-	0xa2, 0x5d, 0x08, 0x22, 0xad, 0x20, 0x00, 0x4f, 0xcf, 0x89, 0xbe, 0xd5, 0xef, 0x1f, 0xd8, 0x6e,
-	0x62, 0x47, 0x0e, 0x6c, 0x37, 0xc3, 0x88, 0x07, 0x5c, 0xfe, 0x5d, 0x8c, 0xb3, 0xe4, 0x6b, 0xd4,
-	0x1a, 0x18, 0x7a, 0x68, 0x4b, 0x81, 0x25, 0x6f, 0xa8, 0x96, 0xc8, 0xff, 0x82, 0x8c, 0xee, 0x52,
-	0xb6, 0x88, 0x5d, 0xd1, 0x35, 0x7e, 0xed, 0x4e, 0xa0, 0x28, 0x7a, 0x14, 0xd5, 0xa4, 0xb7, 0x44,
-	0x8f, 0xa3, 0xf6, 0x71, 0x0a, 0x86, 0x68, 0x6c, 0xf8, 0x16, 0xa1, 0x4f, 0x32, 0x50, 0x78, 0xa3,
-	0xa3, 0xf1, 0x46, 0x34, 0x45, 0xde, 0xa2, 0x0e, 0x94, 0xf8, 0xba, 0xad, 0x7e, 0x3f, 0xf3, 0x96,
-	0x8f, 0x41, 0xbb, 0xcb, 0xd1, 0xee, 0xa0, 0xf9, 0x71, 0x68, 0x5a, 0xbf, 0x8f, 0xbe, 0x87, 0xca,
-	0x8e, 0xe8, 0xa0, 0xf1, 0x9e, 0xc3, 0xbb, 0x86, 0x3d, 0xc6, 0x8c, 0x17, 0xc2, 0x80, 0x55, 0x45,
-	0x29, 0xf7, 0x9e, 0x77, 0x1a, 0x1c, 0x28, 0x07, 0xad, 0x1b, 0x94, 0xba, 0xd9, 0xb5, 0x5b, 0x89,
-	0xd1, 0x68, 0xab, 0x07, 0x7f, 0xca, 0x11, 0x56, 0xd0, 0x72, 0x8a, 0x2d, 0x3e, 0x27, 0xaf, 0xcf,
-	0x1b, 0x6f, 0x78, 0xf6, 0xf6, 0x16, 0x9d, 0x43, 0x25, 0xd2, 0xb9, 0xc9, 0x40, 0x9d, 0x4f, 0x76,
-	0xc6, 0x47, 0x7a, 0x3d, 0x78, 0x9d, 0xe3, 0xae, 0xa2, 0x95, 0x24, 0x6e, 0xa4, 0xdd, 0x31, 0x8a,
-	0xdc, 0x82, 0xa9, 0xed, 0xa1, 0xec, 0xf9, 0xa5, 0xa2, 0xa6, 0x06, 0xa0, 0x55, 0x8e, 0xb4, 0x84,
-	0x16, 0x33, 0x76, 0x8b, 0x0b, 0x0f, 0x30, 0x5e, 0x43, 0x65, 0x7b, 0x18, 0x24, 0xb2, 0x68, 0x3e,
-	0x2d, 0xda, 0x44, 0x52, 0xdc, 0xec, 0x70, 0x24, 0x5f, 0x6d, 0x74, 0x6f, 0x5c, 0x38, 0x1a, 0xc5,
-	0xee, 0xc2, 0x94, 0xac, 0x13, 0x12, 0x41, 0x70, 0xb4, 0x7e, 0xc8, 0xbe, 0x6e, 0x32, 0xda, 0xe2,
-	0x8f, 0x93, 0xa8, 0x3d, 0x21, 0x82, 0x5d, 0x36, 0x13, 0x26, 0x45, 0xa5, 0x9e, 0x79, 0x24, 0x13,
-	0xf8, 0x23, 0x85, 0x3d, 0x7e, 0x10, 0x1e, 0x4e, 0x8c, 0xea, 0x29, 0x58, 0x9c, 0xdd, 0xf1, 0x53,
-	0xca, 0x1f, 0xa0, 0x1c, 0x54, 0xf5, 0xe8, 0xa2, 0xfe, 0xc3, 0xfb, 0x47, 0xde, 0xa0, 0x19, 0xc0,
-	0x6c, 0x6b, 0xc1, 0xf4, 0x2e, 0xa1, 0x21, 0xdc, 0x3b, 0x3f, 0x54, 0xf7, 0x38, 0xc0, 0x02, 0xba,
-	0x33, 0x06, 0x40, 0xbe, 0x56, 0x67, 0x30, 0x33, 0xd2, 0x66, 0x41, 0x0b, 0x29, 0xa7, 0xe0, 0x42,
-	0xbb, 0xc4, 0x45, 0xb8, 0xcf, 0x61, 0x3f, 0xc1, 0x29, 0x5e, 0xe4, 0x47, 0x64, 0xc4, 0xb8, 0xdf,
-	0x40, 0x81, 0x95, 0x6b, 0x68, 0x4c, 0x0d, 0xf7, 0xfe, 0x19, 0xc4, 0x6b, 0xad, 0xdd, 0x16, 0x9e,
-	0x2b, 0xf2, 0xf6, 0x43, 0x22, 0xcd, 0x8a, 0x36, 0x25, 0x6a, 0xd5, 0xb4, 0x7f, 0x9e, 0xf0, 0xb3,
-	0x87, 0xb3, 0xb3, 0xab, 0xd7, 0x7e, 0x98, 0xef, 0x89, 0xd6, 0x25, 0x37, 0xe2, 0x76, 0x8a, 0xd3,
-	0xc6, 0x19, 0x72, 0x61, 0x9e, 0xc2, 0xfd, 0xe5, 0x5b, 0xf3, 0x1d, 0x14, 0xf7, 0x52, 0xad, 0x89,
-	0x76, 0x22, 0x12, 0x27, 0xe1, 0x50, 0xeb, 0x92, 0x71, 0x86, 0x18, 0xbe, 0x21, 0x07, 0x50, 0xf8,
-	0xca, 0x1b, 0xd8, 0x99, 0x17, 0x08, 0xd6, 0xec, 0x96, 0x4c, 0x25, 0xc6, 0xf9, 0xbe, 0xed, 0x0d,
-	0xec, 0x27, 0xca, 0xca, 0xa7, 0x0a, 0x32, 0x61, 0x56, 0x94, 0x21, 0x41, 0x9d, 0x9b, 0x55, 0x75,
-	0x65, 0x26, 0xa0, 0x63, 0x8e, 0x52, 0xf0, 0xdb, 0x0f, 0x2e, 0x81, 0x19, 0xf0, 0x96, 0xff, 0x66,
-	0xe2, 0x62, 0xb0, 0xf9, 0x64, 0xdd, 0x35, 0x52, 0x56, 0xe3, 0xcf, 0x38, 0xea, 0x1a, 0x5a, 0x4d,
-	0x2d, 0x4f, 0x7c, 0xc8, 0xc6, 0x9b, 0x68, 0x7d, 0xfe, 0x96, 0x55, 0x49, 0x73, 0xf1, 0xb2, 0x1b,
-	0x2d, 0xa5, 0xd7, 0x49, 0xf1, 0xba, 0x3c, 0xd3, 0x01, 0x63, 0x12, 0x1b, 0x51, 0x1b, 0x85, 0xa5,
-	0xb4, 0x70, 0xc1, 0xcc, 0x48, 0x35, 0x9d, 0xbc, 0xc6, 0x29, 0xb5, 0x76, 0x26, 0x78, 0x83, 0x83,
-	0xdf, 0xc3, 0x8b, 0x19, 0x65, 0x9a, 0x4b, 0xa8, 0x16, 0x08, 0x63, 0xf0, 0x6f, 0x60, 0x3a, 0x5a,
-	0x80, 0x67, 0x1e, 0xa5, 0x85, 0x8c, 0xad, 0x89, 0x56, 0xed, 0x78, 0x8d, 0xa3, 0x2f, 0xe3, 0x85,
-	0x0c, 0x74, 0xdf, 0xfb, 0xac, 0x1a, 0x7e, 0xa2, 0xac, 0x6c, 0xff, 0x31, 0xff, 0xf3, 0xd6, 0x2f,
-	0x39, 0xf4, 0x2f, 0x05, 0xae, 0x08, 0xe9, 0x75, 0xf5, 0x69, 0xf3, 0xa8, 0xbe, 0x75, 0xb8, 0x87,
-	0x7e, 0x51, 0x36, 0x5a, 0x9b, 0x7b, 0x2f, 0x0e, 0x0f, 0xd4, 0xa3, 0xad, 0x97, 0x47, 0x1b, 0x8d,
-	0xd6, 0xe6, 0x93, 0xfa, 0x56, 0xbf, 0x5f, 0xdf, 0xd0, 0xad, 0x36, 0xd9, 0xec, 0x12, 0xba, 0xd1,
-	0xe0, 0x5f, 0x75, 0xcd, 0x6c, 0xcb, 0x41, 0x76, 0xf3, 0x22, 0x13, 0x1d, 0xcf, 0xe4, 0x0d, 0x00,
-	0xb7, 0xee, 0x10, 0xea, 0x39, 0x66, 0x7d, 0xc3, 0xdb, 0x64, 0xe0, 0x9f, 0x7f, 0xf6, 0x80, 0x98,
-	0x8c, 0xa5, 0xbd, 0xd1, 0xf0, 0x36, 0xeb, 0x27, 0x64, 0xe8, 0x72, 0x21, 0xfc, 0xdf, 0xe5, 0xee,
-	0x6a, 0xfd, 0xac, 0x67, 0xf4, 0x49, 0x5d, 0x0b, 0xb0, 0xdc, 0x2c, 0x2c, 0x37, 0x0d, 0x8b, 0x9c,
-	0xdb, 0x44, 0xa7, 0x19, 0x58, 0x86, 0x69, 0x7b, 0xd4, 0x5d, 0x3b, 0xfe, 0x35, 0x7c, 0x0b, 0x93,
-	0x2d, 0xa2, 0x39, 0xc4, 0x41, 0x2f, 0x4a, 0x39, 0xf4, 0x25, 0x2b, 0x83, 0x89, 0x49, 0x0d, 0x9d,
-	0xff, 0x08, 0xa2, 0xce, 0x9b, 0x42, 0xab, 0x75, 0x91, 0x29, 0x92, 0x76, 0xbd, 0x35, 0xac, 0x6f,
-	0x73, 0xee, 0x27, 0xf2, 0x6f, 0x7d, 0x83, 0xb3, 0x6c, 0xd6, 0x66, 0xd8, 0x4a, 0xcb, 0x31, 0x5e,
-	0x8b, 0x85, 0xb9, 0xd6, 0x34, 0x40, 0x20, 0x7a, 0xe2, 0xf8, 0x7e, 0xd7, 0xa0, 0x3d, 0xaf, 0xb5,
-	0xa6, 0x5b, 0x03, 0xae, 0xa9, 0x69, 0x51, 0xcd, 0x19, 0x36, 0x84, 0xb3, 0x1b, 0xf6, 0x49, 0x97,
-	0xff, 0xd8, 0x4d, 0x6c, 0x69, 0x6b, 0x92, 0x6f, 0xf9, 0xa3, 0x7f, 0x07, 0x00, 0x00, 0xff, 0xff,
-	0xca, 0x85, 0x54, 0x6d, 0x25, 0x27, 0x00, 0x00,
+	// 5144 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x3c, 0x4d, 0x73, 0x1c, 0xc9,
+	0x52, 0xee, 0xf9, 0x90, 0x66, 0x72, 0x24, 0x59, 0x5b, 0xeb, 0x5d, 0xcf, 0x8e, 0x65, 0x7b, 0xdc,
+	0xf6, 0x7a, 0x65, 0xad, 0xad, 0xb1, 0xe5, 0xfd, 0x7a, 0x8b, 0x31, 0xc8, 0x5e, 0xe3, 0xd5, 0xb3,
+	0x6c, 0x99, 0x1e, 0xd9, 0x1b, 0x18, 0x96, 0x7d, 0x3d, 0x3d, 0x35, 0xa3, 0x5e, 0xf5, 0x74, 0x37,
+	0xdd, 0x3d, 0xb2, 0xc6, 0x5e, 0xf3, 0xf1, 0x22, 0x80, 0x78, 0x11, 0x5c, 0xde, 0x12, 0x10, 0xc1,
+	0x89, 0x08, 0x8e, 0xf0, 0x07, 0x08, 0x38, 0x41, 0x04, 0x57, 0x2e, 0x70, 0x20, 0x38, 0x73, 0xe6,
+	0x1f, 0x10, 0x41, 0x64, 0x7d, 0xf4, 0x77, 0xcf, 0x48, 0xda, 0xb7, 0x27, 0x75, 0x56, 0x65, 0x65,
+	0x66, 0x65, 0x55, 0x65, 0x65, 0x66, 0xe5, 0x08, 0x16, 0x7c, 0x63, 0x8f, 0x8e, 0xf4, 0x75, 0xd7,
+	0x73, 0x02, 0x87, 0x2c, 0x9a, 0xa3, 0xd1, 0xb8, 0xdf, 0x5b, 0xe7, 0x8d, 0xad, 0x95, 0xa1, 0xe3,
+	0x0c, 0x2d, 0xda, 0xd1, 0x5d, 0xb3, 0xa3, 0xdb, 0xb6, 0x13, 0xe8, 0x81, 0xe9, 0xd8, 0x3e, 0x47,
+	0x6e, 0x9d, 0x13, 0xbd, 0x0c, 0xea, 0x8d, 0x07, 0x1d, 0x3a, 0x72, 0x83, 0x89, 0xe8, 0xbc, 0xce,
+	0xfe, 0x18, 0x37, 0x86, 0xd4, 0xbe, 0xe1, 0xbf, 0xd4, 0x87, 0x43, 0xea, 0x75, 0x1c, 0x97, 0x0d,
+	0xcf, 0x21, 0xd5, 0x70, 0x7b, 0x1d, 0xb7, 0xc7, 0x01, 0xf5, 0x2c, 0x94, 0x1f, 0xd1, 0x09, 0x59,
+	0x86, 0xf2, 0x3e, 0x9d, 0x34, 0x95, 0xb6, 0xb2, 0xba, 0xa0, 0xe1, 0xa7, 0xfa, 0x25, 0xc0, 0x53,
+	0xea, 0x8d, 0x4c, 0xdf, 0x37, 0x1d, 0x9b, 0xb4, 0xa0, 0xd6, 0xd7, 0x03, 0xbd, 0xa7, 0xfb, 0x94,
+	0x21, 0xd5, 0xb5, 0x10, 0x26, 0x17, 0x00, 0xdc, 0x10, 0xb3, 0x59, 0x6a, 0x2b, 0xab, 0x8b, 0x5a,
+	0xac, 0x45, 0xfd, 0x07, 0x05, 0x2a, 0xcf, 0x7c, 0xea, 0x11, 0x02, 0x95, 0xb1, 0x4f, 0x3d, 0xc1,
+	0x85, 0x7d, 0x93, 0x5f, 0x83, 0x46, 0x84, 0xea, 0x37, 0xcb, 0xed, 0xf2, 0x6a, 0x63, 0xe3, 0xbd,
+	0xf5, 0x84, 0x6a, 0xd6, 0x23, 0x41, 0xb4, 0x38, 0x36, 0x59, 0x81, 0xba, 0xe1, 0x51, 0x3d, 0xa0,
+	0xfd, 0xde, 0xa4, 0x59, 0x61, 0x62, 0x45, 0x0d, 0xb1, 0x5e, 0x3d, 0x68, 0x56, 0x13, 0xbd, 0x7a,
+	0x40, 0xde, 0x85, 0x39, 0xdd, 0x08, 0xcc, 0x03, 0xda, 0x9c, 0x6b, 0x2b, 0xab, 0x35, 0x4d, 0x40,
+	0xea, 0xc7, 0x50, 0x43, 0x61, 0xb7, 0x4d, 0x3f, 0x20, 0xd7, 0xa0, 0x8a, 0x42, 0xfa, 0x4d, 0x85,
+	0x89, 0xf5, 0x76, 0x4a, 0x2c, 0xc4, 0xd3, 0x38, 0x86, 0xfa, 0x7f, 0x0a, 0xcc, 0x77, 0x29, 0x57,
+	0xd6, 0x12, 0x94, 0xcc, 0xbe, 0x50, 0x53, 0xc9, 0xec, 0x87, 0xf3, 0x2e, 0xb1, 0x16, 0x3e, 0xef,
+	0x15, 0xa8, 0x0f, 0x4c, 0xcf, 0x0f, 0xba, 0x94, 0xda, 0xcd, 0x72, 0x5b, 0x59, 0x2d, 0x6b, 0x51,
+	0x03, 0xaa, 0xdb, 0xd2, 0x45, 0x67, 0x85, 0x75, 0x86, 0x30, 0x69, 0x43, 0x03, 0xbf, 0x37, 0xfb,
+	0x7d, 0x8f, 0xfa, 0xbe, 0x98, 0x58, 0xbc, 0x09, 0x17, 0x04, 0xc1, 0xc7, 0x34, 0xd8, 0x73, 0xfa,
+	0x6c, 0x7a, 0x75, 0x2d, 0xd6, 0x42, 0xce, 0x40, 0xd5, 0xd0, 0x2d, 0xcb, 0x6f, 0xce, 0xb7, 0x95,
+	0xd5, 0x8a, 0xc6, 0x01, 0x94, 0x48, 0xe7, 0x04, 0xa8, 0xdf, 0xac, 0xb5, 0xcb, 0xa8, 0xae, 0xb0,
+	0x01, 0x69, 0xd2, 0x43, 0xd7, 0xf4, 0xd8, 0x4e, 0x6a, 0xd6, 0x99, 0x4c, 0xb1, 0x16, 0x75, 0x13,
+	0x1a, 0x62, 0xfa, 0x4c, 0x73, 0x1b, 0x50, 0xf3, 0xa9, 0x58, 0x53, 0xae, 0xbc, 0x77, 0x53, 0xca,
+	0x13, 0xd8, 0x5a, 0x88, 0xa7, 0x3e, 0x87, 0x85, 0x67, 0xbe, 0x3e, 0xa4, 0x1a, 0xfd, 0x83, 0x31,
+	0xf5, 0x83, 0xa9, 0x7b, 0xee, 0x0c, 0x54, 0x7d, 0xd3, 0x36, 0x28, 0xd3, 0x69, 0x59, 0xe3, 0x00,
+	0xb6, 0x8e, 0xed, 0xc0, 0xb4, 0x84, 0x42, 0x39, 0xa0, 0xfe, 0xad, 0x02, 0x55, 0x46, 0x78, 0x2a,
+	0xc5, 0xbc, 0x45, 0x3a, 0x03, 0x55, 0x8f, 0xea, 0x7d, 0x9f, 0xd1, 0xab, 0x68, 0x1c, 0xc0, 0x9d,
+	0xf3, 0xd2, 0x33, 0x03, 0xea, 0xb3, 0xa5, 0xa9, 0x68, 0x02, 0x42, 0x6c, 0xbd, 0x3f, 0x32, 0x6d,
+	0xb6, 0x24, 0x15, 0x8d, 0x03, 0x44, 0x85, 0x05, 0xec, 0x0f, 0xa8, 0x7d, 0x6f, 0x82, 0x63, 0xe6,
+	0x58, 0x67, 0xa2, 0x4d, 0xa5, 0xd0, 0x10, 0x33, 0x77, 0x1d, 0x2f, 0x88, 0x26, 0xa7, 0xe4, 0x4e,
+	0xae, 0x14, 0x9b, 0x1c, 0x59, 0xc3, 0x2d, 0xaa, 0x0f, 0xa9, 0x38, 0x39, 0x67, 0x32, 0x5b, 0x14,
+	0xc9, 0x72, 0x14, 0xf5, 0x2e, 0x90, 0x4d, 0xc3, 0xa0, 0xbe, 0x7f, 0xdf, 0xb1, 0x03, 0xcf, 0xb1,
+	0xba, 0x81, 0x1e, 0xb0, 0x89, 0xef, 0xe9, 0xfe, 0x9e, 0x3c, 0x95, 0xf8, 0xcd, 0x78, 0xb1, 0x8d,
+	0xcf, 0x4f, 0x33, 0x07, 0xd4, 0x3f, 0x82, 0xb7, 0xee, 0xb3, 0xf3, 0xc3, 0x36, 0xbe, 0x58, 0xa5,
+	0xbc, 0x43, 0xdd, 0x82, 0x9a, 0xab, 0xfb, 0xfe, 0x4b, 0xc7, 0xeb, 0x33, 0x0a, 0x0b, 0x5a, 0x08,
+	0xa7, 0xac, 0x45, 0x39, 0x6d, 0x2d, 0x12, 0x6b, 0x54, 0x49, 0xae, 0x91, 0x7a, 0x09, 0x1a, 0x33,
+	0x58, 0xab, 0x0e, 0xbc, 0x73, 0x7f, 0x4f, 0xb7, 0x87, 0xf4, 0xa9, 0x60, 0x38, 0x4d, 0xce, 0x36,
+	0x34, 0x1c, 0xab, 0xff, 0x34, 0x29, 0x6a, 0xbc, 0x09, 0x31, 0x6c, 0xfa, 0x32, 0xc4, 0x28, 0x73,
+	0x8c, 0x58, 0x93, 0x7a, 0x17, 0x16, 0xb6, 0x9d, 0xa1, 0x69, 0x9f, 0x50, 0x1f, 0xea, 0x6f, 0xc0,
+	0xa2, 0x18, 0xef, 0xbb, 0x8e, 0xcd, 0xb7, 0x76, 0xe0, 0xec, 0x53, 0x5b, 0xec, 0x50, 0x0e, 0x90,
+	0x26, 0xcc, 0xbf, 0xd4, 0x3d, 0xdb, 0xb4, 0x87, 0x82, 0x82, 0x04, 0xd5, 0x36, 0xc0, 0xe6, 0x38,
+	0xd8, 0xbb, 0xef, 0xd8, 0x03, 0x73, 0x88, 0xec, 0xf7, 0x4d, 0x9b, 0x5b, 0x9f, 0x45, 0x8d, 0x7d,
+	0xab, 0x57, 0x01, 0x1e, 0xef, 0x6e, 0x77, 0x05, 0x46, 0x13, 0xe6, 0xa9, 0xad, 0xf7, 0x2c, 0xca,
+	0x91, 0x6a, 0x9a, 0x04, 0x55, 0x0f, 0x2a, 0x4f, 0x9c, 0x3e, 0x25, 0x0b, 0xa0, 0x98, 0x42, 0x7e,
+	0xc5, 0x44, 0x68, 0x4f, 0xf0, 0x54, 0xf6, 0x90, 0xbe, 0x47, 0x07, 0xfb, 0x42, 0x13, 0xec, 0x1b,
+	0x2f, 0x0f, 0x8f, 0x0e, 0xd8, 0x6a, 0xd5, 0x34, 0xfc, 0xe4, 0x16, 0xc6, 0xd8, 0xa3, 0xec, 0x28,
+	0xd4, 0x34, 0x0e, 0xb0, 0xb1, 0x8e, 0x13, 0x08, 0x83, 0xcb, 0xbe, 0xd5, 0x35, 0xa8, 0x6e, 0xeb,
+	0x13, 0xea, 0x91, 0x4b, 0xa0, 0x58, 0x05, 0x76, 0x16, 0x85, 0xd2, 0x14, 0x4b, 0x5d, 0x83, 0xca,
+	0xae, 0x47, 0x29, 0x51, 0x41, 0x09, 0x9a, 0x4a, 0xee, 0x7e, 0x67, 0xb4, 0x34, 0x25, 0x50, 0x37,
+	0xa0, 0xf6, 0x88, 0x4e, 0x9e, 0xeb, 0xd6, 0x98, 0x66, 0x2f, 0x37, 0x94, 0xef, 0x00, 0xbb, 0xc4,
+	0xbc, 0x38, 0xa0, 0xfe, 0x5b, 0x09, 0x4a, 0x3b, 0x2e, 0xf9, 0x10, 0xca, 0x8f, 0x9e, 0xfb, 0x0c,
+	0xbd, 0xb1, 0x71, 0x36, 0xc5, 0x40, 0x12, 0xfd, 0xf2, 0x94, 0x86, 0x58, 0x64, 0x03, 0xaa, 0x2f,
+	0x76, 0xdc, 0x80, 0x9f, 0x94, 0xc6, 0x46, 0x2b, 0x85, 0xfe, 0x62, 0xb3, 0xdf, 0xdf, 0xe1, 0x37,
+	0xf1, 0x97, 0xa7, 0x34, 0x8e, 0x4a, 0x3e, 0x85, 0xaa, 0xc6, 0xc6, 0x94, 0xd9, 0x98, 0x8b, 0xa9,
+	0x31, 0x1a, 0x1d, 0x50, 0x8f, 0xda, 0x06, 0x8d, 0x0d, 0x64, 0xf8, 0xe4, 0x3a, 0xcc, 0x7d, 0x41,
+	0x2d, 0x1a, 0xf0, 0x93, 0xd1, 0xd8, 0x20, 0x59, 0xe1, 0xbe, 0x3c, 0xa5, 0x09, 0x1c, 0xf2, 0x09,
+	0x34, 0x9e, 0xd9, 0x9e, 0x24, 0xd6, 0xac, 0x4e, 0x19, 0x12, 0x47, 0x24, 0x1b, 0x30, 0xf7, 0x5b,
+	0x1e, 0xa5, 0xaf, 0xf8, 0xcd, 0xd8, 0xd8, 0x68, 0x66, 0x87, 0x3c, 0xf5, 0xe8, 0xc0, 0x3c, 0x44,
+	0x5e, 0x1c, 0xf3, 0x5e, 0x03, 0xea, 0x8e, 0x4b, 0xc5, 0x5d, 0xf0, 0x19, 0x94, 0x77, 0x5c, 0x9f,
+	0xdc, 0x02, 0xd8, 0x91, 0x6d, 0xf2, 0x16, 0x78, 0x2b, 0x45, 0x6b, 0xc7, 0xd5, 0x62, 0x48, 0xea,
+	0x2e, 0x90, 0x6e, 0xe0, 0x8d, 0x8d, 0x60, 0xec, 0xd1, 0xfe, 0x94, 0xf5, 0xbb, 0x1e, 0x5f, 0xbf,
+	0xec, 0xdd, 0x82, 0xf6, 0x8d, 0xda, 0x81, 0x5c, 0xd7, 0x4d, 0x98, 0x17, 0x2d, 0x78, 0xc9, 0x05,
+	0xe6, 0x88, 0xfa, 0x81, 0x3e, 0x72, 0x19, 0xc1, 0x8a, 0x16, 0x35, 0xe0, 0xd1, 0x70, 0xf5, 0x89,
+	0xe5, 0xe8, 0xf2, 0x98, 0x4a, 0x50, 0xfd, 0x09, 0x54, 0xb7, 0xec, 0x3e, 0x3d, 0xc4, 0x9d, 0x63,
+	0xe2, 0x87, 0x18, 0xcc, 0x01, 0x3c, 0xe0, 0x3e, 0x9e, 0x7f, 0x79, 0x23, 0x55, 0xb4, 0x10, 0x56,
+	0xaf, 0x42, 0xad, 0x2b, 0xbe, 0x13, 0x78, 0x4a, 0x0a, 0xef, 0xaf, 0x14, 0x58, 0x92, 0x88, 0xfd,
+	0xaf, 0xf0, 0x4a, 0x99, 0x86, 0x8e, 0x76, 0x94, 0xf9, 0x0b, 0x4c, 0x2c, 0xc1, 0x34, 0xd6, 0x82,
+	0x33, 0xb5, 0x74, 0x01, 0x88, 0xfb, 0x2b, 0x6a, 0x40, 0xcf, 0xc6, 0x0c, 0xe8, 0x08, 0xaf, 0xb0,
+	0xbc, 0x13, 0xb7, 0x15, 0xd0, 0x91, 0xc6, 0x31, 0xd4, 0xdf, 0x87, 0x0a, 0x82, 0x47, 0x3d, 0x45,
+	0x91, 0x86, 0xca, 0x71, 0x0d, 0x35, 0x61, 0xbe, 0xcf, 0xb6, 0x65, 0x5f, 0xd8, 0x09, 0x09, 0xaa,
+	0x7f, 0x8c, 0xf3, 0x0e, 0x17, 0xbd, 0x80, 0xd5, 0xb1, 0x16, 0xfc, 0xd8, 0x22, 0xdc, 0x86, 0xb9,
+	0x47, 0xcf, 0x85, 0xc7, 0x27, 0xce, 0x7e, 0x79, 0xca, 0xd9, 0x67, 0x27, 0x5f, 0xfd, 0x4d, 0x98,
+	0xef, 0x8a, 0x51, 0x1f, 0x43, 0xa5, 0x1b, 0x0d, 0xbb, 0x94, 0xf6, 0x74, 0x32, 0x3b, 0x5a, 0x63,
+	0xe8, 0xea, 0x2d, 0x98, 0x7f, 0x44, 0x27, 0x8c, 0xc2, 0x55, 0xa8, 0xec, 0xd3, 0x89, 0xa4, 0x90,
+	0x73, 0x48, 0x35, 0xd6, 0xaf, 0x3e, 0x86, 0x1a, 0x6a, 0x48, 0x7a, 0xa7, 0x7c, 0x0d, 0x95, 0x59,
+	0x6b, 0x88, 0x2e, 0x8b, 0x31, 0xf6, 0x7c, 0xc7, 0x13, 0x4b, 0x25, 0x20, 0xf5, 0xe7, 0x0a, 0x54,
+	0x5f, 0x30, 0x95, 0x7f, 0x00, 0x15, 0x44, 0x15, 0x56, 0x2f, 0x97, 0x16, 0x43, 0x60, 0xce, 0x89,
+	0xe1, 0x78, 0x7c, 0x25, 0x14, 0x8d, 0x03, 0xe4, 0x0a, 0x2c, 0x1a, 0x63, 0xcf, 0xa3, 0x76, 0xb0,
+	0x33, 0x18, 0xf8, 0x34, 0x10, 0xf7, 0x43, 0xb2, 0x31, 0x5a, 0x97, 0x4a, 0x6c, 0x5d, 0xd4, 0x4f,
+	0xa1, 0xfe, 0x22, 0x9c, 0xd4, 0x5a, 0x72, 0x52, 0x69, 0xfb, 0xfe, 0x22, 0xbe, 0x33, 0xb7, 0xe2,
+	0xd6, 0x22, 0xa4, 0x70, 0x3b, 0x49, 0xe1, 0x7c, 0xe1, 0x6a, 0xc4, 0x49, 0x3d, 0x82, 0xb7, 0x5f,
+	0xe4, 0xd0, 0xfa, 0x28, 0x49, 0xeb, 0x42, 0x5a, 0x9a, 0x7c, 0x62, 0x7f, 0xad, 0xc0, 0xe9, 0x54,
+	0x17, 0xb9, 0x95, 0xd0, 0xef, 0x0c, 0xa1, 0x7e, 0x2c, 0x4d, 0x7b, 0x50, 0xd1, 0x1c, 0x07, 0xbd,
+	0xf3, 0xd0, 0xce, 0x29, 0xb9, 0x26, 0x1e, 0xb1, 0x98, 0xa1, 0x08, 0x2d, 0x20, 0xf9, 0x04, 0xea,
+	0xbe, 0x39, 0xb4, 0xf5, 0x60, 0x2c, 0x24, 0xca, 0x8e, 0xea, 0xca, 0x7e, 0x2d, 0x42, 0x55, 0x3f,
+	0x86, 0x7a, 0x48, 0xad, 0xc0, 0x7a, 0x4a, 0xbf, 0xa0, 0x24, 0x7c, 0x0a, 0xf4, 0x0b, 0x1e, 0x42,
+	0x3d, 0x24, 0x87, 0xb6, 0x2c, 0xe2, 0xcd, 0xad, 0x42, 0xdd, 0x8f, 0xf7, 0xba, 0xe3, 0x9e, 0x65,
+	0x1a, 0x8f, 0xe8, 0x44, 0xd0, 0x88, 0x1a, 0xd4, 0xbf, 0x51, 0xa0, 0xd1, 0x35, 0x74, 0x5b, 0x5c,
+	0xa6, 0x78, 0x14, 0x5c, 0x76, 0x7b, 0x09, 0x42, 0x02, 0xc2, 0x76, 0x87, 0x2b, 0x54, 0x1c, 0x11,
+	0x27, 0xd4, 0xa4, 0x65, 0x8e, 0xcc, 0x40, 0xda, 0x12, 0x06, 0xa0, 0x2d, 0xf1, 0xe8, 0x01, 0xf5,
+	0x84, 0x93, 0x5a, 0xd3, 0x24, 0x88, 0x93, 0xe9, 0x53, 0xea, 0x0a, 0xcf, 0x87, 0x7d, 0xc7, 0x8e,
+	0xdf, 0x5c, 0xe2, 0xf8, 0x5d, 0x86, 0x7a, 0x78, 0x99, 0x16, 0x09, 0xa6, 0xaa, 0x00, 0xb8, 0x29,
+	0xfc, 0xfb, 0xce, 0xd8, 0x66, 0xe2, 0x18, 0xf8, 0x21, 0x35, 0xc8, 0x00, 0xd5, 0x83, 0xa5, 0x2d,
+	0xdb, 0xb0, 0xc6, 0xe8, 0x41, 0x3f, 0xf5, 0x1c, 0x67, 0x80, 0x31, 0xa8, 0x2e, 0x91, 0x4a, 0x7a,
+	0x6c, 0x43, 0x94, 0xf2, 0x34, 0x5f, 0x8e, 0x34, 0x8f, 0x6d, 0x16, 0xd5, 0xb9, 0x3b, 0xb7, 0xa0,
+	0xb1, 0x6f, 0x6c, 0x73, 0xf5, 0x60, 0xaf, 0x59, 0x6d, 0x97, 0xb1, 0x0d, 0xbf, 0xd5, 0xef, 0x15,
+	0x58, 0xbe, 0xef, 0xd8, 0xbe, 0xe9, 0x07, 0xd4, 0x36, 0x26, 0x9c, 0xed, 0x19, 0xa8, 0xb2, 0x3b,
+	0x48, 0x8a, 0xc7, 0x00, 0x9c, 0x9a, 0x4f, 0x0d, 0xc7, 0xee, 0x0b, 0xee, 0x02, 0x0a, 0x83, 0x60,
+	0x2d, 0x92, 0x21, 0x6a, 0xc0, 0x1b, 0x8e, 0xe3, 0xb1, 0x6e, 0x2e, 0x4e, 0xac, 0x25, 0x57, 0xa8,
+	0x7f, 0x51, 0xa0, 0xca, 0x25, 0x91, 0xd3, 0x50, 0x62, 0xd3, 0x38, 0xba, 0x12, 0xb8, 0xfa, 0x2a,
+	0xa1, 0xfa, 0xae, 0xc0, 0xa2, 0x19, 0x2a, 0x38, 0x62, 0x9a, 0x6c, 0x24, 0xab, 0x70, 0xda, 0x88,
+	0x69, 0x04, 0xf1, 0xe6, 0x18, 0x5e, 0xba, 0x39, 0x71, 0xb3, 0xcf, 0xa7, 0x1c, 0x01, 0x07, 0x4e,
+	0xa3, 0x57, 0x66, 0xfa, 0x81, 0xe3, 0x4d, 0x1e, 0xd8, 0x81, 0x37, 0x39, 0xba, 0x75, 0xbe, 0x0d,
+	0x55, 0x17, 0xa7, 0xdf, 0x2c, 0xe5, 0xda, 0x99, 0xe4, 0x26, 0xd1, 0x38, 0xae, 0xfa, 0xa7, 0x0a,
+	0x2c, 0x45, 0x1c, 0xbf, 0x18, 0x8f, 0xdc, 0x9c, 0x1b, 0xf8, 0x33, 0x0c, 0x1b, 0x02, 0xcf, 0xa4,
+	0xe8, 0xea, 0xe6, 0x19, 0xc3, 0x94, 0xcc, 0x9a, 0x44, 0x47, 0xe1, 0x43, 0xfd, 0x66, 0x85, 0xc7,
+	0xa5, 0x14, 0x67, 0x7e, 0x07, 0x16, 0xbb, 0xfa, 0xc8, 0xb5, 0xa4, 0xe3, 0x8b, 0x2b, 0xe3, 0x9b,
+	0xaf, 0xa4, 0xef, 0xc3, 0xbe, 0x63, 0xc7, 0xa4, 0x94, 0x38, 0xbf, 0x88, 0x4b, 0x69, 0x5f, 0x84,
+	0xfe, 0xec, 0x5b, 0xfd, 0x27, 0x85, 0x1d, 0x30, 0x4e, 0x34, 0xc4, 0x50, 0x22, 0x8c, 0x42, 0x6a,
+	0x18, 0xa5, 0x3a, 0xee, 0xd8, 0xe2, 0xe9, 0x0e, 0x7e, 0xf4, 0x63, 0x2d, 0x71, 0x6d, 0x54, 0x4e,
+	0xa6, 0x8d, 0xea, 0x2c, 0x6d, 0xf4, 0x61, 0xa1, 0x1b, 0x38, 0x9e, 0x3e, 0xa4, 0xdb, 0xf4, 0x80,
+	0x5a, 0xcc, 0x10, 0xe1, 0x87, 0x08, 0xed, 0x38, 0x80, 0x13, 0x08, 0x30, 0x7a, 0x93, 0xa1, 0xba,
+	0x80, 0x08, 0x11, 0x0e, 0x05, 0x17, 0x9d, 0x7d, 0x87, 0xea, 0xac, 0x44, 0xea, 0x54, 0xff, 0xb3,
+	0x0c, 0x8b, 0x82, 0x8d, 0xc8, 0x3e, 0x4c, 0x4b, 0x92, 0x34, 0x61, 0xde, 0xf2, 0x47, 0x5d, 0x24,
+	0xc2, 0xb3, 0x10, 0x12, 0xc4, 0x51, 0x07, 0x96, 0x33, 0x64, 0x5d, 0x7c, 0x09, 0x42, 0x98, 0xdc,
+	0x86, 0x39, 0x26, 0xac, 0xd4, 0xd5, 0xb9, 0xcc, 0xed, 0x17, 0x4d, 0x53, 0x13, 0xa8, 0x3c, 0x4c,
+	0xe5, 0x1a, 0xe6, 0xf9, 0x14, 0x09, 0x62, 0x4c, 0x2e, 0x3e, 0x19, 0x37, 0x9e, 0x50, 0x89, 0x37,
+	0x31, 0x2f, 0xdf, 0xa3, 0x14, 0xe3, 0x46, 0x99, 0xe4, 0x8a, 0x1a, 0x70, 0x6d, 0x11, 0xd8, 0xa6,
+	0xfa, 0x01, 0xcb, 0x74, 0xb1, 0xb5, 0x8d, 0x5a, 0x70, 0x2a, 0x08, 0x31, 0xe2, 0x75, 0x7e, 0x36,
+	0x25, 0x8c, 0xd9, 0x1c, 0x9c, 0xd6, 0xb6, 0x79, 0xc0, 0xfb, 0x81, 0x67, 0x73, 0xe2, 0x6d, 0x68,
+	0x05, 0x10, 0x7e, 0x16, 0x98, 0x96, 0xf9, 0x8a, 0x6f, 0xa0, 0x06, 0xbb, 0xc1, 0xd3, 0xcd, 0x64,
+	0x1d, 0x88, 0xef, 0xea, 0x06, 0xdd, 0x1c, 0xb9, 0x96, 0x39, 0x30, 0x0d, 0x8e, 0xbc, 0xc0, 0x90,
+	0x73, 0x7a, 0x90, 0xb2, 0x47, 0x0d, 0x67, 0x34, 0xa2, 0x76, 0x5f, 0x84, 0x55, 0x8b, 0x2c, 0x51,
+	0x97, 0x6e, 0xc6, 0x5b, 0x8f, 0x3c, 0xa7, 0x5e, 0x38, 0xf4, 0xde, 0xd8, 0xee, 0x5b, 0x14, 0x37,
+	0x5f, 0xb8, 0xae, 0x45, 0x9b, 0x8f, 0x2d, 0xf4, 0xad, 0xf4, 0x69, 0x4f, 0xfb, 0xc2, 0x5d, 0x7d,
+	0x40, 0x99, 0xdd, 0x39, 0xfe, 0x31, 0x7f, 0x01, 0xb0, 0xed, 0x0c, 0x65, 0xbe, 0x24, 0xb1, 0xad,
+	0xeb, 0x72, 0x5b, 0x5f, 0x00, 0x30, 0x9c, 0x91, 0xeb, 0xd8, 0xd4, 0x0e, 0xb8, 0x08, 0x75, 0x2d,
+	0xd6, 0x82, 0xdb, 0x7e, 0xe0, 0x58, 0x96, 0xf3, 0x92, 0xb1, 0xab, 0x69, 0x02, 0x52, 0x0f, 0xa0,
+	0xb6, 0xed, 0x0c, 0xb9, 0xd1, 0xcc, 0xc4, 0x7a, 0xe5, 0x78, 0xac, 0x17, 0xf2, 0x2d, 0xc5, 0xf9,
+	0x62, 0xce, 0x58, 0x72, 0x69, 0x96, 0x45, 0xce, 0x58, 0x36, 0xe0, 0x9e, 0x1c, 0x51, 0x9f, 0xa5,
+	0xdb, 0x78, 0x6a, 0x4a, 0x82, 0xea, 0x37, 0x50, 0x93, 0x1a, 0x39, 0xba, 0xb1, 0x5e, 0x4b, 0x1a,
+	0xeb, 0xb4, 0xaf, 0x9b, 0xb0, 0xd1, 0x3e, 0x10, 0x64, 0xf0, 0xc3, 0xbd, 0xca, 0xe3, 0x30, 0x1d,
+	0xc1, 0x12, 0x63, 0x4a, 0x03, 0x69, 0x91, 0x3f, 0x80, 0xd2, 0xfe, 0xc1, 0x8c, 0xd4, 0x88, 0x56,
+	0xda, 0x3f, 0x20, 0x1b, 0x50, 0xf7, 0xa4, 0xdb, 0x57, 0xc0, 0x8a, 0xf5, 0x69, 0x11, 0x9a, 0xfa,
+	0x1a, 0x96, 0x05, 0xbb, 0xee, 0x73, 0xc9, 0xf0, 0x36, 0x94, 0xfd, 0x90, 0xe3, 0x11, 0x22, 0xab,
+	0xb2, 0x7f, 0x42, 0xe6, 0xcf, 0xf9, 0x5c, 0x1f, 0x46, 0x73, 0xcd, 0xde, 0x81, 0x27, 0xa1, 0xfb,
+	0xaf, 0x0a, 0x2c, 0xf3, 0x8c, 0x91, 0xee, 0xef, 0x15, 0x93, 0x5e, 0x81, 0xfa, 0x81, 0xc4, 0x92,
+	0x4e, 0x6c, 0xd8, 0xc0, 0xa2, 0xa2, 0x30, 0xa0, 0x2d, 0x62, 0xca, 0x51, 0x92, 0x42, 0x56, 0x8e,
+	0x24, 0x24, 0x73, 0xb5, 0x42, 0x5d, 0x0a, 0xd7, 0x35, 0xd6, 0xa2, 0x7e, 0x0d, 0xef, 0x84, 0x73,
+	0x88, 0x9b, 0x15, 0x76, 0x22, 0xf4, 0xc0, 0xd8, 0xa3, 0xbe, 0x4c, 0x26, 0x0a, 0xf0, 0x58, 0xfb,
+	0xec, 0x35, 0x9c, 0x41, 0xdd, 0xa7, 0x13, 0x5f, 0xa4, 0x03, 0x25, 0xcf, 0x69, 0x2a, 0x47, 0xca,
+	0x92, 0x69, 0x25, 0xcf, 0x39, 0xd1, 0x02, 0xdd, 0x83, 0xa5, 0x2f, 0xa9, 0x6e, 0x05, 0x7b, 0x61,
+	0x06, 0x16, 0xdd, 0xd5, 0x40, 0x0f, 0xc6, 0x72, 0x4e, 0x02, 0xc2, 0xc9, 0xa2, 0x8f, 0x2f, 0x5f,
+	0xb9, 0xea, 0x9a, 0x04, 0x55, 0x1b, 0x96, 0x33, 0xc2, 0xaf, 0x40, 0x3d, 0x4a, 0xbe, 0x89, 0xa0,
+	0x25, 0x6c, 0x90, 0x3b, 0xa0, 0x14, 0xed, 0x80, 0x63, 0xac, 0x31, 0x3e, 0x69, 0xb4, 0xee, 0x3b,
+	0x23, 0x57, 0xf7, 0xe8, 0xa6, 0xdd, 0xcf, 0xb0, 0x3e, 0xf2, 0x29, 0x4d, 0xc8, 0x58, 0x4a, 0xcb,
+	0xf8, 0x39, 0x2c, 0xd2, 0x43, 0x97, 0x1a, 0x01, 0xed, 0x6f, 0xcd, 0x94, 0x2c, 0x89, 0xaa, 0xfe,
+	0x42, 0x81, 0x46, 0x2c, 0xf9, 0x89, 0xf3, 0xc5, 0xd8, 0x4a, 0xec, 0x78, 0x0c, 0xac, 0xd6, 0xe2,
+	0xe1, 0x6d, 0x96, 0x6a, 0x17, 0xfb, 0x64, 0xd0, 0x2b, 0xb4, 0x55, 0xce, 0xd1, 0x56, 0x65, 0xb6,
+	0xb6, 0xfe, 0x51, 0x81, 0x85, 0x17, 0xf1, 0x18, 0x30, 0x2b, 0xcc, 0xaf, 0x2a, 0xfa, 0xbb, 0x0a,
+	0x65, 0xf9, 0x02, 0x54, 0x34, 0x25, 0x44, 0x60, 0x78, 0xfa, 0x61, 0x73, 0x6e, 0x2a, 0x9e, 0x7e,
+	0xa8, 0x9e, 0x87, 0x2a, 0x83, 0xa2, 0x64, 0x80, 0x12, 0x4b, 0x06, 0xa8, 0x3f, 0x85, 0x85, 0xad,
+	0xf8, 0xc4, 0xd8, 0x43, 0xc3, 0x90, 0xbb, 0x26, 0x22, 0x61, 0x28, 0x61, 0xe6, 0xd2, 0xea, 0x43,
+	0xfa, 0x64, 0x3c, 0xea, 0x89, 0x67, 0xae, 0x8a, 0x16, 0x6b, 0x51, 0x1f, 0x40, 0xe5, 0x29, 0x3e,
+	0x92, 0x1d, 0x23, 0xad, 0x44, 0xa0, 0x32, 0x42, 0x99, 0xf8, 0x1d, 0xcc, 0xbe, 0xd5, 0x6f, 0xa1,
+	0xda, 0x65, 0x74, 0x4e, 0x92, 0x87, 0xe1, 0x19, 0x58, 0x26, 0x92, 0x90, 0x50, 0x82, 0xb9, 0xbc,
+	0xfe, 0x5d, 0x81, 0x25, 0xe1, 0x65, 0x17, 0x5b, 0xd6, 0xe4, 0xd2, 0x56, 0x4e, 0xbc, 0xb4, 0x18,
+	0xac, 0x7a, 0xce, 0x88, 0x9f, 0x04, 0xee, 0x92, 0x46, 0x0d, 0x38, 0x2e, 0x70, 0x78, 0x1f, 0x77,
+	0x48, 0x25, 0x18, 0xbd, 0xe6, 0xcd, 0xe7, 0xbe, 0xe6, 0xd5, 0xe2, 0x4f, 0x95, 0x2f, 0xe1, 0x34,
+	0x1a, 0xc2, 0xf8, 0xc1, 0xb9, 0x09, 0xd5, 0x57, 0x0e, 0x3e, 0x16, 0x28, 0xb3, 0x1e, 0x18, 0x34,
+	0x8e, 0x78, 0x22, 0x23, 0xf8, 0x7b, 0xfc, 0xea, 0x65, 0x80, 0xe4, 0x9c, 0x9f, 0xac, 0x39, 0x09,
+	0xf5, 0x75, 0xa8, 0x7d, 0x21, 0x43, 0x08, 0x15, 0x16, 0x64, 0x38, 0x61, 0xeb, 0x23, 0x19, 0x62,
+	0x24, 0xda, 0xd4, 0x55, 0x58, 0x7e, 0xe6, 0x53, 0x39, 0x44, 0xa3, 0xae, 0x35, 0xc9, 0x7f, 0x16,
+	0x53, 0xff, 0x5e, 0x81, 0xb3, 0xe2, 0xbd, 0x2f, 0xaa, 0x11, 0x10, 0x9e, 0xe5, 0xa7, 0xfc, 0x85,
+	0xdf, 0xe1, 0x43, 0x96, 0x32, 0x37, 0x48, 0x34, 0x62, 0x93, 0xa1, 0x69, 0x02, 0x1d, 0x4f, 0xd1,
+	0xd8, 0xa7, 0x1e, 0x13, 0x8f, 0x1b, 0xfa, 0x10, 0x4e, 0x44, 0x47, 0xe5, 0xa9, 0x85, 0x10, 0x95,
+	0x4c, 0x21, 0xc4, 0x4f, 0xe1, 0x4c, 0x97, 0x06, 0x9b, 0xac, 0xce, 0x20, 0xfe, 0x8e, 0x19, 0x95,
+	0x22, 0x28, 0xf1, 0x52, 0x84, 0x69, 0x72, 0xa8, 0x8f, 0xe1, 0x8c, 0xd4, 0x0f, 0x66, 0x2a, 0xc3,
+	0xbb, 0xeb, 0x63, 0xa8, 0x4b, 0x79, 0x8a, 0xd2, 0xd8, 0xa1, 0x5e, 0x23, 0x4c, 0xd5, 0xe5, 0x37,
+	0xf0, 0x83, 0x43, 0x6a, 0x6c, 0x5a, 0xd6, 0x6e, 0xb8, 0x07, 0xae, 0x40, 0xd9, 0x71, 0xe5, 0xde,
+	0x23, 0x99, 0xc7, 0x1b, 0x5f, 0xc3, 0xee, 0x13, 0xed, 0x89, 0x5f, 0x2a, 0x30, 0xbf, 0x7b, 0xc8,
+	0x73, 0x35, 0x1f, 0xc2, 0x1c, 0x86, 0x2f, 0x66, 0x30, 0xcd, 0x67, 0x16, 0x28, 0xe4, 0x46, 0x3a,
+	0x34, 0xc9, 0xc5, 0x96, 0x38, 0x91, 0x1f, 0x52, 0x9e, 0xed, 0x87, 0x3c, 0x86, 0xc5, 0x07, 0xf1,
+	0x5b, 0x2c, 0xc7, 0x9a, 0xac, 0xc5, 0x53, 0x48, 0x33, 0xee, 0x9d, 0xef, 0xe2, 0x97, 0xf4, 0x09,
+	0x55, 0xfb, 0x19, 0xd4, 0xe4, 0xc5, 0x2a, 0xa6, 0xbb, 0x92, 0x42, 0x4d, 0x48, 0xac, 0x85, 0xd8,
+	0xea, 0x6f, 0xc3, 0x5b, 0xa1, 0x63, 0xe0, 0x17, 0x9b, 0xc7, 0xe3, 0x4c, 0xa8, 0x0f, 0x8b, 0x21,
+	0x49, 0x16, 0x7f, 0xfc, 0x7a, 0xda, 0xc7, 0x39, 0x82, 0x9f, 0x16, 0x8d, 0xc8, 0xcf, 0xc7, 0xa9,
+	0xf7, 0x63, 0x5c, 0x44, 0x31, 0x49, 0xe2, 0x26, 0x59, 0x29, 0xe2, 0x10, 0xcf, 0xc1, 0x63, 0xda,
+	0x97, 0x27, 0x56, 0xb1, 0xca, 0xa1, 0x38, 0xed, 0x8b, 0x95, 0x36, 0xe6, 0x01, 0x7d, 0x84, 0xb9,
+	0x12, 0xf1, 0x72, 0x27, 0xe1, 0x78, 0x0a, 0xa2, 0x9c, 0x4c, 0x41, 0x88, 0x51, 0xdd, 0x28, 0x9b,
+	0x12, 0xc2, 0xe9, 0xf4, 0x44, 0x35, 0x93, 0x9e, 0xc0, 0xad, 0xff, 0xb6, 0x88, 0x35, 0xee, 0xa1,
+	0xb7, 0x2c, 0x17, 0xe7, 0x88, 0x8f, 0x40, 0x27, 0x39, 0x6e, 0x68, 0x9b, 0x46, 0x63, 0x2b, 0x30,
+	0x9f, 0x86, 0x67, 0xa1, 0xa6, 0xc5, 0x5a, 0xd4, 0x43, 0x58, 0x90, 0x01, 0x2c, 0xd3, 0xf9, 0x8d,
+	0xa4, 0xce, 0x0b, 0xc3, 0x7f, 0x8e, 0x45, 0x7e, 0x92, 0x20, 0xcf, 0x65, 0x4a, 0x57, 0x71, 0x3d,
+	0x0e, 0x11, 0x12, 0x9c, 0xff, 0x4e, 0x01, 0x88, 0xba, 0x32, 0x89, 0xeb, 0x9c, 0xc7, 0x01, 0x5c,
+	0x18, 0xb6, 0x55, 0x28, 0x2f, 0x18, 0xab, 0x68, 0x12, 0xc4, 0x65, 0xb6, 0x78, 0x5e, 0xa7, 0xc2,
+	0x12, 0xaf, 0x02, 0xc2, 0x9d, 0x66, 0xb3, 0x6c, 0x10, 0xcf, 0xdb, 0x72, 0xe0, 0xe8, 0xf9, 0x5a,
+	0x75, 0xc2, 0xef, 0xc7, 0x84, 0x17, 0x79, 0x2b, 0x79, 0x33, 0x9f, 0xcb, 0x3c, 0x0e, 0x45, 0xb8,
+	0x3f, 0xe4, 0x6a, 0x3e, 0xc0, 0xac, 0xe8, 0x80, 0x9e, 0xe8, 0x89, 0xec, 0x87, 0xac, 0x8b, 0x06,
+	0xcb, 0xdd, 0x71, 0xcf, 0x37, 0x3c, 0xb3, 0x17, 0x96, 0x64, 0xe5, 0x7a, 0x57, 0xb9, 0x09, 0xd4,
+	0x33, 0x71, 0xb3, 0x5b, 0x93, 0x06, 0xd6, 0x64, 0xf9, 0x58, 0x7e, 0x61, 0xff, 0xc8, 0x49, 0xed,
+	0x6f, 0x61, 0xe1, 0x21, 0x0d, 0x36, 0xa7, 0x44, 0xf3, 0xf9, 0xaf, 0x01, 0x89, 0x25, 0x2a, 0x1f,
+	0x6d, 0x89, 0x02, 0x58, 0x42, 0x5e, 0xfe, 0xce, 0x60, 0x6a, 0x80, 0x1f, 0x65, 0xa3, 0x4a, 0xe9,
+	0x6c, 0xd4, 0x49, 0xb8, 0xfe, 0x73, 0xe8, 0xfd, 0x9a, 0x86, 0x6e, 0x1d, 0x2f, 0xf5, 0x74, 0x12,
+	0x95, 0x92, 0x47, 0xb0, 0x6c, 0xa4, 0x1e, 0x7c, 0x0a, 0x4a, 0x58, 0xd2, 0xef, 0x42, 0x5a, 0x66,
+	0x20, 0x86, 0xb0, 0x70, 0x4f, 0x37, 0xf6, 0xc7, 0xee, 0x96, 0x3d, 0x70, 0xe2, 0x6e, 0xe1, 0x93,
+	0x1c, 0xb7, 0x10, 0xdb, 0xd0, 0x14, 0x0c, 0x4c, 0x4b, 0xfa, 0x42, 0xec, 0xfb, 0xc8, 0x59, 0xc7,
+	0xa4, 0xfe, 0x2b, 0x69, 0xfd, 0xcb, 0xd4, 0x78, 0x35, 0x96, 0x1a, 0x7f, 0x02, 0x67, 0x34, 0x8a,
+	0xea, 0xa5, 0x5c, 0xce, 0x58, 0x85, 0x17, 0x13, 0x43, 0x89, 0x89, 0x91, 0x16, 0xbf, 0x94, 0x15,
+	0x5f, 0x7d, 0x06, 0x8d, 0x5d, 0xdd, 0xb4, 0xe2, 0x64, 0x3c, 0x67, 0x24, 0x1f, 0x37, 0xf0, 0xfb,
+	0x44, 0xf6, 0xe1, 0x25, 0xd4, 0x90, 0xec, 0x8f, 0x96, 0x7a, 0xe4, 0x47, 0x7c, 0x6c, 0x8b, 0xb7,
+	0x95, 0x9a, 0x26, 0xa0, 0xb5, 0x6b, 0xb0, 0x9c, 0x76, 0xa1, 0x49, 0x1d, 0xaa, 0x0f, 0xb5, 0xcd,
+	0x27, 0xbb, 0xcb, 0xa7, 0x08, 0xc0, 0x9c, 0xf6, 0xe0, 0xf9, 0xce, 0xa3, 0x07, 0xcb, 0xca, 0xc6,
+	0x2f, 0x3f, 0x81, 0xc6, 0xd6, 0x68, 0x34, 0xee, 0x52, 0xef, 0xc0, 0x34, 0x28, 0xd1, 0xa1, 0x8e,
+	0xa6, 0x0c, 0x9d, 0x60, 0x9f, 0xbc, 0xbb, 0xce, 0x6b, 0x9b, 0xd7, 0x65, 0x6d, 0xf3, 0xfa, 0x03,
+	0xac, 0x6d, 0x6e, 0x9d, 0xcd, 0x29, 0xb7, 0xc5, 0x51, 0xea, 0xe5, 0x9f, 0xff, 0xc7, 0xff, 0xfc,
+	0x65, 0xe9, 0x3c, 0x39, 0xd7, 0x39, 0xb8, 0xd5, 0x41, 0x1c, 0x8f, 0xfa, 0x81, 0xeb, 0x39, 0x87,
+	0x93, 0x0e, 0xfa, 0xc7, 0x1d, 0x0b, 0xad, 0xe4, 0x3e, 0x2c, 0x20, 0xb2, 0x28, 0x33, 0x2d, 0xe6,
+	0xd2, 0xca, 0xaf, 0x4b, 0x65, 0x8c, 0x3e, 0x60, 0x8c, 0x2e, 0x91, 0x8b, 0x05, 0x8c, 0x64, 0xe9,
+	0x2a, 0xe9, 0x43, 0xed, 0x21, 0x0d, 0x78, 0x91, 0xe9, 0xb9, 0xdc, 0x12, 0x4c, 0xbe, 0xe8, 0xad,
+	0x56, 0x7e, 0x27, 0x3e, 0xbc, 0xa8, 0x17, 0x19, 0xb7, 0xf7, 0xc8, 0xd9, 0x3c, 0x6e, 0x48, 0xf9,
+	0x10, 0xde, 0x41, 0x33, 0x93, 0x2d, 0xe1, 0x2c, 0x9a, 0x5b, 0x3a, 0x5f, 0x9a, 0x1d, 0xaa, 0x5e,
+	0x61, 0x4c, 0x2f, 0x90, 0x95, 0xa2, 0x29, 0x32, 0x06, 0x26, 0x40, 0x54, 0xf9, 0x49, 0xda, 0xe9,
+	0xd3, 0x9e, 0x2e, 0x0a, 0x6d, 0x15, 0x08, 0xa4, 0x5e, 0x62, 0xdc, 0xce, 0x7d, 0xae, 0xac, 0xa9,
+	0xef, 0xe6, 0x33, 0x24, 0x7f, 0xa2, 0xc0, 0x52, 0xb2, 0x82, 0x93, 0x5c, 0x49, 0xf3, 0xcb, 0x2b,
+	0xf0, 0x2c, 0xe4, 0x79, 0x8b, 0xf1, 0xfc, 0x10, 0x79, 0x5e, 0x2d, 0x98, 0xa4, 0x2c, 0xc6, 0xec,
+	0x18, 0xfc, 0x66, 0x7a, 0x08, 0xcb, 0xcf, 0xdc, 0xbe, 0x1e, 0xd0, 0x58, 0x61, 0x65, 0xfa, 0xd6,
+	0x8c, 0xba, 0x0a, 0x39, 0x9f, 0x8a, 0x08, 0xc5, 0xea, 0x2f, 0x33, 0xd7, 0x6f, 0xd8, 0x35, 0x85,
+	0xd0, 0xe7, 0x50, 0x7f, 0xea, 0x99, 0x76, 0xc0, 0xea, 0x1f, 0x8b, 0x96, 0x3b, 0x7d, 0xde, 0x11,
+	0x59, 0x3d, 0x45, 0xf6, 0xa1, 0xca, 0x2a, 0x4c, 0x33, 0x3b, 0x33, 0x5e, 0xb7, 0xda, 0x5a, 0xc9,
+	0xef, 0xe4, 0x61, 0xa5, 0x38, 0x09, 0x2b, 0xa8, 0xc4, 0x9c, 0xed, 0x69, 0x21, 0xee, 0xf7, 0x9b,
+	0xa5, 0xde, 0x29, 0xf2, 0x35, 0xcc, 0x6d, 0x3b, 0x43, 0x67, 0x1c, 0x14, 0x4a, 0x59, 0x34, 0x49,
+	0x71, 0xaa, 0x91, 0x45, 0x33, 0x97, 0x05, 0x12, 0xfd, 0x0a, 0xca, 0x5d, 0x1a, 0x90, 0xa2, 0xa4,
+	0x66, 0x2b, 0xd7, 0x5a, 0xce, 0xd8, 0x76, 0xcc, 0x20, 0x7e, 0x25, 0x4b, 0x2b, 0x49, 0x8e, 0xdf,
+	0x5d, 0x40, 0x76, 0xba, 0xc4, 0xbc, 0xb8, 0x8c, 0x0c, 0x60, 0x5e, 0x3c, 0x6a, 0x90, 0xf3, 0x39,
+	0x4e, 0x74, 0xf4, 0xb6, 0xd2, 0xca, 0x35, 0xc2, 0xea, 0x55, 0xc6, 0xa4, 0x8d, 0x4c, 0xce, 0xe5,
+	0xcb, 0xde, 0xf1, 0xf5, 0x01, 0x25, 0xbb, 0x50, 0x7e, 0x48, 0x83, 0x5c, 0xe9, 0xf3, 0xee, 0x81,
+	0x69, 0x07, 0x9f, 0x11, 0x7d, 0xbd, 0x4f, 0x27, 0x6f, 0xc8, 0x88, 0x4b, 0xff, 0xb0, 0x40, 0xfa,
+	0xe8, 0xb5, 0xa4, 0x55, 0x14, 0x21, 0xa8, 0x6b, 0x8c, 0xd1, 0x15, 0x9c, 0xc0, 0xc5, 0x29, 0x13,
+	0xe8, 0x0c, 0x69, 0x40, 0xf0, 0x19, 0x4d, 0x04, 0x45, 0xe4, 0x9d, 0xf4, 0x4c, 0x58, 0xad, 0x5d,
+	0xc1, 0x52, 0x4c, 0xd7, 0x52, 0x0f, 0x09, 0x76, 0x7c, 0x1a, 0x10, 0x83, 0x19, 0x6a, 0xce, 0xe0,
+	0xdd, 0xac, 0xaa, 0x18, 0x87, 0xb3, 0x39, 0xea, 0xc2, 0x8e, 0x23, 0x31, 0xc1, 0x59, 0x7c, 0xc7,
+	0x63, 0xa9, 0x90, 0x91, 0x9a, 0xaf, 0xb9, 0x78, 0xec, 0xd7, 0x3a, 0x57, 0xa0, 0x3e, 0xc6, 0xf8,
+	0x43, 0xc6, 0xf8, 0x7d, 0x64, 0xdc, 0x2e, 0x9c, 0x9d, 0xd4, 0x21, 0x05, 0x10, 0xb9, 0x06, 0x2c,
+	0xc2, 0xcd, 0x49, 0x2c, 0x14, 0xa8, 0xf0, 0x06, 0x63, 0xf2, 0x01, 0x32, 0x51, 0x8b, 0x98, 0xe8,
+	0x81, 0x33, 0x32, 0x0d, 0xa1, 0xc9, 0x7a, 0x98, 0xd2, 0x38, 0x06, 0x97, 0xeb, 0x8c, 0xcb, 0x55,
+	0xe4, 0x72, 0x69, 0x06, 0x97, 0xe0, 0x90, 0xfc, 0x21, 0x8f, 0x7d, 0x22, 0x46, 0x97, 0x73, 0xd4,
+	0x94, 0xce, 0xac, 0xb4, 0xd2, 0x0b, 0x2b, 0xd2, 0x4c, 0xea, 0x4d, 0xc6, 0x7b, 0x0d, 0x79, 0xbf,
+	0x3f, 0x6b, 0x86, 0xfa, 0x80, 0x06, 0x87, 0xe4, 0x2f, 0x14, 0x78, 0x3b, 0x27, 0x85, 0x43, 0xae,
+	0x65, 0xfc, 0xdd, 0xa2, 0x34, 0x4f, 0x81, 0x1a, 0x3e, 0x62, 0xa2, 0xac, 0xa3, 0x28, 0xd7, 0x66,
+	0xaa, 0xa1, 0x63, 0x70, 0xf2, 0xc4, 0x80, 0x0a, 0x06, 0x95, 0x24, 0xe3, 0xb3, 0x44, 0x91, 0xe6,
+	0x49, 0x77, 0x2f, 0x3f, 0x87, 0x48, 0x7c, 0x1f, 0xaa, 0xbc, 0xd4, 0xac, 0xb0, 0xee, 0xbb, 0xf5,
+	0x5e, 0x0e, 0x0f, 0x5e, 0x9f, 0x26, 0x77, 0x11, 0x79, 0xbf, 0x80, 0x05, 0xab, 0x57, 0xeb, 0xbc,
+	0xe6, 0x51, 0xe2, 0x1b, 0x32, 0x80, 0x1a, 0x1b, 0xb7, 0x69, 0x59, 0x85, 0x17, 0xc6, 0x14, 0x6e,
+	0x53, 0x1c, 0xb4, 0x88, 0x9b, 0x6e, 0x59, 0x64, 0x00, 0x55, 0x9e, 0x07, 0x2a, 0x9e, 0x54, 0x2b,
+	0x63, 0x7e, 0xc3, 0xec, 0x91, 0xe4, 0x83, 0xba, 0x2b, 0xb2, 0x97, 0x3e, 0x23, 0xff, 0x0d, 0x34,
+	0xee, 0xf3, 0x42, 0x4c, 0x56, 0xa2, 0x76, 0xd4, 0x9b, 0x1a, 0x91, 0xc5, 0x75, 0xd2, 0x24, 0x39,
+	0x57, 0x14, 0x7a, 0xfb, 0xfc, 0x7e, 0xf5, 0xa0, 0x1e, 0x06, 0x67, 0x24, 0x77, 0x6f, 0xb5, 0xa6,
+	0x07, 0x73, 0xf2, 0x14, 0x90, 0xd5, 0x9c, 0x89, 0x48, 0x4c, 0xe6, 0xf8, 0x77, 0x5e, 0xb3, 0x88,
+	0xf8, 0x0d, 0x39, 0x84, 0x46, 0x2c, 0xa0, 0x2b, 0xe0, 0x3a, 0x2b, 0x04, 0x54, 0x37, 0x18, 0xdf,
+	0xeb, 0x64, 0x2d, 0xcb, 0x37, 0x16, 0x1c, 0x26, 0x39, 0xf7, 0x60, 0xfe, 0xde, 0x44, 0x3c, 0xa3,
+	0xe4, 0x72, 0xcd, 0xbd, 0xda, 0x84, 0x8d, 0x21, 0x57, 0x0a, 0x96, 0x8a, 0x11, 0x0f, 0x79, 0xbc,
+	0x82, 0xc6, 0xbd, 0x49, 0xf8, 0xf8, 0x41, 0x2e, 0xe6, 0x19, 0xe2, 0xd8, 0xb3, 0x48, 0xf1, 0x45,
+	0x27, 0x1c, 0x4d, 0x72, 0x6d, 0xda, 0x2d, 0x97, 0xe4, 0xfd, 0x1a, 0x16, 0xf1, 0x22, 0x98, 0x84,
+	0x3f, 0x10, 0xc8, 0x10, 0x17, 0x1d, 0xad, 0xf3, 0x05, 0x1d, 0xfc, 0x97, 0x02, 0xd3, 0x94, 0xcb,
+	0x79, 0x0b, 0xf4, 0xce, 0x6b, 0xf9, 0xf5, 0x86, 0x0c, 0x61, 0x5e, 0x3c, 0x9e, 0x65, 0xee, 0xf6,
+	0xe4, 0xa3, 0x5a, 0xb1, 0x4d, 0x11, 0x4e, 0x04, 0x9e, 0x8b, 0xf7, 0xb2, 0x9c, 0xf7, 0x04, 0x75,
+	0x1b, 0x96, 0xb0, 0xa8, 0x30, 0x2a, 0x89, 0xcb, 0xf5, 0x52, 0xce, 0x17, 0x56, 0xd0, 0xe1, 0x60,
+	0xf5, 0x1a, 0x63, 0x75, 0x19, 0x59, 0x5d, 0x28, 0x64, 0xd5, 0xe9, 0x63, 0xf1, 0xa2, 0x05, 0x55,
+	0x96, 0xfa, 0xc9, 0x38, 0xbc, 0xf1, 0x84, 0x50, 0x2b, 0x7f, 0xce, 0x32, 0x95, 0x32, 0xe3, 0xc8,
+	0x4b, 0x7e, 0x7a, 0x40, 0x3c, 0x98, 0x17, 0xc9, 0x9f, 0x8c, 0x1a, 0x93, 0x49, 0xa1, 0x59, 0x1c,
+	0x8f, 0x36, 0x43, 0xdd, 0x77, 0x06, 0xe4, 0xcf, 0x15, 0x38, 0xcd, 0xea, 0x30, 0x26, 0x61, 0x59,
+	0x46, 0x66, 0xe3, 0xa6, 0x8b, 0x4e, 0x5a, 0x57, 0x8a, 0x10, 0xe2, 0x15, 0x1d, 0x33, 0xdc, 0x00,
+	0xb6, 0x99, 0x0e, 0x18, 0xe7, 0x0e, 0xfb, 0xa5, 0xa0, 0x09, 0xc0, 0xcb, 0x2b, 0x59, 0xc6, 0x7c,
+	0x25, 0x73, 0x36, 0x62, 0xe5, 0x9c, 0xad, 0x1c, 0xdb, 0xcb, 0x11, 0x66, 0x78, 0xd2, 0x3e, 0x43,
+	0x22, 0x06, 0x2c, 0xf0, 0x5f, 0x1b, 0x89, 0x82, 0xe9, 0x62, 0x53, 0x7e, 0x12, 0x77, 0x7d, 0xc0,
+	0x48, 0x13, 0x17, 0x96, 0x36, 0x6d, 0xdd, 0x9a, 0xbc, 0xa2, 0xa2, 0x2a, 0xb1, 0xd0, 0x86, 0xaf,
+	0xe4, 0x57, 0x31, 0x8a, 0x60, 0x7e, 0x95, 0x31, 0x53, 0x49, 0x8e, 0xbf, 0xe6, 0x73, 0xc4, 0x8e,
+	0xc7, 0x30, 0xc9, 0xcf, 0x60, 0x8e, 0xe7, 0x97, 0x8e, 0x7c, 0x01, 0x46, 0x69, 0xb3, 0x19, 0x73,
+	0xea, 0x71, 0xba, 0xdf, 0xe1, 0x83, 0x4a, 0x2c, 0x91, 0x95, 0xf1, 0xa2, 0xf2, 0xd2, 0x5c, 0xd3,
+	0xb8, 0xce, 0xf2, 0x47, 0x11, 0xb1, 0xe3, 0x71, 0xa2, 0xc4, 0x86, 0x39, 0x5e, 0x5f, 0x53, 0x38,
+	0xbf, 0xcc, 0xb9, 0x48, 0x94, 0xe3, 0xa8, 0x37, 0x8a, 0x55, 0xb9, 0xc7, 0x30, 0x3d, 0x81, 0xc9,
+	0x6f, 0xc8, 0x6f, 0xa1, 0x1e, 0xbe, 0x08, 0x91, 0x59, 0xaf, 0x51, 0x27, 0x0a, 0x27, 0xa2, 0x07,
+	0xac, 0x3f, 0x4b, 0xf8, 0x87, 0x11, 0xdb, 0x62, 0xff, 0xf0, 0x88, 0x02, 0xac, 0x33, 0x01, 0x56,
+	0x51, 0x80, 0xcb, 0x53, 0x04, 0x08, 0x3d, 0xc3, 0x1e, 0xcb, 0x76, 0x47, 0x02, 0x1c, 0x39, 0x0c,
+	0x14, 0x46, 0x87, 0x5c, 0x9a, 0xc6, 0x85, 0xc7, 0x82, 0x83, 0xc4, 0xef, 0x09, 0x8f, 0x11, 0x27,
+	0x4f, 0x37, 0x29, 0x11, 0x1b, 0x11, 0x31, 0x1f, 0xc2, 0x62, 0x7c, 0x2e, 0x7e, 0x26, 0xdf, 0x94,
+	0x79, 0xd6, 0x6c, 0x15, 0x3e, 0x09, 0xc6, 0xd3, 0x78, 0x05, 0xa6, 0xdc, 0x8b, 0x18, 0xbd, 0xe4,
+	0xe1, 0x46, 0xa4, 0xc6, 0xbc, 0x70, 0x63, 0xe6, 0x0a, 0x72, 0x77, 0x67, 0xfa, 0x19, 0x61, 0xbe,
+	0x40, 0xa4, 0xcb, 0xdf, 0x85, 0x0a, 0x16, 0x72, 0x90, 0x29, 0xd5, 0x1d, 0x27, 0x4a, 0x6d, 0xbc,
+	0xd2, 0xfb, 0x7d, 0xd2, 0x83, 0x2a, 0x7b, 0x8b, 0x22, 0xd3, 0x5e, 0xa8, 0x5a, 0xcd, 0xbc, 0x67,
+	0x24, 0xa6, 0x3e, 0x75, 0x6a, 0xee, 0xe7, 0x15, 0x0b, 0x1a, 0x7c, 0xa8, 0x87, 0xef, 0x63, 0xb9,
+	0x2e, 0x54, 0x82, 0xd7, 0x4a, 0x1e, 0x42, 0xc8, 0x6f, 0xfa, 0x72, 0x31, 0xcd, 0x71, 0xa6, 0x7b,
+	0xbc, 0xe8, 0x96, 0x69, 0xee, 0x42, 0x1e, 0xc9, 0x29, 0xda, 0x3b, 0x4a, 0x72, 0x85, 0xb3, 0x42,
+	0x15, 0x7e, 0x0d, 0xd5, 0xad, 0x5c, 0x15, 0xc6, 0xab, 0xaf, 0x32, 0x07, 0x0c, 0xcb, 0xa0, 0x66,
+	0x68, 0xcf, 0x64, 0x13, 0xd9, 0x81, 0x0a, 0xfb, 0xd5, 0x45, 0x91, 0x81, 0x84, 0x75, 0xb7, 0x27,
+	0xf2, 0x1f, 0x33, 0x16, 0x1c, 0xfd, 0x9f, 0x9b, 0x0a, 0xf9, 0x06, 0x2a, 0xdb, 0xce, 0xd0, 0xcf,
+	0xe4, 0x1a, 0xa3, 0xba, 0xeb, 0x8c, 0x4f, 0x27, 0xcb, 0xa6, 0x67, 0x30, 0xb0, 0x9c, 0xa1, 0x7f,
+	0x53, 0x21, 0x2e, 0xd4, 0xc3, 0xc7, 0xc1, 0xec, 0x7a, 0xa7, 0x9e, 0x0d, 0xf3, 0x2e, 0x7e, 0x9e,
+	0xc3, 0x9d, 0xb5, 0x00, 0x92, 0xd0, 0x4d, 0x85, 0xfc, 0x0c, 0x2a, 0xf8, 0xcc, 0x91, 0x39, 0x22,
+	0xb1, 0x27, 0x95, 0xd6, 0xd9, 0x9c, 0x3e, 0x66, 0xe5, 0xa6, 0xcf, 0x29, 0xd0, 0x4d, 0xeb, 0xa6,
+	0x82, 0x6e, 0x2a, 0xcf, 0x64, 0x87, 0xb5, 0x4a, 0x45, 0x95, 0x33, 0x85, 0x39, 0xcc, 0xe9, 0x87,
+	0x3e, 0xfc, 0xe7, 0x23, 0x9c, 0xfa, 0x1b, 0xf6, 0xdf, 0x0c, 0x66, 0x33, 0xbb, 0x98, 0x7d, 0x07,
+	0x49, 0x94, 0x46, 0xc9, 0x64, 0x02, 0xb9, 0x9e, 0x9b, 0xde, 0x96, 0xfc, 0x3a, 0xaf, 0xe3, 0x35,
+	0x56, 0x6f, 0x30, 0xd1, 0xbe, 0x9c, 0x2e, 0x9d, 0x22, 0x57, 0xf3, 0x53, 0xed, 0xe9, 0xda, 0xaa,
+	0x42, 0x05, 0x4c, 0x37, 0xf5, 0x3c, 0xbd, 0x1e, 0xfb, 0x67, 0x0f, 0x6f, 0x60, 0x31, 0x51, 0x11,
+	0x95, 0x35, 0xb8, 0x39, 0xf5, 0x52, 0x85, 0xcc, 0x3b, 0x8c, 0xf9, 0x35, 0x64, 0x7e, 0xa5, 0xf0,
+	0xc5, 0x26, 0xd0, 0x23, 0x6e, 0xaf, 0x61, 0x21, 0x5e, 0x44, 0x55, 0x78, 0xfe, 0x2e, 0x17, 0x2c,
+	0x4d, 0xbc, 0xf2, 0x6a, 0xc6, 0x95, 0xcd, 0xb8, 0xcb, 0x05, 0xc0, 0x07, 0xaa, 0x7b, 0xbf, 0x28,
+	0xbf, 0xf8, 0x70, 0x68, 0x06, 0x7b, 0xe3, 0xde, 0xba, 0xe1, 0x60, 0xaa, 0xa2, 0x4f, 0x6d, 0x27,
+	0xd0, 0xbd, 0x49, 0x87, 0x33, 0xeb, 0xb8, 0xfb, 0x43, 0xf6, 0xcf, 0x80, 0x38, 0xd3, 0xef, 0x37,
+	0xff, 0xab, 0x44, 0xfe, 0x57, 0x81, 0xd3, 0xbc, 0xb7, 0xad, 0x3d, 0xe8, 0xee, 0xb6, 0x37, 0x9f,
+	0x6e, 0x91, 0xff, 0x56, 0xee, 0xf4, 0xee, 0x6e, 0x3d, 0x7e, 0xba, 0xa3, 0xed, 0x6e, 0x3e, 0xd9,
+	0xbd, 0xd3, 0xe9, 0xdd, 0xfd, 0xbc, 0xbd, 0x69, 0x59, 0xed, 0x3b, 0x48, 0xf1, 0xee, 0x90, 0x06,
+	0x77, 0x18, 0xed, 0xbb, 0x6d, 0xdd, 0xee, 0x8b, 0x46, 0x34, 0x6c, 0xb1, 0x8e, 0xc1, 0xd8, 0x66,
+	0xaf, 0x77, 0x7e, 0xdb, 0xa3, 0xc1, 0xd8, 0xb3, 0xdb, 0x77, 0xc6, 0x77, 0x51, 0xcc, 0x4f, 0x3e,
+	0xba, 0x41, 0x6d, 0x44, 0xe9, 0xdf, 0xe9, 0x8c, 0xef, 0xb6, 0xb1, 0xf6, 0x84, 0x11, 0x61, 0x75,
+	0xe9, 0xfe, 0xf5, 0xf6, 0xcb, 0x3d, 0xd3, 0xa2, 0x6d, 0x3d, 0xe4, 0xe5, 0x17, 0xf1, 0xf2, 0xf3,
+	0x78, 0xf1, 0x42, 0xa5, 0x02, 0x5e, 0xa6, 0xed, 0x8e, 0x03, 0x7f, 0xfd, 0xc5, 0xef, 0xc0, 0x57,
+	0x30, 0xd7, 0xa3, 0xba, 0x47, 0x3d, 0xf2, 0xb8, 0x56, 0x22, 0x9f, 0xe1, 0xb3, 0x0b, 0xb5, 0x03,
+	0x11, 0xad, 0xb4, 0x59, 0x15, 0xe0, 0xf5, 0x36, 0x4f, 0x27, 0xd1, 0x7e, 0xbb, 0x37, 0x69, 0xdf,
+	0x63, 0xd8, 0x9f, 0x8b, 0xbf, 0xed, 0x3b, 0x0c, 0xe5, 0x6e, 0x6b, 0x11, 0x47, 0x3a, 0x9e, 0xf8,
+	0xe9, 0x4d, 0xbb, 0xd4, 0x03, 0xa8, 0x49, 0xd2, 0xbd, 0x39, 0xb6, 0xe0, 0xb7, 0xff, 0x7f, 0x00,
+	0x92, 0xff, 0xe8, 0x3b, 0xa1, 0x49, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3445,6 +6206,9 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type ImmuServiceClient interface {
 	ListUsers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*UserList, error)
+	ListSessions(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*SessionList, error)
+	GetUsage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageReport, error)
+	GetAccessControlState(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AccessControlState, error)
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
 	UpdateAuthConfig(ctx context.Context, in *AuthConfig, opts ...grpc.CallOption) (*empty.Empty, error)
@@ -3453,30 +6217,53 @@ type ImmuServiceClient interface {
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	Logout(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
 	Set(ctx context.Context, in *KeyValue, opts ...grpc.CallOption) (*Index, error)
+	Delete(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Index, error)
 	SafeSet(ctx context.Context, in *SafeSetOptions, opts ...grpc.CallOption) (*Proof, error)
 	Get(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Item, error)
 	SafeGet(ctx context.Context, in *SafeGetOptions, opts ...grpc.CallOption) (*SafeItem, error)
 	SetBatch(ctx context.Context, in *KVList, opts ...grpc.CallOption) (*Index, error)
 	GetBatch(ctx context.Context, in *KeyList, opts ...grpc.CallOption) (*ItemList, error)
+	SafeGetBatch(ctx context.Context, in *SafeGetBatchOptions, opts ...grpc.CallOption) (*SafeItemList, error)
 	ExecAllOps(ctx context.Context, in *Ops, opts ...grpc.CallOption) (*Index, error)
+	ExecAllTx(ctx context.Context, in *Ops, opts ...grpc.CallOption) (*Index, error)
+	SafeExecAllTx(ctx context.Context, in *SafeExecAllTxOptions, opts ...grpc.CallOption) (*TxProof, error)
+	CompareAndExecAllTx(ctx context.Context, in *CompareAndExecAllTxOptions, opts ...grpc.CallOption) (*Index, error)
 	Scan(ctx context.Context, in *ScanOptions, opts ...grpc.CallOption) (*ItemList, error)
 	Count(ctx context.Context, in *KeyPrefix, opts ...grpc.CallOption) (*ItemsCount, error)
 	CountAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*ItemsCount, error)
+	Stats(ctx context.Context, in *KeyPrefix, opts ...grpc.CallOption) (*PrefixStats, error)
 	CurrentRoot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Root, error)
 	Inclusion(ctx context.Context, in *Index, opts ...grpc.CallOption) (*InclusionProof, error)
 	Consistency(ctx context.Context, in *Index, opts ...grpc.CallOption) (*ConsistencyProof, error)
 	ByIndex(ctx context.Context, in *Index, opts ...grpc.CallOption) (*Item, error)
 	BySafeIndex(ctx context.Context, in *SafeIndexOptions, opts ...grpc.CallOption) (*SafeItem, error)
+	GetBySequence(ctx context.Context, in *Sequence, opts ...grpc.CallOption) (*SequencedWrite, error)
 	History(ctx context.Context, in *HistoryOptions, opts ...grpc.CallOption) (*ItemList, error)
+	DumpKeyHistory(ctx context.Context, in *Key, opts ...grpc.CallOption) (*KeyHistoryDump, error)
+	GetAt(ctx context.Context, in *GetAtOptions, opts ...grpc.CallOption) (*HistoricalItem, error)
+	GetAsOf(ctx context.Context, in *GetAsOfOptions, opts ...grpc.CallOption) (*HistoricalItem, error)
+	VerifyValueHash(ctx context.Context, in *ValueHashOptions, opts ...grpc.CallOption) (*ValueHashVerification, error)
+	SampleKeys(ctx context.Context, in *SampleOptions, opts ...grpc.CallOption) (*KeySample, error)
+	FreezePrefix(ctx context.Context, in *KeyPrefix, opts ...grpc.CallOption) (*Index, error)
+	AnalyzeStorage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StorageReport, error)
+	Backup(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BackupInfo, error)
+	RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*BackupInfo, error)
 	Health(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*HealthResponse, error)
 	Reference(ctx context.Context, in *ReferenceOptions, opts ...grpc.CallOption) (*Index, error)
+	CompareAndReference(ctx context.Context, in *CompareAndReferenceOptions, opts ...grpc.CallOption) (*Index, error)
 	GetReference(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Item, error)
+	Unreference(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Index, error)
+	GetReferences(ctx context.Context, in *ReferencesOptions, opts ...grpc.CallOption) (*ReferenceList, error)
 	SafeReference(ctx context.Context, in *SafeReferenceOptions, opts ...grpc.CallOption) (*Proof, error)
 	ZAdd(ctx context.Context, in *ZAddOptions, opts ...grpc.CallOption) (*Index, error)
 	ZScan(ctx context.Context, in *ZScanOptions, opts ...grpc.CallOption) (*ZItemList, error)
+	SafeZScan(ctx context.Context, in *SafeZScanOptions, opts ...grpc.CallOption) (*SafeZItemList, error)
 	SafeZAdd(ctx context.Context, in *SafeZAddOptions, opts ...grpc.CallOption) (*Proof, error)
 	IScan(ctx context.Context, in *IScanOptions, opts ...grpc.CallOption) (*Page, error)
 	Dump(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (ImmuService_DumpClient, error)
+	Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (ImmuService_LogsClient, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (ImmuService_SubscribeClient, error)
+	Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (ImmuService_TailClient, error)
 	// todo(joe-dz): Enable restore when the feature is required again
 	//	rpc Restore(stream pb.KVList) returns (ItemsCount) {
 	//		option (google.api.http) = {
@@ -3508,6 +6295,33 @@ func (c *immuServiceClient) ListUsers(ctx context.Context, in *empty.Empty, opts
 	return out, nil
 }
 
+func (c *immuServiceClient) ListSessions(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*SessionList, error) {
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) GetUsage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageReport, error) {
+	out := new(UsageReport)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) GetAccessControlState(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*AccessControlState, error) {
+	out := new(AccessControlState)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetAccessControlState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
 	out := new(empty.Empty)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/CreateUser", in, out, opts...)
@@ -3580,6 +6394,15 @@ func (c *immuServiceClient) Set(ctx context.Context, in *KeyValue, opts ...grpc.
 	return out, nil
 }
 
+func (c *immuServiceClient) Delete(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Index, error) {
+	out := new(Index)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) SafeSet(ctx context.Context, in *SafeSetOptions, opts ...grpc.CallOption) (*Proof, error) {
 	out := new(Proof)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SafeSet", in, out, opts...)
@@ -3625,6 +6448,15 @@ func (c *immuServiceClient) GetBatch(ctx context.Context, in *KeyList, opts ...g
 	return out, nil
 }
 
+func (c *immuServiceClient) SafeGetBatch(ctx context.Context, in *SafeGetBatchOptions, opts ...grpc.CallOption) (*SafeItemList, error) {
+	out := new(SafeItemList)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SafeGetBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) ExecAllOps(ctx context.Context, in *Ops, opts ...grpc.CallOption) (*Index, error) {
 	out := new(Index)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/ExecAllOps", in, out, opts...)
@@ -3634,6 +6466,33 @@ func (c *immuServiceClient) ExecAllOps(ctx context.Context, in *Ops, opts ...grp
 	return out, nil
 }
 
+func (c *immuServiceClient) ExecAllTx(ctx context.Context, in *Ops, opts ...grpc.CallOption) (*Index, error) {
+	out := new(Index)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/ExecAllTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) SafeExecAllTx(ctx context.Context, in *SafeExecAllTxOptions, opts ...grpc.CallOption) (*TxProof, error) {
+	out := new(TxProof)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SafeExecAllTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) CompareAndExecAllTx(ctx context.Context, in *CompareAndExecAllTxOptions, opts ...grpc.CallOption) (*Index, error) {
+	out := new(Index)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/CompareAndExecAllTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) Scan(ctx context.Context, in *ScanOptions, opts ...grpc.CallOption) (*ItemList, error) {
 	out := new(ItemList)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/Scan", in, out, opts...)
@@ -3661,6 +6520,15 @@ func (c *immuServiceClient) CountAll(ctx context.Context, in *empty.Empty, opts
 	return out, nil
 }
 
+func (c *immuServiceClient) Stats(ctx context.Context, in *KeyPrefix, opts ...grpc.CallOption) (*PrefixStats, error) {
+	out := new(PrefixStats)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) CurrentRoot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*Root, error) {
 	out := new(Root)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/CurrentRoot", in, out, opts...)
@@ -3706,6 +6574,15 @@ func (c *immuServiceClient) BySafeIndex(ctx context.Context, in *SafeIndexOption
 	return out, nil
 }
 
+func (c *immuServiceClient) GetBySequence(ctx context.Context, in *Sequence, opts ...grpc.CallOption) (*SequencedWrite, error) {
+	out := new(SequencedWrite)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetBySequence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) History(ctx context.Context, in *HistoryOptions, opts ...grpc.CallOption) (*ItemList, error) {
 	out := new(ItemList)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/History", in, out, opts...)
@@ -3715,6 +6592,87 @@ func (c *immuServiceClient) History(ctx context.Context, in *HistoryOptions, opt
 	return out, nil
 }
 
+func (c *immuServiceClient) DumpKeyHistory(ctx context.Context, in *Key, opts ...grpc.CallOption) (*KeyHistoryDump, error) {
+	out := new(KeyHistoryDump)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/DumpKeyHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) GetAt(ctx context.Context, in *GetAtOptions, opts ...grpc.CallOption) (*HistoricalItem, error) {
+	out := new(HistoricalItem)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) GetAsOf(ctx context.Context, in *GetAsOfOptions, opts ...grpc.CallOption) (*HistoricalItem, error) {
+	out := new(HistoricalItem)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetAsOf", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) VerifyValueHash(ctx context.Context, in *ValueHashOptions, opts ...grpc.CallOption) (*ValueHashVerification, error) {
+	out := new(ValueHashVerification)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/VerifyValueHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) SampleKeys(ctx context.Context, in *SampleOptions, opts ...grpc.CallOption) (*KeySample, error) {
+	out := new(KeySample)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SampleKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) FreezePrefix(ctx context.Context, in *KeyPrefix, opts ...grpc.CallOption) (*Index, error) {
+	out := new(Index)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/FreezePrefix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) AnalyzeStorage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*StorageReport, error) {
+	out := new(StorageReport)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/AnalyzeStorage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) Backup(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*BackupInfo, error) {
+	out := new(BackupInfo)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/Backup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) RestoreBackup(ctx context.Context, in *RestoreBackupRequest, opts ...grpc.CallOption) (*BackupInfo, error) {
+	out := new(BackupInfo)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/RestoreBackup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) Health(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*HealthResponse, error) {
 	out := new(HealthResponse)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/Health", in, out, opts...)
@@ -3733,6 +6691,15 @@ func (c *immuServiceClient) Reference(ctx context.Context, in *ReferenceOptions,
 	return out, nil
 }
 
+func (c *immuServiceClient) CompareAndReference(ctx context.Context, in *CompareAndReferenceOptions, opts ...grpc.CallOption) (*Index, error) {
+	out := new(Index)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/CompareAndReference", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) GetReference(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Item, error) {
 	out := new(Item)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetReference", in, out, opts...)
@@ -3742,6 +6709,24 @@ func (c *immuServiceClient) GetReference(ctx context.Context, in *Key, opts ...g
 	return out, nil
 }
 
+func (c *immuServiceClient) Unreference(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Index, error) {
+	out := new(Index)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/Unreference", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) GetReferences(ctx context.Context, in *ReferencesOptions, opts ...grpc.CallOption) (*ReferenceList, error) {
+	out := new(ReferenceList)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/GetReferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) SafeReference(ctx context.Context, in *SafeReferenceOptions, opts ...grpc.CallOption) (*Proof, error) {
 	out := new(Proof)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SafeReference", in, out, opts...)
@@ -3769,30 +6754,135 @@ func (c *immuServiceClient) ZScan(ctx context.Context, in *ZScanOptions, opts ..
 	return out, nil
 }
 
+func (c *immuServiceClient) SafeZScan(ctx context.Context, in *SafeZScanOptions, opts ...grpc.CallOption) (*SafeZItemList, error) {
+	out := new(SafeZItemList)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SafeZScan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *immuServiceClient) SafeZAdd(ctx context.Context, in *SafeZAddOptions, opts ...grpc.CallOption) (*Proof, error) {
 	out := new(Proof)
 	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/SafeZAdd", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *immuServiceClient) IScan(ctx context.Context, in *IScanOptions, opts ...grpc.CallOption) (*Page, error) {
+	out := new(Page)
+	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/IScan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *immuServiceClient) Dump(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (ImmuService_DumpClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ImmuService_serviceDesc.Streams[0], "/immudb.schema.ImmuService/Dump", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &immuServiceDumpClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ImmuService_DumpClient interface {
+	Recv() (*pb.KVList, error)
+	grpc.ClientStream
+}
+
+type immuServiceDumpClient struct {
+	grpc.ClientStream
+}
+
+func (x *immuServiceDumpClient) Recv() (*pb.KVList, error) {
+	m := new(pb.KVList)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *immuServiceClient) Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (ImmuService_LogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ImmuService_serviceDesc.Streams[1], "/immudb.schema.ImmuService/Logs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &immuServiceLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ImmuService_LogsClient interface {
+	Recv() (*LogEntry, error)
+	grpc.ClientStream
+}
+
+type immuServiceLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *immuServiceLogsClient) Recv() (*LogEntry, error) {
+	m := new(LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *immuServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (ImmuService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ImmuService_serviceDesc.Streams[2], "/immudb.schema.ImmuService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &immuServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *immuServiceClient) IScan(ctx context.Context, in *IScanOptions, opts ...grpc.CallOption) (*Page, error) {
-	out := new(Page)
-	err := c.cc.Invoke(ctx, "/immudb.schema.ImmuService/IScan", in, out, opts...)
-	if err != nil {
+type ImmuService_SubscribeClient interface {
+	Recv() (*KeyChange, error)
+	grpc.ClientStream
+}
+
+type immuServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *immuServiceSubscribeClient) Recv() (*KeyChange, error) {
+	m := new(KeyChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *immuServiceClient) Dump(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (ImmuService_DumpClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_ImmuService_serviceDesc.Streams[0], "/immudb.schema.ImmuService/Dump", opts...)
+func (c *immuServiceClient) Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (ImmuService_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ImmuService_serviceDesc.Streams[3], "/immudb.schema.ImmuService/Tail", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &immuServiceDumpClient{stream}
+	x := &immuServiceTailClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -3802,17 +6892,17 @@ func (c *immuServiceClient) Dump(ctx context.Context, in *empty.Empty, opts ...g
 	return x, nil
 }
 
-type ImmuService_DumpClient interface {
-	Recv() (*pb.KVList, error)
+type ImmuService_TailClient interface {
+	Recv() (*TailItem, error)
 	grpc.ClientStream
 }
 
-type immuServiceDumpClient struct {
+type immuServiceTailClient struct {
 	grpc.ClientStream
 }
 
-func (x *immuServiceDumpClient) Recv() (*pb.KVList, error) {
-	m := new(pb.KVList)
+func (x *immuServiceTailClient) Recv() (*TailItem, error) {
+	m := new(TailItem)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
@@ -3867,6 +6957,9 @@ func (c *immuServiceClient) DatabaseList(ctx context.Context, in *empty.Empty, o
 // ImmuServiceServer is the server API for ImmuService service.
 type ImmuServiceServer interface {
 	ListUsers(context.Context, *empty.Empty) (*UserList, error)
+	ListSessions(context.Context, *empty.Empty) (*SessionList, error)
+	GetUsage(context.Context, *UsageRequest) (*UsageReport, error)
+	GetAccessControlState(context.Context, *empty.Empty) (*AccessControlState, error)
 	CreateUser(context.Context, *CreateUserRequest) (*empty.Empty, error)
 	ChangePassword(context.Context, *ChangePasswordRequest) (*empty.Empty, error)
 	UpdateAuthConfig(context.Context, *AuthConfig) (*empty.Empty, error)
@@ -3875,30 +6968,53 @@ type ImmuServiceServer interface {
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 	Logout(context.Context, *empty.Empty) (*empty.Empty, error)
 	Set(context.Context, *KeyValue) (*Index, error)
+	Delete(context.Context, *Key) (*Index, error)
 	SafeSet(context.Context, *SafeSetOptions) (*Proof, error)
 	Get(context.Context, *Key) (*Item, error)
 	SafeGet(context.Context, *SafeGetOptions) (*SafeItem, error)
 	SetBatch(context.Context, *KVList) (*Index, error)
 	GetBatch(context.Context, *KeyList) (*ItemList, error)
+	SafeGetBatch(context.Context, *SafeGetBatchOptions) (*SafeItemList, error)
 	ExecAllOps(context.Context, *Ops) (*Index, error)
+	ExecAllTx(context.Context, *Ops) (*Index, error)
+	SafeExecAllTx(context.Context, *SafeExecAllTxOptions) (*TxProof, error)
+	CompareAndExecAllTx(context.Context, *CompareAndExecAllTxOptions) (*Index, error)
 	Scan(context.Context, *ScanOptions) (*ItemList, error)
 	Count(context.Context, *KeyPrefix) (*ItemsCount, error)
 	CountAll(context.Context, *empty.Empty) (*ItemsCount, error)
+	Stats(context.Context, *KeyPrefix) (*PrefixStats, error)
 	CurrentRoot(context.Context, *empty.Empty) (*Root, error)
 	Inclusion(context.Context, *Index) (*InclusionProof, error)
 	Consistency(context.Context, *Index) (*ConsistencyProof, error)
 	ByIndex(context.Context, *Index) (*Item, error)
 	BySafeIndex(context.Context, *SafeIndexOptions) (*SafeItem, error)
+	GetBySequence(context.Context, *Sequence) (*SequencedWrite, error)
 	History(context.Context, *HistoryOptions) (*ItemList, error)
+	DumpKeyHistory(context.Context, *Key) (*KeyHistoryDump, error)
+	GetAt(context.Context, *GetAtOptions) (*HistoricalItem, error)
+	GetAsOf(context.Context, *GetAsOfOptions) (*HistoricalItem, error)
+	VerifyValueHash(context.Context, *ValueHashOptions) (*ValueHashVerification, error)
+	SampleKeys(context.Context, *SampleOptions) (*KeySample, error)
+	FreezePrefix(context.Context, *KeyPrefix) (*Index, error)
+	AnalyzeStorage(context.Context, *empty.Empty) (*StorageReport, error)
+	Backup(context.Context, *empty.Empty) (*BackupInfo, error)
+	RestoreBackup(context.Context, *RestoreBackupRequest) (*BackupInfo, error)
 	Health(context.Context, *empty.Empty) (*HealthResponse, error)
 	Reference(context.Context, *ReferenceOptions) (*Index, error)
+	CompareAndReference(context.Context, *CompareAndReferenceOptions) (*Index, error)
 	GetReference(context.Context, *Key) (*Item, error)
+	Unreference(context.Context, *Key) (*Index, error)
+	GetReferences(context.Context, *ReferencesOptions) (*ReferenceList, error)
 	SafeReference(context.Context, *SafeReferenceOptions) (*Proof, error)
 	ZAdd(context.Context, *ZAddOptions) (*Index, error)
 	ZScan(context.Context, *ZScanOptions) (*ZItemList, error)
+	SafeZScan(context.Context, *SafeZScanOptions) (*SafeZItemList, error)
 	SafeZAdd(context.Context, *SafeZAddOptions) (*Proof, error)
 	IScan(context.Context, *IScanOptions) (*Page, error)
 	Dump(*empty.Empty, ImmuService_DumpServer) error
+	Logs(*LogRequest, ImmuService_LogsServer) error
+	Subscribe(*SubscribeRequest, ImmuService_SubscribeServer) error
+	Tail(*TailRequest, ImmuService_TailServer) error
 	// todo(joe-dz): Enable restore when the feature is required again
 	//	rpc Restore(stream pb.KVList) returns (ItemsCount) {
 	//		option (google.api.http) = {
@@ -3920,6 +7036,15 @@ type UnimplementedImmuServiceServer struct {
 func (*UnimplementedImmuServiceServer) ListUsers(ctx context.Context, req *empty.Empty) (*UserList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
+func (*UnimplementedImmuServiceServer) ListSessions(ctx context.Context, req *empty.Empty) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (*UnimplementedImmuServiceServer) GetUsage(ctx context.Context, req *UsageRequest) (*UsageReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (*UnimplementedImmuServiceServer) GetAccessControlState(ctx context.Context, req *empty.Empty) (*AccessControlState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccessControlState not implemented")
+}
 func (*UnimplementedImmuServiceServer) CreateUser(ctx context.Context, req *CreateUserRequest) (*empty.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
 }
@@ -3944,6 +7069,9 @@ func (*UnimplementedImmuServiceServer) Logout(ctx context.Context, req *empty.Em
 func (*UnimplementedImmuServiceServer) Set(ctx context.Context, req *KeyValue) (*Index, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
 }
+func (*UnimplementedImmuServiceServer) Delete(ctx context.Context, req *Key) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
 func (*UnimplementedImmuServiceServer) SafeSet(ctx context.Context, req *SafeSetOptions) (*Proof, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SafeSet not implemented")
 }
@@ -3959,9 +7087,21 @@ func (*UnimplementedImmuServiceServer) SetBatch(ctx context.Context, req *KVList
 func (*UnimplementedImmuServiceServer) GetBatch(ctx context.Context, req *KeyList) (*ItemList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBatch not implemented")
 }
+func (*UnimplementedImmuServiceServer) SafeGetBatch(ctx context.Context, req *SafeGetBatchOptions) (*SafeItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SafeGetBatch not implemented")
+}
 func (*UnimplementedImmuServiceServer) ExecAllOps(ctx context.Context, req *Ops) (*Index, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecAllOps not implemented")
 }
+func (*UnimplementedImmuServiceServer) ExecAllTx(ctx context.Context, req *Ops) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecAllTx not implemented")
+}
+func (*UnimplementedImmuServiceServer) SafeExecAllTx(ctx context.Context, req *SafeExecAllTxOptions) (*TxProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SafeExecAllTx not implemented")
+}
+func (*UnimplementedImmuServiceServer) CompareAndExecAllTx(ctx context.Context, req *CompareAndExecAllTxOptions) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndExecAllTx not implemented")
+}
 func (*UnimplementedImmuServiceServer) Scan(ctx context.Context, req *ScanOptions) (*ItemList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
 }
@@ -3971,6 +7111,9 @@ func (*UnimplementedImmuServiceServer) Count(ctx context.Context, req *KeyPrefix
 func (*UnimplementedImmuServiceServer) CountAll(ctx context.Context, req *empty.Empty) (*ItemsCount, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CountAll not implemented")
 }
+func (*UnimplementedImmuServiceServer) Stats(ctx context.Context, req *KeyPrefix) (*PrefixStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
 func (*UnimplementedImmuServiceServer) CurrentRoot(ctx context.Context, req *empty.Empty) (*Root, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CurrentRoot not implemented")
 }
@@ -3986,538 +7129,985 @@ func (*UnimplementedImmuServiceServer) ByIndex(ctx context.Context, req *Index)
 func (*UnimplementedImmuServiceServer) BySafeIndex(ctx context.Context, req *SafeIndexOptions) (*SafeItem, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BySafeIndex not implemented")
 }
+func (*UnimplementedImmuServiceServer) GetBySequence(ctx context.Context, req *Sequence) (*SequencedWrite, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBySequence not implemented")
+}
 func (*UnimplementedImmuServiceServer) History(ctx context.Context, req *HistoryOptions) (*ItemList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
 }
+func (*UnimplementedImmuServiceServer) DumpKeyHistory(ctx context.Context, req *Key) (*KeyHistoryDump, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DumpKeyHistory not implemented")
+}
+func (*UnimplementedImmuServiceServer) GetAt(ctx context.Context, req *GetAtOptions) (*HistoricalItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAt not implemented")
+}
+func (*UnimplementedImmuServiceServer) GetAsOf(ctx context.Context, req *GetAsOfOptions) (*HistoricalItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAsOf not implemented")
+}
+func (*UnimplementedImmuServiceServer) VerifyValueHash(ctx context.Context, req *ValueHashOptions) (*ValueHashVerification, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyValueHash not implemented")
+}
+func (*UnimplementedImmuServiceServer) SampleKeys(ctx context.Context, req *SampleOptions) (*KeySample, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SampleKeys not implemented")
+}
+func (*UnimplementedImmuServiceServer) FreezePrefix(ctx context.Context, req *KeyPrefix) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezePrefix not implemented")
+}
+func (*UnimplementedImmuServiceServer) AnalyzeStorage(ctx context.Context, req *empty.Empty) (*StorageReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeStorage not implemented")
+}
+func (*UnimplementedImmuServiceServer) Backup(ctx context.Context, req *empty.Empty) (*BackupInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (*UnimplementedImmuServiceServer) RestoreBackup(ctx context.Context, req *RestoreBackupRequest) (*BackupInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreBackup not implemented")
+}
 func (*UnimplementedImmuServiceServer) Health(ctx context.Context, req *empty.Empty) (*HealthResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
 }
 func (*UnimplementedImmuServiceServer) Reference(ctx context.Context, req *ReferenceOptions) (*Index, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Reference not implemented")
 }
+func (*UnimplementedImmuServiceServer) CompareAndReference(ctx context.Context, req *CompareAndReferenceOptions) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndReference not implemented")
+}
 func (*UnimplementedImmuServiceServer) GetReference(ctx context.Context, req *Key) (*Item, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetReference not implemented")
 }
+func (*UnimplementedImmuServiceServer) Unreference(ctx context.Context, req *Key) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unreference not implemented")
+}
+func (*UnimplementedImmuServiceServer) GetReferences(ctx context.Context, req *ReferencesOptions) (*ReferenceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReferences not implemented")
+}
 func (*UnimplementedImmuServiceServer) SafeReference(ctx context.Context, req *SafeReferenceOptions) (*Proof, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SafeReference not implemented")
 }
-func (*UnimplementedImmuServiceServer) ZAdd(ctx context.Context, req *ZAddOptions) (*Index, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ZAdd not implemented")
+func (*UnimplementedImmuServiceServer) ZAdd(ctx context.Context, req *ZAddOptions) (*Index, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZAdd not implemented")
+}
+func (*UnimplementedImmuServiceServer) ZScan(ctx context.Context, req *ZScanOptions) (*ZItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ZScan not implemented")
+}
+func (*UnimplementedImmuServiceServer) SafeZScan(ctx context.Context, req *SafeZScanOptions) (*SafeZItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SafeZScan not implemented")
+}
+func (*UnimplementedImmuServiceServer) SafeZAdd(ctx context.Context, req *SafeZAddOptions) (*Proof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SafeZAdd not implemented")
+}
+func (*UnimplementedImmuServiceServer) IScan(ctx context.Context, req *IScanOptions) (*Page, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IScan not implemented")
+}
+func (*UnimplementedImmuServiceServer) Dump(req *empty.Empty, srv ImmuService_DumpServer) error {
+	return status.Errorf(codes.Unimplemented, "method Dump not implemented")
+}
+func (*UnimplementedImmuServiceServer) Logs(req *LogRequest, srv ImmuService_LogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
+}
+func (*UnimplementedImmuServiceServer) Subscribe(req *SubscribeRequest, srv ImmuService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (*UnimplementedImmuServiceServer) Tail(req *TailRequest, srv ImmuService_TailServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
+func (*UnimplementedImmuServiceServer) CreateDatabase(ctx context.Context, req *Database) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabase not implemented")
+}
+func (*UnimplementedImmuServiceServer) UseDatabase(ctx context.Context, req *Database) (*UseDatabaseReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UseDatabase not implemented")
+}
+func (*UnimplementedImmuServiceServer) ChangePermission(ctx context.Context, req *ChangePermissionRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePermission not implemented")
+}
+func (*UnimplementedImmuServiceServer) SetActiveUser(ctx context.Context, req *SetActiveUserRequest) (*empty.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetActiveUser not implemented")
+}
+func (*UnimplementedImmuServiceServer) DatabaseList(ctx context.Context, req *empty.Empty) (*DatabaseListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DatabaseList not implemented")
+}
+
+func RegisterImmuServiceServer(s *grpc.Server, srv ImmuServiceServer) {
+	s.RegisterService(&_ImmuService_serviceDesc, srv)
+}
+
+func _ImmuService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).ListUsers(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).ListSessions(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/GetUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).GetUsage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_GetAccessControlState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).GetAccessControlState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/GetAccessControlState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).GetAccessControlState(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/CreateUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/ChangePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_UpdateAuthConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).UpdateAuthConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/UpdateAuthConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).UpdateAuthConfig(ctx, req.(*AuthConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_UpdateMTLSConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MTLSConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).UpdateMTLSConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/UpdateMTLSConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).UpdateMTLSConfig(ctx, req.(*MTLSConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_PrintTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).PrintTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/PrintTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).PrintTree(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).Logout(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ImmuService_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/Set",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).Set(ctx, req.(*KeyValue))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) ZScan(ctx context.Context, req *ZScanOptions) (*ZItemList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ZScan not implemented")
+
+func _ImmuService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).Delete(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) SafeZAdd(ctx context.Context, req *SafeZAddOptions) (*Proof, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SafeZAdd not implemented")
+
+func _ImmuService_SafeSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeSetOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).SafeSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/SafeSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).SafeSet(ctx, req.(*SafeSetOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) IScan(ctx context.Context, req *IScanOptions) (*Page, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method IScan not implemented")
+
+func _ImmuService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).Get(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) Dump(req *empty.Empty, srv ImmuService_DumpServer) error {
-	return status.Errorf(codes.Unimplemented, "method Dump not implemented")
+
+func _ImmuService_SafeGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeGetOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).SafeGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/SafeGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).SafeGet(ctx, req.(*SafeGetOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) CreateDatabase(ctx context.Context, req *Database) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateDatabase not implemented")
+
+func _ImmuService_SetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KVList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).SetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/SetBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).SetBatch(ctx, req.(*KVList))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) UseDatabase(ctx context.Context, req *Database) (*UseDatabaseReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UseDatabase not implemented")
+
+func _ImmuService_GetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).GetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/GetBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).GetBatch(ctx, req.(*KeyList))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) ChangePermission(ctx context.Context, req *ChangePermissionRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ChangePermission not implemented")
+
+func _ImmuService_SafeGetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeGetBatchOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).SafeGetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/SafeGetBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).SafeGetBatch(ctx, req.(*SafeGetBatchOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) SetActiveUser(ctx context.Context, req *SetActiveUserRequest) (*empty.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SetActiveUser not implemented")
+
+func _ImmuService_ExecAllOps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ops)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).ExecAllOps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/ExecAllOps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).ExecAllOps(ctx, req.(*Ops))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedImmuServiceServer) DatabaseList(ctx context.Context, req *empty.Empty) (*DatabaseListResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DatabaseList not implemented")
+
+func _ImmuService_ExecAllTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Ops)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).ExecAllTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/ExecAllTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).ExecAllTx(ctx, req.(*Ops))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterImmuServiceServer(s *grpc.Server, srv ImmuServiceServer) {
-	s.RegisterService(&_ImmuService_serviceDesc, srv)
+func _ImmuService_SafeExecAllTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeExecAllTxOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).SafeExecAllTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/SafeExecAllTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).SafeExecAllTx(ctx, req.(*SafeExecAllTxOptions))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _ImmuService_CompareAndExecAllTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndExecAllTxOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).ListUsers(ctx, in)
+		return srv.(ImmuServiceServer).CompareAndExecAllTx(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/ListUsers",
+		FullMethod: "/immudb.schema.ImmuService/CompareAndExecAllTx",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).ListUsers(ctx, req.(*empty.Empty))
+		return srv.(ImmuServiceServer).CompareAndExecAllTx(ctx, req.(*CompareAndExecAllTxOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateUserRequest)
+func _ImmuService_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).CreateUser(ctx, in)
+		return srv.(ImmuServiceServer).Scan(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/CreateUser",
+		FullMethod: "/immudb.schema.ImmuService/Scan",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+		return srv.(ImmuServiceServer).Scan(ctx, req.(*ScanOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChangePasswordRequest)
+func _ImmuService_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyPrefix)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).ChangePassword(ctx, in)
+		return srv.(ImmuServiceServer).Count(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/ChangePassword",
+		FullMethod: "/immudb.schema.ImmuService/Count",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).ChangePassword(ctx, req.(*ChangePasswordRequest))
+		return srv.(ImmuServiceServer).Count(ctx, req.(*KeyPrefix))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_UpdateAuthConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AuthConfig)
+func _ImmuService_CountAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).UpdateAuthConfig(ctx, in)
+		return srv.(ImmuServiceServer).CountAll(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/UpdateAuthConfig",
+		FullMethod: "/immudb.schema.ImmuService/CountAll",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).UpdateAuthConfig(ctx, req.(*AuthConfig))
+		return srv.(ImmuServiceServer).CountAll(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_UpdateMTLSConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MTLSConfig)
+func _ImmuService_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyPrefix)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).UpdateMTLSConfig(ctx, in)
+		return srv.(ImmuServiceServer).Stats(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/UpdateMTLSConfig",
+		FullMethod: "/immudb.schema.ImmuService/Stats",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).UpdateMTLSConfig(ctx, req.(*MTLSConfig))
+		return srv.(ImmuServiceServer).Stats(ctx, req.(*KeyPrefix))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_PrintTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _ImmuService_CurrentRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).PrintTree(ctx, in)
+		return srv.(ImmuServiceServer).CurrentRoot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/PrintTree",
+		FullMethod: "/immudb.schema.ImmuService/CurrentRoot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).PrintTree(ctx, req.(*empty.Empty))
+		return srv.(ImmuServiceServer).CurrentRoot(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
+func _ImmuService_Inclusion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Index)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Login(ctx, in)
+		return srv.(ImmuServiceServer).Inclusion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Login",
+		FullMethod: "/immudb.schema.ImmuService/Inclusion",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Login(ctx, req.(*LoginRequest))
+		return srv.(ImmuServiceServer).Inclusion(ctx, req.(*Index))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _ImmuService_Consistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Index)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Logout(ctx, in)
+		return srv.(ImmuServiceServer).Consistency(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Logout",
+		FullMethod: "/immudb.schema.ImmuService/Consistency",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Logout(ctx, req.(*empty.Empty))
+		return srv.(ImmuServiceServer).Consistency(ctx, req.(*Index))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeyValue)
+func _ImmuService_ByIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Index)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Set(ctx, in)
+		return srv.(ImmuServiceServer).ByIndex(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Set",
+		FullMethod: "/immudb.schema.ImmuService/ByIndex",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Set(ctx, req.(*KeyValue))
+		return srv.(ImmuServiceServer).ByIndex(ctx, req.(*Index))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_SafeSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SafeSetOptions)
+func _ImmuService_BySafeIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeIndexOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).SafeSet(ctx, in)
+		return srv.(ImmuServiceServer).BySafeIndex(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/SafeSet",
+		FullMethod: "/immudb.schema.ImmuService/BySafeIndex",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).SafeSet(ctx, req.(*SafeSetOptions))
+		return srv.(ImmuServiceServer).BySafeIndex(ctx, req.(*SafeIndexOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Key)
+func _ImmuService_GetBySequence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Sequence)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Get(ctx, in)
+		return srv.(ImmuServiceServer).GetBySequence(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Get",
+		FullMethod: "/immudb.schema.ImmuService/GetBySequence",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Get(ctx, req.(*Key))
+		return srv.(ImmuServiceServer).GetBySequence(ctx, req.(*Sequence))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_SafeGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SafeGetOptions)
+func _ImmuService_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).SafeGet(ctx, in)
+		return srv.(ImmuServiceServer).History(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/SafeGet",
+		FullMethod: "/immudb.schema.ImmuService/History",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).SafeGet(ctx, req.(*SafeGetOptions))
+		return srv.(ImmuServiceServer).History(ctx, req.(*HistoryOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_SetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KVList)
+func _ImmuService_DumpKeyHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).SetBatch(ctx, in)
+		return srv.(ImmuServiceServer).DumpKeyHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/SetBatch",
+		FullMethod: "/immudb.schema.ImmuService/DumpKeyHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).SetBatch(ctx, req.(*KVList))
+		return srv.(ImmuServiceServer).DumpKeyHistory(ctx, req.(*Key))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_GetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeyList)
+func _ImmuService_GetAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAtOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).GetBatch(ctx, in)
+		return srv.(ImmuServiceServer).GetAt(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/GetBatch",
+		FullMethod: "/immudb.schema.ImmuService/GetAt",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).GetBatch(ctx, req.(*KeyList))
+		return srv.(ImmuServiceServer).GetAt(ctx, req.(*GetAtOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_ExecAllOps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Ops)
+func _ImmuService_GetAsOf_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAsOfOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).ExecAllOps(ctx, in)
+		return srv.(ImmuServiceServer).GetAsOf(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/ExecAllOps",
+		FullMethod: "/immudb.schema.ImmuService/GetAsOf",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).ExecAllOps(ctx, req.(*Ops))
+		return srv.(ImmuServiceServer).GetAsOf(ctx, req.(*GetAsOfOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ScanOptions)
+func _ImmuService_VerifyValueHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValueHashOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Scan(ctx, in)
+		return srv.(ImmuServiceServer).VerifyValueHash(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Scan",
+		FullMethod: "/immudb.schema.ImmuService/VerifyValueHash",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Scan(ctx, req.(*ScanOptions))
+		return srv.(ImmuServiceServer).VerifyValueHash(ctx, req.(*ValueHashOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeyPrefix)
+func _ImmuService_SampleKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SampleOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Count(ctx, in)
+		return srv.(ImmuServiceServer).SampleKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Count",
+		FullMethod: "/immudb.schema.ImmuService/SampleKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Count(ctx, req.(*KeyPrefix))
+		return srv.(ImmuServiceServer).SampleKeys(ctx, req.(*SampleOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_CountAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _ImmuService_FreezePrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyPrefix)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).CountAll(ctx, in)
+		return srv.(ImmuServiceServer).FreezePrefix(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/CountAll",
+		FullMethod: "/immudb.schema.ImmuService/FreezePrefix",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).CountAll(ctx, req.(*empty.Empty))
+		return srv.(ImmuServiceServer).FreezePrefix(ctx, req.(*KeyPrefix))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_CurrentRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _ImmuService_AnalyzeStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).CurrentRoot(ctx, in)
+		return srv.(ImmuServiceServer).AnalyzeStorage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/CurrentRoot",
+		FullMethod: "/immudb.schema.ImmuService/AnalyzeStorage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).CurrentRoot(ctx, req.(*empty.Empty))
+		return srv.(ImmuServiceServer).AnalyzeStorage(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Inclusion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Index)
+func _ImmuService_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Inclusion(ctx, in)
+		return srv.(ImmuServiceServer).Backup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Inclusion",
+		FullMethod: "/immudb.schema.ImmuService/Backup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Inclusion(ctx, req.(*Index))
+		return srv.(ImmuServiceServer).Backup(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Consistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Index)
+func _ImmuService_RestoreBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Consistency(ctx, in)
+		return srv.(ImmuServiceServer).RestoreBackup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Consistency",
+		FullMethod: "/immudb.schema.ImmuService/RestoreBackup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Consistency(ctx, req.(*Index))
+		return srv.(ImmuServiceServer).RestoreBackup(ctx, req.(*RestoreBackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_ByIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Index)
+func _ImmuService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).ByIndex(ctx, in)
+		return srv.(ImmuServiceServer).Health(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/ByIndex",
+		FullMethod: "/immudb.schema.ImmuService/Health",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).ByIndex(ctx, req.(*Index))
+		return srv.(ImmuServiceServer).Health(ctx, req.(*empty.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_BySafeIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SafeIndexOptions)
+func _ImmuService_Reference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReferenceOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).BySafeIndex(ctx, in)
+		return srv.(ImmuServiceServer).Reference(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/BySafeIndex",
+		FullMethod: "/immudb.schema.ImmuService/Reference",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).BySafeIndex(ctx, req.(*SafeIndexOptions))
+		return srv.(ImmuServiceServer).Reference(ctx, req.(*ReferenceOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HistoryOptions)
+func _ImmuService_CompareAndReference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndReferenceOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).History(ctx, in)
+		return srv.(ImmuServiceServer).CompareAndReference(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/History",
+		FullMethod: "/immudb.schema.ImmuService/CompareAndReference",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).History(ctx, req.(*HistoryOptions))
+		return srv.(ImmuServiceServer).CompareAndReference(ctx, req.(*CompareAndReferenceOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+func _ImmuService_GetReference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Health(ctx, in)
+		return srv.(ImmuServiceServer).GetReference(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Health",
+		FullMethod: "/immudb.schema.ImmuService/GetReference",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Health(ctx, req.(*empty.Empty))
+		return srv.(ImmuServiceServer).GetReference(ctx, req.(*Key))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_Reference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReferenceOptions)
+func _ImmuService_Unreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).Reference(ctx, in)
+		return srv.(ImmuServiceServer).Unreference(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/Reference",
+		FullMethod: "/immudb.schema.ImmuService/Unreference",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).Reference(ctx, req.(*ReferenceOptions))
+		return srv.(ImmuServiceServer).Unreference(ctx, req.(*Key))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ImmuService_GetReference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Key)
+func _ImmuService_GetReferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReferencesOptions)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ImmuServiceServer).GetReference(ctx, in)
+		return srv.(ImmuServiceServer).GetReferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/immudb.schema.ImmuService/GetReference",
+		FullMethod: "/immudb.schema.ImmuService/GetReferences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ImmuServiceServer).GetReference(ctx, req.(*Key))
+		return srv.(ImmuServiceServer).GetReferences(ctx, req.(*ReferencesOptions))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -4576,6 +8166,24 @@ func _ImmuService_ZScan_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ImmuService_SafeZScan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SafeZScanOptions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ImmuServiceServer).SafeZScan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/immudb.schema.ImmuService/SafeZScan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ImmuServiceServer).SafeZScan(ctx, req.(*SafeZScanOptions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ImmuService_SafeZAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SafeZAddOptions)
 	if err := dec(in); err != nil {
@@ -4633,6 +8241,69 @@ func (x *immuServiceDumpServer) Send(m *pb.KVList) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ImmuService_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImmuServiceServer).Logs(m, &immuServiceLogsServer{stream})
+}
+
+type ImmuService_LogsServer interface {
+	Send(*LogEntry) error
+	grpc.ServerStream
+}
+
+type immuServiceLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *immuServiceLogsServer) Send(m *LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ImmuService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImmuServiceServer).Subscribe(m, &immuServiceSubscribeServer{stream})
+}
+
+type ImmuService_SubscribeServer interface {
+	Send(*KeyChange) error
+	grpc.ServerStream
+}
+
+type immuServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *immuServiceSubscribeServer) Send(m *KeyChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ImmuService_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ImmuServiceServer).Tail(m, &immuServiceTailServer{stream})
+}
+
+type ImmuService_TailServer interface {
+	Send(*TailItem) error
+	grpc.ServerStream
+}
+
+type immuServiceTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *immuServiceTailServer) Send(m *TailItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _ImmuService_CreateDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Database)
 	if err := dec(in); err != nil {
@@ -4731,6 +8402,18 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListUsers",
 			Handler:    _ImmuService_ListUsers_Handler,
 		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _ImmuService_ListSessions_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _ImmuService_GetUsage_Handler,
+		},
+		{
+			MethodName: "GetAccessControlState",
+			Handler:    _ImmuService_GetAccessControlState_Handler,
+		},
 		{
 			MethodName: "CreateUser",
 			Handler:    _ImmuService_CreateUser_Handler,
@@ -4763,6 +8446,10 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Set",
 			Handler:    _ImmuService_Set_Handler,
 		},
+		{
+			MethodName: "Delete",
+			Handler:    _ImmuService_Delete_Handler,
+		},
 		{
 			MethodName: "SafeSet",
 			Handler:    _ImmuService_SafeSet_Handler,
@@ -4783,10 +8470,26 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetBatch",
 			Handler:    _ImmuService_GetBatch_Handler,
 		},
+		{
+			MethodName: "SafeGetBatch",
+			Handler:    _ImmuService_SafeGetBatch_Handler,
+		},
 		{
 			MethodName: "ExecAllOps",
 			Handler:    _ImmuService_ExecAllOps_Handler,
 		},
+		{
+			MethodName: "ExecAllTx",
+			Handler:    _ImmuService_ExecAllTx_Handler,
+		},
+		{
+			MethodName: "SafeExecAllTx",
+			Handler:    _ImmuService_SafeExecAllTx_Handler,
+		},
+		{
+			MethodName: "CompareAndExecAllTx",
+			Handler:    _ImmuService_CompareAndExecAllTx_Handler,
+		},
 		{
 			MethodName: "Scan",
 			Handler:    _ImmuService_Scan_Handler,
@@ -4799,6 +8502,10 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CountAll",
 			Handler:    _ImmuService_CountAll_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _ImmuService_Stats_Handler,
+		},
 		{
 			MethodName: "CurrentRoot",
 			Handler:    _ImmuService_CurrentRoot_Handler,
@@ -4819,10 +8526,50 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "BySafeIndex",
 			Handler:    _ImmuService_BySafeIndex_Handler,
 		},
+		{
+			MethodName: "GetBySequence",
+			Handler:    _ImmuService_GetBySequence_Handler,
+		},
 		{
 			MethodName: "History",
 			Handler:    _ImmuService_History_Handler,
 		},
+		{
+			MethodName: "DumpKeyHistory",
+			Handler:    _ImmuService_DumpKeyHistory_Handler,
+		},
+		{
+			MethodName: "GetAt",
+			Handler:    _ImmuService_GetAt_Handler,
+		},
+		{
+			MethodName: "GetAsOf",
+			Handler:    _ImmuService_GetAsOf_Handler,
+		},
+		{
+			MethodName: "VerifyValueHash",
+			Handler:    _ImmuService_VerifyValueHash_Handler,
+		},
+		{
+			MethodName: "SampleKeys",
+			Handler:    _ImmuService_SampleKeys_Handler,
+		},
+		{
+			MethodName: "FreezePrefix",
+			Handler:    _ImmuService_FreezePrefix_Handler,
+		},
+		{
+			MethodName: "AnalyzeStorage",
+			Handler:    _ImmuService_AnalyzeStorage_Handler,
+		},
+		{
+			MethodName: "Backup",
+			Handler:    _ImmuService_Backup_Handler,
+		},
+		{
+			MethodName: "RestoreBackup",
+			Handler:    _ImmuService_RestoreBackup_Handler,
+		},
 		{
 			MethodName: "Health",
 			Handler:    _ImmuService_Health_Handler,
@@ -4831,10 +8578,22 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Reference",
 			Handler:    _ImmuService_Reference_Handler,
 		},
+		{
+			MethodName: "CompareAndReference",
+			Handler:    _ImmuService_CompareAndReference_Handler,
+		},
 		{
 			MethodName: "GetReference",
 			Handler:    _ImmuService_GetReference_Handler,
 		},
+		{
+			MethodName: "Unreference",
+			Handler:    _ImmuService_Unreference_Handler,
+		},
+		{
+			MethodName: "GetReferences",
+			Handler:    _ImmuService_GetReferences_Handler,
+		},
 		{
 			MethodName: "SafeReference",
 			Handler:    _ImmuService_SafeReference_Handler,
@@ -4847,6 +8606,10 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ZScan",
 			Handler:    _ImmuService_ZScan_Handler,
 		},
+		{
+			MethodName: "SafeZScan",
+			Handler:    _ImmuService_SafeZScan_Handler,
+		},
 		{
 			MethodName: "SafeZAdd",
 			Handler:    _ImmuService_SafeZAdd_Handler,
@@ -4882,6 +8645,21 @@ var _ImmuService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _ImmuService_Dump_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Logs",
+			Handler:       _ImmuService_Logs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _ImmuService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Tail",
+			Handler:       _ImmuService_Tail_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "schema.proto",
 }
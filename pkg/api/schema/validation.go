@@ -0,0 +1,358 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requiredFields lists, by message, the fields a request must set to a non empty value
+var requiredFields = map[protoreflect.FullName][]protoreflect.Name{
+	"immudb.schema.KeyValue":             {"key"},
+	"immudb.schema.StructuredKeyValue":   {"key"},
+	"immudb.schema.Key":                  {"key"},
+	"immudb.schema.SafeSetOptions":       {"kv"},
+	"immudb.schema.SafeSetSVOptions":     {"skv"},
+	"immudb.schema.SafeGetOptions":       {"key"},
+	"immudb.schema.HistoryOptions":       {"key"},
+	"immudb.schema.ReferenceOptions":     {"reference"},
+	"immudb.schema.SafeReferenceOptions": {"ro"},
+	"immudb.schema.ZAddOptions":          {"set"},
+	"immudb.schema.SafeZAddOptions":      {"zopts"},
+	"immudb.schema.ZScanOptions":         {"set"},
+	"immudb.schema.SafeZScanOptions":     {"zopts"},
+	"immudb.schema.Database":             {"databasename"},
+	"immudb.schema.LoginRequest":         {"user", "password"},
+	"immudb.schema.CreateUserRequest":    {"user", "password"},
+}
+
+// FieldViolation describes why the value of a field of a request is invalid.
+// Field is the path of the field in the JSON request, e.g. kv.key or KVs[1].value.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists the invalid fields of a JSON request
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("invalid request:")
+	for i, v := range e.Violations {
+		if i > 0 {
+			sb.WriteString(";")
+		}
+		if v.Field == "" {
+			sb.WriteString(" " + v.Description)
+		} else {
+			fmt.Fprintf(&sb, " %s %s", v.Field, v.Description)
+		}
+	}
+	return sb.String()
+}
+
+// GRPCStatus returns an InvalidArgument status carrying the violations as a BadRequest detail
+func (e *ValidationError) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations,
+			&errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+	}
+	if detailed, err := st.WithDetails(badRequest); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// ValidateJSON checks data against the schema of m before it is unmarshaled: unknown fields, values of the wrong
+// type or encoding (e.g. bytes not base64 encoded) and missing required fields are all reported at once.
+// It returns a *ValidationError if data is not valid.
+func ValidateJSON(data []byte, m proto.Message) error {
+	v := &validator{}
+	v.message(bytes.TrimSpace(data), proto.MessageReflect(m).Descriptor(), "")
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+type validator struct {
+	violations []FieldViolation
+}
+
+func (v *validator) fail(field, description string) {
+	v.violations = append(v.violations, FieldViolation{Field: field, Description: description})
+}
+
+func (v *validator) message(data []byte, md protoreflect.MessageDescriptor, path string) {
+	if strings.HasPrefix(string(md.FullName()), "google.protobuf.") {
+		// well-known types have their own JSON representation, left to the unmarshaler
+		return
+	}
+	if isNull(data) {
+		v.required(md, nil, path)
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		if path == "" {
+			v.fail("", "body must be a JSON object")
+		} else {
+			v.fail(path, "must be a JSON object")
+		}
+		return
+	}
+
+	// sorted, so that violations are reported in a stable order
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	oneofs := map[protoreflect.FullName]string{}
+	for _, name := range names {
+		value := fields[name]
+		fieldPath := join(path, name)
+		fd := md.Fields().ByJSONName(name)
+		if fd == nil {
+			fd = md.Fields().ByName(protoreflect.Name(name))
+		}
+		if fd == nil {
+			v.fail(fieldPath, fmt.Sprintf("is not a field of %s", md.Name()))
+			continue
+		}
+		if oneof := fd.ContainingOneof(); oneof != nil && !isNull(value) {
+			if other, ok := oneofs[oneof.FullName()]; ok {
+				v.fail(fieldPath, fmt.Sprintf("can not be set along with %s", other))
+				continue
+			}
+			oneofs[oneof.FullName()] = name
+		}
+		v.field(value, fd, fieldPath)
+	}
+	v.required(md, fields, path)
+}
+
+func (v *validator) required(md protoreflect.MessageDescriptor, fields map[string]json.RawMessage, path string) {
+	for _, name := range requiredFields[md.FullName()] {
+		fd := md.Fields().ByName(name)
+		value, ok := fields[fd.JSONName()]
+		if !ok {
+			value, ok = fields[string(name)]
+		}
+		if !ok || isNull(value) || isEmpty(value) {
+			v.fail(join(path, fd.JSONName()), "is required")
+		}
+	}
+}
+
+func (v *validator) field(data []byte, fd protoreflect.FieldDescriptor, path string) {
+	if isNull(data) {
+		return
+	}
+	switch {
+	case fd.IsMap():
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(data, &entries); err != nil {
+			v.fail(path, "must be a JSON object")
+			return
+		}
+		for key, value := range entries {
+			v.value(value, fd.MapValue(), fmt.Sprintf("%s[%q]", path, key))
+		}
+	case fd.IsList():
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			v.fail(path, "must be a JSON array")
+			return
+		}
+		for i, elem := range elems {
+			v.value(elem, fd, fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		v.value(data, fd, path)
+	}
+}
+
+func (v *validator) value(data []byte, fd protoreflect.FieldDescriptor, path string) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		v.message(data, fd.Message(), path)
+	case protoreflect.BytesKind:
+		s, ok := jsonString(data)
+		if !ok || !isBase64(s) {
+			v.fail(path, "must be a base64 encoded string")
+		}
+	case protoreflect.StringKind:
+		if _, ok := jsonString(data); !ok {
+			v.fail(path, "must be a string")
+		}
+	case protoreflect.BoolKind:
+		if s := string(data); s != "true" && s != "false" {
+			v.fail(path, "must be a boolean")
+		}
+	case protoreflect.EnumKind:
+		if s, ok := jsonString(data); ok {
+			if fd.Enum().Values().ByName(protoreflect.Name(s)) == nil {
+				v.fail(path, fmt.Sprintf("must be one of the values of %s", fd.Enum().Name()))
+			}
+		} else if _, err := strconv.ParseInt(string(data), 10, 32); err != nil {
+			v.fail(path, fmt.Sprintf("must be one of the values of %s", fd.Enum().Name()))
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		s, quoted := jsonString(data)
+		if !quoted {
+			s = string(data)
+		} else if s == "NaN" || s == "Infinity" || s == "-Infinity" {
+			return
+		}
+		if f, err := strconv.ParseFloat(s, 64); err != nil || math.IsInf(f, 0) {
+			v.fail(path, "must be a number")
+		}
+	default:
+		v.integer(data, fd.Kind(), path)
+	}
+}
+
+// integer checks a value of one of the integer kinds, quoted or not, as accepted by the JSON unmarshaler
+func (v *validator) integer(data []byte, kind protoreflect.Kind, path string) {
+	s, quoted := jsonString(data)
+	if !quoted {
+		s = string(data)
+	}
+	// exponent notation is accepted as long as the value is integral
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) && strings.ContainsAny(s, ".eE") {
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	var err error
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		_, err = strconv.ParseInt(s, 10, 32)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		_, err = strconv.ParseUint(s, 10, 32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		_, err = strconv.ParseInt(s, 10, 64)
+	default:
+		_, err = strconv.ParseUint(s, 10, 64)
+	}
+	if err != nil {
+		switch kind {
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			v.fail(path, "must be a non negative integer")
+		default:
+			v.fail(path, "must be an integer")
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func isNull(data []byte) bool {
+	return len(data) == 0 || string(data) == "null"
+}
+
+func isEmpty(data []byte) bool {
+	s := string(data)
+	return s == `""` || s == "{}" || s == "[]"
+}
+
+func jsonString(data []byte) (string, bool) {
+	if len(data) == 0 || data[0] != '"' {
+		return "", false
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// isBase64 accepts the standard and the URL encodings, padded or not, like the JSON unmarshaler does
+func isBase64(s string) bool {
+	enc := base64.StdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.URLEncoding
+	}
+	if len(s)%4 != 0 {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	_, err := enc.DecodeString(s)
+	return err == nil
+}
+
+// NewValidatingMarshaler wraps the marshaler used by the REST gateway, so that request bodies are validated by
+// ValidateJSON before being unmarshaled: invalid requests are then rejected with a 400 Bad Request listing
+// every invalid field, instead of the first error of the unmarshaler.
+//
+//	mux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard,
+//		schema.NewValidatingMarshaler(&runtime.JSONPb{OrigName: true})))
+func NewValidatingMarshaler(m runtime.Marshaler) runtime.Marshaler {
+	return &validatingMarshaler{Marshaler: m}
+}
+
+type validatingMarshaler struct {
+	runtime.Marshaler
+}
+
+func (m *validatingMarshaler) Unmarshal(data []byte, v interface{}) error {
+	if msg, ok := v.(proto.Message); ok {
+		if err := ValidateJSON(data, msg); err != nil {
+			return err
+		}
+	}
+	return m.Marshaler.Unmarshal(data, v)
+}
+
+func (m *validatingMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	d := json.NewDecoder(r)
+	return runtime.DecoderFunc(func(v interface{}) error {
+		var data json.RawMessage
+		if err := d.Decode(&data); err != nil {
+			if err == io.EOF {
+				return err
+			}
+			return &ValidationError{Violations: []FieldViolation{{Description: "body is not valid JSON: " + err.Error()}}}
+		}
+		return m.Unmarshal(data, v)
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func TestRequiredFieldsExist(t *testing.T) {
+	for message, fields := range requiredFields {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(message)
+		require.NoError(t, err, message)
+		for _, field := range fields {
+			assert.NotNil(t, d.(protoreflect.MessageDescriptor).Fields().ByName(field), "%s.%s", message, field)
+		}
+	}
+}
+
+func violations(t *testing.T, err error) map[string]string {
+	require.Error(t, err)
+	verr, ok := err.(*ValidationError)
+	require.True(t, ok, "%v", err)
+	found := map[string]string{}
+	for _, v := range verr.Violations {
+		found[v.Field] = v.Description
+	}
+	return found
+}
+
+func TestValidateJSON(t *testing.T) {
+	assert.NoError(t, ValidateJSON([]byte(`{"kv": {"key": "a2V5", "value": "dmFsdWU="}, "rootIndex": {"index": "10"}}`), &SafeSetOptions{}))
+	assert.NoError(t, ValidateJSON([]byte(`{"key": "a2V5", "value": null}`), &KeyValue{}))
+	assert.NoError(t, ValidateJSON([]byte(`{"key": "a-_5"}`), &Key{}))
+	assert.NoError(t, ValidateJSON([]byte(`{"set": "cw==", "score": {"score": "NaN"}, "key": "aw==", "index": {"index": 1e2}}`), &ZAddOptions{}))
+	assert.NoError(t, ValidateJSON([]byte(`{"Operations": [{"KVs": {"key": "aw=="}}, {"ROpts": {"reference": "cg==", "key": "aw=="}}]}`), &Ops{}))
+	assert.NoError(t, ValidateJSON([]byte(`{"action": "REVOKE", "username": "u"}`), &ChangePermissionRequest{}))
+
+	found := violations(t, ValidateJSON([]byte(`{"kv": {"key": "not base64!", "valeu": "dg=="}, "rootIndex": {"index": -1}}`), &SafeSetOptions{}))
+	assert.Equal(t, map[string]string{
+		"kv.key":          "must be a base64 encoded string",
+		"kv.valeu":        "is not a field of KeyValue",
+		"rootIndex.index": "must be a non negative integer",
+	}, found)
+
+	found = violations(t, ValidateJSON([]byte(`{"KVs": [{"key": "aw=="}, {"value": "dg=="}, 3]}`), &KVList{}))
+	assert.Equal(t, map[string]string{
+		"KVs[1].key": "is required",
+		"KVs[2]":     "must be a JSON object",
+	}, found)
+
+	found = violations(t, ValidateJSON([]byte(`{"Operations": [{"KVs": {"key": "aw=="}, "ZOpts": {"set": "cw=="}}]}`), &Ops{}))
+	assert.Equal(t, map[string]string{"Operations[0].ZOpts": "can not be set along with KVs"}, found)
+
+	found = violations(t, ValidateJSON([]byte(`{"action": "MAYBE", "permission": "x", "database": 1, "username": true}`), &ChangePermissionRequest{}))
+	assert.Equal(t, map[string]string{
+		"action":     "must be one of the values of PermissionAction",
+		"permission": "must be a non negative integer",
+		"database":   "must be a string",
+		"username":   "must be a string",
+	}, found)
+
+	found = violations(t, ValidateJSON([]byte(`{"username": "", "active": "yes"}`), &SetActiveUserRequest{}))
+	assert.Equal(t, map[string]string{"active": "must be a boolean"}, found)
+
+	found = violations(t, ValidateJSON([]byte(`{}`), &LoginRequest{}))
+	assert.Equal(t, map[string]string{"user": "is required", "password": "is required"}, found)
+
+	found = violations(t, ValidateJSON([]byte(`[]`), &KeyValue{}))
+	assert.Equal(t, map[string]string{"": "body must be a JSON object"}, found)
+}
+
+func TestValidationErrorStatus(t *testing.T) {
+	err := ValidateJSON([]byte(`{"kv": {"key": "!"}, "x": 1}`), &SafeSetOptions{})
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "invalid request: "))
+	assert.Contains(t, err.Error(), "kv.key must be a base64 encoded string")
+	assert.Contains(t, err.Error(), "x is not a field of SafeSetOptions")
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest := st.Details()[0].(*errdetails.BadRequest)
+	assert.Len(t, badRequest.FieldViolations, 2)
+	// the gateway reports decoding errors as InvalidArgument, i.e. as 400 Bad Request
+	assert.Equal(t, 400, runtime.HTTPStatusFromCode(st.Code()))
+}
+
+func TestValidatingMarshaler(t *testing.T) {
+	m := NewValidatingMarshaler(&runtime.JSONPb{OrigName: true})
+
+	var kv KeyValue
+	require.NoError(t, m.NewDecoder(strings.NewReader(`{"key": "aw==", "value": "dg=="}`)).Decode(&kv))
+	assert.Equal(t, []byte("k"), kv.Key)
+	assert.Equal(t, []byte("v"), kv.Value)
+
+	assert.Equal(t, io.EOF, m.NewDecoder(strings.NewReader("")).Decode(&kv))
+
+	found := violations(t, m.NewDecoder(strings.NewReader(`{"key": 1}`)).Decode(&kv))
+	assert.Equal(t, map[string]string{"key": "must be a base64 encoded string"}, found)
+
+	err := m.NewDecoder(strings.NewReader(`{"key": `)).Decode(&kv)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "body is not valid JSON")
+
+	require.NoError(t, m.Unmarshal([]byte(`{"key": "aw=="}`), &Key{}))
+	require.Error(t, m.Unmarshal([]byte(`{"key": ""}`), &Key{}))
+}
@@ -31,42 +31,70 @@ const (
 
 var methodsPermissions = map[string][]uint32{
 	// readwrite methods
-	"Set":           {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"Get":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"SafeSet":       {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"SafeGet":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"SetBatch":      {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"GetBatch":      {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"ExecAllOps":    {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"Reference":     {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"SafeReference": {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"ZAdd":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"SafeZAdd":      {PermissionSysAdmin, PermissionAdmin, PermissionRW},
-	"ZScan":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"BySafeIndex":   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"IScan":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"Scan":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"History":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"ByIndex":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"Count":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"CountAll":      {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"DatabaseList":  {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"Consistency":   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"Inclusion":     {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
-	"CurrentRoot":   {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Set":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Get":                 {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SafeSet":             {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"SafeGet":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SafeGetBatch":        {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SetBatch":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"GetBatch":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ExecAllOps":          {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ExecAllTx":           {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"SafeExecAllTx":       {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"CompareAndExecAllTx": {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Delete":              {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Reference":           {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"SafeReference":       {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"CompareAndReference": {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"Unreference":         {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"ZAdd":                {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"SafeZAdd":            {PermissionSysAdmin, PermissionAdmin, PermissionRW},
+	"GetReferences":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ZScan":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SafeZScan":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Subscribe":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Tail":                {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"BySafeIndex":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"IScan":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Scan":                {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"History":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"GetAt":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"GetAsOf":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"ByIndex":             {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"GetBySequence":       {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"VerifyValueHash":     {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Count":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"CountAll":            {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Stats":               {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"DatabaseList":        {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Consistency":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"Inclusion":           {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"CurrentRoot":         {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+	"SampleKeys":          {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
+
+	// only a hash of the users and of their permissions is returned, so that any auditor can read it
+	"GetAccessControlState": {PermissionSysAdmin, PermissionAdmin, PermissionRW, PermissionR},
 
 	// admin methods
 	"ListUsers":        {PermissionSysAdmin, PermissionAdmin},
+	"ListSessions":     {PermissionSysAdmin},
+	"GetUsage":         {PermissionSysAdmin, PermissionAdmin},
 	"CreateUser":       {PermissionSysAdmin, PermissionAdmin},
 	"ChangePassword":   {PermissionSysAdmin, PermissionAdmin},
 	"SetPermission":    {PermissionSysAdmin, PermissionAdmin},
 	"DeactivateUser":   {PermissionSysAdmin, PermissionAdmin},
 	"SetActiveUser":    {PermissionSysAdmin, PermissionAdmin},
+	"FreezePrefix":     {PermissionSysAdmin, PermissionAdmin},
+	"AnalyzeStorage":   {PermissionSysAdmin, PermissionAdmin},
+	"Backup":           {PermissionSysAdmin, PermissionAdmin},
+	"RestoreBackup":    {PermissionSysAdmin},
 	"UpdateAuthConfig": {PermissionSysAdmin},
 	"UpdateMTLSConfig": {PermissionSysAdmin},
 	"CreateDatabase":   {PermissionSysAdmin},
 	"PrintTree":        {PermissionSysAdmin},
 	"Dump":             {PermissionSysAdmin, PermissionAdmin},
+	"DumpKeyHistory":   {PermissionSysAdmin, PermissionAdmin},
+	"Logs":             {PermissionSysAdmin},
 }
 
 //HasPermissionForMethod checks if userPermission can access method name
@@ -112,6 +112,15 @@ func parsePublicTokenPayload(token string) (*JSONToken, error) {
 	}, nil
 }
 
+// TokenExpiration returns the expiration time of the token, read from its public payload without verifying it
+func TokenExpiration(token string) (time.Time, error) {
+	jsonToken, err := parsePublicTokenPayload(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return jsonToken.Expiration, nil
+}
+
 func verifyToken(token string) (*JSONToken, error) {
 	tokenPayload, err := parsePublicTokenPayload(token)
 	if err != nil {
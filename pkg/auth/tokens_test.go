@@ -20,6 +20,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc/metadata"
 )
@@ -65,6 +66,23 @@ func TestToken(t *testing.T) {
 	}
 }
 
+func TestTokenExpiration(t *testing.T) {
+	token, err := GenerateToken(User{Username: "immudb", Active: true}, 0)
+	if err != nil {
+		t.Fatalf("Error GenerateToken %s", err)
+	}
+	expiration, err := TokenExpiration(token)
+	if err != nil {
+		t.Errorf("Error TokenExpiration %s", err)
+	}
+	if d := time.Until(expiration); d <= 0 || d > tokenValidity {
+		t.Errorf("TokenExpiration expected within %s, found %s", tokenValidity, expiration)
+	}
+	if _, err = TokenExpiration("not-a-token"); err == nil {
+		t.Errorf("TokenExpiration, failed to catch malformed token")
+	}
+}
+
 func TestVerifyFromCtx(t *testing.T) {
 	u := User{
 		Username: "immudb",
@@ -19,14 +19,19 @@ package auditor
 import (
 	"bytes"
 	"context"
+	"crypto"
 	"encoding/base64"
-	"encoding/json"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/codenotary/immudb/pkg/client/rootservice"
@@ -37,7 +42,9 @@ import (
 	"github.com/codenotary/immudb/pkg/client"
 	"github.com/codenotary/immudb/pkg/client/cache"
 	"github.com/codenotary/immudb/pkg/client/timestamp"
+	"github.com/codenotary/immudb/pkg/client/tracing"
 	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
@@ -45,7 +52,12 @@ import (
 
 // Auditor the auditor interface
 type Auditor interface {
+	// Run audits every interval until stopc is closed. With singleRun, it audits once and returns a *RunError
+	// if any database could not be audited or was possibly tampered, see ExitCode.
 	Run(interval time.Duration, singleRun bool, stopc <-chan struct{}, donec chan<- struct{}) error
+	// RunContext audits every interval until ctx is done, aborting the audit in progress, if any.
+	// It returns ctx.Err() once cancelled.
+	RunContext(ctx context.Context, interval time.Duration, singleRun bool) error
 }
 
 // AuditNotificationConfig holds the URL and credentials used to publish audit
@@ -55,8 +67,35 @@ type AuditNotificationConfig struct {
 	Username       string
 	Password       string
 	RequestTimeout time.Duration
+	// HMACKey, if set, authenticates the notification body with an HMAC-SHA256 sent in the NotificationHMACHeader header
+	HMACKey []byte
+	// Signer, if set, signs the notification body with its Ed25519 or ECDSA key,
+	// the signature is sent in the NotificationSignatureHeader header
+	Signer crypto.Signer
+	// MaxRetries is the number of times a notification is sent again if the endpoint is unavailable,
+	// waiting RetryBackoff before the first retry and doubling the wait at every further retry
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// DeadLetterDir, if set, is the directory where the notifications which could not be published are queued,
+	// to be sent again after the next successful publish
+	DeadLetterDir string
+	// Template, if set, formats the body of the notifications instead of the AuditNotificationRequest JSON,
+	// e.g. to post them directly to a Slack webhook, see ParseNotificationTemplate
+	Template *template.Template
+	// ContentType is the content type of the notifications, application/json if empty
+	ContentType string
+	// MinInterval, if set, is the minimum time between two requests to the notification URL:
+	// the notifications exceeding the rate are held and sent at the end of the following audit runs
+	MinInterval time.Duration
+	// BatchSize, if set, aggregates up to BatchSize notifications in a single request, posting the JSON array
+	// of their AuditNotificationRequest, or executing Template with a []NotificationTemplateData.
+	// A batch is sent once full, or once its oldest notification has waited BatchWait.
+	BatchSize int
+	BatchWait time.Duration
 
 	publishFunc func(*http.Request) (*http.Response, error)
+	sleep       func(time.Duration)
+	now         func() time.Time
 }
 
 type defaultAuditor struct {
@@ -71,16 +110,79 @@ type defaultAuditor struct {
 	databases          []string
 	password           []byte
 	auditDatabases     []string
+	includeDatabases   []*regexp.Regexp
+	excludeDatabases   []*regexp.Regexp
+	deniedDatabases    map[string]struct{}
 	auditSignature     string
 	notificationConfig AuditNotificationConfig
+	deadLetters        *deadLetterQueue
 	serviceClient      schema.ImmuServiceClient
 	uuidProvider       rootservice.UUIDProvider
 
 	slugifyRegExp *regexp.Regexp
 	updateMetrics func(string, string, bool, bool, bool, *schema.Root, *schema.Root)
+
+	// pinned roots not yet proven to be consistent with the server history
+	pinnedRoots map[string]PinnedRoot
+
+	notifiers []Notifier
+	onTamper  func(result AuditResult)
+
+	// proofArchive, if set, keeps every consistency proof fetched
+	proofArchive ProofArchive
+
+	// trustedPublicKeys, if set, are the only keys the roots of the server may be signed with
+	trustedPublicKeys [][]byte
+
+	// metrics, if set, records the outcome of every audit
+	metrics *Metrics
+
+	// gossip, if set, exchanges the trusted roots with other auditors to detect split views
+	gossip *Gossip
+
+	// tracer, if set, traces every audit and its steps with spans
+	tracer tracing.Tracer
+
+	// session token reused across audits, see session
+	token           string
+	tokenExpiration time.Time
+	sessionMu       sync.Mutex
+
+	// digests of the audits published every digestPeriod, if positive, see AuditDigest
+	digestPeriod    time.Duration
+	digestNotifiers []DigestNotifier
+	// elapsedDigests are the digests of the elapsed periods, published at the end of the run
+	elapsedDigests []*AuditDigest
+
+	// auditAccessControl enables the comparison of the users and permissions of the server between audits
+	auditAccessControl bool
+
+	// progress of the auditor, persisted in stateStore if set
+	state       *State
+	stateStore  StateStore
+	resumeAfter string
+
+	// databaseInterval, if set, spaces out the audits of every database
+	databaseInterval DatabaseInterval
+
+	// number of databases audited in parallel at every run, one database per run is audited if not greater than 1
+	workers int
+
+	// scheduling of the runs, see intervalSchedule
+	intervalJitter      float64
+	adaptiveMinInterval time.Duration
+	adaptiveSuccesses   int
+	rand                *rand.Rand
+	// runFailed tells whether the current run met an error or a verification failure
+	runFailed bool
+	// outcomes of the audits of the current run, see RunError
+	outcomes []DatabaseOutcome
+	// mu guards the history, the pinned roots and the state while databases are audited in parallel
+	mu sync.Mutex
 }
 
-// DefaultAuditor creates initializes a default auditor implementation
+// DefaultAuditor creates initializes a default auditor implementation.
+// updateMetrics may be nil, the built-in Prometheus metrics are enabled with WithMetrics.
 func DefaultAuditor(
 	interval time.Duration,
 	serverAddress string,
@@ -94,7 +196,8 @@ func DefaultAuditor(
 	uuidProvider rootservice.UUIDProvider,
 	history cache.HistoryCache,
 	updateMetrics func(string, string, bool, bool, bool, *schema.Root, *schema.Root),
-	log logger.Logger) (Auditor, error) {
+	log logger.Logger,
+	options ...Option) (Auditor, error) {
 
 	switch auditSignature {
 	case "validate":
@@ -116,25 +219,46 @@ func DefaultAuditor(
 	httpClient := &http.Client{Timeout: notificationConfig.RequestTimeout}
 	notificationConfig.publishFunc = httpClient.Do
 
-	return &defaultAuditor{
-		0,
-		0,
-		log,
-		serverAddress,
-		*dialOptions,
-		history,
-		client.NewTimestampService(dt),
-		[]byte(username),
-		nil,
-		[]byte(password),
-		auditDatabases,
-		auditSignature,
-		notificationConfig,
-		serviceClient,
-		uuidProvider,
-		slugifyRegExp,
-		updateMetrics,
-	}, nil
+	a := &defaultAuditor{
+		index:              0,
+		databaseIndex:      0,
+		logger:             log,
+		serverAddress:      serverAddress,
+		dialOptions:        *dialOptions,
+		history:            history,
+		ts:                 client.NewTimestampService(dt),
+		username:           []byte(username),
+		databases:          nil,
+		password:           []byte(password),
+		auditDatabases:     auditDatabases,
+		auditSignature:     auditSignature,
+		notificationConfig: notificationConfig,
+		serviceClient:      serviceClient,
+		uuidProvider:       uuidProvider,
+		slugifyRegExp:      slugifyRegExp,
+		updateMetrics:      updateMetrics,
+		pinnedRoots:        map[string]PinnedRoot{},
+		state:              &State{Databases: map[string]*DatabaseState{}},
+		rand:               rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, option := range options {
+		option(a)
+	}
+
+	if len(notificationConfig.DeadLetterDir) > 0 {
+		a.deadLetters = newDeadLetterQueue(
+			filepath.Join(notificationConfig.DeadLetterDir, slugifyRegExp.ReplaceAllString(serverAddress, "_")))
+	}
+
+	if a.stateStore != nil {
+		if a.state, err = a.stateStore.Load(serverAddress); err != nil {
+			return nil, err
+		}
+		a.index = a.state.Index
+		a.resumeAfter = a.state.LastDatabase
+	}
+
+	return a, nil
 }
 
 func (a *defaultAuditor) Run(
@@ -144,151 +268,338 @@ func (a *defaultAuditor) Run(
 	donec chan<- struct{},
 ) (err error) {
 	defer func() { donec <- struct{}{} }()
+	ctx, cancel := stopContext(stopc)
+	defer cancel()
+	return ignoreCancel(ctx, a.RunContext(ctx, interval, singleRun))
+}
+
+func (a *defaultAuditor) RunContext(ctx context.Context, interval time.Duration, singleRun bool) (err error) {
 	a.logger.Infof("starting auditor with a %s interval ...", interval)
 
 	if singleRun {
-		err = a.audit()
+		err = a.audit(ctx)
+		if err == nil && ctx.Err() == nil {
+			err = runError(a.runOutcomes())
+		}
 	} else {
-		err = repeat(interval, stopc, a.audit)
+		schedule := a.newSchedule(interval)
+		err = repeat(func() time.Duration { return schedule.next(a.lastRunFailed()) }, ctx.Done(), func() error {
+			return a.audit(ctx)
+		})
 		if err != nil {
 			return err
 		}
 	}
+	a.flushNotifications(flushAll)
+	a.logout()
 	a.logger.Infof("auditor stopped")
+	if err == nil {
+		err = ctx.Err()
+	}
 	return err
 }
 
-func (a *defaultAuditor) audit() error {
+func (a *defaultAuditor) audit(ctx context.Context) error {
 	start := time.Now()
 	a.index++
-	a.logger.Infof("audit #%d started @ %s", a.index, start)
-
-	verified := true
-	checked := false
-	withError := false
-	serverID := "unknown"
-	var prevRoot *schema.Root
-	var root *schema.Root
-	defer func() {
-		a.updateMetrics(
-			serverID, a.serverAddress, checked, withError, verified, prevRoot, root)
-	}()
+	index := a.index
+	a.logger.Infof("audit #%d started @ %s", index, start)
+	a.startRun()
+	defer a.flushNotifications(flushRunEnd)
+	defer func() { a.publishDigests(ctx, time.Now()) }()
+	ctx, span := tracing.Start(a.tracer, ctx, "audit", tracing.Int64("audit.index", int64(index)))
+	defer span.End()
 
 	// returning an error would completely stop the auditor process
 	var noErr error
 
-	ctx := context.Background()
-	loginResponse, err := a.serviceClient.Login(ctx, &schema.LoginRequest{
-		User:     a.username,
-		Password: a.password,
-	})
+	sessionCtx, err := a.session(ctx)
 	if err != nil {
 		a.logger.Errorf("error logging in with user %s: %v", a.username, err)
-		withError = true
+		a.auditFailed(ctx, start)
 		return noErr
 	}
-	defer a.serviceClient.Logout(ctx, &empty.Empty{})
+	ctx = sessionCtx
 
-	md := metadata.Pairs("authorization", loginResponse.Token)
-	ctx = metadata.NewOutgoingContext(context.Background(), md)
+	if a.auditAccessControl {
+		a.auditAccessControlState(ctx, start)
+	}
 
-	//check if we have cycled through the list of databases
-	if a.databaseIndex == len(a.databases) {
-		//if we have reached the end get a fresh list of dbs that belong to the user
-		dbs, err := a.serviceClient.DatabaseList(ctx, &emptypb.Empty{})
+	if a.workers <= 1 {
+		dbName, due, err := a.nextDatabase(ctx, index, start)
 		if err != nil {
-			a.logger.Errorf("error getting a list of databases %v", err)
-			withError = true
+			a.auditFailed(ctx, start)
 			return noErr
 		}
-		a.databases = nil
-		for _, db := range dbs.Databases {
-			dbMustBeAudited := len(a.auditDatabases) <= 0
-			for _, dbPrefix := range a.auditDatabases {
-				if strings.HasPrefix(db.Databasename, dbPrefix) {
-					dbMustBeAudited = true
-					break
-				}
+		if !due {
+			a.logger.Infof("audit #%d skipped: no database is due for audit", index)
+			return noErr
+		}
+		a.auditDatabase(ctx, index, start, dbName, func() { a.databaseIndex++ })
+		return noErr
+	}
+
+	// with a worker pool, every database is audited at every run
+	if err := a.loadDatabases(ctx, index); err != nil {
+		a.auditFailed(ctx, start)
+		return noErr
+	}
+	due := a.dueDatabases(start)
+	dbNames := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < a.workers && w < len(due); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for dbName := range dbNames {
+				a.auditDatabase(ctx, index, time.Now(), dbName, func() {})
 			}
-			if dbMustBeAudited {
-				a.databases = append(a.databases, db.Databasename)
+		}()
+	}
+	for _, dbName := range due {
+		dbNames <- dbName
+	}
+	close(dbNames)
+	wg.Wait()
+	a.databaseIndex = len(a.databases)
+
+	a.logger.Infof("audit #%d of %d database(s) with %d workers finished in %s @ %s",
+		index, len(due), a.workers, time.Since(start), time.Now().Format(time.RFC3339Nano))
+	return noErr
+}
+
+// auditFailed reports an audit that failed before a database could be selected
+func (a *defaultAuditor) auditFailed(ctx context.Context, start time.Time) {
+	if aborted(ctx) {
+		a.logger.Infof("audit aborted: %v", ctx.Err())
+		return
+	}
+	a.markRunFailed()
+	a.recordOutcome("", OutcomeFailed)
+	if a.updateMetrics != nil {
+		a.updateMetrics("unknown", a.serverAddress, false, true, true, nil, nil)
+	}
+	a.metrics.observeAudit("unknown", a.serverAddress, "", start, false)
+	a.recordDigest(start, "", OutcomeFailed, nil)
+	a.recordAudit(start, "", false)
+}
+
+// loadDatabases (re)loads the list of the databases to audit, resuming the rotation from the saved state if any
+func (a *defaultAuditor) loadDatabases(ctx context.Context, index uint64) error {
+	var dbs *schema.DatabaseListResponse
+	_, err := a.withSession(ctx, func(ctx context.Context) (err error) {
+		dbs, err = a.serviceClient.DatabaseList(ctx, &emptypb.Empty{})
+		return err
+	})
+	if err != nil {
+		a.logger.Errorf("error getting a list of databases %v", err)
+		return err
+	}
+	a.databases = nil
+	for _, db := range dbs.Databases {
+		if a.mustBeAudited(db.Databasename) {
+			a.databases = append(a.databases, db.Databasename)
+		}
+	}
+	a.databaseIndex = 0
+	if a.resumeAfter != "" {
+		for i, db := range a.databases {
+			if db == a.resumeAfter {
+				a.databaseIndex = (i + 1) % len(a.databases)
+				break
 			}
 		}
-		a.databaseIndex = 0
-		if len(a.databases) <= 0 {
-			a.logger.Errorf(
-				"audit #%d aborted: no databases to audit found after (re)loading the list of databases",
-				a.index)
-			withError = true
-			return noErr
+		a.resumeAfter = ""
+	}
+	if len(a.databases) <= 0 {
+		a.logger.Errorf(
+			"audit #%d aborted: no databases to audit found after (re)loading the list of databases",
+			index)
+		return errors.New("no databases to audit")
+	}
+	a.logger.Infof(
+		"audit #%d - list of databases to audit has been (re)loaded - %d database(s) found: %v",
+		index, len(a.databases), a.databases)
+	return nil
+}
+
+// mustBeAudited tells if dbName is selected by the audited prefixes or by the include patterns, if any,
+// and is neither excluded by a pattern nor denied
+func (a *defaultAuditor) mustBeAudited(dbName string) bool {
+	if _, denied := a.deniedDatabases[dbName]; denied {
+		return false
+	}
+	for _, pattern := range a.excludeDatabases {
+		if pattern.MatchString(dbName) {
+			return false
+		}
+	}
+	if len(a.auditDatabases) == 0 && len(a.includeDatabases) == 0 {
+		return true
+	}
+	for _, dbPrefix := range a.auditDatabases {
+		if strings.HasPrefix(dbName, dbPrefix) {
+			return true
+		}
+	}
+	for _, pattern := range a.includeDatabases {
+		if pattern.MatchString(dbName) {
+			return true
 		}
-		a.logger.Infof(
-			"audit #%d - list of databases to audit has been (re)loaded - %d database(s) found: %v",
-			a.index, len(a.databases), a.databases)
 	}
-	dbName := a.databases[a.databaseIndex]
-	resp, err := a.serviceClient.UseDatabase(ctx, &schema.Database{
-		Databasename: dbName,
+	return false
+}
+
+// auditDatabase checks that the current root of dbName is consistent with the last one known locally.
+// Errors are logged and reported to the metrics, so that the failure of a database does not affect the others.
+// selected is called once dbName has been successfully selected on the server.
+func (a *defaultAuditor) auditDatabase(ctx context.Context, index uint64, start time.Time, dbName string, selected func()) {
+	verified := true
+	checked := false
+	withError := false
+	skipped := false
+	serverID := "unknown"
+	var prevRoot *schema.Root
+	var root *schema.Root
+	var auditedDB string
+	ctx, span := tracing.Start(a.tracer, ctx, "audit-database",
+		tracing.Int64("audit.index", int64(index)), tracing.String("db", dbName))
+	defer func() {
+		span.SetAttributes(
+			tracing.String("server.id", serverID),
+			tracing.Bool("audit.checked", checked),
+			tracing.Bool("audit.verified", verified),
+			tracing.Bool("audit.error", withError))
+		span.End()
+		if aborted(ctx) {
+			a.logger.Infof("audit #%d of database %s aborted: %v", index, dbName, ctx.Err())
+			return
+		}
+		if a.updateMetrics != nil {
+			a.updateMetrics(
+				serverID, a.serverAddress, checked, withError, verified, prevRoot, root)
+		}
+		a.metrics.observeAudit(serverID, a.serverAddress, dbName, start, checked && !verified)
+		if withError || (checked && !verified) {
+			a.markRunFailed()
+		}
+		outcome := OutcomeVerified
+		switch {
+		case checked && !verified:
+			outcome = OutcomeTampered
+		case withError:
+			outcome = OutcomeFailed
+		case skipped:
+			outcome = OutcomeSkipped
+		}
+		a.recordOutcome(dbName, outcome)
+		var digestRoot *Root
+		if root != nil {
+			digestRoot = &Root{Index: root.GetIndex(), Hash: fmt.Sprintf("%x", root.GetRoot())}
+		}
+		a.recordDigest(start, dbName, outcome, digestRoot)
+		a.recordAudit(start, auditedDB, checked && !verified)
+	}()
+
+	var resp *schema.UseDatabaseReply
+	useCtx, useSpan := tracing.Start(a.tracer, ctx, "use-database", tracing.String("db", dbName))
+	_, err := a.withSession(useCtx, func(ctx context.Context) (err error) {
+		resp, err = a.serviceClient.UseDatabase(ctx, &schema.Database{
+			Databasename: dbName,
+		})
+		return err
 	})
+	tracing.End(useSpan, err)
 	if err != nil {
 		a.logger.Errorf("error selecting database %s: %v", dbName, err)
 		withError = true
-		return noErr
+		return
 	}
 
-	md = metadata.Pairs("authorization", resp.Token)
-	ctx = metadata.NewOutgoingContext(context.Background(), md)
+	md := metadata.Pairs("authorization", resp.Token)
+	ctx = metadata.NewOutgoingContext(ctx, md)
 
-	a.logger.Infof("audit #%d - auditing database %s\n", a.index, dbName)
-	a.databaseIndex++
+	a.logger.Infof("audit #%d - auditing database %s\n", index, dbName)
+	selected()
+	auditedDB = dbName
 
-	root, err = a.serviceClient.CurrentRoot(ctx, &empty.Empty{})
+	rootCtx, rootSpan := tracing.Start(a.tracer, ctx, "current-root")
+	root, err = a.serviceClient.CurrentRoot(rootCtx, &empty.Empty{})
+	tracing.End(rootSpan, err)
 	if err != nil {
 		a.logger.Errorf("error getting current root: %v", err)
 		withError = true
-		return noErr
+		return
 	}
 
 	if a.auditSignature == "validate" {
 		if okSig, err := root.CheckSignature(); err != nil || !okSig {
 			a.logger.Errorf(
 				"audit #%d aborted: could not verify signature on server root at %s @ %s",
-				a.index, serverID, a.serverAddress)
+				index, serverID, a.serverAddress)
 			withError = true
-			return noErr
+			return
+		}
+	}
+	if len(a.trustedPublicKeys) > 0 {
+		// the key embedded in the root is ignored, a compromised server could have swapped it
+		if okSig, err := root.CheckSignatureWith(a.trustedPublicKeys...); err != nil || !okSig {
+			a.logger.Errorf(
+				"audit #%d aborted: server root at %s @ %s is not signed by any of the trusted public keys",
+				index, serverID, a.serverAddress)
+			withError = true
+			return
 		}
 	}
 
 	isEmptyDB := len(root.GetRoot()) == 0 && root.GetIndex() == 0
 
 	serverID = a.getServerID(ctx)
+	a.mu.Lock()
 	prevRoot, err = a.history.Get(serverID, dbName)
+	// until the pinned root is proven, nothing learned from the server (or cached from it) is trusted
+	pin, pinned := a.pinnedRoots[pinKey(serverID, dbName)]
+	a.mu.Unlock()
 	if err != nil {
 		a.logger.Errorf(err.Error())
 		withError = true
-		return noErr
+		return
+	}
+	if pinned {
+		a.logger.Infof(
+			"audit #%d - consistency with pinned root %x at index %d must be proven for db %s",
+			index, pin.Hash, pin.Index, dbName)
+		prevRoot = pin.root()
 	}
 	if prevRoot != nil {
 		if isEmptyDB {
 			a.logger.Errorf(
 				"audit #%d aborted: database is empty on server %s @ %s, "+
 					"but locally a previous root exists with hash %x at index %d",
-				a.index, serverID, a.serverAddress, prevRoot.GetRoot(), prevRoot.GetIndex())
+				index, serverID, a.serverAddress, prevRoot.GetRoot(), prevRoot.GetIndex())
 			withError = true
-			return noErr
+			return
 		}
-		proof, err := a.serviceClient.Consistency(ctx, &schema.Index{
+		consistencyCtx, consistencySpan := tracing.Start(a.tracer, ctx, "consistency",
+			tracing.Int64("root.index", int64(prevRoot.GetIndex())))
+		proof, err := a.serviceClient.Consistency(consistencyCtx, &schema.Index{
 			Index: prevRoot.GetIndex(),
 		})
+		if err == nil {
+			consistencySpan.SetAttributes(tracing.Int64("root.new_index", int64(proof.Second)))
+		}
+		tracing.End(consistencySpan, err)
 		if err != nil {
 			a.logger.Errorf(
 				"error fetching consistency proof for previous root %d: %v",
 				prevRoot.GetIndex(), err)
 			withError = true
-			return noErr
+			return
 		}
+		fetchedAt := time.Now()
 		verified =
 			proof.Verify(schema.Root{Payload: &schema.RootIndex{Index: prevRoot.GetIndex(), Root: prevRoot.GetRoot()}})
+		a.archiveProof(serverID, dbName, index, fetchedAt, prevRoot, proof, verified)
 		firstRoot := proof.FirstRoot
 		// proof.FirstRoot is empty if check fails
 		if !verified && len(firstRoot) == 0 {
@@ -296,7 +607,7 @@ func (a *defaultAuditor) audit() error {
 		}
 		a.logger.Infof("audit #%d result:\n db: %s, consistent:	%t\n"+
 			"  firstRoot:	%x at index: %d\n  secondRoot:	%x at index: %d",
-			a.index, dbName, verified,
+			index, dbName, verified,
 			firstRoot, proof.First, proof.SecondRoot, proof.Second)
 		root = &schema.Root{
 			Payload: &schema.RootIndex{Index: proof.Second, Root: proof.SecondRoot},
@@ -308,59 +619,144 @@ func (a *defaultAuditor) audit() error {
 			Signature: nil,
 		}
 		checked = true
+		prevNotifiedRoot := &Root{
+			Index: proof.First,
+			Hash:  fmt.Sprintf("%x", firstRoot),
+			Signature: Signature{
+				Signature: base64.StdEncoding.EncodeToString(prevRoot.GetSignature().GetSignature()),
+				PublicKey: base64.StdEncoding.EncodeToString(prevRoot.GetSignature().GetPublicKey()),
+			},
+		}
+		currNotifiedRoot := &Root{
+			Index: proof.Second,
+			Hash:  fmt.Sprintf("%x", proof.SecondRoot),
+			Signature: Signature{
+				Signature: base64.StdEncoding.EncodeToString(root.GetSignature().GetSignature()),
+				PublicKey: base64.StdEncoding.EncodeToString(root.GetSignature().GetPublicKey()),
+			},
+		}
+		runAt := time.Now()
+		notifyCtx, notifySpan := tracing.Start(a.tracer, ctx, "publish-notification",
+			tracing.Bool("audit.tampered", !verified))
 		// publish audit notification
 		if len(a.notificationConfig.URL) > 0 {
 			err := a.publishAuditNotification(
 				dbName,
-				time.Now(),
+				runAt,
 				!verified,
-				&Root{
-					Index: proof.First,
-					Hash:  fmt.Sprintf("%x", firstRoot),
-					Signature: Signature{
-						Signature: base64.StdEncoding.EncodeToString(prevRoot.GetSignature().GetSignature()),
-						PublicKey: base64.StdEncoding.EncodeToString(prevRoot.GetSignature().GetPublicKey()),
-					},
-				},
-				&Root{
-					Index: proof.Second,
-					Hash:  fmt.Sprintf("%x", proof.SecondRoot),
-					Signature: Signature{
-						Signature: base64.StdEncoding.EncodeToString(root.GetSignature().GetSignature()),
-						PublicKey: base64.StdEncoding.EncodeToString(root.GetSignature().GetPublicKey()),
-					},
-				},
+				prevNotifiedRoot,
+				currNotifiedRoot,
 			)
 			if err != nil {
+				notifySpan.RecordError(err)
 				a.logger.Errorf(
 					"error publishing audit notification for db %s: %v", dbName, err)
+			} else if a.notificationConfig.throttled() {
+				a.logger.Infof(
+					"audit notification for db %s has been queued for %s",
+					dbName, a.notificationConfig.URL)
 			} else {
 				a.logger.Infof(
 					"audit notification for db %s has been published at %s",
 					dbName, a.notificationConfig.URL)
 			}
 		}
+		a.notify(notifyCtx, &AuditNotification{
+			ServerID:     serverID,
+			DB:           dbName,
+			RunAt:        runAt,
+			Tampered:     !verified,
+			PreviousRoot: prevNotifiedRoot,
+			CurrentRoot:  currNotifiedRoot,
+		})
+		notifySpan.End()
+		if !verified {
+			a.tampered(AuditResult{
+				ServerID:      serverID,
+				ServerAddress: a.serverAddress,
+				DB:            dbName,
+				AuditIndex:    index,
+				RunAt:         runAt,
+				PreviousRoot:  prevRoot,
+				CurrentRoot:   root,
+			})
+		}
 	} else if isEmptyDB {
 		a.logger.Warningf("audit #%d canceled: database is empty on server %s @ %s",
-			a.index, serverID, a.serverAddress)
-		return noErr
+			index, serverID, a.serverAddress)
+		skipped = true
+		return
 	}
 
 	if !verified {
 		a.logger.Warningf(
 			"audit #%d detected possible tampering of db %s remote root (at index %d) "+
 				"so it will not overwrite the previous local root (at index %d)",
-			a.index, dbName, root.GetIndex(), prevRoot.GetIndex())
-	} else if prevRoot == nil || root.GetIndex() != prevRoot.GetIndex() {
-		if err := a.history.Set(root, serverID, dbName); err != nil {
+			index, dbName, root.GetIndex(), prevRoot.GetIndex())
+	} else {
+		a.mu.Lock()
+		if pinned {
+			a.logger.Infof("audit #%d - db %s proven to be consistent with pinned root at index %d",
+				index, dbName, pin.Index)
+			delete(a.pinnedRoots, pinKey(serverID, dbName))
+		}
+		if pinned || prevRoot == nil || root.GetIndex() != prevRoot.GetIndex() {
+			err = a.history.Set(root, serverID, dbName)
+		}
+		a.mu.Unlock()
+		if err != nil {
 			a.logger.Errorf(err.Error())
-			return noErr
+			withError = true
+			return
+		}
+		if a.gossip != nil {
+			a.gossipRoot(ctx, index, serverID, dbName, root)
 		}
 	}
 	a.logger.Infof("audit #%d finished in %s @ %s",
-		a.index, time.Since(start), time.Now().Format(time.RFC3339Nano))
+		index, time.Since(start), time.Now().Format(time.RFC3339Nano))
+}
 
-	return noErr
+// archiveProof appends the consistency proof to the archive, if any. Errors are logged and do not fail the audit.
+func (a *defaultAuditor) archiveProof(
+	serverID string,
+	dbName string,
+	index uint64,
+	fetchedAt time.Time,
+	prevRoot *schema.Root,
+	proof *schema.ConsistencyProof,
+	verified bool,
+) {
+	if a.proofArchive == nil {
+		return
+	}
+	raw, err := proto.Marshal(proof)
+	if err == nil {
+		err = a.proofArchive.Append(&ArchivedProof{
+			ServerID:      serverID,
+			ServerAddress: a.serverAddress,
+			DB:            dbName,
+			AuditIndex:    index,
+			FetchedAt:     fetchedAt,
+			PreviousRoot: &Root{
+				Index: prevRoot.GetIndex(),
+				Hash:  fmt.Sprintf("%x", prevRoot.GetRoot()),
+				Signature: Signature{
+					Signature: base64.StdEncoding.EncodeToString(prevRoot.GetSignature().GetSignature()),
+					PublicKey: base64.StdEncoding.EncodeToString(prevRoot.GetSignature().GetPublicKey()),
+				},
+			},
+			CurrentRoot: &Root{
+				Index: proof.Second,
+				Hash:  fmt.Sprintf("%x", proof.SecondRoot),
+			},
+			Proof:    raw,
+			Verified: verified,
+		})
+	}
+	if err != nil {
+		a.logger.Errorf("audit #%d - error archiving consistency proof of db %s: %v", index, dbName, err)
+	}
 }
 
 // Signature ...
@@ -404,17 +800,92 @@ func (a *defaultAuditor) publishAuditNotification(
 		CurrentRoot:  currRoot,
 	}
 
-	reqBody, err := json.Marshal(payload)
+	n := heldNotification{serverAddress: a.serverAddress, payload: payload}
+	if a.notificationConfig.throttled() {
+		return a.holdNotification(n)
+	}
+
+	reqBody, err := a.formatNotification(n)
 	if err != nil {
 		return err
 	}
+	return a.sendNotification(reqBody)
+}
+
+// sendNotification posts the body of one or more audit notifications, queueing it in the dead letter queue, if any,
+// if the endpoint is unavailable, and publishing the notifications queued so far once the endpoint is back
+func (a *defaultAuditor) sendNotification(reqBody []byte) error {
+	if err := a.postNotificationWithRetries(reqBody); err != nil {
+		if nErr, ok := err.(*notificationError); a.deadLetters == nil || (ok && !nErr.retryable) {
+			return err
+		}
+		if qErr := a.deadLetters.push(reqBody); qErr != nil {
+			return fmt.Errorf("%v; the notification could not be queued either: %v", err, qErr)
+		}
+		return fmt.Errorf("%v; the notification has been queued to be sent again later", err)
+	}
+
+	if a.deadLetters != nil {
+		sent, err := a.deadLetters.flush(a.postNotification)
+		if sent > 0 {
+			a.logger.Infof("%d queued audit notification(s) have been published at %s", sent, a.notificationConfig.URL)
+		}
+		if err != nil {
+			a.logger.Warningf("error publishing queued audit notifications: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// postNotificationWithRetries publishes an audit notification, retrying up to MaxRetries times with exponential backoff
+// if the notification endpoint is unavailable
+func (a *defaultAuditor) postNotificationWithRetries(reqBody []byte) error {
+	sleep := a.notificationConfig.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	backoff := a.notificationConfig.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := a.postNotification(reqBody)
+		if err == nil || attempt >= a.notificationConfig.MaxRetries {
+			return err
+		}
+		if nErr, ok := err.(*notificationError); ok && !nErr.retryable {
+			return err
+		}
+		sleep(backoff)
+		if backoff *= 2; backoff > maxNotificationRetryBackoff {
+			backoff = maxNotificationRetryBackoff
+		}
+	}
+}
+
+// notificationError is returned by postNotification when the notification endpoint rejects a notification
+type notificationError struct {
+	msg string
+	// retryable is false if the notification has been rejected as invalid, so that sending it again is pointless
+	retryable bool
+}
 
+func (e *notificationError) Error() string {
+	return e.msg
+}
+
+func (a *defaultAuditor) postNotification(reqBody []byte) error {
 	req, err := http.NewRequest("POST", a.notificationConfig.URL, bytes.NewBuffer(reqBody))
 	if err != nil {
-		return err
+		return &notificationError{msg: err.Error()}
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	contentType := a.notificationConfig.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	if err = authenticateNotification(req, reqBody, a.notificationConfig); err != nil {
+		return &notificationError{msg: err.Error()}
+	}
 
 	resp, err := a.notificationConfig.publishFunc(req)
 	if err != nil {
@@ -426,16 +897,105 @@ func (a *defaultAuditor) publishAuditNotification(
 	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
 	default:
 		respBody, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf(
-			"POST %s request with body %s: "+
-				"got unexpected response status %s with response body %s",
-			a.notificationConfig.URL, reqBody,
-			resp.Status, respBody)
+		return &notificationError{
+			msg: fmt.Sprintf(
+				"POST %s request with body %s: "+
+					"got unexpected response status %s with response body %s",
+				a.notificationConfig.URL, reqBody,
+				resp.Status, respBody),
+			retryable: resp.StatusCode >= 500 ||
+				resp.StatusCode == http.StatusRequestTimeout ||
+				resp.StatusCode == http.StatusTooManyRequests,
+		}
 	}
 
 	return nil
 }
 
+// auditAccessControlState compares the hash of the users and permissions of the server with the one seen
+// at the previous audit, notifying any change since unauthorized permission changes are a tampering vector
+func (a *defaultAuditor) auditAccessControlState(ctx context.Context, start time.Time) {
+	var acs *schema.AccessControlState
+	ctx, err := a.withSession(ctx, func(ctx context.Context) (err error) {
+		acs, err = a.serviceClient.GetAccessControlState(ctx, &empty.Empty{})
+		return err
+	})
+	if err != nil {
+		a.logger.Errorf("error getting the users and permissions state: %v", err)
+		return
+	}
+	hash := hex.EncodeToString(acs.GetHash())
+
+	a.mu.Lock()
+	previous := a.state.AccessControlHash
+	a.state.AccessControlHash = hash
+	a.mu.Unlock()
+
+	if previous == "" || previous == hash {
+		return
+	}
+	a.logger.Warningf("the users or permissions of the server @ %s changed since the previous audit: state hash %s -> %s",
+		a.serverAddress, previous, hash)
+	a.notify(ctx, &AuditNotification{
+		ServerID: a.getServerID(ctx),
+		RunAt:    start,
+		AccessControl: &AccessControlChange{
+			PreviousHash: previous,
+			CurrentHash:  hash,
+			Users:        acs.GetUsers(),
+		},
+	})
+}
+
+// recordAudit updates the auditor state after an audit and persists it, if a state store is set
+func (a *defaultAuditor) recordAudit(at time.Time, db string, tampered bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.state.Index = a.index
+	if db != "" {
+		a.state.LastDatabase = db
+		dbState := a.state.database(db)
+		dbState.LastAuditAt = at
+		dbState.Audits++
+		if tampered {
+			dbState.Tampered++
+		}
+	}
+	a.saveState()
+}
+
+// saveState persists the auditor state, if a state store is set. The caller must hold a.mu.
+func (a *defaultAuditor) saveState() {
+	if a.stateStore == nil {
+		return
+	}
+	if err := a.stateStore.Save(a.serverAddress, a.state); err != nil {
+		a.logger.Errorf("error saving auditor state: %v", err)
+	}
+}
+
+// notify publishes n to all the configured notifiers, failures are logged without stopping the auditor
+func (a *defaultAuditor) notify(ctx context.Context, n *AuditNotification) {
+	for _, notifier := range a.notifiers {
+		if err := notifier.Notify(ctx, n); err != nil {
+			a.logger.Errorf("error publishing audit notification for db %s with %T: %v", n.DB, notifier, err)
+		}
+	}
+}
+
+// tampered hands result to the OnTamper hook, if any. A panicking hook is logged and does not stop the auditor.
+func (a *defaultAuditor) tampered(result AuditResult) {
+	if a.onTamper == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger.Errorf("OnTamper hook panicked for db %s: %v", result.DB, r)
+		}
+	}()
+	a.onTamper(result)
+}
+
 func (a *defaultAuditor) getServerID(
 	ctx context.Context,
 ) string {
@@ -461,21 +1021,53 @@ func (a *defaultAuditor) getServerID(
 
 // repeat executes f every interval until stopc is closed or f returns an error.
 // It executes f once right after being called.
+// repeat runs f, then again every next() from the start of the previous run, until stopc is closed or f fails
 func repeat(
-	interval time.Duration,
+	next func() time.Duration,
 	stopc <-chan struct{},
 	f func() error,
 ) error {
-	tick := time.NewTicker(interval)
-	defer tick.Stop()
 	for {
+		start := time.Now()
 		if err := f(); err != nil {
 			return err
 		}
+		wait := next() - time.Since(start)
+		if wait < 0 {
+			wait = 0
+		}
+		timer := time.NewTimer(wait)
 		select {
 		case <-stopc:
+			timer.Stop()
 			return nil
-		case <-tick.C:
+		case <-timer.C:
 		}
 	}
 }
+
+// stopContext returns a context cancelled when stopc is closed or cancel is called
+func stopContext(stopc <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-stopc:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// ignoreCancel returns err unless it is caused by the cancellation of ctx
+func ignoreCancel(ctx context.Context, err error) error {
+	if err != nil && err == ctx.Err() {
+		return nil
+	}
+	return err
+}
+
+// aborted tells if the audit running with ctx has been cancelled, in which case its outcome is not meaningful
+func aborted(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
@@ -18,12 +18,16 @@ package auditor
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -38,7 +42,10 @@ import (
 	"github.com/codenotary/immudb/pkg/logger"
 	"github.com/codenotary/immudb/pkg/server"
 	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/codenotary/immudb/pkg/signer"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -129,7 +136,7 @@ func TestDefaultAuditorLoginErr(t *testing.T) {
 		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
 		logger.NewSimpleLogger("test", &wm))
 	assert.NoError(t, err)
-	err = auditor.(*defaultAuditor).audit()
+	err = auditor.(*defaultAuditor).audit(context.Background())
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(wm.written), 1)
 	assert.Contains(t, wm.written[len(wm.written)-1], "some login error")
@@ -166,7 +173,7 @@ func TestDefaultAuditorDatabaseListErr(t *testing.T) {
 		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
 		logger.NewSimpleLogger("test", &wm))
 	assert.NoError(t, err)
-	err = auditor.(*defaultAuditor).audit()
+	err = auditor.(*defaultAuditor).audit(context.Background())
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(wm.written), 1)
 	assert.Contains(t, wm.written[len(wm.written)-1], "some database list error")
@@ -205,7 +212,7 @@ func TestDefaultAuditorDatabaseListEmpty(t *testing.T) {
 		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
 		logger.NewSimpleLogger("test", &wm))
 	assert.NoError(t, err)
-	err = auditor.(*defaultAuditor).audit()
+	err = auditor.(*defaultAuditor).audit(context.Background())
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(wm.written), 1)
 	assert.Contains(t, wm.written[len(wm.written)-1], "no databases to audit found")
@@ -247,7 +254,7 @@ func TestDefaultAuditorUseDatabaseErr(t *testing.T) {
 		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
 		logger.NewSimpleLogger("test", &wm))
 	assert.NoError(t, err)
-	err = auditor.(*defaultAuditor).audit()
+	err = auditor.(*defaultAuditor).audit(context.Background())
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(wm.written), 1)
 	assert.Contains(t, wm.written[len(wm.written)-1], "some use database error")
@@ -292,7 +299,7 @@ func TestDefaultAuditorCurrentRootErr(t *testing.T) {
 		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
 		logger.NewSimpleLogger("test", &wm))
 	assert.NoError(t, err)
-	err = auditor.(*defaultAuditor).audit()
+	err = auditor.(*defaultAuditor).audit(context.Background())
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(wm.written), 1)
 	assert.Contains(t, wm.written[len(wm.written)-1], "some current root error")
@@ -538,6 +545,61 @@ func TestDefaultAuditorRunOnDbWithSignature(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestDefaultAuditorTrustedPublicKeys(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(
+		server.Options{}.
+			WithAuth(true).
+			WithInMemoryStore(true).
+			WithSigningKey("./../../../test/signer/ec3.key").
+			WithAdminPassword(auth.SysAdminPassword))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val`)})
+	require.NoError(t, err)
+
+	trustedKey, err := signer.LoadPublicKey("./../../../test/signer/ec3.pub")
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	untrustedKey := elliptic.Marshal(otherKey.Curve, otherKey.X, otherKey.Y)
+
+	audit := func(publicKeys ...[]byte) (withError bool) {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			fmt.Sprintf("%s:%d", "address", 0),
+			&ds,
+			"immudb",
+			"immudb",
+			nil,
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			rootservice.NewImmudbUUIDProvider(serviceClient),
+			cache.NewHistoryFileCache(dirname),
+			func(_ string, _ string, _ bool, err bool, _ bool, _ *schema.Root, _ *schema.Root) { withError = err },
+			logger.NewSimpleLogger("test", os.Stdout),
+			WithTrustedPublicKeys(publicKeys...))
+		require.NoError(t, err)
+		require.NoError(t, da.(*defaultAuditor).audit(context.Background()))
+		return withError
+	}
+
+	require.False(t, audit(trustedKey))
+	require.False(t, audit(untrustedKey, trustedKey))
+	require.True(t, audit(untrustedKey))
+}
+
 func TestDefaultAuditorRunOnDbWithFailSignature(t *testing.T) {
 	defer os.RemoveAll(dirname)
 	serviceClient := clienttest.NewImmuServiceClientMock()
@@ -583,9 +645,9 @@ func TestDefaultAuditorRunOnDbWithFailSignature(t *testing.T) {
 
 	auditorDone := make(chan struct{}, 2)
 	err = da.Run(time.Duration(10), true, context.TODO().Done(), auditorDone)
-	assert.Nil(t, err)
+	assert.True(t, errors.Is(err, ErrAuditFailed))
 	err = da.Run(time.Duration(10), true, context.TODO().Done(), auditorDone)
-	assert.Nil(t, err)
+	assert.True(t, errors.Is(err, ErrAuditFailed))
 }
 
 func TestDefaultAuditorRunOnDbWithWrongAuditSignatureMode(t *testing.T) {
@@ -698,3 +760,261 @@ func TestPublishAuditNotification(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid control character in URL")
 }
+
+func TestDefaultAuditorRunOnDbWithPinnedRoot(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val`)})
+	require.NoError(t, err)
+	pinnedRoot, err := serviceClient.CurrentRoot(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val2`)})
+	require.NoError(t, err)
+
+	uuidProvider := rootservice.NewImmudbUUIDProvider(serviceClient)
+
+	newAuditor := func(pin PinnedRoot) *defaultAuditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			fmt.Sprintf("%s:%d", "address", 0),
+			&ds,
+			"immudb",
+			"immudb",
+			nil,
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			uuidProvider,
+			cache.NewHistoryFileCache(dirname),
+			func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
+			logger.NewSimpleLogger("test", os.Stdout),
+			WithPinnedRoots(pin))
+		require.NoError(t, err)
+		return da.(*defaultAuditor)
+	}
+
+	serverID := newAuditor(PinnedRoot{}).getServerID(ctx)
+	dbs, err := serviceClient.DatabaseList(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Len(t, dbs.Databases, 1)
+	dbName := dbs.Databases[0].Databasename
+
+	// a spoofed pin is never proven, so nothing gets trusted
+	da := newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: pinnedRoot.GetIndex(), Hash: []byte(`spoofed`)})
+	require.NoError(t, da.audit(context.Background()))
+	require.Len(t, da.pinnedRoots, 1)
+	prevRoot, err := da.history.Get(serverID, dbName)
+	require.NoError(t, err)
+	require.Nil(t, prevRoot)
+
+	da = newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: pinnedRoot.GetIndex(), Hash: pinnedRoot.GetRoot()})
+	require.NoError(t, da.audit(context.Background()))
+	require.Len(t, da.pinnedRoots, 0)
+	prevRoot, err = da.history.Get(serverID, dbName)
+	require.NoError(t, err)
+	require.NotNil(t, prevRoot)
+	require.Equal(t, pinnedRoot.GetIndex()+1, prevRoot.GetIndex())
+}
+
+func TestDefaultAuditorOnTamper(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val`)})
+	require.NoError(t, err)
+	root, err := serviceClient.CurrentRoot(ctx, &empty.Empty{})
+	require.NoError(t, err)
+
+	uuidProvider := rootservice.NewImmudbUUIDProvider(serviceClient)
+
+	var results []AuditResult
+	metrics := NewMetrics(prometheus.NewRegistry())
+	newAuditor := func(pin PinnedRoot, onTamper func(AuditResult)) *defaultAuditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			fmt.Sprintf("%s:%d", "address", 0),
+			&ds,
+			"immudb",
+			"immudb",
+			nil,
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			uuidProvider,
+			cache.NewHistoryFileCache(dirname),
+			nil,
+			logger.NewSimpleLogger("test", os.Stdout),
+			WithPinnedRoots(pin),
+			WithOnTamper(onTamper),
+			WithMetrics(metrics))
+		require.NoError(t, err)
+		return da.(*defaultAuditor)
+	}
+
+	serverID := newAuditor(PinnedRoot{}, nil).getServerID(ctx)
+	dbs, err := serviceClient.DatabaseList(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	dbName := dbs.Databases[0].Databasename
+
+	// a consistent server does not trigger the hook
+	da := newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: root.GetIndex(), Hash: root.GetRoot()},
+		func(result AuditResult) { results = append(results, result) })
+	require.NoError(t, da.audit(context.Background()))
+	require.Empty(t, results)
+
+	// a root the server can not prove consistency with does
+	da = newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: root.GetIndex(), Hash: []byte(`spoofed`)},
+		func(result AuditResult) { results = append(results, result) })
+	require.NoError(t, da.audit(context.Background()))
+	require.Len(t, results, 1)
+	require.Equal(t, serverID, results[0].ServerID)
+	require.Equal(t, "address:0", results[0].ServerAddress)
+	require.Equal(t, dbName, results[0].DB)
+	require.Equal(t, uint64(1), results[0].AuditIndex)
+	require.Equal(t, []byte(`spoofed`), results[0].PreviousRoot.GetRoot())
+	require.Equal(t, root.GetIndex(), results[0].CurrentRoot.GetIndex())
+	require.Equal(t, 2., testutil.ToFloat64(metrics.audits.WithLabelValues(serverID, "address:0", dbName)))
+	require.Equal(t, 1., testutil.ToFloat64(metrics.tampered.WithLabelValues(serverID, "address:0", dbName)))
+
+	// a panicking hook does not stop the auditor
+	da = newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: root.GetIndex(), Hash: []byte(`spoofed`)},
+		func(result AuditResult) { panic("hook failure") })
+	require.NoError(t, da.audit(context.Background()))
+	require.Equal(t, uint64(1), da.state.Databases[dbName].Tampered)
+}
+
+func TestDefaultAuditorDatabaseFilters(t *testing.T) {
+	newAuditor := func(prefixes []string, options ...Option) *defaultAuditor {
+		a := &defaultAuditor{auditDatabases: prefixes}
+		for _, option := range options {
+			option(a)
+		}
+		return a
+	}
+	audited := func(a *defaultAuditor, dbs ...string) []string {
+		var selected []string
+		for _, db := range dbs {
+			if a.mustBeAudited(db) {
+				selected = append(selected, db)
+			}
+		}
+		return selected
+	}
+	dbs := []string{"defaultdb", "prod_orders", "prod_users", "scratch_1", "test_orders", "legacy"}
+
+	require.Equal(t, dbs, audited(newAuditor(nil), dbs...))
+	require.Equal(t, []string{"prod_orders", "prod_users"}, audited(newAuditor([]string{"prod"}), dbs...))
+
+	// include patterns select databases in addition to the prefixes
+	a := newAuditor([]string{"default"}, WithIncludeDatabases(regexp.MustCompile(`_orders$`)))
+	require.Equal(t, []string{"defaultdb", "prod_orders", "test_orders"}, audited(a, dbs...))
+
+	// exclusions and denials win over any selection
+	a = newAuditor(
+		nil,
+		WithExcludeDatabases(regexp.MustCompile(`^(scratch|test)_`)),
+		WithDeniedDatabases("legacy"))
+	require.Equal(t, []string{"defaultdb", "prod_orders", "prod_users"}, audited(a, dbs...))
+
+	a = newAuditor(
+		[]string{"prod"},
+		WithIncludeDatabases(regexp.MustCompile(`orders`)),
+		WithExcludeDatabases(regexp.MustCompile(`^test_`)),
+		WithDeniedDatabases("prod_users"))
+	require.Equal(t, []string{"prod_orders"}, audited(a, dbs...))
+}
+
+func TestDefaultAuditorAccessControl(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+
+	notifier := &recordingNotifier{}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		fmt.Sprintf("%s:%d", "address", 0),
+		&ds,
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		serviceClient,
+		rootservice.NewImmudbUUIDProvider(serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
+		logger.NewSimpleLogger("test", os.Stdout),
+		WithAccessControlAudit(),
+		WithNotifiers(notifier))
+	require.NoError(t, err)
+	auditor := da.(*defaultAuditor)
+
+	accessControlChanges := func() (changes []*AccessControlChange) {
+		for _, n := range notifier.notifications {
+			if n.AccessControl != nil {
+				changes = append(changes, n.AccessControl)
+			}
+		}
+		return changes
+	}
+
+	require.NoError(t, auditor.audit(context.Background()))
+	require.NoError(t, auditor.audit(context.Background()))
+	require.NotEmpty(t, auditor.state.AccessControlHash)
+	require.Empty(t, accessControlChanges())
+
+	_, err = serviceClient.CreateUser(ctx, &schema.CreateUserRequest{
+		User:       []byte("rwuser"),
+		Password:   []byte("rwuserPas@1"),
+		Database:   "defaultdb",
+		Permission: auth.PermissionRW,
+	})
+	require.NoError(t, err)
+	previousHash := auditor.state.AccessControlHash
+
+	require.NoError(t, auditor.audit(context.Background()))
+	changes := accessControlChanges()
+	require.Len(t, changes, 1)
+	require.Equal(t, previousHash, changes[0].PreviousHash)
+	require.Equal(t, auditor.state.AccessControlHash, changes[0].CurrentHash)
+	require.Equal(t, uint32(2), changes[0].Users)
+
+	require.NoError(t, auditor.audit(context.Background()))
+	require.Len(t, accessControlChanges(), 1)
+}
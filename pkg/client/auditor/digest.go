@@ -0,0 +1,163 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Digest periods accepted by ParseDigestPeriod besides durations
+const (
+	DigestDaily  = 24 * time.Hour
+	DigestWeekly = 7 * DigestDaily
+)
+
+// AuditDigest summarizes the audits of a server over a period, for the stakeholders who need a periodic report
+// rather than the result of every audit
+type AuditDigest struct {
+	ServerAddress string    `json:"server_address"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	// Audits is the number of audits performed, Verified, Tampered and Failed their results: the failed audits
+	// include the ones failing before a database could be selected, which are not listed in Databases
+	Audits    uint64                     `json:"audits"`
+	Verified  uint64                     `json:"verified"`
+	Tampered  uint64                     `json:"tampered"`
+	Failed    uint64                     `json:"failed"`
+	Databases map[string]*DatabaseDigest `json:"databases"`
+}
+
+// DatabaseDigest summarizes the audits of a database over the period of a digest. FirstRoot and LastRoot are the
+// first and the last root proven consistent in the period, if any, showing how far the database has grown.
+type DatabaseDigest struct {
+	Audits    uint64 `json:"audits"`
+	Verified  uint64 `json:"verified"`
+	Tampered  uint64 `json:"tampered"`
+	Failed    uint64 `json:"failed"`
+	FirstRoot *Root  `json:"first_root,omitempty"`
+	LastRoot  *Root  `json:"last_root,omitempty"`
+}
+
+// DigestNotifier publishes audit digests. The built-in notifiers implement it besides Notifier.
+type DigestNotifier interface {
+	NotifyDigest(ctx context.Context, d *AuditDigest) error
+}
+
+// ParseDigestPeriod parses the period of the audit digests: daily, weekly or a duration
+func ParseDigestPeriod(s string) (time.Duration, error) {
+	switch s {
+	case "daily":
+		return DigestDaily, nil
+	case "weekly":
+		return DigestWeekly, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid digest period %s, expected daily, weekly or a positive duration", s)
+	}
+	return d, nil
+}
+
+// record accounts the outcome of an audit of db, root being the root proven consistent by a verified audit.
+// Skipped audits are left out.
+func (d *AuditDigest) record(db string, outcome Outcome, root *Root) {
+	if outcome == OutcomeSkipped {
+		return
+	}
+	d.Audits++
+	dbDigest := &DatabaseDigest{}
+	if db != "" {
+		if d.Databases[db] == nil {
+			d.Databases[db] = &DatabaseDigest{}
+		}
+		dbDigest = d.Databases[db]
+	}
+	dbDigest.Audits++
+	switch outcome {
+	case OutcomeVerified:
+		d.Verified++
+		dbDigest.Verified++
+		if root != nil {
+			if dbDigest.FirstRoot == nil {
+				dbDigest.FirstRoot = root
+			}
+			dbDigest.LastRoot = root
+		}
+	case OutcomeTampered:
+		d.Tampered++
+		dbDigest.Tampered++
+	case OutcomeFailed:
+		d.Failed++
+		dbDigest.Failed++
+	}
+}
+
+// recordDigest accounts the outcome of an audit started at in the digest of the period including it, if digests
+// are enabled. The pending digest is part of the state, so that it survives restarts if a state store is set.
+func (a *defaultAuditor) recordDigest(at time.Time, db string, outcome Outcome, root *Root) {
+	if a.digestPeriod <= 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d := a.state.Digest
+	if d == nil || !at.Before(d.To) {
+		// the digest of an elapsed period is published at the end of the run
+		from := at.Truncate(a.digestPeriod)
+		d = &AuditDigest{
+			ServerAddress: a.serverAddress,
+			From:          from,
+			To:            from.Add(a.digestPeriod),
+			Databases:     map[string]*DatabaseDigest{},
+		}
+		if a.state.Digest != nil {
+			a.elapsedDigests = append(a.elapsedDigests, a.state.Digest)
+		}
+		a.state.Digest = d
+	}
+	d.record(db, outcome, root)
+}
+
+// publishDigests publishes the digests of the periods elapsed by now to the digest notifiers.
+// Failures are logged and the digest is not published again, as for the notifications.
+func (a *defaultAuditor) publishDigests(ctx context.Context, now time.Time) {
+	if a.digestPeriod <= 0 {
+		return
+	}
+	a.mu.Lock()
+	digests := a.elapsedDigests
+	a.elapsedDigests = nil
+	if d := a.state.Digest; d != nil && !now.Before(d.To) {
+		digests = append(digests, d)
+		a.state.Digest = nil
+		a.saveState()
+	}
+	a.mu.Unlock()
+
+	for _, d := range digests {
+		for _, notifier := range a.digestNotifiers {
+			if err := notifier.NotifyDigest(ctx, d); err != nil {
+				a.logger.Errorf("error publishing audit digest from %s to %s with %T: %v",
+					d.From.Format(time.RFC3339), d.To.Format(time.RFC3339), notifier, err)
+			}
+		}
+		a.logger.Infof("audit digest from %s to %s has been published: %d audit(s), %d verified, %d tampered, %d failed",
+			d.From.Format(time.RFC3339), d.To.Format(time.RFC3339), d.Audits, d.Verified, d.Tampered, d.Failed)
+	}
+}
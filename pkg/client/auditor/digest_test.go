@@ -0,0 +1,190 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type recordingDigestNotifier struct {
+	digests []*AuditDigest
+	err     error
+}
+
+func (r *recordingDigestNotifier) NotifyDigest(ctx context.Context, d *AuditDigest) error {
+	r.digests = append(r.digests, d)
+	return r.err
+}
+
+func TestParseDigestPeriod(t *testing.T) {
+	d, err := ParseDigestPeriod("daily")
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, d)
+
+	d, err = ParseDigestPeriod("weekly")
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, d)
+
+	d, err = ParseDigestPeriod("12h")
+	require.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, d)
+
+	_, err = ParseDigestPeriod("monthly")
+	assert.Error(t, err)
+	_, err = ParseDigestPeriod("-1h")
+	assert.Error(t, err)
+}
+
+func TestDefaultAuditorDigest(t *testing.T) {
+	ok := &recordingDigestNotifier{}
+	failing := &recordingDigestNotifier{err: errors.New("unavailable")}
+	a := &defaultAuditor{
+		serverAddress: "localhost:3322",
+		logger:        logger.NewSimpleLogger("test", os.Stdout),
+		state:         &State{Databases: map[string]*DatabaseState{}},
+	}
+
+	// digests are disabled by default
+	day := time.Date(2020, 11, 13, 0, 0, 0, 0, time.UTC)
+	a.recordDigest(day.Add(time.Hour), "db1", OutcomeVerified, nil)
+	require.Nil(t, a.state.Digest)
+
+	WithDigest(DigestDaily, failing, ok)(a)
+	a.recordDigest(day.Add(time.Hour), "db1", OutcomeVerified, &Root{Index: 1, Hash: "h1"})
+	a.recordDigest(day.Add(2*time.Hour), "db1", OutcomeVerified, &Root{Index: 5, Hash: "h5"})
+	a.recordDigest(day.Add(3*time.Hour), "db2", OutcomeTampered, nil)
+	a.recordDigest(day.Add(4*time.Hour), "db2", OutcomeSkipped, nil)
+	a.recordDigest(day.Add(5*time.Hour), "", OutcomeFailed, nil)
+
+	// the period is not over yet
+	a.publishDigests(context.Background(), day.Add(23*time.Hour))
+	require.Empty(t, ok.digests)
+
+	d := a.state.Digest
+	require.NotNil(t, d)
+	assert.Equal(t, "localhost:3322", d.ServerAddress)
+	assert.Equal(t, day, d.From)
+	assert.Equal(t, day.Add(24*time.Hour), d.To)
+	assert.Equal(t, uint64(4), d.Audits)
+	assert.Equal(t, uint64(2), d.Verified)
+	assert.Equal(t, uint64(1), d.Tampered)
+	assert.Equal(t, uint64(1), d.Failed)
+	require.Len(t, d.Databases, 2)
+	assert.Equal(t, &DatabaseDigest{
+		Audits:    2,
+		Verified:  2,
+		FirstRoot: &Root{Index: 1, Hash: "h1"},
+		LastRoot:  &Root{Index: 5, Hash: "h5"},
+	}, d.Databases["db1"])
+	assert.Equal(t, &DatabaseDigest{Audits: 1, Tampered: 1}, d.Databases["db2"])
+
+	// an audit in the following period starts a new digest, the elapsed one is published at the end of the run
+	a.recordDigest(day.Add(25*time.Hour), "db1", OutcomeVerified, &Root{Index: 7, Hash: "h7"})
+	require.NotSame(t, d, a.state.Digest)
+	a.publishDigests(context.Background(), day.Add(25*time.Hour))
+	assert.Equal(t, []*AuditDigest{d}, ok.digests)
+	assert.Equal(t, []*AuditDigest{d}, failing.digests)
+
+	// the pending digest is published once its period is over, even without further audits
+	next := a.state.Digest
+	a.publishDigests(context.Background(), day.Add(48*time.Hour))
+	assert.Equal(t, []*AuditDigest{d, next}, ok.digests)
+	assert.Nil(t, a.state.Digest)
+	a.publishDigests(context.Background(), day.Add(72*time.Hour))
+	assert.Len(t, ok.digests, 2)
+}
+
+func TestDefaultAuditorDigestAudit(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword))
+	bs.Start()
+
+	ds := []grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	defer clientConn.Close()
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lr, err := serviceClient.Login(context.TODO(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lr.Token))
+	_, err = serviceClient.CreateDatabase(ctx, &schema.Database{Databasename: "db1"})
+	require.NoError(t, err)
+	ur, err := serviceClient.UseDatabase(ctx, &schema.Database{Databasename: "db1"})
+	require.NoError(t, err)
+	dbCtx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", ur.Token))
+	_, err = serviceClient.Set(dbCtx, &schema.KeyValue{Key: []byte("key"), Value: []byte("value")})
+	require.NoError(t, err)
+
+	n := &recordingDigestNotifier{}
+	newAuditor := func() *defaultAuditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			"address:0",
+			&ds,
+			"immudb",
+			"immudb",
+			[]string{"db"},
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			rootservice.NewImmudbUUIDProvider(serviceClient),
+			cache.NewHistoryFileCache(dirname),
+			nil,
+			logger.NewSimpleLogger("test", os.Stdout),
+			WithStateStore(NewFileStateStore(dirname)),
+			WithDigest(DigestWeekly, n))
+		require.NoError(t, err)
+		return da.(*defaultAuditor)
+	}
+
+	da := newAuditor()
+	require.NoError(t, da.audit(context.Background()))
+	require.NoError(t, da.audit(context.Background()))
+	require.Empty(t, n.digests)
+
+	// the pending digest survives restarts
+	da = newAuditor()
+	d := da.state.Digest
+	require.NotNil(t, d)
+	assert.Equal(t, uint64(2), d.Audits)
+	assert.Equal(t, uint64(2), d.Verified)
+	require.Contains(t, d.Databases, "db1")
+	assert.Equal(t, time.Monday, d.From.UTC().Weekday())
+	assert.Equal(t, DigestWeekly, d.To.Sub(d.From))
+
+	da.publishDigests(context.Background(), d.To)
+	require.Len(t, n.digests, 1)
+	assert.Equal(t, uint64(2), n.digests[0].Databases["db1"].Audits)
+	assert.Nil(t, newAuditor().state.Digest)
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// GossipPath is the path of the gossip endpoint, relative to the base URL of every peer
+const GossipPath = "/gossip/roots"
+
+// maxPeerRoots is the number of roots received from the peers kept for every server and database until checked
+const maxPeerRoots = 64
+
+// maxGossipBody bounds the size of the roots received from a peer in a single request
+const maxGossipBody = 1 << 20
+
+// ObservedRoot is a root of a database trusted by an auditor, i.e. proven consistent with the roots it trusted before
+type ObservedRoot struct {
+	Auditor    string    `json:"auditor"`
+	ServerID   string    `json:"server_id"`
+	DB         string    `json:"db"`
+	Index      uint64    `json:"index"`
+	Hash       string    `json:"hash"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// RootDivergence reports that two auditors trusted roots of the same database which are not part of the same history:
+// either the roots differ at the same index or the server could not prove the root of the peer consistent with the local one.
+// Each auditor alone sees a consistent history, the server is showing them different views of it.
+type RootDivergence struct {
+	ServerID string       `json:"server_id"`
+	DB       string       `json:"db"`
+	Local    ObservedRoot `json:"local"`
+	Peer     ObservedRoot `json:"peer"`
+}
+
+// GossipConfig configures the exchange of the observed roots between auditors
+type GossipConfig struct {
+	// ID identifies the auditor to its peers, the host name is used if empty
+	ID string
+	// Peers are the base URLs, e.g. http://auditor-2:9478, of the gossip endpoints of the other auditors, see StartGossipServer
+	Peers []string
+	// HMACKey, if set, authenticates the roots exchanged with the peers, which must be configured with the same key
+	HMACKey        []byte
+	RequestTimeout time.Duration
+}
+
+type localRoot struct {
+	root     ObservedRoot
+	diverged func(RootDivergence)
+}
+
+// Gossip shares the roots observed by the auditors with their peers and compares them with the roots the peers observed,
+// detecting split-view attacks that a single auditor cannot see.
+// A single Gossip can be shared by the auditors of several servers, see WithGossip.
+type Gossip struct {
+	config GossipConfig
+	client *http.Client
+
+	mu sync.Mutex
+	// last root observed locally for every server and database
+	local map[string]localRoot
+	// roots received from the peers and not checked yet, for every server and database
+	peers map[string][]ObservedRoot
+}
+
+// NewGossip validates config and creates a Gossip, the endpoint receiving the roots of the peers is served by StartGossipServer
+func NewGossip(config GossipConfig) (*Gossip, error) {
+	if config.ID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		config.ID = hostname
+	}
+	for _, peer := range config.Peers {
+		u, err := url.Parse(peer)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid gossip peer %q: an http or https URL is expected", peer)
+		}
+	}
+	return &Gossip{
+		config: config,
+		client: &http.Client{Timeout: config.RequestTimeout},
+		local:  map[string]localRoot{},
+		peers:  map[string][]ObservedRoot{},
+	}, nil
+}
+
+// Roots returns the last root observed locally for every server and database
+func (g *Gossip) Roots() []ObservedRoot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	roots := make([]ObservedRoot, 0, len(g.local))
+	for _, l := range g.local {
+		roots = append(roots, l.root)
+	}
+	return roots
+}
+
+// observe records root as the last one trusted locally and publishes it to the peers.
+// diverged is called whenever a peer is found to have observed a root of the same index with a different hash.
+// All the peers are tried, the returned error reports the ones the root could not be published to.
+func (g *Gossip) observe(ctx context.Context, root ObservedRoot, diverged func(RootDivergence)) error {
+	root.Auditor = g.config.ID
+
+	g.mu.Lock()
+	g.local[pinKey(root.ServerID, root.DB)] = localRoot{root: root, diverged: diverged}
+	g.mu.Unlock()
+
+	body, err := json.Marshal([]ObservedRoot{root})
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, peer := range g.config.Peers {
+		if err := g.publish(ctx, peer, body); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", peer, err))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New("error publishing root to gossip peers: " + strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (g *Gossip) publish(ctx context.Context, peer string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(peer, "/")+GossipPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if err = authenticateNotification(req, body, AuditNotificationConfig{HMACKey: g.config.HMACKey}); err != nil {
+		return err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// ServeHTTP receives with POST the roots observed by a peer and returns with GET the roots observed locally
+func (g *Gossip) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(g.Roots())
+	case http.MethodPost:
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxGossipBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err = VerifyAuditNotification(r.Header, body, g.config.HMACKey, nil); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var roots []ObservedRoot
+		if err = json.Unmarshal(body, &roots); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		g.receive(roots)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// receive compares the roots of a peer with the local ones of the same index right away,
+// the others are kept to be proven consistent at the next audit of their database, see pending
+func (g *Gossip) receive(roots []ObservedRoot) {
+	var divergences []func()
+	g.mu.Lock()
+	for _, root := range roots {
+		if root.Auditor == g.config.ID {
+			continue
+		}
+		key := pinKey(root.ServerID, root.DB)
+		if l, ok := g.local[key]; ok && l.root.Index == root.Index {
+			if !strings.EqualFold(l.root.Hash, root.Hash) && l.diverged != nil {
+				d, diverged := RootDivergence{ServerID: root.ServerID, DB: root.DB, Local: l.root, Peer: root}, l.diverged
+				divergences = append(divergences, func() { diverged(d) })
+			}
+			continue
+		}
+		g.keep(key, root)
+	}
+	g.mu.Unlock()
+	for _, d := range divergences {
+		d()
+	}
+}
+
+// keep adds root to the roots of the peers to be checked, dropping the oldest ones beyond maxPeerRoots. g.mu must be held.
+func (g *Gossip) keep(key string, root ObservedRoot) {
+	roots := g.peers[key]
+	for _, r := range roots {
+		if r.Index == root.Index && strings.EqualFold(r.Hash, root.Hash) {
+			return
+		}
+	}
+	roots = append(roots, root)
+	if len(roots) > maxPeerRoots {
+		roots = roots[len(roots)-maxPeerRoots:]
+	}
+	g.peers[key] = roots
+}
+
+// pending removes and returns the roots received from the peers for the database, up to index included
+func (g *Gossip) pending(serverID string, db string, index uint64) []ObservedRoot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := pinKey(serverID, db)
+	var due, left []ObservedRoot
+	for _, r := range g.peers[key] {
+		if r.Index <= index {
+			due = append(due, r)
+		} else {
+			left = append(left, r)
+		}
+	}
+	if len(left) > 0 {
+		g.peers[key] = left
+	} else {
+		delete(g.peers, key)
+	}
+	return due
+}
+
+// requeue gives back a root returned by pending which could not be checked
+func (g *Gossip) requeue(root ObservedRoot) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.keep(pinKey(root.ServerID, root.DB), root)
+}
+
+// StartGossipServer serves the gossip endpoint of g at GossipPath on address.
+// The listener is set up before returning, the returned server must be shut down by the caller.
+func StartGossipServer(address string, g *Gossip) (*http.Server, error) {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(GossipPath, g)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+	return srv, nil
+}
+
+// gossipRoot proves the roots published by the peers for the database consistent with root, which has just been trusted,
+// then publishes root to the peers
+func (a *defaultAuditor) gossipRoot(ctx context.Context, index uint64, serverID string, db string, root *schema.Root) {
+	local := ObservedRoot{
+		Auditor:    a.gossip.config.ID,
+		ServerID:   serverID,
+		DB:         db,
+		Index:      root.GetIndex(),
+		Hash:       fmt.Sprintf("%x", root.GetRoot()),
+		ObservedAt: time.Now(),
+	}
+	diverged := func(d RootDivergence) { a.rootsDiverged(index, d) }
+	for _, peer := range a.gossip.pending(serverID, db, local.Index) {
+		if peer.Index == local.Index {
+			if !strings.EqualFold(peer.Hash, local.Hash) {
+				diverged(RootDivergence{ServerID: serverID, DB: db, Local: local, Peer: peer})
+			}
+			continue
+		}
+		peerHash, err := hex.DecodeString(peer.Hash)
+		if err != nil {
+			a.logger.Warningf("audit #%d - ignoring malformed root %q of db %s from gossip peer %s", index, peer.Hash, db, peer.Auditor)
+			continue
+		}
+		proof, err := a.serviceClient.Consistency(ctx, &schema.Index{Index: peer.Index})
+		if err != nil {
+			a.logger.Errorf("error fetching consistency proof for root %d of gossip peer %s: %v", peer.Index, peer.Auditor, err)
+			a.gossip.requeue(peer)
+			continue
+		}
+		if proof.Second != local.Index || !bytes.Equal(proof.SecondRoot, root.GetRoot()) {
+			// the server moved on since root was fetched: root must be proven consistent with the same newer root
+			link, err := a.serviceClient.Consistency(ctx, &schema.Index{Index: local.Index})
+			if err != nil || link.Second != proof.Second || !bytes.Equal(link.SecondRoot, proof.SecondRoot) {
+				a.gossip.requeue(peer)
+				continue
+			}
+			if !link.Verify(schema.Root{Payload: &schema.RootIndex{Index: local.Index, Root: root.GetRoot()}}) {
+				diverged(RootDivergence{ServerID: serverID, DB: db, Local: local, Peer: peer})
+				continue
+			}
+		}
+		if !proof.Verify(schema.Root{Payload: &schema.RootIndex{Index: peer.Index, Root: peerHash}}) {
+			diverged(RootDivergence{ServerID: serverID, DB: db, Local: local, Peer: peer})
+		}
+	}
+	if err := a.gossip.observe(ctx, local, diverged); err != nil {
+		a.logger.Warningf("audit #%d - %v", index, err)
+	}
+}
+
+// rootsDiverged alerts that a peer auditor trusted a root of the database which is not part of the history trusted locally
+// by the audit index
+func (a *defaultAuditor) rootsDiverged(index uint64, d RootDivergence) {
+	a.logger.Errorf(
+		"split view of db %s on server %s @ %s: root %s at index %d trusted by %s diverges from root %s at index %d trusted by %s",
+		d.DB, d.ServerID, a.serverAddress, d.Local.Hash, d.Local.Index, d.Local.Auditor, d.Peer.Hash, d.Peer.Index, d.Peer.Auditor)
+	a.markRunFailed()
+	a.recordOutcome(d.DB, OutcomeTampered)
+
+	runAt := time.Now()
+	a.notify(context.Background(), &AuditNotification{
+		ServerID:     d.ServerID,
+		DB:           d.DB,
+		RunAt:        runAt,
+		Tampered:     true,
+		PreviousRoot: &Root{Index: d.Peer.Index, Hash: d.Peer.Hash},
+		CurrentRoot:  &Root{Index: d.Local.Index, Hash: d.Local.Hash},
+		Divergence:   &d,
+	})
+	peerHash, _ := hex.DecodeString(d.Peer.Hash)
+	localHash, _ := hex.DecodeString(d.Local.Hash)
+	a.tampered(AuditResult{
+		ServerID:      d.ServerID,
+		ServerAddress: a.serverAddress,
+		DB:            d.DB,
+		AuditIndex:    index,
+		RunAt:         runAt,
+		PreviousRoot:  &schema.Root{Payload: &schema.RootIndex{Index: d.Peer.Index, Root: peerHash}},
+		CurrentRoot:   &schema.Root{Payload: &schema.RootIndex{Index: d.Local.Index, Root: localHash}},
+		Divergence:    &d,
+	})
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newGossipPeer(t *testing.T, config GossipConfig) (*Gossip, *httptest.Server) {
+	g, err := NewGossip(config)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	mux.Handle(GossipPath, g)
+	return g, httptest.NewServer(mux)
+}
+
+func TestGossipExchange(t *testing.T) {
+	b, bsrv := newGossipPeer(t, GossipConfig{ID: "b", HMACKey: []byte(`secret`)})
+	defer bsrv.Close()
+	a, asrv := newGossipPeer(t, GossipConfig{ID: "a", Peers: []string{bsrv.URL + "/"}, HMACKey: []byte(`secret`)})
+	defer asrv.Close()
+
+	var divergences []RootDivergence
+	require.NoError(t, b.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 5, Hash: "bb"},
+		func(d RootDivergence) { divergences = append(divergences, d) }))
+
+	// roots of other indexes are kept to be proven consistent at the next audit
+	require.NoError(t, a.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 3, Hash: "a3"}, nil))
+	require.NoError(t, a.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 7, Hash: "a7"}, nil))
+	require.Empty(t, divergences)
+	require.Equal(t, []ObservedRoot{{Auditor: "a", ServerID: "s", DB: "db", Index: 3, Hash: "a3"}}, b.pending("s", "db", 5))
+	require.Empty(t, b.pending("s", "db", 5))
+	b.requeue(ObservedRoot{Auditor: "a", ServerID: "s", DB: "db", Index: 3, Hash: "a3"})
+	require.Len(t, b.pending("s", "db", 7), 2)
+
+	// roots of the same index are compared right away
+	require.NoError(t, a.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 5, Hash: "BB"}, nil))
+	require.Empty(t, divergences)
+	require.NoError(t, a.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 5, Hash: "aa"}, nil))
+	require.Len(t, divergences, 1)
+	require.Equal(t, "s", divergences[0].ServerID)
+	require.Equal(t, "db", divergences[0].DB)
+	require.Equal(t, "bb", divergences[0].Local.Hash)
+	require.Equal(t, "b", divergences[0].Local.Auditor)
+	require.Equal(t, "aa", divergences[0].Peer.Hash)
+	require.Equal(t, "a", divergences[0].Peer.Auditor)
+
+	require.Len(t, a.Roots(), 1)
+	require.Equal(t, uint64(5), a.Roots()[0].Index)
+}
+
+func TestGossipAuthentication(t *testing.T) {
+	b, bsrv := newGossipPeer(t, GossipConfig{ID: "b", HMACKey: []byte(`secret`)})
+	defer bsrv.Close()
+	a, err := NewGossip(GossipConfig{ID: "a", Peers: []string{bsrv.URL}, HMACKey: []byte(`other`)})
+	require.NoError(t, err)
+
+	err = a.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 1, Hash: "aa"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), bsrv.URL)
+	require.Empty(t, b.pending("s", "db", 1))
+
+	resp, err := http.Post(bsrv.URL+GossipPath, "application/json", strings.NewReader(`[]`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestGossipHTTP(t *testing.T) {
+	g, srv := newGossipPeer(t, GossipConfig{ID: "g"})
+	defer srv.Close()
+	require.NoError(t, g.observe(context.Background(), ObservedRoot{ServerID: "s", DB: "db", Index: 1, Hash: "aa"}, nil))
+
+	resp, err := http.Get(srv.URL + GossipPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post(srv.URL+GossipPath, "application/json", strings.NewReader(`{`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodDelete, srv.URL+GossipPath, nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	_, err = NewGossip(GossipConfig{Peers: []string{"auditor-2:9478"}})
+	require.Error(t, err)
+
+	s, err := StartGossipServer("127.0.0.1:0", g)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+}
+
+func TestDefaultAuditorGossip(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+
+	_, err = serviceClient.SafeSet(ctx, &schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`key1`), Value: []byte(`val1`)}})
+	require.NoError(t, err)
+	first, err := serviceClient.CurrentRoot(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	_, err = serviceClient.SafeSet(ctx, &schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`key2`), Value: []byte(`val2`)}})
+	require.NoError(t, err)
+
+	g, err := NewGossip(GossipConfig{ID: "local"})
+	require.NoError(t, err)
+	var results []AuditResult
+	notifier := &recordingNotifier{}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		fmt.Sprintf("%s:%d", "address", 0),
+		&ds,
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		serviceClient,
+		rootservice.NewImmudbUUIDProvider(serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		nil,
+		logger.NewSimpleLogger("test", os.Stdout),
+		WithGossip(g),
+		WithNotifiers(notifier),
+		WithOnTamper(func(result AuditResult) { results = append(results, result) }))
+	require.NoError(t, err)
+	a := da.(*defaultAuditor)
+
+	serverID := a.getServerID(ctx)
+	dbs, err := serviceClient.DatabaseList(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	dbName := dbs.Databases[0].Databasename
+
+	spoofed := strings.Repeat("ab", 32)
+	g.receive([]ObservedRoot{
+		// consistent with the history seen by the auditor
+		{Auditor: "peer", ServerID: serverID, DB: dbName, Index: first.GetIndex(), Hash: fmt.Sprintf("%x", first.GetRoot())},
+		// not part of the history seen by the auditor
+		{Auditor: "peer", ServerID: serverID, DB: dbName, Index: first.GetIndex(), Hash: spoofed},
+		{Auditor: "peer", ServerID: serverID, DB: dbName, Index: first.GetIndex() + 1, Hash: spoofed},
+		// not yet audited
+		{Auditor: "peer", ServerID: serverID, DB: dbName, Index: first.GetIndex() + 100, Hash: spoofed},
+	})
+
+	require.NoError(t, a.audit(context.Background()))
+	require.Len(t, results, 2)
+	for _, r := range results {
+		require.Equal(t, serverID, r.ServerID)
+		require.Equal(t, dbName, r.DB)
+		require.Equal(t, uint64(1), r.AuditIndex)
+		require.Equal(t, "peer", r.Divergence.Peer.Auditor)
+		require.Equal(t, spoofed, r.Divergence.Peer.Hash)
+		require.Equal(t, first.GetIndex()+1, r.Divergence.Local.Index)
+	}
+	require.Len(t, notifier.notifications, 2)
+	require.True(t, notifier.notifications[0].Tampered)
+	require.NotNil(t, notifier.notifications[0].Divergence)
+	require.True(t, a.lastRunFailed())
+
+	roots := g.Roots()
+	require.Len(t, roots, 1)
+	require.Equal(t, "local", roots[0].Auditor)
+	require.Equal(t, first.GetIndex()+1, roots[0].Index)
+	require.Len(t, g.pending(serverID, dbName, first.GetIndex()+100), 1)
+
+	// roots of the index trusted locally received afterwards are compared right away
+	g.receive([]ObservedRoot{{Auditor: "peer", ServerID: serverID, DB: dbName, Index: first.GetIndex() + 1, Hash: roots[0].Hash}})
+	require.Len(t, results, 2)
+	g.receive([]ObservedRoot{{Auditor: "peer", ServerID: serverID, DB: dbName, Index: first.GetIndex() + 1, Hash: spoofed}})
+	require.Len(t, results, 3)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+)
+
+// DefaultAdaptiveSuccesses is the number of consecutive successful audits after which
+// an adaptive interval shortened by a failure is doubled back toward the configured one
+const DefaultAdaptiveSuccesses = 3
+
+// intervalSchedule computes the wait between two audit runs: the configured interval, optionally
+// shortened after failed runs (adaptive mode) and randomly spread by a jitter fraction.
+type intervalSchedule struct {
+	interval time.Duration
+	jitter   float64
+
+	// adaptive mode is enabled if minInterval is positive and lower than interval
+	minInterval        time.Duration
+	successesToBackOff int
+
+	current   time.Duration
+	successes int
+	rand      *rand.Rand
+	logger    logger.Logger
+}
+
+func (a *defaultAuditor) newSchedule(interval time.Duration) *intervalSchedule {
+	successes := a.adaptiveSuccesses
+	if successes < 1 {
+		successes = DefaultAdaptiveSuccesses
+	}
+	return &intervalSchedule{
+		interval:           interval,
+		jitter:             a.intervalJitter,
+		minInterval:        a.adaptiveMinInterval,
+		successesToBackOff: successes,
+		current:            interval,
+		rand:               a.rand,
+		logger:             a.logger,
+	}
+}
+
+// next returns the wait before the next run, given the outcome of the last one
+func (s *intervalSchedule) next(failed bool) time.Duration {
+	if s.minInterval > 0 && s.minInterval < s.interval {
+		s.adapt(failed)
+	}
+	wait := s.current
+	if s.jitter > 0 {
+		wait += time.Duration((2*s.rand.Float64() - 1) * s.jitter * float64(wait))
+	}
+	return wait
+}
+
+func (s *intervalSchedule) adapt(failed bool) {
+	if failed {
+		s.successes = 0
+		if s.current == s.minInterval {
+			return
+		}
+		s.current /= 2
+		if s.current < s.minInterval {
+			s.current = s.minInterval
+		}
+		s.logger.Warningf("audit failed, shortening the audit interval to %s", s.current)
+		return
+	}
+	if s.current == s.interval {
+		return
+	}
+	s.successes++
+	if s.successes < s.successesToBackOff {
+		return
+	}
+	s.successes = 0
+	s.current *= 2
+	if s.current > s.interval {
+		s.current = s.interval
+	}
+	s.logger.Infof("%d successful audits in a row, backing off the audit interval to %s", s.successesToBackOff, s.current)
+}
+
+// startRun clears the outcome of the previous audit run
+func (a *defaultAuditor) startRun() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.runFailed = false
+	a.outcomes = nil
+}
+
+// markRunFailed records that the current audit run met an error or a verification failure
+func (a *defaultAuditor) markRunFailed() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.runFailed = true
+}
+
+// lastRunFailed tells whether the last audit run met an error or a verification failure
+func (a *defaultAuditor) lastRunFailed() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.runFailed
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestScheduleAuditor(options ...Option) *defaultAuditor {
+	a := &defaultAuditor{
+		logger: logger.NewSimpleLogger("auditor_test", os.Stderr),
+		rand:   rand.New(rand.NewSource(1)),
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+func TestIntervalScheduleFixed(t *testing.T) {
+	s := newTestScheduleAuditor().newSchedule(time.Minute)
+	require.Equal(t, time.Minute, s.next(false))
+	require.Equal(t, time.Minute, s.next(true))
+}
+
+func TestIntervalScheduleJitter(t *testing.T) {
+	s := newTestScheduleAuditor(WithIntervalJitter(0.1)).newSchedule(time.Minute)
+	spread := false
+	for i := 0; i < 100; i++ {
+		wait := s.next(false)
+		require.True(t, wait >= 54*time.Second && wait <= 66*time.Second, wait)
+		spread = spread || wait != time.Minute
+	}
+	require.True(t, spread)
+
+	a := newTestScheduleAuditor(WithIntervalJitter(2))
+	require.Equal(t, 1.0, a.intervalJitter)
+	a = newTestScheduleAuditor(WithIntervalJitter(-1))
+	require.Equal(t, 0.0, a.intervalJitter)
+}
+
+func TestIntervalScheduleAdaptive(t *testing.T) {
+	s := newTestScheduleAuditor(WithAdaptiveInterval(10*time.Second, 2)).newSchedule(time.Minute)
+	require.Equal(t, time.Minute, s.next(false))
+	require.Equal(t, 30*time.Second, s.next(true))
+	require.Equal(t, 15*time.Second, s.next(true))
+	require.Equal(t, 10*time.Second, s.next(true))
+	require.Equal(t, 10*time.Second, s.next(true))
+
+	require.Equal(t, 10*time.Second, s.next(false))
+	require.Equal(t, 20*time.Second, s.next(false))
+	require.Equal(t, 20*time.Second, s.next(false))
+	// a failure resets the count of successes
+	require.Equal(t, 10*time.Second, s.next(true))
+	require.Equal(t, 10*time.Second, s.next(false))
+	require.Equal(t, 20*time.Second, s.next(false))
+	require.Equal(t, 20*time.Second, s.next(false))
+	require.Equal(t, 40*time.Second, s.next(false))
+	require.Equal(t, 40*time.Second, s.next(false))
+	require.Equal(t, time.Minute, s.next(false))
+	require.Equal(t, time.Minute, s.next(false))
+
+	// disabled if the minimum is not lower than the interval
+	s = newTestScheduleAuditor(WithAdaptiveInterval(time.Minute, 0)).newSchedule(time.Minute)
+	require.Equal(t, time.Minute, s.next(true))
+	require.Equal(t, DefaultAdaptiveSuccesses, s.successesToBackOff)
+}
+
+func TestRunOutcome(t *testing.T) {
+	a := newTestScheduleAuditor()
+	a.markRunFailed()
+	require.True(t, a.lastRunFailed())
+	a.startRun()
+	require.False(t, a.lastRunFailed())
+}
+
+func TestRepeat(t *testing.T) {
+	stopc := make(chan struct{})
+	runs := 0
+	waits := 0
+	err := repeat(func() time.Duration {
+		waits++
+		if runs == 3 {
+			return time.Hour
+		}
+		return 0
+	}, stopc, func() error {
+		runs++
+		if runs == 3 {
+			close(stopc)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, runs)
+	require.Equal(t, 3, waits)
+}
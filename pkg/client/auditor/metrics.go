@@ -0,0 +1,112 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	metricsNamespace = "immuclient"
+	metricsSubsystem = "auditor"
+)
+
+var metricsLabels = []string{"server_id", "server_address", "db"}
+
+// Metrics holds the Prometheus metrics of the audits, labelled by server and database.
+// A nil *Metrics is valid and does not record anything.
+type Metrics struct {
+	audits        *prometheus.CounterVec
+	tampered      *prometheus.CounterVec
+	lastAudit     *prometheus.GaugeVec
+	auditDuration *prometheus.HistogramVec
+}
+
+// NewMetrics creates the audit metrics and registers them on reg, e.g. prometheus.DefaultRegisterer.
+// Metrics already registered by another auditor sharing reg are reused.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	return &Metrics{
+		audits: registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "audits_total",
+			Help:      "Number of audits run, including the failed ones.",
+		}, metricsLabels)).(*prometheus.CounterVec),
+		tampered: registerCollector(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "tampered_total",
+			Help:      "Number of audits which detected a possible tampering.",
+		}, metricsLabels)).(*prometheus.CounterVec),
+		lastAudit: registerCollector(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "last_audit_timestamp_seconds",
+			Help:      "Timestamp in unix seconds at which the latest audit started.",
+		}, metricsLabels)).(*prometheus.GaugeVec),
+		auditDuration: registerCollector(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubsystem,
+			Name:      "audit_duration_seconds",
+			Help:      "Duration of the audits.",
+			Buckets:   prometheus.DefBuckets,
+		}, metricsLabels)).(*prometheus.HistogramVec),
+	}
+}
+
+// registerCollector registers c, returning the existing collector instead if an identical one was already registered
+func registerCollector(reg prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+	}
+	return c
+}
+
+// observeAudit records an audit of db started at start. Audits failed before a database could be selected
+// are recorded with an empty db.
+func (m *Metrics) observeAudit(serverID string, serverAddress string, db string, start time.Time, tampered bool) {
+	if m == nil {
+		return
+	}
+	m.audits.WithLabelValues(serverID, serverAddress, db).Inc()
+	if tampered {
+		m.tampered.WithLabelValues(serverID, serverAddress, db).Inc()
+	}
+	m.lastAudit.WithLabelValues(serverID, serverAddress, db).Set(float64(start.UnixNano()) / 1e9)
+	m.auditDuration.WithLabelValues(serverID, serverAddress, db).Observe(time.Since(start).Seconds())
+}
+
+// StartMetricsServer serves the metrics gathered by g, e.g. prometheus.DefaultGatherer, at /metrics on address.
+// The listener is set up before returning, the returned server must be shut down by the caller.
+func StartMetricsServer(address string, g prometheus.Gatherer) (*http.Server, error) {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(g, promhttp.HandlerOpts{}))
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(l)
+	return srv, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	var nilMetrics *Metrics
+	nilMetrics.observeAudit("server", "address:0", "db", time.Now(), true)
+
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+	// auditors sharing a registry share the metrics too
+	require.Equal(t, m, NewMetrics(reg))
+
+	start := time.Unix(1600000000, 0)
+	m.observeAudit("server", "address:0", "db", start, false)
+	m.observeAudit("server", "address:0", "db", start, true)
+	m.observeAudit("unknown", "address:0", "", start, false)
+
+	require.Equal(t, 2., testutil.ToFloat64(m.audits.WithLabelValues("server", "address:0", "db")))
+	require.Equal(t, 1., testutil.ToFloat64(m.tampered.WithLabelValues("server", "address:0", "db")))
+	require.Equal(t, 1., testutil.ToFloat64(m.audits.WithLabelValues("unknown", "address:0", "")))
+	require.Equal(t, 1600000000., testutil.ToFloat64(m.lastAudit.WithLabelValues("server", "address:0", "db")))
+}
+
+func TestStartMetricsServer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+	m.observeAudit("server", "address:0", "db", time.Now(), false)
+
+	_, err := StartMetricsServer("invalid address", reg)
+	require.Error(t, err)
+
+	srv, err := StartMetricsServer("127.0.0.1:19477", reg)
+	require.NoError(t, err)
+	defer srv.Close()
+
+	resp, err := http.Get("http://127.0.0.1:19477/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(body), `immuclient_auditor_audits_total{db="db",server_address="address:0",server_id="server"} 1`))
+	require.True(t, strings.Contains(string(body), "immuclient_auditor_audit_duration_seconds_bucket"))
+}
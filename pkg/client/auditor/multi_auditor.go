@@ -0,0 +1,174 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// ErrNoEndpoints is returned when a MultiAuditor is created without servers to audit
+var ErrNoEndpoints = errors.New("at least one immudb server to audit is required")
+
+// Endpoint is an immudb server audited by a MultiAuditor
+type Endpoint struct {
+	Address        string
+	DialOptions    []grpc.DialOption
+	Username       string
+	PasswordBase64 string
+	// AuditDatabases are the prefixes of the names of the databases to audit, all databases are audited if empty
+	AuditDatabases []string
+	// HistoryNamespace isolates the roots of this server in the shared history cache, it defaults to Address
+	HistoryNamespace string
+}
+
+// MultiAuditor audits several immudb servers from a single process:
+// each audit run checks the next database of the next server, cycling through all of them.
+type MultiAuditor struct {
+	auditors []*defaultAuditor
+	conns    []*grpc.ClientConn
+	next     int
+	last     int
+	logger   logger.Logger
+}
+
+// NewMultiAuditor connects to the given endpoints and returns an auditor cycling through them.
+// The options are applied to the auditor of every endpoint.
+func NewMultiAuditor(
+	endpoints []Endpoint,
+	auditSignature string,
+	notificationConfig AuditNotificationConfig,
+	history cache.HistoryCache,
+	updateMetrics func(string, string, bool, bool, bool, *schema.Root, *schema.Root),
+	log logger.Logger,
+	options ...Option) (*MultiAuditor, error) {
+
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	m := &MultiAuditor{logger: log}
+	for _, endpoint := range endpoints {
+		conn, err := grpc.Dial(endpoint.Address, endpoint.DialOptions...)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("error connecting to immudb server @ %s: %v", endpoint.Address, err)
+		}
+		m.conns = append(m.conns, conn)
+
+		namespace := endpoint.HistoryNamespace
+		if namespace == "" {
+			namespace = endpoint.Address
+		}
+		serviceClient := schema.NewImmuServiceClient(conn)
+		a, err := DefaultAuditor(
+			0,
+			endpoint.Address,
+			&endpoint.DialOptions,
+			endpoint.Username,
+			endpoint.PasswordBase64,
+			endpoint.AuditDatabases,
+			auditSignature,
+			notificationConfig,
+			serviceClient,
+			rootservice.NewImmudbUUIDProvider(serviceClient),
+			cache.NewNamespacedHistoryCache(history, namespace),
+			updateMetrics,
+			log,
+			options...)
+		if err != nil {
+			m.Close()
+			return nil, fmt.Errorf("error creating auditor for immudb server @ %s: %v", endpoint.Address, err)
+		}
+		m.auditors = append(m.auditors, a.(*defaultAuditor))
+	}
+	return m, nil
+}
+
+// Run audits the servers in turn every interval. With singleRun, every server is audited once
+// and a *RunError holding the outcomes of all servers is returned if any of them was not verified.
+func (m *MultiAuditor) Run(
+	interval time.Duration,
+	singleRun bool,
+	stopc <-chan struct{},
+	donec chan<- struct{},
+) (err error) {
+	defer func() { donec <- struct{}{} }()
+	ctx, cancel := stopContext(stopc)
+	defer cancel()
+	return ignoreCancel(ctx, m.RunContext(ctx, interval, singleRun))
+}
+
+// RunContext is like Run, auditing the servers until ctx is done
+func (m *MultiAuditor) RunContext(ctx context.Context, interval time.Duration, singleRun bool) (err error) {
+	m.logger.Infof("starting auditor of %d servers with a %s interval ...", len(m.auditors), interval)
+
+	if singleRun {
+		var outcomes []DatabaseOutcome
+		for range m.auditors {
+			if err = m.audit(ctx); err != nil || ctx.Err() != nil {
+				break
+			}
+			outcomes = append(outcomes, m.auditors[m.last].runOutcomes()...)
+		}
+		if err == nil && ctx.Err() == nil {
+			err = runError(outcomes)
+		}
+	} else {
+		// the scheduling options are the same for all auditors: the outcome of the last audited server drives the schedule
+		schedule := m.auditors[0].newSchedule(interval)
+		err = repeat(func() time.Duration { return schedule.next(m.auditors[m.last].lastRunFailed()) }, ctx.Done(), func() error {
+			return m.audit(ctx)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	m.logger.Infof("auditor stopped")
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+func (m *MultiAuditor) audit(ctx context.Context) error {
+	a := m.auditors[m.next]
+	m.last = m.next
+	m.next = (m.next + 1) % len(m.auditors)
+	m.logger.Infof("auditing immudb server @ %s", a.serverAddress)
+	return a.audit(ctx)
+}
+
+// Close closes the connections to the audited servers
+func (m *MultiAuditor) Close() error {
+	var err error
+	for _, conn := range m.conns {
+		if cerr := conn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	m.conns = nil
+	return err
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestMultiAuditorNoEndpoints(t *testing.T) {
+	_, err := NewMultiAuditor(
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		cache.NewHistoryFileCache(dirname),
+		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
+		logger.NewSimpleLogger("test", os.Stdout))
+	require.Equal(t, ErrNoEndpoints, err)
+}
+
+func TestMultiAuditorPasswordDecodeErr(t *testing.T) {
+	_, err := NewMultiAuditor(
+		[]Endpoint{{Address: "address:0", Username: "immudb", PasswordBase64: "enc:" + string([]byte{0})}},
+		"ignore",
+		AuditNotificationConfig{},
+		cache.NewHistoryFileCache(dirname),
+		func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
+		logger.NewSimpleLogger("test", os.Stdout))
+	require.Error(t, err)
+}
+
+func TestMultiAuditorRun(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	var endpoints []Endpoint
+	for _, name := range []string{"srv1", "srv2"} {
+		bs := servertest.NewBufconnServer(server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword))
+		bs.Start()
+		endpoints = append(endpoints, Endpoint{
+			Address:          name + ":3322",
+			DialOptions:      []grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()},
+			Username:         "immudb",
+			PasswordBase64:   "immudb",
+			HistoryNamespace: name,
+		})
+	}
+
+	var mu sync.Mutex
+	audited := map[string]int{}
+	ma, err := NewMultiAuditor(
+		endpoints,
+		"ignore",
+		AuditNotificationConfig{},
+		cache.NewHistoryFileCache(dirname),
+		func(serverID string, address string, checked bool, withError bool, verified bool, prev *schema.Root, curr *schema.Root) {
+			mu.Lock()
+			defer mu.Unlock()
+			require.False(t, withError)
+			audited[address]++
+		},
+		logger.NewSimpleLogger("test", os.Stdout))
+	require.NoError(t, err)
+	defer ma.Close()
+
+	auditorDone := make(chan struct{}, 2)
+	require.NoError(t, ma.Run(time.Duration(10), true, context.TODO().Done(), auditorDone))
+	require.Equal(t, map[string]int{"srv1:3322": 1, "srv2:3322": 1}, audited)
+
+	for _, name := range []string{"srv1", "srv2"} {
+		_, err := os.Stat(filepath.Join(dirname, name))
+		require.NoError(t, err)
+	}
+
+	stopc := make(chan struct{})
+	go func() {
+		for {
+			mu.Lock()
+			done := audited["srv1:3322"] > 1 && audited["srv2:3322"] > 1
+			mu.Unlock()
+			if done {
+				close(stopc)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	require.NoError(t, ma.Run(time.Millisecond, false, stopc, auditorDone))
+}
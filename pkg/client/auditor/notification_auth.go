@@ -0,0 +1,160 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+)
+
+// Headers authenticating the body of the audit notifications published to the notification URL
+const (
+	// NotificationHMACHeader holds the hex encoded HMAC-SHA256 of the body
+	NotificationHMACHeader = "X-Immudb-Hmac-Sha256"
+	// NotificationSignatureHeader holds the base64 encoded signature of the body
+	NotificationSignatureHeader = "X-Immudb-Signature"
+	// NotificationSignatureAlgorithmHeader holds the algorithm of the signature, either ed25519 or ecdsa-sha256
+	NotificationSignatureAlgorithmHeader = "X-Immudb-Signature-Algorithm"
+)
+
+const (
+	signatureAlgorithmEd25519     = "ed25519"
+	signatureAlgorithmECDSASHA256 = "ecdsa-sha256"
+)
+
+// ErrNotificationNotAuthentic is returned when a notification doesn't carry a valid HMAC or signature
+var ErrNotificationNotAuthentic = errors.New("audit notification is not authentic")
+
+type ecdsaSignature struct {
+	R *big.Int
+	S *big.Int
+}
+
+// authenticateNotification sets the HMAC and signature headers of req, as configured, for the notification body
+func authenticateNotification(req *http.Request, body []byte, config AuditNotificationConfig) error {
+	if len(config.HMACKey) > 0 {
+		mac := hmac.New(sha256.New, config.HMACKey)
+		mac.Write(body)
+		req.Header.Set(NotificationHMACHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+	if config.Signer == nil {
+		return nil
+	}
+	var algorithm string
+	var signature []byte
+	var err error
+	switch config.Signer.Public().(type) {
+	case ed25519.PublicKey:
+		algorithm = signatureAlgorithmEd25519
+		signature, err = config.Signer.Sign(rand.Reader, body, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		algorithm = signatureAlgorithmECDSASHA256
+		digest := sha256.Sum256(body)
+		signature, err = config.Signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return fmt.Errorf("unsupported notification signing key %T, only Ed25519 and ECDSA keys are supported", config.Signer.Public())
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set(NotificationSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	req.Header.Set(NotificationSignatureAlgorithmHeader, algorithm)
+	return nil
+}
+
+// VerifyAuditNotification checks the headers of an audit notification against its body, as done by a receiver.
+// The HMAC is checked if hmacKey is not empty and the signature is checked if publicKey, an Ed25519 or ECDSA key, is not nil.
+func VerifyAuditNotification(header http.Header, body []byte, hmacKey []byte, publicKey crypto.PublicKey) error {
+	if len(hmacKey) > 0 {
+		sum, err := hex.DecodeString(header.Get(NotificationHMACHeader))
+		if err != nil {
+			return ErrNotificationNotAuthentic
+		}
+		mac := hmac.New(sha256.New, hmacKey)
+		mac.Write(body)
+		if !hmac.Equal(sum, mac.Sum(nil)) {
+			return ErrNotificationNotAuthentic
+		}
+	}
+	if publicKey == nil {
+		return nil
+	}
+	signature, err := base64.StdEncoding.DecodeString(header.Get(NotificationSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return ErrNotificationNotAuthentic
+	}
+	algorithm := header.Get(NotificationSignatureAlgorithmHeader)
+	switch pk := publicKey.(type) {
+	case ed25519.PublicKey:
+		if algorithm != signatureAlgorithmEd25519 || !ed25519.Verify(pk, body, signature) {
+			return ErrNotificationNotAuthentic
+		}
+	case *ecdsa.PublicKey:
+		var es ecdsaSignature
+		if _, err := asn1.Unmarshal(signature, &es); err != nil {
+			return ErrNotificationNotAuthentic
+		}
+		digest := sha256.Sum256(body)
+		if algorithm != signatureAlgorithmECDSASHA256 || !ecdsa.Verify(pk, digest[:], es.R, es.S) {
+			return ErrNotificationNotAuthentic
+		}
+	default:
+		return fmt.Errorf("unsupported notification public key %T, only Ed25519 and ECDSA keys are supported", publicKey)
+	}
+	return nil
+}
+
+// LoadNotificationSigningKey reads a PEM encoded Ed25519 or ECDSA private key, either in PKCS #8 or in SEC 1 form,
+// to be used as AuditNotificationConfig.Signer
+func LoadNotificationSigningKey(path string) (crypto.Signer, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no private key found in notification signing key file %s", path)
+	}
+	if block.Type == "EC PRIVATE KEY" {
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("unsupported notification signing key %T, only Ed25519 and ECDSA keys are supported", key)
+}
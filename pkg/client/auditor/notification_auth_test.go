@@ -0,0 +1,137 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func publishAuthenticatedNotification(t *testing.T, config AuditNotificationConfig) (http.Header, []byte, error) {
+	var header http.Header
+	var body []byte
+	config.URL = "http://some-non-existent-url.com"
+	config.publishFunc = func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		body, _ = ioutil.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	a := &defaultAuditor{notificationConfig: config}
+	err := a.publishAuditNotification(
+		"some-db", time.Now(), false, &Root{Index: 1, Hash: "root-hash-1"}, &Root{Index: 2, Hash: "root-hash-2"})
+	return header, body, err
+}
+
+func TestAuthenticatedAuditNotification(t *testing.T) {
+	header, body, err := publishAuthenticatedNotification(t, AuditNotificationConfig{})
+	require.NoError(t, err)
+	require.Empty(t, header.Get(NotificationHMACHeader))
+	require.Empty(t, header.Get(NotificationSignatureHeader))
+	require.NoError(t, VerifyAuditNotification(header, body, nil, nil))
+
+	hmacKey := []byte("some-hmac-key")
+	header, body, err = publishAuthenticatedNotification(t, AuditNotificationConfig{HMACKey: hmacKey})
+	require.NoError(t, err)
+	require.NoError(t, VerifyAuditNotification(header, body, hmacKey, nil))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(header, body, []byte("other-key"), nil))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(header, append(body, ' '), hmacKey, nil))
+
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	header, body, err = publishAuthenticatedNotification(t, AuditNotificationConfig{HMACKey: hmacKey, Signer: edPrivateKey})
+	require.NoError(t, err)
+	require.Equal(t, "ed25519", header.Get(NotificationSignatureAlgorithmHeader))
+	require.NoError(t, VerifyAuditNotification(header, body, hmacKey, edPublicKey))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(header, append(body, ' '), nil, edPublicKey))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(header, body, nil, &ecPrivateKey.PublicKey))
+
+	header, body, err = publishAuthenticatedNotification(t, AuditNotificationConfig{Signer: ecPrivateKey})
+	require.NoError(t, err)
+	require.Empty(t, header.Get(NotificationHMACHeader))
+	require.Equal(t, "ecdsa-sha256", header.Get(NotificationSignatureAlgorithmHeader))
+	require.NoError(t, VerifyAuditNotification(header, body, nil, &ecPrivateKey.PublicKey))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(header, append(body, ' '), nil, &ecPrivateKey.PublicKey))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(header, body, hmacKey, &ecPrivateKey.PublicKey))
+	require.Equal(t, ErrNotificationNotAuthentic, VerifyAuditNotification(http.Header{}, body, nil, &ecPrivateKey.PublicKey))
+
+	rsaPrivateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	_, _, err = publishAuthenticatedNotification(t, AuditNotificationConfig{Signer: rsaPrivateKey})
+	require.Error(t, err)
+	require.Error(t, VerifyAuditNotification(header, body, nil, &rsaPrivateKey.PublicKey))
+}
+
+func TestLoadNotificationSigningKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notification-keys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeKey := func(name, pemType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der}), 0600))
+		return path
+	}
+
+	_, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edPrivateKey)
+	require.NoError(t, err)
+	signer, err := LoadNotificationSigningKey(writeKey("ed25519.pem", "PRIVATE KEY", der))
+	require.NoError(t, err)
+	require.Equal(t, edPrivateKey, signer)
+
+	ecPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err = x509.MarshalECPrivateKey(ecPrivateKey)
+	require.NoError(t, err)
+	signer, err = LoadNotificationSigningKey(writeKey("ec.pem", "EC PRIVATE KEY", der))
+	require.NoError(t, err)
+	require.Equal(t, ecPrivateKey.D, signer.(*ecdsa.PrivateKey).D)
+
+	rsaPrivateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKCS8PrivateKey(rsaPrivateKey)
+	require.NoError(t, err)
+	_, err = LoadNotificationSigningKey(writeKey("rsa.pem", "PRIVATE KEY", der))
+	require.Error(t, err)
+
+	_, err = LoadNotificationSigningKey(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "empty.pem"), []byte("no pem here"), 0600))
+	_, err = LoadNotificationSigningKey(filepath.Join(dir, "empty.pem"))
+	require.Error(t, err)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"sync"
+	"time"
+)
+
+// maxHeldNotifications caps the notifications held for a destination, the oldest ones are dropped beyond it
+const maxHeldNotifications = 1000
+
+// heldNotification is an audit notification waiting to be sent, along with the address of the audited server
+type heldNotification struct {
+	serverAddress string
+	payload       AuditNotificationRequest
+	heldAt        time.Time
+}
+
+// notificationDestination keeps the notifications held for a notification URL. It is shared by all the auditors
+// publishing to the same URL, e.g. the ones of a MultiAuditor, so that the URL is rate limited as a whole.
+type notificationDestination struct {
+	// mu serializes the requests to the URL
+	mu       sync.Mutex
+	lastSent time.Time
+	held     []heldNotification
+}
+
+var notificationDestinations = struct {
+	sync.Mutex
+	byURL map[string]*notificationDestination
+}{byURL: map[string]*notificationDestination{}}
+
+func destinationFor(url string) *notificationDestination {
+	notificationDestinations.Lock()
+	defer notificationDestinations.Unlock()
+	d, ok := notificationDestinations.byURL[url]
+	if !ok {
+		d = &notificationDestination{}
+		notificationDestinations.byURL[url] = d
+	}
+	return d
+}
+
+// throttled is true if the notifications are rate limited or batched instead of being sent right away
+func (c AuditNotificationConfig) throttled() bool {
+	return c.MinInterval > 0 || c.BatchSize > 0
+}
+
+type flushMode int
+
+const (
+	// flushReady sends the batches which are full or have waited BatchWait, if MinInterval allows it
+	flushReady flushMode = iota
+	// flushRunEnd sends the partial batches as well if BatchWait is not set
+	flushRunEnd
+	// flushAll sends all the held notifications, regardless of MinInterval and BatchWait
+	flushAll
+)
+
+// holdNotification holds a notification for its destination, sending the held notifications which are ready
+func (a *defaultAuditor) holdNotification(n heldNotification) error {
+	n.heldAt = a.now()
+	d := destinationFor(a.notificationConfig.URL)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.held = append(d.held, n)
+	if dropped := len(d.held) - maxHeldNotifications; dropped > 0 {
+		a.logger.Warningf("too many audit notifications held for %s, dropping the %d oldest one(s)",
+			a.notificationConfig.URL, dropped)
+		d.held = append(d.held[:0], d.held[dropped:]...)
+	}
+	return a.flushHeld(d, flushReady)
+}
+
+// flushNotifications sends the notifications held for the notification URL, if any, logging the errors
+func (a *defaultAuditor) flushNotifications(mode flushMode) {
+	if len(a.notificationConfig.URL) == 0 || !a.notificationConfig.throttled() {
+		return
+	}
+	d := destinationFor(a.notificationConfig.URL)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := a.flushHeld(d, mode); err != nil {
+		a.logger.Errorf("error publishing held audit notifications at %s: %v", a.notificationConfig.URL, err)
+	}
+}
+
+// flushHeld sends the notifications held in d, one request at a time, until the held notifications are not ready
+// to be sent according to mode. d.mu must be held.
+func (a *defaultAuditor) flushHeld(d *notificationDestination, mode flushMode) error {
+	config := a.notificationConfig
+	for len(d.held) > 0 {
+		now := a.now()
+		if mode != flushAll {
+			if config.MinInterval > 0 && !d.lastSent.IsZero() && now.Sub(d.lastSent) < config.MinInterval {
+				return nil
+			}
+			if config.BatchSize > 0 && len(d.held) < config.BatchSize {
+				waited := now.Sub(d.held[0].heldAt)
+				if (config.BatchWait > 0 && waited < config.BatchWait) || (config.BatchWait <= 0 && mode != flushRunEnd) {
+					return nil
+				}
+			}
+		}
+
+		size := 1
+		if config.BatchSize > 0 {
+			size = config.BatchSize
+			if size > len(d.held) {
+				size = len(d.held)
+			}
+		}
+		batch := append([]heldNotification(nil), d.held[:size]...)
+		d.held = append(d.held[:0], d.held[size:]...)
+
+		var reqBody []byte
+		var err error
+		if config.BatchSize > 0 {
+			reqBody, err = a.formatNotificationBatch(batch)
+		} else {
+			reqBody, err = a.formatNotification(batch[0])
+		}
+		if err != nil {
+			return err
+		}
+		d.lastSent = now
+		if err = a.sendNotification(reqBody); err != nil {
+			return err
+		}
+		a.logger.Infof("%d audit notification(s) have been published at %s", len(batch), config.URL)
+	}
+	return nil
+}
+
+func (a *defaultAuditor) now() time.Time {
+	if a.notificationConfig.now != nil {
+		return a.notificationConfig.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type notificationRecorder struct {
+	bodies [][]byte
+	clock  time.Time
+}
+
+func (r *notificationRecorder) auditor(url string, config AuditNotificationConfig) *defaultAuditor {
+	config.URL = url
+	config.publishFunc = func(req *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(req.Body)
+		r.bodies = append(r.bodies, body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	config.now = func() time.Time { return r.clock }
+	return &defaultAuditor{
+		serverAddress:      "immudb:3322",
+		notificationConfig: config,
+		logger:             logger.NewSimpleLogger("test", os.Stdout),
+	}
+}
+
+func publishNotification(t *testing.T, a *defaultAuditor, db string) {
+	require.NoError(t, a.publishAuditNotification(db, time.Now(), false, &Root{Index: 1}, &Root{Index: 2}))
+}
+
+func TestNotificationRateLimit(t *testing.T) {
+	r := &notificationRecorder{clock: time.Now()}
+	a := r.auditor("http://rate-limited-url.com", AuditNotificationConfig{MinInterval: time.Minute})
+
+	publishNotification(t, a, "db1")
+	publishNotification(t, a, "db2")
+	publishNotification(t, a, "db3")
+	require.Len(t, r.bodies, 1)
+
+	// held notifications are sent one at a time, once the interval has elapsed
+	a.flushNotifications(flushRunEnd)
+	require.Len(t, r.bodies, 1)
+	r.clock = r.clock.Add(time.Minute)
+	a.flushNotifications(flushRunEnd)
+	require.Len(t, r.bodies, 2)
+	var req AuditNotificationRequest
+	require.NoError(t, json.Unmarshal(r.bodies[1], &req))
+	require.Equal(t, "db2", req.DB)
+
+	// the destination is shared by the auditors publishing to the same URL
+	other := r.auditor("http://rate-limited-url.com", AuditNotificationConfig{MinInterval: time.Minute})
+	publishNotification(t, other, "db4")
+	require.Len(t, r.bodies, 2)
+
+	// stopping the auditor sends all the held notifications
+	a.flushNotifications(flushAll)
+	require.Len(t, r.bodies, 4)
+	require.NoError(t, json.Unmarshal(r.bodies[3], &req))
+	require.Equal(t, "db4", req.DB)
+}
+
+func TestNotificationBatch(t *testing.T) {
+	r := &notificationRecorder{clock: time.Now()}
+	a := r.auditor("http://batched-url.com", AuditNotificationConfig{BatchSize: 2, BatchWait: time.Minute})
+
+	publishNotification(t, a, "db1")
+	a.flushNotifications(flushRunEnd)
+	require.Empty(t, r.bodies)
+	publishNotification(t, a, "db2")
+	require.Len(t, r.bodies, 1)
+	var batch []AuditNotificationRequest
+	require.NoError(t, json.Unmarshal(r.bodies[0], &batch))
+	require.Len(t, batch, 2)
+	require.Equal(t, "db1", batch[0].DB)
+	require.Equal(t, "db2", batch[1].DB)
+
+	// a partial batch is sent once it has waited BatchWait
+	publishNotification(t, a, "db3")
+	r.clock = r.clock.Add(time.Minute)
+	a.flushNotifications(flushReady)
+	require.Len(t, r.bodies, 2)
+	require.NoError(t, json.Unmarshal(r.bodies[1], &batch))
+	require.Len(t, batch, 1)
+
+	// without BatchWait, partial batches are sent at the end of the audit run
+	tmpl, err := ParseNotificationTemplate(`{"dbs": [{{range $i, $n := .}}{{if $i}}, {{end}}{{json $n.DB}}{{end}}]}`)
+	require.NoError(t, err)
+	a = r.auditor("http://batched-templated-url.com", AuditNotificationConfig{BatchSize: 10, Template: tmpl})
+	publishNotification(t, a, "db1")
+	publishNotification(t, a, "db2")
+	a.flushNotifications(flushReady)
+	require.Len(t, r.bodies, 2)
+	a.flushNotifications(flushRunEnd)
+	require.Len(t, r.bodies, 3)
+	require.JSONEq(t, `{"dbs": ["db1", "db2"]}`, string(r.bodies[2]))
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxNotificationRetryBackoff caps the wait between two attempts to publish an audit notification
+const maxNotificationRetryBackoff = time.Minute
+
+const deadLetterExt = ".json"
+
+// deadLetterQueue keeps the audit notifications which could not be published in a directory, one file per notification,
+// named after the time it was queued so that notifications are sent again in their original order
+type deadLetterQueue struct {
+	dir string
+	// mu serializes the access to dir by auditors working in parallel
+	mu  sync.Mutex
+	seq uint64
+}
+
+func newDeadLetterQueue(dir string) *deadLetterQueue {
+	return &deadLetterQueue{dir: dir}
+}
+
+// push queues the body of a notification, which may include credentials and is therefore readable by the owner only
+func (q *deadLetterQueue) push(body []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := os.MkdirAll(q.dir, 0700); err != nil {
+		return err
+	}
+	q.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), q.seq%1000000, deadLetterExt)
+	return ioutil.WriteFile(filepath.Join(q.dir, name), body, 0600)
+}
+
+// flush publishes the queued notifications in order, removing them once published.
+// It stops at the first notification which can't be published because the endpoint is unavailable, while
+// notifications rejected as invalid by the endpoint are dropped. It returns the number of published notifications.
+func (q *deadLetterQueue) flush(publish func(body []byte) error) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	files, err := ioutil.ReadDir(q.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), deadLetterExt) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	sent := 0
+	var dropped []string
+	for _, name := range names {
+		path := filepath.Join(q.dir, name)
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return sent, err
+		}
+		if err = publish(body); err != nil {
+			if nErr, ok := err.(*notificationError); !ok || nErr.retryable {
+				return sent, err
+			}
+			dropped = append(dropped, name)
+		} else {
+			sent++
+		}
+		if err = os.Remove(path); err != nil {
+			return sent, err
+		}
+	}
+	if len(dropped) > 0 {
+		return sent, fmt.Errorf("queued notifications %s have been rejected and dropped", strings.Join(dropped, ", "))
+	}
+	return sent, nil
+}
+
+// len returns the number of queued notifications
+func (q *deadLetterQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	matches, _ := filepath.Glob(filepath.Join(q.dir, "*"+deadLetterExt))
+	return len(matches)
+}
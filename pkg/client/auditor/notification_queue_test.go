@@ -0,0 +1,152 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishAuditNotificationRetries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notification-queue")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var statuses []int
+	var published []string
+	var sleeps []time.Duration
+	a := &defaultAuditor{
+		logger: logger.NewSimpleLogger("test", ioutil.Discard),
+		notificationConfig: AuditNotificationConfig{
+			URL:          "http://some-non-existent-url.com",
+			MaxRetries:   3,
+			RetryBackoff: 40 * time.Second,
+			publishFunc: func(req *http.Request) (*http.Response, error) {
+				status := http.StatusNoContent
+				if len(statuses) > 0 {
+					status, statuses = statuses[0], statuses[1:]
+				}
+				if status == 0 {
+					return nil, errors.New("connection refused")
+				}
+				if status == http.StatusNoContent {
+					body, _ := ioutil.ReadAll(req.Body)
+					published = append(published, string(body))
+				}
+				return &http.Response{
+					Status:     http.StatusText(status),
+					StatusCode: status,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}, nil
+			},
+			sleep: func(d time.Duration) {
+				sleeps = append(sleeps, d)
+			},
+		},
+		deadLetters: newDeadLetterQueue(dir),
+	}
+	runAt := time.Now()
+	publish := func(db string) error {
+		return a.publishAuditNotification(db, runAt, false, &Root{Index: 1}, &Root{Index: 2})
+	}
+
+	// the endpoint recovers before retries are exhausted
+	statuses = []int{0, http.StatusServiceUnavailable, http.StatusNoContent}
+	require.NoError(t, publish("db1"))
+	require.Equal(t, []time.Duration{40 * time.Second, 60 * time.Second}, sleeps)
+	require.Len(t, published, 1)
+	require.Contains(t, published[0], `"db":"db1"`)
+
+	// notifications are queued once retries are exhausted
+	sleeps = nil
+	statuses = []int{0, 0, 0, 0, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+	err = publish("db2")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "queued")
+	require.Len(t, sleeps, 3)
+	require.Error(t, publish("db3"))
+	require.Equal(t, 2, a.deadLetters.len())
+
+	// invalid notifications are neither retried nor queued
+	sleeps = nil
+	statuses = []int{http.StatusBadRequest}
+	err = publish("db4")
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "queued")
+	require.Empty(t, sleeps)
+	require.Equal(t, 2, a.deadLetters.len())
+
+	// queued notifications are flushed in order after the next successful publish
+	published = nil
+	statuses = nil
+	require.NoError(t, publish("db5"))
+	require.Len(t, published, 3)
+	require.Contains(t, published[0], `"db":"db5"`)
+	require.Contains(t, published[1], `"db":"db2"`)
+	require.Contains(t, published[2], `"db":"db3"`)
+	require.Equal(t, 0, a.deadLetters.len())
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notification-queue")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	q := newDeadLetterQueue(dir + "/server")
+	sent, err := q.flush(func([]byte) error { return nil })
+	require.NoError(t, err)
+	require.Equal(t, 0, sent)
+
+	for _, body := range []string{"n1", "n2", "n3", "n4"} {
+		require.NoError(t, q.push([]byte(body)))
+	}
+
+	// a rejected notification is dropped, an unavailable endpoint stops the flush
+	var published []string
+	sent, err = q.flush(func(body []byte) error {
+		switch string(body) {
+		case "n2":
+			return &notificationError{msg: "bad request"}
+		case "n3":
+			return &notificationError{msg: "unavailable", retryable: true}
+		}
+		published = append(published, string(body))
+		return nil
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, sent)
+	require.Equal(t, []string{"n1"}, published)
+	require.Equal(t, 2, q.len())
+
+	published = nil
+	sent, err = q.flush(func(body []byte) error {
+		published = append(published, string(body))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, sent)
+	require.Equal(t, []string{"n3", "n4"}, published)
+	require.Equal(t, 0, q.len())
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"text/template"
+	"time"
+)
+
+// NotificationTemplateData is the data the notification template is executed with
+type NotificationTemplateData struct {
+	ServerAddress string
+	DB            string
+	RunAt         time.Time
+	Tampered      bool
+	PreviousRoot  *Root
+	CurrentRoot   *Root
+	// Username and Password are the ones of AuditNotificationConfig, for endpoints expecting credentials in the body
+	Username string
+	Password string
+}
+
+// Built-in notification templates, selected by name with LoadNotificationTemplate
+var builtinNotificationTemplates = map[string]string{
+	// slack posts a message to a Slack incoming webhook
+	"slack": `{"text": {{json (printf "%s immudb audit of db %s on %s: %s (index %d -> %d)" ` +
+		`(status .Tampered ":rotating_light:" ":white_check_mark:") .DB .ServerAddress (status .Tampered "possible tampering detected" "consistent") ` +
+		`.PreviousRoot.Index .CurrentRoot.Index)}}}`,
+}
+
+var notificationTemplateFuncs = template.FuncMap{
+	// json encodes its argument as JSON, e.g. to quote and escape strings
+	"json": func(v interface{}) (string, error) {
+		bs, err := json.Marshal(v)
+		return string(bs), err
+	},
+	// status returns ifTampered or ifConsistent, according to tampered
+	"status": func(tampered bool, ifTampered string, ifConsistent string) string {
+		if tampered {
+			return ifTampered
+		}
+		return ifConsistent
+	},
+	// rfc3339 formats a time as RFC 3339
+	"rfc3339": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+}
+
+// ParseNotificationTemplate parses a text/template formatting the body of the audit notifications posted
+// to the notification URL, executed with a NotificationTemplateData, or with a []NotificationTemplateData
+// if notifications are batched. Besides the standard functions,
+// json, status and rfc3339 are available.
+func ParseNotificationTemplate(text string) (*template.Template, error) {
+	t, err := template.New("notification").Funcs(notificationTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification template: %v", err)
+	}
+	return t, nil
+}
+
+// LoadNotificationTemplate returns the built-in notification template with the given name, e.g. slack,
+// or parses the template in the file at the given path
+func LoadNotificationTemplate(nameOrPath string) (*template.Template, error) {
+	if text, ok := builtinNotificationTemplates[nameOrPath]; ok {
+		return ParseNotificationTemplate(text)
+	}
+	text, err := ioutil.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading notification template: %v", err)
+	}
+	return ParseNotificationTemplate(string(text))
+}
+
+// formatNotification returns the body of an audit notification: the AuditNotificationRequest JSON,
+// or the output of the configured template
+func (a *defaultAuditor) formatNotification(n heldNotification) ([]byte, error) {
+	if a.notificationConfig.Template == nil {
+		return json.Marshal(n.payload)
+	}
+	return a.executeNotificationTemplate(templateData(n.serverAddress, n.payload))
+}
+
+// formatNotificationBatch returns the body of a batch of audit notifications: the JSON array of their
+// AuditNotificationRequest, or the output of the configured template executed with a []NotificationTemplateData
+func (a *defaultAuditor) formatNotificationBatch(batch []heldNotification) ([]byte, error) {
+	if a.notificationConfig.Template == nil {
+		payloads := make([]AuditNotificationRequest, len(batch))
+		for i, n := range batch {
+			payloads[i] = n.payload
+		}
+		return json.Marshal(payloads)
+	}
+	data := make([]NotificationTemplateData, len(batch))
+	for i, n := range batch {
+		data[i] = templateData(n.serverAddress, n.payload)
+	}
+	return a.executeNotificationTemplate(data)
+}
+
+func (a *defaultAuditor) executeNotificationTemplate(data interface{}) ([]byte, error) {
+	var body bytes.Buffer
+	if err := a.notificationConfig.Template.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("error executing notification template: %v", err)
+	}
+	return body.Bytes(), nil
+}
+
+func templateData(serverAddress string, payload AuditNotificationRequest) NotificationTemplateData {
+	return NotificationTemplateData{
+		ServerAddress: serverAddress,
+		DB:            payload.DB,
+		RunAt:         payload.RunAt,
+		Tampered:      payload.Tampered,
+		PreviousRoot:  payload.PreviousRoot,
+		CurrentRoot:   payload.CurrentRoot,
+		Username:      payload.Username,
+		Password:      payload.Password,
+	}
+}
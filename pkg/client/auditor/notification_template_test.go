@@ -0,0 +1,110 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func publishTemplatedNotification(t *testing.T, config AuditNotificationConfig, tampered bool) (http.Header, []byte, error) {
+	var header http.Header
+	var body []byte
+	config.URL = "http://some-non-existent-url.com"
+	config.publishFunc = func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		body, _ = ioutil.ReadAll(req.Body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	a := &defaultAuditor{serverAddress: "immudb:3322", notificationConfig: config}
+	runAt, err := time.Parse(time.RFC3339, "2020-11-13T00:53:42+01:00")
+	require.NoError(t, err)
+	err = a.publishAuditNotification(
+		`some "db"`, runAt, tampered, &Root{Index: 1, Hash: "root-hash-1"}, &Root{Index: 2, Hash: "root-hash-2"})
+	return header, body, err
+}
+
+func TestNotificationTemplate(t *testing.T) {
+	tmpl, err := ParseNotificationTemplate(
+		`{"summary": {{json .DB}}, "severity": "{{status .Tampered "critical" "info"}}", ` +
+			`"at": "{{rfc3339 .RunAt}}", "server": "{{.ServerAddress}}", "to": "{{.CurrentRoot.Hash}}", "key": "{{.Password}}"}`)
+	require.NoError(t, err)
+
+	header, body, err := publishTemplatedNotification(t,
+		AuditNotificationConfig{Password: "routing-key", Template: tmpl, ContentType: "application/vnd+json"}, true)
+	require.NoError(t, err)
+	require.Equal(t, "application/vnd+json", header.Get("Content-Type"))
+	require.JSONEq(t,
+		`{"summary": "some \"db\"", "severity": "critical", "at": "2020-11-13T00:53:42+01:00", `+
+			`"server": "immudb:3322", "to": "root-hash-2", "key": "routing-key"}`,
+		string(body))
+
+	// without template, the AuditNotificationRequest JSON is posted
+	header, body, err = publishTemplatedNotification(t, AuditNotificationConfig{}, false)
+	require.NoError(t, err)
+	require.Equal(t, "application/json", header.Get("Content-Type"))
+	var req AuditNotificationRequest
+	require.NoError(t, json.Unmarshal(body, &req))
+	require.Equal(t, `some "db"`, req.DB)
+
+	// a template failing to execute fails the notification
+	tmpl, err = ParseNotificationTemplate(`{{.Unknown}}`)
+	require.NoError(t, err)
+	_, _, err = publishTemplatedNotification(t, AuditNotificationConfig{Template: tmpl}, false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error executing notification template")
+
+	_, err = ParseNotificationTemplate(`{{.DB`)
+	require.Error(t, err)
+}
+
+func TestLoadNotificationTemplate(t *testing.T) {
+	tmpl, err := LoadNotificationTemplate("slack")
+	require.NoError(t, err)
+	_, body, err := publishTemplatedNotification(t, AuditNotificationConfig{Template: tmpl}, true)
+	require.NoError(t, err)
+	var msg map[string]string
+	require.NoError(t, json.Unmarshal(body, &msg))
+	require.Equal(t,
+		`:rotating_light: immudb audit of db some "db" on immudb:3322: possible tampering detected (index 1 -> 2)`,
+		msg["text"])
+
+	dir, err := ioutil.TempDir("", "notification_template")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "template.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"db": {{json .DB}}}`), 0644))
+	tmpl, err = LoadNotificationTemplate(path)
+	require.NoError(t, err)
+	_, body, err = publishTemplatedNotification(t, AuditNotificationConfig{Template: tmpl}, false)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"db": "some \"db\""}`, string(body))
+
+	_, err = LoadNotificationTemplate(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+}
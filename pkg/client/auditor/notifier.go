@@ -0,0 +1,154 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// AuditNotification holds the outcome of the audit of a database
+type AuditNotification struct {
+	ServerID     string    `json:"server_id"`
+	DB           string    `json:"db"`
+	RunAt        time.Time `json:"run_at"`
+	Tampered     bool      `json:"tampered"`
+	PreviousRoot *Root     `json:"previous_root"`
+	CurrentRoot  *Root     `json:"current_root"`
+	// AccessControl is set, with DB empty, when the users or permissions of the server changed since the previous audit
+	AccessControl *AccessControlChange `json:"access_control,omitempty"`
+	// Divergence is set, with Tampered, when another auditor trusted a root of DB which is not part of the local history:
+	// PreviousRoot is then the root of the peer and CurrentRoot the local one
+	Divergence *RootDivergence `json:"divergence,omitempty"`
+}
+
+// AccessControlChange holds the hashes of the users and permissions of a server before and after a change
+type AccessControlChange struct {
+	PreviousHash string `json:"previous_hash"`
+	CurrentHash  string `json:"current_hash"`
+	Users        uint32 `json:"users"`
+}
+
+// AuditResult holds the outcome of an audit which detected a possible tampering, as handed to the OnTamper hook
+type AuditResult struct {
+	ServerID      string
+	ServerAddress string
+	DB            string
+	// AuditIndex is the number of the audit run, as logged by the auditor
+	AuditIndex uint64
+	RunAt      time.Time
+	// PreviousRoot is the last trusted root, which the server failed to prove consistency with
+	PreviousRoot *schema.Root
+	// CurrentRoot is the root returned by the server, which is not trusted and not saved as the last one
+	CurrentRoot *schema.Root
+	// Divergence is set when another auditor trusted a root which is not part of the local history,
+	// PreviousRoot is then the root of the peer and CurrentRoot the local one
+	Divergence *RootDivergence
+}
+
+// Notifier publishes audit results to an alerting pipeline
+type Notifier interface {
+	Notify(ctx context.Context, n *AuditNotification) error
+}
+
+// NotifierFactory builds a notifier from its backend specific settings
+type NotifierFactory func(settings url.Values) (Notifier, error)
+
+var notifierFactories = struct {
+	factories map[string]NotifierFactory
+	sync.RWMutex
+}{factories: map[string]NotifierFactory{}}
+
+// RegisterNotifier makes a notifier backend available to NewNotifier and ParseNotifier under the given kind.
+// It panics if factory is nil or if kind has already been registered.
+func RegisterNotifier(kind string, factory NotifierFactory) {
+	if factory == nil {
+		panic("auditor: RegisterNotifier factory is nil")
+	}
+	notifierFactories.Lock()
+	defer notifierFactories.Unlock()
+	if _, ok := notifierFactories.factories[kind]; ok {
+		panic("auditor: RegisterNotifier called twice for notifier " + kind)
+	}
+	notifierFactories.factories[kind] = factory
+}
+
+// NotifierKinds returns the sorted list of the registered notifier backends
+func NotifierKinds() []string {
+	notifierFactories.RLock()
+	defer notifierFactories.RUnlock()
+	kinds := make([]string, 0, len(notifierFactories.factories))
+	for kind := range notifierFactories.factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewNotifier builds a notifier of the given registered kind
+func NewNotifier(kind string, settings url.Values) (Notifier, error) {
+	notifierFactories.RLock()
+	factory, ok := notifierFactories.factories[kind]
+	notifierFactories.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier %s, available ones are: %s", kind, strings.Join(NotifierKinds(), ", "))
+	}
+	return factory(settings)
+}
+
+// ParseNotifier builds a notifier from a spec in the kind?key=value&key=value format,
+// e.g. kafka?proxy-url=http://localhost:8082&topic=audit
+func ParseNotifier(spec string) (Notifier, error) {
+	kind, query := strings.TrimSpace(spec), ""
+	if i := strings.Index(kind, "?"); i >= 0 {
+		kind, query = kind[:i], kind[i+1:]
+	}
+	settings, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifier %s: %v", spec, err)
+	}
+	return NewNotifier(kind, settings)
+}
+
+// settingDuration parses an optional duration setting, falling back to def when it's missing
+func settingDuration(settings url.Values, key string, def time.Duration) (time.Duration, error) {
+	v := settings.Get(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s setting %s: %v", key, v, err)
+	}
+	return d, nil
+}
+
+func requireSettings(kind string, settings url.Values, keys ...string) error {
+	for _, key := range keys {
+		if settings.Get(key) == "" {
+			return fmt.Errorf("%s notifier requires the %s setting", kind, key)
+		}
+	}
+	return nil
+}
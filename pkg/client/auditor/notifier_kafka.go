@@ -0,0 +1,118 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterNotifier("kafka", func(settings url.Values) (Notifier, error) {
+		if err := requireSettings("kafka", settings, "proxy-url", "topic"); err != nil {
+			return nil, err
+		}
+		timeout, err := settingDuration(settings, "timeout", 5*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		return NewKafkaNotifier(settings.Get("proxy-url"), settings.Get("topic"), timeout), nil
+	})
+}
+
+// KafkaNotifier produces audit results to a Kafka topic through a Kafka REST Proxy (API v2).
+// Records are keyed by database, so that the results of the same database land in the same partition,
+// and digests by server address.
+type KafkaNotifier struct {
+	ProxyURL string
+	Topic    string
+
+	publishFunc func(*http.Request) (*http.Response, error)
+}
+
+// NewKafkaNotifier returns a notifier producing to topic through the REST proxy listening at proxyURL
+func NewKafkaNotifier(proxyURL string, topic string, timeout time.Duration) *KafkaNotifier {
+	httpClient := &http.Client{Timeout: timeout}
+	return &KafkaNotifier{
+		ProxyURL:    strings.TrimSuffix(proxyURL, "/"),
+		Topic:       topic,
+		publishFunc: httpClient.Do,
+	}
+}
+
+type kafkaRecord struct {
+	Key   string             `json:"key"`
+	Value *AuditNotification `json:"value"`
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaDigestRecord struct {
+	Key   string       `json:"key"`
+	Value *AuditDigest `json:"value"`
+}
+
+type kafkaDigestRecords struct {
+	Records []kafkaDigestRecord `json:"records"`
+}
+
+// Notify ...
+func (k *KafkaNotifier) Notify(ctx context.Context, n *AuditNotification) error {
+	return k.produce(ctx, kafkaRecords{Records: []kafkaRecord{{Key: n.DB, Value: n}}})
+}
+
+// NotifyDigest ...
+func (k *KafkaNotifier) NotifyDigest(ctx context.Context, d *AuditDigest) error {
+	return k.produce(ctx, kafkaDigestRecords{Records: []kafkaDigestRecord{{Key: d.ServerAddress, Value: d}}})
+}
+
+func (k *KafkaNotifier) produce(ctx context.Context, records interface{}) error {
+	reqBody, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	u := k.ProxyURL + "/topics/" + url.PathEscape(k.Topic)
+	req, err := http.NewRequest("POST", u, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := k.publishFunc(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("POST %s: got unexpected response status %s with response body %s", u, resp.Status, respBody)
+	}
+	return nil
+}
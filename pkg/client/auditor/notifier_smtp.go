@@ -0,0 +1,131 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterNotifier("smtp", func(settings url.Values) (Notifier, error) {
+		if err := requireSettings("smtp", settings, "address", "from", "to"); err != nil {
+			return nil, err
+		}
+		return NewSMTPNotifier(
+			settings.Get("address"),
+			settings.Get("username"),
+			settings.Get("password"),
+			settings.Get("from"),
+			settings["to"]...), nil
+	})
+}
+
+// SMTPNotifier mails audit results. Authentication is performed only if a username is provided.
+type SMTPNotifier struct {
+	Address  string
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier returns a notifier mailing audit results through the SMTP server listening at address (host:port)
+func NewSMTPNotifier(address string, username string, password string, from string, to ...string) *SMTPNotifier {
+	return &SMTPNotifier{
+		Address:  address,
+		Username: username,
+		Password: password,
+		From:     from,
+		To:       to,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Notify ...
+func (s *SMTPNotifier) Notify(ctx context.Context, n *AuditNotification) error {
+	body, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	result := "verified"
+	if n.Tampered {
+		result = "TAMPERED"
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	if n.AccessControl != nil {
+		fmt.Fprintf(&msg, "Subject: immudb audit of server %s: USERS OR PERMISSIONS CHANGED\r\n", n.ServerID)
+	} else if n.Divergence != nil {
+		fmt.Fprintf(&msg, "Subject: immudb audit of db %s on server %s: SPLIT VIEW DETECTED\r\n", n.DB, n.ServerID)
+	} else {
+		fmt.Fprintf(&msg, "Subject: immudb audit of db %s on server %s: %s\r\n", n.DB, n.ServerID, result)
+	}
+	fmt.Fprintf(&msg, "Content-Type: application/json; charset=utf-8\r\n\r\n")
+	msg.Write(body)
+	msg.WriteString("\r\n")
+	return s.send(msg.Bytes())
+}
+
+// NotifyDigest ...
+func (s *SMTPNotifier) NotifyDigest(ctx context.Context, d *AuditDigest) error {
+	body, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	result := "all verified"
+	if d.Tampered > 0 {
+		result = fmt.Sprintf("%d TAMPERED", d.Tampered)
+	} else if d.Failed > 0 {
+		result = fmt.Sprintf("%d failed", d.Failed)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&msg, "Subject: immudb audit digest of server %s from %s to %s: %d audit(s), %s\r\n",
+		d.ServerAddress, d.From.Format(time.RFC3339), d.To.Format(time.RFC3339), d.Audits, result)
+	fmt.Fprintf(&msg, "Content-Type: application/json; charset=utf-8\r\n\r\n")
+	msg.Write(body)
+	msg.WriteString("\r\n")
+	return s.send(msg.Bytes())
+}
+
+func (s *SMTPNotifier) send(msg []byte) error {
+	var auth smtp.Auth
+	if len(s.Username) > 0 {
+		host, _, err := net.SplitHostPort(s.Address)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	return s.sendMail(s.Address, auth, s.From, s.To, msg)
+}
@@ -0,0 +1,80 @@
+// +build !windows,!plan9
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+	"net/url"
+)
+
+func init() {
+	RegisterNotifier("syslog", func(settings url.Values) (Notifier, error) {
+		tag := settings.Get("tag")
+		if tag == "" {
+			tag = "immudb-auditor"
+		}
+		return NewSyslogNotifier(settings.Get("network"), settings.Get("address"), tag)
+	})
+}
+
+// SyslogNotifier logs audit results to syslog: tampering is reported with critical severity,
+// successful audits with informational one.
+type SyslogNotifier struct {
+	writer syslogWriter
+}
+
+type syslogWriter interface {
+	Crit(m string) error
+	Info(m string) error
+}
+
+// NewSyslogNotifier connects to the syslog daemon at address over network, or to the local one if network is empty
+func NewSyslogNotifier(network string, address string, tag string) (*SyslogNotifier, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogNotifier{writer: w}, nil
+}
+
+// Notify ...
+func (s *SyslogNotifier) Notify(ctx context.Context, n *AuditNotification) error {
+	msg, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	if n.Tampered || n.AccessControl != nil {
+		return s.writer.Crit(string(msg))
+	}
+	return s.writer.Info(string(msg))
+}
+
+// NotifyDigest ...
+func (s *SyslogNotifier) NotifyDigest(ctx context.Context, d *AuditDigest) error {
+	msg, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	if d.Tampered > 0 {
+		return s.writer.Crit(string(msg))
+	}
+	return s.writer.Info(string(msg))
+}
@@ -0,0 +1,69 @@
+// +build !windows,!plan9
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSyslogWriter struct {
+	crit []string
+	info []string
+}
+
+func (w *fakeSyslogWriter) Crit(m string) error {
+	w.crit = append(w.crit, m)
+	return nil
+}
+
+func (w *fakeSyslogWriter) Info(m string) error {
+	w.info = append(w.info, m)
+	return nil
+}
+
+func TestSyslogNotifier(t *testing.T) {
+	w := &fakeSyslogWriter{}
+	s := &SyslogNotifier{writer: w}
+
+	n := testNotification()
+	require.NoError(t, s.Notify(context.Background(), n))
+	n.Tampered = false
+	require.NoError(t, s.Notify(context.Background(), n))
+
+	require.Len(t, w.crit, 1)
+	require.Len(t, w.info, 1)
+	assert.Contains(t, w.crit[0], `"tampered":true`)
+	assert.Contains(t, w.info[0], `"tampered":false`)
+
+	d := testDigest()
+	require.NoError(t, s.NotifyDigest(context.Background(), d))
+	d.Tampered = 0
+	require.NoError(t, s.NotifyDigest(context.Background(), d))
+	require.Len(t, w.crit, 2)
+	require.Len(t, w.info, 2)
+	assert.Contains(t, w.crit[1], `"tampered":1`)
+	assert.Contains(t, w.info[1], `"tampered":0`)
+
+	_, err := NewSyslogNotifier("udp", "", "immudb-auditor")
+	assert.Error(t, err)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	notifications []*AuditNotification
+	err           error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n *AuditNotification) error {
+	r.notifications = append(r.notifications, n)
+	return r.err
+}
+
+func testNotification() *AuditNotification {
+	runAt, _ := time.Parse(time.RFC3339, "2020-11-13T00:53:42+01:00")
+	return &AuditNotification{
+		ServerID:     "server-1",
+		DB:           "defaultdb",
+		RunAt:        runAt,
+		Tampered:     true,
+		PreviousRoot: &Root{Index: 1, Hash: "root-hash-1"},
+		CurrentRoot:  &Root{Index: 2, Hash: "root-hash-2"},
+	}
+}
+
+func TestNotifierRegistry(t *testing.T) {
+	assert.Subset(t, NotifierKinds(), []string{"kafka", "smtp"})
+
+	RegisterNotifier("test-registry", func(settings url.Values) (Notifier, error) {
+		return &recordingNotifier{}, nil
+	})
+	defer func() {
+		notifierFactories.Lock()
+		delete(notifierFactories.factories, "test-registry")
+		notifierFactories.Unlock()
+	}()
+	assert.Panics(t, func() {
+		RegisterNotifier("test-registry", func(settings url.Values) (Notifier, error) { return nil, nil })
+	})
+	assert.Panics(t, func() { RegisterNotifier("test-nil", nil) })
+
+	n, err := ParseNotifier("test-registry")
+	require.NoError(t, err)
+	assert.IsType(t, &recordingNotifier{}, n)
+
+	_, err = ParseNotifier("unknown?a=b")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown notifier unknown")
+
+	_, err = ParseNotifier("kafka?topic=%zz")
+	assert.Error(t, err)
+	_, err = ParseNotifier("kafka?topic=audit")
+	assert.EqualError(t, err, "kafka notifier requires the proxy-url setting")
+	_, err = ParseNotifier("kafka?topic=audit&proxy-url=http://localhost:8082&timeout=never")
+	assert.Error(t, err)
+
+	n, err = ParseNotifier(" kafka?proxy-url=http://localhost:8082/&topic=audit&timeout=1s ")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8082", n.(*KafkaNotifier).ProxyURL)
+
+	n, err = ParseNotifier("smtp?address=localhost:25&from=auditor@immudb.io&to=a@immudb.io&to=b@immudb.io")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a@immudb.io", "b@immudb.io"}, n.(*SMTPNotifier).To)
+}
+
+func TestKafkaNotifier(t *testing.T) {
+	k := NewKafkaNotifier("http://localhost:8082", "audit", time.Second)
+	var req *http.Request
+	var body []byte
+	k.publishFunc = func(r *http.Request) (*http.Response, error) {
+		req = r
+		body, _ = ioutil.ReadAll(r.Body)
+		return &http.Response{
+			Status:     http.StatusText(http.StatusOK),
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		}, nil
+	}
+
+	require.NoError(t, k.Notify(context.Background(), testNotification()))
+	assert.Equal(t, "http://localhost:8082/topics/audit", req.URL.String())
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", req.Header.Get("Content-Type"))
+	var records kafkaRecords
+	require.NoError(t, json.Unmarshal(body, &records))
+	require.Len(t, records.Records, 1)
+	assert.Equal(t, "defaultdb", records.Records[0].Key)
+	assert.True(t, records.Records[0].Value.Tampered)
+
+	k.publishFunc = func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			Status:     http.StatusText(http.StatusNotFound),
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(strings.NewReader("topic not found")),
+		}, nil
+	}
+	err := k.Notify(context.Background(), testNotification())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "topic not found")
+
+	k.publishFunc = func(r *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}
+	assert.EqualError(t, k.Notify(context.Background(), testNotification()), "connection refused")
+}
+
+func testDigest() *AuditDigest {
+	from, _ := time.Parse(time.RFC3339, "2020-11-13T00:00:00Z")
+	return &AuditDigest{
+		ServerAddress: "localhost:3322",
+		From:          from,
+		To:            from.Add(DigestDaily),
+		Audits:        3,
+		Verified:      2,
+		Tampered:      1,
+		Databases: map[string]*DatabaseDigest{
+			"defaultdb": {Audits: 3, Verified: 2, Tampered: 1, FirstRoot: &Root{Index: 1, Hash: "root-hash-1"}},
+		},
+	}
+}
+
+func TestKafkaNotifierDigest(t *testing.T) {
+	k := NewKafkaNotifier("http://localhost:8082", "audit", time.Second)
+	var body []byte
+	k.publishFunc = func(r *http.Request) (*http.Response, error) {
+		body, _ = ioutil.ReadAll(r.Body)
+		return &http.Response{
+			Status:     http.StatusText(http.StatusOK),
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		}, nil
+	}
+
+	require.NoError(t, k.NotifyDigest(context.Background(), testDigest()))
+	var records kafkaDigestRecords
+	require.NoError(t, json.Unmarshal(body, &records))
+	require.Len(t, records.Records, 1)
+	assert.Equal(t, "localhost:3322", records.Records[0].Key)
+	assert.Equal(t, testDigest(), records.Records[0].Value)
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	s := NewSMTPNotifier("localhost:25", "user", "pass", "auditor@immudb.io", "ops@immudb.io")
+	var msg []byte
+	var auth smtp.Auth
+	s.sendMail = func(addr string, a smtp.Auth, from string, to []string, m []byte) error {
+		assert.Equal(t, "localhost:25", addr)
+		assert.Equal(t, []string{"ops@immudb.io"}, to)
+		auth, msg = a, m
+		return nil
+	}
+
+	require.NoError(t, s.Notify(context.Background(), testNotification()))
+	assert.NotNil(t, auth)
+	assert.Contains(t, string(msg), "Subject: immudb audit of db defaultdb on server server-1: TAMPERED\r\n")
+	assert.Contains(t, string(msg), `"previous_root"`)
+
+	s.Username = ""
+	require.NoError(t, s.Notify(context.Background(), testNotification()))
+	assert.Nil(t, auth)
+
+	require.NoError(t, s.NotifyDigest(context.Background(), testDigest()))
+	assert.Contains(t, string(msg), "Subject: immudb audit digest of server localhost:3322 from "+
+		"2020-11-13T00:00:00Z to 2020-11-14T00:00:00Z: 3 audit(s), 1 TAMPERED\r\n")
+	assert.Contains(t, string(msg), `"first_root"`)
+
+	s.Username = "user"
+	s.Address = "localhost"
+	assert.Error(t, s.Notify(context.Background(), testNotification()))
+	assert.Error(t, s.NotifyDigest(context.Background(), testDigest()))
+}
+
+func TestDefaultAuditorNotify(t *testing.T) {
+	ok := &recordingNotifier{}
+	failing := &recordingNotifier{err: errors.New("unavailable")}
+	a := &defaultAuditor{logger: logger.NewSimpleLogger("test", os.Stdout)}
+	WithNotifiers(failing, ok)(a)
+
+	n := testNotification()
+	a.notify(context.Background(), n)
+	assert.Equal(t, []*AuditNotification{n}, ok.notifications)
+	assert.Equal(t, []*AuditNotification{n}, failing.notifications)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/client/tracing"
+)
+
+// Option configures optional behaviours of the default auditor
+type Option func(*defaultAuditor)
+
+// WithPinnedRoots sets externally known roots the auditor must prove consistency with
+// before trusting any root received from the server
+func WithPinnedRoots(pins ...PinnedRoot) Option {
+	return func(a *defaultAuditor) {
+		for _, pin := range pins {
+			a.pinnedRoots[pinKey(pin.ServerID, pin.Database)] = pin
+		}
+	}
+}
+
+// WithNotifiers adds notifiers the results of every audit are published to,
+// in addition to the webhook set up by AuditNotificationConfig
+func WithNotifiers(notifiers ...Notifier) Option {
+	return func(a *defaultAuditor) {
+		a.notifiers = append(a.notifiers, notifiers...)
+	}
+}
+
+// WithOnTamper sets a hook called with the result of every audit which detects a possible tampering,
+// so that embedding applications can react to it (e.g. page someone or stop writing to the server).
+// The hook is called synchronously by the auditor, concurrently when auditing with several workers.
+func WithOnTamper(onTamper func(result AuditResult)) Option {
+	return func(a *defaultAuditor) {
+		a.onTamper = onTamper
+	}
+}
+
+// WithStateStore makes the auditor persist its progress (audit counter, database rotation and per database
+// audit history) in store, resuming from the saved state when created
+func WithStateStore(store StateStore) Option {
+	return func(a *defaultAuditor) {
+		a.stateStore = store
+	}
+}
+
+// WithProofArchive makes the auditor keep in archive every consistency proof it fetches,
+// so that the whole audit trail can be verified again offline
+func WithProofArchive(archive ProofArchive) Option {
+	return func(a *defaultAuditor) {
+		a.proofArchive = archive
+	}
+}
+
+// WithTrustedPublicKeys makes the auditor reject any root whose signature does not verify against one of
+// publicKeys, regardless of the public key embedded in the root. See signer.LoadPublicKey to read them from PEM files.
+func WithTrustedPublicKeys(publicKeys ...[]byte) Option {
+	return func(a *defaultAuditor) {
+		a.trustedPublicKeys = append(a.trustedPublicKeys, publicKeys...)
+	}
+}
+
+// WithMetrics makes the auditor record the outcome of every audit in m, see NewMetrics
+func WithMetrics(m *Metrics) Option {
+	return func(a *defaultAuditor) {
+		a.metrics = m
+	}
+}
+
+// WithGossip makes the auditor publish every root it trusts through g and prove consistent with its own history
+// the roots published by the other auditors, notifying any divergence. g can be shared by several auditors.
+func WithGossip(g *Gossip) Option {
+	return func(a *defaultAuditor) {
+		a.gossip = g
+	}
+}
+
+// WithTracer makes the auditor trace every audit with tracer, e.g. an adapter of an OpenTelemetry tracer, with a
+// span per step: login, use-database, current-root, consistency and publish-notification. To trace the calls to the
+// server as well, dial it with tracing.UnaryClientInterceptor.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(a *defaultAuditor) {
+		a.tracer = tracer
+	}
+}
+
+// WithAccessControlAudit makes the auditor fetch at every run a hash of the users and permissions of the server,
+// notifying whenever it changes since the previous audit
+func WithAccessControlAudit() Option {
+	return func(a *defaultAuditor) {
+		a.auditAccessControl = true
+	}
+}
+
+// WithWorkers makes the auditor verify all the databases at every run, auditing up to workers databases in parallel.
+// By default, a single database is audited at every run, cycling through all of them.
+func WithWorkers(workers int) Option {
+	return func(a *defaultAuditor) {
+		a.workers = workers
+	}
+}
+
+// WithIncludeDatabases selects for audit the databases matching any of patterns, in addition to the ones
+// having the prefixes the auditor has been created with
+func WithIncludeDatabases(patterns ...*regexp.Regexp) Option {
+	return func(a *defaultAuditor) {
+		a.includeDatabases = append(a.includeDatabases, patterns...)
+	}
+}
+
+// WithExcludeDatabases skips the databases matching any of patterns, even if selected by a prefix or an include pattern
+func WithExcludeDatabases(patterns ...*regexp.Regexp) Option {
+	return func(a *defaultAuditor) {
+		a.excludeDatabases = append(a.excludeDatabases, patterns...)
+	}
+}
+
+// WithDeniedDatabases never audits the databases with the given names
+func WithDeniedDatabases(names ...string) Option {
+	return func(a *defaultAuditor) {
+		if a.deniedDatabases == nil {
+			a.deniedDatabases = map[string]struct{}{}
+		}
+		for _, name := range names {
+			a.deniedDatabases[name] = struct{}{}
+		}
+	}
+}
+
+// WithDatabaseIntervals makes the auditor skip the databases audited less than interval(dbName) ago, so that
+// frequently changing databases can be audited at every run while nearly static ones are checked seldom.
+// The interval the auditor is run with remains the granularity of the audits, see IntervalsByPattern.
+func WithDatabaseIntervals(interval DatabaseInterval) Option {
+	return func(a *defaultAuditor) {
+		a.databaseInterval = interval
+	}
+}
+
+// WithIntervalJitter spreads every wait between two audit runs by a random amount of up to fraction of the interval,
+// in both directions, so that auditors started together do not hit the servers at the same time.
+// fraction is clamped between 0 (no jitter) and 1.
+func WithIntervalJitter(fraction float64) Option {
+	return func(a *defaultAuditor) {
+		if fraction < 0 {
+			fraction = 0
+		} else if fraction > 1 {
+			fraction = 1
+		}
+		a.intervalJitter = fraction
+	}
+}
+
+// WithAdaptiveInterval halves the interval between audit runs, down to minInterval, after every run which met an
+// error or a verification failure, and doubles it back toward the configured interval after successes consecutive
+// successful runs (DefaultAdaptiveSuccesses if not positive), detecting issues sooner without a constant load.
+func WithAdaptiveInterval(minInterval time.Duration, successes int) Option {
+	return func(a *defaultAuditor) {
+		a.adaptiveMinInterval = minInterval
+		a.adaptiveSuccesses = successes
+	}
+}
+
+// WithDigest makes the auditor publish to notifiers, every period, a digest of the audits performed in it: the
+// databases covered, the results and the roots proven consistent. Periods are aligned to the multiples of period
+// since the zero time in UTC, see ParseDigestPeriod. The digest of a period is published by the first run ending
+// after it, so the pending digest should be persisted with WithStateStore when the auditor runs once per process.
+func WithDigest(period time.Duration, notifiers ...DigestNotifier) Option {
+	return func(a *defaultAuditor) {
+		a.digestPeriod = period
+		a.digestNotifiers = append(a.digestNotifiers, notifiers...)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// PinnedRoot is an externally known root of a database which the auditor trusts
+type PinnedRoot struct {
+	ServerID string
+	Database string
+	Index    uint64
+	Hash     []byte
+}
+
+// ParsePinnedRoot parses a pinned root in the serverID:database:index:hexhash format
+func ParsePinnedRoot(s string) (PinnedRoot, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 4 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return PinnedRoot{}, fmt.Errorf("invalid pinned root %s: expected format is serverID:database:index:hash", s)
+	}
+	index, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return PinnedRoot{}, fmt.Errorf("invalid pinned root %s: %v", s, err)
+	}
+	hash, err := hex.DecodeString(parts[3])
+	if err != nil || len(hash) == 0 {
+		return PinnedRoot{}, fmt.Errorf("invalid pinned root %s: hash must be hex encoded", s)
+	}
+	return PinnedRoot{
+		ServerID: parts[0],
+		Database: parts[1],
+		Index:    index,
+		Hash:     hash,
+	}, nil
+}
+
+func (p PinnedRoot) root() *schema.Root {
+	return &schema.Root{
+		Payload: &schema.RootIndex{
+			Index: p.Index,
+			Root:  p.Hash,
+		},
+	}
+}
+
+func pinKey(serverID string, db string) string {
+	return serverID + ":" + db
+}
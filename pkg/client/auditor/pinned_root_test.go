@@ -0,0 +1,47 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePinnedRoot(t *testing.T) {
+	pin, err := ParsePinnedRoot(" bs6c1gn3ig4gamf7nlf0:defaultdb:42:0a0b0c ")
+	require.NoError(t, err)
+	require.Equal(t, PinnedRoot{
+		ServerID: "bs6c1gn3ig4gamf7nlf0",
+		Database: "defaultdb",
+		Index:    42,
+		Hash:     []byte{0x0a, 0x0b, 0x0c},
+	}, pin)
+	require.Equal(t, uint64(42), pin.root().GetIndex())
+
+	for _, invalid := range []string{
+		"",
+		"serverid:defaultdb:42",
+		":defaultdb:42:0a0b0c",
+		"serverid:defaultdb:index:0a0b0c",
+		"serverid:defaultdb:42:nothex",
+		"serverid:defaultdb:42:",
+	} {
+		_, err = ParsePinnedRoot(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/golang/protobuf/proto"
+)
+
+// ArchivedProof is a consistency proof fetched by the auditor, together with everything needed to verify it again offline
+type ArchivedProof struct {
+	ServerID      string    `json:"server_id"`
+	ServerAddress string    `json:"server_address"`
+	DB            string    `json:"db"`
+	AuditIndex    uint64    `json:"audit_index"`
+	FetchedAt     time.Time `json:"fetched_at"`
+	// PreviousRoot is the root trusted by the auditor, CurrentRoot is the one proven by the server
+	PreviousRoot *Root `json:"previous_root"`
+	CurrentRoot  *Root `json:"current_root"`
+	// Proof is the protobuf encoded schema.ConsistencyProof, as received from the server
+	Proof    []byte `json:"proof"`
+	Verified bool   `json:"verified"`
+}
+
+// Verify checks again that the archived proof proves the consistency between the previous and the current root
+func (p *ArchivedProof) Verify() (bool, error) {
+	if p.PreviousRoot == nil || p.CurrentRoot == nil {
+		return false, fmt.Errorf("archived proof of db %s has no roots", p.DB)
+	}
+	var proof schema.ConsistencyProof
+	if err := proto.Unmarshal(p.Proof, &proof); err != nil {
+		return false, fmt.Errorf("error decoding archived proof of db %s: %v", p.DB, err)
+	}
+	prevHash, err := hex.DecodeString(p.PreviousRoot.Hash)
+	if err != nil {
+		return false, fmt.Errorf("error decoding previous root of db %s: %v", p.DB, err)
+	}
+	currHash, err := hex.DecodeString(p.CurrentRoot.Hash)
+	if err != nil {
+		return false, fmt.Errorf("error decoding current root of db %s: %v", p.DB, err)
+	}
+	verified := proof.Verify(schema.Root{Payload: &schema.RootIndex{Index: p.PreviousRoot.Index, Root: prevHash}})
+	return verified &&
+		proof.Second == p.CurrentRoot.Index &&
+		bytes.Equal(proof.SecondRoot, currHash), nil
+}
+
+// ProofArchive keeps the consistency proofs fetched by the auditor, so that the audit trail can be verified again offline
+type ProofArchive interface {
+	Append(proof *ArchivedProof) error
+}
+
+type fileProofArchive struct {
+	mu            sync.Mutex
+	path          string
+	dir           bool
+	slugifyRegExp *regexp.Regexp
+}
+
+// NewFileProofArchive returns a proof archive appending every proof as a json line to the file at path.
+// If path is an existing directory, the proofs are appended to a file per audited server inside it.
+// Archive files are never truncated nor rewritten.
+func NewFileProofArchive(path string) ProofArchive {
+	info, err := os.Stat(path)
+	return &fileProofArchive{
+		path:          path,
+		dir:           err == nil && info.IsDir(),
+		slugifyRegExp: regexp.MustCompile(`[^a-zA-Z0-9\-_.]+`),
+	}
+}
+
+func (a *fileProofArchive) file(serverAddress string) string {
+	if !a.dir {
+		return a.path
+	}
+	return filepath.Join(a.path, a.slugifyRegExp.ReplaceAllString(serverAddress, "_")+".proofs.jsonl")
+}
+
+// Append writes the proof at the end of the archive, synced to disk before returning
+func (a *fileProofArchive) Append(proof *ArchivedProof) error {
+	raw, err := json.Marshal(proof)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	path := a.file(proof.ServerAddress)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("error opening proof archive %s: %v", path, err)
+	}
+	if _, err = f.Write(append(raw, '\n')); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ReadProofArchive reads all the proofs of an archive written by a file proof archive
+func ReadProofArchive(r io.Reader) ([]*ArchivedProof, error) {
+	var proofs []*ArchivedProof
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		proof := &ArchivedProof{}
+		if err := json.Unmarshal(scanner.Bytes(), proof); err != nil {
+			return nil, fmt.Errorf("error reading archived proof at line %d: %v", line, err)
+		}
+		proofs = append(proofs, proof)
+	}
+	return proofs, scanner.Err()
+}
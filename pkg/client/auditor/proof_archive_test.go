@@ -0,0 +1,156 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestDefaultAuditorProofArchive(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	archiveDir, err := ioutil.TempDir("", "proof_archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(archiveDir)
+
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val`)})
+	require.NoError(t, err)
+	root, err := serviceClient.CurrentRoot(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val2`)})
+	require.NoError(t, err)
+
+	uuidProvider := rootservice.NewImmudbUUIDProvider(serviceClient)
+
+	newAuditor := func(pin PinnedRoot) *defaultAuditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			fmt.Sprintf("%s:%d", "address", 0),
+			&ds,
+			"immudb",
+			"immudb",
+			nil,
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			uuidProvider,
+			cache.NewHistoryFileCache(dirname),
+			func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
+			logger.NewSimpleLogger("test", os.Stdout),
+			WithPinnedRoots(pin),
+			WithProofArchive(NewFileProofArchive(archiveDir)))
+		require.NoError(t, err)
+		return da.(*defaultAuditor)
+	}
+
+	serverID := newAuditor(PinnedRoot{}).getServerID(ctx)
+	dbs, err := serviceClient.DatabaseList(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	dbName := dbs.Databases[0].Databasename
+
+	require.NoError(t, newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: root.GetIndex(), Hash: root.GetRoot()}).audit(context.Background()))
+	require.NoError(t, newAuditor(PinnedRoot{ServerID: serverID, Database: dbName, Index: root.GetIndex(), Hash: []byte(`spoofed`)}).audit(context.Background()))
+
+	f, err := os.Open(filepath.Join(archiveDir, "address_0.proofs.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+	proofs, err := ReadProofArchive(f)
+	require.NoError(t, err)
+	require.Len(t, proofs, 2)
+
+	require.Equal(t, serverID, proofs[0].ServerID)
+	require.Equal(t, "address:0", proofs[0].ServerAddress)
+	require.Equal(t, dbName, proofs[0].DB)
+	require.Equal(t, root.GetIndex(), proofs[0].PreviousRoot.Index)
+	require.Equal(t, root.GetIndex()+1, proofs[0].CurrentRoot.Index)
+	require.False(t, proofs[0].FetchedAt.IsZero())
+	require.True(t, proofs[0].Verified)
+	verified, err := proofs[0].Verify()
+	require.NoError(t, err)
+	require.True(t, verified)
+
+	// a proof that did not verify is archived as well, and does not verify offline either
+	require.False(t, proofs[1].Verified)
+	verified, err = proofs[1].Verify()
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	// tampering with the archived roots is detected
+	proofs[0].CurrentRoot.Index++
+	verified, err = proofs[0].Verify()
+	require.NoError(t, err)
+	require.False(t, verified)
+}
+
+func TestFileProofArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proof_archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "proofs.jsonl")
+	archive := NewFileProofArchive(path)
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, archive.Append(&ArchivedProof{ServerAddress: "address:0", DB: "db", AuditIndex: i}))
+	}
+	// a new archive appends to the existing file
+	require.NoError(t, NewFileProofArchive(path).Append(&ArchivedProof{ServerAddress: "address:1", DB: "db", AuditIndex: 4}))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	proofs, err := ReadProofArchive(f)
+	require.NoError(t, err)
+	require.Len(t, proofs, 4)
+	for i, proof := range proofs {
+		require.Equal(t, uint64(i+1), proof.AuditIndex)
+	}
+
+	_, err = proofs[0].Verify()
+	require.Error(t, err)
+
+	require.Error(t, NewFileProofArchive(filepath.Join(dir, "missing", "proofs.jsonl")).Append(&ArchivedProof{}))
+}
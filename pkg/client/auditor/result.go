@@ -0,0 +1,155 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTampered is matched, with errors.Is, by the error of a single run which detected a possible tampering
+var ErrTampered = errors.New("possible tampering detected")
+
+// ErrAuditFailed is matched, with errors.Is, by the error of a single run in which some audit could not be completed
+var ErrAuditFailed = errors.New("audit could not be completed")
+
+// Exit codes of a single run, see ExitCode
+const (
+	ExitVerified = 0
+	ExitFailed   = 1
+	ExitTampered = 2
+)
+
+// Outcome is the outcome of the audit of a database
+type Outcome int
+
+// Outcomes ordered by severity: the most severe one is kept when a database is audited more than once in a run
+const (
+	OutcomeSkipped Outcome = iota
+	OutcomeVerified
+	OutcomeFailed
+	OutcomeTampered
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSkipped:
+		return "skipped"
+	case OutcomeVerified:
+		return "verified"
+	case OutcomeFailed:
+		return "failed"
+	case OutcomeTampered:
+		return "tampered"
+	}
+	return fmt.Sprintf("outcome(%d)", int(o))
+}
+
+// DatabaseOutcome holds the outcome of the audit of a database in a single run.
+// DB is empty if the audit failed before a database could be selected, e.g. if the server is unreachable.
+type DatabaseOutcome struct {
+	ServerAddress string
+	DB            string
+	Outcome       Outcome
+}
+
+// RunError is returned by a single run (Run and RunContext with singleRun) if some audit did not verify the server.
+// Whether a tampering was detected can be checked with errors.Is(err, ErrTampered), and
+// whether some audit could not be completed with errors.Is(err, ErrAuditFailed).
+type RunError struct {
+	Outcomes []DatabaseOutcome
+}
+
+func (e *RunError) Error() string {
+	var failures []string
+	for _, o := range e.Outcomes {
+		if o.Outcome != OutcomeFailed && o.Outcome != OutcomeTampered {
+			continue
+		}
+		db := o.DB
+		if db == "" {
+			db = "-"
+		}
+		failures = append(failures, fmt.Sprintf("%s/%s: %s", o.ServerAddress, db, o.Outcome))
+	}
+	return "audit run did not verify all databases: " + strings.Join(failures, ", ")
+}
+
+// Is makes RunError match ErrTampered and ErrAuditFailed, according to its outcomes
+func (e *RunError) Is(target error) bool {
+	switch target {
+	case ErrTampered:
+		return e.has(OutcomeTampered)
+	case ErrAuditFailed:
+		return e.has(OutcomeFailed)
+	}
+	return false
+}
+
+func (e *RunError) has(outcome Outcome) bool {
+	for _, o := range e.Outcomes {
+		if o.Outcome == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// ExitCode maps the error returned by a single run to a process exit code, for CI pipelines:
+// ExitVerified if err is nil, ExitTampered if a tampering was detected, ExitFailed otherwise
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitVerified
+	case errors.Is(err, ErrTampered):
+		return ExitTampered
+	}
+	return ExitFailed
+}
+
+// recordOutcome records the outcome of the audit of db in the current run, keeping the most severe one
+func (a *defaultAuditor) recordOutcome(db string, outcome Outcome) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, o := range a.outcomes {
+		if o.DB == db {
+			if outcome > o.Outcome {
+				a.outcomes[i].Outcome = outcome
+			}
+			return
+		}
+	}
+	a.outcomes = append(a.outcomes, DatabaseOutcome{ServerAddress: a.serverAddress, DB: db, Outcome: outcome})
+}
+
+// runOutcomes returns the outcomes recorded in the current run
+func (a *defaultAuditor) runOutcomes() []DatabaseOutcome {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]DatabaseOutcome(nil), a.outcomes...)
+}
+
+// runError returns a RunError if any of outcomes is a failure or a tampering, nil otherwise
+func runError(outcomes []DatabaseOutcome) error {
+	for _, o := range outcomes {
+		if o.Outcome == OutcomeFailed || o.Outcome == OutcomeTampered {
+			return &RunError{Outcomes: outcomes}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRunErrorOutcomes(t *testing.T) {
+	require.NoError(t, runError(nil))
+	require.NoError(t, runError([]DatabaseOutcome{{DB: "db1", Outcome: OutcomeVerified}, {DB: "db2", Outcome: OutcomeSkipped}}))
+
+	err := runError([]DatabaseOutcome{
+		{ServerAddress: "address:0", DB: "db1", Outcome: OutcomeVerified},
+		{ServerAddress: "address:0", DB: "", Outcome: OutcomeFailed},
+	})
+	require.True(t, errors.Is(err, ErrAuditFailed))
+	require.False(t, errors.Is(err, ErrTampered))
+	require.Equal(t, ExitFailed, ExitCode(err))
+	require.Equal(t, "audit run did not verify all databases: address:0/-: failed", err.Error())
+
+	err = runError([]DatabaseOutcome{
+		{ServerAddress: "address:0", DB: "db1", Outcome: OutcomeTampered},
+		{ServerAddress: "address:0", DB: "db2", Outcome: OutcomeFailed},
+	})
+	require.True(t, errors.Is(err, ErrAuditFailed))
+	require.True(t, errors.Is(err, ErrTampered))
+	require.Equal(t, ExitTampered, ExitCode(err))
+
+	require.Equal(t, ExitVerified, ExitCode(nil))
+	require.Equal(t, ExitFailed, ExitCode(errors.New("some error")))
+	require.Equal(t, "tampered", OutcomeTampered.String())
+}
+
+func TestDefaultAuditorSingleRunLoginErr(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	serviceClient := clienttest.ImmuServiceClientMock{
+		LoginF: func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+			return nil, errors.New("connection refused")
+		},
+		LogoutF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+			return new(empty.Empty), nil
+		},
+	}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		"address:0",
+		&[]grpc.DialOption{grpc.WithInsecure()},
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		&serviceClient,
+		rootservice.NewImmudbUUIDProvider(&serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		nil,
+		logger.NewSimpleLogger("test", os.Stdout))
+	require.NoError(t, err)
+
+	err = da.RunContext(context.Background(), time.Hour, true)
+	var runErr *RunError
+	require.True(t, errors.As(err, &runErr))
+	require.Equal(t, []DatabaseOutcome{{ServerAddress: "address:0", Outcome: OutcomeFailed}}, runErr.Outcomes)
+	require.Equal(t, ExitFailed, ExitCode(err))
+
+	// the error of a single run is returned by Run as well
+	donec := make(chan struct{}, 1)
+	require.Equal(t, ExitFailed, ExitCode(da.Run(time.Hour, true, make(chan struct{}), donec)))
+	<-donec
+}
+
+func TestDefaultAuditorSingleRunTampered(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	ds := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lresp, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lresp.Token))
+
+	_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(`key`), Value: []byte(`val`)})
+	require.NoError(t, err)
+	root, err := serviceClient.CurrentRoot(ctx, &empty.Empty{})
+	require.NoError(t, err)
+
+	uuidProvider := rootservice.NewImmudbUUIDProvider(serviceClient)
+	newAuditor := func(options ...Option) Auditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			"address:0",
+			&ds,
+			"immudb",
+			"immudb",
+			nil,
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			uuidProvider,
+			cache.NewHistoryFileCache(dirname),
+			nil,
+			logger.NewSimpleLogger("test", os.Stdout),
+			options...)
+		require.NoError(t, err)
+		return da
+	}
+
+	dbs, err := serviceClient.DatabaseList(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	dbName := dbs.Databases[0].Databasename
+	serverID := newAuditor().(*defaultAuditor).getServerID(ctx)
+
+	// a verified database does not fail the run
+	require.NoError(t, newAuditor().RunContext(context.Background(), time.Hour, true))
+
+	err = newAuditor(WithPinnedRoots(PinnedRoot{ServerID: serverID, Database: dbName, Index: root.GetIndex(), Hash: []byte(`spoofed`)})).
+		RunContext(context.Background(), time.Hour, true)
+	require.True(t, errors.Is(err, ErrTampered))
+	require.False(t, errors.Is(err, ErrAuditFailed))
+	require.Equal(t, ExitTampered, ExitCode(err))
+	require.Equal(t, []DatabaseOutcome{{ServerAddress: "address:0", DB: dbName, Outcome: OutcomeTampered}}, err.(*RunError).Outcomes)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// newBlockingAuditor returns an auditor whose server never answers DatabaseList until the call is cancelled
+func newBlockingAuditor(t *testing.T, results *auditResults, listing chan<- struct{}) *defaultAuditor {
+	serviceClient := clienttest.ImmuServiceClientMock{
+		LoginF: func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+			return &schema.LoginResponse{Token: "token"}, nil
+		},
+		LogoutF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+			return new(empty.Empty), nil
+		},
+		DatabaseListF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error) {
+			listing <- struct{}{}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		HealthF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.HealthResponse, error) {
+			return &schema.HealthResponse{Status: true}, nil
+		},
+	}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		"address:0",
+		&[]grpc.DialOption{grpc.WithInsecure()},
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		&serviceClient,
+		rootservice.NewImmudbUUIDProvider(&serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		results.update,
+		logger.NewSimpleLogger("test", os.Stdout))
+	require.NoError(t, err)
+	return da.(*defaultAuditor)
+}
+
+func TestDefaultAuditorRunContextCancel(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	results := &auditResults{}
+	listing := make(chan struct{}, 1)
+	a := newBlockingAuditor(t, results, listing)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- a.RunContext(ctx, time.Hour, false) }()
+
+	<-listing
+	cancel()
+	select {
+	case err := <-errc:
+		require.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the audit in progress has not been aborted")
+	}
+	// an aborted audit is neither an error nor a failure
+	require.Equal(t, 0, results.withError)
+	require.False(t, a.lastRunFailed())
+	require.Empty(t, a.token)
+}
+
+func TestDefaultAuditorRunContextDeadline(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	a := newBlockingAuditor(t, &auditResults{}, make(chan struct{}, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(t, context.DeadlineExceeded, a.RunContext(ctx, time.Hour, true))
+}
+
+func TestDefaultAuditorRunStop(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	listing := make(chan struct{}, 1)
+	a := newBlockingAuditor(t, &auditResults{}, listing)
+
+	stopc := make(chan struct{})
+	donec := make(chan struct{}, 1)
+	errc := make(chan error, 1)
+	go func() { errc <- a.Run(time.Hour, false, stopc, donec) }()
+
+	<-listing
+	close(stopc)
+	require.NoError(t, <-errc)
+	<-donec
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DatabaseInterval returns the minimum time between two audits of the database dbName.
+// Databases with a zero interval are audited whenever their turn comes, i.e. at every run with several workers.
+type DatabaseInterval func(dbName string) time.Duration
+
+// IntervalRule sets the audit interval of the databases whose name matches Pattern
+type IntervalRule struct {
+	Pattern  *regexp.Regexp
+	Interval time.Duration
+}
+
+// ParseIntervalRule parses an interval rule in the pattern=duration format, e.g. ^logs.*=1h
+func ParseIntervalRule(s string) (IntervalRule, error) {
+	s = strings.TrimSpace(s)
+	sep := strings.LastIndex(s, "=")
+	if sep <= 0 {
+		return IntervalRule{}, fmt.Errorf("invalid interval rule %s: expected format is pattern=duration", s)
+	}
+	pattern, err := regexp.Compile(s[:sep])
+	if err != nil {
+		return IntervalRule{}, fmt.Errorf("invalid interval rule %s: %v", s, err)
+	}
+	interval, err := time.ParseDuration(s[sep+1:])
+	if err != nil || interval < 0 {
+		return IntervalRule{}, fmt.Errorf("invalid interval rule %s: duration must be a non negative Go duration", s)
+	}
+	return IntervalRule{Pattern: pattern, Interval: interval}, nil
+}
+
+// IntervalsByPattern returns a DatabaseInterval giving to every database the interval of the first rule
+// matching its name, 0 if none does
+func IntervalsByPattern(rules ...IntervalRule) DatabaseInterval {
+	return func(dbName string) time.Duration {
+		for _, rule := range rules {
+			if rule.Pattern.MatchString(dbName) {
+				return rule.Interval
+			}
+		}
+		return 0
+	}
+}
+
+// isDue tells if the audit interval of dbName elapsed since the start of its last audit.
+// Ticks are not exact, so a database is due a hundredth of its interval in advance.
+func (a *defaultAuditor) isDue(dbName string, now time.Time) bool {
+	if a.databaseInterval == nil {
+		return true
+	}
+	interval := a.databaseInterval(dbName)
+	if interval <= 0 {
+		return true
+	}
+	a.mu.Lock()
+	dbState, ok := a.state.Databases[dbName]
+	a.mu.Unlock()
+	if !ok || dbState.LastAuditAt.IsZero() {
+		return true
+	}
+	return now.Sub(dbState.LastAuditAt) >= interval-interval/100
+}
+
+// nextDatabase moves the rotation to the next database due for audit, reloading the list of databases
+// at most once. It returns false if no database is due.
+func (a *defaultAuditor) nextDatabase(ctx context.Context, index uint64, now time.Time) (string, bool, error) {
+	reloaded := false
+	for {
+		if a.databaseIndex == len(a.databases) {
+			if reloaded {
+				return "", false, nil
+			}
+			//if we have reached the end get a fresh list of dbs that belong to the user
+			if err := a.loadDatabases(ctx, index); err != nil {
+				return "", false, err
+			}
+			reloaded = true
+		}
+		dbName := a.databases[a.databaseIndex]
+		if a.isDue(dbName, now) {
+			return dbName, true, nil
+		}
+		a.databaseIndex++
+	}
+}
+
+// dueDatabases returns the databases of the list due for audit
+func (a *defaultAuditor) dueDatabases(now time.Time) []string {
+	var due []string
+	for _, dbName := range a.databases {
+		if a.isDue(dbName, now) {
+			due = append(due, dbName)
+		}
+	}
+	return due
+}
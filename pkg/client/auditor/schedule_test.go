@@ -0,0 +1,140 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestParseIntervalRule(t *testing.T) {
+	rule, err := ParseIntervalRule(" ^logs=1h ")
+	require.NoError(t, err)
+	require.Equal(t, "^logs", rule.Pattern.String())
+	require.Equal(t, time.Hour, rule.Interval)
+
+	rule, err = ParseIntervalRule("a=b=0s")
+	require.NoError(t, err)
+	require.Equal(t, "a=b", rule.Pattern.String())
+	require.Equal(t, time.Duration(0), rule.Interval)
+
+	for _, s := range []string{"", "logs", "=1h", "logs=", "logs=1x", "logs=-1s", "(=1h"} {
+		_, err = ParseIntervalRule(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestIntervalsByPattern(t *testing.T) {
+	interval := IntervalsByPattern(
+		IntervalRule{Pattern: regexp.MustCompile("^logs"), Interval: time.Hour},
+		IntervalRule{Pattern: regexp.MustCompile("s$"), Interval: time.Minute},
+	)
+	require.Equal(t, time.Hour, interval("logs"))
+	require.Equal(t, time.Minute, interval("payments"))
+	require.Equal(t, time.Duration(0), interval("defaultdb"))
+}
+
+func TestDefaultAuditorDatabaseIntervals(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	var selected []string
+	serviceClient := clienttest.ImmuServiceClientMock{
+		LoginF: func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+			return &schema.LoginResponse{Token: ""}, nil
+		},
+		LogoutF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+			return new(empty.Empty), nil
+		},
+		DatabaseListF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error) {
+			return &schema.DatabaseListResponse{
+				Databases: []*schema.Database{{Databasename: "hot"}, {Databasename: "static"}},
+			}, nil
+		},
+		UseDatabaseF: func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*schema.UseDatabaseReply, error) {
+			selected = append(selected, in.Databasename)
+			return &schema.UseDatabaseReply{Token: ""}, nil
+		},
+		CurrentRootF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error) {
+			return &schema.Root{Payload: &schema.RootIndex{}}, nil
+		},
+		HealthF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.HealthResponse, error) {
+			return &schema.HealthResponse{Status: true}, nil
+		},
+	}
+
+	newAuditor := func(options ...Option) *defaultAuditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			"address:0",
+			&[]grpc.DialOption{grpc.WithInsecure()},
+			"immudb",
+			"immudb",
+			nil,
+			"ignore",
+			AuditNotificationConfig{},
+			&serviceClient,
+			rootservice.NewImmudbUUIDProvider(&serviceClient),
+			cache.NewHistoryFileCache(dirname),
+			nil,
+			logger.NewSimpleLogger("test", os.Stdout),
+			append(options, WithDatabaseIntervals(IntervalsByPattern(
+				IntervalRule{Pattern: regexp.MustCompile("^static$"), Interval: time.Hour})))...)
+		require.NoError(t, err)
+		return da.(*defaultAuditor)
+	}
+
+	// one database per run: the static database is skipped until its interval elapses
+	a := newAuditor()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, a.audit(context.Background()))
+	}
+	require.Equal(t, []string{"hot", "static", "hot", "hot"}, selected)
+
+	a.state.Databases["static"].LastAuditAt = time.Now().Add(-time.Hour)
+	require.NoError(t, a.audit(context.Background()))
+	require.Equal(t, "static", selected[len(selected)-1])
+
+	// no run is spent on a database which is not due
+	selected = nil
+	a = newAuditor()
+	a.state.Databases["hot"] = &DatabaseState{LastAuditAt: time.Now()}
+	a.state.Databases["static"] = &DatabaseState{LastAuditAt: time.Now()}
+	a.databaseInterval = IntervalsByPattern(IntervalRule{Pattern: regexp.MustCompile(".*"), Interval: time.Hour})
+	require.NoError(t, a.audit(context.Background()))
+	require.Empty(t, selected)
+
+	// with several workers only the due databases are audited at every run
+	selected = nil
+	a = newAuditor(WithWorkers(2))
+	require.NoError(t, a.audit(context.Background()))
+	require.ElementsMatch(t, []string{"hot", "static"}, selected)
+	selected = nil
+	require.NoError(t, a.audit(context.Background()))
+	require.Equal(t, []string{"hot"}, selected)
+}
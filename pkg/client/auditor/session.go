@@ -0,0 +1,119 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenRefreshMargin is how long before its expiration the session token is renewed,
+// so that it does not expire in the middle of an audit
+const tokenRefreshMargin = 5 * time.Minute
+
+// session returns a child of ctx carrying the session token of the auditor. The token is reused across audits:
+// the auditor logs in at the first audit, when the token is about to expire and after the server rejected it.
+func (a *defaultAuditor) session(ctx context.Context) (context.Context, error) {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+
+	if a.token == "" ||
+		(!a.tokenExpiration.IsZero() && time.Now().Add(tokenRefreshMargin).After(a.tokenExpiration)) {
+		loginCtx, span := tracing.Start(a.tracer, ctx, "login", tracing.String("user", string(a.username)))
+		resp, err := a.serviceClient.Login(loginCtx, &schema.LoginRequest{
+			User:     a.username,
+			Password: a.password,
+		})
+		tracing.End(span, err)
+		if err != nil {
+			return nil, err
+		}
+		a.token = resp.Token
+		// an unknown expiration leaves the token in use until the server rejects it
+		a.tokenExpiration, _ = auth.TokenExpiration(resp.Token)
+	}
+
+	return metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", a.token)), nil
+}
+
+// withSession calls f with the session context ctx. If the server rejects the token, e.g. because it has been
+// restarted in the meantime, the auditor logs in again and f is retried once with the new session context,
+// which is returned.
+func (a *defaultAuditor) withSession(ctx context.Context, f func(context.Context) error) (context.Context, error) {
+	err := f(ctx)
+	if !isTokenRejected(err) {
+		return ctx, err
+	}
+
+	a.logger.Infof("session token rejected by the server @ %s, logging in again: %v", a.serverAddress, err)
+	a.dropToken(sessionToken(ctx))
+	if ctx, err = a.session(ctx); err != nil {
+		return nil, err
+	}
+	return ctx, f(ctx)
+}
+
+// dropToken forgets token, unless another audit worker has already replaced it
+func (a *defaultAuditor) dropToken(token string) {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	if a.token == token {
+		a.token = ""
+		a.tokenExpiration = time.Time{}
+	}
+}
+
+// logout closes the session, if any
+func (a *defaultAuditor) logout() {
+	a.sessionMu.Lock()
+	token := a.token
+	a.sessionMu.Unlock()
+	if token == "" {
+		return
+	}
+
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", token))
+	if _, err := a.serviceClient.Logout(ctx, &empty.Empty{}); err != nil {
+		a.logger.Warningf("error logging out with user %s: %v", a.username, err)
+	}
+	a.dropToken(token)
+}
+
+func sessionToken(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if tokens := md.Get("authorization"); len(tokens) > 0 {
+		return tokens[0]
+	}
+	return ""
+}
+
+// isTokenRejected tells if err is caused by an invalid or expired token
+func isTokenRejected(err error) bool {
+	if err == nil {
+		return false
+	}
+	return status.Code(err) == codes.Unauthenticated || strings.Contains(err.Error(), "token has expired")
+}
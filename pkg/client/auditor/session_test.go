@@ -0,0 +1,115 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDefaultAuditorSessionReuse(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	logins, logouts := 0, 0
+	validToken := ""
+	serviceClient := clienttest.ImmuServiceClientMock{
+		LoginF: func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+			logins++
+			validToken = fmt.Sprintf("token-%d", logins)
+			return &schema.LoginResponse{Token: validToken}, nil
+		},
+		LogoutF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+			logouts++
+			return new(empty.Empty), nil
+		},
+		DatabaseListF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error) {
+			return &schema.DatabaseListResponse{Databases: []*schema.Database{{Databasename: "db1"}}}, nil
+		},
+		UseDatabaseF: func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*schema.UseDatabaseReply, error) {
+			if sessionToken(ctx) != validToken {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+			return &schema.UseDatabaseReply{Token: "db-token"}, nil
+		},
+		CurrentRootF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error) {
+			return &schema.Root{Payload: &schema.RootIndex{}}, nil
+		},
+		HealthF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.HealthResponse, error) {
+			return &schema.HealthResponse{Status: true}, nil
+		},
+	}
+
+	results := &auditResults{}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		"address:0",
+		&[]grpc.DialOption{grpc.WithInsecure()},
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		&serviceClient,
+		rootservice.NewImmudbUUIDProvider(&serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		results.update,
+		logger.NewSimpleLogger("test", os.Stdout))
+	require.NoError(t, err)
+	a := da.(*defaultAuditor)
+
+	// the token is reused by the following audits
+	for i := 0; i < 3; i++ {
+		require.NoError(t, a.audit(context.Background()))
+	}
+	require.Equal(t, 1, logins)
+	require.Equal(t, 0, logouts)
+	require.Equal(t, 0, results.withError)
+
+	// the server forgot the token, e.g. after a restart
+	validToken = ""
+	require.NoError(t, a.audit(context.Background()))
+	require.Equal(t, 2, logins)
+	require.Equal(t, "token-2", a.token)
+	require.Equal(t, 0, results.withError)
+
+	// a token about to expire is renewed before being used
+	a.tokenExpiration = time.Now().Add(tokenRefreshMargin / 2)
+	require.NoError(t, a.audit(context.Background()))
+	require.Equal(t, 3, logins)
+	require.Equal(t, 0, results.withError)
+
+	// the session is closed when the auditor stops
+	auditorDone := make(chan struct{}, 1)
+	require.NoError(t, da.Run(time.Duration(0), true, nil, auditorDone))
+	require.Equal(t, 3, logins)
+	require.Equal(t, 1, logouts)
+	require.Empty(t, a.token)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// State is the progress of an auditor, persisted so that a restarted auditor resumes where it left off
+type State struct {
+	// Index is the number of audits run so far
+	Index uint64 `json:"index"`
+	// LastDatabase is the last audited database, the rotation resumes from the following one
+	LastDatabase string                    `json:"last_database"`
+	Databases    map[string]*DatabaseState `json:"databases"`
+	// AccessControlHash is the hex encoded hash of the users and permissions of the server seen at the last audit
+	AccessControlHash string `json:"access_control_hash,omitempty"`
+	// Digest is the digest of the audits of the current period, not yet published
+	Digest *AuditDigest `json:"digest,omitempty"`
+}
+
+// DatabaseState holds the audit history of a database
+type DatabaseState struct {
+	LastAuditAt time.Time `json:"last_audit_at"`
+	Audits      uint64    `json:"audits"`
+	Tampered    uint64    `json:"tampered"`
+}
+
+// StateStore persists the state of auditors, identified by the address of the audited server
+type StateStore interface {
+	Load(serverAddress string) (*State, error)
+	Save(serverAddress string, state *State) error
+}
+
+type fileStateStore struct {
+	dir           string
+	slugifyRegExp *regexp.Regexp
+}
+
+// NewFileStateStore returns a state store keeping the state of every auditor in a json file inside dir,
+// which is usually the directory of the history cache
+func NewFileStateStore(dir string) StateStore {
+	return &fileStateStore{
+		dir:           dir,
+		slugifyRegExp: regexp.MustCompile(`[^a-zA-Z0-9\-_.]+`),
+	}
+}
+
+func (s *fileStateStore) path(serverAddress string) string {
+	return filepath.Join(s.dir, s.slugifyRegExp.ReplaceAllString(serverAddress, "_")+".state.json")
+}
+
+// Load returns the saved state, or an empty one if none was saved yet
+func (s *fileStateStore) Load(serverAddress string) (*State, error) {
+	state := &State{Databases: map[string]*DatabaseState{}}
+	raw, err := ioutil.ReadFile(s.path(serverAddress))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(raw, state); err != nil {
+		return nil, fmt.Errorf("error reading auditor state %s: %v", s.path(serverAddress), err)
+	}
+	if state.Databases == nil {
+		state.Databases = map[string]*DatabaseState{}
+	}
+	return state, nil
+}
+
+// Save writes the state to a temporary file first, so that a crash never leaves a truncated state behind
+func (s *fileStateStore) Save(serverAddress string, state *State) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error ensuring auditor state dir %s exists: %v", s.dir, err)
+	}
+	path := s.path(serverAddress)
+	if err = ioutil.WriteFile(path+".tmp", raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (s *State) database(db string) *DatabaseState {
+	dbState, ok := s.Databases[db]
+	if !ok {
+		dbState = &DatabaseState{}
+		s.Databases[db] = dbState
+	}
+	return dbState
+}
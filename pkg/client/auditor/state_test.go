@@ -0,0 +1,132 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestFileStateStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auditor_state")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store := NewFileStateStore(filepath.Join(dir, "history"))
+	state, err := store.Load("localhost:3322")
+	require.NoError(t, err)
+	require.Equal(t, &State{Databases: map[string]*DatabaseState{}}, state)
+
+	at := time.Unix(1600000000, 0).UTC()
+	state.Index = 3
+	state.LastDatabase = "db1"
+	state.database("db1").LastAuditAt = at
+	state.database("db1").Audits = 2
+	state.database("db1").Tampered = 1
+	require.NoError(t, store.Save("localhost:3322", state))
+
+	loaded, err := store.Load("localhost:3322")
+	require.NoError(t, err)
+	require.Equal(t, state, loaded)
+
+	other, err := store.Load("localhost:3323")
+	require.NoError(t, err)
+	require.Empty(t, other.Databases)
+
+	require.NoError(t, ioutil.WriteFile(store.(*fileStateStore).path("corrupted"), []byte("{"), 0644))
+	_, err = store.Load("corrupted")
+	require.Error(t, err)
+}
+
+func TestDefaultAuditorResumesFromState(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword))
+	bs.Start()
+
+	ds := []grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	defer clientConn.Close()
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lr, err := serviceClient.Login(context.TODO(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lr.Token))
+	for _, db := range []string{"db1", "db2"} {
+		_, err = serviceClient.CreateDatabase(ctx, &schema.Database{Databasename: db})
+		require.NoError(t, err)
+	}
+
+	var audited []string
+	newAuditor := func() *defaultAuditor {
+		da, err := DefaultAuditor(
+			time.Duration(0),
+			"address:0",
+			&ds,
+			"immudb",
+			"immudb",
+			[]string{"db"},
+			"ignore",
+			AuditNotificationConfig{},
+			serviceClient,
+			rootservice.NewImmudbUUIDProvider(serviceClient),
+			cache.NewHistoryFileCache(dirname),
+			func(string, string, bool, bool, bool, *schema.Root, *schema.Root) {},
+			logger.NewSimpleLogger("test", os.Stdout),
+			WithStateStore(NewFileStateStore(dirname)))
+		require.NoError(t, err)
+		return da.(*defaultAuditor)
+	}
+
+	da := newAuditor()
+	require.NoError(t, da.audit(context.Background()))
+	audited = append(audited, da.state.LastDatabase)
+
+	// a restarted auditor keeps counting and audits the next database
+	da = newAuditor()
+	require.Equal(t, uint64(1), da.index)
+	require.NoError(t, da.audit(context.Background()))
+	audited = append(audited, da.state.LastDatabase)
+	require.ElementsMatch(t, []string{"db1", "db2"}, audited)
+
+	da = newAuditor()
+	require.Equal(t, uint64(2), da.index)
+	require.NoError(t, da.audit(context.Background()))
+	require.Equal(t, audited[0], da.state.LastDatabase)
+
+	state := da.state
+	require.Equal(t, uint64(3), state.Index)
+	require.Equal(t, uint64(2), state.Databases[audited[0]].Audits)
+	require.Equal(t, uint64(1), state.Databases[audited[1]].Audits)
+	require.Zero(t, state.Databases[audited[0]].Tampered)
+	require.False(t, state.Databases[audited[0]].LastAuditAt.IsZero())
+}
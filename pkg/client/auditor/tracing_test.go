@@ -0,0 +1,145 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type tracedSpan struct {
+	name       string
+	parent     string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+type parentSpanKey struct{}
+
+// spanRecorder records the spans along with the name of their parent span
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*tracedSpan
+}
+
+func (r *spanRecorder) Start(ctx context.Context, name string, attributes ...tracing.Attribute) (context.Context, tracing.Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	span := &tracedSpan{name: name, attributes: map[string]interface{}{}}
+	if parent, ok := ctx.Value(parentSpanKey{}).(*tracedSpan); ok {
+		span.parent = parent.name
+	}
+	span.SetAttributes(attributes...)
+	r.spans = append(r.spans, span)
+	return context.WithValue(ctx, parentSpanKey{}, span), span
+}
+
+func (r *spanRecorder) names() (names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, span := range r.spans {
+		names = append(names, span.parent+"/"+span.name)
+	}
+	return names
+}
+
+func (s *tracedSpan) SetAttributes(attributes ...tracing.Attribute) {
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *tracedSpan) RecordError(err error) { s.err = err }
+func (s *tracedSpan) End()                  { s.ended = true }
+
+func TestDefaultAuditorTracing(t *testing.T) {
+	defer os.RemoveAll(dirname)
+
+	serviceClient := clienttest.NewImmuServiceClientMock()
+	serviceClient.LoginF = func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+		return &schema.LoginResponse{Token: "token"}, nil
+	}
+	serviceClient.DatabaseListF = func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error) {
+		return &schema.DatabaseListResponse{Databases: []*schema.Database{{Databasename: "traceddb"}}}, nil
+	}
+	serviceClient.CurrentRootF = func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error) {
+		return &schema.Root{Payload: &schema.RootIndex{Index: 1, Root: []byte{1}}}, nil
+	}
+	recorder := &spanRecorder{}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		"address:0",
+		&[]grpc.DialOption{grpc.WithInsecure()},
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		serviceClient,
+		rootservice.NewImmudbUUIDProvider(serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		nil,
+		logger.NewSimpleLogger("test", os.Stdout),
+		WithTracer(recorder))
+	require.NoError(t, err)
+	a := da.(*defaultAuditor)
+
+	// the first audit logs in and trusts the current root, the second one checks the consistency with it
+	require.NoError(t, a.audit(context.Background()))
+	require.Equal(t, []string{
+		"/audit",
+		"audit/login",
+		"audit/audit-database",
+		"audit-database/use-database",
+		"audit-database/current-root",
+	}, recorder.names())
+	recorder.spans = nil
+
+	require.NoError(t, a.audit(context.Background()))
+	require.Equal(t, []string{
+		"/audit",
+		"audit/audit-database",
+		"audit-database/use-database",
+		"audit-database/current-root",
+		"audit-database/consistency",
+		"audit-database/publish-notification",
+	}, recorder.names())
+	for _, span := range recorder.spans {
+		require.True(t, span.ended, span.name)
+		require.NoError(t, span.err, span.name)
+	}
+	require.Equal(t, int64(2), recorder.spans[0].attributes["audit.index"])
+	require.Equal(t, "traceddb", recorder.spans[1].attributes["db"])
+	require.Equal(t, true, recorder.spans[1].attributes["audit.checked"])
+	// the empty consistency proof returned by the mock does not verify
+	require.Equal(t, false, recorder.spans[1].attributes["audit.verified"])
+	require.Equal(t, true, recorder.spans[5].attributes["audit.tampered"])
+}
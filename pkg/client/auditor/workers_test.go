@@ -0,0 +1,167 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type auditResults struct {
+	checked, withError, verified int
+	sync.Mutex
+}
+
+func (r *auditResults) update(_ string, _ string, checked bool, withError bool, verified bool, _ *schema.Root, _ *schema.Root) {
+	r.Lock()
+	defer r.Unlock()
+	if checked {
+		r.checked++
+	}
+	if withError {
+		r.withError++
+	}
+	if verified {
+		r.verified++
+	}
+}
+
+func TestDefaultAuditorWorkers(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	bs := servertest.NewBufconnServer(server.Options{}.WithAuth(true).WithInMemoryStore(true).WithAdminPassword(auth.SysAdminPassword))
+	bs.Start()
+
+	ds := []grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}
+	clientConn, err := grpc.Dial("add", ds...)
+	require.NoError(t, err)
+	defer clientConn.Close()
+	serviceClient := schema.NewImmuServiceClient(clientConn)
+
+	lr, err := serviceClient.Login(context.TODO(), &schema.LoginRequest{User: []byte("immudb"), Password: []byte("immudb")})
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lr.Token))
+	for _, db := range []string{"db1", "db2", "db3"} {
+		_, err = serviceClient.CreateDatabase(ctx, &schema.Database{Databasename: db})
+		require.NoError(t, err)
+		ur, err := serviceClient.UseDatabase(ctx, &schema.Database{Databasename: db})
+		require.NoError(t, err)
+		dbCtx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", ur.Token))
+		_, err = serviceClient.Set(dbCtx, &schema.KeyValue{Key: []byte("key"), Value: []byte(db)})
+		require.NoError(t, err)
+	}
+
+	results := &auditResults{}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		"address:0",
+		&ds,
+		"immudb",
+		"immudb",
+		[]string{"db"},
+		"ignore",
+		AuditNotificationConfig{},
+		serviceClient,
+		rootservice.NewImmudbUUIDProvider(serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		results.update,
+		logger.NewSimpleLogger("test", os.Stdout),
+		WithWorkers(2))
+	require.NoError(t, err)
+
+	// every database is audited at every run
+	require.NoError(t, da.(*defaultAuditor).audit(context.Background()))
+	require.Equal(t, 0, results.withError)
+	require.Equal(t, 0, results.checked)
+	require.Equal(t, 3, results.verified)
+
+	require.NoError(t, da.(*defaultAuditor).audit(context.Background()))
+	require.Equal(t, 0, results.withError)
+	require.Equal(t, 3, results.checked)
+	require.Equal(t, 6, results.verified)
+	require.Equal(t, uint64(2), da.(*defaultAuditor).state.Index)
+	for _, db := range []string{"db1", "db2", "db3"} {
+		require.Equal(t, uint64(2), da.(*defaultAuditor).state.Databases[db].Audits)
+	}
+}
+
+func TestDefaultAuditorWorkersErrorIsolation(t *testing.T) {
+	defer os.RemoveAll(dirname)
+	serviceClient := clienttest.ImmuServiceClientMock{
+		LoginF: func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error) {
+			return &schema.LoginResponse{Token: ""}, nil
+		},
+		LogoutF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+			return new(empty.Empty), nil
+		},
+		DatabaseListF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error) {
+			return &schema.DatabaseListResponse{
+				Databases: []*schema.Database{{Databasename: "db1"}, {Databasename: "db2"}, {Databasename: "db3"}},
+			}, nil
+		},
+		UseDatabaseF: func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*schema.UseDatabaseReply, error) {
+			if in.Databasename == "db2" {
+				return nil, errors.New("some use database error")
+			}
+			return &schema.UseDatabaseReply{Token: ""}, nil
+		},
+		CurrentRootF: func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error) {
+			return nil, errors.New("some current root error")
+		},
+	}
+
+	results := &auditResults{}
+	da, err := DefaultAuditor(
+		time.Duration(0),
+		"address:0",
+		&[]grpc.DialOption{grpc.WithInsecure()},
+		"immudb",
+		"immudb",
+		nil,
+		"ignore",
+		AuditNotificationConfig{},
+		&serviceClient,
+		rootservice.NewImmudbUUIDProvider(&serviceClient),
+		cache.NewHistoryFileCache(dirname),
+		results.update,
+		logger.NewSimpleLogger("test", os.Stdout),
+		WithWorkers(3))
+	require.NoError(t, err)
+
+	require.NoError(t, da.(*defaultAuditor).audit(context.Background()))
+	require.Equal(t, 3, results.withError)
+	// the databases which could be selected have been audited despite the failure of db2
+	require.Equal(t, uint64(1), da.(*defaultAuditor).state.Databases["db1"].Audits)
+	require.Nil(t, da.(*defaultAuditor).state.Databases["db2"])
+	require.Equal(t, uint64(1), da.(*defaultAuditor).state.Databases["db3"].Audits)
+}
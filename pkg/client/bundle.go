@@ -0,0 +1,143 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// DownloadVerificationBundle collects a sample of sampleSize entries (or all of them when sampleSize is 0) along with
+// the proofs linking each of them to the roots seen while downloading, and writes the resulting bundle to w.
+// The bundle starts from the locally trusted root, so it can be verified on an isolated machine by VerifyBundle.
+func (c *immuClient) DownloadVerificationBundle(ctx context.Context, w io.Writer, sampleSize uint64) (*schema.VerificationBundle, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	base, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+	current, err := c.ServiceClient.CurrentRoot(ctx, &empty.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	if len(current.GetRoot()) == 0 {
+		return nil, schema.ErrEmptyVerificationBundle
+	}
+
+	bundle := &schema.VerificationBundle{}
+	if len(base.GetRoot()) > 0 {
+		bundle.Base = base
+	}
+
+	prev := bundle.Base
+	for _, index := range sampleIndexes(current.GetIndex()+1, sampleSize) {
+		var rootIndex *schema.Index
+		if prev != nil {
+			rootIndex = &schema.Index{Index: prev.GetIndex()}
+		}
+		safeItem, err := c.ServiceClient.BySafeIndex(ctx, &schema.SafeIndexOptions{
+			Index:     index,
+			RootIndex: rootIndex,
+		})
+		if err != nil {
+			return nil, err
+		}
+		bundle.Entries = append(bundle.Entries, safeItem)
+		prev = safeItem.Proof.NewRoot()
+	}
+	bundle.Root = prev
+
+	err = bundle.Verify()
+	c.metrics.observeVerification(err == nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.Rootservice.SetRoot(bundle.Root, c.Options.CurrentDatabase); err != nil {
+		return nil, err
+	}
+
+	raw, err := proto.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(raw); err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("DownloadVerificationBundle finished in %s", time.Since(start))
+
+	return bundle, nil
+}
+
+// VerifyBundle reads a bundle produced by DownloadVerificationBundle and verifies it without contacting the server.
+// It's up to the caller to compare the returned bundle base root with a locally trusted one.
+func VerifyBundle(r io.Reader) (*schema.VerificationBundle, error) {
+	raw, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	bundle := &schema.VerificationBundle{}
+	if err = proto.Unmarshal(raw, bundle); err != nil {
+		return nil, err
+	}
+	if err = bundle.Verify(); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// sampleIndexes returns sampleSize distinct random indexes lower than width in ascending order,
+// or all of them when sampleSize is 0 or not lower than width.
+func sampleIndexes(width uint64, sampleSize uint64) []uint64 {
+	if sampleSize == 0 || sampleSize >= width {
+		indexes := make([]uint64, width)
+		for i := range indexes {
+			indexes[i] = uint64(i)
+		}
+		return indexes
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	picked := make(map[uint64]struct{}, sampleSize)
+	indexes := make([]uint64, 0, sampleSize)
+	for uint64(len(indexes)) < sampleSize {
+		i := uint64(rnd.Int63n(int64(width)))
+		if _, ok := picked[i]; ok {
+			continue
+		}
+		picked[i] = struct{}{}
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// CacheKeyEnv is the environment variable LoadCacheKey reads the key from when no key file is given
+const CacheKeyEnv = "IMMUDB_CACHE_KEY"
+
+// CacheKeySize is the size of the keys of the encrypted caches (AES-256)
+const CacheKeySize = 32
+
+// ErrInvalidCacheKey is returned for keys which are not CacheKeySize bytes long
+var ErrInvalidCacheKey = fmt.Errorf("cache key must be %d bytes long", CacheKeySize)
+
+// ErrCorruptedCache is returned when a root of an encrypted cache cannot be authenticated,
+// i.e. it has been tampered with, written by a plaintext cache or with another key
+var ErrCorruptedCache = errors.New("cached root cannot be authenticated: the cache has been tampered with or the key is wrong")
+
+// rootCodec encodes the marshaled roots stored in the cache files
+type rootCodec interface {
+	encode(raw []byte, serverID string, databasename string) ([]byte, error)
+	decode(data []byte, serverID string, databasename string) ([]byte, error)
+}
+
+type plainCodec struct{}
+
+func (plainCodec) encode(raw []byte, serverID string, databasename string) ([]byte, error) {
+	return raw, nil
+}
+
+func (plainCodec) decode(data []byte, serverID string, databasename string) ([]byte, error) {
+	return data, nil
+}
+
+// aeadCodec encrypts and authenticates the roots with AES-GCM. The server ID and the database name are
+// authenticated as well, so that a root cannot be moved to another server or database of the cache.
+type aeadCodec struct {
+	aead cipher.AEAD
+}
+
+func newAEADCodec(key []byte) (*aeadCodec, error) {
+	if len(key) != CacheKeySize {
+		return nil, ErrInvalidCacheKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &aeadCodec{aead: aead}, nil
+}
+
+func associatedData(serverID string, databasename string) []byte {
+	return []byte(serverID + "\x00" + databasename)
+}
+
+func (c *aeadCodec) encode(raw []byte, serverID string, databasename string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(raw)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, raw, associatedData(serverID, databasename)), nil
+}
+
+func (c *aeadCodec) decode(data []byte, serverID string, databasename string) ([]byte, error) {
+	if len(data) < c.aead.NonceSize() {
+		return nil, ErrCorruptedCache
+	}
+	nonce := data[:c.aead.NonceSize()]
+	raw, err := c.aead.Open(nil, nonce, data[c.aead.NonceSize():], associatedData(serverID, databasename))
+	if err != nil {
+		return nil, ErrCorruptedCache
+	}
+	return raw, nil
+}
+
+// LoadCacheKey reads the base64 encoded key of the encrypted caches from keyFile or, if empty,
+// from the CacheKeyEnv environment variable. A nil key is returned if neither is set.
+// A key can be generated with e.g. "head -c 32 /dev/urandom | base64".
+func LoadCacheKey(keyFile string) ([]byte, error) {
+	encoded := os.Getenv(CacheKeyEnv)
+	if keyFile != "" {
+		content, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cache key from %s: %v", keyFile, err)
+		}
+		encoded = string(content)
+	}
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("cache key is not base64 encoded: %v", err)
+	}
+	if len(key) != CacheKeySize {
+		return nil, ErrInvalidCacheKey
+	}
+	return key, nil
+}
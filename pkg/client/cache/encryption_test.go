@@ -0,0 +1,135 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+var dirnameenc = "./test-encrypted"
+
+var testCacheKey = bytes.Repeat([]byte{0x42}, CacheKeySize)
+
+func TestEncryptedHistoryFileCache(t *testing.T) {
+	defer os.RemoveAll(dirnameenc)
+
+	_, err := NewEncryptedHistoryFileCache(dirnameenc, []byte("short"))
+	assert.Equal(t, ErrInvalidCacheKey, err)
+
+	fc, err := NewEncryptedHistoryFileCache(dirnameenc, testCacheKey)
+	assert.NoError(t, err)
+	assert.NoError(t, fc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 7, Root: []byte("hash")}}, "uuid", "dbName"))
+	assert.NoError(t, fc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 9, Root: []byte("other")}}, "uuid", "otherdb"))
+
+	root, err := fc.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), root.GetIndex())
+	assert.Equal(t, []byte("hash"), root.GetRoot())
+
+	// the roots are not stored in plaintext
+	rootFile := filepath.Join(dirnameenc, "uuid", ".root")
+	content, err := ioutil.ReadFile(rootFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), base64.StdEncoding.EncodeToString([]byte("hash")))
+
+	// a root cannot be read with another key, nor moved to another database
+	other, err := NewEncryptedHistoryFileCache(dirnameenc, bytes.Repeat([]byte{0x24}, CacheKeySize))
+	assert.NoError(t, err)
+	_, err = other.Get("uuid", "dbName")
+	assert.True(t, errors.Is(err, ErrCorruptedCache), err)
+
+	lines := bytes.Split(content, []byte("\n"))
+	var swapped [][]byte
+	for _, line := range lines {
+		if bytes.HasPrefix(line, []byte("otherdb:")) {
+			swapped = append(swapped, append([]byte("dbName:"), line[len("otherdb:"):]...))
+		}
+	}
+	assert.NoError(t, ioutil.WriteFile(rootFile, bytes.Join(swapped, []byte("\n")), 0644))
+	_, err = fc.Get("uuid", "dbName")
+	assert.True(t, errors.Is(err, ErrCorruptedCache), err)
+
+	// plaintext roots are rejected
+	assert.NoError(t, NewHistoryFileCache(dirnameenc).Set(&schema.Root{Payload: &schema.RootIndex{Index: 1}}, "uuid", "dbName"))
+	_, err = fc.Walk("uuid", "dbName", func(root *schema.Root) interface{} { return nil })
+	assert.True(t, errors.Is(err, ErrCorruptedCache), err)
+}
+
+func TestEncryptedFileCache(t *testing.T) {
+	os.Mkdir(dirnameenc, os.ModePerm)
+	defer os.RemoveAll(dirnameenc)
+
+	_, err := NewEncryptedFileCache(dirnameenc, nil)
+	assert.Equal(t, ErrInvalidCacheKey, err)
+
+	fc, err := NewEncryptedFileCache(dirnameenc, testCacheKey)
+	assert.NoError(t, err)
+	assert.NoError(t, fc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 3}}, "uuid", "dbName"))
+	root, err := fc.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), root.GetIndex())
+
+	// a root cannot be moved to another server
+	content, err := ioutil.ReadFile(filepath.Join(dirnameenc, ROOT_FN+"uuid"))
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dirnameenc, ROOT_FN+"uuid2"), content, 0644))
+	_, err = fc.Get("uuid2", "dbName")
+	assert.Equal(t, ErrCorruptedCache, err)
+}
+
+func TestLoadCacheKey(t *testing.T) {
+	os.Mkdir(dirnameenc, os.ModePerm)
+	defer os.RemoveAll(dirnameenc)
+	defer os.Unsetenv(CacheKeyEnv)
+
+	os.Unsetenv(CacheKeyEnv)
+	key, err := LoadCacheKey("")
+	assert.NoError(t, err)
+	assert.Nil(t, key)
+
+	encoded := base64.StdEncoding.EncodeToString(testCacheKey)
+	os.Setenv(CacheKeyEnv, encoded)
+	key, err = LoadCacheKey("")
+	assert.NoError(t, err)
+	assert.Equal(t, testCacheKey, key)
+
+	// the key file takes precedence over the environment
+	keyFile := filepath.Join(dirnameenc, "key")
+	otherKey := bytes.Repeat([]byte{0x24}, CacheKeySize)
+	assert.NoError(t, ioutil.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(otherKey)+"\n"), 0600))
+	key, err = LoadCacheKey(keyFile)
+	assert.NoError(t, err)
+	assert.Equal(t, otherKey, key)
+
+	_, err = LoadCacheKey(filepath.Join(dirnameenc, "missing"))
+	assert.Error(t, err)
+	os.Setenv(CacheKeyEnv, "not base64!")
+	_, err = LoadCacheKey("")
+	assert.Error(t, err)
+	os.Setenv(CacheKeyEnv, base64.StdEncoding.EncodeToString([]byte("short")))
+	_, err = LoadCacheKey("")
+	assert.Equal(t, ErrInvalidCacheKey, err)
+}
@@ -31,12 +31,23 @@ import (
 const ROOT_FN = ".root-"
 
 type fileCache struct {
-	Dir string
+	Dir   string
+	codec rootCodec
 }
 
 // NewFileCache returns a new file cache
 func NewFileCache(dir string) Cache {
-	return &fileCache{Dir: dir}
+	return &fileCache{Dir: dir, codec: plainCodec{}}
+}
+
+// NewEncryptedFileCache returns a file cache encrypting and authenticating the roots with key,
+// which must be CacheKeySize bytes long, see LoadCacheKey
+func NewEncryptedFileCache(dir string, key []byte) (Cache, error) {
+	codec, err := newAEADCodec(key)
+	if err != nil {
+		return nil, err
+	}
+	return &fileCache{Dir: dir, codec: codec}, nil
 }
 
 func (w *fileCache) Get(serverUUID string, databasename string) (*schema.Root, error) {
@@ -58,6 +69,9 @@ func (w *fileCache) Get(serverUUID string, databasename string) (*schema.Root, e
 			if err != nil {
 				return nil, fmt.Errorf("could not find previous root")
 			}
+			if oldRoot, err = w.codec.decode(oldRoot, serverUUID, databasename); err != nil {
+				return nil, err
+			}
 			root := schema.NewRoot()
 			if err = proto.Unmarshal(oldRoot, root); err != nil {
 				return nil, err
@@ -73,6 +87,9 @@ func (w *fileCache) Set(root *schema.Root, serverUUID string, databasename strin
 	if err != nil {
 		return err
 	}
+	if raw, err = w.codec.encode(raw, serverUUID, databasename); err != nil {
+		return err
+	}
 	fn := filepath.Join(w.Dir, string(getRootFileName([]byte(ROOT_FN), []byte(serverUUID))))
 
 	input, _ := ioutil.ReadFile(fn)
@@ -29,12 +29,23 @@ import (
 )
 
 type historyFileCache struct {
-	dir string
+	dir   string
+	codec rootCodec
 }
 
 // NewHistoryFileCache returns a new history file cache
 func NewHistoryFileCache(dir string) HistoryCache {
-	return &historyFileCache{dir: dir}
+	return &historyFileCache{dir: dir, codec: plainCodec{}}
+}
+
+// NewEncryptedHistoryFileCache returns a history file cache encrypting and authenticating the roots with key,
+// which must be CacheKeySize bytes long (see LoadCacheKey), so that they cannot be rewritten to hide a tampering
+func NewEncryptedHistoryFileCache(dir string, key []byte) (HistoryCache, error) {
+	codec, err := newAEADCodec(key)
+	if err != nil {
+		return nil, err
+	}
+	return &historyFileCache{dir: dir, codec: codec}, nil
 }
 
 func (history *historyFileCache) Get(serverID string, databasename string) (*schema.Root, error) {
@@ -50,7 +61,7 @@ func (history *historyFileCache) Get(serverID string, databasename string) (*sch
 
 	prevRootFileName := rootsFileInfos[len(rootsFileInfos)-1].Name()
 	prevRootFilePath := filepath.Join(rootsDir, prevRootFileName)
-	return history.unmarshalRoot(prevRootFilePath, databasename)
+	return history.unmarshalRoot(prevRootFilePath, serverID, databasename)
 }
 
 func (history *historyFileCache) Walk(
@@ -71,7 +82,7 @@ func (history *historyFileCache) Walk(
 
 	for _, rootFileInfo := range rootsFileInfos {
 		rootFilePath := filepath.Join(rootsDir, rootFileInfo.Name())
-		root, err := history.unmarshalRoot(rootFilePath, databasename)
+		root, err := history.unmarshalRoot(rootFilePath, serverID, databasename)
 		if err != nil {
 			return nil, err
 		}
@@ -96,6 +107,9 @@ func (history *historyFileCache) Set(root *schema.Root, serverID string, databas
 	if err != nil {
 		return err
 	}
+	if raw, err = history.codec.encode(raw, serverID, databasename); err != nil {
+		return err
+	}
 
 	newRoot := databasename + ":" + base64.StdEncoding.EncodeToString(raw) + "\n"
 	var exists bool
@@ -133,7 +147,7 @@ func (history *historyFileCache) getRootsFileInfos(dir string) ([]os.FileInfo, e
 	return rootsFileInfos, nil
 }
 
-func (history *historyFileCache) unmarshalRoot(fpath string, databasename string) (*schema.Root, error) {
+func (history *historyFileCache) unmarshalRoot(fpath string, serverID string, databasename string) (*schema.Root, error) {
 	root := schema.NewRoot()
 	raw, err := ioutil.ReadFile(fpath)
 	if err != nil {
@@ -153,6 +167,9 @@ func (history *historyFileCache) unmarshalRoot(fpath string, databasename string
 			if err != nil {
 				return nil, fmt.Errorf("could not find previous root")
 			}
+			if oldRoot, err = history.codec.decode(oldRoot, serverID, databasename); err != nil {
+				return nil, fmt.Errorf("error reading root from %s: %w", fpath, err)
+			}
 
 			if err = proto.Unmarshal(oldRoot, root); err != nil {
 				return nil, fmt.Errorf("error unmarshaling root from %s: %v", fpath, err)
@@ -0,0 +1,56 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"path"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+type namespacedHistoryCache struct {
+	history   HistoryCache
+	namespace string
+}
+
+// NewNamespacedHistoryCache returns a history cache storing the roots in history under the given namespace,
+// so that the roots of different servers sharing the same ID (e.g. not publishing one) never collide
+func NewNamespacedHistoryCache(history HistoryCache, namespace string) HistoryCache {
+	if namespace == "" {
+		return history
+	}
+	return &namespacedHistoryCache{history: history, namespace: namespace}
+}
+
+func (n *namespacedHistoryCache) serverID(serverID string) string {
+	return path.Join(n.namespace, serverID)
+}
+
+func (n *namespacedHistoryCache) Get(serverID string, databasename string) (*schema.Root, error) {
+	return n.history.Get(n.serverID(serverID), databasename)
+}
+
+func (n *namespacedHistoryCache) Set(root *schema.Root, serverID string, databasename string) error {
+	return n.history.Set(root, n.serverID(serverID), databasename)
+}
+
+func (n *namespacedHistoryCache) Walk(
+	serverID string, databasename string,
+	f func(*schema.Root) interface{},
+) ([]interface{}, error) {
+	return n.history.Walk(n.serverID(serverID), databasename, f)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespacedHistoryCache(t *testing.T) {
+	dir := "./test_namespaced"
+	defer os.RemoveAll(dir)
+
+	history := NewHistoryFileCache(dir)
+	require.Equal(t, history, NewNamespacedHistoryCache(history, ""))
+
+	a := NewNamespacedHistoryCache(history, "a")
+	b := NewNamespacedHistoryCache(history, "b")
+
+	require.NoError(t, a.Set(&schema.Root{Payload: &schema.RootIndex{Index: 1}}, "uuid", "db"))
+	require.NoError(t, b.Set(&schema.Root{Payload: &schema.RootIndex{Index: 2}}, "uuid", "db"))
+
+	root, err := a.Get("uuid", "db")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), root.GetIndex())
+
+	root, err = b.Get("uuid", "db")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), root.GetIndex())
+
+	root, err = history.Get("uuid", "db")
+	require.NoError(t, err)
+	require.Nil(t, root)
+
+	indexes, err := a.Walk("uuid", "db", func(root *schema.Root) interface{} {
+		return root.GetIndex()
+	})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{uint64(1)}, indexes)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EtcdOptions configures the cluster of an EtcdStore
+type EtcdOptions struct {
+	// Endpoint is the URL of an etcd member, e.g. http://127.0.0.1:2379
+	Endpoint string
+	// Username and Password, if set, authenticate the requests
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+}
+
+func etcdOptionsFromSettings(settings url.Values) (EtcdOptions, error) {
+	endpoint, err := requireSetting("etcd", settings, "endpoint")
+	if err != nil {
+		return EtcdOptions{}, err
+	}
+	options := EtcdOptions{Endpoint: endpoint, Username: settings.Get("username"), Password: settings.Get("password")}
+	if timeout := settings.Get("timeout"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return EtcdOptions{}, fmt.Errorf("invalid timeout setting of remote store etcd: %v", err)
+		}
+		options.HTTPClient = &http.Client{Timeout: d}
+	}
+	return options, nil
+}
+
+// EtcdStore is a RemoteStore keeping the values in etcd, through the JSON gateway of its v3 API
+type EtcdStore struct {
+	options EtcdOptions
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewEtcdStore returns a store on the etcd cluster described by options
+func NewEtcdStore(options EtcdOptions) *EtcdStore {
+	options.Endpoint = strings.TrimSuffix(options.Endpoint, "/")
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &EtcdStore{options: options}
+}
+
+type etcdKeyValue struct {
+	// keys and values are base64 encoded by encoding/json
+	Key   []byte `json:"key,omitempty"`
+	Value []byte `json:"value,omitempty"`
+}
+
+type etcdRangeResponse struct {
+	Kvs []etcdKeyValue `json:"kvs"`
+}
+
+// Get ...
+func (s *EtcdStore) Get(key string) ([]byte, error) {
+	var resp etcdRangeResponse
+	if err := s.call("/v3/kv/range", etcdKeyValue{Key: []byte(key)}, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	if resp.Kvs[0].Value == nil {
+		return []byte{}, nil
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+type etcdCompare struct {
+	Key    []byte `json:"key"`
+	Result string `json:"result"`
+	Target string `json:"target"`
+	// Value is compared if Target is VALUE, CreateRevision if it is CREATE
+	Value          []byte `json:"value,omitempty"`
+	CreateRevision string `json:"create_revision,omitempty"`
+}
+
+type etcdRequestOp struct {
+	RequestPut etcdKeyValue `json:"request_put"`
+}
+
+type etcdTxnRequest struct {
+	Compare []etcdCompare   `json:"compare"`
+	Success []etcdRequestOp `json:"success"`
+}
+
+type etcdTxnResponse struct {
+	Succeeded bool `json:"succeeded"`
+}
+
+// CompareAndSwap puts value in a transaction comparing the value of key with old, or checking that key has never
+// been created, its create revision being 0, if old is nil
+func (s *EtcdStore) CompareAndSwap(key string, old []byte, value []byte) (bool, error) {
+	compare := etcdCompare{Key: []byte(key), Result: "EQUAL", Target: "VALUE", Value: old}
+	if old == nil {
+		compare = etcdCompare{Key: []byte(key), Result: "EQUAL", Target: "CREATE", CreateRevision: "0"}
+	}
+	request := etcdTxnRequest{
+		Compare: []etcdCompare{compare},
+		Success: []etcdRequestOp{{RequestPut: etcdKeyValue{Key: []byte(key), Value: value}}},
+	}
+	var resp etcdTxnResponse
+	if err := s.call("/v3/kv/txn", request, &resp); err != nil {
+		return false, err
+	}
+	return resp.Succeeded, nil
+}
+
+func (s *EtcdStore) call(path string, request interface{}, response interface{}) error {
+	token, err := s.authenticate()
+	if err != nil {
+		return err
+	}
+	err = s.post(path, token, request, response)
+	if err == errEtcdUnauthenticated && token != "" {
+		// the token expired, authenticate again
+		s.mu.Lock()
+		if s.token == token {
+			s.token = ""
+		}
+		s.mu.Unlock()
+		if token, err = s.authenticate(); err != nil {
+			return err
+		}
+		err = s.post(path, token, request, response)
+	}
+	return err
+}
+
+func (s *EtcdStore) authenticate() (string, error) {
+	if s.options.Username == "" {
+		return "", nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" {
+		return s.token, nil
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	request := map[string]string{"name": s.options.Username, "password": s.options.Password}
+	if err := s.post("/v3/auth/authenticate", "", request, &resp); err != nil {
+		return "", err
+	}
+	s.token = resp.Token
+	return s.token, nil
+}
+
+var errEtcdUnauthenticated = fmt.Errorf("etcd: invalid auth token")
+
+func (s *EtcdStore) post(path string, token string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.options.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := s.options.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusUnauthorized || bytes.Contains(respBody, []byte("invalid auth token")) {
+			return errEtcdUnauthenticated
+		}
+		return fmt.Errorf("etcd: %s %s: %s", path, resp.Status, respBody)
+	}
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, response)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEtcdStore(t *testing.T) {
+	var mu sync.Mutex
+	values := map[string][]byte{}
+	validToken := "token-1"
+	authentications := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/v3/auth/authenticate" {
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			if req["name"] != "root" || req["password"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":"etcdserver: authentication failed, invalid user ID or password"}`))
+				return
+			}
+			authentications++
+			json.NewEncoder(w).Encode(map[string]string{"token": validToken})
+			return
+		}
+		if r.Header.Get("Authorization") != validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"etcdserver: invalid auth token"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v3/kv/txn":
+			var txn etcdTxnRequest
+			json.NewDecoder(r.Body).Decode(&txn)
+			compare := txn.Compare[0]
+			value, ok := values[string(compare.Key)]
+			if compare.Target == "CREATE" && !ok || compare.Target == "VALUE" && ok && bytes.Equal(value, compare.Value) {
+				for _, op := range txn.Success {
+					values[string(op.RequestPut.Key)] = op.RequestPut.Value
+				}
+				w.Write([]byte(`{"succeeded":true}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/v3/kv/range":
+			var kv etcdKeyValue
+			json.NewDecoder(r.Body).Decode(&kv)
+			resp := etcdRangeResponse{}
+			if value, ok := values[string(kv.Key)]; ok {
+				resp.Kvs = append(resp.Kvs, etcdKeyValue{Key: kv.Key, Value: value})
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	store := NewEtcdStore(EtcdOptions{Endpoint: server.URL + "/", Username: "root", Password: "secret"})
+	value, err := store.Get("auditor/uuid/dbName")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	swapped, err := store.CompareAndSwap("auditor/uuid/dbName", nil, []byte("root"))
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+	value, err = store.Get("auditor/uuid/dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("root"), value)
+	assert.Equal(t, 1, authentications)
+
+	// the value is swapped only if it is still the old one
+	swapped, err = store.CompareAndSwap("auditor/uuid/dbName", nil, []byte("other"))
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+	swapped, err = store.CompareAndSwap("auditor/uuid/dbName", []byte("stale"), []byte("other"))
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+	swapped, err = store.CompareAndSwap("auditor/uuid/dbName", []byte("root"), []byte("next"))
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+
+	// an expired token is renewed
+	mu.Lock()
+	validToken = "token-2"
+	mu.Unlock()
+	value, err = store.Get("auditor/uuid/dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("next"), value)
+	assert.Equal(t, 2, authentications)
+
+	wrong := NewEtcdStore(EtcdOptions{Endpoint: server.URL, Username: "root", Password: "wrong"})
+	_, err = wrong.Get("auditor/uuid/dbName")
+	assert.Error(t, err)
+
+	anonymous := NewEtcdStore(EtcdOptions{Endpoint: server.URL})
+	_, err = anonymous.CompareAndSwap("auditor/uuid/dbName", nil, []byte("root"))
+	assert.Equal(t, errEtcdUnauthenticated, err)
+}
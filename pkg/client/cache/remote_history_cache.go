@@ -0,0 +1,185 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/golang/protobuf/proto"
+)
+
+// RemoteStore is a key-value store a remote history cache keeps the roots in,
+// so that they survive the restart of the auditor and can be shared between auditor replicas
+type RemoteStore interface {
+	// Get returns the value of key, nil if not found
+	Get(key string) ([]byte, error)
+	// CompareAndSwap sets key to value atomically if its value is still old, nil meaning that key is not found,
+	// and returns whether it has been set
+	CompareAndSwap(key string, old []byte, value []byte) (bool, error)
+}
+
+// maxRemoteSetAttempts bounds the attempts of Set to advance a root updated concurrently by other auditors
+const maxRemoteSetAttempts = 10
+
+type remoteHistoryCache struct {
+	store  RemoteStore
+	prefix string
+	codec  rootCodec
+}
+
+// NewRemoteHistoryCache returns a history cache keeping the last root of every database in store, under prefix.
+// If key is not nil, the roots are encrypted and authenticated with it, see NewEncryptedHistoryFileCache.
+// Auditors sharing the same store and prefix share the roots: a root only replaces an older one, by index.
+func NewRemoteHistoryCache(store RemoteStore, prefix string, key []byte) (HistoryCache, error) {
+	var codec rootCodec = plainCodec{}
+	if key != nil {
+		c, err := newAEADCodec(key)
+		if err != nil {
+			return nil, err
+		}
+		codec = c
+	}
+	return &remoteHistoryCache{store: store, prefix: prefix, codec: codec}, nil
+}
+
+func (r *remoteHistoryCache) key(serverID string, databasename string) string {
+	return path.Join(r.prefix, serverID, databasename)
+}
+
+func (r *remoteHistoryCache) Get(serverID string, databasename string) (*schema.Root, error) {
+	key := r.key(serverID, databasename)
+	data, err := r.store.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("error reading root from %s: %v", key, err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return r.decode(data, key, serverID, databasename)
+}
+
+func (r *remoteHistoryCache) decode(data []byte, key string, serverID string, databasename string) (*schema.Root, error) {
+	raw, err := r.codec.decode(data, serverID, databasename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading root from %s: %w", key, err)
+	}
+	root := schema.NewRoot()
+	if err = proto.Unmarshal(raw, root); err != nil {
+		return nil, fmt.Errorf("error unmarshaling root from %s: %v", key, err)
+	}
+	return root, nil
+}
+
+// Set advances the root of the database to root, unless the stored one is as recent already. The stored root is
+// compared and swapped, so that concurrent auditors never move it back.
+func (r *remoteHistoryCache) Set(root *schema.Root, serverID string, databasename string) error {
+	raw, err := proto.Marshal(root)
+	if err != nil {
+		return err
+	}
+	if raw, err = r.codec.encode(raw, serverID, databasename); err != nil {
+		return err
+	}
+	key := r.key(serverID, databasename)
+	for attempt := 0; attempt < maxRemoteSetAttempts; attempt++ {
+		current, err := r.store.Get(key)
+		if err != nil {
+			return fmt.Errorf("error reading root from %s: %v", key, err)
+		}
+		if current != nil {
+			stored, err := r.decode(current, key, serverID, databasename)
+			if err != nil {
+				return err
+			}
+			if stored.GetIndex() >= root.GetIndex() {
+				return nil
+			}
+		}
+		swapped, err := r.store.CompareAndSwap(key, current, raw)
+		if err != nil {
+			return fmt.Errorf("error writing root %d to %s: %v", root.GetIndex(), key, err)
+		}
+		if swapped {
+			return nil
+		}
+	}
+	return fmt.Errorf("error writing root %d to %s: updated concurrently %d times", root.GetIndex(), key, maxRemoteSetAttempts)
+}
+
+// Walk calls f with the last root of the database, if any, like the history file cache
+func (r *remoteHistoryCache) Walk(
+	serverID string, databasename string,
+	f func(*schema.Root) interface{},
+) ([]interface{}, error) {
+	root, err := r.Get(serverID, databasename)
+	if err != nil || root == nil {
+		return nil, err
+	}
+	return []interface{}{f(root)}, nil
+}
+
+// ParseRemoteStore returns the remote store described by spec, in the kind?key=value&key=value format:
+//
+//	redis?address=host:port&password=...&db=0&timeout=5s
+//	s3?endpoint=https://s3.amazonaws.com&region=us-east-1&bucket=...&access-key-id=...&secret-access-key=...&path-style=true
+//	etcd?endpoint=http://host:2379&username=...&password=...
+//
+// The credentials of s3 default to the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+func ParseRemoteStore(spec string) (RemoteStore, error) {
+	kind := spec
+	var settings url.Values
+	if i := strings.Index(spec, "?"); i >= 0 {
+		kind = spec[:i]
+		var err error
+		if settings, err = url.ParseQuery(spec[i+1:]); err != nil {
+			return nil, fmt.Errorf("invalid settings of remote store %s: %v", kind, err)
+		}
+	}
+	switch kind {
+	case "redis":
+		options, err := redisOptionsFromSettings(settings)
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisStore(options), nil
+	case "s3":
+		options, err := s3OptionsFromSettings(settings)
+		if err != nil {
+			return nil, err
+		}
+		return NewS3Store(options), nil
+	case "etcd":
+		options, err := etcdOptionsFromSettings(settings)
+		if err != nil {
+			return nil, err
+		}
+		return NewEtcdStore(options), nil
+	}
+	return nil, fmt.Errorf("unknown remote store %q, supported ones are redis, s3 and etcd", kind)
+}
+
+func requireSetting(kind string, settings url.Values, name string) (string, error) {
+	value := settings.Get(name)
+	if value == "" {
+		return "", fmt.Errorf("remote store %s requires the %s setting", kind, name)
+	}
+	return value, nil
+}
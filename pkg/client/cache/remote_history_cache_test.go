@@ -0,0 +1,184 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRemoteStore struct {
+	sync.Mutex
+	values map[string][]byte
+}
+
+func (m *memoryRemoteStore) Get(key string) ([]byte, error) {
+	m.Lock()
+	defer m.Unlock()
+	return m.values[key], nil
+}
+
+func (m *memoryRemoteStore) CompareAndSwap(key string, old []byte, value []byte) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	if current, ok := m.values[key]; ok != (old != nil) || !bytes.Equal(current, old) {
+		return false, nil
+	}
+	m.values[key] = value
+	return true, nil
+}
+
+// racingRemoteStore lets another auditor set its root right before every swap
+type racingRemoteStore struct {
+	*memoryRemoteStore
+	race func()
+}
+
+func (r *racingRemoteStore) CompareAndSwap(key string, old []byte, value []byte) (bool, error) {
+	r.race()
+	return r.memoryRemoteStore.CompareAndSwap(key, old, value)
+}
+
+type failingRemoteStore struct{}
+
+func (failingRemoteStore) Get(key string) ([]byte, error) { return nil, errors.New("unreachable") }
+func (failingRemoteStore) CompareAndSwap(key string, old []byte, value []byte) (bool, error) {
+	return false, errors.New("unreachable")
+}
+
+func TestRemoteHistoryCache(t *testing.T) {
+	store := &memoryRemoteStore{values: map[string][]byte{}}
+	hc, err := NewRemoteHistoryCache(store, "auditor", nil)
+	assert.NoError(t, err)
+
+	root, err := hc.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Nil(t, root)
+	results, err := hc.Walk("uuid", "dbName", func(root *schema.Root) interface{} { return root.GetIndex() })
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	assert.NoError(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 1}}, "uuid", "dbName"))
+	assert.NoError(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 2}}, "uuid", "dbName"))
+	assert.Contains(t, store.values, "auditor/uuid/dbName")
+
+	// another replica sharing the store sees the same roots
+	replica, err := NewRemoteHistoryCache(store, "auditor", nil)
+	assert.NoError(t, err)
+	root, err = replica.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), root.GetIndex())
+	results, err = replica.Walk("uuid", "dbName", func(root *schema.Root) interface{} { return root.GetIndex() })
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{uint64(2)}, results)
+
+	// an older root doesn't replace the stored one
+	assert.NoError(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 1}}, "uuid", "dbName"))
+	root, err = replica.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), root.GetIndex())
+
+	_, err = NewRemoteHistoryCache(store, "auditor", []byte("short"))
+	assert.Equal(t, ErrInvalidCacheKey, err)
+
+	failing, err := NewRemoteHistoryCache(failingRemoteStore{}, "", nil)
+	assert.NoError(t, err)
+	_, err = failing.Get("uuid", "dbName")
+	assert.Error(t, err)
+	assert.Error(t, failing.Set(&schema.Root{}, "uuid", "dbName"))
+}
+
+func TestRemoteHistoryCacheConcurrentSet(t *testing.T) {
+	store := &memoryRemoteStore{values: map[string][]byte{}}
+	other, err := NewRemoteHistoryCache(store, "auditor", nil)
+	assert.NoError(t, err)
+
+	// the root set concurrently is read again: a more recent one is kept, an older one replaced
+	otherIndex := uint64(10)
+	racing := &racingRemoteStore{memoryRemoteStore: store, race: func() {
+		assert.NoError(t, other.Set(&schema.Root{Payload: &schema.RootIndex{Index: otherIndex}}, "uuid", "dbName"))
+	}}
+	hc, err := NewRemoteHistoryCache(racing, "auditor", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 5}}, "uuid", "dbName"))
+	root, err := hc.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), root.GetIndex())
+
+	racing.race = func() {
+		otherIndex++
+		assert.NoError(t, other.Set(&schema.Root{Payload: &schema.RootIndex{Index: otherIndex}}, "uuid", "dbName"))
+	}
+	assert.Error(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 100}}, "uuid", "dbName"))
+
+	racing.race = func() {}
+	assert.NoError(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 100}}, "uuid", "dbName"))
+	root, err = other.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), root.GetIndex())
+}
+
+func TestEncryptedRemoteHistoryCache(t *testing.T) {
+	store := &memoryRemoteStore{values: map[string][]byte{}}
+	hc, err := NewRemoteHistoryCache(store, "auditor", testCacheKey)
+	assert.NoError(t, err)
+	assert.NoError(t, hc.Set(&schema.Root{Payload: &schema.RootIndex{Index: 5}}, "uuid", "dbName"))
+	root, err := hc.Get("uuid", "dbName")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), root.GetIndex())
+
+	store.values["auditor/uuid/other"] = store.values["auditor/uuid/dbName"]
+	_, err = hc.Get("uuid", "other")
+	assert.True(t, errors.Is(err, ErrCorruptedCache), err)
+}
+
+func TestParseRemoteStore(t *testing.T) {
+	store, err := ParseRemoteStore("redis?address=localhost:6379&password=secret&db=2&timeout=1s&tls=true")
+	assert.NoError(t, err)
+	redis := store.(*RedisStore)
+	assert.Equal(t, "localhost:6379", redis.options.Address)
+	assert.Equal(t, "secret", redis.options.Password)
+	assert.Equal(t, 2, redis.options.DB)
+	assert.NotNil(t, redis.options.TLSConfig)
+
+	store, err = ParseRemoteStore("s3?bucket=roots&region=eu-west-1&access-key-id=id&secret-access-key=secret")
+	assert.NoError(t, err)
+	s3 := store.(*S3Store)
+	assert.Equal(t, "https://s3.eu-west-1.amazonaws.com", s3.options.Endpoint)
+	assert.Equal(t, "roots", s3.options.Bucket)
+	assert.Equal(t, "id", s3.options.AccessKeyID)
+
+	store, err = ParseRemoteStore("etcd?endpoint=http://localhost:2379/&username=root&password=secret")
+	assert.NoError(t, err)
+	etcd := store.(*EtcdStore)
+	assert.Equal(t, "http://localhost:2379", etcd.options.Endpoint)
+	assert.Equal(t, "root", etcd.options.Username)
+
+	for _, spec := range []string{
+		"", "memcached", "redis", "redis?address=localhost:6379&db=x", "redis?address=localhost:6379&timeout=x",
+		"s3?region=us-east-1", "s3?bucket=roots&timeout=x", "etcd", "etcd?endpoint=http://localhost:2379&timeout=x",
+		"redis?%zz",
+	} {
+		_, err = ParseRemoteStore(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisOptions configures the connection of a RedisStore
+type RedisOptions struct {
+	Address  string
+	Password string
+	DB       int
+	Timeout  time.Duration
+	// TLSConfig, if set, enables TLS
+	TLSConfig *tls.Config
+}
+
+func redisOptionsFromSettings(settings url.Values) (RedisOptions, error) {
+	address, err := requireSetting("redis", settings, "address")
+	if err != nil {
+		return RedisOptions{}, err
+	}
+	options := RedisOptions{Address: address, Password: settings.Get("password"), Timeout: 5 * time.Second}
+	if db := settings.Get("db"); db != "" {
+		if options.DB, err = strconv.Atoi(db); err != nil {
+			return RedisOptions{}, fmt.Errorf("invalid db setting of remote store redis: %v", err)
+		}
+	}
+	if timeout := settings.Get("timeout"); timeout != "" {
+		if options.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return RedisOptions{}, fmt.Errorf("invalid timeout setting of remote store redis: %v", err)
+		}
+	}
+	if tlsEnabled := settings.Get("tls"); tlsEnabled == "true" {
+		options.TLSConfig = &tls.Config{}
+	}
+	return options, nil
+}
+
+// RedisStore is a RemoteStore keeping the values in Redis, speaking its protocol (RESP) over a single connection
+type RedisStore struct {
+	options RedisOptions
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// NewRedisStore returns a store connecting to Redis on first use
+func NewRedisStore(options RedisOptions) *RedisStore {
+	return &RedisStore{options: options}
+}
+
+// redisError is an error replied by the server, which leaves the connection usable
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// Get ...
+func (s *RedisStore) Get(key string) ([]byte, error) {
+	reply, err := s.do("GET", []byte(key))
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected reply to GET: %v", reply)
+	}
+	return value, nil
+}
+
+// CompareAndSwap watches key while reading it, so that the transaction setting it is aborted if another client sets
+// it in between
+func (s *RedisStore) CompareAndSwap(key string, old []byte, value []byte) (bool, error) {
+	reply, err := s.exchange(false, func() (interface{}, error) {
+		if _, err := s.roundTrip("WATCH", []byte(key)); err != nil {
+			return nil, err
+		}
+		current, err := s.roundTrip("GET", []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		if currentValue, _ := current.([]byte); (current == nil) != (old == nil) || !bytes.Equal(currentValue, old) {
+			_, err = s.roundTrip("UNWATCH")
+			return nil, err
+		}
+		if _, err = s.roundTrip("MULTI"); err != nil {
+			return nil, err
+		}
+		if _, err = s.roundTrip("SET", []byte(key), value); err != nil {
+			return nil, err
+		}
+		// the reply is null if the transaction has been aborted
+		return s.roundTrip("EXEC")
+	})
+	return reply != nil, err
+}
+
+// Close closes the connection to the server, if any
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *RedisStore) do(command string, args ...[]byte) (interface{}, error) {
+	return s.exchange(true, func() (interface{}, error) {
+		return s.roundTrip(command, args...)
+	})
+}
+
+// exchange runs f on the connection, dialing it first if needed. The connection is dialed again by the next exchange
+// if f fails, unless the error has been replied by the server and keepOnReply is set.
+func (s *RedisStore) exchange(keepOnReply bool, f func() (interface{}, error)) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := f()
+	var replied redisError
+	if err != nil && (!keepOnReply || !errors.As(err, &replied)) {
+		// the connection is in an unknown state
+		s.conn.Close()
+		s.conn = nil
+	}
+	return reply, err
+}
+
+func (s *RedisStore) connect() (err error) {
+	dialer := &net.Dialer{Timeout: s.options.Timeout}
+	if s.options.TLSConfig != nil {
+		s.conn, err = tls.DialWithDialer(dialer, "tcp", s.options.Address, s.options.TLSConfig)
+	} else {
+		s.conn, err = dialer.Dial("tcp", s.options.Address)
+	}
+	if err != nil {
+		s.conn = nil
+		return fmt.Errorf("redis: error connecting to %s: %v", s.options.Address, err)
+	}
+	s.r = bufio.NewReader(s.conn)
+
+	if s.options.Password != "" {
+		_, err = s.roundTrip("AUTH", []byte(s.options.Password))
+	}
+	if err == nil && s.options.DB != 0 {
+		_, err = s.roundTrip("SELECT", []byte(strconv.Itoa(s.options.DB)))
+	}
+	if err != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *RedisStore) roundTrip(command string, args ...[]byte) (interface{}, error) {
+	if s.options.Timeout > 0 {
+		s.conn.SetDeadline(time.Now().Add(s.options.Timeout))
+	}
+	buf := []byte("*" + strconv.Itoa(len(args)+1) + "\r\n")
+	buf = appendBulkString(buf, []byte(command))
+	for _, arg := range args {
+		buf = appendBulkString(buf, arg)
+	}
+	if _, err := s.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readRedisReply(s.r)
+}
+
+func appendBulkString(buf []byte, s []byte) []byte {
+	buf = append(buf, "$"+strconv.Itoa(len(s))+"\r\n"...)
+	buf = append(buf, s...)
+	return append(buf, "\r\n"...)
+}
+
+// readRedisReply reads a reply: a string for simple strings, []byte for bulk strings, int64 for integers,
+// []interface{} for arrays and nil for null replies
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < -1 {
+			return nil, fmt.Errorf("redis: malformed bulk string length %q", payload)
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		value := make([]byte, n+2)
+		if _, err = io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+		return value[:n], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < -1 {
+			return nil, fmt.Errorf("redis: malformed array length %q", payload)
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis serves GET, SET, AUTH, SELECT and the WATCH transactions from memory, closing the connection on QUIT
+type fakeRedis struct {
+	listener net.Listener
+	password string
+	// beforeExec, if set, is called before executing a transaction
+	beforeExec func()
+
+	mu       sync.Mutex
+	values   map[string][]byte
+	versions map[string]int
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	f := &fakeRedis{listener: listener, password: password, values: map[string][]byte{}, versions: map[string]int{}}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authenticated := f.password == ""
+	watched := map[string]int{}
+	var queued [][]string
+	for {
+		reply, err := readRedisReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		f.mu.Unlock()
+
+		var out string
+		switch {
+		case args[0] == "QUIT":
+			return
+		case args[0] == "AUTH":
+			authenticated = args[1] == f.password
+			out = "+OK\r\n"
+			if !authenticated {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			out = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SELECT":
+			out = "+OK\r\n"
+		case args[0] == "WATCH":
+			f.mu.Lock()
+			watched[args[1]] = f.versions[args[1]]
+			f.mu.Unlock()
+			out = "+OK\r\n"
+		case args[0] == "UNWATCH":
+			watched = map[string]int{}
+			out = "+OK\r\n"
+		case args[0] == "MULTI":
+			queued = [][]string{}
+			out = "+OK\r\n"
+		case args[0] == "EXEC":
+			f.mu.Lock()
+			beforeExec := f.beforeExec
+			f.mu.Unlock()
+			if beforeExec != nil {
+				beforeExec()
+			}
+			f.mu.Lock()
+			out = "*" + strconv.Itoa(len(queued)) + "\r\n"
+			for key, version := range watched {
+				if f.versions[key] != version {
+					out = "*-1\r\n"
+				}
+			}
+			for _, command := range queued {
+				if out != "*-1\r\n" {
+					f.set(command[1], command[2])
+					out += "+OK\r\n"
+				}
+			}
+			f.mu.Unlock()
+			queued, watched = nil, map[string]int{}
+		case args[0] == "SET" && queued != nil:
+			queued = append(queued, args)
+			out = "+QUEUED\r\n"
+		case args[0] == "SET":
+			f.mu.Lock()
+			f.set(args[1], args[2])
+			f.mu.Unlock()
+			out = "+OK\r\n"
+		case args[0] == "GET":
+			f.mu.Lock()
+			value, ok := f.values[args[1]]
+			f.mu.Unlock()
+			out = "$-1\r\n"
+			if ok {
+				out = string(appendBulkString(nil, value))
+			}
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		conn.Write([]byte(out))
+	}
+}
+
+func (f *fakeRedis) set(key string, value string) {
+	f.values[key] = []byte(value)
+	f.versions[key]++
+}
+
+func TestRedisStore(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.listener.Close()
+
+	store := NewRedisStore(RedisOptions{Address: server.listener.Addr().String(), Password: "secret", DB: 3, Timeout: time.Second})
+	defer store.Close()
+
+	value, err := store.Get("key")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	binary := []byte("value\r\nwith\x00binary")
+	swapped, err := store.CompareAndSwap("key", nil, binary)
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+	value, err = store.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, binary, value)
+
+	// the value is swapped only if it is still the old one
+	swapped, err = store.CompareAndSwap("key", nil, []byte("other"))
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+	server.mu.Lock()
+	server.beforeExec = func() {
+		server.mu.Lock()
+		server.set("key", string(binary))
+		server.mu.Unlock()
+	}
+	server.mu.Unlock()
+	swapped, err = store.CompareAndSwap("key", binary, []byte("other"))
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+	server.mu.Lock()
+	server.beforeExec = nil
+	server.mu.Unlock()
+	value, err = store.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, binary, value)
+
+	// errors replied by the server keep the connection
+	_, err = store.do("UNKNOWN")
+	assert.EqualError(t, err, "redis: ERR unknown command")
+
+	// a closed connection is dialed again
+	_, err = store.do("QUIT")
+	assert.Error(t, err)
+	value, err = store.Get("key")
+	assert.NoError(t, err)
+	assert.Equal(t, binary, value)
+
+	server.mu.Lock()
+	assert.Equal(t, []string{"AUTH secret", "SELECT 3", "GET key"}, server.commands[:3])
+	assert.Equal(t, "AUTH secret", server.commands[len(server.commands)-3])
+	server.mu.Unlock()
+
+	wrong := NewRedisStore(RedisOptions{Address: server.listener.Addr().String(), Password: "wrong", Timeout: time.Second})
+	_, err = wrong.Get("key")
+	assert.EqualError(t, err, "redis: WRONGPASS invalid password")
+	assert.Nil(t, wrong.conn)
+
+	unreachable := NewRedisStore(RedisOptions{Address: "127.0.0.1:1", Timeout: time.Second})
+	_, err = unreachable.Get("key")
+	assert.Error(t, err)
+	assert.NoError(t, unreachable.Close())
+}
+
+func TestReadRedisReply(t *testing.T) {
+	reply, err := readRedisReply(bufio.NewReader(strings.NewReader("*3\r\n:42\r\n+OK\r\n$-1\r\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(42), "OK", nil}, reply)
+
+	for _, malformed := range []string{"", "+OK\n", "$x\r\n", "$5\r\nab", "*x\r\n", "*2\r\n+OK\r\n", "?\r\n"} {
+		_, err = readRedisReply(bufio.NewReader(strings.NewReader(malformed)))
+		assert.Error(t, err, malformed)
+	}
+}
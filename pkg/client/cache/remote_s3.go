@@ -0,0 +1,259 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Options configures the bucket of an S3Store. Any S3 compatible service (e.g. MinIO) can be used.
+type S3Options struct {
+	// Endpoint is the URL of the service, e.g. https://s3.amazonaws.com
+	Endpoint string
+	Region   string
+	Bucket   string
+	// PathStyle addresses the bucket as a path of the endpoint instead of as a subdomain
+	PathStyle       bool
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	HTTPClient      *http.Client
+}
+
+func s3OptionsFromSettings(settings url.Values) (S3Options, error) {
+	bucket, err := requireSetting("s3", settings, "bucket")
+	if err != nil {
+		return S3Options{}, err
+	}
+	options := S3Options{
+		Endpoint:        settings.Get("endpoint"),
+		Region:          settings.Get("region"),
+		Bucket:          bucket,
+		PathStyle:       settings.Get("path-style") == "true",
+		AccessKeyID:     settings.Get("access-key-id"),
+		SecretAccessKey: settings.Get("secret-access-key"),
+		SessionToken:    settings.Get("session-token"),
+	}
+	if options.AccessKeyID == "" {
+		options.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		options.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		options.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if timeout := settings.Get("timeout"); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil {
+			return S3Options{}, fmt.Errorf("invalid timeout setting of remote store s3: %v", err)
+		}
+		options.HTTPClient = &http.Client{Timeout: d}
+	}
+	return options, nil
+}
+
+// S3Store is a RemoteStore keeping every value in an object of an S3 bucket, signing the requests with AWS Signature V4
+type S3Store struct {
+	options S3Options
+	now     func() time.Time
+}
+
+// NewS3Store returns a store on the bucket described by options. The endpoint defaults to AWS in the region,
+// the region to us-east-1.
+func NewS3Store(options S3Options) *S3Store {
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+	if options.Endpoint == "" {
+		options.Endpoint = "https://s3." + options.Region + ".amazonaws.com"
+	}
+	options.Endpoint = strings.TrimSuffix(options.Endpoint, "/")
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &S3Store{options: options, now: time.Now}
+}
+
+func (s *S3Store) objectURL(key string) (string, error) {
+	u, err := url.Parse(s.options.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	escapedKey := (&url.URL{Path: key}).EscapedPath()
+	if s.options.PathStyle {
+		return u.Scheme + "://" + u.Host + u.Path + "/" + s.options.Bucket + "/" + escapedKey, nil
+	}
+	return u.Scheme + "://" + s.options.Bucket + "." + u.Host + u.Path + "/" + escapedKey, nil
+}
+
+// Get ...
+func (s *S3Store) Get(key string) ([]byte, error) {
+	value, _, err := s.get(key)
+	return value, err
+}
+
+// get returns the value of key and the ETag of its object
+func (s *S3Store) get(key string) ([]byte, string, error) {
+	resp, err := s.do("GET", key, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("s3: GET %s: %s: %s", key, resp.Status, body)
+	}
+	return body, resp.Header.Get("ETag"), nil
+}
+
+// CompareAndSwap puts the object of key with a conditional request: If-Match the ETag of the object read, or
+// If-None-Match any object if old is nil. The service fails the request if the object has been put in between.
+func (s *S3Store) CompareAndSwap(key string, old []byte, value []byte) (bool, error) {
+	current, etag, err := s.get(key)
+	if err != nil {
+		return false, err
+	}
+	if (current == nil) != (old == nil) || !bytes.Equal(current, old) {
+		return false, nil
+	}
+	condition := map[string]string{"If-None-Match": "*"}
+	if current != nil {
+		condition = map[string]string{"If-Match": etag}
+	}
+	resp, err := s.do("PUT", key, value, condition)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		// 409 is replied if a conflicting conditional request is in progress
+		return false, nil
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	return false, fmt.Errorf("s3: PUT %s: %s: %s", key, resp.Status, body)
+}
+
+func (s *S3Store) do(method string, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if s.options.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.options.SessionToken)
+	}
+	signV4(req, body, s.options.AccessKeyID, s.options.SecretAccessKey, s.options.Region, "s3", s.now())
+	return s.options.HTTPClient.Do(req)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// signV4 signs req with AWS Signature Version 4, covering the host and all the headers set on req
+func signV4(req *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		",SignedHeaders="+signedHeaders+",Signature="+signature)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string{}, query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape escapes s as required by AWS: every byte but the unreserved characters, with spaces as %20
+func awsEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
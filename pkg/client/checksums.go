@@ -0,0 +1,78 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrResponseChecksumMismatch is returned when the payload of a response doesn't match its checksum, as when it is
+// corrupted in transit
+var ErrResponseChecksumMismatch = errors.New("response checksum mismatch: the response was corrupted in transit")
+
+// ErrChecksumsUnsupported is returned when checksums are enabled but the server answers without checksum, as servers
+// predating checksums do
+var ErrChecksumsUnsupported = errors.New("the server doesn't support checksums")
+
+// checksumInterceptor sends the checksum of every request, so that the server refuses the requests corrupted in
+// transit before handling them, and verifies the checksum the server answers with
+func checksumInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	sum, err := server.Checksum(msg)
+	if err != nil {
+		return err
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, server.CHECKSUM_HEADER, sum)
+
+	var header metadata.MD
+	if err = invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...); err != nil {
+		return err
+	}
+
+	msg, ok = reply.(proto.Message)
+	if !ok {
+		return nil
+	}
+	values := header.Get(server.CHECKSUM_HEADER)
+	if len(values) == 0 {
+		return ErrChecksumsUnsupported
+	}
+	if sum, err = server.Checksum(msg); err != nil {
+		return err
+	}
+	if sum != values[0] {
+		return ErrResponseChecksumMismatch
+	}
+	return nil
+}
+
+// checksumDialOptions returns the dial options checksumming the unary calls, if checksums are enabled
+func checksumDialOptions(options *Options) []grpc.DialOption {
+	if !options.Checksums {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(checksumInterceptor)}
+}
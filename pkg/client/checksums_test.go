@@ -0,0 +1,92 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestChecksums(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true).WithRequireChecksums(true))
+	// the checksums are verified by an interceptor of the server, missing from the grpc server of servertest
+	bs.GrpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(auth.ServerUnaryInterceptor,
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return bs.Server.ChecksumInterceptor(ctx, req, info, handler)
+		}))
+	bs.Start()
+
+	dir, err := ioutil.TempDir("", "checksums_client")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	plain, err := grpc.Dial("bufnet", grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer plain.Close()
+	_, err = schema.NewImmuServiceClient(plain).Health(context.TODO(), &empty.Empty{})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(NewHomedirService())
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	cli, err := NewImmuClient(DefaultOptions().WithDir(dir).WithDialOptions(&dialOptions).WithTokenService(ts).WithChecksums(true))
+	require.NoError(t, err)
+	defer cli.Disconnect()
+
+	lresp, err := cli.Login(context.TODO(), []byte("immudb"), []byte("immudb"))
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lresp.Token))
+
+	_, err = cli.SafeSet(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+	item, err := cli.SafeGet(ctx, []byte("key"))
+	require.NoError(t, err)
+	require.True(t, item.Verified)
+	require.Equal(t, []byte("value"), item.Value)
+
+	// a payload corrupted after its checksum has been computed is refused before being written
+	corrupting := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if kv, ok := req.(*schema.KeyValue); ok {
+			kv.Value = []byte("corrupted")
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(checksumInterceptor, corrupting))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = schema.NewImmuServiceClient(conn).Set(ctx, &schema.KeyValue{Key: []byte("key"), Value: []byte("value2")})
+	require.Equal(t, codes.DataLoss, status.Code(err))
+
+	item, err = cli.SafeGet(ctx, []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), item.Value)
+}
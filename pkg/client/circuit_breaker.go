@@ -0,0 +1,264 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCircuitOpen is returned without calling the server while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker is open: immudb server is failing or too slow")
+
+// CircuitState is the state of the circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every call with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe call through at a time to check whether the server recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerOptions configures the circuit breaker which stops calling a degraded immudb server,
+// so that its errors and latency do not cascade into every request of the application
+type CircuitBreakerOptions struct {
+	// Window is the period over which failed calls are counted
+	Window time.Duration
+	// MinCalls is the number of calls within a window below which the circuit never opens
+	MinCalls int
+	// ErrorRate is the ratio of failed calls within a window, between 0 and 1, opening the circuit
+	ErrorRate float64
+	// SlowCallDuration, if positive, counts as failed the calls lasting longer, even if successful
+	SlowCallDuration time.Duration
+	// OpenTimeout is how long the circuit stays open before probing the server again
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of successful probe calls closing the circuit again
+	HalfOpenProbes int
+	// OnStateChange, if set, is called whenever the circuit changes state
+	OnStateChange func(from, to CircuitState)
+}
+
+// DefaultCircuitBreakerOptions opens the circuit for 30 seconds when half of at least 20 calls within 10 seconds
+// fail or last more than 5 seconds, closing it again after 3 successful probes
+func DefaultCircuitBreakerOptions() *CircuitBreakerOptions {
+	return &CircuitBreakerOptions{
+		Window:           10 * time.Second,
+		MinCalls:         20,
+		ErrorRate:        0.5,
+		SlowCallDuration: 5 * time.Second,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenProbes:   3,
+	}
+}
+
+// Validate checks the thresholds are meaningful
+func (o *CircuitBreakerOptions) Validate() error {
+	if o.Window <= 0 || o.OpenTimeout <= 0 || o.SlowCallDuration < 0 {
+		return fmt.Errorf("%w: circuit breaker window and open timeout must be positive", ErrIllegalArguments)
+	}
+	if o.ErrorRate <= 0 || o.ErrorRate > 1 {
+		return fmt.Errorf("%w: circuit breaker error rate must be greater than 0 and not exceed 1", ErrIllegalArguments)
+	}
+	if o.MinCalls < 1 || o.HalfOpenProbes < 1 {
+		return fmt.Errorf("%w: circuit breaker min calls and half-open probes must be at least 1", ErrIllegalArguments)
+	}
+	return nil
+}
+
+type circuitBreaker struct {
+	options CircuitBreakerOptions
+	now     func() time.Time
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	calls       int
+	failures    int
+	openedAt    time.Time
+	probing     bool
+	probes      int
+}
+
+func newCircuitBreaker(options CircuitBreakerOptions) *circuitBreaker {
+	return &circuitBreaker{options: options, now: time.Now}
+}
+
+// currentState returns the state of the circuit, reporting as half-open an open circuit ready to be probed
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.options.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow tells whether a call may be sent to the server. probe is true for the probe calls of a half-open circuit.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.options.OpenTimeout {
+			return false, ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+		b.probes = 0
+		fallthrough
+	case CircuitHalfOpen:
+		if b.probing {
+			return false, ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// done records the outcome of a call allowed by allow
+func (b *circuitBreaker) done(probe bool, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if probe {
+		b.probing = false
+		if failed {
+			b.open(now)
+			return
+		}
+		if b.probes++; b.probes >= b.options.HalfOpenProbes {
+			b.setState(CircuitClosed)
+			b.resetWindow(now)
+		}
+		return
+	}
+	if b.state != CircuitClosed {
+		// a call started before the circuit opened
+		return
+	}
+
+	if now.Sub(b.windowStart) >= b.options.Window {
+		b.resetWindow(now)
+	}
+	b.calls++
+	if failed {
+		b.failures++
+	}
+	if b.calls >= b.options.MinCalls && float64(b.failures) >= b.options.ErrorRate*float64(b.calls) {
+		b.open(now)
+	}
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.setState(CircuitOpen)
+	b.openedAt = now
+}
+
+func (b *circuitBreaker) resetWindow(now time.Time) {
+	b.windowStart = now
+	b.calls = 0
+	b.failures = 0
+}
+
+func (b *circuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.options.OnStateChange != nil {
+		b.options.OnStateChange(from, state)
+	}
+}
+
+// isServerFailure tells whether err is a sign of a degraded server, as opposed to e.g. a key not found
+// or a call cancelled by the application
+func isServerFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+func (b *circuitBreaker) failed(start time.Time, err error) bool {
+	return isServerFailure(err) ||
+		(b.options.SlowCallDuration > 0 && b.now().Sub(start) > b.options.SlowCallDuration)
+}
+
+func (b *circuitBreaker) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		probe, err := b.allow()
+		if err != nil {
+			return err
+		}
+		start := b.now()
+		err = invoker(ctx, method, req, reply, cc, opts...)
+		b.done(probe, b.failed(start, err))
+		return err
+	}
+}
+
+// streamInterceptor only accounts for the opening of streams, whose duration depends on the application
+func (b *circuitBreaker) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		probe, err := b.allow()
+		if err != nil {
+			return nil, err
+		}
+		start := b.now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		b.done(probe, b.failed(start, err))
+		return stream, err
+	}
+}
+
+// circuitBreakerDialOptions returns the dial options protecting the calls with a circuit breaker, if enabled
+func circuitBreakerDialOptions(options *Options) []grpc.DialOption {
+	if options.CircuitBreaker == nil {
+		return nil
+	}
+	breaker := newCircuitBreaker(*options.CircuitBreaker)
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(breaker.unaryInterceptor()),
+		grpc.WithChainStreamInterceptor(breaker.streamInterceptor()),
+	}
+}
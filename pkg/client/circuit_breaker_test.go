@@ -0,0 +1,146 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var transitions []string
+	options := CircuitBreakerOptions{
+		Window:           time.Minute,
+		MinCalls:         4,
+		ErrorRate:        0.5,
+		SlowCallDuration: time.Second,
+		OpenTimeout:      10 * time.Second,
+		HalfOpenProbes:   2,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	}
+	require.NoError(t, options.Validate())
+
+	now := time.Unix(0, 0)
+	breaker := newCircuitBreaker(options)
+	breaker.now = func() time.Time { return now }
+
+	calls := 0
+	invoker := func(err error, duration time.Duration) grpc.UnaryInvoker {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			now = now.Add(duration)
+			return err
+		}
+	}
+	call := breaker.unaryInterceptor()
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	notFound := status.Error(codes.NotFound, "key not found")
+
+	// errors caused by the application do not count as failures
+	for i := 0; i < 4; i++ {
+		require.Equal(t, notFound, call(context.Background(), "/Get", nil, nil, nil, invoker(notFound, 0)))
+	}
+	require.Equal(t, CircuitClosed, breaker.currentState())
+
+	// a new window starts, below MinCalls the circuit stays closed
+	now = now.Add(time.Minute)
+	require.Equal(t, unavailable, call(context.Background(), "/Get", nil, nil, nil, invoker(unavailable, 0)))
+	require.NoError(t, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 2*time.Second)))
+	require.NoError(t, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 0)))
+	require.Equal(t, CircuitClosed, breaker.currentState())
+	// a slow call is a failure
+	require.NoError(t, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 2*time.Second)))
+	require.Equal(t, CircuitOpen, breaker.currentState())
+
+	// the server is not called while the circuit is open
+	calls = 0
+	require.Equal(t, ErrCircuitOpen, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 0)))
+	require.Equal(t, 0, calls)
+
+	// a failed probe opens the circuit again
+	now = now.Add(10 * time.Second)
+	require.Equal(t, CircuitHalfOpen, breaker.currentState())
+	require.Equal(t, unavailable, call(context.Background(), "/Get", nil, nil, nil, invoker(unavailable, 0)))
+	require.Equal(t, CircuitOpen, breaker.currentState())
+	require.Equal(t, ErrCircuitOpen, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 0)))
+
+	// successful probes close the circuit
+	now = now.Add(10 * time.Second)
+	require.NoError(t, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 0)))
+	require.Equal(t, CircuitHalfOpen, breaker.currentState())
+	require.NoError(t, call(context.Background(), "/Get", nil, nil, nil, invoker(nil, 0)))
+	require.Equal(t, CircuitClosed, breaker.currentState())
+
+	require.Equal(t, []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}, transitions)
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	options := DefaultCircuitBreakerOptions()
+	options.MinCalls = 1
+	now := time.Unix(0, 0)
+	breaker := newCircuitBreaker(*options)
+	breaker.now = func() time.Time { return now }
+
+	breaker.done(false, true)
+	require.Equal(t, CircuitOpen, breaker.currentState())
+
+	now = now.Add(options.OpenTimeout)
+	probe, err := breaker.allow()
+	require.NoError(t, err)
+	require.True(t, probe)
+	// further calls are rejected while the probe is in progress
+	_, err = breaker.allow()
+	require.Equal(t, ErrCircuitOpen, err)
+	breaker.done(true, false)
+	probe, err = breaker.allow()
+	require.NoError(t, err)
+	require.True(t, probe)
+}
+
+func TestCircuitBreakerOptionsValidate(t *testing.T) {
+	require.NoError(t, DefaultCircuitBreakerOptions().Validate())
+	for _, change := range []func(o *CircuitBreakerOptions){
+		func(o *CircuitBreakerOptions) { o.Window = 0 },
+		func(o *CircuitBreakerOptions) { o.OpenTimeout = 0 },
+		func(o *CircuitBreakerOptions) { o.SlowCallDuration = -1 },
+		func(o *CircuitBreakerOptions) { o.ErrorRate = 0 },
+		func(o *CircuitBreakerOptions) { o.ErrorRate = 1.5 },
+		func(o *CircuitBreakerOptions) { o.MinCalls = 0 },
+		func(o *CircuitBreakerOptions) { o.HalfOpenProbes = 0 },
+	} {
+		options := DefaultCircuitBreakerOptions()
+		change(options)
+		err := options.Validate()
+		require.True(t, errors.Is(err, ErrIllegalArguments), err)
+	}
+
+	_, err := NewImmuClient(DefaultOptions().WithCircuitBreaker(&CircuitBreakerOptions{}))
+	require.True(t, errors.Is(err, ErrIllegalArguments))
+}
@@ -19,6 +19,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/binary"
@@ -26,7 +27,9 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -35,15 +38,19 @@ import (
 	"github.com/codenotary/immudb/pkg/auth"
 	"github.com/codenotary/immudb/pkg/client/cache"
 	"github.com/codenotary/immudb/pkg/client/timestamp"
+	"github.com/codenotary/immudb/pkg/compression"
 	"github.com/codenotary/immudb/pkg/logger"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/status"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc"
 
+	"github.com/codenotary/immudb/pkg/api"
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/store"
 )
@@ -58,6 +65,9 @@ type ImmuClient interface {
 	Logout(ctx context.Context) error
 	CreateUser(ctx context.Context, user []byte, pass []byte, permission uint32, databasename string) error
 	ListUsers(ctx context.Context) (*schema.UserList, error)
+	ListSessions(ctx context.Context) (*schema.SessionList, error)
+	GetUsage(ctx context.Context, database string, since time.Time, until time.Time) (*schema.UsageReport, error)
+	GetAccessControlState(ctx context.Context) (*schema.AccessControlState, error)
 	ChangePassword(ctx context.Context, user []byte, oldPass []byte, newPass []byte) error
 	ChangePermission(ctx context.Context, action schema.PermissionAction, username string, database string, permissions uint32) error
 	UpdateAuthConfig(ctx context.Context, kind auth.Kind) error
@@ -70,26 +80,55 @@ type ImmuClient interface {
 	Get(ctx context.Context, key []byte) (*schema.StructuredItem, error)
 	SafeGet(ctx context.Context, key []byte, opts ...grpc.CallOption) (*VerifiedItem, error)
 	RawSafeGet(ctx context.Context, key []byte, opts ...grpc.CallOption) (*VerifiedItem, error)
-	Scan(ctx context.Context, options *schema.ScanOptions) (*schema.StructuredItemList, error)
-	ZScan(ctx context.Context, options *schema.ZScanOptions) (*schema.ZStructuredItemList, error)
+	Scan(ctx context.Context, options *schema.ScanOptions) (*StructuredItemPage, error)
+	ZScan(ctx context.Context, options *schema.ZScanOptions) (*ZStructuredItemPage, error)
+	SafeZScan(ctx context.Context, options *schema.ZScanOptions) (*VerifiedZItemPage, error)
 	ByIndex(ctx context.Context, index uint64) (*schema.StructuredItem, error)
+	GetBySequence(ctx context.Context, sequence uint64) (*schema.SequencedWrite, error)
+	VerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*VerifiedValueHash, error)
+	RawVerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*VerifiedValueHash, error)
 	RawBySafeIndex(ctx context.Context, index uint64) (*VerifiedItem, error)
 	IScan(ctx context.Context, pageNumber uint64, pageSize uint64) (*schema.SPage, error)
 	Count(ctx context.Context, prefix []byte) (*schema.ItemsCount, error)
 	CountAll(ctx context.Context) (*schema.ItemsCount, error)
+	Stats(ctx context.Context, prefix []byte) (*schema.PrefixStats, error)
+	VerifiedCount(ctx context.Context, prefix []byte, sampleSize uint64) (*VerifiedCount, error)
+	VerifiedExists(ctx context.Context, key []byte) (*VerifiedExistence, error)
 	SetAll(ctx context.Context, kvList *schema.KVList) (*schema.Index, error)
 	ExecAllOps(ctx context.Context, in *schema.Ops) (*schema.Index, error)
+	ExecAllTx(ctx context.Context, in *schema.Ops) (*schema.Index, error)
+	SafeExecAllTx(ctx context.Context, in *schema.Ops) (*VerifiedIndex, error)
+	CompareAndExecAllTx(ctx context.Context, in *schema.Ops, expected []*schema.ExpectedIndex) (*schema.Index, error)
+	Begin(ctx context.Context) (*Tx, error)
 	SetBatch(ctx context.Context, request *BatchRequest) (*schema.Index, error)
 	GetBatch(ctx context.Context, keys [][]byte) (*schema.StructuredItemList, error)
+	SafeGetBatch(ctx context.Context, keys [][]byte) (*VerifiedItemList, error)
 	Inclusion(ctx context.Context, index uint64) (*schema.InclusionProof, error)
 	Consistency(ctx context.Context, index uint64) (*schema.ConsistencyProof, error)
-	History(ctx context.Context, options *schema.HistoryOptions) (*schema.StructuredItemList, error)
+	History(ctx context.Context, options *schema.HistoryOptions) (*StructuredItemPage, error)
 	Reference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*schema.Index, error)
 	GetReference(ctx context.Context, key *schema.Key) (*schema.StructuredItem, error)
+	GetReferences(ctx context.Context, key []byte, index *schema.Index) (*schema.ReferenceList, error)
+	GetReferenceChain(ctx context.Context, reference []byte) (*ReferenceChain, error)
+	CompareAndReference(ctx context.Context, reference []byte, key []byte, value []byte, expectedIndex *schema.Index) (*schema.Index, error)
 	SafeReference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*VerifiedIndex, error)
 	ZAdd(ctx context.Context, set []byte, score float64, key []byte, index *schema.Index) (*schema.Index, error)
 	SafeZAdd(ctx context.Context, set []byte, score float64, key []byte, index *schema.Index) (*VerifiedIndex, error)
 	Dump(ctx context.Context, writer io.WriteSeeker) (int64, error)
+	DumpKeyHistory(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error)
+	HistoryTimeline(ctx context.Context, key []byte, verify bool) (*KeyTimeline, error)
+	GetAt(ctx context.Context, key []byte, index uint64) (*VerifiedItem, error)
+	GetAsOf(ctx context.Context, key []byte, t time.Time) (*VerifiedItem, error)
+	SampleKeys(ctx context.Context, size uint64, prefix []byte, seed int64) (*schema.KeySample, error)
+	Delete(ctx context.Context, key []byte) (*VerifiedIndex, error)
+	Unreference(ctx context.Context, reference []byte) (*VerifiedIndex, error)
+	FreezePrefix(ctx context.Context, prefix []byte) (*VerifiedIndex, error)
+	AnalyzeStorage(ctx context.Context) (*schema.StorageReport, error)
+	Backup(ctx context.Context) (*schema.BackupInfo, error)
+	RestoreBackup(ctx context.Context, file string, databaseName string) (*schema.BackupInfo, error)
+	DownloadVerificationBundle(ctx context.Context, w io.Writer, sampleSize uint64) (*schema.VerificationBundle, error)
+	Logs(ctx context.Context, req *schema.LogRequest, handler func(*schema.LogEntry) error) error
+	Subscribe(ctx context.Context, req *schema.SubscribeRequest, handler func(*VerifiedItem) error) error
 	HealthCheck(ctx context.Context) error
 	verifyAndSetRoot(result *schema.Proof, root *schema.Root, ctx context.Context) (bool, error)
 
@@ -123,6 +162,7 @@ type immuClient struct {
 	Rootservice   rootservice.RootService
 	ts            TimestampService
 	Tkns          TokenService
+	metrics       *clientMetrics
 	sync.RWMutex
 }
 
@@ -144,12 +184,27 @@ func NewImmuClient(options *Options) (c ImmuClient, err error) {
 	c.WithLogger(l)
 	c.WithTokenService(options.Tkns.WithTokenFileName(options.TokenFileName))
 
+	if err = compression.Validate(options.Compression); err != nil {
+		return nil, err
+	}
+	if options.CircuitBreaker != nil {
+		if err = options.CircuitBreaker.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	rootCache := cache.NewFileCache(options.Dir)
+	if options.CacheKey != nil {
+		if rootCache, err = cache.NewEncryptedFileCache(options.Dir, options.CacheKey); err != nil {
+			return nil, err
+		}
+	}
+
 	options.DialOptions = c.SetupDialOptions(options)
 	if db, err := options.Tkns.GetDatabase(); err == nil && len(db) > 0 {
 		options.CurrentDatabase = db
 	}
 
-	c.WithOptions(options)
+	ic := c.WithOptions(options)
 
 	var clientConn *grpc.ClientConn
 	if clientConn, err = c.Connect(ctx); err != nil {
@@ -172,7 +227,7 @@ func NewImmuClient(options *Options) (c ImmuClient, err error) {
 	immudbRootProvider := rootservice.NewImmudbRootProvider(serviceClient)
 	immudbUUIDProvider := rootservice.NewImmudbUUIDProvider(serviceClient)
 
-	rootService, err := rootservice.NewRootService(cache.NewFileCache(options.Dir), l, immudbRootProvider, immudbUUIDProvider)
+	rootService, err := rootservice.NewRootService(newMetricsCache(rootCache, ic.metrics), l, immudbRootProvider, immudbUUIDProvider)
 	if err != nil {
 		return nil, logErr(l, "Unable to create root service: %s", err)
 	}
@@ -258,6 +313,10 @@ func (c *immuClient) SetupDialOptions(options *Options) *[]grpc.DialOption {
 	}
 
 	opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(options.MaxRecvMsgSize)))
+	opts = append(opts, tracingDialOptions(options)...)
+	opts = append(opts, compressionDialOptions(options)...)
+	opts = append(opts, checksumDialOptions(options)...)
+	opts = append(opts, circuitBreakerDialOptions(options)...)
 
 	return &opts
 }
@@ -330,6 +389,38 @@ func (c *immuClient) ListUsers(ctx context.Context) (*schema.UserList, error) {
 	return c.ServiceClient.ListUsers(ctx, new(empty.Empty))
 }
 
+// ListSessions returns when, from where and for which RPC the tokens in use were last used
+func (c *immuClient) ListSessions(ctx context.Context) (*schema.SessionList, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+	return c.ServiceClient.ListSessions(ctx, new(empty.Empty))
+}
+
+// GetUsage returns the operations run against database (all of them if empty) and the bytes written to it
+// from since to until, by default in the last day. Zero times select the default bounds.
+func (c *immuClient) GetUsage(ctx context.Context, database string, since time.Time, until time.Time) (*schema.UsageReport, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+	req := &schema.UsageRequest{Database: database}
+	if !since.IsZero() {
+		req.Since = since.Unix()
+	}
+	if !until.IsZero() {
+		req.Until = until.Unix()
+	}
+	return c.ServiceClient.GetUsage(ctx, req)
+}
+
+// GetAccessControlState returns a hash of the users of the server and of their permissions
+func (c *immuClient) GetAccessControlState(ctx context.Context) (*schema.AccessControlState, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+	return c.ServiceClient.GetAccessControlState(ctx, new(empty.Empty))
+}
+
 // CreateUser ...
 func (c *immuClient) CreateUser(ctx context.Context, user []byte, pass []byte, permission uint32, databasename string) error {
 	start := time.Now()
@@ -488,6 +579,12 @@ func (c *immuClient) CurrentRoot(ctx context.Context) (*schema.Root, error) {
 		return nil, err
 	}
 
+	if c.metrics != nil && root.GetSignature() != nil {
+		if ok, err := root.CheckSignature(); err != nil || !ok {
+			c.metrics.observeSignatureMismatch()
+		}
+	}
+
 	c.Logger.Debugf("Current root finished in %s", time.Since(start))
 
 	return root, err
@@ -527,6 +624,10 @@ func (c *immuClient) SafeGet(ctx context.Context, key []byte, opts ...grpc.CallO
 	}
 
 	verified := safeItem.Proof.Verify(h, *root)
+	c.metrics.observeVerification(verified)
+	if err = c.checkVerification("SafeGet", verified, safeItem.Item.GetIndex()); err != nil {
+		return nil, err
+	}
 	if verified {
 		// saving a fresh root
 		tocache := schema.NewRoot()
@@ -588,6 +689,10 @@ func (c *immuClient) RawSafeGet(ctx context.Context, key []byte, opts ...grpc.Ca
 	}
 
 	verified := safeItem.Proof.Verify(h, *root)
+	c.metrics.observeVerification(verified)
+	if err = c.checkVerification("RawSafeGet", verified, safeItem.Item.GetIndex()); err != nil {
+		return nil, err
+	}
 	if verified {
 		// saving a fresh root
 		tocache := schema.NewRoot()
@@ -610,32 +715,177 @@ func (c *immuClient) RawSafeGet(ctx context.Context, key []byte, opts ...grpc.Ca
 		nil
 }
 
-// Scan ...
-func (c *immuClient) Scan(ctx context.Context, options *schema.ScanOptions) (*schema.StructuredItemList, error) {
+// Scan returns a page of the keys matching options, resuming from the page token set in ctx by WithPageToken if any
+func (c *immuClient) Scan(ctx context.Context, options *schema.ScanOptions) (*StructuredItemPage, error) {
 	if !c.IsConnected() {
 		return nil, ErrNotConnected
 	}
 
-	list, err := c.ServiceClient.Scan(ctx, options)
+	position, cursor, err := pageFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	opts := *options
+	if cursor != nil {
+		opts.Cursor = cursor
+	}
 
-	return list.ToSItemList()
+	list, err := c.ServiceClient.Scan(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cursor is returned only if more items follow
+	more := options.Limit > 0 && len(list.Cursor) > 0
+	sl, err := list.ToSItemList()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StructuredItemPage{
+		StructuredItemList: sl,
+		PageInfo:           newPageInfo(position, len(sl.Items), more, list.Cursor),
+	}, nil
+}
+
+// ZScan returns a page of the sorted set matching options, resuming from the page token set in ctx by WithPageToken if any
+func (c *immuClient) ZScan(ctx context.Context, options *schema.ZScanOptions) (*ZStructuredItemPage, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	position, offset, err := pageFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := *options
+	opts.Limit = peekLimit(options.Limit)
+	if offset != nil {
+		opts.Offset = offset
+	}
+
+	list, err := c.ServiceClient.ZScan(ctx, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	more := options.Limit > 0 && uint64(len(list.Items)) > options.Limit
+	if more {
+		list.Items = list.Items[:options.Limit]
+	}
+	zl, err := list.ToZSItemList()
+	if err != nil {
+		return nil, err
+	}
+	if more {
+		offset = list.Items[len(list.Items)-1].CurrentOffset
+	}
+
+	return &ZStructuredItemPage{
+		ZStructuredItemList: zl,
+		PageInfo:            newPageInfo(position, len(zl.Items), more, offset),
+	}, nil
 }
 
-// ZScan ...
-func (c *immuClient) ZScan(ctx context.Context, options *schema.ZScanOptions) (*schema.ZStructuredItemList, error) {
+// SafeZScan is like ZScan, verifying that every entry of the page, and the item it references, is included in the
+// trusted root, and that the entry indexes the item with its score. The root is then updated, as SafeGet does.
+func (c *immuClient) SafeZScan(ctx context.Context, options *schema.ZScanOptions) (*VerifiedZItemPage, error) {
+	start := time.Now()
+
+	c.Lock()
+	defer c.Unlock()
+
 	if !c.IsConnected() {
 		return nil, ErrNotConnected
 	}
 
-	list, err := c.ServiceClient.ZScan(ctx, options)
+	position, offset, err := pageFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := *options
+	opts.Limit = peekLimit(options.Limit)
+	if offset != nil {
+		opts.Offset = offset
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.ServiceClient.SafeZScan(ctx, &schema.SafeZScanOptions{Zopts: &opts, RootIndex: &schema.Index{Index: root.GetIndex()}})
 	if err != nil {
 		return nil, err
 	}
 
-	return list.ToZSItemList()
+	fresh, unverified := verifySafeZItemList(options.Set, list, root)
+	verified := unverified == nil
+	if len(list.Items) > 0 {
+		c.metrics.observeVerification(verified)
+	}
+	if !verified {
+		if err = c.checkVerification("SafeZScan", false, unverified.GetIndex()); err != nil {
+			return nil, err
+		}
+	}
+	if fresh != nil {
+		// saving a fresh root
+		if err = c.Rootservice.SetRoot(fresh, c.Options.CurrentDatabase); err != nil {
+			return nil, err
+		}
+	}
+
+	more := options.Limit > 0 && uint64(len(list.Items)) > options.Limit
+	if more {
+		list.Items = list.Items[:options.Limit]
+		offset = list.Items[len(list.Items)-1].CurrentOffset
+	}
+	page := &VerifiedZItemPage{Items: make([]*VerifiedZItem, 0, len(list.Items))}
+	for _, zitem := range list.Items {
+		sitem, err := zitem.Item.ToSItem()
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, &VerifiedZItem{
+			VerifiedItem: VerifiedItem{
+				Key:      sitem.GetKey(),
+				Value:    sitem.Value.Payload,
+				Index:    sitem.GetIndex(),
+				Time:     sitem.Value.Timestamp,
+				Deleted:  sitem.Deleted,
+				Verified: verified,
+			},
+			Score:      zitem.Score,
+			EntryIndex: zitem.Index,
+		})
+	}
+	page.PageInfo = newPageInfo(position, len(page.Items), more, offset)
+
+	c.Logger.Debugf("SafeZScan finished in %s", time.Since(start))
+
+	return page, nil
+}
+
+// verifySafeZItemList verifies that the entries of the sorted set set in list, and the items they reference, are
+// included in the root their multiproof leads to, itself consistent with root. It returns that root, if verified,
+// or the item of the first entry that can't be verified.
+func verifySafeZItemList(set []byte, list *schema.SafeZItemList, root *schema.Root) (*schema.Root, *schema.Item) {
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	mp := list.MultiProof
+	verified := mp != nil && mp.Verify(*root)
+	for _, zitem := range list.Items {
+		if !verified {
+			return nil, zitem.Item
+		}
+		leaf, err := store.SortedSetEntryHash(set, zitem)
+		if err != nil || !mp.Includes(zitem.Index, leaf) || !mp.Includes(zitem.Item.Index, zitem.Item.Hash()) {
+			return nil, zitem.Item
+		}
+	}
+	return &schema.Root{Payload: &schema.RootIndex{Index: mp.At, Root: mp.Root}}, nil
 }
 
 // IScan ...
@@ -668,6 +918,64 @@ func (c *immuClient) CountAll(ctx context.Context) (*schema.ItemsCount, error) {
 	return c.ServiceClient.CountAll(ctx, new(empty.Empty))
 }
 
+// Stats returns the number of live keys having the provided prefix and the number of entries of all their versions,
+// along with their size in bytes, computed by the server
+func (c *immuClient) Stats(ctx context.Context, prefix []byte) (*schema.PrefixStats, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+	return c.ServiceClient.Stats(ctx, &schema.KeyPrefix{Prefix: prefix})
+}
+
+// VerifiedCount counts the keys having the provided prefix, references excluded, and checks a random sample
+// of sampleSize of them: the current entry of every sampled key is fetched again and verified against the trusted root.
+// The count is reported as verified only if every sampled entry is.
+// A prefix without keys can't be backed by any inclusion proof and is never verified.
+func (c *immuClient) VerifiedCount(ctx context.Context, prefix []byte, sampleSize uint64) (*VerifiedCount, error) {
+	start := time.Now()
+
+	sample, err := c.SampleKeys(ctx, sampleSize, prefix, rand.Int63())
+	if status.Code(err) == codes.NotFound {
+		return &VerifiedCount{Prefix: prefix}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vc := &VerifiedCount{Prefix: prefix, Count: sample.GetPopulation(), Verified: true}
+	for _, e := range sample.GetEntries() {
+		vi, err := c.RawSafeGet(ctx, e.GetItem().GetKey())
+		if err != nil {
+			return nil, err
+		}
+		vc.Sampled++
+		// a newer entry may have been written since the sample was drawn, an older one can't be current
+		vc.Verified = vc.Verified && vi.Verified && vi.Index >= e.GetItem().GetIndex()
+	}
+
+	c.Logger.Debugf("VerifiedCount finished in %s", time.Since(start))
+
+	return vc, nil
+}
+
+// VerifiedExists tells whether key exists, proving it with the inclusion of its current entry against the trusted root.
+// The absence of a key can't be proven: a missing key is reported as not existing and not verified.
+func (c *immuClient) VerifiedExists(ctx context.Context, key []byte) (*VerifiedExistence, error) {
+	vi, err := c.RawSafeGet(ctx, key)
+	if status.Code(err) == codes.NotFound {
+		return &VerifiedExistence{Key: key}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &VerifiedExistence{
+		Key:      key,
+		Exists:   true,
+		Index:    vi.Index,
+		Verified: vi.Verified,
+	}, nil
+}
+
 // Set ...
 func (c *immuClient) Set(ctx context.Context, key []byte, value []byte) (*schema.Index, error) {
 	start := time.Now()
@@ -758,12 +1066,16 @@ func (c *immuClient) SafeSet(ctx context.Context, key []byte, value []byte) (*Ve
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkVerification("SafeSet", verified, result.Index); err != nil {
+		return nil, err
+	}
 
 	c.Logger.Debugf("safeset finished in %s", time.Since(start))
 
 	return &VerifiedIndex{
 			Index:    result.Index,
 			Verified: verified,
+			Sequence: result.Sequence,
 		},
 		nil
 }
@@ -822,12 +1134,16 @@ func (c *immuClient) RawSafeSet(ctx context.Context, key []byte, value []byte) (
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkVerification("RawSafeSet", verified, result.Index); err != nil {
+		return nil, err
+	}
 
 	c.Logger.Debugf("safeset finished in %s", time.Since(start))
 
 	return &VerifiedIndex{
 			Index:    result.Index,
 			Verified: verified,
+			Sequence: result.Sequence,
 		},
 		nil
 }
@@ -859,6 +1175,98 @@ func (c *immuClient) ExecAllOps(ctx context.Context, op *schema.Ops) (*schema.In
 	return result, err
 }
 
+// ExecAllTx is like ExecAllOps, with the operations committed as a single transaction. The returned index is the one of
+// the commit entry
+func (c *immuClient) ExecAllTx(ctx context.Context, op *schema.Ops) (*schema.Index, error) {
+	op, err := c.NewSOps(op)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.ServiceClient.ExecAllTx(ctx, op)
+	return result, err
+}
+
+// SafeExecAllTx is like ExecAllTx, but the whole transaction is verified with the proof of its commit entry
+func (c *immuClient) SafeExecAllTx(ctx context.Context, op *schema.Ops) (*VerifiedIndex, error) {
+	start := time.Now()
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err = c.NewSOps(op)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.ServiceClient.SafeExecAllTx(ctx, &schema.SafeExecAllTxOptions{
+		Ops: op,
+		RootIndex: &schema.Index{
+			Index: root.GetIndex(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = verifyTx(op, result); err != nil {
+		return nil, err
+	}
+
+	verified, err := c.verifyAndSetRoot(result.Proof, root, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.checkVerification("SafeExecAllTx", verified, result.Proof.Index); err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("safeexecalltx finished in %s", time.Since(start))
+
+	return &VerifiedIndex{
+			Index:    result.Proof.Index,
+			Verified: verified,
+			Sequence: result.Proof.Sequence,
+		},
+		nil
+}
+
+// verifyTx ensures that the entries of the transaction are the ones of the given operations and that they are the ones
+// digested by the commit entry, so that result.Proof.Leaf can be trusted for all of them
+func verifyTx(op *schema.Ops, result *schema.TxProof) error {
+	if result.GetCommit() == nil || result.GetProof() == nil {
+		return errors.New("proof does not match the given transaction")
+	}
+	first, last, ok := store.TxRange(result.Commit.Key)
+	if !ok || len(op.Operations) != len(result.Entries) || last-first+1 != uint64(len(result.Entries)) ||
+		result.Commit.Index != last+1 {
+		return errors.New("proof does not match the given transaction")
+	}
+	leaves := make([][sha256.Size]byte, len(result.Entries))
+	for i, entry := range result.Entries {
+		if entry.Index != first+uint64(i) {
+			return errors.New("proof does not match the given transaction")
+		}
+		if kv, ok := op.Operations[i].Operation.(*schema.Op_KVs); ok &&
+			(!bytes.Equal(kv.KVs.Key, entry.Key) || !bytes.Equal(kv.KVs.Value, entry.Value)) {
+			return errors.New("proof does not match the given transaction")
+		}
+		copy(leaves[i][:], entry.Hash())
+	}
+	digest := api.TxDigest(leaves)
+	if !bytes.Equal(digest[:], result.Commit.Value) || !bytes.Equal(result.Commit.Hash(), result.Proof.Leaf) {
+		return errors.New("proof does not match the given transaction")
+	}
+	return nil
+}
+
 // SetBatch ...
 func (c *immuClient) SetBatch(ctx context.Context, request *BatchRequest) (*schema.Index, error) {
 	start := time.Now()
@@ -907,6 +1315,118 @@ func (c *immuClient) GetBatch(ctx context.Context, keys [][]byte) (*schema.Struc
 	return list.ToSItemList()
 }
 
+// SafeGetBatch fetches the current entries of many keys in a single round trip, verified against the trusted root, as
+// by SafeGet, by a single multiproof. Missing and deleted keys are left out, as by GetBatch.
+func (c *immuClient) SafeGetBatch(ctx context.Context, keys [][]byte) (*VerifiedItemList, error) {
+	start := time.Now()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &schema.SafeGetBatchOptions{RootIndex: &schema.Index{Index: root.GetIndex()}, MultiProof: true}
+	for _, key := range keys {
+		opts.Keys = append(opts.Keys, &schema.Key{Key: key})
+	}
+
+	list, err := c.ServiceClient.SafeGetBatch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh, unverified, err := verifySafeItemList(list, root)
+	if err != nil {
+		return nil, err
+	}
+	verified := unverified == nil
+	if len(list.Items) > 0 {
+		c.metrics.observeVerification(verified)
+	}
+	if !verified {
+		if err = c.checkVerification("SafeGetBatch", false, unverified.GetIndex()); err != nil {
+			return nil, err
+		}
+	}
+	if fresh != nil {
+		// saving a fresh root
+		if err = c.Rootservice.SetRoot(fresh, c.Options.CurrentDatabase); err != nil {
+			return nil, err
+		}
+	}
+
+	vlist, err := toVerifiedItemList(list, verified)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("SafeGetBatch finished in %s", time.Since(start))
+
+	return vlist, nil
+}
+
+// verifySafeItemList verifies the items of list against root, by their multiproof if any, or by the proof of each item,
+// all of them against the same root. It returns the root the items are proved against, if verified, or the first item
+// that can't be verified.
+func verifySafeItemList(list *schema.SafeItemList, root *schema.Root) (*schema.Root, *schema.Item, error) {
+	if len(list.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	if mp := list.MultiProof; mp != nil {
+		verified := mp.Verify(*root)
+		for _, safeItem := range list.Items {
+			h, err := safeItem.Hash()
+			if err != nil {
+				return nil, nil, err
+			}
+			if !verified || !mp.Includes(safeItem.Item.GetIndex(), h) {
+				return nil, safeItem.Item, nil
+			}
+		}
+		return &schema.Root{Payload: &schema.RootIndex{Index: mp.At, Root: mp.Root}}, nil, nil
+	}
+
+	var fresh *schema.Proof
+	for _, safeItem := range list.Items {
+		h, err := safeItem.Hash()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !safeItem.Proof.Verify(h, *root) ||
+			(fresh != nil && (safeItem.Proof.At != fresh.At || !bytes.Equal(safeItem.Proof.Root, fresh.Root))) {
+			return nil, safeItem.Item, nil
+		}
+		fresh = safeItem.Proof
+	}
+	return fresh.NewRoot(), nil, nil
+}
+
+func toVerifiedItemList(list *schema.SafeItemList, verified bool) (*VerifiedItemList, error) {
+	vlist := &VerifiedItemList{Items: make([]*VerifiedItem, 0, len(list.Items))}
+	for _, safeItem := range list.Items {
+		sitem, err := safeItem.ToSafeSItem()
+		if err != nil {
+			return nil, err
+		}
+		vlist.Items = append(vlist.Items, &VerifiedItem{
+			Key:      sitem.Item.GetKey(),
+			Value:    sitem.Item.Value.Payload,
+			Index:    sitem.Item.GetIndex(),
+			Time:     sitem.Item.Value.Timestamp,
+			Verified: verified,
+		})
+	}
+	return vlist, nil
+}
+
 // Inclusion ...
 func (c *immuClient) Inclusion(ctx context.Context, index uint64) (*schema.InclusionProof, error) {
 	start := time.Now()
@@ -941,16 +1461,35 @@ func (c *immuClient) Consistency(ctx context.Context, index uint64) (*schema.Con
 	return result, err
 }
 
-// ByIndex returns a structured value at index
-func (c *immuClient) ByIndex(ctx context.Context, index uint64) (*schema.StructuredItem, error) {
+// GetBySequence returns the entries written by the write having the given sequence number.
+// Sequence numbers are assigned only by servers running in sequencer mode.
+func (c *immuClient) GetBySequence(ctx context.Context, sequence uint64) (*schema.SequencedWrite, error) {
 	start := time.Now()
 
 	if !c.IsConnected() {
 		return nil, ErrNotConnected
 	}
 
-	item, err := c.ServiceClient.ByIndex(ctx, &schema.Index{
-		Index: index,
+	write, err := c.ServiceClient.GetBySequence(ctx, &schema.Sequence{Sequence: sequence})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("GetBySequence finished in %s", time.Since(start))
+
+	return write, nil
+}
+
+// ByIndex returns a structured value at index
+func (c *immuClient) ByIndex(ctx context.Context, index uint64) (*schema.StructuredItem, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	item, err := c.ServiceClient.ByIndex(ctx, &schema.Index{
+		Index: index,
 	})
 	if err != nil {
 		return nil, err
@@ -966,6 +1505,66 @@ func (c *immuClient) ByIndex(ctx context.Context, index uint64) (*schema.Structu
 	return result, err
 }
 
+// VerifyValueHash asks the server whether valueHash, the SHA-256 of a value written with Set or SafeSet,
+// matches the entry at index, or the current entry of key when index is nil, without downloading the value.
+// The inclusion and consistency proof of the checked entry is verified against the trusted root.
+func (c *immuClient) VerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*VerifiedValueHash, error) {
+	return c.verifyValueHash(ctx, "VerifyValueHash", &schema.ValueHashOptions{Key: key, ValueHash: valueHash, Index: index, Structured: true})
+}
+
+// RawVerifyValueHash is like VerifyValueHash for values written with RawSafeSet, hashing the stored bytes.
+func (c *immuClient) RawVerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*VerifiedValueHash, error) {
+	return c.verifyValueHash(ctx, "RawVerifyValueHash", &schema.ValueHashOptions{Key: key, ValueHash: valueHash, Index: index})
+}
+
+func (c *immuClient) verifyValueHash(ctx context.Context, method string, options *schema.ValueHashOptions) (*VerifiedValueHash, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+	options.RootIndex = &schema.Index{Index: root.GetIndex()}
+
+	v, err := c.ServiceClient.VerifyValueHash(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// the leaf cannot be recomputed without the value: the proof binds the returned leaf to the trusted root
+	verified := v.Proof.Verify(v.Proof.GetLeaf(), *root)
+	c.metrics.observeVerification(verified)
+	if err = c.checkVerification(method, verified, v.Proof.GetIndex()); err != nil {
+		return nil, err
+	}
+	if verified {
+		// saving a fresh root
+		tocache := schema.NewRoot()
+		tocache.SetIndex(v.Proof.At)
+		tocache.SetRoot(v.Proof.Root)
+		err = c.Rootservice.SetRoot(tocache, c.Options.CurrentDatabase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.Logger.Debugf("%s finished in %s", method, time.Since(start))
+
+	return &VerifiedValueHash{
+		Key:      options.Key,
+		Index:    v.Proof.GetIndex(),
+		Matches:  v.Matches,
+		Verified: verified,
+	}, nil
+}
+
 // RawBySafeIndex returns a verified index at specified index
 func (c *immuClient) RawBySafeIndex(ctx context.Context, index uint64) (*VerifiedItem, error) {
 	c.Lock()
@@ -999,6 +1598,10 @@ func (c *immuClient) RawBySafeIndex(ctx context.Context, index uint64) (*Verifie
 	}
 
 	verified := safeItem.Proof.Verify(h, *root)
+	c.metrics.observeVerification(verified)
+	if err = c.checkVerification("RawBySafeIndex", verified, safeItem.Item.GetIndex()); err != nil {
+		return nil, err
+	}
 	if verified {
 		// saving a fresh root
 		tocache := schema.NewRoot()
@@ -1016,32 +1619,59 @@ func (c *immuClient) RawBySafeIndex(ctx context.Context, index uint64) (*Verifie
 			Key:      safeItem.Item.GetKey(),
 			Value:    safeItem.Item.Value,
 			Index:    safeItem.Item.GetIndex(),
+			Deleted:  safeItem.Item.GetDeleted(),
 			Verified: verified,
 		},
 		nil
 }
 
-// History ...
-func (c *immuClient) History(ctx context.Context, options *schema.HistoryOptions) (sl *schema.StructuredItemList, err error) {
+// History returns a page of the history of a key, resuming from the page token set in ctx by WithPageToken if any.
+// The history can be restricted to a range of indexes and to a window of commit times, the restrictions applying to
+// every page.
+func (c *immuClient) History(ctx context.Context, options *schema.HistoryOptions) (*StructuredItemPage, error) {
 	start := time.Now()
 
 	if !c.IsConnected() {
 		return nil, ErrNotConnected
 	}
 
-	list, err := c.ServiceClient.History(ctx, options)
+	position, offset, err := pageFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	opts := *options
+	opts.Limit = peekLimit(options.Limit)
+	if offset != nil {
+		if len(offset) != 8 {
+			return nil, ErrInvalidPageToken
+		}
+		opts.Offset = binary.BigEndian.Uint64(offset)
+	}
 
-	sl, err = list.ToSItemList()
+	list, err := c.ServiceClient.History(ctx, &opts)
 	if err != nil {
 		return nil, err
 	}
 
+	more := options.Limit > 0 && uint64(len(list.Items)) > options.Limit
+	if more {
+		list.Items = list.Items[:options.Limit]
+	}
+	sl, err := list.ToSItemList()
+	if err != nil {
+		return nil, err
+	}
+	if more {
+		offset = make([]byte, 8)
+		binary.BigEndian.PutUint64(offset, list.Items[len(list.Items)-1].Index)
+	}
+
 	c.Logger.Debugf("history finished in %s", time.Since(start))
 
-	return sl, err
+	return &StructuredItemPage{
+		StructuredItemList: sl,
+		PageInfo:           newPageInfo(position, len(sl.Items), more, offset),
+	}, nil
 }
 
 // Reference ...
@@ -1063,6 +1693,33 @@ func (c *immuClient) Reference(ctx context.Context, reference []byte, key []byte
 	return result, err
 }
 
+// CompareAndReference sets the provided key-value and binds the reference to it, only if the reference
+// currently points to the expected index. A nil expected index means that the reference must not exist yet.
+func (c *immuClient) CompareAndReference(ctx context.Context, reference []byte, key []byte, value []byte, expectedIndex *schema.Index) (*schema.Index, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	skv := c.NewSKV(key, value)
+
+	kv, err := skv.ToKV()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.ServiceClient.CompareAndReference(ctx, &schema.CompareAndReferenceOptions{
+		Kv:            kv,
+		Reference:     reference,
+		ExpectedIndex: expectedIndex,
+	})
+
+	c.Logger.Debugf("compare and reference finished in %s", time.Since(start))
+
+	return result, err
+}
+
 // Reference ...
 func (c *immuClient) GetReference(ctx context.Context, key *schema.Key) (*schema.StructuredItem, error) {
 	if !c.IsConnected() {
@@ -1075,6 +1732,86 @@ func (c *immuClient) GetReference(ctx context.Context, key *schema.Key) (*schema
 	return item.ToSItem()
 }
 
+// GetReferences returns the references currently resolving to key, along with the index of the entry of each of
+// them. If index is provided only the references resolving to the entry at that index are returned.
+func (c *immuClient) GetReferences(ctx context.Context, key []byte, index *schema.Index) (*schema.ReferenceList, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	list, err := c.ServiceClient.GetReferences(ctx, &schema.ReferencesOptions{Key: key, Index: index})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("GetReferences finished in %s", time.Since(start))
+
+	return list, nil
+}
+
+// GetReferenceChain resolves reference and returns every hop of the resolution: the latest entry of the reference,
+// the key it points to and the entry of the key it resolved to, either the pinned revision or the latest one.
+// Both entries are proven against the trusted root and checked to be linked to each other.
+func (c *immuClient) GetReferenceChain(ctx context.Context, reference []byte) (*ReferenceChain, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	// fails if reference is not a reference
+	resolved, err := c.ServiceClient.GetReference(ctx, &schema.Key{Key: reference})
+	if err != nil {
+		return nil, err
+	}
+	list, err := c.ServiceClient.History(ctx, &schema.HistoryOptions{Key: reference, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 || list.Items[0].Deleted {
+		return nil, ErrReferenceChainMismatch
+	}
+
+	ref, err := c.RawBySafeIndex(ctx, list.Items[0].Index)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(ref.Key, reference) || ref.Deleted || len(ref.Value) < 9 {
+		return nil, ErrReferenceChainMismatch
+	}
+	key, flag, index := store.UnwrapZIndexReference(ref.Value)
+	pinned := flag == byte(1)
+	if !bytes.Equal(key, resolved.Key) || (pinned && index != resolved.Index) {
+		return nil, ErrReferenceChainMismatch
+	}
+
+	target, err := c.RawBySafeIndex(ctx, resolved.Index)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(target.Key, key) || target.Deleted || !bytes.Equal(target.Value, resolved.Value) {
+		return nil, ErrReferenceChainMismatch
+	}
+	sitem, err := (&schema.Item{Key: target.Key, Value: target.Value, Index: target.Index}).ToSItem()
+	if err != nil {
+		return nil, err
+	}
+	target.Value = sitem.Value.Payload
+	target.Time = sitem.Value.Timestamp
+
+	c.Logger.Debugf("GetReferenceChain finished in %s", time.Since(start))
+
+	return &ReferenceChain{
+		Reference: ref,
+		Key:       key,
+		Pinned:    pinned,
+		Target:    target,
+		Verified:  ref.Verified && target.Verified,
+	}, nil
+}
+
 // SafeReference ...
 func (c *immuClient) SafeReference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*VerifiedIndex, error) {
 	start := time.Now()
@@ -1131,12 +1868,16 @@ func (c *immuClient) SafeReference(ctx context.Context, reference []byte, key []
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkVerification("SafeReference", verified, result.Index); err != nil {
+		return nil, err
+	}
 
 	c.Logger.Debugf("safereference finished in %s", time.Since(start))
 
 	return &VerifiedIndex{
 			Index:    result.Index,
 			Verified: verified,
+			Sequence: result.Sequence,
 		},
 		nil
 }
@@ -1219,12 +1960,16 @@ func (c *immuClient) SafeZAdd(ctx context.Context, set []byte, score float64, ke
 	if err != nil {
 		return nil, err
 	}
+	if err = c.checkVerification("SafeZAdd", verified, result.Index); err != nil {
+		return nil, err
+	}
 
 	c.Logger.Debugf("safezadd finished in %s", time.Since(start))
 
 	return &VerifiedIndex{
 			Index:    result.Index,
 			Verified: verified,
+			Sequence: result.Sequence,
 		},
 		nil
 }
@@ -1277,6 +2022,394 @@ func (c *immuClient) Dump(ctx context.Context, writer io.WriteSeeker) (int64, er
 	return counter, nil
 }
 
+// DumpKeyHistory returns the whole history of the provided key along with the inclusion proofs of all its revisions.
+// The returned dump can be verified offline by calling its Verify method.
+func (c *immuClient) DumpKeyHistory(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	dump, err := c.ServiceClient.DumpKeyHistory(ctx, &schema.Key{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("DumpKeyHistory finished in %s", time.Since(start))
+
+	return dump, nil
+}
+
+// HistoryTimeline returns the revisions of key, oldest first, telling for each one whether it is covered by
+// the locally cached root. With verify, the inclusion of every revision is proven against the trusted root.
+func (c *immuClient) HistoryTimeline(ctx context.Context, key []byte, verify bool) (*KeyTimeline, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.ServiceClient.History(ctx, &schema.HistoryOptions{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &KeyTimeline{Key: key, LocalRoot: root, Entries: make([]*TimelineEntry, 0, len(list.Items))}
+	for _, item := range list.Items {
+		sitem, err := item.ToSItem()
+		if err != nil {
+			return nil, err
+		}
+		timeline.Entries = append(timeline.Entries, &TimelineEntry{
+			Index:     item.Index,
+			Time:      sitem.Value.Timestamp,
+			ValueSize: len(sitem.Value.Payload),
+			Deleted:   item.Deleted,
+			Hash:      item.Hash(),
+			Covered:   item.Index <= root.GetIndex(),
+		})
+	}
+	sort.Slice(timeline.Entries, func(i, j int) bool { return timeline.Entries[i].Index < timeline.Entries[j].Index })
+
+	if verify {
+		for _, entry := range timeline.Entries {
+			vi, err := c.RawBySafeIndex(ctx, entry.Index)
+			if err != nil {
+				return nil, err
+			}
+			entry.Verified = vi.Verified && bytes.Equal(vi.Key, key) &&
+				bytes.Equal((&schema.Item{Key: vi.Key, Value: vi.Value, Index: vi.Index, Deleted: vi.Deleted}).Hash(), entry.Hash)
+		}
+	}
+
+	c.Logger.Debugf("HistoryTimeline finished in %s", time.Since(start))
+
+	return timeline, nil
+}
+
+// GetAt returns the entry key had at the provided index of the tree, verified against the root the tree had at that
+// index, which is in turn proven consistent with the trusted root.
+func (c *immuClient) GetAt(ctx context.Context, key []byte, index uint64) (*VerifiedItem, error) {
+	at := func(historical *schema.HistoricalItem) bool {
+		return historical.GetProof().GetAt() == index && historical.GetItem().GetIndex() <= index
+	}
+	return c.getHistorical(ctx, "GetAt", key, at, func(rootIndex *schema.Index) (*schema.HistoricalItem, error) {
+		return c.ServiceClient.GetAt(ctx, &schema.GetAtOptions{Key: key, Index: index, RootIndex: rootIndex})
+	})
+}
+
+// GetAsOf is like GetAt, at the last index committed at or before t.
+func (c *immuClient) GetAsOf(ctx context.Context, key []byte, t time.Time) (*VerifiedItem, error) {
+	asOf := func(historical *schema.HistoricalItem) bool {
+		return historical.GetItem().GetIndex() <= historical.GetProof().GetAt()
+	}
+	return c.getHistorical(ctx, "GetAsOf", key, asOf, func(rootIndex *schema.Index) (*schema.HistoricalItem, error) {
+		return c.ServiceClient.GetAsOf(ctx, &schema.GetAsOfOptions{Key: key, Timestamp: t.UnixNano(), RootIndex: rootIndex})
+	})
+}
+
+// getHistorical verifies the historical item returned by get against the trusted root, caching the historical root
+// if it comes after the trusted one. The item is only verified if it is an entry of key and its indexes are those
+// the read asked for, as told by at.
+func (c *immuClient) getHistorical(ctx context.Context, method string, key []byte, at func(*schema.HistoricalItem) bool, get func(*schema.Index) (*schema.HistoricalItem, error)) (*VerifiedItem, error) {
+	start := time.Now()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	historical, err := get(&schema.Index{Index: root.GetIndex()})
+	if err != nil {
+		return nil, err
+	}
+
+	verified := historical.Verify(*root) && bytes.Equal(historical.GetItem().GetKey(), key) && at(historical)
+	c.metrics.observeVerification(verified)
+	if err = c.checkVerification(method, verified, historical.GetItem().GetIndex()); err != nil {
+		return nil, err
+	}
+	if verified && historical.Proof.At > root.GetIndex() {
+		// saving a fresh root
+		tocache := schema.NewRoot()
+		tocache.SetIndex(historical.Proof.At)
+		tocache.SetRoot(historical.Proof.Root)
+		if err = c.Rootservice.SetRoot(tocache, c.Options.CurrentDatabase); err != nil {
+			return nil, err
+		}
+	}
+
+	c.Logger.Debugf("%s finished in %s", method, time.Since(start))
+	sitem, err := historical.Item.ToSItem()
+	if err != nil {
+		return nil, err
+	}
+
+	return &VerifiedItem{
+		Key:      sitem.Key,
+		Value:    sitem.Value.Payload,
+		Index:    sitem.Index,
+		Time:     sitem.Value.Timestamp,
+		Verified: verified,
+	}, nil
+}
+
+// SampleKeys returns a reproducible pseudo-random sample of size keys having the provided prefix, drawn with seed,
+// along with the inclusion proofs of their current entries. The sample is verified before being returned.
+func (c *immuClient) SampleKeys(ctx context.Context, size uint64, prefix []byte, seed int64) (*schema.KeySample, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	sample, err := c.ServiceClient.SampleKeys(ctx, &schema.SampleOptions{Size: size, Prefix: prefix, Seed: seed})
+	if err != nil {
+		return nil, err
+	}
+	if sample.GetSeed() != seed || !bytes.Equal(sample.GetPrefix(), prefix) {
+		return nil, schema.ErrCorruptedKeySample
+	}
+	if err = sample.Verify(); err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("SampleKeys finished in %s", time.Since(start))
+
+	return sample, nil
+}
+
+// Delete marks key as deleted in the current database. The key isn't returned by Get and Scan anymore,
+// while its history keeps the verifiable tombstone written at the returned index.
+func (c *immuClient) Delete(ctx context.Context, key []byte) (*VerifiedIndex, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	index, err := c.ServiceClient.Delete(ctx, &schema.Key{Key: key})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := c.RawBySafeIndex(ctx, index.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("Delete finished in %s", time.Since(start))
+
+	return &VerifiedIndex{
+		Index:    index.Index,
+		Verified: item.Verified && item.Deleted && bytes.Equal(item.Key, key),
+		Sequence: index.Sequence,
+	}, nil
+}
+
+// Unreference retires reference in the current database, the referenced key is left as it is. The reference can't be
+// resolved anymore, while its history keeps the verifiable tombstone written at the returned index.
+// To re-point a reference, bind it to another key with Reference instead.
+func (c *immuClient) Unreference(ctx context.Context, reference []byte) (*VerifiedIndex, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	index, err := c.ServiceClient.Unreference(ctx, &schema.Key{Key: reference})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := c.RawBySafeIndex(ctx, index.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("Unreference finished in %s", time.Since(start))
+
+	return &VerifiedIndex{
+		Index:    index.Index,
+		Verified: item.Verified && item.Deleted && bytes.Equal(item.Key, reference),
+		Sequence: index.Sequence,
+	}, nil
+}
+
+// FreezePrefix seals the provided key prefix of the current database, so that no further writes are allowed under it.
+// The entry recording the freeze is fetched back and verified against the current root.
+func (c *immuClient) FreezePrefix(ctx context.Context, prefix []byte) (*VerifiedIndex, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	index, err := c.ServiceClient.FreezePrefix(ctx, &schema.KeyPrefix{Prefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := c.RawBySafeIndex(ctx, index.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("FreezePrefix finished in %s", time.Since(start))
+
+	return &VerifiedIndex{
+		Index:    index.Index,
+		Verified: item.Verified && bytes.Equal(item.Value, prefix),
+		Sequence: index.Sequence,
+	}, nil
+}
+
+// AnalyzeStorage returns a report on how efficiently the current database is stored, along with recommended maintenance actions
+func (c *immuClient) AnalyzeStorage(ctx context.Context) (*schema.StorageReport, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	report, err := c.ServiceClient.AnalyzeStorage(ctx, new(empty.Empty))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("AnalyzeStorage finished in %s", time.Since(start))
+
+	return report, nil
+}
+
+// Backup takes a consistent snapshot of the current database without stopping writes. The snapshot is written on the
+// server, in its backup directory, and the returned root is the one the tree had when it was taken.
+func (c *immuClient) Backup(ctx context.Context) (*schema.BackupInfo, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	info, err := c.ServiceClient.Backup(ctx, new(empty.Empty))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("Backup finished in %s", time.Since(start))
+
+	return info, nil
+}
+
+// RestoreBackup rebuilds the new database databaseName from a snapshot written by Backup, file being its path on
+// the server. The database is made available only once the root recomputed from the snapshot matches the recorded one.
+func (c *immuClient) RestoreBackup(ctx context.Context, file string, databaseName string) (*schema.BackupInfo, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	info, err := c.ServiceClient.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: file, DatabaseName: databaseName})
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("RestoreBackup finished in %s", time.Since(start))
+
+	return info, nil
+}
+
+// Logs streams the server log entries matching the request to handler.
+// If the request asks to follow the log, it returns only when ctx is done, the stream breaks or handler fails.
+func (c *immuClient) Logs(ctx context.Context, req *schema.LogRequest, handler func(*schema.LogEntry) error) error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	stream, err := c.ServiceClient.Logs(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err = handler(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// Subscribe streams to handler the changes of the key, or of the keys starting with the prefix, selected by req, as
+// they are committed. It returns only when ctx is done, the stream breaks or handler fails.
+// If req asks for proofs, the inclusion of every change is verified, against the root the proof leads to as the
+// changes don't update the local root, and the changes are flagged as verified.
+func (c *immuClient) Subscribe(ctx context.Context, req *schema.SubscribeRequest, handler func(*VerifiedItem) error) error {
+	if !c.IsConnected() {
+		return ErrNotConnected
+	}
+
+	stream, err := c.ServiceClient.Subscribe(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		change, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		item := change.GetItem()
+		verified := false
+		if req.Proof {
+			verified = change.Proof.Verify(item.GetIndex(), item.Hash())
+			c.metrics.observeVerification(verified)
+			if err = c.checkVerification("Subscribe", verified, item.GetIndex()); err != nil {
+				return err
+			}
+		}
+		vi := &VerifiedItem{Key: item.GetKey(), Index: item.GetIndex(), Deleted: item.GetDeleted(), Verified: verified}
+		if !item.GetDeleted() {
+			sitem, err := item.ToSItem()
+			if err != nil {
+				return err
+			}
+			vi.Value, vi.Time = sitem.Value.Payload, sitem.Value.Timestamp
+		}
+		if err = handler(vi); err != nil {
+			return err
+		}
+	}
+}
+
 // todo(joe-dz): Enable restore when the feature is required again.
 // Also, make sure that the generated files are updated
 // Restore to be used from Immu CLI
@@ -1430,6 +2563,7 @@ func writeSeek(w io.WriteSeeker, msg []byte, offset int64) (int64, error) {
 
 func (c *immuClient) verifyAndSetRoot(result *schema.Proof, root *schema.Root, ctx context.Context) (bool, error) {
 	verified := result.Verify(result.Leaf, *root)
+	c.metrics.observeVerification(verified)
 	var err error
 
 	if verified {
@@ -19,6 +19,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"log"
@@ -47,6 +48,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -477,6 +480,12 @@ func TestImmuClientDisconnect(t *testing.T) {
 	_, err = client.ZScan(context.TODO(), &schema.ZScanOptions{Set: []byte("key")})
 	require.Error(t, ErrNotConnected, err)
 
+	_, err = client.SafeZScan(context.TODO(), &schema.ZScanOptions{Set: []byte("key")})
+	require.Error(t, ErrNotConnected, err)
+
+	err = client.Subscribe(context.TODO(), &schema.SubscribeRequest{}, nil)
+	require.Error(t, ErrNotConnected, err)
+
 	_, err = client.IScan(context.TODO(), 1, 1)
 	require.Error(t, ErrNotConnected, err)
 
@@ -714,12 +723,43 @@ func TestImmuClient_History(t *testing.T) {
 		Key: []byte(`key1`),
 	})
 
-	assert.IsType(t, &schema.StructuredItemList{}, sil)
+	assert.IsType(t, &StructuredItemPage{}, sil)
 	assert.Nil(t, err)
 	assert.Len(t, sil.Items, 2)
 	client.Disconnect()
 }
 
+func TestImmuClient_HistoryRanges(t *testing.T) {
+	setup()
+	var indexes []uint64
+	for _, value := range []string{`v1`, `v2`, `v3`, `v4`} {
+		index, err := client.Set(context.TODO(), []byte(`ranged`), []byte(value))
+		require.NoError(t, err)
+		indexes = append(indexes, index.Index)
+	}
+
+	ctx := context.TODO()
+	options := &schema.HistoryOptions{Key: []byte(`ranged`), FromIndex: indexes[1], ToIndex: indexes[3], Limit: 2, Reverse: true}
+	page, err := client.History(ctx, options)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, []byte(`v2`), page.Items[0].Value.Payload)
+	assert.True(t, page.More)
+	page, err = client.History(WithPageToken(ctx, page.NextPageToken), options)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, []byte(`v4`), page.Items[0].Value.Payload)
+	assert.False(t, page.More)
+
+	page, err = client.History(ctx, &schema.HistoryOptions{Key: []byte(`ranged`), Until: time.Now().Add(-time.Hour).Unix()})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 0)
+
+	_, err = client.History(ctx, &schema.HistoryOptions{Key: []byte(`ranged`), FromIndex: indexes[3], ToIndex: indexes[1]})
+	require.Error(t, err)
+	client.Disconnect()
+}
+
 func TestImmuClient_SetAll(t *testing.T) {
 	setup()
 
@@ -762,6 +802,149 @@ func TestImmuClient_GetBatch(t *testing.T) {
 	client.Disconnect()
 }
 
+func TestImmuClient_SafeGetBatch(t *testing.T) {
+	setup()
+	first, err := client.Set(context.TODO(), []byte(`batch1`), []byte(`val1`))
+	require.NoError(t, err)
+	_, err = client.SafeSet(context.TODO(), []byte(`batch2`), []byte(`val2`))
+	require.NoError(t, err)
+
+	list, err := client.SafeGetBatch(context.TODO(), [][]byte{[]byte(`batch1`), []byte(`missing`), []byte(`batch2`)})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, first.Index, list.Items[0].Index)
+	assert.Equal(t, []byte(`val1`), list.Items[0].Value)
+	assert.Equal(t, []byte(`val2`), list.Items[1].Value)
+	for _, item := range list.Items {
+		assert.True(t, item.Verified)
+	}
+	client.Disconnect()
+}
+
+func TestImmuClient_GetAt(t *testing.T) {
+	setup()
+	first, err := client.Set(context.TODO(), []byte(`timetravel`), []byte(`v1`))
+	require.NoError(t, err)
+	_, err = client.SafeSet(context.TODO(), []byte(`timetravel`), []byte(`v2`))
+	require.NoError(t, err)
+
+	item, err := client.GetAt(context.TODO(), []byte(`timetravel`), first.Index)
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v1`), item.Value)
+	assert.Equal(t, first.Index, item.Index)
+	assert.True(t, item.Verified)
+
+	item, err = client.GetAsOf(context.TODO(), []byte(`timetravel`), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v2`), item.Value)
+	assert.True(t, item.Verified)
+
+	// proven entries of another key or at another index are not verified
+	ic := client.(*immuClient)
+	honest := ic.ServiceClient
+	ic.ServiceClient = &redirectingHistory{ImmuServiceClient: honest, key: []byte(`timetravel`), index: first.Index}
+	item, err = client.GetAt(context.TODO(), []byte(`elsewhere`), first.Index)
+	require.NoError(t, err)
+	assert.False(t, item.Verified)
+	item, err = client.GetAt(context.TODO(), []byte(`timetravel`), first.Index+1)
+	require.NoError(t, err)
+	assert.False(t, item.Verified)
+	item, err = client.GetAsOf(context.TODO(), []byte(`elsewhere`), time.Now())
+	require.NoError(t, err)
+	assert.False(t, item.Verified)
+	ic.ServiceClient = honest
+	client.Disconnect()
+}
+
+// redirectingHistory answers the time-travel reads with the entries key had at index
+type redirectingHistory struct {
+	schema.ImmuServiceClient
+	key   []byte
+	index uint64
+}
+
+func (r *redirectingHistory) GetAt(ctx context.Context, in *schema.GetAtOptions, opts ...grpc.CallOption) (*schema.HistoricalItem, error) {
+	return r.ImmuServiceClient.GetAt(ctx, &schema.GetAtOptions{Key: r.key, Index: r.index, RootIndex: in.RootIndex}, opts...)
+}
+
+func (r *redirectingHistory) GetAsOf(ctx context.Context, in *schema.GetAsOfOptions, opts ...grpc.CallOption) (*schema.HistoricalItem, error) {
+	return r.ImmuServiceClient.GetAsOf(ctx, &schema.GetAsOfOptions{Key: r.key, Timestamp: in.Timestamp, RootIndex: in.RootIndex}, opts...)
+}
+
+func TestImmuClient_SafeZScan(t *testing.T) {
+	setup()
+	for i, k := range []string{`zs1`, `zs2`, `zs3`} {
+		_, err := client.Set(context.TODO(), []byte(k), []byte(`v`+k))
+		require.NoError(t, err)
+		_, err = client.ZAdd(context.TODO(), []byte(`safezscan`), float64(3-i), []byte(k), nil)
+		require.NoError(t, err)
+	}
+
+	page, err := client.SafeZScan(context.TODO(), &schema.ZScanOptions{Set: []byte(`safezscan`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.True(t, page.More)
+	assert.Equal(t, []byte(`zs3`), page.Items[0].Key)
+	assert.Equal(t, []byte(`vzs3`), page.Items[0].Value)
+	assert.Equal(t, float64(1), page.Items[0].Score)
+	for _, item := range page.Items {
+		assert.True(t, item.Verified)
+		assert.NotEqual(t, item.Index, item.EntryIndex)
+	}
+
+	page, err = client.SafeZScan(WithPageToken(context.TODO(), page.NextPageToken), &schema.ZScanOptions{Set: []byte(`safezscan`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, []byte(`zs1`), page.Items[0].Key)
+	assert.True(t, page.Items[0].Verified)
+	assert.False(t, page.More)
+
+	page, err = client.SafeZScan(context.TODO(), &schema.ZScanOptions{Set: []byte(`missing`)})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	client.Disconnect()
+}
+
+func TestImmuClient_Subscribe(t *testing.T) {
+	setup()
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan *VerifiedItem, 100)
+	done := make(chan error)
+	go func() {
+		done <- client.Subscribe(ctx, &schema.SubscribeRequest{Prefix: []byte(`sub/`), Proof: true}, func(item *VerifiedItem) error {
+			changes <- item
+			return nil
+		})
+	}()
+	// changes are sent only once the subscription is established
+	require.Eventually(t, func() bool {
+		if _, err := client.Set(context.TODO(), []byte(`sub/ready`), []byte(`ready`)); err != nil {
+			return false
+		}
+		select {
+		case <-changes:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, time.Millisecond)
+
+	index, err := client.Set(context.TODO(), []byte(`sub/key`), []byte(`value`))
+	require.NoError(t, err)
+	for {
+		change := <-changes
+		require.True(t, change.Verified)
+		if bytes.Equal(change.Key, []byte(`sub/key`)) {
+			assert.Equal(t, index.Index, change.Index)
+			assert.Equal(t, []byte(`value`), change.Value)
+			break
+		}
+	}
+	cancel()
+	require.NoError(t, <-done)
+	client.Disconnect()
+}
+
 func TestImmuClient_SetBatch(t *testing.T) {
 	setup()
 	br := BatchRequest{
@@ -877,6 +1060,19 @@ func TestImmuClient_Count(t *testing.T) {
 	client.Disconnect()
 }
 
+func TestImmuClient_Stats(t *testing.T) {
+	setup()
+	_, _ = client.Set(context.TODO(), []byte(`stats1`), []byte(`val1`))
+	_, _ = client.Set(context.TODO(), []byte(`stats1`), []byte(`val2`))
+	_, _ = client.Set(context.TODO(), []byte(`stats2`), []byte(`val1`))
+
+	stats, err := client.Stats(context.TODO(), []byte(`stats`))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), stats.LiveKeys)
+	assert.Equal(t, uint64(3), stats.Entries)
+	client.Disconnect()
+}
+
 func TestImmuClient_CountAll(t *testing.T) {
 	setup()
 	_, _ = client.SafeSet(context.TODO(), []byte(`key1`), []byte(`val11`))
@@ -902,7 +1098,7 @@ func TestImmuClient_Scan(t *testing.T) {
 
 	sil, err := client.Scan(context.TODO(), &schema.ScanOptions{Prefix: []byte("key")})
 
-	assert.IsType(t, &schema.StructuredItemList{}, sil)
+	assert.IsType(t, &StructuredItemPage{}, sil)
 	assert.Nil(t, err)
 	assert.Len(t, sil.Items, 2)
 	client.Disconnect()
@@ -972,6 +1168,419 @@ func TestImmuClient_GetReference(t *testing.T) {
 	client.Disconnect()
 }
 
+func TestImmuClient_IdempotentSet(t *testing.T) {
+	setup()
+	ctx := WithIdempotencyKey(context.TODO(), "idempotent-set")
+	idx, err := client.Set(ctx, []byte(`key`), []byte(`value`))
+	require.NoError(t, err)
+	retried, err := client.Set(ctx, []byte(`key`), []byte(`value`))
+	require.NoError(t, err)
+	assert.Equal(t, idx.Index, retried.Index)
+
+	_, err = client.Set(ctx, []byte(`key`), []byte(`other value`))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	vi, err := client.SafeSet(WithIdempotencyKey(context.TODO(), "idempotent-safeset"), []byte(`key`), []byte(`value`))
+	require.NoError(t, err)
+	retriedVi, err := client.SafeSet(WithIdempotencyKey(context.TODO(), "idempotent-safeset"), []byte(`key`), []byte(`value`))
+	require.NoError(t, err)
+	assert.Equal(t, vi.Index, retriedVi.Index)
+	assert.True(t, retriedVi.Verified)
+	client.Disconnect()
+}
+
+func TestImmuClient_VerificationBundle(t *testing.T) {
+	setup()
+	for i := 0; i < 10; i++ {
+		_, err := client.Set(context.TODO(), []byte(`bundle`+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+	root, err := client.CurrentRoot(context.TODO())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	bundle, err := client.DownloadVerificationBundle(context.TODO(), &buf, 0)
+	require.NoError(t, err)
+	assert.Len(t, bundle.Entries, int(root.GetIndex()+1))
+
+	verified, err := VerifyBundle(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, bundle.Root.GetRoot(), verified.Root.GetRoot())
+
+	buf.Reset()
+	bundle, err = client.DownloadVerificationBundle(context.TODO(), &buf, 3)
+	require.NoError(t, err)
+	assert.Len(t, bundle.Entries, 3)
+	_, err = VerifyBundle(&buf)
+	assert.NoError(t, err)
+
+	bundle.Entries[1].Item.Value = []byte(`tampered`)
+	assert.Equal(t, schema.ErrCorruptedVerificationBundle, bundle.Verify())
+
+	_, err = VerifyBundle(bytes.NewReader(nil))
+	assert.Equal(t, schema.ErrEmptyVerificationBundle, err)
+	client.Disconnect()
+}
+
+func TestImmuClient_SampleKeys(t *testing.T) {
+	setup()
+	for i := 0; i < 10; i++ {
+		_, err := client.Set(context.TODO(), []byte(`sample`+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+
+	sample, err := client.SampleKeys(context.TODO(), 3, []byte(`sample`), 42)
+	require.NoError(t, err)
+	assert.Len(t, sample.Entries, 3)
+	assert.Equal(t, uint64(10), sample.Population)
+
+	again, err := client.SampleKeys(context.TODO(), 3, []byte(`sample`), 42)
+	require.NoError(t, err)
+	for i := range sample.Entries {
+		assert.Equal(t, sample.Entries[i].Item.Key, again.Entries[i].Item.Key)
+	}
+
+	_, err = client.SampleKeys(context.TODO(), 0, []byte(`sample`), 42)
+	assert.Error(t, err)
+	client.Disconnect()
+}
+
+func TestImmuClient_VerifiedCount(t *testing.T) {
+	setup()
+	for i := 0; i < 10; i++ {
+		_, err := client.Set(context.TODO(), []byte(`counted`+strconv.Itoa(i)), []byte(strconv.Itoa(i)))
+		require.NoError(t, err)
+	}
+	_, err := client.Set(context.TODO(), []byte(`counted0`), []byte(`again`))
+	require.NoError(t, err)
+
+	vc, err := client.VerifiedCount(context.TODO(), []byte(`counted`), 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), vc.Count)
+	assert.Equal(t, uint64(4), vc.Sampled)
+	assert.True(t, vc.Verified)
+
+	vc, err = client.VerifiedCount(context.TODO(), []byte(`uncounted`), 4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), vc.Count)
+	assert.False(t, vc.Verified)
+
+	_, err = client.VerifiedCount(context.TODO(), []byte(`counted`), 0)
+	assert.Error(t, err)
+	client.Disconnect()
+}
+
+func TestImmuClient_VerifiedExists(t *testing.T) {
+	setup()
+	index, err := client.Set(context.TODO(), []byte(`existing`), []byte(`value`))
+	require.NoError(t, err)
+
+	ve, err := client.VerifiedExists(context.TODO(), []byte(`existing`))
+	require.NoError(t, err)
+	assert.True(t, ve.Exists)
+	assert.True(t, ve.Verified)
+	assert.Equal(t, index.Index, ve.Index)
+
+	ve, err = client.VerifiedExists(context.TODO(), []byte(`missing`))
+	require.NoError(t, err)
+	assert.False(t, ve.Exists)
+	assert.False(t, ve.Verified)
+	client.Disconnect()
+}
+
+func TestImmuClient_HistoryTimeline(t *testing.T) {
+	setup()
+	_, err := client.CurrentRoot(context.TODO())
+	require.NoError(t, err)
+	first, err := client.SafeSet(context.TODO(), []byte(`timeline`), []byte(`first`))
+	require.NoError(t, err)
+	second, err := client.Set(context.TODO(), []byte(`timeline`), []byte(`second value`))
+	require.NoError(t, err)
+
+	timeline, err := client.HistoryTimeline(context.TODO(), []byte(`timeline`), false)
+	require.NoError(t, err)
+	require.Len(t, timeline.Entries, 2)
+	assert.Equal(t, first.Index, timeline.Entries[0].Index)
+	assert.Equal(t, second.Index, timeline.Entries[1].Index)
+	assert.Equal(t, len(`second value`), timeline.Entries[1].ValueSize)
+	// the root cached by SafeSet covers the first revision only
+	assert.Equal(t, first.Index, timeline.LocalRoot.GetIndex())
+	assert.True(t, timeline.Entries[0].Covered)
+	assert.False(t, timeline.Entries[1].Covered)
+	assert.False(t, timeline.Entries[0].Verified)
+
+	timeline, err = client.HistoryTimeline(context.TODO(), []byte(`timeline`), true)
+	require.NoError(t, err)
+	for _, entry := range timeline.Entries {
+		assert.True(t, entry.Verified)
+	}
+
+	timeline, err = client.HistoryTimeline(context.TODO(), []byte(`missing`), true)
+	require.NoError(t, err)
+	assert.Empty(t, timeline.Entries)
+	client.Disconnect()
+}
+
+func TestImmuClient_VerifyValueHash(t *testing.T) {
+	setup()
+	first, err := client.SafeSet(context.TODO(), []byte(`hashed`), []byte(`first`))
+	require.NoError(t, err)
+	_, err = client.SafeSet(context.TODO(), []byte(`hashed`), []byte(`second`))
+	require.NoError(t, err)
+	_, err = client.RawSafeSet(context.TODO(), []byte(`rawHashed`), []byte(`raw`))
+	require.NoError(t, err)
+
+	h := sha256.Sum256([]byte(`second`))
+	v, err := client.VerifyValueHash(context.TODO(), []byte(`hashed`), h[:], nil)
+	require.NoError(t, err)
+	assert.True(t, v.Matches)
+	assert.True(t, v.Verified)
+
+	h = sha256.Sum256([]byte(`first`))
+	v, err = client.VerifyValueHash(context.TODO(), []byte(`hashed`), h[:], nil)
+	require.NoError(t, err)
+	assert.False(t, v.Matches)
+	assert.True(t, v.Verified)
+
+	v, err = client.VerifyValueHash(context.TODO(), []byte(`hashed`), h[:], &schema.Index{Index: first.Index})
+	require.NoError(t, err)
+	assert.True(t, v.Matches)
+	assert.Equal(t, first.Index, v.Index)
+
+	h = sha256.Sum256([]byte(`raw`))
+	v, err = client.RawVerifyValueHash(context.TODO(), []byte(`rawHashed`), h[:], nil)
+	require.NoError(t, err)
+	assert.True(t, v.Matches)
+	assert.True(t, v.Verified)
+
+	_, err = client.VerifyValueHash(context.TODO(), []byte(`hashed`), h[:4], nil)
+	assert.Error(t, err)
+	client.Disconnect()
+}
+
+func TestImmuClient_Pagination(t *testing.T) {
+	setup()
+	for _, k := range []string{`page1`, `page2`, `page3`} {
+		_, err := client.Set(context.TODO(), []byte(k), []byte(`v`))
+		require.NoError(t, err)
+		_, err = client.ZAdd(context.TODO(), []byte(`pages`), 1, []byte(k), nil)
+		require.NoError(t, err)
+	}
+	for _, v := range []string{`v1`, `v2`, `v3`} {
+		_, err := client.Set(context.TODO(), []byte(`paged`), []byte(v))
+		require.NoError(t, err)
+	}
+
+	ctx := context.TODO()
+	page, err := client.Scan(ctx, &schema.ScanOptions{Prefix: []byte(`page`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, []byte(`page1`), page.GetItems()[0].Key)
+	assert.True(t, page.More)
+	assert.Equal(t, uint64(3), page.TotalEstimate)
+	require.NotEmpty(t, page.NextPageToken)
+
+	page, err = client.Scan(WithPageToken(ctx, page.NextPageToken), &schema.ScanOptions{Prefix: []byte(`page`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, []byte(`page3`), page.Items[0].Key)
+	assert.Equal(t, []byte(`paged`), page.Items[1].Key)
+	assert.False(t, page.More)
+	assert.Equal(t, uint64(4), page.TotalEstimate)
+	assert.Empty(t, page.NextPageToken)
+
+	// newest-first
+	page, err = client.Scan(ctx, &schema.ScanOptions{Prefix: []byte(`page`), Limit: 3, Reverse: true})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 3)
+	assert.Equal(t, []byte(`paged`), page.Items[0].Key)
+	assert.True(t, page.More)
+	page, err = client.Scan(WithPageToken(ctx, page.NextPageToken), &schema.ScanOptions{Prefix: []byte(`page`), Limit: 3, Reverse: true})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, []byte(`page1`), page.Items[0].Key)
+	assert.False(t, page.More)
+
+	zpage, err := client.ZScan(ctx, &schema.ZScanOptions{Set: []byte(`pages`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, zpage.Items, 2)
+	assert.True(t, zpage.More)
+	ztoken := zpage.NextPageToken
+	zpage, err = client.ZScan(WithPageToken(ctx, ztoken), &schema.ZScanOptions{Set: []byte(`pages`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, zpage.Items, 1)
+	assert.Equal(t, []byte(`page3`), zpage.Items[0].Item.Key)
+	assert.False(t, zpage.More)
+	assert.Equal(t, uint64(3), zpage.TotalEstimate)
+
+	hpage, err := client.History(ctx, &schema.HistoryOptions{Key: []byte(`paged`), Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, hpage.Items, 1)
+	assert.Equal(t, []byte(`v3`), hpage.Items[0].Value.Payload)
+	assert.True(t, hpage.More)
+	hpage, err = client.History(WithPageToken(ctx, hpage.NextPageToken), &schema.HistoryOptions{Key: []byte(`paged`), Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, hpage.Items, 2)
+	assert.Equal(t, []byte(`v1`), hpage.Items[1].Value.Payload)
+	assert.False(t, hpage.More)
+	assert.Equal(t, uint64(3), hpage.TotalEstimate)
+
+	// without a limit everything is returned in a single page
+	hpage, err = client.History(ctx, &schema.HistoryOptions{Key: []byte(`paged`)})
+	require.NoError(t, err)
+	require.Len(t, hpage.Items, 3)
+	assert.False(t, hpage.More)
+
+	_, err = client.Scan(WithPageToken(ctx, "!"), &schema.ScanOptions{Prefix: []byte(`page`)})
+	assert.Equal(t, ErrInvalidPageToken, err)
+	// a sorted set token isn't a valid history one
+	_, err = client.History(WithPageToken(ctx, ztoken), &schema.HistoryOptions{Key: []byte(`paged`)})
+	assert.Equal(t, ErrInvalidPageToken, err)
+	client.Disconnect()
+}
+
+func TestImmuClient_FreezePrefix(t *testing.T) {
+	setup()
+	_, err := client.Set(context.TODO(), []byte(`sealed1`), []byte(`v1`))
+	require.NoError(t, err)
+
+	vi, err := client.FreezePrefix(context.TODO(), []byte(`sealed`))
+	require.NoError(t, err)
+	assert.True(t, vi.Verified)
+
+	_, err = client.Set(context.TODO(), []byte(`sealed1`), []byte(`v2`))
+	assert.Error(t, err)
+	item, err := client.Get(context.TODO(), []byte(`sealed1`))
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v1`), item.Value.Payload)
+
+	_, err = client.FreezePrefix(context.TODO(), []byte(`sealed`))
+	assert.Error(t, err)
+	client.Disconnect()
+}
+
+func TestImmuClient_Delete(t *testing.T) {
+	setup()
+	_, err := client.Set(context.TODO(), []byte(`removed`), []byte(`v1`))
+	require.NoError(t, err)
+
+	vi, err := client.Delete(context.TODO(), []byte(`removed`))
+	require.NoError(t, err)
+	assert.True(t, vi.Verified)
+
+	_, err = client.Get(context.TODO(), []byte(`removed`))
+	assert.Error(t, err)
+	_, err = client.Delete(context.TODO(), []byte(`removed`))
+	assert.Error(t, err)
+
+	list, err := client.History(context.TODO(), &schema.HistoryOptions{Key: []byte(`removed`)})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.True(t, list.Items[0].Deleted)
+	assert.Equal(t, vi.Index, list.Items[0].Index)
+
+	timeline, err := client.HistoryTimeline(context.TODO(), []byte(`removed`), true)
+	require.NoError(t, err)
+	require.Len(t, timeline.Entries, 2)
+	assert.True(t, timeline.Entries[1].Deleted)
+	assert.True(t, timeline.Entries[1].Verified)
+	client.Disconnect()
+}
+
+func TestImmuClient_Unreference(t *testing.T) {
+	setup()
+	_, err := client.Set(context.TODO(), []byte(`release-1`), []byte(`v1`))
+	require.NoError(t, err)
+	_, err = client.Reference(context.TODO(), []byte(`stable`), []byte(`release-1`), nil)
+	require.NoError(t, err)
+
+	vi, err := client.Unreference(context.TODO(), []byte(`stable`))
+	require.NoError(t, err)
+	assert.True(t, vi.Verified)
+
+	_, err = client.GetReference(context.TODO(), &schema.Key{Key: []byte(`stable`)})
+	assert.Error(t, err)
+	_, err = client.Unreference(context.TODO(), []byte(`stable`))
+	assert.Error(t, err)
+	_, err = client.Unreference(context.TODO(), []byte(`release-1`))
+	assert.Error(t, err)
+
+	item, err := client.Get(context.TODO(), []byte(`release-1`))
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v1`), item.Value.Payload)
+	client.Disconnect()
+}
+
+func TestImmuClient_GetReferences(t *testing.T) {
+	setup()
+	idx1, err := client.Set(context.TODO(), []byte(`document`), []byte(`v1`))
+	require.NoError(t, err)
+	_, err = client.Reference(context.TODO(), []byte(`latest`), []byte(`document`), nil)
+	require.NoError(t, err)
+	_, err = client.Reference(context.TODO(), []byte(`first`), []byte(`document`), idx1)
+	require.NoError(t, err)
+	idx2, err := client.Set(context.TODO(), []byte(`document`), []byte(`v2`))
+	require.NoError(t, err)
+
+	list, err := client.GetReferences(context.TODO(), []byte(`document`), nil)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, []byte(`first`), list.Items[0].Reference.Reference)
+	assert.Equal(t, idx1.Index, list.Items[0].Reference.Index.Index)
+	assert.Equal(t, []byte(`latest`), list.Items[1].Reference.Reference)
+
+	list, err = client.GetReferences(context.TODO(), []byte(`document`), idx2)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, []byte(`latest`), list.Items[0].Reference.Reference)
+	client.Disconnect()
+}
+
+func TestImmuClient_GetReferenceChain(t *testing.T) {
+	setup()
+	idx1, err := client.Set(context.TODO(), []byte(`release`), []byte(`v1`))
+	require.NoError(t, err)
+	refIdx, err := client.Reference(context.TODO(), []byte(`stable`), []byte(`release`), idx1)
+	require.NoError(t, err)
+	_, err = client.Reference(context.TODO(), []byte(`current`), []byte(`release`), nil)
+	require.NoError(t, err)
+	idx2, err := client.Set(context.TODO(), []byte(`release`), []byte(`v2`))
+	require.NoError(t, err)
+
+	chain, err := client.GetReferenceChain(context.TODO(), []byte(`stable`))
+	require.NoError(t, err)
+	require.True(t, chain.Verified)
+	assert.True(t, chain.Pinned)
+	assert.Equal(t, []byte(`release`), chain.Key)
+	assert.Equal(t, refIdx.Index, chain.Reference.Index)
+	assert.Equal(t, []byte(`stable`), chain.Reference.Key)
+	assert.Equal(t, idx1.Index, chain.Target.Index)
+	assert.Equal(t, []byte(`v1`), chain.Target.Value)
+
+	chain, err = client.GetReferenceChain(context.TODO(), []byte(`current`))
+	require.NoError(t, err)
+	require.True(t, chain.Verified)
+	assert.False(t, chain.Pinned)
+	assert.Equal(t, idx2.Index, chain.Target.Index)
+	assert.Equal(t, []byte(`v2`), chain.Target.Value)
+
+	_, err = client.GetReferenceChain(context.TODO(), []byte(`release`))
+	require.Error(t, err)
+	client.Disconnect()
+}
+
+func TestSampleIndexes(t *testing.T) {
+	assert.Equal(t, []uint64{0, 1, 2}, sampleIndexes(3, 0))
+	assert.Equal(t, []uint64{0, 1, 2}, sampleIndexes(3, 5))
+
+	indexes := sampleIndexes(100, 10)
+	assert.Len(t, indexes, 10)
+	assert.True(t, sort.SliceIsSorted(indexes, func(i, j int) bool { return indexes[i] < indexes[j] }))
+	for i := 1; i < len(indexes); i++ {
+		assert.NotEqual(t, indexes[i-1], indexes[i])
+	}
+}
+
 func TestImmuClient_GetOptions(t *testing.T) {
 	setup()
 	op := client.GetOptions()
@@ -1003,6 +1612,38 @@ func TestImmuClient_ExecAllOpsOptions(t *testing.T) {
 	client.Disconnect()
 }
 
+func TestImmuClient_ExecAllTx(t *testing.T) {
+	setup()
+
+	aOps := func() *schema.Ops {
+		return &schema.Ops{
+			Operations: []*schema.Op{
+				{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`txKey1`), Value: []byte(`val1`)}}},
+				{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`txKey2`), Value: []byte(`val2`)}}},
+				{Operation: &schema.Op_ROpts{ROpts: &schema.ReferenceOptions{Reference: []byte(`txRef`), Key: []byte(`txKey2`)}}},
+			},
+		}
+	}
+
+	idx, err := client.ExecAllTx(context.TODO(), aOps())
+	require.NoError(t, err)
+	require.NotNil(t, idx)
+
+	vi, err := client.SafeExecAllTx(context.TODO(), aOps())
+	require.NoError(t, err)
+	require.True(t, vi.Verified)
+	require.Equal(t, idx.Index+4, vi.Index)
+
+	item, err := client.SafeGet(context.TODO(), []byte(`txRef`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`val2`), item.Value)
+
+	_, err = client.SafeExecAllTx(context.TODO(), &schema.Ops{})
+	require.Error(t, err)
+
+	client.Disconnect()
+}
+
 func TestEnforcedLogoutAfterPasswordChange(t *testing.T) {
 	setup()
 	var (
@@ -0,0 +1,66 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clienttest
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// NewBufconnClient starts a bufconn server with options and auth enabled, and returns a client of it logged in as
+// the default admin, the context authenticating its calls and the function disconnecting the client and stopping the
+// server. The client keeps its state in a temporary directory, removed along with the server.
+func NewBufconnClient(options server.Options) (client.ImmuClient, context.Context, func(), error) {
+	dir, err := ioutil.TempDir("", "clienttest")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bs := servertest.NewBufconnServer(options.WithAuth(true))
+	bs.Start()
+	stop := func() {
+		bs.Stop()
+		os.RemoveAll(dir)
+	}
+
+	opts := client.DefaultOptions().
+		WithDir(dir).
+		WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()})
+	cli, err := client.NewImmuClient(opts)
+	if err != nil {
+		stop()
+		return nil, nil, nil, err
+	}
+	resp, err := cli.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	if err != nil {
+		cli.Disconnect()
+		stop()
+		return nil, nil, nil, err
+	}
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", resp.Token))
+
+	return cli, ctx, func() {
+		cli.Disconnect()
+		stop()
+	}, nil
+}
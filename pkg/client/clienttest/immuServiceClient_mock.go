@@ -28,46 +28,49 @@ import (
 type ImmuServiceClientMock struct {
 	schema.ImmuServiceClient
 
-	ListUsersF        func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.UserList, error)
-	GetUserF          func(ctx context.Context, in *schema.UserRequest, opts ...grpc.CallOption) error
-	CreateUserF       func(ctx context.Context, in *schema.CreateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	ChangePasswordF   func(ctx context.Context, in *schema.ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	SetPermissionF    func(ctx context.Context, in *schema.Item, opts ...grpc.CallOption) (*empty.Empty, error)
-	DeactivateUserF   func(ctx context.Context, in *schema.UserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	UpdateAuthConfigF func(ctx context.Context, in *schema.AuthConfig, opts ...grpc.CallOption) (*empty.Empty, error)
-	UpdateMTLSConfigF func(ctx context.Context, in *schema.MTLSConfig, opts ...grpc.CallOption) (*empty.Empty, error)
-	PrintTreeF        func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Tree, error)
-	LoginF            func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error)
-	LogoutF           func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
-	SetF              func(ctx context.Context, in *schema.KeyValue, opts ...grpc.CallOption) (*schema.Index, error)
-	SafeSetF          func(ctx context.Context, in *schema.SafeSetOptions, opts ...grpc.CallOption) (*schema.Proof, error)
-	GetF              func(ctx context.Context, in *schema.Key, opts ...grpc.CallOption) (*schema.Item, error)
-	SafeGetF          func(ctx context.Context, in *schema.SafeGetOptions, opts ...grpc.CallOption) (*schema.SafeItem, error)
-	SetBatchF         func(ctx context.Context, in *schema.KVList, opts ...grpc.CallOption) (*schema.Index, error)
-	GetBatchF         func(ctx context.Context, in *schema.KeyList, opts ...grpc.CallOption) (*schema.ItemList, error)
-	ScanF             func(ctx context.Context, in *schema.ScanOptions, opts ...grpc.CallOption) (*schema.ItemList, error)
-	CountF            func(ctx context.Context, in *schema.KeyPrefix, opts ...grpc.CallOption) (*schema.ItemsCount, error)
-	CountAllF         func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.ItemsCount, error)
-	CurrentRootF      func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error)
-	InclusionF        func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.InclusionProof, error)
-	ConsistencyF      func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.ConsistencyProof, error)
-	ByIndexF          func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.Item, error)
-	BySafeIndexF      func(ctx context.Context, in *schema.SafeIndexOptions, opts ...grpc.CallOption) (*schema.SafeItem, error)
-	HistoryF          func(ctx context.Context, in *schema.HistoryOptions, opts ...grpc.CallOption) (*schema.ItemList, error)
-	HealthF           func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.HealthResponse, error)
-	ReferenceF        func(ctx context.Context, in *schema.ReferenceOptions, opts ...grpc.CallOption) (*schema.Index, error)
-	SafeReferenceF    func(ctx context.Context, in *schema.SafeReferenceOptions, opts ...grpc.CallOption) (*schema.Proof, error)
-	ZAddF             func(ctx context.Context, in *schema.ZAddOptions, opts ...grpc.CallOption) (*schema.Index, error)
-	ZScanF            func(ctx context.Context, in *schema.ZScanOptions, opts ...grpc.CallOption) (*schema.ZItemList, error)
-	SafeZAddF         func(ctx context.Context, in *schema.SafeZAddOptions, opts ...grpc.CallOption) (*schema.Proof, error)
-	IScanF            func(ctx context.Context, in *schema.IScanOptions, opts ...grpc.CallOption) (*schema.Page, error)
-	DumpF             func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (schema.ImmuService_DumpClient, error)
-	CreateDatabaseF   func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*empty.Empty, error)
-	UseDatabaseF      func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*schema.UseDatabaseReply, error)
-	ChangePermissionF func(ctx context.Context, in *schema.ChangePermissionRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	SetActiveUserF    func(ctx context.Context, in *schema.SetActiveUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
-	DatabaseListF     func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error)
-	ExecAllOpsF       func(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error)
+	ListUsersF           func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.UserList, error)
+	GetUserF             func(ctx context.Context, in *schema.UserRequest, opts ...grpc.CallOption) error
+	CreateUserF          func(ctx context.Context, in *schema.CreateUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	ChangePasswordF      func(ctx context.Context, in *schema.ChangePasswordRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	SetPermissionF       func(ctx context.Context, in *schema.Item, opts ...grpc.CallOption) (*empty.Empty, error)
+	DeactivateUserF      func(ctx context.Context, in *schema.UserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	UpdateAuthConfigF    func(ctx context.Context, in *schema.AuthConfig, opts ...grpc.CallOption) (*empty.Empty, error)
+	UpdateMTLSConfigF    func(ctx context.Context, in *schema.MTLSConfig, opts ...grpc.CallOption) (*empty.Empty, error)
+	PrintTreeF           func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Tree, error)
+	LoginF               func(ctx context.Context, in *schema.LoginRequest, opts ...grpc.CallOption) (*schema.LoginResponse, error)
+	LogoutF              func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
+	SetF                 func(ctx context.Context, in *schema.KeyValue, opts ...grpc.CallOption) (*schema.Index, error)
+	SafeSetF             func(ctx context.Context, in *schema.SafeSetOptions, opts ...grpc.CallOption) (*schema.Proof, error)
+	GetF                 func(ctx context.Context, in *schema.Key, opts ...grpc.CallOption) (*schema.Item, error)
+	SafeGetF             func(ctx context.Context, in *schema.SafeGetOptions, opts ...grpc.CallOption) (*schema.SafeItem, error)
+	SetBatchF            func(ctx context.Context, in *schema.KVList, opts ...grpc.CallOption) (*schema.Index, error)
+	GetBatchF            func(ctx context.Context, in *schema.KeyList, opts ...grpc.CallOption) (*schema.ItemList, error)
+	ScanF                func(ctx context.Context, in *schema.ScanOptions, opts ...grpc.CallOption) (*schema.ItemList, error)
+	CountF               func(ctx context.Context, in *schema.KeyPrefix, opts ...grpc.CallOption) (*schema.ItemsCount, error)
+	CountAllF            func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.ItemsCount, error)
+	CurrentRootF         func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error)
+	InclusionF           func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.InclusionProof, error)
+	ConsistencyF         func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.ConsistencyProof, error)
+	ByIndexF             func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.Item, error)
+	BySafeIndexF         func(ctx context.Context, in *schema.SafeIndexOptions, opts ...grpc.CallOption) (*schema.SafeItem, error)
+	HistoryF             func(ctx context.Context, in *schema.HistoryOptions, opts ...grpc.CallOption) (*schema.ItemList, error)
+	HealthF              func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.HealthResponse, error)
+	ReferenceF           func(ctx context.Context, in *schema.ReferenceOptions, opts ...grpc.CallOption) (*schema.Index, error)
+	SafeReferenceF       func(ctx context.Context, in *schema.SafeReferenceOptions, opts ...grpc.CallOption) (*schema.Proof, error)
+	ZAddF                func(ctx context.Context, in *schema.ZAddOptions, opts ...grpc.CallOption) (*schema.Index, error)
+	ZScanF               func(ctx context.Context, in *schema.ZScanOptions, opts ...grpc.CallOption) (*schema.ZItemList, error)
+	SafeZAddF            func(ctx context.Context, in *schema.SafeZAddOptions, opts ...grpc.CallOption) (*schema.Proof, error)
+	IScanF               func(ctx context.Context, in *schema.IScanOptions, opts ...grpc.CallOption) (*schema.Page, error)
+	DumpF                func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (schema.ImmuService_DumpClient, error)
+	CreateDatabaseF      func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*empty.Empty, error)
+	UseDatabaseF         func(ctx context.Context, in *schema.Database, opts ...grpc.CallOption) (*schema.UseDatabaseReply, error)
+	ChangePermissionF    func(ctx context.Context, in *schema.ChangePermissionRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	SetActiveUserF       func(ctx context.Context, in *schema.SetActiveUserRequest, opts ...grpc.CallOption) (*empty.Empty, error)
+	DatabaseListF        func(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.DatabaseListResponse, error)
+	ExecAllOpsF          func(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error)
+	ExecAllTxF           func(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error)
+	SafeExecAllTxF       func(ctx context.Context, in *schema.SafeExecAllTxOptions, opts ...grpc.CallOption) (*schema.TxProof, error)
+	CompareAndExecAllTxF func(ctx context.Context, in *schema.CompareAndExecAllTxOptions, opts ...grpc.CallOption) (*schema.Index, error)
 }
 
 func (iscm *ImmuServiceClientMock) CurrentRoot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error) {
@@ -161,6 +164,18 @@ func (icm *ImmuServiceClientMock) ExecAllOps(ctx context.Context, in *schema.Ops
 	return icm.ExecAllOpsF(ctx, in, opts...)
 }
 
+func (icm *ImmuServiceClientMock) ExecAllTx(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error) {
+	return icm.ExecAllTxF(ctx, in, opts...)
+}
+
+func (icm *ImmuServiceClientMock) SafeExecAllTx(ctx context.Context, in *schema.SafeExecAllTxOptions, opts ...grpc.CallOption) (*schema.TxProof, error) {
+	return icm.SafeExecAllTxF(ctx, in, opts...)
+}
+
+func (icm *ImmuServiceClientMock) CompareAndExecAllTx(ctx context.Context, in *schema.CompareAndExecAllTxOptions, opts ...grpc.CallOption) (*schema.Index, error) {
+	return icm.CompareAndExecAllTxF(ctx, in, opts...)
+}
+
 func (icm *ImmuServiceClientMock) Inclusion(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.InclusionProof, error) {
 	return icm.InclusionF(ctx, in, opts...)
 }
@@ -228,6 +243,15 @@ func NewImmuServiceClientMock() *ImmuServiceClientMock {
 		ExecAllOpsF: func(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error) {
 			return &schema.Index{}, nil
 		},
+		ExecAllTxF: func(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error) {
+			return &schema.Index{}, nil
+		},
+		SafeExecAllTxF: func(ctx context.Context, in *schema.SafeExecAllTxOptions, opts ...grpc.CallOption) (*schema.TxProof, error) {
+			return &schema.TxProof{}, nil
+		},
+		CompareAndExecAllTxF: func(ctx context.Context, in *schema.CompareAndExecAllTxOptions, opts ...grpc.CallOption) (*schema.Index, error) {
+			return &schema.Index{}, nil
+		},
 		InclusionF: func(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.InclusionProof, error) {
 			return &schema.InclusionProof{}, nil
 		},
@@ -20,6 +20,7 @@ package clienttest
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/client"
@@ -46,26 +47,45 @@ type ImmuClientMock struct {
 	SafeReferenceF      func(context.Context, []byte, []byte, *schema.Index) (*client.VerifiedIndex, error)
 	ZAddF               func(context.Context, []byte, float64, []byte, *schema.Index) (*schema.Index, error)
 	SafeZAddF           func(context.Context, []byte, float64, []byte, *schema.Index) (*client.VerifiedIndex, error)
-	HistoryF            func(context.Context, *schema.HistoryOptions) (*schema.StructuredItemList, error)
+	HistoryF            func(context.Context, *schema.HistoryOptions) (*client.StructuredItemPage, error)
 	UseDatabaseF        func(context.Context, *schema.Database) (*schema.UseDatabaseReply, error)
 	DumpF               func(context.Context, io.WriteSeeker) (int64, error)
+	DumpKeyHistoryF     func(context.Context, []byte) (*schema.KeyHistoryDump, error)
+	HistoryTimelineF    func(context.Context, []byte, bool) (*client.KeyTimeline, error)
+	SampleKeysF         func(context.Context, uint64, []byte, int64) (*schema.KeySample, error)
+	DeleteF             func(context.Context, []byte) (*client.VerifiedIndex, error)
+	FreezePrefixF       func(context.Context, []byte) (*client.VerifiedIndex, error)
+	AnalyzeStorageF     func(context.Context) (*schema.StorageReport, error)
+	BackupF             func(context.Context) (*schema.BackupInfo, error)
+	RestoreBackupF      func(context.Context, string, string) (*schema.BackupInfo, error)
+	DownloadBundleF     func(context.Context, io.Writer, uint64) (*schema.VerificationBundle, error)
+	LogsF               func(context.Context, *schema.LogRequest, func(*schema.LogEntry) error) error
 	CurrentRootF        func(context.Context) (*schema.Root, error)
 	ByIndexF            func(context.Context, uint64) (*schema.StructuredItem, error)
+	GetBySequenceF      func(context.Context, uint64) (*schema.SequencedWrite, error)
+	VerifyValueHashF    func(context.Context, []byte, []byte, *schema.Index) (*client.VerifiedValueHash, error)
+	RawVerifyValueHashF func(context.Context, []byte, []byte, *schema.Index) (*client.VerifiedValueHash, error)
+	VerifiedCountF      func(context.Context, []byte, uint64) (*client.VerifiedCount, error)
+	VerifiedExistsF     func(context.Context, []byte) (*client.VerifiedExistence, error)
 	GetF                func(context.Context, []byte) (*schema.StructuredItem, error)
 	RawSafeGetF         func(context.Context, []byte, ...grpc.CallOption) (vi *client.VerifiedItem, err error)
 	RawBySafeIndexF     func(context.Context, uint64) (*client.VerifiedItem, error)
 	ListUsersF          func(context.Context) (*schema.UserList, error)
+	ListSessionsF       func(context.Context) (*schema.SessionList, error)
+	GetUsageF           func(context.Context, string, time.Time, time.Time) (*schema.UsageReport, error)
 	SetActiveUserF      func(context.Context, *schema.SetActiveUserRequest) error
 	ChangePermissionF   func(context.Context, schema.PermissionAction, string, string, uint32) error
-	ZScanF              func(context.Context, *schema.ZScanOptions) (*schema.ZStructuredItemList, error)
+	ZScanF              func(context.Context, *schema.ZScanOptions) (*client.ZStructuredItemPage, error)
 	IScanF              func(context.Context, uint64, uint64) (*schema.SPage, error)
-	ScanF               func(context.Context, *schema.ScanOptions) (*schema.StructuredItemList, error)
+	ScanF               func(context.Context, *schema.ScanOptions) (*client.StructuredItemPage, error)
 	CountF              func(context.Context, []byte) (*schema.ItemsCount, error)
 	RawSafeSetF         func(context.Context, []byte, []byte) (vi *client.VerifiedIndex, err error)
 	CreateDatabaseF     func(context.Context, *schema.Database) error
 	DatabaseListF       func(context.Context) (*schema.DatabaseListResponse, error)
 	ChangePasswordF     func(context.Context, []byte, []byte, []byte) error
 	CreateUserF         func(context.Context, []byte, []byte, uint32, string) error
+
+	GetAccessControlStateF func(context.Context) (*schema.AccessControlState, error)
 }
 
 // GetOptions ...
@@ -144,7 +164,7 @@ func (icm *ImmuClientMock) SafeZAdd(ctx context.Context, set []byte, score float
 }
 
 // History ...
-func (icm *ImmuClientMock) History(ctx context.Context, options *schema.HistoryOptions) (*schema.StructuredItemList, error) {
+func (icm *ImmuClientMock) History(ctx context.Context, options *schema.HistoryOptions) (*client.StructuredItemPage, error) {
 	return icm.HistoryF(ctx, options)
 }
 
@@ -158,6 +178,56 @@ func (icm *ImmuClientMock) Dump(ctx context.Context, writer io.WriteSeeker) (int
 	return icm.DumpF(ctx, writer)
 }
 
+// DumpKeyHistory ...
+func (icm *ImmuClientMock) DumpKeyHistory(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error) {
+	return icm.DumpKeyHistoryF(ctx, key)
+}
+
+// HistoryTimeline ...
+func (icm *ImmuClientMock) HistoryTimeline(ctx context.Context, key []byte, verify bool) (*client.KeyTimeline, error) {
+	return icm.HistoryTimelineF(ctx, key, verify)
+}
+
+// SampleKeys ...
+func (icm *ImmuClientMock) SampleKeys(ctx context.Context, size uint64, prefix []byte, seed int64) (*schema.KeySample, error) {
+	return icm.SampleKeysF(ctx, size, prefix, seed)
+}
+
+// Delete ...
+func (icm *ImmuClientMock) Delete(ctx context.Context, key []byte) (*client.VerifiedIndex, error) {
+	return icm.DeleteF(ctx, key)
+}
+
+// FreezePrefix ...
+func (icm *ImmuClientMock) FreezePrefix(ctx context.Context, prefix []byte) (*client.VerifiedIndex, error) {
+	return icm.FreezePrefixF(ctx, prefix)
+}
+
+// AnalyzeStorage ...
+func (icm *ImmuClientMock) AnalyzeStorage(ctx context.Context) (*schema.StorageReport, error) {
+	return icm.AnalyzeStorageF(ctx)
+}
+
+// Backup ...
+func (icm *ImmuClientMock) Backup(ctx context.Context) (*schema.BackupInfo, error) {
+	return icm.BackupF(ctx)
+}
+
+// RestoreBackup ...
+func (icm *ImmuClientMock) RestoreBackup(ctx context.Context, file string, databaseName string) (*schema.BackupInfo, error) {
+	return icm.RestoreBackupF(ctx, file, databaseName)
+}
+
+// DownloadVerificationBundle ...
+func (icm *ImmuClientMock) DownloadVerificationBundle(ctx context.Context, w io.Writer, sampleSize uint64) (*schema.VerificationBundle, error) {
+	return icm.DownloadBundleF(ctx, w, sampleSize)
+}
+
+// Logs ...
+func (icm *ImmuClientMock) Logs(ctx context.Context, req *schema.LogRequest, handler func(*schema.LogEntry) error) error {
+	return icm.LogsF(ctx, req, handler)
+}
+
 // CurrentRoot ...
 func (icm *ImmuClientMock) CurrentRoot(ctx context.Context) (*schema.Root, error) {
 	return icm.CurrentRootF(ctx)
@@ -168,6 +238,31 @@ func (icm *ImmuClientMock) ByIndex(ctx context.Context, index uint64) (*schema.S
 	return icm.ByIndexF(ctx, index)
 }
 
+// GetBySequence ...
+func (icm *ImmuClientMock) GetBySequence(ctx context.Context, sequence uint64) (*schema.SequencedWrite, error) {
+	return icm.GetBySequenceF(ctx, sequence)
+}
+
+// VerifyValueHash ...
+func (icm *ImmuClientMock) VerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*client.VerifiedValueHash, error) {
+	return icm.VerifyValueHashF(ctx, key, valueHash, index)
+}
+
+// RawVerifyValueHash ...
+func (icm *ImmuClientMock) RawVerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*client.VerifiedValueHash, error) {
+	return icm.RawVerifyValueHashF(ctx, key, valueHash, index)
+}
+
+// VerifiedCount ...
+func (icm *ImmuClientMock) VerifiedCount(ctx context.Context, prefix []byte, sampleSize uint64) (*client.VerifiedCount, error) {
+	return icm.VerifiedCountF(ctx, prefix, sampleSize)
+}
+
+// VerifiedExists ...
+func (icm *ImmuClientMock) VerifiedExists(ctx context.Context, key []byte) (*client.VerifiedExistence, error) {
+	return icm.VerifiedExistsF(ctx, key)
+}
+
 // Get ...
 func (icm *ImmuClientMock) Get(ctx context.Context, key []byte) (*schema.StructuredItem, error) {
 	return icm.GetF(ctx, key)
@@ -188,6 +283,21 @@ func (icm *ImmuClientMock) ListUsers(ctx context.Context) (*schema.UserList, err
 	return icm.ListUsersF(ctx)
 }
 
+// ListSessions ...
+func (icm *ImmuClientMock) ListSessions(ctx context.Context) (*schema.SessionList, error) {
+	return icm.ListSessionsF(ctx)
+}
+
+// GetUsage ...
+func (icm *ImmuClientMock) GetUsage(ctx context.Context, database string, since time.Time, until time.Time) (*schema.UsageReport, error) {
+	return icm.GetUsageF(ctx, database, since, until)
+}
+
+// GetAccessControlState ...
+func (icm *ImmuClientMock) GetAccessControlState(ctx context.Context) (*schema.AccessControlState, error) {
+	return icm.GetAccessControlStateF(ctx)
+}
+
 // SetActiveUser ...
 func (icm *ImmuClientMock) SetActiveUser(ctx context.Context, u *schema.SetActiveUserRequest) error {
 	return icm.SetActiveUserF(ctx, u)
@@ -199,7 +309,7 @@ func (icm *ImmuClientMock) ChangePermission(ctx context.Context, action schema.P
 }
 
 // ZScan ...
-func (icm *ImmuClientMock) ZScan(ctx context.Context, options *schema.ZScanOptions) (*schema.ZStructuredItemList, error) {
+func (icm *ImmuClientMock) ZScan(ctx context.Context, options *schema.ZScanOptions) (*client.ZStructuredItemPage, error) {
 	return icm.ZScanF(ctx, options)
 }
 
@@ -209,7 +319,7 @@ func (icm *ImmuClientMock) IScan(ctx context.Context, pageNumber uint64, pageSiz
 }
 
 // Scan ...
-func (icm *ImmuClientMock) Scan(ctx context.Context, options *schema.ScanOptions) (*schema.StructuredItemList, error) {
+func (icm *ImmuClientMock) Scan(ctx context.Context, options *schema.ScanOptions) (*client.StructuredItemPage, error) {
 	return icm.ScanF(ctx, options)
 }
 
@@ -0,0 +1,80 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/codenotary/immudb/pkg/compression"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// compressionSelector decides which calls are worth compressing. The server answers a compressed call
+// with a compressed response, so a call is compressed if either its request is at least threshold bytes
+// or the last response received for the same method was.
+type compressionSelector struct {
+	compressor string
+	threshold  int
+	// last response size of every method, so that reads of large values get compressed responses
+	responseSizes sync.Map
+}
+
+func newCompressionSelector(compressor string, threshold int) *compressionSelector {
+	return &compressionSelector{compressor: compressor, threshold: threshold}
+}
+
+func (s *compressionSelector) compress(method string, req interface{}) bool {
+	if msg, ok := req.(proto.Message); !ok || proto.Size(msg) >= s.threshold {
+		return true
+	}
+	size, ok := s.responseSizes.Load(method)
+	return ok && size.(int) >= s.threshold
+}
+
+func (s *compressionSelector) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if s.compress(method, req) {
+			opts = append(opts, grpc.UseCompressor(s.compressor))
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if msg, ok := reply.(proto.Message); ok && err == nil {
+			s.responseSizes.Store(method, proto.Size(msg))
+		}
+		return err
+	}
+}
+
+// streamInterceptor compresses every stream, as streams usually carry bulk data (e.g. dumps)
+func (s *compressionSelector) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, cc, method, append(opts, grpc.UseCompressor(s.compressor))...)
+	}
+}
+
+// compressionDialOptions returns the dial options compressing the calls according to options, if compression is enabled
+func compressionDialOptions(options *Options) []grpc.DialOption {
+	if options.Compression == compression.None {
+		return nil
+	}
+	selector := newCompressionSelector(options.Compression, options.CompressionThreshold)
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(selector.unaryInterceptor()),
+		grpc.WithChainStreamInterceptor(selector.streamInterceptor()),
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/compression"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCompressionSelector(t *testing.T) {
+	selector := newCompressionSelector(compression.Gzip, 100)
+	var compressed bool
+	invoker := func(value []byte) grpc.UnaryInvoker {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			compressed = false
+			for _, opt := range opts {
+				if c, ok := opt.(grpc.CompressorCallOption); ok && c.CompressorType == compression.Gzip {
+					compressed = true
+				}
+			}
+			reply.(*schema.Item).Value = value
+			return nil
+		}
+	}
+	interceptor := selector.unaryInterceptor()
+	large := make([]byte, 100)
+
+	require.NoError(t, interceptor(context.Background(), "/Get", &schema.Key{Key: []byte(`key`)}, &schema.Item{}, nil, invoker(nil)))
+	require.False(t, compressed)
+	require.NoError(t, interceptor(context.Background(), "/Set", &schema.KeyValue{Key: []byte(`key`), Value: large}, &schema.Item{}, nil, invoker(nil)))
+	require.True(t, compressed)
+
+	// once a large response has been received, the calls to the same method are compressed
+	require.NoError(t, interceptor(context.Background(), "/Get", &schema.Key{Key: []byte(`key`)}, &schema.Item{}, nil, invoker(large)))
+	require.False(t, compressed)
+	require.NoError(t, interceptor(context.Background(), "/Get", &schema.Key{Key: []byte(`key`)}, &schema.Item{}, nil, invoker(nil)))
+	require.True(t, compressed)
+	require.NoError(t, interceptor(context.Background(), "/Get", &schema.Key{Key: []byte(`key`)}, &schema.Item{}, nil, invoker(nil)))
+	require.False(t, compressed)
+}
+
+func TestCompressedCalls(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	for _, name := range []string{compression.Gzip, compression.Zstd} {
+		if !compression.Supported(name) {
+			t.Logf("%s compressor not available", name)
+			continue
+		}
+		opts := DefaultOptions().WithCompression(name).WithCompressionThreshold(0)
+		dialOptions := append([]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}, compressionDialOptions(opts)...)
+		conn, err := grpc.Dial("add", dialOptions...)
+		require.NoError(t, err)
+		serviceClient := schema.NewImmuServiceClient(conn)
+
+		lr, err := serviceClient.Login(context.Background(), &schema.LoginRequest{User: []byte(`immudb`), Password: []byte(`immudb`)})
+		require.NoError(t, err, name)
+		ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("authorization", lr.Token))
+		value := []byte(`{"amount":100,"currency":"EUR","description":"` + name + `"}`)
+		_, err = serviceClient.Set(ctx, &schema.KeyValue{Key: []byte(name), Value: value})
+		require.NoError(t, err, name)
+		item, err := serviceClient.Get(ctx, &schema.Key{Key: []byte(name)})
+		require.NoError(t, err, name)
+		require.Equal(t, value, item.Value)
+		require.NoError(t, conn.Close())
+	}
+
+	_, err := NewImmuClient(DefaultOptions().WithCompression("lz4"))
+	require.Error(t, err)
+}
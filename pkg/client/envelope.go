@@ -0,0 +1,220 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// ErrEnvelopeUnknownKey is returned when a value has been sealed with a key the envelope doesn't hold
+var ErrEnvelopeUnknownKey = errors.New("the value has been sealed with an unknown key")
+
+// ErrEnvelopeCorrupted is returned when a sealed value can't be opened, as when it's not sealed, it has been tampered
+// with or it has been read from another key than the one it has been written to
+var ErrEnvelopeCorrupted = errors.New("the sealed value is corrupted")
+
+const (
+	envelopeVersion = byte(1)
+	dataKeySize     = 32
+)
+
+// Envelope seals the values on the client with envelope encryption, so that they are opaque to the server: every
+// value is encrypted with its own random data key, itself encrypted (wrapped) with a key encryption key, and stored
+// along with it. The server only sees, hashes and proves the sealed values, so that the verified reads and writes keep
+// verifying exactly what has been written, while only the holders of the key encryption key can open them.
+// Values are bound to the key they are written to, so that the server can't serve a value as the one of another key.
+//
+// The key encryption keys are identified by ids stored with the values. Rotate adds a new key sealing the values from
+// then on, while the values sealed before keep being opened with the previous keys, which can be dropped once every
+// value has been sealed again with Reseal. Envelope is safe for concurrent use.
+type Envelope struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewEnvelope returns an envelope sealing the values with the key encryption key kek, identified by keyID.
+// kek must be an AES-128, AES-192 or AES-256 key, of 16, 24 or 32 bytes.
+func NewEnvelope(keyID string, kek []byte) (*Envelope, error) {
+	e := &Envelope{keys: map[string]cipher.AEAD{}}
+	if err := e.Rotate(keyID, kek); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// AddKey adds a previous key encryption key, only used to open the values it sealed
+func (e *Envelope) AddKey(keyID string, kek []byte) error {
+	return e.addKey(keyID, kek, false)
+}
+
+// Rotate adds the key encryption key kek, identified by keyID, and seals the values with it from now on
+func (e *Envelope) Rotate(keyID string, kek []byte) error {
+	return e.addKey(keyID, kek, true)
+}
+
+func (e *Envelope) addKey(keyID string, kek []byte, current bool) error {
+	if keyID == "" || len(keyID) > 255 {
+		return fmt.Errorf("%w: key ids must be 1 to 255 bytes long", ErrIllegalArguments)
+	}
+	aead, err := newAEAD(kek)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.keys[keyID]; ok {
+		return fmt.Errorf("%w: key %s already added", ErrIllegalArguments, keyID)
+	}
+	e.keys[keyID] = aead
+	if current {
+		e.current = keyID
+	}
+	return nil
+}
+
+// Seal returns value sealed with the current key encryption key, to be written to key
+func (e *Envelope) Seal(key []byte, value []byte) ([]byte, error) {
+	e.mu.RLock()
+	keyID, kek := e.current, e.keys[e.current]
+	e.mu.RUnlock()
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := append([]byte{envelopeVersion, byte(len(keyID))}, keyID...)
+	header := len(sealed)
+	if sealed, err = seal(kek, sealed, dataKey, envelopeAAD(sealed, key)); err != nil {
+		return nil, err
+	}
+	return seal(aead, sealed, value, envelopeAAD(sealed[:header], key))
+}
+
+// Open returns the value sealed by Seal, read from key
+func (e *Envelope) Open(key []byte, sealed []byte) ([]byte, error) {
+	if len(sealed) < 2 || sealed[0] != envelopeVersion {
+		return nil, ErrEnvelopeCorrupted
+	}
+	header := 2 + int(sealed[1])
+	if len(sealed) < header {
+		return nil, ErrEnvelopeCorrupted
+	}
+	e.mu.RLock()
+	kek, ok := e.keys[string(sealed[2:header])]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, ErrEnvelopeUnknownKey
+	}
+
+	aad := envelopeAAD(sealed[:header], key)
+	dataKey, rest, err := open(kek, sealed[header:], dataKeySize+kek.Overhead(), aad)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, ErrEnvelopeCorrupted
+	}
+	value, _, err := open(aead, rest, len(rest)-aead.NonceSize(), aad)
+	return value, err
+}
+
+// Reseal returns the value sealed by Seal, read from key, sealed again with the current key encryption key
+func (e *Envelope) Reseal(key []byte, sealed []byte) ([]byte, error) {
+	value, err := e.Open(key, sealed)
+	if err != nil {
+		return nil, err
+	}
+	return e.Seal(key, value)
+}
+
+// SafeSet seals value and writes it to key with c.SafeSet, verifying its inclusion
+func (e *Envelope) SafeSet(ctx context.Context, c ImmuClient, key []byte, value []byte) (*VerifiedIndex, error) {
+	sealed, err := e.Seal(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return c.SafeSet(ctx, key, sealed)
+}
+
+// SafeGet reads key with c.SafeGet, verifying its inclusion, and returns the item with its value opened.
+// The proofs are verified on the sealed value, as the server only knows it.
+func (e *Envelope) SafeGet(ctx context.Context, c ImmuClient, key []byte, opts ...grpc.CallOption) (*VerifiedItem, error) {
+	vi, err := c.SafeGet(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	value, err := e.Open(vi.Key, vi.Value)
+	if err != nil {
+		return nil, err
+	}
+	opened := *vi
+	opened.Value = value
+	return &opened, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIllegalArguments, err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// envelopeAAD is the data authenticated along with the sealed data key and value: the header of the envelope,
+// so that the key id can't be altered, and the key the value is written to
+func envelopeAAD(header []byte, key []byte) []byte {
+	return append(append([]byte{}, header...), key...)
+}
+
+// seal appends to dst a random nonce followed by plaintext encrypted and authenticated with aead
+func seal(aead cipher.AEAD, dst []byte, plaintext []byte, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	dst = append(dst, nonce...)
+	return aead.Seal(dst, nonce, plaintext, aad), nil
+}
+
+// open decrypts the ciphertext of size bytes following the nonce at the beginning of src, returning the plaintext
+// and what follows the ciphertext
+func open(aead cipher.AEAD, src []byte, size int, aad []byte) ([]byte, []byte, error) {
+	n := aead.NonceSize()
+	if size < aead.Overhead() || len(src) < n+size {
+		return nil, nil, ErrEnvelopeCorrupted
+	}
+	plaintext, err := aead.Open(nil, src[:n], src[n:n+size], aad)
+	if err != nil {
+		return nil, nil, ErrEnvelopeCorrupted
+	}
+	return plaintext, src[n+size:], nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestEnvelope(t *testing.T) {
+	kek1, kek2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 16)
+	e, err := NewEnvelope("k1", kek1)
+	require.NoError(t, err)
+
+	_, err = NewEnvelope("k1", []byte(`short`))
+	require.True(t, errors.Is(err, ErrIllegalArguments))
+	require.True(t, errors.Is(e.AddKey("", kek2), ErrIllegalArguments))
+	require.True(t, errors.Is(e.AddKey("k1", kek2), ErrIllegalArguments))
+
+	sealed, err := e.Seal([]byte(`key`), []byte(`secret`))
+	require.NoError(t, err)
+	require.False(t, bytes.Contains(sealed, []byte(`secret`)))
+	value, err := e.Open([]byte(`key`), sealed)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`secret`), value)
+
+	empty, err := e.Seal([]byte(`key`), nil)
+	require.NoError(t, err)
+	value, err = e.Open([]byte(`key`), empty)
+	require.NoError(t, err)
+	require.Empty(t, value)
+
+	// sealed values are bound to their key and can't be altered
+	_, err = e.Open([]byte(`other`), sealed)
+	require.Equal(t, ErrEnvelopeCorrupted, err)
+	for i := range sealed {
+		tampered := append([]byte{}, sealed...)
+		tampered[i] ^= 1
+		_, err = e.Open([]byte(`key`), tampered)
+		require.Error(t, err, i)
+	}
+	_, err = e.Open([]byte(`key`), sealed[:len(sealed)-1])
+	require.Equal(t, ErrEnvelopeCorrupted, err)
+	_, err = e.Open([]byte(`key`), []byte(`secret`))
+	require.Equal(t, ErrEnvelopeCorrupted, err)
+
+	// values sealed before a rotation are still opened, and can be sealed again with the new key
+	require.NoError(t, e.Rotate("k2", kek2))
+	value, err = e.Open([]byte(`key`), sealed)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`secret`), value)
+	resealed, err := e.Reseal([]byte(`key`), sealed)
+	require.NoError(t, err)
+
+	rotated, err := NewEnvelope("k2", kek2)
+	require.NoError(t, err)
+	_, err = rotated.Open([]byte(`key`), sealed)
+	require.Equal(t, ErrEnvelopeUnknownKey, err)
+	value, err = rotated.Open([]byte(`key`), resealed)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`secret`), value)
+}
+
+func TestEnvelopeSafeSetGet(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	dir, err := ioutil.TempDir("", "envelope")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(NewHomedirService())
+	c, err := NewImmuClient(DefaultOptions().WithDir(dir).WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}).WithTokenService(ts))
+	require.NoError(t, err)
+	defer c.Disconnect()
+	lr, err := c.Login(context.TODO(), []byte(`immudb`), []byte(`immudb`))
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lr.Token))
+
+	e, err := NewEnvelope("k1", bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	vi, err := e.SafeSet(ctx, c, []byte(`patient`), []byte(`diagnosis`))
+	require.NoError(t, err)
+	require.True(t, vi.Verified)
+
+	item, err := e.SafeGet(ctx, c, []byte(`patient`))
+	require.NoError(t, err)
+	require.True(t, item.Verified)
+	require.Equal(t, []byte(`diagnosis`), item.Value)
+
+	// the server only knows the sealed value
+	raw, err := c.SafeGet(ctx, []byte(`patient`))
+	require.NoError(t, err)
+	require.True(t, raw.Verified)
+	require.NotEqual(t, []byte(`diagnosis`), raw.Value)
+	require.Equal(t, raw.Index, item.Index)
+
+	_, err = c.SafeSet(ctx, []byte(`plain`), []byte(`value`))
+	require.NoError(t, err)
+	_, err = e.SafeGet(ctx, c, []byte(`plain`))
+	require.Equal(t, ErrEnvelopeCorrupted, err)
+}
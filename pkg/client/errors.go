@@ -28,3 +28,7 @@ var (
 	ErrNotConnected      = errors.New("not connected")
 	ErrHealthCheckFailed = errors.New("health check failed")
 )
+
+// ErrReferenceChainMismatch is returned when the hops of a reference resolution don't match, as when the reference
+// is updated while being resolved
+var ErrReferenceChainMismatch = errors.New("reference chain mismatch: the resolved entries are not linked to each other")
@@ -0,0 +1,688 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpclient provides a lightweight immudb client talking to the immugw REST proxy.
+// It is meant for environments in which gRPC egress is blocked: proofs returned by the gateway
+// are still verified locally against the cached trusted root, like the gRPC client does.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/client/timestamp"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// ImmuClient is the subset of the gRPC client interface that can be served over the REST proxy.
+// Streaming operations are not available.
+type ImmuClient interface {
+	Login(ctx context.Context, user []byte, pass []byte) (*schema.LoginResponse, error)
+	Logout(ctx context.Context) error
+	UseDatabase(ctx context.Context, d *schema.Database) (*schema.UseDatabaseReply, error)
+	HealthCheck(ctx context.Context) error
+	CurrentRoot(ctx context.Context) (*schema.Root, error)
+	Set(ctx context.Context, key []byte, value []byte) (*schema.Index, error)
+	SafeSet(ctx context.Context, key []byte, value []byte) (*client.VerifiedIndex, error)
+	Get(ctx context.Context, key []byte) (*schema.StructuredItem, error)
+	SafeGet(ctx context.Context, key []byte) (*client.VerifiedItem, error)
+	Scan(ctx context.Context, options *schema.ScanOptions) (*schema.StructuredItemList, error)
+	ZScan(ctx context.Context, options *schema.ZScanOptions) (*schema.ZStructuredItemList, error)
+	ByIndex(ctx context.Context, index uint64) (*schema.StructuredItem, error)
+	IScan(ctx context.Context, pageNumber uint64, pageSize uint64) (*schema.SPage, error)
+	Count(ctx context.Context, prefix []byte) (*schema.ItemsCount, error)
+	CountAll(ctx context.Context) (*schema.ItemsCount, error)
+	SetAll(ctx context.Context, kvList *schema.KVList) (*schema.Index, error)
+	GetBatch(ctx context.Context, keys [][]byte) (*schema.StructuredItemList, error)
+	Inclusion(ctx context.Context, index uint64) (*schema.InclusionProof, error)
+	Consistency(ctx context.Context, index uint64) (*schema.ConsistencyProof, error)
+	History(ctx context.Context, options *schema.HistoryOptions) (*schema.StructuredItemList, error)
+	Reference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*schema.Index, error)
+	GetReference(ctx context.Context, key *schema.Key) (*schema.StructuredItem, error)
+	SafeReference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*client.VerifiedIndex, error)
+	ZAdd(ctx context.Context, set []byte, score float64, key []byte, index *schema.Index) (*schema.Index, error)
+	SafeZAdd(ctx context.Context, set []byte, score float64, key []byte, index *schema.Index) (*client.VerifiedIndex, error)
+}
+
+type immuHTTPClient struct {
+	Logger      logger.Logger
+	Options     *Options
+	httpClient  *http.Client
+	Rootservice rootservice.RootService
+	ts          client.TimestampService
+	token       string
+	sync.RWMutex
+}
+
+// NewImmuHTTPClient creates a new REST client and loads the trusted root of the server behind the gateway
+func NewImmuHTTPClient(options *Options) (ImmuClient, error) {
+	c := &immuHTTPClient{
+		Logger:     logger.NewSimpleLogger("immuhttpclient", os.Stderr),
+		Options:    options,
+		httpClient: options.HTTPClient,
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: options.Timeout}
+	}
+
+	rootService, err := rootservice.NewRootService(cache.NewFileCache(options.Dir), c.Logger, httpRootProvider{c}, httpUUIDProvider{c})
+	if err != nil {
+		return nil, err
+	}
+
+	dt, err := timestamp.NewDefaultTimestamp()
+	if err != nil {
+		return nil, err
+	}
+	c.ts = client.NewTimestampService(dt)
+	c.Rootservice = rootService
+
+	return c, nil
+}
+
+func (c *immuHTTPClient) getToken() string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.token
+}
+
+func (c *immuHTTPClient) setToken(token string) {
+	c.Lock()
+	defer c.Unlock()
+	c.token = token
+}
+
+// encodeBytes encodes a binary path parameter the way the gateway decodes it
+func encodeBytes(b []byte) string {
+	return base64.URLEncoding.EncodeToString(b)
+}
+
+func (c *immuHTTPClient) newSKV(key []byte, value []byte) *schema.StructuredKeyValue {
+	return &schema.StructuredKeyValue{
+		Key: key,
+		Value: &schema.Content{
+			Timestamp: uint64(c.ts.GetTime().Unix()),
+			Payload:   value,
+		},
+	}
+}
+
+// Login ...
+func (c *immuHTTPClient) Login(ctx context.Context, user []byte, pass []byte) (*schema.LoginResponse, error) {
+	start := time.Now()
+
+	result := &schema.LoginResponse{}
+	if _, err := c.do(ctx, http.MethodPost, "/login", &schema.LoginRequest{User: user, Password: pass}, result); err != nil {
+		return nil, err
+	}
+	c.setToken(string(result.GetToken()))
+
+	c.Logger.Debugf("login finished in %s", time.Since(start))
+
+	return result, nil
+}
+
+// Logout ...
+func (c *immuHTTPClient) Logout(ctx context.Context) error {
+	start := time.Now()
+
+	_, err := c.do(ctx, http.MethodPost, "/logout", new(empty.Empty), new(empty.Empty))
+	c.setToken("")
+
+	c.Logger.Debugf("logout finished in %s", time.Since(start))
+
+	return err
+}
+
+// UseDatabase selects the database used by all following calls
+func (c *immuHTTPClient) UseDatabase(ctx context.Context, d *schema.Database) (*schema.UseDatabaseReply, error) {
+	start := time.Now()
+
+	result := &schema.UseDatabaseReply{}
+	if _, err := c.do(ctx, http.MethodGet, "/usedatabase/"+d.Databasename, nil, result); err != nil {
+		return nil, err
+	}
+	c.setToken(result.Token)
+
+	c.Lock()
+	c.Options.CurrentDatabase = d.Databasename
+	c.Unlock()
+
+	c.Logger.Debugf("UseDatabase finished in %s", time.Since(start))
+
+	return result, nil
+}
+
+// HealthCheck ...
+func (c *immuHTTPClient) HealthCheck(ctx context.Context) error {
+	start := time.Now()
+
+	response := &schema.HealthResponse{}
+	if _, err := c.do(ctx, http.MethodGet, "/healthresponse", nil, response); err != nil {
+		return err
+	}
+	if !response.Status {
+		return client.ErrHealthCheckFailed
+	}
+
+	c.Logger.Debugf("health-check finished in %s", time.Since(start))
+
+	return nil
+}
+
+// CurrentRoot returns current merkle tree root and index
+func (c *immuHTTPClient) CurrentRoot(ctx context.Context) (*schema.Root, error) {
+	start := time.Now()
+
+	root, err := httpRootProvider{c}.CurrentRoot(ctx)
+
+	c.Logger.Debugf("Current root finished in %s", time.Since(start))
+
+	return root, err
+}
+
+// Set ...
+func (c *immuHTTPClient) Set(ctx context.Context, key []byte, value []byte) (*schema.Index, error) {
+	start := time.Now()
+
+	kv, err := c.newSKV(key, value).ToKV()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &schema.Index{}
+	if _, err := c.do(ctx, http.MethodPost, "/item", kv, result); err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("set finished in %s", time.Since(start))
+
+	return result, nil
+}
+
+// SafeSet ...
+func (c *immuHTTPClient) SafeSet(ctx context.Context, key []byte, value []byte) (*client.VerifiedIndex, error) {
+	start := time.Now()
+
+	root, err := c.Rootservice.GetRoot(ctx, c.currentDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	skv := c.newSKV(key, value)
+	kv, err := skv.ToKV()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &schema.SafeSetOptions{
+		Kv: kv,
+		RootIndex: &schema.Index{
+			Index: root.GetIndex(),
+		},
+	}
+
+	result := &schema.Proof{}
+	if _, err := c.do(ctx, http.MethodPost, "/item/safe", opts, result); err != nil {
+		return nil, err
+	}
+
+	// This guard ensures that result.Leaf is equal to the item's hash computed from
+	// request values. From now on, result.Leaf can be trusted.
+	sitem := schema.StructuredItem{
+		Key:   key,
+		Value: skv.Value,
+		Index: result.Index,
+	}
+	item, err := sitem.ToItem()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(item.Hash(), result.Leaf) {
+		return nil, errors.New("proof does not match the given item")
+	}
+
+	verified, err := c.verifyAndSetRoot(result, root)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("safeset finished in %s", time.Since(start))
+
+	return &client.VerifiedIndex{
+			Index:    result.Index,
+			Verified: verified,
+		},
+		nil
+}
+
+// Get ...
+func (c *immuHTTPClient) Get(ctx context.Context, key []byte) (*schema.StructuredItem, error) {
+	start := time.Now()
+
+	item := &schema.Item{}
+	if _, err := c.do(ctx, http.MethodGet, "/item/"+encodeBytes(key), nil, item); err != nil {
+		return nil, err
+	}
+
+	result, err := item.ToSItem()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("get finished in %s", time.Since(start))
+
+	return result, nil
+}
+
+// SafeGet ...
+func (c *immuHTTPClient) SafeGet(ctx context.Context, key []byte) (*client.VerifiedItem, error) {
+	start := time.Now()
+
+	root, err := c.Rootservice.GetRoot(ctx, c.currentDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	sgOpts := &schema.SafeGetOptions{
+		Key: key,
+		RootIndex: &schema.Index{
+			Index: root.GetIndex(),
+		},
+	}
+
+	safeItem := &schema.SafeItem{}
+	if _, err := c.do(ctx, http.MethodPost, "/item/safe/get", sgOpts, safeItem); err != nil {
+		return nil, err
+	}
+	if safeItem.Item == nil || safeItem.Proof == nil {
+		return nil, errors.New("incomplete safe item received from the gateway")
+	}
+	if !bytes.Equal(safeItem.Item.Key, key) {
+		return nil, errors.New("safe item of another key received from the gateway")
+	}
+
+	h, err := safeItem.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	verified := safeItem.Proof.Verify(h, *root)
+	if verified {
+		// saving a fresh root
+		tocache := schema.NewRoot()
+		tocache.SetIndex(safeItem.Proof.At)
+		tocache.SetRoot(safeItem.Proof.Root)
+		if err := c.Rootservice.SetRoot(tocache, c.currentDatabase()); err != nil {
+			return nil, err
+		}
+	}
+
+	c.Logger.Debugf("safeget finished in %s", time.Since(start))
+
+	sitem, err := safeItem.ToSafeSItem()
+	if err != nil {
+		return nil, err
+	}
+
+	return &client.VerifiedItem{
+			Key:      sitem.Item.GetKey(),
+			Value:    sitem.Item.Value.Payload,
+			Index:    sitem.Item.GetIndex(),
+			Time:     sitem.Item.Value.Timestamp,
+			Verified: verified,
+		},
+		nil
+}
+
+// Scan ...
+func (c *immuHTTPClient) Scan(ctx context.Context, options *schema.ScanOptions) (*schema.StructuredItemList, error) {
+	list := &schema.ItemList{}
+	if _, err := c.do(ctx, http.MethodPost, "/item/scan", options, list); err != nil {
+		return nil, err
+	}
+	return list.ToSItemList()
+}
+
+// ZScan ...
+func (c *immuHTTPClient) ZScan(ctx context.Context, options *schema.ZScanOptions) (*schema.ZStructuredItemList, error) {
+	list := &schema.ZItemList{}
+	if _, err := c.do(ctx, http.MethodPost, "/zscan", options, list); err != nil {
+		return nil, err
+	}
+	return list.ToZSItemList()
+}
+
+// ByIndex returns the item stored at the given index
+func (c *immuHTTPClient) ByIndex(ctx context.Context, index uint64) (*schema.StructuredItem, error) {
+	start := time.Now()
+
+	item := &schema.Item{}
+	if _, err := c.do(ctx, http.MethodGet, "/item/index/"+strconv.FormatUint(index, 10), nil, item); err != nil {
+		return nil, err
+	}
+
+	result, err := item.ToSItem()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("by-index finished in %s", time.Since(start))
+
+	return result, nil
+}
+
+// IScan iterates over all elements by insertion order
+func (c *immuHTTPClient) IScan(ctx context.Context, pageNumber uint64, pageSize uint64) (*schema.SPage, error) {
+	page := &schema.Page{}
+	if _, err := c.do(ctx, http.MethodPost, "/iscan", &schema.IScanOptions{PageSize: pageSize, PageNumber: pageNumber}, page); err != nil {
+		return nil, err
+	}
+	return page.ToSPage()
+}
+
+// Count returns count of key prefix
+func (c *immuHTTPClient) Count(ctx context.Context, prefix []byte) (*schema.ItemsCount, error) {
+	result := &schema.ItemsCount{}
+	if _, err := c.do(ctx, http.MethodGet, "/item/count/"+encodeBytes(prefix), nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CountAll returns the total count of entries
+func (c *immuHTTPClient) CountAll(ctx context.Context) (*schema.ItemsCount, error) {
+	result := &schema.ItemsCount{}
+	if _, err := c.do(ctx, http.MethodGet, "/item/countall", nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetAll ...
+func (c *immuHTTPClient) SetAll(ctx context.Context, kvList *schema.KVList) (*schema.Index, error) {
+	if kvList == nil {
+		return nil, client.ErrIllegalArguments
+	}
+
+	slist := &schema.SKVList{}
+	for _, kv := range kvList.KVs {
+		slist.SKVs = append(slist.SKVs, c.newSKV(kv.Key, kv.Value))
+	}
+	svlist, err := slist.ToKVList()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &schema.Index{}
+	if _, err := c.do(ctx, http.MethodPost, "/batch/set", svlist, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetBatch ...
+func (c *immuHTTPClient) GetBatch(ctx context.Context, keys [][]byte) (*schema.StructuredItemList, error) {
+	start := time.Now()
+
+	keyList := &schema.KeyList{}
+	for _, key := range keys {
+		keyList.Keys = append(keyList.Keys, &schema.Key{Key: key})
+	}
+
+	list := &schema.ItemList{}
+	_, err := c.do(ctx, http.MethodPost, "/batch/get", keyList, list)
+
+	c.Logger.Debugf("get-batch finished in %s", time.Since(start))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return list.ToSItemList()
+}
+
+// Inclusion ...
+func (c *immuHTTPClient) Inclusion(ctx context.Context, index uint64) (*schema.InclusionProof, error) {
+	start := time.Now()
+
+	result := &schema.InclusionProof{}
+	_, err := c.do(ctx, http.MethodGet, "/inclusionproof/"+strconv.FormatUint(index, 10), nil, result)
+
+	c.Logger.Debugf("inclusion finished in %s", time.Since(start))
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Consistency ...
+func (c *immuHTTPClient) Consistency(ctx context.Context, index uint64) (*schema.ConsistencyProof, error) {
+	start := time.Now()
+
+	result := &schema.ConsistencyProof{}
+	_, err := c.do(ctx, http.MethodGet, "/consistencyproof/"+strconv.FormatUint(index, 10), nil, result)
+
+	c.Logger.Debugf("consistency finished in %s", time.Since(start))
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// History ...
+func (c *immuHTTPClient) History(ctx context.Context, options *schema.HistoryOptions) (*schema.StructuredItemList, error) {
+	start := time.Now()
+
+	list := &schema.ItemList{}
+	if _, err := c.do(ctx, http.MethodPost, "/history", options, list); err != nil {
+		return nil, err
+	}
+
+	sl, err := list.ToSItemList()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("history finished in %s", time.Since(start))
+
+	return sl, nil
+}
+
+// Reference ...
+func (c *immuHTTPClient) Reference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*schema.Index, error) {
+	start := time.Now()
+
+	result := &schema.Index{}
+	_, err := c.do(ctx, http.MethodPost, "/reference", &schema.ReferenceOptions{
+		Reference: reference,
+		Key:       key,
+		Index:     index,
+	}, result)
+
+	c.Logger.Debugf("reference finished in %s", time.Since(start))
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetReference ...
+func (c *immuHTTPClient) GetReference(ctx context.Context, key *schema.Key) (*schema.StructuredItem, error) {
+	item := &schema.Item{}
+	if _, err := c.do(ctx, http.MethodGet, "/reference/"+encodeBytes(key.GetKey()), nil, item); err != nil {
+		return nil, err
+	}
+	return item.ToSItem()
+}
+
+// SafeReference ...
+func (c *immuHTTPClient) SafeReference(ctx context.Context, reference []byte, key []byte, index *schema.Index) (*client.VerifiedIndex, error) {
+	start := time.Now()
+
+	root, err := c.Rootservice.GetRoot(ctx, c.currentDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &schema.SafeReferenceOptions{
+		Ro: &schema.ReferenceOptions{
+			Reference: reference,
+			Key:       key,
+			Index:     index,
+		},
+		RootIndex: &schema.Index{
+			Index: root.GetIndex(),
+		},
+	}
+
+	result := &schema.Proof{}
+	if _, err := c.do(ctx, http.MethodPost, "/safe/reference", opts, result); err != nil {
+		return nil, err
+	}
+
+	// This guard ensures that result.Leaf is equal to the item's hash computed
+	// from request values. From now on, result.Leaf can be trusted.
+	item := schema.Item{
+		Key:   reference,
+		Value: store.WrapZIndexReference(key, index),
+		Index: result.Index,
+	}
+	if !bytes.Equal(item.Hash(), result.Leaf) {
+		return nil, errors.New("proof does not match the given item")
+	}
+
+	verified, err := c.verifyAndSetRoot(result, root)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("safereference finished in %s", time.Since(start))
+
+	return &client.VerifiedIndex{
+			Index:    result.Index,
+			Verified: verified,
+		},
+		nil
+}
+
+// ZAdd ...
+func (c *immuHTTPClient) ZAdd(ctx context.Context, set []byte, score float64, key []byte, index *schema.Index) (*schema.Index, error) {
+	start := time.Now()
+
+	result := &schema.Index{}
+	_, err := c.do(ctx, http.MethodPost, "/zadd", &schema.ZAddOptions{
+		Set:   set,
+		Score: &schema.Score{Score: score},
+		Key:   key,
+		Index: index,
+	}, result)
+
+	c.Logger.Debugf("zadd finished in %s", time.Since(start))
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SafeZAdd ...
+func (c *immuHTTPClient) SafeZAdd(ctx context.Context, set []byte, score float64, key []byte, index *schema.Index) (*client.VerifiedIndex, error) {
+	start := time.Now()
+
+	root, err := c.Rootservice.GetRoot(ctx, c.currentDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &schema.SafeZAddOptions{
+		Zopts: &schema.ZAddOptions{
+			Set:   set,
+			Score: &schema.Score{Score: score},
+			Key:   key,
+			Index: index,
+		},
+		RootIndex: &schema.Index{
+			Index: root.GetIndex(),
+		},
+	}
+
+	result := &schema.Proof{}
+	if _, err := c.do(ctx, http.MethodPost, "/safe/zadd", opts, result); err != nil {
+		return nil, err
+	}
+
+	// This guard ensures that result.Leaf is equal to the item's hash computed
+	// from request values. From now on, result.Leaf can be trusted.
+	item := schema.Item{
+		Key:   store.BuildSetKey(key, set, score, index),
+		Value: store.WrapZIndexReference(key, index),
+		Index: result.Index,
+	}
+	if !bytes.Equal(item.Hash(), result.Leaf) {
+		return nil, errors.New("proof does not match the given item")
+	}
+
+	verified, err := c.verifyAndSetRoot(result, root)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("safezadd finished in %s", time.Since(start))
+
+	return &client.VerifiedIndex{
+			Index:    result.Index,
+			Verified: verified,
+		},
+		nil
+}
+
+func (c *immuHTTPClient) currentDatabase() string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.Options.CurrentDatabase
+}
+
+func (c *immuHTTPClient) verifyAndSetRoot(result *schema.Proof, root *schema.Root) (bool, error) {
+	verified := result.Verify(result.Leaf, *root)
+	var err error
+
+	if verified {
+		//saving a fresh root
+		tocache := schema.NewRoot()
+		tocache.SetIndex(result.Index)
+		tocache.SetRoot(result.Root)
+		err = c.Rootservice.SetRoot(tocache, c.currentDatabase())
+	}
+
+	return verified, err
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const adminPassword = "non-default-admin-password"
+
+// newGateway starts a gateway in front of a new server, its handler wrapped by the provided middlewares if any
+func newGateway(t *testing.T, middlewares ...func(http.Handler) http.Handler) (*Options, func()) {
+	options := server.DefaultOptions().WithAuth(true).WithInMemoryStore(true).WithCorruptionCheck(false).WithAdminPassword(adminPassword)
+	bs := servertest.NewBufconnServer(options)
+	bs.Start()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure())
+	require.NoError(t, err)
+
+	mux := runtime.NewServeMux()
+	require.NoError(t, schema.RegisterImmuServiceHandler(context.Background(), mux, conn))
+	var handler http.Handler = mux
+	for _, m := range middlewares {
+		handler = m(handler)
+	}
+	gw := httptest.NewServer(handler)
+
+	u, err := url.Parse(gw.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "httpclient")
+	require.NoError(t, err)
+
+	return DefaultOptions().WithDir(dir).WithAddress(u.Hostname()).WithPort(port), func() {
+		gw.Close()
+		conn.Close()
+		bs.GrpcServer.Stop()
+		bs.Server.CloseDatabases()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestImmuHTTPClient(t *testing.T) {
+	options, closer := newGateway(t)
+	defer closer()
+
+	ctx := context.Background()
+	cli, err := NewImmuHTTPClient(options)
+	require.NoError(t, err)
+
+	_, err = cli.Set(ctx, []byte("key"), []byte("value"))
+	assert.Error(t, err)
+
+	_, err = cli.Login(ctx, []byte("immudb"), []byte(adminPassword))
+	require.NoError(t, err)
+	_, err = cli.UseDatabase(ctx, &schema.Database{Databasename: server.DefaultdbName})
+	require.NoError(t, err)
+
+	require.NoError(t, cli.HealthCheck(ctx))
+
+	idx, err := cli.Set(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	item, err := cli.Get(ctx, []byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), item.Value.Payload)
+	assert.Equal(t, idx.Index, item.Index)
+
+	vi, err := cli.SafeSet(ctx, []byte("safe/key"), []byte("safe value"))
+	require.NoError(t, err)
+	assert.True(t, vi.Verified)
+
+	vitem, err := cli.SafeGet(ctx, []byte("safe/key"))
+	require.NoError(t, err)
+	assert.True(t, vitem.Verified)
+	assert.Equal(t, []byte("safe value"), vitem.Value)
+
+	vi, err = cli.SafeReference(ctx, []byte("ref"), []byte("key"), nil)
+	require.NoError(t, err)
+	assert.True(t, vi.Verified)
+
+	ref, err := cli.GetReference(ctx, &schema.Key{Key: []byte("ref")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), ref.Value.Payload)
+
+	vi, err = cli.SafeZAdd(ctx, []byte("set"), 1, []byte("key"), nil)
+	require.NoError(t, err)
+	assert.True(t, vi.Verified)
+
+	zlist, err := cli.ZScan(ctx, &schema.ZScanOptions{Set: []byte("set")})
+	require.NoError(t, err)
+	require.Len(t, zlist.Items, 1)
+
+	_, err = cli.SetAll(ctx, &schema.KVList{KVs: []*schema.KeyValue{
+		{Key: []byte("batch1"), Value: []byte("value1")},
+		{Key: []byte("batch2"), Value: []byte("value2")},
+	}})
+	require.NoError(t, err)
+
+	list, err := cli.GetBatch(ctx, [][]byte{[]byte("batch1"), []byte("batch2")})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+
+	count, err := cli.Count(ctx, []byte("batch1"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count.Count)
+
+	list, err = cli.Scan(ctx, &schema.ScanOptions{Prefix: []byte("batch")})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+
+	list, err = cli.History(ctx, &schema.HistoryOptions{Key: []byte("key")})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+
+	item, err = cli.ByIndex(ctx, idx.Index)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key"), item.Key)
+
+	root, err := cli.CurrentRoot(ctx)
+	require.NoError(t, err)
+
+	inclusion, err := cli.Inclusion(ctx, idx.Index)
+	require.NoError(t, err)
+	assert.Equal(t, root.GetIndex(), inclusion.At)
+
+	consistency, err := cli.Consistency(ctx, idx.Index)
+	require.NoError(t, err)
+	assert.Equal(t, idx.Index, consistency.First)
+
+	_, err = cli.Get(ctx, []byte("missing"))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	require.NoError(t, cli.Logout(ctx))
+}
+
+// redirectingGateway serves the safe reads of any key with the proven item of key
+func redirectingGateway(key []byte) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/immurestproxy/item/safe/get" {
+				var body map[string]interface{}
+				if err := json.NewDecoder(r.Body).Decode(&body); err == nil {
+					body["key"] = base64.StdEncoding.EncodeToString(key)
+					forged, _ := json.Marshal(body)
+					r.Body = ioutil.NopCloser(bytes.NewReader(forged))
+					r.ContentLength = int64(len(forged))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestImmuHTTPClientSafeGetOfAnotherKey(t *testing.T) {
+	options, closer := newGateway(t, redirectingGateway([]byte("other")))
+	defer closer()
+
+	ctx := context.Background()
+	cli, err := NewImmuHTTPClient(options)
+	require.NoError(t, err)
+	_, err = cli.Login(ctx, []byte("immudb"), []byte(adminPassword))
+	require.NoError(t, err)
+	_, err = cli.UseDatabase(ctx, &schema.Database{Databasename: server.DefaultdbName})
+	require.NoError(t, err)
+
+	_, err = cli.Set(ctx, []byte("other"), []byte("other value"))
+	require.NoError(t, err)
+	_, err = cli.Set(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	vitem, err := cli.SafeGet(ctx, []byte("other"))
+	require.NoError(t, err)
+	assert.True(t, vitem.Verified)
+
+	_, err = cli.SafeGet(ctx, []byte("key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Options http client options
+type Options struct {
+	Dir             string
+	Address         string
+	Port            int
+	Scheme          string
+	Timeout         time.Duration
+	CurrentDatabase string
+	HTTPClient      *http.Client `json:"-"`
+}
+
+// DefaultOptions ...
+func DefaultOptions() *Options {
+	return &Options{
+		Dir:     ".",
+		Address: "127.0.0.1",
+		Port:    3323,
+		Scheme:  "http",
+		Timeout: 30 * time.Second,
+	}
+}
+
+// WithDir sets the folder in which verified roots are cached
+func (o *Options) WithDir(dir string) *Options {
+	o.Dir = dir
+	return o
+}
+
+// WithAddress sets the gateway address
+func (o *Options) WithAddress(address string) *Options {
+	o.Address = address
+	return o
+}
+
+// WithPort sets the gateway port
+func (o *Options) WithPort(port int) *Options {
+	o.Port = port
+	return o
+}
+
+// WithScheme sets the URL scheme, http or https
+func (o *Options) WithScheme(scheme string) *Options {
+	o.Scheme = scheme
+	return o
+}
+
+// WithTimeout sets the timeout of every request
+func (o *Options) WithTimeout(timeout time.Duration) *Options {
+	o.Timeout = timeout
+	return o
+}
+
+// WithHTTPClient sets the underlying http client, e.g. to provide custom TLS settings or proxies.
+// If set, Timeout is ignored
+func (o *Options) WithHTTPClient(httpClient *http.Client) *Options {
+	o.HTTPClient = httpClient
+	return o
+}
+
+// BaseURL returns the URL of the gateway REST proxy
+func (o *Options) BaseURL() string {
+	return o.Scheme + "://" + o.Address + ":" + strconv.Itoa(o.Port) + "/v1/immurestproxy"
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/rootservice"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var marshaler = jsonpb.Marshaler{OrigName: true}
+var unmarshaler = jsonpb.Unmarshaler{AllowUnknownFields: true}
+
+// errorBody is the JSON error returned by the gateway for a failed call
+type errorBody struct {
+	Error   string `json:"error"`
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+// do sends the JSON encoded in message to the gateway and decodes the response in out.
+// Errors returned by the server are converted back to gRPC status errors, so that callers can
+// handle them exactly as the ones returned by the gRPC client.
+func (c *immuHTTPClient) do(ctx context.Context, method string, path string, in proto.Message, out proto.Message) (http.Header, error) {
+	var body io.Reader
+	if in != nil {
+		var buf bytes.Buffer
+		if err := marshaler.Marshal(&buf, in); err != nil {
+			return nil, err
+		}
+		body = &buf
+	}
+
+	req, err := http.NewRequest(method, c.Options.BaseURL()+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if token := c.getToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		var eb errorBody
+		if err := json.Unmarshal(b, &eb); err != nil || eb.Code == 0 {
+			return nil, fmt.Errorf("unexpected response from %s: %s", path, resp.Status)
+		}
+		return nil, status.Error(codes.Code(eb.Code), eb.Message)
+	}
+
+	if out != nil {
+		if err := unmarshaler.Unmarshal(resp.Body, out); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Header, nil
+}
+
+type httpRootProvider struct {
+	c *immuHTTPClient
+}
+
+func (r httpRootProvider) CurrentRoot(ctx context.Context) (*schema.Root, error) {
+	root := &schema.Root{}
+	if _, err := r.c.do(ctx, http.MethodGet, "/root", nil, root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type httpUUIDProvider struct {
+	c *immuHTTPClient
+}
+
+// CurrentUUID issues a Health command to the gateway, then parses and returns
+// the server UUID from the forwarded response metadata
+func (r httpUUIDProvider) CurrentUUID(ctx context.Context) (string, error) {
+	header, err := r.c.do(ctx, http.MethodGet, "/healthresponse", nil, &schema.HealthResponse{})
+	if err != nil {
+		return "", err
+	}
+	serverUUID := header.Get(runtime.MetadataHeaderPrefix + server.SERVER_UUID_HEADER)
+	if serverUUID == "" {
+		return "", rootservice.ErrNoServerUuid
+	}
+	return serverUUID, nil
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/codenotary/immudb/pkg/server"
+	"google.golang.org/grpc/metadata"
+)
+
+// WithIdempotencyKey returns a context that makes Set and SafeSet idempotent: retrying a write with the same key
+// returns the index of the original entry instead of appending a duplicate.
+// Keys are remembered by the server for a limited time only.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, server.IDEMPOTENCY_KEY_HEADER, key)
+}
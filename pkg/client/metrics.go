@@ -0,0 +1,123 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var metricsNamespace = "immuclient"
+
+// clientMetrics client-side verification Prometheus metrics.
+// A nil *clientMetrics is valid and does not record anything.
+type clientMetrics struct {
+	verifications        prometheus.Counter
+	verificationFailures prometheus.Counter
+	signatureMismatches  prometheus.Counter
+	rootCacheMisses      prometheus.Counter
+}
+
+// newClientMetrics creates the client metrics and registers them on the provided registerer.
+// If reg is nil metrics are disabled and nil is returned.
+func newClientMetrics(reg prometheus.Registerer) *clientMetrics {
+	if reg == nil {
+		return nil
+	}
+	return &clientMetrics{
+		verifications: registerCounter(reg, prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "verifications_total",
+			Help:      "Number of client-side proof verifications performed.",
+		}),
+		verificationFailures: registerCounter(reg, prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "verification_failures_total",
+			Help:      "Number of client-side proof verifications that failed.",
+		}),
+		signatureMismatches: registerCounter(reg, prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "signature_mismatches_total",
+			Help:      "Number of server roots whose signature could not be verified.",
+		}),
+		rootCacheMisses: registerCounter(reg, prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "root_cache_misses_total",
+			Help:      "Number of times the local root cache had no root for the current server and database.",
+		}),
+	}
+}
+
+// registerCounter registers a new counter, reusing the existing one if it was already registered
+// (e.g. when more clients share the same registerer).
+func registerCounter(reg prometheus.Registerer, opts prometheus.CounterOpts) prometheus.Counter {
+	counter := prometheus.NewCounter(opts)
+	if err := reg.Register(counter); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := are.ExistingCollector.(prometheus.Counter); ok {
+				return existing
+			}
+		}
+	}
+	return counter
+}
+
+func (m *clientMetrics) observeVerification(verified bool) {
+	if m == nil {
+		return
+	}
+	m.verifications.Inc()
+	if !verified {
+		m.verificationFailures.Inc()
+	}
+}
+
+func (m *clientMetrics) observeSignatureMismatch() {
+	if m == nil {
+		return
+	}
+	m.signatureMismatches.Inc()
+}
+
+func (m *clientMetrics) observeRootCacheMiss() {
+	if m == nil {
+		return
+	}
+	m.rootCacheMisses.Inc()
+}
+
+// metricsCache wraps a root cache counting the cache misses
+type metricsCache struct {
+	cache.Cache
+	metrics *clientMetrics
+}
+
+func newMetricsCache(c cache.Cache, metrics *clientMetrics) cache.Cache {
+	if metrics == nil {
+		return c
+	}
+	return &metricsCache{Cache: c, metrics: metrics}
+}
+
+func (c *metricsCache) Get(serverUuid string, databasename string) (*schema.Root, error) {
+	root, err := c.Cache.Get(serverUuid, databasename)
+	if err != nil {
+		c.metrics.observeRootCacheMiss()
+	}
+	return root, err
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheMock struct {
+	root *schema.Root
+}
+
+func (c *cacheMock) Get(serverUuid string, databasename string) (*schema.Root, error) {
+	if c.root == nil {
+		return nil, errors.New("root not found")
+	}
+	return c.root, nil
+}
+
+func (c *cacheMock) Set(root *schema.Root, serverUuid string, databasename string) error {
+	c.root = root
+	return nil
+}
+
+func TestClientMetrics(t *testing.T) {
+	var nilMetrics *clientMetrics
+	assert.Nil(t, newClientMetrics(nil))
+	assert.NotPanics(t, func() {
+		nilMetrics.observeVerification(false)
+		nilMetrics.observeSignatureMismatch()
+		nilMetrics.observeRootCacheMiss()
+	})
+
+	reg := prometheus.NewRegistry()
+	m := newClientMetrics(reg)
+	m.observeVerification(true)
+	m.observeVerification(false)
+	m.observeSignatureMismatch()
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.verifications))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.verificationFailures))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.signatureMismatches))
+
+	// metrics registered twice on the same registry are shared
+	m2 := newClientMetrics(reg)
+	m2.observeVerification(true)
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.verifications))
+
+	c := newMetricsCache(&cacheMock{}, m)
+	_, err := c.Get("uuid", "db")
+	assert.Error(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.rootCacheMisses))
+
+	assert.NoError(t, c.Set(schema.NewRoot(), "uuid", "db"))
+	_, err = c.Get("uuid", "db")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.rootCacheMisses))
+
+	mc := &cacheMock{}
+	assert.Equal(t, mc, newMetricsCache(mc, nil))
+}
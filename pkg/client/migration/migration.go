@@ -0,0 +1,207 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration runs ordered, named migrations against an immudb database exactly once, recording each of them
+// in a key of the database itself, so that applications can seed and upgrade their reference data in every
+// environment they are deployed to.
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultKeyPrefix prefixes the keys recording the migrations which ran, the name of the migration follows it
+const DefaultKeyPrefix = "_migrations/"
+
+// Errors related to migrations
+var (
+	ErrInvalidMigration = errors.New("migrations must have a unique, non-empty name and a function")
+	ErrOutOfOrder       = errors.New("a migration has been recorded while a preceding one has not")
+	ErrConcurrentRun    = errors.New("the migration has been recorded by another runner meanwhile")
+	ErrNotVerified      = errors.New("the record of the migration could not be verified")
+)
+
+// Migration is a named step seeding or upgrading data, Up is called only if the migration has never run
+type Migration struct {
+	Name string
+	Up   func(ctx context.Context, c client.ImmuClient) error
+}
+
+// Record tells when a migration ran, Index is the one of the entry recording it and Verified whether the entry
+// has been proven against the trusted root
+type Record struct {
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+	Index     uint64    `json:"-"`
+	Verified  bool      `json:"-"`
+}
+
+// Runner runs a list of migrations in order
+type Runner struct {
+	client     client.ImmuClient
+	migrations []Migration
+	prefix     []byte
+	now        func() time.Time
+}
+
+// NewRunner returns a runner of the migrations, which run in the given order
+func NewRunner(c client.ImmuClient, migrations ...Migration) *Runner {
+	return &Runner{
+		client:     c,
+		migrations: migrations,
+		prefix:     []byte(DefaultKeyPrefix),
+		now:        time.Now,
+	}
+}
+
+// WithKeyPrefix sets the prefix of the keys recording the migrations, e.g. to keep separate lists in one database
+func (r *Runner) WithKeyPrefix(prefix []byte) *Runner {
+	r.prefix = prefix
+	return r
+}
+
+// Run applies the migrations which have not run yet, in order, returning the records of all of them.
+// Each migration is recorded as soon as it succeeds: if one fails, Run stops and the following ones run the next
+// time. The record is written only if no other runner recorded the same migration meanwhile, otherwise Run fails
+// with ErrConcurrentRun. A migration recorded after a missing one fails Run with ErrOutOfOrder, since the list
+// must only grow at its end.
+func (r *Runner) Run(ctx context.Context) ([]Record, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	records, err := r.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, m := range r.migrations {
+		if records[i] != nil {
+			continue
+		}
+		if err = m.Up(ctx, r.client); err != nil {
+			return nil, fmt.Errorf("migration %s: %w", m.Name, err)
+		}
+		if records[i], err = r.record(ctx, m.Name); err != nil {
+			return nil, fmt.Errorf("migration %s: %w", m.Name, err)
+		}
+	}
+
+	applied := make([]Record, 0, len(records))
+	for _, rec := range records {
+		applied = append(applied, *rec)
+	}
+	return applied, nil
+}
+
+// Applied returns the verified records of the migrations, nil for the ones which have not run yet.
+// It fails with ErrNotVerified if a record can't be proven, and with ErrOutOfOrder if a migration has been recorded
+// while a preceding one has not.
+func (r *Runner) Applied(ctx context.Context) ([]*Record, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	records := make([]*Record, len(r.migrations))
+	missing := -1
+	for i, m := range r.migrations {
+		rec, err := r.read(ctx, m.Name)
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil {
+			if missing < 0 {
+				missing = i
+			}
+			continue
+		}
+		if missing >= 0 {
+			return nil, fmt.Errorf("%w: %s is recorded, %s is not", ErrOutOfOrder, m.Name, r.migrations[missing].Name)
+		}
+		records[i] = rec
+	}
+	return records, nil
+}
+
+func (r *Runner) validate() error {
+	names := make(map[string]bool, len(r.migrations))
+	for _, m := range r.migrations {
+		if m.Name == "" || m.Up == nil || names[m.Name] {
+			return ErrInvalidMigration
+		}
+		names[m.Name] = true
+	}
+	return nil
+}
+
+func (r *Runner) key(name string) []byte {
+	return append(append([]byte{}, r.prefix...), name...)
+}
+
+// read returns the verified record of the migration, nil if it has not run
+func (r *Runner) read(ctx context.Context, name string) (*Record, error) {
+	vi, err := r.client.SafeGet(ctx, r.key(name))
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !vi.Verified {
+		return nil, fmt.Errorf("%w: %s", ErrNotVerified, name)
+	}
+	rec := &Record{}
+	if err = json.Unmarshal(vi.Value, rec); err != nil {
+		return nil, fmt.Errorf("record of migration %s: %v", name, err)
+	}
+	rec.Index = vi.Index
+	rec.Verified = vi.Verified
+	return rec, nil
+}
+
+// record writes the record of the migration, provided that it has not been written meanwhile, and reads it back
+// to verify it
+func (r *Runner) record(ctx context.Context, name string) (*Record, error) {
+	v, err := json.Marshal(&Record{Name: name, AppliedAt: r.now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+	key := r.key(name)
+	_, err = r.client.CompareAndExecAllTx(ctx, &schema.Ops{
+		Operations: []*schema.Op{{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: key, Value: v}}}},
+	}, []*schema.ExpectedIndex{{Key: key}})
+	if err == client.ErrTxConflict {
+		return nil, ErrConcurrentRun
+	}
+	if err != nil {
+		return nil, err
+	}
+	rec, err := r.read(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotVerified, name)
+	}
+	return rec, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner(t *testing.T) {
+	cli, ctx, closer, err := clienttest.NewBufconnClient(server.DefaultOptions().WithInMemoryStore(true))
+	require.NoError(t, err)
+	defer closer()
+
+	runs := map[string]int{}
+	migration := func(name string, err error) Migration {
+		return Migration{Name: name, Up: func(ctx context.Context, c client.ImmuClient) error {
+			runs[name]++
+			if err != nil {
+				return err
+			}
+			_, err := c.Set(ctx, []byte(`country:`+name), []byte(name))
+			return err
+		}}
+	}
+
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	runner := NewRunner(cli, migration("it", nil), migration("fr", nil))
+	runner.now = func() time.Time { return now }
+
+	records, err := runner.Run(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, "it", records[0].Name)
+	require.Equal(t, now, records[0].AppliedAt)
+	require.True(t, records[0].Verified)
+	require.Equal(t, "fr", records[1].Name)
+
+	// the recorded migrations don't run again, the new ones do
+	failure := errors.New("failure")
+	runner = NewRunner(cli, migration("it", nil), migration("fr", nil), migration("de", failure), migration("es", nil))
+	_, err = runner.Run(ctx)
+	require.Error(t, err)
+	require.Equal(t, map[string]int{"it": 1, "fr": 1, "de": 1}, runs)
+
+	applied, err := runner.Applied(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, applied[1])
+	require.Nil(t, applied[2])
+	require.Nil(t, applied[3])
+
+	runner = NewRunner(cli, migration("it", nil), migration("fr", nil), migration("de", nil), migration("es", nil))
+	records, err = runner.Run(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, map[string]int{"it": 1, "fr": 1, "de": 2, "es": 1}, runs)
+
+	item, err := cli.Get(ctx, []byte(`country:es`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`es`), item.Value.Payload)
+
+	// a migration inserted in the middle of the list
+	_, err = NewRunner(cli, migration("it", nil), migration("uk", nil), migration("fr", nil)).Run(ctx)
+	require.True(t, errors.Is(err, ErrOutOfOrder))
+
+	// a separate list of migrations
+	records, err = NewRunner(cli, migration("it", nil)).WithKeyPrefix([]byte(`_seed/`)).Run(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, 2, runs["it"])
+
+	_, err = NewRunner(cli, migration("it", nil), migration("it", nil)).Run(ctx)
+	require.Equal(t, ErrInvalidMigration, err)
+	_, err = NewRunner(cli, Migration{Name: "noop"}).Run(ctx)
+	require.Equal(t, ErrInvalidMigration, err)
+}
+
+func TestRunnerConcurrentRun(t *testing.T) {
+	cli, ctx, closer, err := clienttest.NewBufconnClient(server.DefaultOptions().WithInMemoryStore(true))
+	require.NoError(t, err)
+	defer closer()
+
+	// another runner records the migration while it's running
+	runner := NewRunner(cli, Migration{Name: "seed", Up: func(ctx context.Context, c client.ImmuClient) error {
+		_, err := NewRunner(c, Migration{Name: "seed", Up: func(context.Context, client.ImmuClient) error {
+			return nil
+		}}).Run(ctx)
+		return err
+	}})
+	_, err = runner.Run(ctx)
+	require.True(t, errors.Is(err, ErrConcurrentRun))
+}
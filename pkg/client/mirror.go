@@ -0,0 +1,226 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"google.golang.org/grpc"
+)
+
+// ErrMirrorUnverified is returned when the data served by an untrusted mirror can't be verified against the roots of
+// the authoritative server
+var ErrMirrorUnverified = errors.New("the mirror served data that can't be verified against the authoritative server")
+
+// mirrorClient serves the verified reads from an untrusted mirror, verifying them against the roots of the
+// authoritative server, and everything else from the authoritative server
+type mirrorClient struct {
+	ReadOnlyClient
+	authoritative *immuClient
+	mirror        schema.ImmuServiceClient
+	mirrorClient  ImmuClient
+}
+
+// NewMirrorClient returns a read-only client whose verified reads (SafeGet and SafeGetBatch) are served by the mirror,
+// e.g. a replica or a caching proxy close to the client, while the roots they are verified against only come from the
+// authoritative server. The mirror is never trusted: its proofs are verified locally and the root they lead to is
+// checked to be the one of the authoritative server at the same index, which costs a round trip to the authoritative
+// server only when the mirror is ahead of the last verified root. Reads the mirror fails to serve, e.g. as it lags
+// behind, are served by the authoritative server, while data that can't be verified fails with ErrMirrorUnverified.
+// The mirror is called with the credentials of the authoritative server, so mirror should share its token file.
+func NewMirrorClient(options *Options, mirror *Options) (ReadOnlyClient, error) {
+	c, err := NewImmuClient(options)
+	if err != nil {
+		return nil, err
+	}
+	mc, err := NewImmuClient(mirror)
+	if err != nil {
+		c.Disconnect()
+		return nil, err
+	}
+	return newMirrorClient(c.(*immuClient), *mc.GetServiceClient(), mc), nil
+}
+
+func newMirrorClient(authoritative *immuClient, mirror schema.ImmuServiceClient, mc ImmuClient) *mirrorClient {
+	return &mirrorClient{
+		ReadOnlyClient: authoritative,
+		authoritative:  authoritative,
+		mirror:         mirror,
+		mirrorClient:   mc,
+	}
+}
+
+// Disconnect closes the connections to both the authoritative server and the mirror
+func (m *mirrorClient) Disconnect() error {
+	if m.mirrorClient != nil {
+		m.mirrorClient.Disconnect()
+	}
+	return m.ReadOnlyClient.Disconnect()
+}
+
+// SafeGet reads key from the mirror, verifying it against the roots of the authoritative server
+func (m *mirrorClient) SafeGet(ctx context.Context, key []byte, opts ...grpc.CallOption) (*VerifiedItem, error) {
+	vi, served, err := m.mirrorSafeGet(ctx, key, opts...)
+	if served {
+		return vi, err
+	}
+	m.authoritative.Logger.Debugf("the mirror failed to serve SafeGet, reading from the authoritative server: %v", err)
+	return m.ReadOnlyClient.SafeGet(ctx, key, opts...)
+}
+
+func (m *mirrorClient) mirrorSafeGet(ctx context.Context, key []byte, opts ...grpc.CallOption) (*VerifiedItem, bool, error) {
+	start := time.Now()
+	c := m.authoritative
+
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.IsConnected() {
+		return nil, true, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, true, err
+	}
+
+	safeItem, err := m.mirror.SafeGet(ctx, &schema.SafeGetOptions{
+		Key:       key,
+		RootIndex: &schema.Index{Index: root.GetIndex()},
+	}, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	h, err := safeItem.Hash()
+	if err != nil {
+		return nil, true, err
+	}
+	verified := safeItem.Proof.Verify(h, *root)
+	if verified {
+		if verified, err = m.pin(ctx, root, safeItem.Proof.NewRoot()); err != nil {
+			return nil, true, err
+		}
+	}
+	c.metrics.observeVerification(verified)
+	if !verified {
+		return nil, true, ErrMirrorUnverified
+	}
+	if err = c.Rootservice.SetRoot(safeItem.Proof.NewRoot(), c.Options.CurrentDatabase); err != nil {
+		return nil, true, err
+	}
+
+	c.Logger.Debugf("mirror SafeGet finished in %s", time.Since(start))
+	sitem, err := safeItem.ToSafeSItem()
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &VerifiedItem{
+		Key:      sitem.Item.GetKey(),
+		Value:    sitem.Item.Value.Payload,
+		Index:    sitem.Item.GetIndex(),
+		Time:     sitem.Item.Value.Timestamp,
+		Verified: verified,
+	}, true, nil
+}
+
+// SafeGetBatch reads keys from the mirror, verifying them against the roots of the authoritative server
+func (m *mirrorClient) SafeGetBatch(ctx context.Context, keys [][]byte) (*VerifiedItemList, error) {
+	list, served, err := m.mirrorSafeGetBatch(ctx, keys)
+	if served {
+		return list, err
+	}
+	m.authoritative.Logger.Debugf("the mirror failed to serve SafeGetBatch, reading from the authoritative server: %v", err)
+	return m.ReadOnlyClient.SafeGetBatch(ctx, keys)
+}
+
+func (m *mirrorClient) mirrorSafeGetBatch(ctx context.Context, keys [][]byte) (*VerifiedItemList, bool, error) {
+	start := time.Now()
+	c := m.authoritative
+
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.IsConnected() {
+		return nil, true, ErrNotConnected
+	}
+
+	root, err := c.Rootservice.GetRoot(ctx, c.Options.CurrentDatabase)
+	if err != nil {
+		return nil, true, err
+	}
+
+	opts := &schema.SafeGetBatchOptions{RootIndex: &schema.Index{Index: root.GetIndex()}, MultiProof: true}
+	for _, key := range keys {
+		opts.Keys = append(opts.Keys, &schema.Key{Key: key})
+	}
+
+	list, err := m.mirror.SafeGetBatch(ctx, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	fresh, unverified, err := verifySafeItemList(list, root)
+	if err != nil {
+		return nil, true, err
+	}
+	verified := unverified == nil
+	if verified && fresh != nil {
+		if verified, err = m.pin(ctx, root, fresh); err != nil {
+			return nil, true, err
+		}
+	}
+	if len(list.Items) > 0 {
+		c.metrics.observeVerification(verified)
+	}
+	if !verified {
+		return nil, true, ErrMirrorUnverified
+	}
+	if fresh != nil {
+		if err = c.Rootservice.SetRoot(fresh, c.Options.CurrentDatabase); err != nil {
+			return nil, true, err
+		}
+	}
+
+	vlist, err := toVerifiedItemList(list, verified)
+	if err != nil {
+		return nil, true, err
+	}
+
+	c.Logger.Debugf("mirror SafeGetBatch finished in %s", time.Since(start))
+
+	return vlist, true, nil
+}
+
+// pin tells whether root, proved by the mirror, is the root of the authoritative server at the same index. Roots
+// equal to the trusted one need no round trip, otherwise the authoritative server proves that its current root is
+// consistent with root.
+func (m *mirrorClient) pin(ctx context.Context, trusted *schema.Root, root *schema.Root) (bool, error) {
+	if root.GetIndex() == trusted.GetIndex() && len(trusted.GetRoot()) > 0 {
+		return bytes.Equal(root.GetRoot(), trusted.GetRoot()), nil
+	}
+	cp, err := m.authoritative.ServiceClient.Consistency(ctx, &schema.Index{Index: root.GetIndex()})
+	if err != nil {
+		return false, err
+	}
+	return cp.Verify(*root), nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// forgingMirror serves the values of the authoritative server, replacing the value of the entries with forged
+type forgingMirror struct {
+	schema.ImmuServiceClient
+	forged []byte
+	err    error
+}
+
+func (m *forgingMirror) SafeGet(ctx context.Context, in *schema.SafeGetOptions, opts ...grpc.CallOption) (*schema.SafeItem, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	item, err := m.ImmuServiceClient.SafeGet(ctx, in, opts...)
+	if err == nil && m.forged != nil {
+		item.Item.Value = m.forged
+	}
+	return item, err
+}
+
+func TestMirrorClient(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	// the mirror is served by the same server over another connection
+	dialOptions := []grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}
+	mirrorDialOptions := []grpc.DialOption{grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure()}
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(NewHomedirService())
+	dir, err := ioutil.TempDir("", "mirror_client")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	opts := DefaultOptions().WithDir(dir).WithDialOptions(&dialOptions).WithTokenService(ts)
+	mirrorOpts := DefaultOptions().WithDir(dir).WithDialOptions(&mirrorDialOptions).WithTokenService(ts)
+
+	rw, err := NewImmuClient(DefaultOptions().WithDir(dir).WithDialOptions(&[]grpc.DialOption{grpc.WithContextDialer(bs.Dialer)}).WithTokenService(ts))
+	require.NoError(t, err)
+	defer rw.Disconnect()
+	lresp, err := rw.Login(context.TODO(), []byte("immudb"), []byte("immudb"))
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lresp.Token))
+	_, err = rw.SafeSet(ctx, []byte("key1"), []byte("value1"))
+	require.NoError(t, err)
+
+	ro, err := NewMirrorClient(opts, mirrorOpts)
+	require.NoError(t, err)
+	defer ro.Disconnect()
+	_, ok := ro.(ImmuClient)
+	require.False(t, ok)
+
+	// the mirror is at the trusted root
+	item, err := ro.SafeGet(ctx, []byte("key1"))
+	require.NoError(t, err)
+	require.True(t, item.Verified)
+	require.Equal(t, []byte("value1"), item.Value)
+
+	// the mirror is ahead of the trusted root, which is pinned by the authoritative server
+	_, err = rw.Set(ctx, []byte("key2"), []byte("value2"))
+	require.NoError(t, err)
+	_, err = rw.Set(ctx, []byte("key3"), []byte("value3"))
+	require.NoError(t, err)
+	item, err = ro.SafeGet(ctx, []byte("key2"))
+	require.NoError(t, err)
+	require.True(t, item.Verified)
+	require.Equal(t, []byte("value2"), item.Value)
+
+	list, err := ro.SafeGetBatch(ctx, [][]byte{[]byte("key1"), []byte("key3")})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	require.Equal(t, []byte("value3"), list.Items[1].Value)
+
+	m := ro.(*mirrorClient)
+	honest := m.mirror
+	m.mirror = &forgingMirror{ImmuServiceClient: honest, forged: []byte("forged")}
+	_, err = ro.SafeGet(ctx, []byte("key1"))
+	require.Equal(t, ErrMirrorUnverified, err)
+
+	// reads the mirror fails to serve are served by the authoritative server
+	m.mirror = &forgingMirror{ImmuServiceClient: honest, err: errors.New("unavailable")}
+	item, err = ro.SafeGet(ctx, []byte("key1"))
+	require.NoError(t, err)
+	require.True(t, item.Verified)
+	require.Equal(t, []byte("value1"), item.Value)
+
+	// roots not matching the authoritative ones are not pinned
+	root, err := rw.CurrentRoot(ctx)
+	require.NoError(t, err)
+	forged := &schema.Root{Payload: &schema.RootIndex{Index: root.GetIndex() - 1, Root: make([]byte, 32)}}
+	pinned, err := m.pin(ctx, root, forged)
+	require.NoError(t, err)
+	require.False(t, pinned)
+	forged.Payload.Index = root.GetIndex()
+	pinned, err = m.pin(ctx, root, forged)
+	require.NoError(t, err)
+	require.False(t, pinned)
+}
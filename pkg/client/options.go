@@ -21,6 +21,9 @@ import (
 	"strconv"
 
 	c "github.com/codenotary/immudb/cmd/helper"
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"github.com/codenotary/immudb/pkg/compression"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 )
 
@@ -48,28 +51,45 @@ type Options struct {
 	PrometheusHost     string
 	PrometheusPort     string
 	LogFileName        string
+	MetricsRegisterer  prometheus.Registerer `json:"-"`
+	VerificationPolicy VerificationPolicy    `json:"-"`
+	// Compression is the name of the compressor used for the calls to the server (gzip or zstd), none by default.
+	// Only calls whose request, or whose last response, is at least CompressionThreshold bytes are compressed.
+	Compression          string
+	CompressionThreshold int
+	// CircuitBreaker, if set, stops calling the server while it is failing or too slow, see CircuitBreakerOptions
+	CircuitBreaker *CircuitBreakerOptions `json:"-"`
+	// CacheKey, if set, encrypts and authenticates the roots trusted by the client in Dir, see cache.LoadCacheKey
+	CacheKey []byte `json:"-"`
+	// Tracer, if set, traces every call to the server with a span, see tracing.Tracer
+	Tracer tracing.Tracer `json:"-"`
+	// Checksums makes the unary calls carry the checksum of their payloads, verified on both ends
+	Checksums bool
 }
 
 // DefaultOptions ...
 func DefaultOptions() *Options {
 	return &Options{
-		Dir:                ".",
-		Address:            "127.0.0.1",
-		Port:               3322,
-		HealthCheckRetries: 5,
-		MTLs:               false,
-		Auth:               true,
-		MaxRecvMsgSize:     4 * 1024 * 1024, //4Mb
-		Config:             "configs/immuclient.toml",
-		TokenFileName:      "token",
-		DialOptions:        &[]grpc.DialOption{},
-		PasswordReader:     c.DefaultPasswordReader,
-		Tkns:               NewTokenService().WithTokenFileName("token").WithHds(NewHomedirService()),
-		Metrics:            true,
-		PidPath:            "",
-		PrometheusHost:     "",
-		PrometheusPort:     "",
-		LogFileName:        "",
+		Dir:                  ".",
+		Address:              "127.0.0.1",
+		Port:                 3322,
+		HealthCheckRetries:   5,
+		MTLs:                 false,
+		Auth:                 true,
+		MaxRecvMsgSize:       4 * 1024 * 1024, //4Mb
+		Config:               "configs/immuclient.toml",
+		TokenFileName:        "token",
+		DialOptions:          &[]grpc.DialOption{},
+		PasswordReader:       c.DefaultPasswordReader,
+		Tkns:                 NewTokenService().WithTokenFileName("token").WithHds(NewHomedirService()),
+		Metrics:              true,
+		PidPath:              "",
+		PrometheusHost:       "",
+		PrometheusPort:       "",
+		LogFileName:          "",
+		VerificationPolicy:   WarnVerification,
+		Compression:          compression.None,
+		CompressionThreshold: 1024,
 	}
 }
 
@@ -79,6 +99,62 @@ func (o *Options) WithLogFileName(filename string) *Options {
 	return o
 }
 
+// WithMetricsRegisterer sets the Prometheus registerer on which client-side verification metrics are exposed.
+// Metrics are not collected if no registerer is provided
+func (o *Options) WithMetricsRegisterer(reg prometheus.Registerer) *Options {
+	o.MetricsRegisterer = reg
+	return o
+}
+
+// WithVerificationPolicy sets how the Safe* methods react to proofs that cannot be verified,
+// e.g. StrictVerification, WarnVerification or a custom callback
+func (o *Options) WithVerificationPolicy(policy VerificationPolicy) *Options {
+	o.VerificationPolicy = policy
+	return o
+}
+
+// WithCompression sets the compressor used for the calls to the server: compression.Gzip, compression.Zstd
+// or compression.None to disable compression
+func (o *Options) WithCompression(compressor string) *Options {
+	o.Compression = compressor
+	return o
+}
+
+// WithCompressionThreshold sets the size in bytes from which messages are worth compressing, 0 compresses every call
+func (o *Options) WithCompressionThreshold(threshold int) *Options {
+	o.CompressionThreshold = threshold
+	return o
+}
+
+// WithCircuitBreaker protects the application from a degraded server with a circuit breaker configured by cb,
+// e.g. DefaultCircuitBreakerOptions(). A nil cb disables the circuit breaker.
+func (o *Options) WithCircuitBreaker(cb *CircuitBreakerOptions) *Options {
+	o.CircuitBreaker = cb
+	return o
+}
+
+// WithCacheKey makes the client encrypt and authenticate with key the roots it trusts, so that they cannot be
+// read or rewritten by whoever has access to the files in Dir. key must be cache.CacheKeySize bytes long.
+func (o *Options) WithCacheKey(key []byte) *Options {
+	o.CacheKey = key
+	return o
+}
+
+// WithTracer makes the client trace every call to the server with tracer, e.g. an adapter of an OpenTelemetry
+// tracer. A nil tracer disables tracing.
+func (o *Options) WithTracer(tracer tracing.Tracer) *Options {
+	o.Tracer = tracer
+	return o
+}
+
+// WithChecksums makes the client send the checksum of every request and verify the checksum of every response,
+// so that the payloads corrupted in transit are detected, e.g. between the server and a proxy terminating TLS.
+// Requests corrupted in transit are refused by the server before being handled.
+func (o *Options) WithChecksums(checksums bool) *Options {
+	o.Checksums = checksums
+	return o
+}
+
 // WithPrometheusHost set prometheus host
 func (o *Options) WithPrometheusHost(host string) *Options {
 	o.PrometheusHost = host
@@ -18,6 +18,10 @@ package client
 
 import (
 	"testing"
+
+	"github.com/codenotary/immudb/pkg/client/cache"
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func TestOptions(t *testing.T) {
@@ -41,7 +45,11 @@ func TestOptions(t *testing.T) {
 		WithAuth(true).
 		WithMaxRecvMsgSize(1 << 20).
 		WithConfig("configfile").
-		WithTokenFileName("tokenfile")
+		WithTokenFileName("tokenfile").
+		WithMetricsRegisterer(prometheus.NewRegistry()).
+		WithCircuitBreaker(DefaultCircuitBreakerOptions()).
+		WithCacheKey([]byte("cachekey")).
+		WithTracer(tracing.NewLogTracer(nil, 0))
 	if op.LogFileName != "logfilename" ||
 		op.PrometheusHost != "localhost" ||
 		op.PrometheusPort != "1234" ||
@@ -60,8 +68,18 @@ func TestOptions(t *testing.T) {
 		op.MaxRecvMsgSize != 1<<20 ||
 		op.Config != "configfile" ||
 		op.TokenFileName != "tokenfile" ||
+		op.MetricsRegisterer == nil ||
+		op.CircuitBreaker == nil ||
+		string(op.CacheKey) != "cachekey" ||
+		op.Tracer == nil ||
 		op.Bind() != "127.0.0.1:4321" ||
 		len(op.String()) == 0 {
 		t.Fatal("Client options fail")
 	}
 }
+
+func TestOptionsInvalidCacheKey(t *testing.T) {
+	if _, err := NewImmuClient(DefaultOptions().WithCacheKey([]byte("short"))); err != cache.ErrInvalidCacheKey {
+		t.Fatalf("expected %v, got %v", cache.ErrInvalidCacheKey, err)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// ErrInvalidPageToken is returned when the token set by WithPageToken wasn't returned by a previous listing
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageInfo tells where a page stands in a listing, so that it can be paginated without further requests.
+// More is reported only when a limit is set, a listing without limit being returned in a single page.
+type PageInfo struct {
+	// More tells whether items follow the page
+	More bool `json:"more"`
+	// NextPageToken resumes the listing after the page once set by WithPageToken, it's empty on the last page
+	NextPageToken string `json:"next_page_token,omitempty"`
+	// TotalEstimate is a lower bound of the number of items in the listing: the items of the pages read so far,
+	// plus one if more follow. It's exact on the last page.
+	TotalEstimate uint64 `json:"total_estimate"`
+}
+
+// StructuredItemPage is a page of Scan or History. It embeds the list previously returned, along with its accessors.
+type StructuredItemPage struct {
+	*schema.StructuredItemList
+	PageInfo
+}
+
+// ZStructuredItemPage is a page of ZScan. It embeds the list previously returned, along with its accessors.
+type ZStructuredItemPage struct {
+	*schema.ZStructuredItemList
+	PageInfo
+}
+
+type pageTokenKey struct{}
+
+// WithPageToken returns a context making Scan, ZScan or History resume the listing after the page that returned token.
+// The other options of the listing are expected to be the same used for that page.
+func WithPageToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, pageTokenKey{}, token)
+}
+
+// pageFromContext returns the position in the listing and the offset to resume from, as set by WithPageToken.
+// The offset of Scan is the cursor returned by the server.
+func pageFromContext(ctx context.Context) (position uint64, offset []byte, err error) {
+	token, _ := ctx.Value(pageTokenKey{}).(string)
+	if token == "" {
+		return 0, nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, nil, ErrInvalidPageToken
+	}
+	position, n := binary.Uvarint(b)
+	if n <= 0 || n == len(b) {
+		return 0, nil, ErrInvalidPageToken
+	}
+	return position, b[n:], nil
+}
+
+// peekLimit is the number of items to ask for a page of limit items: one more tells whether others follow
+func peekLimit(limit uint64) uint64 {
+	if limit == 0 {
+		return 0
+	}
+	return limit + 1
+}
+
+// newPageInfo describes the page of count items found at position. offset is the one of the last item of the page,
+// or the cursor returned by Scan.
+func newPageInfo(position uint64, count int, more bool, offset []byte) PageInfo {
+	info := PageInfo{
+		More:          more,
+		TotalEstimate: position + uint64(count),
+	}
+	if more {
+		info.TotalEstimate++
+		b := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(offset))
+		b = append(b[:binary.PutUvarint(b, position+uint64(count))], offset...)
+		info.NextPageToken = base64.RawURLEncoding.EncodeToString(b)
+	}
+	return info
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"google.golang.org/grpc"
+)
+
+// ReadOnlyClient is the subset of ImmuClient that cannot modify the ledger:
+// it only reads and verifies entries, and selects the user and database to read from.
+type ReadOnlyClient interface {
+	Disconnect() error
+	IsConnected() bool
+	WaitForHealthCheck(ctx context.Context) (err error)
+	HealthCheck(ctx context.Context) error
+	Login(ctx context.Context, user []byte, pass []byte) (*schema.LoginResponse, error)
+	Logout(ctx context.Context) error
+	UseDatabase(ctx context.Context, d *schema.Database) (*schema.UseDatabaseReply, error)
+	DatabaseList(ctx context.Context) (*schema.DatabaseListResponse, error)
+
+	CurrentRoot(ctx context.Context) (*schema.Root, error)
+	Get(ctx context.Context, key []byte) (*schema.StructuredItem, error)
+	SafeGet(ctx context.Context, key []byte, opts ...grpc.CallOption) (*VerifiedItem, error)
+	RawSafeGet(ctx context.Context, key []byte, opts ...grpc.CallOption) (*VerifiedItem, error)
+	GetBatch(ctx context.Context, keys [][]byte) (*schema.StructuredItemList, error)
+	SafeGetBatch(ctx context.Context, keys [][]byte) (*VerifiedItemList, error)
+	GetReference(ctx context.Context, key *schema.Key) (*schema.StructuredItem, error)
+	GetReferences(ctx context.Context, key []byte, index *schema.Index) (*schema.ReferenceList, error)
+	GetReferenceChain(ctx context.Context, reference []byte) (*ReferenceChain, error)
+	Scan(ctx context.Context, options *schema.ScanOptions) (*StructuredItemPage, error)
+	ZScan(ctx context.Context, options *schema.ZScanOptions) (*ZStructuredItemPage, error)
+	SafeZScan(ctx context.Context, options *schema.ZScanOptions) (*VerifiedZItemPage, error)
+	IScan(ctx context.Context, pageNumber uint64, pageSize uint64) (*schema.SPage, error)
+	ByIndex(ctx context.Context, index uint64) (*schema.StructuredItem, error)
+	GetBySequence(ctx context.Context, sequence uint64) (*schema.SequencedWrite, error)
+	VerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*VerifiedValueHash, error)
+	RawVerifyValueHash(ctx context.Context, key []byte, valueHash []byte, index *schema.Index) (*VerifiedValueHash, error)
+	RawBySafeIndex(ctx context.Context, index uint64) (*VerifiedItem, error)
+	History(ctx context.Context, options *schema.HistoryOptions) (*StructuredItemPage, error)
+	Count(ctx context.Context, prefix []byte) (*schema.ItemsCount, error)
+	CountAll(ctx context.Context) (*schema.ItemsCount, error)
+	VerifiedCount(ctx context.Context, prefix []byte, sampleSize uint64) (*VerifiedCount, error)
+	VerifiedExists(ctx context.Context, key []byte) (*VerifiedExistence, error)
+	Inclusion(ctx context.Context, index uint64) (*schema.InclusionProof, error)
+	Consistency(ctx context.Context, index uint64) (*schema.ConsistencyProof, error)
+	PrintTree(ctx context.Context) (*schema.Tree, error)
+	Dump(ctx context.Context, writer io.WriteSeeker) (int64, error)
+	DumpKeyHistory(ctx context.Context, key []byte) (*schema.KeyHistoryDump, error)
+	HistoryTimeline(ctx context.Context, key []byte, verify bool) (*KeyTimeline, error)
+	GetAt(ctx context.Context, key []byte, index uint64) (*VerifiedItem, error)
+	GetAsOf(ctx context.Context, key []byte, t time.Time) (*VerifiedItem, error)
+	SampleKeys(ctx context.Context, size uint64, prefix []byte, seed int64) (*schema.KeySample, error)
+	DownloadVerificationBundle(ctx context.Context, w io.Writer, sampleSize uint64) (*schema.VerificationBundle, error)
+}
+
+// readOnlyClient hides the underlying client, so that it can't be type asserted back to an ImmuClient
+type readOnlyClient struct {
+	ReadOnlyClient
+}
+
+// NewReadOnlyClient returns a client exposing only the methods that cannot modify the ledger,
+// so that code paths that must never write (e.g. reporting services or verifiers) are checked at compile time
+func NewReadOnlyClient(options *Options) (ReadOnlyClient, error) {
+	c, err := NewImmuClient(options)
+	if err != nil {
+		return nil, err
+	}
+	return AsReadOnly(c), nil
+}
+
+// AsReadOnly restricts an already set up client to its read-only methods
+func AsReadOnly(c ImmuClient) ReadOnlyClient {
+	return &readOnlyClient{ReadOnlyClient: c}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	ts := NewTokenService().WithTokenFileName("testTokenFile").WithHds(NewHomedirService())
+	// the roots of other test servers, which share the same (missing) UUID, must not be reused
+	dir, err := ioutil.TempDir("", "readonly_client")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	opts := DefaultOptions().WithDir(dir).WithDialOptions(&dialOptions).WithTokenService(ts)
+
+	rw, err := NewImmuClient(opts)
+	require.NoError(t, err)
+	defer rw.Disconnect()
+	lresp, err := rw.Login(context.TODO(), []byte("immudb"), []byte("immudb"))
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lresp.Token))
+	_, err = rw.SafeSet(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+
+	ro, err := NewReadOnlyClient(opts)
+	require.NoError(t, err)
+	defer ro.Disconnect()
+
+	_, ok := ro.(ImmuClient)
+	require.False(t, ok)
+	_, ok = ro.(interface {
+		Set(ctx context.Context, key []byte, value []byte) (*schema.Index, error)
+	})
+	require.False(t, ok)
+
+	lresp, err = ro.Login(context.TODO(), []byte("immudb"), []byte("immudb"))
+	require.NoError(t, err)
+	ctx = metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lresp.Token))
+
+	item, err := ro.SafeGet(ctx, []byte("key"))
+	require.NoError(t, err)
+	require.True(t, item.Verified)
+	require.Equal(t, []byte("value"), item.Value)
+}
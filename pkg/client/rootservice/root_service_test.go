@@ -88,6 +88,15 @@ type immuServiceClientMock struct{}
 func (m *immuServiceClientMock) ListUsers(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.UserList, error) {
 	return &schema.UserList{}, nil
 }
+func (m *immuServiceClientMock) ListSessions(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.SessionList, error) {
+	return &schema.SessionList{}, nil
+}
+func (m *immuServiceClientMock) GetUsage(ctx context.Context, in *schema.UsageRequest, opts ...grpc.CallOption) (*schema.UsageReport, error) {
+	return &schema.UsageReport{}, nil
+}
+func (m *immuServiceClientMock) GetAccessControlState(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.AccessControlState, error) {
+	return &schema.AccessControlState{}, nil
+}
 func (m *immuServiceClientMock) GetUser(ctx context.Context, in *schema.UserRequest, opts ...grpc.CallOption) error {
 	return nil
 }
@@ -133,12 +142,30 @@ func (m *immuServiceClientMock) SafeGet(ctx context.Context, in *schema.SafeGetO
 func (m *immuServiceClientMock) SetBatch(ctx context.Context, in *schema.KVList, opts ...grpc.CallOption) (*schema.Index, error) {
 	return &schema.Index{}, nil
 }
+func (m *immuServiceClientMock) SafeGetBatch(ctx context.Context, in *schema.SafeGetBatchOptions, opts ...grpc.CallOption) (*schema.SafeItemList, error) {
+	return &schema.SafeItemList{}, nil
+}
 func (m *immuServiceClientMock) GetBatch(ctx context.Context, in *schema.KeyList, opts ...grpc.CallOption) (*schema.ItemList, error) {
 	return &schema.ItemList{}, nil
 }
 func (m *immuServiceClientMock) ExecAllOps(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error) {
 	return &schema.Index{}, nil
 }
+func (m *immuServiceClientMock) ExecAllTx(ctx context.Context, in *schema.Ops, opts ...grpc.CallOption) (*schema.Index, error) {
+	return &schema.Index{}, nil
+}
+func (m *immuServiceClientMock) SafeExecAllTx(ctx context.Context, in *schema.SafeExecAllTxOptions, opts ...grpc.CallOption) (*schema.TxProof, error) {
+	return &schema.TxProof{}, nil
+}
+func (m *immuServiceClientMock) Unreference(ctx context.Context, in *schema.Key, opts ...grpc.CallOption) (*schema.Index, error) {
+	return &schema.Index{}, nil
+}
+func (m *immuServiceClientMock) GetReferences(ctx context.Context, in *schema.ReferencesOptions, opts ...grpc.CallOption) (*schema.ReferenceList, error) {
+	return &schema.ReferenceList{}, nil
+}
+func (m *immuServiceClientMock) CompareAndExecAllTx(ctx context.Context, in *schema.CompareAndExecAllTxOptions, opts ...grpc.CallOption) (*schema.Index, error) {
+	return &schema.Index{}, nil
+}
 func (m *immuServiceClientMock) Scan(ctx context.Context, in *schema.ScanOptions, opts ...grpc.CallOption) (*schema.ItemList, error) {
 	return &schema.ItemList{}, nil
 }
@@ -148,6 +175,9 @@ func (m *immuServiceClientMock) Count(ctx context.Context, in *schema.KeyPrefix,
 func (m *immuServiceClientMock) CountAll(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.ItemsCount, error) {
 	return &schema.ItemsCount{}, nil
 }
+func (m *immuServiceClientMock) Stats(ctx context.Context, in *schema.KeyPrefix, opts ...grpc.CallOption) (*schema.PrefixStats, error) {
+	return &schema.PrefixStats{}, nil
+}
 func (m *immuServiceClientMock) CurrentRoot(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.Root, error) {
 	return &schema.Root{}, nil
 }
@@ -160,12 +190,57 @@ func (m *immuServiceClientMock) Consistency(ctx context.Context, in *schema.Inde
 func (m *immuServiceClientMock) ByIndex(ctx context.Context, in *schema.Index, opts ...grpc.CallOption) (*schema.Item, error) {
 	return &schema.Item{}, nil
 }
+func (m *immuServiceClientMock) GetBySequence(ctx context.Context, in *schema.Sequence, opts ...grpc.CallOption) (*schema.SequencedWrite, error) {
+	return &schema.SequencedWrite{}, nil
+}
 func (m *immuServiceClientMock) BySafeIndex(ctx context.Context, in *schema.SafeIndexOptions, opts ...grpc.CallOption) (*schema.SafeItem, error) {
 	return &schema.SafeItem{}, nil
 }
 func (m *immuServiceClientMock) History(ctx context.Context, in *schema.HistoryOptions, opts ...grpc.CallOption) (*schema.ItemList, error) {
 	return &schema.ItemList{}, nil
 }
+func (m *immuServiceClientMock) DumpKeyHistory(ctx context.Context, in *schema.Key, opts ...grpc.CallOption) (*schema.KeyHistoryDump, error) {
+	return &schema.KeyHistoryDump{}, nil
+}
+func (m *immuServiceClientMock) GetAt(ctx context.Context, in *schema.GetAtOptions, opts ...grpc.CallOption) (*schema.HistoricalItem, error) {
+	return &schema.HistoricalItem{}, nil
+}
+func (m *immuServiceClientMock) GetAsOf(ctx context.Context, in *schema.GetAsOfOptions, opts ...grpc.CallOption) (*schema.HistoricalItem, error) {
+	return &schema.HistoricalItem{}, nil
+}
+func (m *immuServiceClientMock) VerifyValueHash(ctx context.Context, in *schema.ValueHashOptions, opts ...grpc.CallOption) (*schema.ValueHashVerification, error) {
+	return &schema.ValueHashVerification{}, nil
+}
+func (m *immuServiceClientMock) SampleKeys(ctx context.Context, in *schema.SampleOptions, opts ...grpc.CallOption) (*schema.KeySample, error) {
+	return &schema.KeySample{}, nil
+}
+func (m *immuServiceClientMock) Delete(ctx context.Context, in *schema.Key, opts ...grpc.CallOption) (*schema.Index, error) {
+	return &schema.Index{}, nil
+}
+func (m *immuServiceClientMock) FreezePrefix(ctx context.Context, in *schema.KeyPrefix, opts ...grpc.CallOption) (*schema.Index, error) {
+	return &schema.Index{}, nil
+}
+func (m *immuServiceClientMock) AnalyzeStorage(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.StorageReport, error) {
+	return &schema.StorageReport{}, nil
+}
+func (m *immuServiceClientMock) Backup(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.BackupInfo, error) {
+	return &schema.BackupInfo{}, nil
+}
+func (m *immuServiceClientMock) RestoreBackup(ctx context.Context, in *schema.RestoreBackupRequest, opts ...grpc.CallOption) (*schema.BackupInfo, error) {
+	return &schema.BackupInfo{}, nil
+}
+func (m *immuServiceClientMock) Logs(ctx context.Context, in *schema.LogRequest, opts ...grpc.CallOption) (schema.ImmuService_LogsClient, error) {
+	return nil, nil
+}
+func (m *immuServiceClientMock) Subscribe(ctx context.Context, in *schema.SubscribeRequest, opts ...grpc.CallOption) (schema.ImmuService_SubscribeClient, error) {
+	return nil, nil
+}
+func (m *immuServiceClientMock) Tail(ctx context.Context, in *schema.TailRequest, opts ...grpc.CallOption) (schema.ImmuService_TailClient, error) {
+	return nil, nil
+}
+func (m *immuServiceClientMock) CompareAndReference(ctx context.Context, in *schema.CompareAndReferenceOptions, opts ...grpc.CallOption) (*schema.Index, error) {
+	return &schema.Index{}, nil
+}
 func (m *immuServiceClientMock) Health(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*schema.HealthResponse, error) {
 	return &schema.HealthResponse{}, nil
 }
@@ -184,6 +259,9 @@ func (m *immuServiceClientMock) ZAdd(ctx context.Context, in *schema.ZAddOptions
 func (m *immuServiceClientMock) ZScan(ctx context.Context, in *schema.ZScanOptions, opts ...grpc.CallOption) (*schema.ZItemList, error) {
 	return &schema.ZItemList{}, nil
 }
+func (m *immuServiceClientMock) SafeZScan(ctx context.Context, in *schema.SafeZScanOptions, opts ...grpc.CallOption) (*schema.SafeZItemList, error) {
+	return &schema.SafeZItemList{}, nil
+}
 func (m *immuServiceClientMock) SafeZAdd(ctx context.Context, in *schema.SafeZAddOptions, opts ...grpc.CallOption) (*schema.Proof, error) {
 	return &schema.Proof{}, nil
 }
@@ -0,0 +1,209 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqldriver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmptyStatement is returned when the statement holds no command
+var ErrEmptyStatement = errors.New("empty statement")
+
+// ErrUnterminatedString is returned when a quoted argument is not closed
+var ErrUnterminatedString = errors.New("unterminated quoted string")
+
+type commandSpec struct {
+	query bool
+	args  int
+	limit bool
+}
+
+var commands = map[string]commandSpec{
+	"SET":       {args: 2},
+	"SAFESET":   {args: 2},
+	"REFERENCE": {args: 2},
+	"GET":       {query: true, args: 1},
+	"SAFEGET":   {query: true, args: 1},
+	"SCAN":      {query: true, args: 1, limit: true},
+	"HISTORY":   {query: true, args: 1, limit: true},
+	"COUNT":     {query: true, args: 1},
+}
+
+type token struct {
+	text        string
+	placeholder bool
+}
+
+type command struct {
+	name         string
+	args         []token
+	limit        *token
+	placeholders int
+}
+
+// parseCommand splits the statement into its command name and arguments and checks them
+// against the grammar; placeholders are bound later, when the statement is executed.
+func parseCommand(statement string) (*command, error) {
+	tokens, err := tokenize(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmptyStatement
+	}
+	if tokens[0].placeholder {
+		return nil, fmt.Errorf("invalid statement: command expected, found placeholder")
+	}
+
+	name := strings.ToUpper(tokens[0].text)
+	spec, ok := commands[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q", tokens[0].text)
+	}
+
+	cmd := &command{name: name}
+	rest := tokens[1:]
+	if len(rest) < spec.args {
+		return nil, fmt.Errorf("%s expects %d arguments, %d given", name, spec.args, len(rest))
+	}
+	cmd.args, rest = rest[:spec.args], rest[spec.args:]
+
+	if spec.limit && len(rest) == 2 && !rest[0].placeholder && strings.ToUpper(rest[0].text) == "LIMIT" {
+		cmd.limit, rest = &rest[1], rest[2:]
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%s expects %d arguments, %d given", name, spec.args, len(tokens)-1)
+	}
+
+	for _, t := range cmd.args {
+		if t.placeholder {
+			cmd.placeholders++
+		}
+	}
+	if cmd.limit != nil && cmd.limit.placeholder {
+		cmd.placeholders++
+	}
+
+	return cmd, nil
+}
+
+func (c *command) isQuery() bool {
+	return commands[c.name].query
+}
+
+// bind resolves the command arguments, replacing placeholders with the given values in order
+func (c *command) bind(values []driver.NamedValue) (args [][]byte, limit uint64, err error) {
+	if len(values) != c.placeholders {
+		return nil, 0, fmt.Errorf("%s expects %d placeholder values, %d given", c.name, c.placeholders, len(values))
+	}
+
+	next := 0
+	resolve := func(t token) ([]byte, error) {
+		if !t.placeholder {
+			return []byte(t.text), nil
+		}
+		v := values[next]
+		next++
+		return toBytes(v.Value)
+	}
+
+	for _, t := range c.args {
+		arg, err := resolve(t)
+		if err != nil {
+			return nil, 0, err
+		}
+		args = append(args, arg)
+	}
+
+	if c.limit != nil {
+		l, err := resolve(*c.limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		if limit, err = strconv.ParseUint(string(l), 10, 64); err != nil {
+			return nil, 0, fmt.Errorf("invalid limit %q", l)
+		}
+	}
+
+	return args, limit, nil
+}
+
+func tokenize(statement string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(statement); {
+		switch ch := statement[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '?':
+			tokens = append(tokens, token{placeholder: true})
+			i++
+		case ch == '\'':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(statement) {
+					return nil, ErrUnterminatedString
+				}
+				if statement[i] == '\'' {
+					if i+1 < len(statement) && statement[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(statement[i])
+				i++
+			}
+			tokens = append(tokens, token{text: sb.String()})
+		default:
+			start := i
+			for i < len(statement) && !strings.ContainsRune(" \t\n\r?'", rune(statement[i])) {
+				i++
+			}
+			tokens = append(tokens, token{text: statement[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+func toBytes(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case int64:
+		return []byte(strconv.FormatInt(v, 10)), nil
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case bool:
+		return []byte(strconv.FormatBool(v)), nil
+	case time.Time:
+		return []byte(v.Format(time.RFC3339Nano)), nil
+	case nil:
+		return nil, errors.New("nil values are not supported")
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
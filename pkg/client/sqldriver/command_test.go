@@ -0,0 +1,66 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqldriver
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	cmd, err := parseCommand("  set ? 'it''s a value'")
+	require.NoError(t, err)
+	require.Equal(t, "SET", cmd.name)
+	require.False(t, cmd.isQuery())
+	require.Equal(t, 1, cmd.placeholders)
+
+	args, _, err := cmd.bind([]driver.NamedValue{{Ordinal: 1, Value: int64(42)}})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("42"), []byte("it's a value")}, args)
+
+	cmd, err = parseCommand("SCAN user: LIMIT ?")
+	require.NoError(t, err)
+	require.True(t, cmd.isQuery())
+	args, limit, err := cmd.bind([]driver.NamedValue{{Ordinal: 1, Value: "10"}})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("user:")}, args)
+	require.Equal(t, uint64(10), limit)
+
+	_, _, err = cmd.bind(nil)
+	require.Error(t, err)
+	_, _, err = cmd.bind([]driver.NamedValue{{Ordinal: 1, Value: "ten"}})
+	require.Error(t, err)
+	_, _, err = cmd.bind([]driver.NamedValue{{Ordinal: 1, Value: nil}})
+	require.Error(t, err)
+
+	_, err = parseCommand(" ")
+	require.Equal(t, ErrEmptyStatement, err)
+	_, err = parseCommand("GET 'key")
+	require.Equal(t, ErrUnterminatedString, err)
+	_, err = parseCommand("DELETE key")
+	require.Error(t, err)
+	_, err = parseCommand("? key")
+	require.Error(t, err)
+	_, err = parseCommand("GET")
+	require.Error(t, err)
+	_, err = parseCommand("GET key other")
+	require.Error(t, err)
+	_, err = parseCommand("COUNT prefix LIMIT 1")
+	require.Error(t, err)
+}
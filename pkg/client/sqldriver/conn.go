@@ -0,0 +1,310 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqldriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrTxNotSupported is returned by Begin: every write is committed on its own
+var ErrTxNotSupported = errors.New("transactions are not supported")
+
+// ErrNotQuery is returned when a query command is executed through Exec or vice versa
+var ErrNotQuery = errors.New("command does not match the call: use Exec for writes and Query for reads")
+
+var (
+	_ driver.Conn               = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.StmtExecContext    = (*stmt)(nil)
+	_ driver.StmtQueryContext   = (*stmt)(nil)
+)
+
+type conn struct {
+	client client.ImmuClient
+	token  string
+}
+
+// context attaches the session token to the outgoing metadata, keeping any metadata already set
+// by the caller (e.g. tracing headers)
+func (c *conn) context(ctx context.Context) context.Context {
+	if c.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", c.token)
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	cmd, err := parseCommand(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{conn: c, cmd: cmd}, nil
+}
+
+func (c *conn) Close() error {
+	return c.client.Disconnect()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, ErrTxNotSupported
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if err := c.client.HealthCheck(c.context(ctx)); err != nil {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	cmd, err := parseCommand(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.exec(ctx, cmd, args)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	cmd, err := parseCommand(query)
+	if err != nil {
+		return nil, err
+	}
+	return c.query(ctx, cmd, args)
+}
+
+func (c *conn) exec(ctx context.Context, cmd *command, values []driver.NamedValue) (driver.Result, error) {
+	if cmd.isQuery() {
+		return nil, ErrNotQuery
+	}
+	args, _, err := cmd.bind(values)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = c.context(ctx)
+
+	switch cmd.name {
+	case "SET":
+		idx, err := c.client.Set(ctx, args[0], args[1])
+		if err != nil {
+			return nil, err
+		}
+		return result(idx.GetIndex()), nil
+	case "SAFESET":
+		vi, err := c.client.SafeSet(ctx, args[0], args[1])
+		if err != nil {
+			return nil, err
+		}
+		return result(vi.Index), nil
+	default: // REFERENCE
+		idx, err := c.client.Reference(ctx, args[0], args[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		return result(idx.GetIndex()), nil
+	}
+}
+
+func (c *conn) query(ctx context.Context, cmd *command, values []driver.NamedValue) (driver.Rows, error) {
+	if !cmd.isQuery() {
+		return nil, ErrNotQuery
+	}
+	args, limit, err := cmd.bind(values)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = c.context(ctx)
+
+	switch cmd.name {
+	case "GET":
+		item, err := c.client.Get(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return newItemRows(item), nil
+	case "SAFEGET":
+		vi, err := c.client.SafeGet(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &verifiedItemRows{item: vi}, nil
+	case "SCAN":
+		list, err := c.client.Scan(ctx, &schema.ScanOptions{Prefix: args[0], Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		return newItemRows(list.GetItems()...), nil
+	case "HISTORY":
+		list, err := c.client.History(ctx, &schema.HistoryOptions{Key: args[0], Limit: limit})
+		if err != nil {
+			return nil, err
+		}
+		return newItemRows(list.GetItems()...), nil
+	default: // COUNT
+		count, err := c.client.Count(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &countRows{count: count.GetCount()}, nil
+	}
+}
+
+type stmt struct {
+	conn *conn
+	cmd  *command
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return s.cmd.placeholders
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.exec(context.Background(), s.cmd, namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(context.Background(), s.cmd, namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.exec(ctx, s.cmd, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.query(ctx, s.cmd, args)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+// result reports the index of the written entry as LastInsertId
+type result uint64
+
+func (r result) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+var itemColumns = []string{"key", "value", "index", "time"}
+
+type itemRows struct {
+	items []*schema.StructuredItem
+	pos   int
+}
+
+func newItemRows(items ...*schema.StructuredItem) *itemRows {
+	return &itemRows{items: items}
+}
+
+func (r *itemRows) Columns() []string {
+	return itemColumns
+}
+
+func (r *itemRows) Close() error {
+	return nil
+}
+
+func (r *itemRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.items) {
+		return io.EOF
+	}
+	item := r.items[r.pos]
+	r.pos++
+
+	dest[0] = item.GetKey()
+	dest[1] = item.GetValue().GetPayload()
+	dest[2] = int64(item.GetIndex())
+	dest[3] = int64(item.GetValue().GetTimestamp())
+	return nil
+}
+
+type verifiedItemRows struct {
+	item *client.VerifiedItem
+	done bool
+}
+
+func (r *verifiedItemRows) Columns() []string {
+	return append(itemColumns[:len(itemColumns):len(itemColumns)], "verified")
+}
+
+func (r *verifiedItemRows) Close() error {
+	return nil
+}
+
+func (r *verifiedItemRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+
+	dest[0] = r.item.Key
+	dest[1] = r.item.Value
+	dest[2] = int64(r.item.Index)
+	dest[3] = int64(r.item.Time)
+	dest[4] = r.item.Verified
+	return nil
+}
+
+type countRows struct {
+	count uint64
+	done  bool
+}
+
+func (r *countRows) Columns() []string {
+	return []string{"count"}
+}
+
+func (r *countRows) Close() error {
+	return nil
+}
+
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+
+	dest[0] = int64(r.count)
+	return nil
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"google.golang.org/grpc"
+)
+
+// tracingDialOptions returns the dial options tracing the calls with options.Tracer, if any. They come before the
+// other interceptors, so that the spans also cover the time spent compressing and in the circuit breaker.
+func tracingDialOptions(options *Options) []grpc.DialOption {
+	if options.Tracer == nil {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor(options.Tracer)),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor(options.Tracer)),
+	}
+}
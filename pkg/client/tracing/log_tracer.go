@@ -0,0 +1,129 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+)
+
+// TraceParentHeader is the W3C trace context header forwarding the span to the server
+const TraceParentHeader = "traceparent"
+
+type spanContextKey struct{}
+
+// LogTracer logs every ended span with its duration, attributes and error, if any. The spans are identified
+// by W3C trace context IDs, forwarded to the server in the traceparent header.
+type LogTracer struct {
+	logger logger.Logger
+	// threshold is the minimum duration of the logged spans
+	threshold time.Duration
+}
+
+// NewLogTracer returns a tracer logging with l the spans lasting at least threshold, e.g. 0 to log them all
+func NewLogTracer(l logger.Logger, threshold time.Duration) *LogTracer {
+	return &LogTracer{logger: l, threshold: threshold}
+}
+
+type logSpan struct {
+	tracer  *LogTracer
+	name    string
+	traceID string
+	spanID  string
+	start   time.Time
+
+	mu         sync.Mutex
+	attributes []Attribute
+	err        error
+	ended      bool
+}
+
+// Start implements Tracer
+func (t *LogTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	span := &logSpan{
+		tracer:     t,
+		name:       name,
+		spanID:     randomID(8),
+		start:      time.Now(),
+		attributes: attributes,
+	}
+	if parent, ok := ctx.Value(spanContextKey{}).(*logSpan); ok {
+		span.traceID = parent.traceID
+	} else {
+		span.traceID = randomID(16)
+	}
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// Inject implements Propagator
+func (t *LogTracer) Inject(ctx context.Context, set func(key string, value string)) {
+	if span, ok := ctx.Value(spanContextKey{}).(*logSpan); ok {
+		set(TraceParentHeader, span.traceParent())
+	}
+}
+
+func (s *logSpan) traceParent() string {
+	return fmt.Sprintf("00-%s-%s-01", s.traceID, s.spanID)
+}
+
+func (s *logSpan) SetAttributes(attributes ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+func (s *logSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *logSpan) End() {
+	elapsed := time.Since(s.start)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended || elapsed < s.tracer.threshold {
+		s.ended = true
+		return
+	}
+	s.ended = true
+
+	var attributes strings.Builder
+	for _, a := range s.attributes {
+		fmt.Fprintf(&attributes, " %s=%v", a.Key, a.Value)
+	}
+	if s.err != nil {
+		s.tracer.logger.Warningf("span %s %s failed after %s:%s error=%v",
+			s.name, s.traceParent(), elapsed, attributes.String(), s.err)
+		return
+	}
+	s.tracer.logger.Infof("span %s %s ended after %s:%s", s.name, s.traceParent(), elapsed, attributes.String())
+}
+
+func randomID(size int) string {
+	id := make([]byte, size)
+	// an ID from a failed read only makes the trace harder to follow
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
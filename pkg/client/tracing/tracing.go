@@ -0,0 +1,131 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing instruments the client calls and the audits with spans. Its API mirrors the OpenTelemetry
+// tracing API, so that an OpenTelemetry tracer is plugged in with a thin adapter implementing Tracer and,
+// to correlate the client spans with the server-side traces, Propagator.
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Attribute is a key-value pair describing a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string attribute
+func String(key string, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 returns an integer attribute
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a timed operation, ended by End
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts the spans. The span is a child of the span carried by ctx, if any, and the returned context
+// carries the new span.
+type Tracer interface {
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// Propagator is implemented by the tracers able to forward the span carried by ctx to the server,
+// e.g. as a W3C traceparent header, through set
+type Propagator interface {
+	Inject(ctx context.Context, set func(key string, value string))
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// Start starts a span with tracer, which may be nil to disable tracing
+func Start(tracer Tracer, ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, name, attributes...)
+}
+
+// End records err, if not nil, and ends span
+func End(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// UnaryClientInterceptor traces every unary call with a span named after the gRPC method, forwarding the span
+// to the server if tracer is a Propagator
+func UnaryClientInterceptor(tracer Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := tracer.Start(ctx, method, String("rpc.system", "grpc"), String("rpc.method", method))
+		ctx = inject(ctx, tracer)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		span.SetAttributes(Int64("rpc.grpc.status_code", int64(status.Code(err))))
+		End(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor traces the opening of every stream with a span named after the gRPC method,
+// forwarding the span to the server if tracer is a Propagator
+func StreamClientInterceptor(tracer Tracer) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := tracer.Start(ctx, method, String("rpc.system", "grpc"), String("rpc.method", method))
+		ctx = inject(ctx, tracer)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		span.SetAttributes(Int64("rpc.grpc.status_code", int64(status.Code(err))))
+		End(span, err)
+		return stream, err
+	}
+}
+
+func inject(ctx context.Context, tracer Tracer) context.Context {
+	propagator, ok := tracer.(Propagator)
+	if !ok {
+		return ctx
+	}
+	var kv []string
+	propagator.Inject(ctx, func(key string, value string) {
+		kv = append(kv, key, value)
+	})
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
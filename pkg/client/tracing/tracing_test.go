@@ -0,0 +1,130 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordedSpan{name: name, attributes: map[string]interface{}{}}
+	span.SetAttributes(attributes...)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (s *recordedSpan) SetAttributes(attributes ...Attribute) {
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+func TestStartWithoutTracer(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := Start(nil, ctx, "noop", String("k", "v"))
+	assert.Equal(t, ctx, spanCtx)
+	span.SetAttributes(Bool("b", true))
+	End(span, errors.New("ignored"))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tracer := &recordingTracer{}
+	interceptor := UnaryClientInterceptor(tracer)
+
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	require.NoError(t, interceptor(context.Background(), "/immudb.schema.ImmuService/CurrentRoot", nil, nil, nil, invoker))
+
+	failure := status.Error(codes.Unavailable, "down")
+	invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return failure
+	}
+	require.Equal(t, failure, interceptor(context.Background(), "/immudb.schema.ImmuService/Consistency", nil, nil, nil, invoker))
+
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, "/immudb.schema.ImmuService/CurrentRoot", tracer.spans[0].name)
+	assert.True(t, tracer.spans[0].ended)
+	assert.Nil(t, tracer.spans[0].err)
+	assert.Equal(t, int64(codes.OK), tracer.spans[0].attributes["rpc.grpc.status_code"])
+	assert.Equal(t, "grpc", tracer.spans[0].attributes["rpc.system"])
+	assert.True(t, tracer.spans[1].ended)
+	assert.Equal(t, failure, tracer.spans[1].err)
+	assert.Equal(t, int64(codes.Unavailable), tracer.spans[1].attributes["rpc.grpc.status_code"])
+}
+
+func TestLogTracer(t *testing.T) {
+	var out bytes.Buffer
+	tracer := NewLogTracer(logger.NewSimpleLogger("test", &out), 0)
+
+	ctx, parent := tracer.Start(context.Background(), "audit", Int64("audit.index", 1))
+	var traceParent string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		traceParent = md.Get(TraceParentHeader)[0]
+		return errors.New("unavailable")
+	}
+	require.Error(t, UnaryClientInterceptor(tracer)(ctx, "/immudb.schema.ImmuService/Login", nil, nil, nil, invoker))
+	parent.End()
+	parent.End()
+
+	require.Regexp(t, "^00-[0-9a-f]{32}-[0-9a-f]{16}-01$", traceParent)
+	parentSpan := parent.(*logSpan)
+	assert.Equal(t, parentSpan.traceID, traceParent[3:35], "child spans must belong to the trace of their parent")
+	assert.NotEqual(t, parentSpan.spanID, traceParent[36:52])
+
+	logged := out.String()
+	assert.Regexp(t, regexp.MustCompile(`span /immudb.schema.ImmuService/Login 00-\S+ failed after \S+: .*error=unavailable`), logged)
+	assert.Regexp(t, `span audit 00-\S+ ended after \S+: audit.index=1`, logged)
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("span audit ")), "a span must be logged once")
+
+	out.Reset()
+	_, span := NewLogTracer(logger.NewSimpleLogger("test", &out), time.Hour).Start(context.Background(), "fast")
+	span.End()
+	assert.Empty(t, out.String(), "spans faster than the threshold must not be logged")
+}
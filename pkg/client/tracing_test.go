@@ -0,0 +1,67 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/client/tracing"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/server/servertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTracedCalls(t *testing.T) {
+	bs := servertest.NewBufconnServer(server.DefaultOptions().WithAuth(true).WithInMemoryStore(true))
+	bs.Start()
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithContextDialer(bs.Dialer), grpc.WithInsecure(),
+	}
+	dir, err := ioutil.TempDir("", "traced_client")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	var out bytes.Buffer
+	tracer := tracing.NewLogTracer(logger.NewSimpleLogger("test", &out), 0)
+	opts := DefaultOptions().
+		WithDir(dir).
+		WithDialOptions(&dialOptions).
+		WithTokenService(NewTokenService().WithTokenFileName("testTokenFile").WithHds(NewHomedirService())).
+		WithTracer(tracer)
+
+	client, err := NewImmuClient(opts)
+	require.NoError(t, err)
+	defer client.Disconnect()
+	lresp, err := client.Login(context.TODO(), []byte("immudb"), []byte("immudb"))
+	require.NoError(t, err)
+	ctx := metadata.NewOutgoingContext(context.TODO(), metadata.Pairs("authorization", lresp.Token))
+	_, err = client.Set(ctx, []byte("key"), []byte("value"))
+	require.NoError(t, err)
+	_, err = client.Get(ctx, []byte("missing"))
+	require.Error(t, err)
+
+	require.Contains(t, out.String(), "span /immudb.schema.ImmuService/Login 00-")
+	require.Contains(t, out.String(), "span /immudb.schema.ImmuService/Set 00-")
+	require.Regexp(t, `span /immudb.schema.ImmuService/Get 00-\S+ failed after \S+: .*rpc.grpc.status_code=\d+ error=`, out.String())
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors related to interactive transactions
+var (
+	ErrTxConflict = errors.New("transaction conflict: a key read by the transaction has been modified")
+	ErrTxDone     = errors.New("transaction has already been committed or discarded")
+)
+
+// Tx is an interactive transaction started with Begin, for read-modify-write workflows.
+// Keys are read from the server once, all of them as they were at the index of the tree current at the first read,
+// so that the reads are a consistent snapshot of the database. Writes are buffered until Commit, which writes them
+// atomically only if none of the keys read has been modified since the snapshot, so that every read is consistent
+// with the state the writes are committed on. A Tx must not be used concurrently.
+type Tx struct {
+	client *immuClient
+	// snapshot is the root of the tree the reads are pinned to, taken by the first read
+	snapshot *schema.RootIndex
+	reads    map[string]*schema.StructuredItem
+	order    [][]byte
+	writes   map[string][]byte
+	keys     [][]byte
+	done     bool
+}
+
+// Begin starts an interactive transaction
+func (c *immuClient) Begin(ctx context.Context) (*Tx, error) {
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+	return &Tx{
+		client: c,
+		reads:  map[string]*schema.StructuredItem{},
+		writes: map[string][]byte{},
+	}, nil
+}
+
+// Get returns the value of key as seen by the transaction: the value set by the transaction itself if any, otherwise
+// the item key had in the snapshot of the transaction. A missing key is reported with a codes.NotFound error, and
+// is expected to be still missing on commit.
+func (tx *Tx) Get(ctx context.Context, key []byte) (*schema.StructuredItem, error) {
+	if tx.done {
+		return nil, ErrTxDone
+	}
+	if value, ok := tx.writes[string(key)]; ok {
+		return &schema.StructuredItem{Key: key, Value: &schema.Content{Payload: value}}, nil
+	}
+	item, ok := tx.reads[string(key)]
+	if !ok {
+		var err error
+		if item, err = tx.read(ctx, key); err != nil {
+			return nil, err
+		}
+		tx.reads[string(key)] = item
+		tx.order = append(tx.order, key)
+	}
+	if item == nil {
+		return nil, store.ErrKeyNotFound
+	}
+	return item, nil
+}
+
+// read reads key from the server as it was in the snapshot of the transaction, taking the snapshot on the first read.
+// It returns a nil item if key was missing.
+func (tx *Tx) read(ctx context.Context, key []byte) (*schema.StructuredItem, error) {
+	if tx.snapshot == nil {
+		root, err := tx.client.ServiceClient.CurrentRoot(ctx, &empty.Empty{})
+		if err != nil {
+			return nil, err
+		}
+		tx.snapshot = &schema.RootIndex{Index: root.GetPayload().GetIndex(), Root: root.GetPayload().GetRoot()}
+	}
+	if len(tx.snapshot.Root) == 0 {
+		// the database was empty
+		return nil, nil
+	}
+	item, err := tx.client.ServiceClient.GetAt(ctx, &schema.GetAtOptions{Key: key, Index: tx.snapshot.Index})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item.GetItem().ToSItem()
+}
+
+// Set buffers the write of key, it's sent to the server on commit
+func (tx *Tx) Set(key []byte, value []byte) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	if _, ok := tx.writes[string(key)]; !ok {
+		tx.keys = append(tx.keys, key)
+	}
+	tx.writes[string(key)] = value
+	return nil
+}
+
+// Commit writes the buffered values as a single transaction, returning the index of its commit entry.
+// It fails with ErrTxConflict if any key read by the transaction has been modified since the snapshot, in which case
+// nothing is written and the whole read-modify-write can be retried with a new transaction.
+// A transaction without writes has nothing to commit and returns a nil index.
+func (tx *Tx) Commit(ctx context.Context) (*schema.Index, error) {
+	if tx.done {
+		return nil, ErrTxDone
+	}
+	tx.done = true
+	if len(tx.keys) == 0 {
+		return nil, nil
+	}
+
+	ops := &schema.Ops{}
+	for _, key := range tx.keys {
+		ops.Operations = append(ops.Operations, &schema.Op{
+			Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: key, Value: tx.writes[string(key)]}},
+		})
+	}
+	expected := make([]*schema.ExpectedIndex, 0, len(tx.order))
+	for _, key := range tx.order {
+		e := &schema.ExpectedIndex{Key: key}
+		if item := tx.reads[string(key)]; item != nil {
+			e.Index = &schema.Index{Index: item.Index}
+		}
+		expected = append(expected, e)
+	}
+	return tx.client.CompareAndExecAllTx(ctx, ops, expected)
+}
+
+// Discard abandons the transaction without writing anything, it's a no-op once the transaction is done
+func (tx *Tx) Discard() {
+	tx.done = true
+}
+
+// CompareAndExecAllTx is like ExecAllTx, provided that each of the expected keys is at the expected index, a missing
+// index meaning that the key is expected not to exist. It fails with ErrTxConflict if any of them is not.
+func (c *immuClient) CompareAndExecAllTx(ctx context.Context, ops *schema.Ops, expected []*schema.ExpectedIndex) (*schema.Index, error) {
+	start := time.Now()
+
+	if !c.IsConnected() {
+		return nil, ErrNotConnected
+	}
+
+	ops, err := c.NewSOps(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.ServiceClient.CompareAndExecAllTx(ctx, &schema.CompareAndExecAllTxOptions{
+		Ops:      ops,
+		Expected: expected,
+	})
+	if s, ok := status.FromError(err); ok && err != nil &&
+		s.Code() == codes.Aborted && s.Message() == status.Convert(store.ErrTxConflict).Message() {
+		return nil, ErrTxConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.Logger.Debugf("compareandexecalltx finished in %s", time.Since(start))
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTx(t *testing.T) {
+	setup()
+	defer client.Disconnect()
+	ctx := context.TODO()
+
+	_, err := client.Set(ctx, []byte(`txBalance`), []byte(`100`))
+	require.NoError(t, err)
+
+	tx, err := client.Begin(ctx)
+	require.NoError(t, err)
+	item, err := tx.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`100`), item.Value.Payload)
+	_, err = tx.Get(ctx, []byte(`txWithdrawal`))
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	require.NoError(t, tx.Set([]byte(`txBalance`), []byte(`90`)))
+	require.NoError(t, tx.Set([]byte(`txWithdrawal`), []byte(`10`)))
+	// the transaction sees its own writes
+	item, err = tx.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`90`), item.Value.Payload)
+
+	index, err := tx.Commit(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, index)
+	_, err = tx.Commit(ctx)
+	require.Equal(t, ErrTxDone, err)
+	require.Equal(t, ErrTxDone, tx.Set([]byte(`txBalance`), []byte(`0`)))
+
+	item, err = client.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`90`), item.Value.Payload)
+
+	// a key read by the transaction is modified concurrently
+	tx, err = client.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	_, err = client.Set(ctx, []byte(`txBalance`), []byte(`50`))
+	require.NoError(t, err)
+	// reads are repeatable within the transaction
+	item, err = tx.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`90`), item.Value.Payload)
+	require.NoError(t, tx.Set([]byte(`txBalance`), []byte(`80`)))
+	_, err = tx.Commit(ctx)
+	require.Equal(t, ErrTxConflict, err)
+
+	item, err = client.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`50`), item.Value.Payload)
+
+	// a key read as missing is created concurrently
+	tx, err = client.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Get(ctx, []byte(`txLock`))
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.Set(ctx, []byte(`txLock`), []byte(`owner1`))
+	require.NoError(t, err)
+	require.NoError(t, tx.Set([]byte(`txLock`), []byte(`owner2`)))
+	_, err = tx.Commit(ctx)
+	require.Equal(t, ErrTxConflict, err)
+
+	// reads see the database as it was at the first read of the transaction
+	tx, err = client.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Get(ctx, []byte(`txBalance`))
+	require.NoError(t, err)
+	_, err = client.Set(ctx, []byte(`txOther`), []byte(`1`))
+	require.NoError(t, err)
+	_, err = tx.Get(ctx, []byte(`txOther`))
+	require.Equal(t, codes.NotFound, status.Code(err))
+	tx.Discard()
+
+	tx, err = client.Begin(ctx)
+	require.NoError(t, err)
+	tx.Discard()
+	_, err = tx.Get(ctx, []byte(`txBalance`))
+	require.Equal(t, ErrTxDone, err)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import "encoding/json"
+
+// Codec marshals the values of type T to the bytes stored in immudb, and back
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+type jsonCodec[T any] struct{}
+
+// JSON returns the codec marshaling the values to JSON with encoding/json
+func JSON[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+func (jsonCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[T]) Unmarshal(data []byte) (value T, err error) {
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
+type funcCodec[T any] struct {
+	marshal   func(T) ([]byte, error)
+	unmarshal func([]byte) (T, error)
+}
+
+// Funcs returns the codec marshaling the values with the given functions, e.g. to reuse the encoding of an
+// existing format
+func Funcs[T any](marshal func(T) ([]byte, error), unmarshal func([]byte) (T, error)) Codec[T] {
+	return funcCodec[T]{marshal: marshal, unmarshal: unmarshal}
+}
+
+func (c funcCodec[T]) Marshal(value T) ([]byte, error) {
+	return c.marshal(value)
+}
+
+func (c funcCodec[T]) Unmarshal(data []byte) (T, error) {
+	return c.unmarshal(data)
+}
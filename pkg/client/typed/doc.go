@@ -0,0 +1,28 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package typed provides accessors of immudb keys holding values of a Go type, marshaled by a Codec, so that
+// applications read and write their entities with no marshaling code of their own:
+//
+//	users := typed.NewStore[User](c, "users/", typed.JSON[User]())
+//	index, err := users.Put(ctx, "alice", User{Name: "Alice"})
+//	alice, err := users.Get(ctx, "alice")
+//
+// The package is built on type parameters, so it's a module of its own requiring Go 1.18 or later, while the rest of
+// immudb still builds with Go 1.13:
+//
+//	go get github.com/codenotary/immudb/pkg/client/typed
+package typed
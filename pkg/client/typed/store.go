@@ -0,0 +1,152 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors related to typed stores
+var (
+	ErrNotFound    = errors.New("no value is stored under the key")
+	ErrNotVerified = errors.New("the value could not be verified")
+)
+
+// Entry is a value read by Scan, along with its key, without the prefix of the store, and the index of the entry
+// holding it
+type Entry[T any] struct {
+	Key   string
+	Value T
+	Index uint64
+}
+
+// Page is a page of Scan, see client.WithPageToken to read the following ones
+type Page[T any] struct {
+	Entries []Entry[T]
+	client.PageInfo
+}
+
+// Store reads and writes the values of type T under the keys starting with a prefix, e.g. a collection of entities
+type Store[T any] struct {
+	client   client.ImmuClient
+	prefix   string
+	codec    Codec[T]
+	verified bool
+}
+
+// NewStore returns the store of the values of type T under prefix, marshaled by codec. A nil codec marshals them
+// to JSON.
+func NewStore[T any](c client.ImmuClient, prefix string, codec Codec[T]) *Store[T] {
+	if codec == nil {
+		codec = JSON[T]()
+	}
+	return &Store[T]{client: c, prefix: prefix, codec: codec}
+}
+
+// WithVerification makes Put and Get verify the entries against the trusted root, as SafeSet and SafeGet do.
+// Unverified reads fail with ErrNotVerified.
+func (s *Store[T]) WithVerification(verified bool) *Store[T] {
+	s.verified = verified
+	return s
+}
+
+// Put stores value under key and returns the index of the entry
+func (s *Store[T]) Put(ctx context.Context, key string, value T) (uint64, error) {
+	data, err := s.codec.Marshal(value)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling the value of %s: %v", key, err)
+	}
+	if s.verified {
+		vi, err := s.client.SafeSet(ctx, []byte(s.prefix+key), data)
+		if err != nil {
+			return 0, err
+		}
+		if !vi.Verified {
+			return 0, fmt.Errorf("%w: %s", ErrNotVerified, key)
+		}
+		return vi.Index, nil
+	}
+	index, err := s.client.Set(ctx, []byte(s.prefix+key), data)
+	if err != nil {
+		return 0, err
+	}
+	return index.GetIndex(), nil
+}
+
+// Get returns the value stored under key, failing with ErrNotFound if there's none or if it has been deleted
+func (s *Store[T]) Get(ctx context.Context, key string) (value T, err error) {
+	var data []byte
+	var deleted bool
+	if s.verified {
+		var vi *client.VerifiedItem
+		if vi, err = s.client.SafeGet(ctx, []byte(s.prefix+key)); err == nil {
+			if !vi.Verified {
+				return value, fmt.Errorf("%w: %s", ErrNotVerified, key)
+			}
+			data, deleted = vi.Value, vi.Deleted
+		}
+	} else {
+		var item *schema.StructuredItem
+		if item, err = s.client.Get(ctx, []byte(s.prefix+key)); err == nil {
+			data, deleted = item.GetValue().GetPayload(), item.GetDeleted()
+		}
+	}
+	if status.Code(err) == codes.NotFound || (err == nil && deleted) {
+		return value, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return value, err
+	}
+	return s.unmarshal(key, data)
+}
+
+// Scan returns the values stored under the keys starting with keyPrefix, in key order, up to limit of them if limit
+// is greater than zero. Deleted values are skipped.
+func (s *Store[T]) Scan(ctx context.Context, keyPrefix string, limit uint64) (*Page[T], error) {
+	list, err := s.client.Scan(ctx, &schema.ScanOptions{Prefix: []byte(s.prefix + keyPrefix), Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	page := &Page[T]{Entries: make([]Entry[T], 0, len(list.Items)), PageInfo: list.PageInfo}
+	for _, item := range list.Items {
+		if item.GetDeleted() {
+			continue
+		}
+		key := string(item.Key[len(s.prefix):])
+		value, err := s.unmarshal(key, item.GetValue().GetPayload())
+		if err != nil {
+			return nil, err
+		}
+		page.Entries = append(page.Entries, Entry[T]{Key: key, Value: value, Index: item.Index})
+	}
+	return page, nil
+}
+
+func (s *Store[T]) unmarshal(key string, data []byte) (T, error) {
+	value, err := s.codec.Unmarshal(data)
+	if err != nil {
+		return value, fmt.Errorf("unmarshaling the value of %s: %v", key, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package typed
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+func TestStore(t *testing.T) {
+	cli, ctx, closer, err := clienttest.NewBufconnClient(server.DefaultOptions().WithInMemoryStore(true))
+	require.NoError(t, err)
+	defer closer()
+
+	users := NewStore[user](cli, "users/", nil)
+	_, err = users.Get(ctx, "alice")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	index, err := users.Put(ctx, "alice", user{Name: "Alice", Admin: true})
+	require.NoError(t, err)
+	_, err = users.Put(ctx, "bob", user{Name: "Bob"})
+	require.NoError(t, err)
+	_, err = cli.Set(ctx, []byte("other"), []byte("not a user"))
+	require.NoError(t, err)
+
+	alice, err := users.Get(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, user{Name: "Alice", Admin: true}, alice)
+
+	page, err := users.Scan(ctx, "", 0)
+	require.NoError(t, err)
+	require.Equal(t, []Entry[user]{
+		{Key: "alice", Value: user{Name: "Alice", Admin: true}, Index: index},
+		{Key: "bob", Value: user{Name: "Bob"}, Index: index + 1},
+	}, page.Entries)
+	require.False(t, page.More)
+
+	page, err = users.Scan(ctx, "", 1)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	require.True(t, page.More)
+	page, err = users.Scan(client.WithPageToken(ctx, page.NextPageToken), "", 1)
+	require.NoError(t, err)
+	require.Equal(t, "bob", page.Entries[0].Key)
+
+	_, err = cli.Delete(ctx, []byte("users/bob"))
+	require.NoError(t, err)
+	_, err = users.Get(ctx, "bob")
+	require.True(t, errors.Is(err, ErrNotFound))
+	page, err = users.Scan(ctx, "", 0)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+
+	verified := NewStore[user](cli, "users/", JSON[user]()).WithVerification(true)
+	_, err = verified.Put(ctx, "carol", user{Name: "Carol"})
+	require.NoError(t, err)
+	carol, err := verified.Get(ctx, "carol")
+	require.NoError(t, err)
+	require.Equal(t, "Carol", carol.Name)
+	_, err = verified.Get(ctx, "bob")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	// values which can't be unmarshaled are reported along with their key
+	_, err = cli.Set(ctx, []byte("users/dave"), []byte("not a user"))
+	require.NoError(t, err)
+	_, err = users.Get(ctx, "dave")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dave")
+}
+
+func TestFuncs(t *testing.T) {
+	cli, ctx, closer, err := clienttest.NewBufconnClient(server.DefaultOptions().WithInMemoryStore(true))
+	require.NoError(t, err)
+	defer closer()
+
+	counters := NewStore[int](cli, "counters/", Funcs(
+		func(v int) ([]byte, error) { return []byte(strconv.Itoa(v)), nil },
+		func(data []byte) (int, error) { return strconv.Atoi(string(data)) },
+	))
+	_, err = counters.Put(ctx, "visits", 42)
+	require.NoError(t, err)
+	visits, err := counters.Get(ctx, "visits")
+	require.NoError(t, err)
+	require.Equal(t, 42, visits)
+
+	raw, err := cli.Get(ctx, []byte("counters/visits"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("42"), raw.Value.Payload)
+}
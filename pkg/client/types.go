@@ -57,6 +57,7 @@ func (c *immuClient) WithTokenService(tokenService TokenService) *immuClient {
 
 func (c *immuClient) WithOptions(options *Options) *immuClient {
 	c.Options = options
+	c.metrics = newClientMetrics(options.MetricsRegisterer)
 	return c
 }
 
@@ -94,7 +95,7 @@ func (c *immuClient) NewSOps(ops *schema.Ops) (*schema.Ops, error) {
 				return nil, err
 			}
 			x.KVs = kv
-		case *schema.Op_ZOpts:
+		case *schema.Op_ZOpts, *schema.Op_ROpts:
 			continue
 		case nil:
 			continue
@@ -111,13 +112,97 @@ type VerifiedItem struct {
 	Value    []byte `json:"value"`
 	Index    uint64 `json:"index"`
 	Time     uint64 `json:"time"`
+	Deleted  bool   `json:"deleted,omitempty"`
 	Verified bool   `json:"verified"`
 }
 
+// VerifiedItemList is a list of entries, each one proven to be included in the same root
+type VerifiedItemList struct {
+	Items []*VerifiedItem `json:"items"`
+}
+
+// VerifiedZItem is an entry of a sorted set along with the item it references, both proven to be included in the
+// same root
+type VerifiedZItem struct {
+	VerifiedItem
+	Score float64 `json:"score"`
+	// EntryIndex is the index of the entry of the sorted set, Index being the one of the item
+	EntryIndex uint64 `json:"entry_index"`
+}
+
+// VerifiedZItemPage is a page of SafeZScan
+type VerifiedZItemPage struct {
+	Items []*VerifiedZItem `json:"items"`
+	PageInfo
+}
+
+// ReferenceChain is how a reference has been resolved: the entry of the reference, the key it points to and the
+// entry of the key it resolved to. Each hop is proven against the trusted root, Verified tells whether both were.
+type ReferenceChain struct {
+	// Reference is the latest entry of the reference, its value being the encoded target
+	Reference *VerifiedItem `json:"reference"`
+	Key       []byte        `json:"key"`
+	// Pinned tells whether the reference is bound to a revision of the key rather than following its latest one
+	Pinned bool `json:"pinned"`
+	// Target is the revision of the key the reference resolved to, holding its value and timestamp
+	Target   *VerifiedItem `json:"target"`
+	Verified bool          `json:"verified"`
+}
+
+// VerifiedValueHash is the outcome of checking a locally computed value hash against the server.
+// Matches is reported by the server, Verified tells whether the checked entry is proven to be included
+// in a root consistent with the trusted one.
+type VerifiedValueHash struct {
+	Key      []byte `json:"key"`
+	Index    uint64 `json:"index"`
+	Matches  bool   `json:"matches"`
+	Verified bool   `json:"verified"`
+}
+
+// VerifiedCount is the number of keys having a prefix as reported by the server, backed by a random sample of keys
+// whose current entries are proven to be included in a root consistent with the trusted one.
+// Sampled is the number of keys checked.
+type VerifiedCount struct {
+	Prefix   []byte `json:"prefix"`
+	Count    uint64 `json:"count"`
+	Sampled  uint64 `json:"sampled"`
+	Verified bool   `json:"verified"`
+}
+
+// VerifiedExistence tells whether a key exists. Existence is proven by the inclusion of its current entry,
+// at Index, in a root consistent with the trusted one: a missing key is never Verified.
+type VerifiedExistence struct {
+	Key      []byte `json:"key"`
+	Exists   bool   `json:"exists"`
+	Index    uint64 `json:"index"`
+	Verified bool   `json:"verified"`
+}
+
+// KeyTimeline is the history of a key, oldest revision first, along with the root cached locally when it was fetched.
+type KeyTimeline struct {
+	Key       []byte           `json:"key"`
+	LocalRoot *schema.Root     `json:"local_root"`
+	Entries   []*TimelineEntry `json:"entries"`
+}
+
+// TimelineEntry is a revision of a key. Covered tells whether the entry is included in the locally cached root,
+// Verified whether its inclusion has been proven, if verification was requested.
+type TimelineEntry struct {
+	Index     uint64 `json:"index"`
+	Time      uint64 `json:"time"`
+	ValueSize int    `json:"value_size"`
+	Deleted   bool   `json:"deleted,omitempty"`
+	Hash      []byte `json:"hash"`
+	Covered   bool   `json:"covered"`
+	Verified  bool   `json:"verified"`
+}
+
 // VerifiedIndex ...
 type VerifiedIndex struct {
 	Index    uint64 `json:"index"`
 	Verified bool   `json:"verified"`
+	// Sequence is the number assigned to the write by a server running in sequencer mode, 0 otherwise
+	Sequence uint64 `json:"sequence"`
 }
 
 // Reset ...
@@ -0,0 +1,73 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codenotary/immudb/pkg/logger"
+)
+
+// ErrVerificationFailed is returned, possibly wrapped, when a proof could not be verified and the verification policy fails closed
+var ErrVerificationFailed = errors.New("proof verification failed")
+
+// VerificationFailure describes a proof returned to a Safe* method that could not be verified against the locally trusted root
+type VerificationFailure struct {
+	Method   string
+	Database string
+	Index    uint64
+}
+
+func (f *VerificationFailure) Error() string {
+	return fmt.Sprintf(
+		"%s: %v for index %d of database %s",
+		f.Method, ErrVerificationFailed, f.Index, f.Database)
+}
+
+// Unwrap allows matching any verification failure against ErrVerificationFailed with errors.Is
+func (f *VerificationFailure) Unwrap() error {
+	return ErrVerificationFailed
+}
+
+// VerificationPolicy decides how the Safe* methods react when a proof cannot be verified.
+// If it returns an error the call fails with it, otherwise the result is returned with Verified set to false.
+type VerificationPolicy func(failure *VerificationFailure, log logger.Logger) error
+
+// StrictVerification fails closed: any call whose proof cannot be verified returns the failure as error
+func StrictVerification(failure *VerificationFailure, log logger.Logger) error {
+	return failure
+}
+
+// WarnVerification logs the failure and lets the call return its unverified result
+func WarnVerification(failure *VerificationFailure, log logger.Logger) error {
+	log.Warningf("%v", failure)
+	return nil
+}
+
+func (c *immuClient) checkVerification(method string, verified bool, index uint64) error {
+	if verified {
+		return nil
+	}
+	policy := c.Options.VerificationPolicy
+	if policy == nil {
+		policy = WarnVerification
+	}
+	return policy(
+		&VerificationFailure{Method: method, Database: c.Options.CurrentDatabase, Index: index},
+		c.Logger)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationPolicy(t *testing.T) {
+	var logs bytes.Buffer
+	c := &immuClient{
+		Options: DefaultOptions(),
+		Logger:  logger.NewSimpleLogger("test", &logs),
+	}
+	c.Options.CurrentDatabase = "defaultdb"
+
+	require.NoError(t, c.checkVerification("SafeGet", true, 1))
+
+	// warnings are the default
+	require.NoError(t, c.checkVerification("SafeGet", false, 1))
+	require.Contains(t, logs.String(), "SafeGet: proof verification failed for index 1 of database defaultdb")
+
+	c.Options.WithVerificationPolicy(nil)
+	require.NoError(t, c.checkVerification("SafeGet", false, 1))
+
+	c.Options.WithVerificationPolicy(StrictVerification)
+	err := c.checkVerification("SafeSet", false, 2)
+	require.True(t, errors.Is(err, ErrVerificationFailed))
+	failure, ok := err.(*VerificationFailure)
+	require.True(t, ok)
+	require.Equal(t, VerificationFailure{Method: "SafeSet", Database: "defaultdb", Index: 2}, *failure)
+	require.NoError(t, c.checkVerification("SafeSet", true, 2))
+
+	errCustom := errors.New("custom")
+	var failures []string
+	c.Options.WithVerificationPolicy(func(failure *VerificationFailure, log logger.Logger) error {
+		failures = append(failures, failure.Method)
+		if failure.Method == "SafeZAdd" {
+			return errCustom
+		}
+		return nil
+	})
+	require.NoError(t, c.checkVerification("SafeReference", false, 3))
+	require.Equal(t, errCustom, c.checkVerification("SafeZAdd", false, 4))
+	require.Equal(t, []string{"SafeReference", "SafeZAdd"}, failures)
+}
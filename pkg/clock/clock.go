@@ -0,0 +1,85 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of wall-clock time used by the server, e.g. to timestamp entries and log records
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// System returns the clock of the operating system
+func System() Clock {
+	return systemClock{}
+}
+
+// Func adapts an external time source to a Clock
+type Func func() time.Time
+
+// Now ...
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// Monotonic wraps a clock so that it never goes backward:
+// if the wrapped clock is stepped back, the last returned time is returned until it catches up again.
+type Monotonic struct {
+	// last is the last returned time as unix nanoseconds, it must be at the top for memory alignment
+	last  int64
+	clock Clock
+}
+
+// NewMonotonic returns a monotonic clock reading from c
+func NewMonotonic(c Clock) *Monotonic {
+	if m, ok := c.(*Monotonic); ok {
+		return m
+	}
+	return &Monotonic{clock: c}
+}
+
+// NewMonotonicFrom is like NewMonotonic, but it never returns a time earlier than last
+func NewMonotonicFrom(c Clock, last time.Time) *Monotonic {
+	m := &Monotonic{clock: c}
+	if !last.IsZero() {
+		m.last = last.UnixNano()
+	}
+	return m
+}
+
+// Now ...
+func (m *Monotonic) Now() time.Time {
+	for {
+		last := atomic.LoadInt64(&m.last)
+		now := m.clock.Now().UnixNano()
+		if now < last {
+			now = last
+		}
+		if atomic.CompareAndSwapInt64(&m.last, last, now) {
+			return time.Unix(0, now)
+		}
+	}
+}
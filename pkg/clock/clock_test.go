@@ -0,0 +1,85 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := System().Now()
+	require.False(t, now.Before(before))
+	require.False(t, now.After(time.Now()))
+}
+
+func TestFunc(t *testing.T) {
+	at := time.Unix(1600000000, 0)
+	c := Func(func() time.Time { return at })
+	require.Equal(t, at, c.Now())
+}
+
+func TestMonotonic(t *testing.T) {
+	at := time.Unix(1600000000, 0)
+	m := NewMonotonic(Func(func() time.Time { return at }))
+	require.Equal(t, at.UnixNano(), m.Now().UnixNano())
+
+	// the wrapped clock is stepped back
+	stepped := at
+	at = at.Add(-time.Hour)
+	require.Equal(t, stepped.UnixNano(), m.Now().UnixNano())
+
+	// and then catches up
+	at = stepped.Add(time.Second)
+	require.Equal(t, at.UnixNano(), m.Now().UnixNano())
+
+	require.Same(t, m, NewMonotonic(m))
+}
+
+func TestMonotonicFrom(t *testing.T) {
+	last := time.Unix(1600000000, 0)
+	m := NewMonotonicFrom(Func(func() time.Time { return last.Add(-time.Minute) }), last)
+	require.Equal(t, last.UnixNano(), m.Now().UnixNano())
+
+	m = NewMonotonicFrom(System(), time.Time{})
+	require.False(t, m.Now().IsZero())
+}
+
+func TestMonotonicConcurrent(t *testing.T) {
+	m := NewMonotonic(System())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prev := m.Now()
+			for j := 0; j < 1000; j++ {
+				now := m.Now()
+				if now.Before(prev) {
+					t.Errorf("time went backward: %v < %v", now, prev)
+					return
+				}
+				prev = now
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidNTPResponse is returned when the NTP server replies with a malformed or kiss-of-death packet
+var ErrInvalidNTPResponse = errors.New("invalid NTP response")
+
+// seconds between the NTP epoch (1900) and the unix one (1970)
+const ntpEpochOffset = 2208988800
+
+// NTP is a clock disciplined by an NTP server: the offset between the local clock and the server one
+// is measured at start and then every interval, and applied to the local clock.
+// If a later measurement fails, the last known offset is kept.
+type NTP struct {
+	// offset is the last measured offset in nanoseconds, it must be at the top for memory alignment
+	offset int64
+
+	server   string
+	timeout  time.Duration
+	query    func(server string, timeout time.Duration) (time.Duration, error)
+	lastErr  atomic.Value
+	quit     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewNTP returns a clock disciplined by the NTP server at address (host:port).
+// It fails if the first offset measurement fails.
+func NewNTP(address string, interval time.Duration, timeout time.Duration) (*NTP, error) {
+	return newNTP(address, interval, timeout, queryNTPOffset)
+}
+
+func newNTP(
+	address string,
+	interval time.Duration,
+	timeout time.Duration,
+	query func(server string, timeout time.Duration) (time.Duration, error),
+) (*NTP, error) {
+	c := &NTP{
+		server:  address,
+		timeout: timeout,
+		query:   query,
+		quit:    make(chan struct{}),
+	}
+	if err := c.sync(); err != nil {
+		return nil, err
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.quit:
+				return
+			case <-ticker.C:
+				c.sync()
+			}
+		}
+	}()
+	return c, nil
+}
+
+func (c *NTP) sync() error {
+	offset, err := c.query(c.server, c.timeout)
+	if err != nil {
+		c.lastErr.Store(err)
+		return err
+	}
+	atomic.StoreInt64(&c.offset, int64(offset))
+	return nil
+}
+
+// Now ...
+func (c *NTP) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns the last measured offset between the NTP server clock and the local one
+func (c *NTP) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.offset))
+}
+
+// LastError returns the error of the last failed offset measurement, if any
+func (c *NTP) LastError() error {
+	if err, ok := c.lastErr.Load().(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Stop stops the periodic offset measurement
+func (c *NTP) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.quit)
+		c.wg.Wait()
+	})
+}
+
+// queryNTPOffset performs an SNTP (RFC 4330) request and returns the offset of the server clock from the local one
+func queryNTPOffset(server string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	req := make([]byte, 48)
+	// leap indicator 0, version 4, mode 3 (client)
+	req[0] = 0<<6 | 4<<3 | 3
+
+	t1 := time.Now()
+	if _, err = conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+	t4 := time.Now()
+
+	// the reply must come from a server (mode 4) and not be a kiss-of-death packet (stratum 0)
+	if n < 48 || resp[0]&0x7 != 4 || resp[1] == 0 {
+		return 0, ErrInvalidNTPResponse
+	}
+
+	t2 := ntpTime(resp[32:40])
+	t3 := ntpTime(resp[40:48])
+	return (t2.Sub(t1) + t3.Sub(t4)) / 2, nil
+}
+
+func ntpTime(b []byte) time.Time {
+	secs := int64(binary.BigEndian.Uint32(b[:4])) - ntpEpochOffset
+	nsecs := (int64(binary.BigEndian.Uint32(b[4:8])) * 1e9) >> 32
+	return time.Unix(secs, nsecs)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clock
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNTPServer answers SNTP requests with the local time shifted by offset
+func fakeNTPServer(t *testing.T, offset time.Duration, stratum byte) (string, func()) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			resp := make([]byte, 48)
+			resp[0] = 0<<6 | 4<<3 | 4
+			resp[1] = stratum
+			now := time.Now().Add(offset)
+			putNTPTime(resp[32:40], now)
+			putNTPTime(resp[40:48], now)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String(), func() { conn.Close() }
+}
+
+func putNTPTime(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[:4], uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:8], uint32((int64(t.Nanosecond())<<32)/1e9))
+}
+
+func TestQueryNTPOffset(t *testing.T) {
+	addr, stop := fakeNTPServer(t, time.Hour, 2)
+	defer stop()
+	offset, err := queryNTPOffset(addr, time.Second)
+	require.NoError(t, err)
+	require.InDelta(t, float64(time.Hour), float64(offset), float64(time.Second))
+}
+
+func TestQueryNTPOffsetKissOfDeath(t *testing.T) {
+	addr, stop := fakeNTPServer(t, 0, 0)
+	defer stop()
+	_, err := queryNTPOffset(addr, time.Second)
+	require.Equal(t, ErrInvalidNTPResponse, err)
+}
+
+func TestNewNTP(t *testing.T) {
+	addr, stop := fakeNTPServer(t, -time.Hour, 1)
+	defer stop()
+	c, err := NewNTP(addr, time.Hour, time.Second)
+	require.NoError(t, err)
+	defer c.Stop()
+
+	require.NoError(t, c.LastError())
+	require.InDelta(t, float64(-time.Hour), float64(c.Offset()), float64(time.Second))
+	require.WithinDuration(t, time.Now().Add(-time.Hour), c.Now(), time.Second)
+}
+
+func TestNTPKeepsLastOffset(t *testing.T) {
+	errUnreachable := errors.New("unreachable")
+	var calls int32
+	query := func(string, time.Duration) (time.Duration, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return time.Minute, nil
+		}
+		return 0, errUnreachable
+	}
+	c, err := newNTP("ntp", time.Millisecond, time.Second, query)
+	require.NoError(t, err)
+
+	for atomic.LoadInt32(&calls) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Stop()
+	c.Stop()
+
+	require.Equal(t, time.Minute, c.Offset())
+	require.Equal(t, errUnreachable, c.LastError())
+}
+
+func TestNewNTPFails(t *testing.T) {
+	errUnreachable := errors.New("unreachable")
+	_, err := newNTP("ntp", time.Hour, time.Second, func(string, time.Duration) (time.Duration, error) {
+		return 0, errUnreachable
+	})
+	require.Equal(t, errUnreachable, err)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compression registers the gRPC compressors supported by immudb. Importing it enables the server
+// to accept and answer compressed calls, and the clients to compress their calls.
+package compression
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+const (
+	// None disables compression
+	None = ""
+	// Gzip is the name of the gzip compressor
+	Gzip = gzip.Name
+	// Zstd is the name of the zstd compressor, available only in binaries built with cgo
+	Zstd = "zstd"
+)
+
+// Supported tells if the compressor with the given name is available, None is always supported
+func Supported(name string) bool {
+	return name == None || encoding.GetCompressor(name) != nil
+}
+
+// Validate returns an error if the compressor with the given name is not available
+func Validate(name string) error {
+	if !Supported(name) {
+		return fmt.Errorf("unsupported compression %s, allowed values are %s and %s (only if built with cgo)", name, Gzip, Zstd)
+	}
+	return nil
+}
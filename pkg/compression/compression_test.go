@@ -0,0 +1,98 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestCompressors(t *testing.T) {
+	require.True(t, Supported(None))
+	require.NoError(t, Validate(Gzip))
+	require.Error(t, Validate("lz4"))
+
+	msg := bytes.Repeat([]byte(`{"key":"value"}`), 1000)
+	for _, name := range []string{Gzip, Zstd} {
+		if !Supported(name) {
+			t.Logf("%s compressor not available", name)
+			continue
+		}
+		compressor := encoding.GetCompressor(name)
+		var compressed bytes.Buffer
+		w, err := compressor.Compress(&compressed)
+		require.NoError(t, err)
+		_, err = w.Write(msg)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.Less(t, compressed.Len(), len(msg)/10, name)
+
+		r, err := compressor.Decompress(&compressed)
+		require.NoError(t, err)
+		decompressed, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, msg, decompressed, name)
+	}
+}
+
+func TestZstdForgedContentSize(t *testing.T) {
+	if !Supported(Zstd) {
+		t.Skip("zstd compressor not available")
+	}
+
+	// a frame declaring 1TB of content, with a 1KB window and a single raw block of 3 bytes
+	frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0xc0, 0x00}
+	frame = append(frame, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(frame[6:], 1<<40)
+	frame = append(frame, 0x19, 0x00, 0x00, 'a', 'b', 'c')
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	r, err := encoding.GetCompressor(Zstd).Decompress(bytes.NewReader(frame))
+	require.NoError(t, err)
+	// gRPC reads at most one byte more than the maximum message size
+	decompressed, _ := ioutil.ReadAll(io.LimitReader(r, 4<<20+1))
+	runtime.ReadMemStats(&after)
+	require.LessOrEqual(t, len(decompressed), 3)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+}
+
+func TestZstdReadLimit(t *testing.T) {
+	if !Supported(Zstd) {
+		t.Skip("zstd compressor not available")
+	}
+
+	var compressed bytes.Buffer
+	w, err := encoding.GetCompressor(Zstd).Compress(&compressed)
+	require.NoError(t, err)
+	_, err = w.Write(make([]byte, 16<<20))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := encoding.GetCompressor(Zstd).Decompress(&compressed)
+	require.NoError(t, err)
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, 1<<20+1))
+	require.NoError(t, err)
+	require.Len(t, decompressed, 1<<20+1)
+}
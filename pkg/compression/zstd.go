@@ -0,0 +1,98 @@
+// +build cgo
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+
+	"github.com/DataDog/zstd"
+	"google.golang.org/grpc/encoding"
+)
+
+// zstdLevel is the default zstd compression level, a good compromise between speed and ratio
+const zstdLevel = 3
+
+func init() {
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// zstdCompressor compresses every message as a whole, messages are bounded by the maximum gRPC message size anyway.
+// Messages are decompressed as they are read instead, so that gRPC can stop reading them at the maximum message size:
+// the size declared by the frame is sent by the peer and can't be trusted to size the decompression buffer.
+type zstdCompressor struct{}
+
+func (*zstdCompressor) Name() string {
+	return Zstd
+}
+
+func (*zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdWriter{w: w}, nil
+}
+
+func (*zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	z := &zstdReader{r: zstd.NewReader(r)}
+	// gRPC doesn't read past the maximum message size, nor closes the reader
+	runtime.SetFinalizer(z, (*zstdReader).close)
+	return z, nil
+}
+
+type zstdWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (z *zstdWriter) Write(p []byte) (int, error) {
+	return z.buf.Write(p)
+}
+
+func (z *zstdWriter) Close() error {
+	dst, err := zstd.CompressLevel(nil, z.buf.Bytes(), zstdLevel)
+	if err != nil {
+		return err
+	}
+	_, err = z.w.Write(dst)
+	return err
+}
+
+// zstdReader releases the decompression context as soon as the message has been read, or fails to be
+type zstdReader struct {
+	r   io.ReadCloser
+	err error
+}
+
+func (z *zstdReader) Read(p []byte) (int, error) {
+	if z.r == nil {
+		return 0, z.err
+	}
+	n, err := z.r.Read(p)
+	if err != nil {
+		z.close()
+		z.err = err
+	}
+	return n, err
+}
+
+func (z *zstdReader) close() {
+	if z.r != nil {
+		z.r.Close()
+		z.r = nil
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package connector streams the entries of an immudb database to external systems as they are committed, along
+// with the proofs of their inclusion in the Merkle tree, so that downstream consumers get a verifiable feed.
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+)
+
+// ErrNotVerified is returned when an entry can't be proven against the roots verified before
+var ErrNotVerified = errors.New("an entry could not be verified against the previously verified root")
+
+// Event is an entry of the database and the proof of its inclusion in the root at RootIndex. Hash is the leaf of
+// the entry, which consumers can recompute from the key, the value and the index of the entry, see schema.Item.Hash,
+// unless the value has been pruned by the retention policy: a pruned event has no value and can't be recomputed.
+type Event struct {
+	Database      string   `json:"database"`
+	Index         uint64   `json:"index"`
+	Key           []byte   `json:"key"`
+	Value         []byte   `json:"value"`
+	Deleted       bool     `json:"deleted,omitempty"`
+	Pruned        bool     `json:"pruned,omitempty"`
+	Hash          []byte   `json:"hash"`
+	Root          []byte   `json:"root"`
+	RootIndex     uint64   `json:"root_index"`
+	InclusionPath [][]byte `json:"inclusion_path"`
+}
+
+// Marshal returns the JSON encoding of the event
+func (e *Event) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// Sink publishes the events to an external system
+type Sink interface {
+	// Publish returns once all the events have been durably published, in order
+	Publish(ctx context.Context, events []*Event) error
+	Close() error
+}
+
+// State is the position of the connector in the database: the index of the next entry to publish and the last root
+// the published entries have been verified against
+type State struct {
+	Next      uint64 `json:"next"`
+	RootIndex uint64 `json:"root_index"`
+	Root      []byte `json:"root,omitempty"`
+}
+
+// Options of the connector
+type Options struct {
+	// Database labels the events
+	Database string
+	// From is the index of the first entry published, unless a state has been saved in StateFile
+	From uint64
+	// BatchSize is the maximum number of events published at once
+	BatchSize int
+	// PollInterval is the longest time an event waits for its batch to fill up before being published
+	PollInterval time.Duration
+	// StateFile saves the state after every publication, so that a restarted connector resumes where it stopped
+	// and keeps verifying the entries against the same roots. No state is saved if empty.
+	StateFile string
+}
+
+// DefaultOptions returns the options of a connector publishing every entry of database, in batches of 100
+func DefaultOptions(database string) Options {
+	return Options{
+		Database:     database,
+		BatchSize:    100,
+		PollInterval: time.Second,
+	}
+}
+
+// Connector publishes the entries of a database to a sink, in index order, at least once. Every entry is verified
+// against the last verified root, the first root being trusted on first use.
+type Connector struct {
+	client schema.ImmuServiceClient
+	sink   Sink
+	opts   Options
+	mu     sync.Mutex
+	state  State
+	Logger logger.Logger
+}
+
+// New returns the connector publishing the entries read by c to sink, resuming from the state saved in
+// opts.StateFile, if any
+func New(c schema.ImmuServiceClient, sink Sink, opts Options, l logger.Logger) (*Connector, error) {
+	if opts.BatchSize <= 0 || opts.PollInterval <= 0 {
+		return nil, fmt.Errorf("invalid connector options: the batch size and the poll interval must be positive")
+	}
+	cn := &Connector{client: c, sink: sink, opts: opts, state: State{Next: opts.From}, Logger: l}
+	if opts.StateFile != "" {
+		data, err := ioutil.ReadFile(opts.StateFile)
+		if err == nil {
+			err = json.Unmarshal(data, &cn.state)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("invalid connector state in %s: %v", opts.StateFile, err)
+		}
+	}
+	return cn, nil
+}
+
+// State returns the position of the connector
+func (c *Connector) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Run publishes the entries as they are committed, until ctx is done or publishing fails. The entries are read from
+// a single Tail stream: a batch is published once it holds BatchSize events, or after PollInterval otherwise.
+func (c *Connector) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.client.Tail(ctx, &schema.TailRequest{
+		From:      c.state.Next,
+		RootIndex: &schema.Index{Index: c.state.RootIndex},
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	items := make(chan *schema.TailItem)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			item, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(c.opts.PollInterval)
+	defer ticker.Stop()
+	state := c.state
+	var events []*Event
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err = <-recvErr:
+			if ctx.Err() != nil || err == io.EOF {
+				return nil
+			}
+			return err
+		case item := <-items:
+			e, err := c.verify(&state, item)
+			if err != nil {
+				return err
+			}
+			if events = append(events, e); len(events) < c.opts.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(events) == 0 {
+				continue
+			}
+		}
+		if err = c.publish(ctx, state, events); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		events = nil
+	}
+}
+
+// verify checks that item is the entry following the ones already verified, skipping the indexes of failed writes,
+// and that it is included in a root consistent with the last verified one, which state then moves to.
+// The value of a pruned entry can't be verified, only its leaf.
+func (c *Connector) verify(state *State, item *schema.TailItem) (*Event, error) {
+	entry, proof := item.GetItem(), item.GetProof()
+	leaf := entry.Hash()
+	if item.GetPruned() {
+		leaf = proof.GetLeaf()
+	}
+	trusted := schema.NewRoot()
+	trusted.SetIndex(state.RootIndex)
+	trusted.SetRoot(state.Root)
+	if entry.GetIndex() < state.Next || proof.GetIndex() != entry.GetIndex() || !proof.Verify(leaf, *trusted) {
+		return nil, fmt.Errorf("%w: index %d", ErrNotVerified, state.Next)
+	}
+	state.Next, state.RootIndex, state.Root = entry.GetIndex()+1, proof.At, proof.Root
+
+	return &Event{
+		Database:      c.opts.Database,
+		Index:         entry.GetIndex(),
+		Key:           entry.GetKey(),
+		Value:         entry.GetValue(),
+		Deleted:       entry.GetDeleted(),
+		Pruned:        item.GetPruned(),
+		Hash:          leaf,
+		Root:          proof.Root,
+		RootIndex:     proof.At,
+		InclusionPath: proof.InclusionPath,
+	}, nil
+}
+
+// publish publishes events to the sink, then saves state, the position following the last of them
+func (c *Connector) publish(ctx context.Context, state State, events []*Event) error {
+	if err := c.sink.Publish(ctx, events); err != nil {
+		return err
+	}
+	if err := c.saveState(state); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.state = state
+	c.mu.Unlock()
+	return nil
+}
+
+// saveState writes the state to a temporary file first, so that a crash never leaves a partial state behind
+func (c *Connector) saveState(state State) error {
+	if c.opts.StateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp := c.opts.StateFile + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.opts.StateFile)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/client/clienttest"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/server"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/stretchr/testify/require"
+)
+
+type memorySink struct {
+	sync.Mutex
+	events  []*Event
+	batches []int
+	err     error
+}
+
+func (s *memorySink) Publish(ctx context.Context, events []*Event) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, events...)
+	s.batches = append(s.batches, len(events))
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) published() []*Event {
+	s.Lock()
+	defer s.Unlock()
+	return append([]*Event(nil), s.events...)
+}
+
+func (s *memorySink) fail(err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.err = err
+}
+
+// run runs cn until the returned function is called, which returns the error Run returned
+func run(ctx context.Context, cn *Connector) func() error {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- cn.Run(ctx) }()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func TestConnector(t *testing.T) {
+	dir, err := ioutil.TempDir("", "connector")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cli, ctx, closer, err := clienttest.NewBufconnClient(server.DefaultOptions().WithInMemoryStore(true))
+	require.NoError(t, err)
+	defer closer()
+
+	sink := &memorySink{}
+	opts := DefaultOptions("defaultdb")
+	opts.BatchSize = 2
+	opts.PollInterval = 10 * time.Millisecond
+	opts.StateFile = filepath.Join(dir, "state.json")
+	cn, err := New(*cli.GetServiceClient(), sink, opts, logger.NewSimpleLogger("connector", os.Stderr))
+	require.NoError(t, err)
+
+	stop := run(ctx, cn)
+	for _, k := range []string{"k0", "k1", "k2"} {
+		_, err = cli.Set(ctx, []byte(k), []byte("v"))
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool { return cn.State().Next == 3 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, stop())
+
+	events := sink.published()
+	require.Len(t, events, 3)
+	for _, n := range sink.batches {
+		require.LessOrEqual(t, n, 2)
+	}
+	root, err := cli.CurrentRoot(ctx)
+	require.NoError(t, err)
+	for i, e := range events {
+		require.Equal(t, "defaultdb", e.Database)
+		require.Equal(t, uint64(i), e.Index)
+		require.Equal(t, []byte("k"+string(rune('0'+i))), e.Key)
+		require.False(t, e.Pruned)
+		item := schema.Item{Key: e.Key, Value: e.Value, Index: e.Index}
+		require.Equal(t, item.Hash(), e.Hash)
+	}
+	require.Equal(t, root.GetRoot(), events[2].Root)
+	require.Equal(t, State{Next: 3, RootIndex: 2, Root: root.GetRoot()}, cn.State())
+
+	// a failed publication stops the connector, which resumes from the same entry
+	_, err = cli.Set(ctx, []byte("k3"), []byte("v"))
+	require.NoError(t, err)
+	sink.fail(errors.New("unavailable"))
+	require.Equal(t, sink.err, cn.Run(ctx))
+	require.Equal(t, uint64(3), cn.State().Next)
+
+	// a restarted connector resumes from the saved state
+	sink.fail(nil)
+	cn, err = New(*cli.GetServiceClient(), sink, opts, logger.NewSimpleLogger("connector", os.Stderr))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), cn.State().Next)
+	stop = run(ctx, cn)
+	require.Eventually(t, func() bool { return cn.State().Next == 4 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, stop())
+	events = sink.published()
+	require.Len(t, events, 4)
+	require.Equal(t, []byte("k3"), events[3].Key)
+
+	_, err = New(*cli.GetServiceClient(), sink, Options{}, nil)
+	require.Error(t, err)
+}
+
+func TestConnectorPruned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "connector")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	st, err := store.Open(store.DefaultOptions(dir, logger.NewSimpleLogger("connector", os.Stderr)))
+	require.NoError(t, err)
+	defer st.Close()
+
+	for _, v := range []string{"v0", "v1"} {
+		_, err = st.Set(schema.KeyValue{Key: []byte("k"), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	_, err = st.EnforceRetention(store.RetentionPolicy{MaxRevisions: 1}, 0)
+	require.NoError(t, err)
+
+	// the items are streamed as the Tail RPC does
+	cn, err := New(nil, &memorySink{}, DefaultOptions("defaultdb"), nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	state := cn.State()
+	var events []*Event
+	require.NoError(t, st.Tail(ctx, 0, func(e *store.TailEntry) error {
+		proof, err := st.ProofAt(e.Item.Index, &schema.Index{Index: state.RootIndex})
+		if err != nil {
+			return err
+		}
+		event, err := cn.verify(&state, &schema.TailItem{Item: e.Item, Proof: proof, Pruned: e.Type == store.EntryPruned})
+		if err != nil {
+			return err
+		}
+		if events = append(events, event); len(events) == 2 {
+			cancel()
+		}
+		return nil
+	}))
+
+	require.True(t, events[0].Pruned)
+	require.Equal(t, []byte("k"), events[0].Key)
+	require.Nil(t, events[0].Value)
+	require.NotEmpty(t, events[0].Hash)
+	require.False(t, events[1].Pruned)
+	require.Equal(t, []byte("v1"), events[1].Value)
+	require.Equal(t, uint64(2), state.Next)
+
+	// a value can't pass for the pruned one
+	proof, err := st.ProofAt(0, nil)
+	require.NoError(t, err)
+	state = State{}
+	_, err = cn.verify(&state, &schema.TailItem{Item: &schema.Item{Key: []byte("k"), Value: []byte("forged")}, Proof: proof})
+	require.True(t, errors.Is(err, ErrNotVerified))
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// the subset of the Kafka protocol spoken by KafkaSink: version 3 of the Produce API, the oldest one served by every
+// broker since Kafka 4, carrying version 2 record batches
+const (
+	kafkaProduceAPIKey     = 0
+	kafkaProduceAPIVersion = 3
+	kafkaRecordBatchMagic  = 2
+	kafkaMaxResponseSize   = 1 << 20
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrKafkaProduce is returned when the broker refuses the records
+var ErrKafkaProduce = errors.New("the kafka broker refused the records")
+
+// KafkaOptions tells where KafkaSink publishes the events
+type KafkaOptions struct {
+	// Broker is the address of the broker leading Partition of Topic
+	Broker    string
+	Topic     string
+	Partition int32
+	// ClientID identifies the connector in the logs and quotas of the broker
+	ClientID string
+	// Acks is the number of replicas acknowledging the records before the broker responds, -1 meaning all the
+	// in-sync ones
+	Acks int16
+	// Timeout bounds the connection to the broker and every request
+	Timeout time.Duration
+}
+
+// DefaultKafkaOptions returns the options publishing to the partition 0 of topic on a local broker, acknowledged
+// by all the in-sync replicas
+func DefaultKafkaOptions(topic string) KafkaOptions {
+	return KafkaOptions{
+		Broker:   "127.0.0.1:9092",
+		Topic:    topic,
+		ClientID: "immucdc",
+		Acks:     -1,
+		Timeout:  10 * time.Second,
+	}
+}
+
+// KafkaSink publishes the events to a partition of a Kafka topic, one record per event, keyed by the key of the
+// entry and holding the JSON encoding of the event. Records are produced with no idempotence: an event may be
+// published more than once if the connector restarts, consumers can deduplicate them by index.
+type KafkaSink struct {
+	opts          KafkaOptions
+	mu            sync.Mutex
+	conn          net.Conn
+	correlationID int32
+}
+
+// NewKafkaSink returns the sink publishing to the broker in opts, which is dialed on the first publication
+func NewKafkaSink(opts KafkaOptions) *KafkaSink {
+	return &KafkaSink{opts: opts}
+}
+
+// Publish produces the events in a single record batch, returning once the broker acknowledged them
+func (k *KafkaSink) Publish(ctx context.Context, events []*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	records := make([]kafkaRecord, 0, len(events))
+	for _, e := range events {
+		value, err := e.Marshal()
+		if err != nil {
+			return err
+		}
+		records = append(records, kafkaRecord{key: e.Key, value: value})
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.conn == nil {
+		d := net.Dialer{Timeout: k.opts.Timeout}
+		conn, err := d.DialContext(ctx, "tcp", k.opts.Broker)
+		if err != nil {
+			return err
+		}
+		k.conn = conn
+	}
+	k.correlationID++
+	err := k.produce(k.encodeProduceRequest(k.correlationID, records, time.Now()))
+	if err != nil && !errors.Is(err, ErrKafkaProduce) {
+		// the connection is in an unknown state, the next publication dials again
+		k.conn.Close()
+		k.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection to the broker
+func (k *KafkaSink) Close() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.conn == nil {
+		return nil
+	}
+	err := k.conn.Close()
+	k.conn = nil
+	return err
+}
+
+func (k *KafkaSink) produce(request []byte) error {
+	if err := k.conn.SetDeadline(time.Now().Add(k.opts.Timeout)); err != nil {
+		return err
+	}
+	if _, err := k.conn.Write(request); err != nil {
+		return err
+	}
+	if k.opts.Acks == 0 {
+		// the broker doesn't respond when no acknowledgment is required
+		return nil
+	}
+	var size int32
+	if err := binary.Read(k.conn, binary.BigEndian, &size); err != nil {
+		return err
+	}
+	if size < 4 || size > kafkaMaxResponseSize {
+		return fmt.Errorf("invalid kafka response size %d", size)
+	}
+	response := make([]byte, size)
+	if _, err := io.ReadFull(k.conn, response); err != nil {
+		return err
+	}
+	return k.decodeProduceResponse(response)
+}
+
+type kafkaRecord struct {
+	key   []byte
+	value []byte
+}
+
+// kafkaEncoder appends the primitive types of the Kafka protocol to a buffer
+type kafkaEncoder []byte
+
+func (e *kafkaEncoder) int8(v int8) {
+	*e = append(*e, byte(v))
+}
+
+func (e *kafkaEncoder) int16(v int16) {
+	*e = append(*e, byte(v>>8), byte(v))
+}
+
+func (e *kafkaEncoder) int32(v int32) {
+	*e = append(*e, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32((*e)[len(*e)-4:], uint32(v))
+}
+
+func (e *kafkaEncoder) int64(v int64) {
+	*e = append(*e, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64((*e)[len(*e)-8:], uint64(v))
+}
+
+func (e *kafkaEncoder) varint(v int64) {
+	var b [binary.MaxVarintLen64]byte
+	*e = append(*e, b[:binary.PutVarint(b[:], v)]...)
+}
+
+func (e *kafkaEncoder) string(s string) {
+	e.int16(int16(len(s)))
+	*e = append(*e, s...)
+}
+
+func (e *kafkaEncoder) varbytes(b []byte) {
+	if b == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(b)))
+	*e = append(*e, b...)
+}
+
+// encodeProduceRequest returns the size delimited produce request of the records
+func (k *KafkaSink) encodeProduceRequest(correlationID int32, records []kafkaRecord, now time.Time) []byte {
+	batch := encodeRecordBatch(records, now)
+
+	e := kafkaEncoder{0, 0, 0, 0} // size
+	e.int16(kafkaProduceAPIKey)
+	e.int16(kafkaProduceAPIVersion)
+	e.int32(correlationID)
+	e.string(k.opts.ClientID)
+	e.int16(-1) // no transactional id
+	e.int16(k.opts.Acks)
+	e.int32(int32(k.opts.Timeout / time.Millisecond))
+	e.int32(1) // topics
+	e.string(k.opts.Topic)
+	e.int32(1) // partitions
+	e.int32(k.opts.Partition)
+	e.int32(int32(len(batch)))
+	e = append(e, batch...)
+	binary.BigEndian.PutUint32(e, uint32(len(e)-4))
+	return e
+}
+
+// encodeRecordBatch returns the version 2 record batch of the records, with no compression
+func encodeRecordBatch(records []kafkaRecord, now time.Time) []byte {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+
+	e := kafkaEncoder{}
+	e.int64(0)  // base offset, assigned by the broker
+	e.int32(0)  // batch length, set below
+	e.int32(-1) // partition leader epoch
+	e.int8(kafkaRecordBatchMagic)
+	e.int32(0) // crc, set below
+	crcStart := len(e)
+	e.int16(0) // attributes: no compression, create time
+	e.int32(int32(len(records) - 1))
+	e.int64(timestamp)
+	e.int64(timestamp)
+	e.int64(-1) // producer id
+	e.int16(-1) // producer epoch
+	e.int32(-1) // base sequence
+	e.int32(int32(len(records)))
+	for i, r := range records {
+		body := kafkaEncoder{}
+		body.int8(0)   // attributes
+		body.varint(0) // timestamp delta
+		body.varint(int64(i))
+		body.varbytes(r.key)
+		body.varbytes(r.value)
+		body.varint(0) // headers
+		e.varint(int64(len(body)))
+		e = append(e, body...)
+	}
+	binary.BigEndian.PutUint32(e[8:], uint32(len(e)-12))
+	binary.BigEndian.PutUint32(e[crcStart-4:], crc32.Checksum(e[crcStart:], castagnoliTable))
+	return e
+}
+
+// kafkaDecoder reads the primitive types of the Kafka protocol, the first error sticks
+type kafkaDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.b) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) string() string {
+	return string(d.next(int(d.int16())))
+}
+
+func (k *KafkaSink) decodeProduceResponse(response []byte) error {
+	d := &kafkaDecoder{b: response}
+	if id := d.int32(); d.err == nil && id != k.correlationID {
+		return fmt.Errorf("unexpected kafka correlation id %d, expected %d", id, k.correlationID)
+	}
+	for topics := d.int32(); topics > 0 && d.err == nil; topics-- {
+		topic := d.string()
+		for partitions := d.int32(); partitions > 0 && d.err == nil; partitions-- {
+			partition := d.int32()
+			code := d.int16()
+			d.int64() // base offset
+			d.int64() // log append time
+			if d.err == nil && code != 0 {
+				return fmt.Errorf("%w: error code %d on partition %d of topic %s", ErrKafkaProduce, code, partition, topic)
+			}
+		}
+	}
+	return d.err
+}
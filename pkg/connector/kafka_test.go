@@ -0,0 +1,197 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker accepts produce requests, decoding their records, and responds with errorCode
+type fakeBroker struct {
+	listener  net.Listener
+	errorCode int32
+	requests  chan fakeProduce
+}
+
+type fakeProduce struct {
+	clientID  string
+	acks      int16
+	topic     string
+	partition int32
+	records   []kafkaRecord
+	err       error
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	b := &fakeBroker{listener: l, requests: make(chan fakeProduce, 10)}
+	go b.serve()
+	return b
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			for {
+				var size int32
+				if binary.Read(conn, binary.BigEndian, &size) != nil {
+					return
+				}
+				request := make([]byte, size)
+				if _, err := io.ReadFull(conn, request); err != nil {
+					return
+				}
+				correlationID, p := decodeProduce(request)
+				b.requests <- p
+
+				var e kafkaEncoder
+				e.int32(0)
+				e.int32(correlationID)
+				e.int32(1)
+				e.string(p.topic)
+				e.int32(1)
+				e.int32(p.partition)
+				e.int16(int16(atomic.LoadInt32(&b.errorCode)))
+				e.int64(0)
+				e.int64(-1)
+				e.int32(0) // throttle time
+				binary.BigEndian.PutUint32(e, uint32(len(e)-4))
+				if _, err := conn.Write(e); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+func decodeProduce(request []byte) (int32, fakeProduce) {
+	var p fakeProduce
+	d := &kafkaDecoder{b: request}
+	if d.int16() != kafkaProduceAPIKey || d.int16() != kafkaProduceAPIVersion {
+		p.err = errors.New("unexpected api")
+	}
+	correlationID := d.int32()
+	p.clientID = d.string()
+	d.int16() // transactional id
+	p.acks = d.int16()
+	d.int32() // timeout
+	d.int32() // topics
+	p.topic = d.string()
+	d.int32() // partitions
+	p.partition = d.int32()
+	batch := d.next(int(d.int32()))
+	if d.err != nil {
+		p.err = d.err
+		return correlationID, p
+	}
+
+	bd := &kafkaDecoder{b: batch}
+	bd.int64() // base offset
+	if int(bd.int32()) != len(batch)-12 {
+		p.err = errors.New("invalid batch length")
+	}
+	bd.int32() // partition leader epoch
+	if magic := bd.next(1); len(magic) == 0 || magic[0] != kafkaRecordBatchMagic {
+		p.err = errors.New("invalid magic")
+	}
+	if uint32(bd.int32()) != crc32.Checksum(bd.b, castagnoliTable) {
+		p.err = errors.New("invalid crc")
+	}
+	bd.next(2 + 4 + 8 + 8 + 8 + 2 + 4)
+	count := bd.int32()
+	rest := bd.b
+	varint := func() int64 {
+		v, n := binary.Varint(rest)
+		rest = rest[n:]
+		return v
+	}
+	for i := int32(0); i < count; i++ {
+		varint()        // length
+		rest = rest[1:] // attributes
+		varint()        // timestamp delta
+		if varint() != int64(i) {
+			p.err = errors.New("invalid offset delta")
+		}
+		key := rest[:varint()]
+		rest = rest[len(key):]
+		value := rest[:varint()]
+		rest = rest[len(value):]
+		varint() // headers
+		p.records = append(p.records, kafkaRecord{key: key, value: value})
+	}
+	return correlationID, p
+}
+
+func TestKafkaSink(t *testing.T) {
+	b := newFakeBroker(t)
+	defer b.listener.Close()
+
+	opts := DefaultKafkaOptions("immudb")
+	opts.Broker = b.listener.Addr().String()
+	opts.Partition = 2
+	opts.Timeout = 5 * time.Second
+	sink := NewKafkaSink(opts)
+	defer sink.Close()
+
+	events := []*Event{
+		{Database: "defaultdb", Index: 0, Key: []byte("k0"), Value: []byte("v0")},
+		{Database: "defaultdb", Index: 1, Key: []byte("k1"), Value: []byte("v1")},
+	}
+	require.NoError(t, sink.Publish(context.Background(), events))
+	require.NoError(t, sink.Publish(context.Background(), nil))
+
+	p := <-b.requests
+	require.NoError(t, p.err)
+	require.Equal(t, "immucdc", p.clientID)
+	require.Equal(t, int16(-1), p.acks)
+	require.Equal(t, "immudb", p.topic)
+	require.Equal(t, int32(2), p.partition)
+	require.Len(t, p.records, 2)
+	for i, r := range p.records {
+		value, err := events[i].Marshal()
+		require.NoError(t, err)
+		require.Equal(t, events[i].Key, r.key)
+		require.Equal(t, value, r.value)
+	}
+
+	// the broker errors are reported, keeping the connection
+	atomic.StoreInt32(&b.errorCode, 3)
+	err := sink.Publish(context.Background(), events[:1])
+	require.True(t, errors.Is(err, ErrKafkaProduce))
+	require.NotNil(t, sink.conn)
+	<-b.requests
+
+	b.listener.Close()
+	sink.Close()
+	require.Error(t, sink.Publish(context.Background(), events[:1]))
+}
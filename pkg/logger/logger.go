@@ -17,6 +17,7 @@ limitations under the License.
 package logger
 
 import (
+	"fmt"
 	"os"
 	"strings"
 )
@@ -41,17 +42,36 @@ type Logger interface {
 	CloneWithLevel(level LogLevel) Logger
 }
 
-func logLevelFromEnvironment() LogLevel {
-	logLevel, _ := os.LookupEnv("LOG_LEVEL")
-	switch strings.ToLower(logLevel) {
+// ParseLogLevel converts a level name (debug, info, warn or error) to a LogLevel
+func ParseLogLevel(level string) (LogLevel, error) {
+	switch strings.ToLower(level) {
 	case "error":
-		return LogError
+		return LogError, nil
 	case "warn":
-		return LogWarn
+		return LogWarn, nil
 	case "info":
-		return LogInfo
+		return LogInfo, nil
 	case "debug":
-		return LogDebug
+		return LogDebug, nil
+	}
+	return LogInfo, fmt.Errorf("unknown log level %q", level)
+}
+
+// String returns the level name
+func (l LogLevel) String() string {
+	switch l {
+	case LogError:
+		return "error"
+	case LogWarn:
+		return "warn"
+	case LogInfo:
+		return "info"
 	}
-	return LogInfo
+	return "debug"
+}
+
+func logLevelFromEnvironment() LogLevel {
+	logLevel, _ := os.LookupEnv("LOG_LEVEL")
+	level, _ := ParseLogLevel(logLevel)
+	return level
 }
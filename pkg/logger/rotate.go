@@ -0,0 +1,128 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// RotatingFile is a log file rotated once it reaches a maximum size: the file is renamed to file.1, the previous
+// file.1 to file.2 and so on, the oldest backup being removed.
+type RotatingFile struct {
+	mu         sync.Mutex
+	name       string
+	maxSize    int64
+	maxBackups int
+	out        *os.File
+	size       int64
+}
+
+// NewRotatingFile opens the log file name, rotated when a write would make it larger than maxSize bytes.
+// At most maxBackups rotated files are kept. The file is never rotated if maxSize is not positive.
+func NewRotatingFile(name string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	f := &RotatingFile{name: name, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// NewRotatingFileLogger is like NewFileLogger, with the log file rotated by size
+func NewRotatingFileLogger(name string, file string, maxSize int64, maxBackups int) (logger Logger, out io.Closer, err error) {
+	f, err := NewRotatingFile(file, maxSize, maxBackups)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger = &FileLogger{
+		Logger:   log.New(f, name, log.LstdFlags),
+		LogLevel: logLevelFromEnvironment(),
+	}
+	return logger, f, nil
+}
+
+func (f *RotatingFile) open() error {
+	out, err := setup(f.name)
+	if err != nil {
+		return err
+	}
+	info, err := out.Stat()
+	if err != nil {
+		out.Close()
+		return err
+	}
+	f.out = out
+	f.size = info.Size()
+	return nil
+}
+
+// Write writes p to the log file, rotating it first if needed
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.out == nil {
+		return 0, os.ErrClosed
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.out.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.out.Close(); err != nil {
+		return err
+	}
+	f.out = nil
+	if f.maxBackups > 0 {
+		os.Remove(f.backupName(f.maxBackups))
+		for i := f.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(f.backupName(i), f.backupName(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(f.name, f.backupName(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(f.name); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func (f *RotatingFile) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", f.name, i)
+}
+
+// Close closes the log file
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.out == nil {
+		return nil
+	}
+	err := f.out.Close()
+	f.out = nil
+	return err
+}
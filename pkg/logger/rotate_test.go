@@ -0,0 +1,68 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := "test-rotating-file"
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "test.log")
+
+	f, err := NewRotatingFile(name, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	for file, content := range map[string]string{name: "dddddd\n", name + ".1": "cccccc\n", name + ".2": "bbbbbb\n"} {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, content, string(b))
+	}
+	_, err = os.Stat(name + ".3")
+	require.True(t, os.IsNotExist(err))
+
+	// the size of the existing file is taken into account when reopened
+	f, err = NewRotatingFile(name, 10, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("eeeeee\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	b, err := ioutil.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, "eeeeee\n", string(b))
+	_, err = f.Write([]byte("ffffff\n"))
+	require.Equal(t, os.ErrClosed, err)
+
+	l, out, err := NewRotatingFileLogger("test ", name, 1024, 1)
+	require.NoError(t, err)
+	l.Errorf("some error")
+	require.NoError(t, out.Close())
+	b, err = ioutil.ReadFile(name)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "ERROR: some error")
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/clock"
+)
+
+// Entry is a structured log record
+type Entry struct {
+	Time      time.Time
+	Level     LogLevel
+	Component string
+	Message   string
+}
+
+// Tail keeps the most recent log entries and fans out new ones to subscribers,
+// so that logs can be followed remotely.
+type Tail struct {
+	history     []Entry
+	next        int
+	full        bool
+	subscribers map[chan Entry]struct{}
+	listeners   int32
+	clock       clock.Clock
+	sync.Mutex
+}
+
+// NewTail creates a Tail retaining the last size entries
+func NewTail(size int) *Tail {
+	return &Tail{
+		history:     make([]Entry, size),
+		subscribers: make(map[chan Entry]struct{}),
+		clock:       clock.System(),
+	}
+}
+
+// SetClock sets the clock used to timestamp the published entries
+func (t *Tail) SetClock(c clock.Clock) {
+	t.Lock()
+	defer t.Unlock()
+	t.clock = c
+}
+
+// Recent returns the retained entries, oldest first
+func (t *Tail) Recent() []Entry {
+	t.Lock()
+	defer t.Unlock()
+	return t.recent()
+}
+
+func (t *Tail) recent() []Entry {
+	if !t.full {
+		return append([]Entry(nil), t.history[:t.next]...)
+	}
+	return append(append([]Entry(nil), t.history[t.next:]...), t.history[:t.next]...)
+}
+
+// Subscribe returns the retained entries together with a channel receiving all the following ones.
+// Entries are dropped for subscribers not keeping up. The returned function must be called to unsubscribe.
+func (t *Tail) Subscribe(buffer int) ([]Entry, <-chan Entry, func()) {
+	ch := make(chan Entry, buffer)
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.subscribers[ch] = struct{}{}
+	atomic.AddInt32(&t.listeners, 1)
+
+	var once sync.Once
+	return t.recent(), ch, func() {
+		once.Do(func() {
+			t.Lock()
+			defer t.Unlock()
+			delete(t.subscribers, ch)
+			atomic.AddInt32(&t.listeners, -1)
+			close(ch)
+		})
+	}
+}
+
+func (t *Tail) publish(e Entry) {
+	t.Lock()
+	defer t.Unlock()
+
+	e.Time = t.clock.Now()
+	if len(t.history) > 0 {
+		t.history[t.next] = e
+		t.next = (t.next + 1) % len(t.history)
+		t.full = t.full || t.next == 0
+	}
+	for ch := range t.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+type tailLogger struct {
+	Logger
+	component string
+	tail      *Tail
+}
+
+// NewTailLogger returns a Logger writing to l and publishing every entry, regardless of the level of l,
+// to t under the given component name. Wrapping a tail logger again only changes the component.
+func NewTailLogger(l Logger, component string, t *Tail) Logger {
+	if tl, ok := l.(*tailLogger); ok {
+		l = tl.Logger
+	}
+	return &tailLogger{Logger: l, component: component, tail: t}
+}
+
+func (l *tailLogger) publish(level LogLevel, f string, v ...interface{}) {
+	// debug entries are published only while somebody is listening, so that
+	// the cost of formatting them is not paid in normal operation
+	if level == LogDebug && atomic.LoadInt32(&l.tail.listeners) == 0 {
+		return
+	}
+	l.tail.publish(Entry{
+		Level:     level,
+		Component: l.component,
+		Message:   fmt.Sprintf(f, v...),
+	})
+}
+
+// CloneWithLevel ...
+func (l *tailLogger) CloneWithLevel(level LogLevel) Logger {
+	return &tailLogger{Logger: l.Logger.CloneWithLevel(level), component: l.component, tail: l.tail}
+}
+
+// Errorf ...
+func (l *tailLogger) Errorf(f string, v ...interface{}) {
+	l.Logger.Errorf(f, v...)
+	l.publish(LogError, f, v...)
+}
+
+// Warningf ...
+func (l *tailLogger) Warningf(f string, v ...interface{}) {
+	l.Logger.Warningf(f, v...)
+	l.publish(LogWarn, f, v...)
+}
+
+// Infof ...
+func (l *tailLogger) Infof(f string, v ...interface{}) {
+	l.Logger.Infof(f, v...)
+	l.publish(LogInfo, f, v...)
+}
+
+// Debugf ...
+func (l *tailLogger) Debugf(f string, v ...interface{}) {
+	l.Logger.Debugf(f, v...)
+	l.publish(LogDebug, f, v...)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailLogger(t *testing.T) {
+	out := bytes.NewBufferString("")
+	tail := NewTail(2)
+	l := NewTailLogger(NewSimpleLoggerWithLevel("test", out, LogError), "server", tail)
+
+	l.Debugf("debug %d", 1)
+	l.Infof("info %d", 1)
+	l.Warningf("warning %d", 1)
+	require.NotContains(t, out.String(), "warning 1")
+
+	recent := tail.Recent()
+	require.Len(t, recent, 2)
+	require.Equal(t, "info 1", recent[0].Message)
+	require.Equal(t, LogInfo, recent[0].Level)
+	require.Equal(t, "warning 1", recent[1].Message)
+	require.Equal(t, "server", recent[1].Component)
+
+	dbLogger := NewTailLogger(l, "db", tail)
+	history, entries, unsubscribe := tail.Subscribe(10)
+	require.Len(t, history, 2)
+
+	dbLogger.Debugf("debug %d", 2)
+	dbLogger.CloneWithLevel(LogDebug).Errorf("error %d", 2)
+	require.Contains(t, out.String(), "error 2")
+
+	e := <-entries
+	require.Equal(t, "debug 2", e.Message)
+	require.Equal(t, "db", e.Component)
+	e = <-entries
+	require.Equal(t, "error 2", e.Message)
+	require.Equal(t, LogError, e.Level)
+
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-entries
+	require.False(t, ok)
+
+	l.Debugf("debug %d", 3)
+	require.Equal(t, "error 2", tail.Recent()[1].Message)
+}
+
+func TestParseLogLevel(t *testing.T) {
+	for _, level := range []LogLevel{LogDebug, LogInfo, LogWarn, LogError} {
+		parsed, err := ParseLogLevel(level.String())
+		require.NoError(t, err)
+		require.Equal(t, level, parsed)
+	}
+	_, err := ParseLogLevel("verbose")
+	require.Error(t, err)
+}
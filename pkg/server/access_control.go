@@ -0,0 +1,97 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/store/sysstore"
+	"github.com/golang/protobuf/ptypes/empty"
+)
+
+// GetAccessControlState returns a hash of the users and of their permissions, so that auditors can detect
+// permission changes between audits without being able to list the users
+func (s *ImmuServer) GetAccessControlState(ctx context.Context, req *empty.Empty) (*schema.AccessControlState, error) {
+	itemList, err := s.sysDb.Scan(&schema.ScanOptions{
+		Prefix: []byte{sysstore.KeyPrefixUser},
+	})
+	if err != nil {
+		s.Logger.Errorf("error getting users: %v", err)
+		return nil, err
+	}
+	users := make([]*auth.User, 0, len(itemList.Items))
+	for _, item := range itemList.Items {
+		var user auth.User
+		if err = json.Unmarshal(item.Value, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return &schema.AccessControlState{
+		Hash:  accessControlHash(users),
+		Users: uint32(len(users)),
+	}, nil
+}
+
+// accessControlHash hashes the name, the status and the permissions of every user, in a canonical order.
+// Passwords and creation metadata are left out, since they don't grant any access.
+func accessControlHash(users []*auth.User) []byte {
+	sorted := make([]*auth.User, len(users))
+	copy(sorted, users)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Username < sorted[j].Username })
+
+	h := sha256.New()
+	writeString := func(s string) {
+		var l [4]byte
+		binary.BigEndian.PutUint32(l[:], uint32(len(s)))
+		h.Write(l[:])
+		h.Write([]byte(s))
+	}
+	writeUint32 := func(v uint32) {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], v)
+		h.Write(b[:])
+	}
+	for _, user := range sorted {
+		writeString(user.Username)
+		if user.Active {
+			writeUint32(1)
+		} else {
+			writeUint32(0)
+		}
+		permissions := make([]auth.Permission, len(user.Permissions))
+		copy(permissions, user.Permissions)
+		sort.Slice(permissions, func(i, j int) bool {
+			if permissions[i].Database != permissions[j].Database {
+				return permissions[i].Database < permissions[j].Database
+			}
+			return permissions[i].Permission < permissions[j].Permission
+		})
+		writeUint32(uint32(len(permissions)))
+		for _, permission := range permissions {
+			writeString(permission.Database)
+			writeUint32(permission.Permission)
+		}
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessControlHash(t *testing.T) {
+	users := []*auth.User{
+		{Username: "b", Active: true, Permissions: []auth.Permission{{Database: "db2", Permission: auth.PermissionR}, {Database: "db1", Permission: auth.PermissionRW}}},
+		{Username: "a", Active: true, HashedPassword: []byte(`secret`)},
+	}
+	hash := accessControlHash(users)
+	require.Len(t, hash, 32)
+
+	// the order of users and permissions and the passwords do not matter
+	reordered := []*auth.User{
+		{Username: "a", Active: true, HashedPassword: []byte(`changed`)},
+		{Username: "b", Active: true, Permissions: []auth.Permission{{Database: "db1", Permission: auth.PermissionRW}, {Database: "db2", Permission: auth.PermissionR}}},
+	}
+	require.Equal(t, hash, accessControlHash(reordered))
+
+	reordered[1].Permissions[1].Permission = auth.PermissionRW
+	require.NotEqual(t, hash, accessControlHash(reordered))
+	reordered[1].Permissions[1].Permission = auth.PermissionR
+	reordered[0].Active = false
+	require.NotEqual(t, hash, accessControlHash(reordered))
+}
+
+func TestServerGetAccessControlState(t *testing.T) {
+	s := newInmemoryAuthServer()
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+
+	state, err := s.GetAccessControlState(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), state.Users)
+
+	_, err = s.CreateUser(ctx, &schema.CreateUserRequest{
+		User:       []byte("rwuser"),
+		Password:   []byte("rwuserPas@1"),
+		Database:   DefaultdbName,
+		Permission: auth.PermissionRW,
+	})
+	require.NoError(t, err)
+	created, err := s.GetAccessControlState(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), created.Users)
+	require.NotEqual(t, state.Hash, created.Hash)
+
+	_, err = s.ChangePermission(ctx, &schema.ChangePermissionRequest{
+		Action:     schema.PermissionAction_GRANT,
+		Database:   DefaultdbName,
+		Permission: auth.PermissionAdmin,
+		Username:   "rwuser",
+	})
+	require.NoError(t, err)
+	granted, err := s.GetAccessControlState(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.NotEqual(t, created.Hash, granted.Hash)
+
+	unchanged, err := s.GetAccessControlState(ctx, &empty.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, granted.Hash, unchanged.Hash)
+}
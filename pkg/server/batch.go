@@ -40,6 +40,17 @@ func (s *ImmuServer) GetBatch(ctx context.Context, kl *schema.KeyList) (*schema.
 	return list, nil
 }
 
+// SafeGetBatch returns the current entries of many keys in a single round trip, each one along with its inclusion
+// proof against the same root
+func (s *ImmuServer) SafeGetBatch(ctx context.Context, opts *schema.SafeGetBatchOptions) (*schema.SafeItemList, error) {
+	s.Logger.Debugf("safe get batch of %d keys", len(opts.Keys))
+	ind, err := s.getDbIndexFromCtx(ctx, "SafeGetBatch")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).SafeGetBatch(opts)
+}
+
 func (s *ImmuServer) ExecAllOps(ctx context.Context, operations *schema.Ops) (*schema.Index, error) {
 	s.Logger.Debugf("set batch atomic operations")
 
@@ -50,3 +61,36 @@ func (s *ImmuServer) ExecAllOps(ctx context.Context, operations *schema.Ops) (*s
 
 	return s.dbList.GetByIndex(ind).ExecAllOps(operations)
 }
+
+func (s *ImmuServer) ExecAllTx(ctx context.Context, operations *schema.Ops) (*schema.Index, error) {
+	s.Logger.Debugf("set batch atomic transaction")
+
+	ind, err := s.getDbIndexFromCtx(ctx, "ExecAllTx")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dbList.GetByIndex(ind).ExecAllTx(operations)
+}
+
+func (s *ImmuServer) SafeExecAllTx(ctx context.Context, opts *schema.SafeExecAllTxOptions) (*schema.TxProof, error) {
+	s.Logger.Debugf("safe set batch atomic transaction")
+
+	ind, err := s.getDbIndexFromCtx(ctx, "SafeExecAllTx")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dbList.GetByIndex(ind).SafeExecAllTx(opts)
+}
+
+func (s *ImmuServer) CompareAndExecAllTx(ctx context.Context, opts *schema.CompareAndExecAllTxOptions) (*schema.Index, error) {
+	s.Logger.Debugf("compare and set batch atomic transaction")
+
+	ind, err := s.getDbIndexFromCtx(ctx, "CompareAndExecAllTx")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dbList.GetByIndex(ind).CompareAndExecAllTx(opts)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"hash/crc32"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protoV2 "google.golang.org/protobuf/proto"
+)
+
+// CHECKSUM_HEADER is the metadata carrying the checksum of the payload of a request, and of its response.
+// Clients ask for checksummed responses by sending the checksum of their requests.
+const CHECKSUM_HEADER = "immudb-checksum"
+
+// ErrChecksumMismatch happens when the payload of a request doesn't match its checksum, as when it is corrupted in transit
+var ErrChecksumMismatch = status.New(codes.DataLoss, "request checksum mismatch: the request was corrupted in transit").Err()
+
+// ErrChecksumRequired happens when a request without checksum reaches a server requiring checksums
+var ErrChecksumRequired = status.New(codes.FailedPrecondition, "request checksums are required by the server").Err()
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Checksum returns the CRC-32C, hex encoded, of the deterministic wire encoding of msg. Both ends re-encode the
+// messages they exchange, so that the checksum doesn't depend on how the payload has been framed or compressed.
+func Checksum(msg proto.Message) (string, error) {
+	bs, err := protoV2.MarshalOptions{Deterministic: true}.Marshal(proto.MessageV2(msg))
+	if err != nil {
+		return "", err
+	}
+	sum := crc32.Checksum(bs, checksumTable)
+	return hex.EncodeToString([]byte{byte(sum >> 24), byte(sum >> 16), byte(sum >> 8), byte(sum)}), nil
+}
+
+// ChecksumInterceptor verifies the checksum of the requests carrying one before they are handled, and answers them
+// with the checksum of the response. Requests without checksum are refused if the server requires checksums.
+// Checksums only cover unary calls, they are meant for deployments terminating TLS upstream of the server.
+func (s *ImmuServer) ChecksumInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	checksum := checksumFromContext(ctx)
+	if checksum == "" {
+		if s.Options.RequireChecksums {
+			return nil, ErrChecksumRequired
+		}
+		return handler(ctx, req)
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+	sum, err := Checksum(msg)
+	if err != nil {
+		return nil, err
+	}
+	if sum != checksum {
+		s.Logger.Warningf("checksum mismatch on %s: %s received, %s computed", info.FullMethod, checksum, sum)
+		return nil, ErrChecksumMismatch
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	if msg, ok := resp.(proto.Message); ok {
+		if sum, err = Checksum(msg); err != nil {
+			return nil, err
+		}
+		if err = grpc.SetHeader(ctx, metadata.Pairs(CHECKSUM_HEADER, sum)); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func checksumFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(CHECKSUM_HEADER)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestChecksum(t *testing.T) {
+	kv := &schema.KeyValue{Key: []byte("key"), Value: []byte("value")}
+	sum, err := Checksum(kv)
+	require.NoError(t, err)
+	require.Len(t, sum, 8)
+
+	same, err := Checksum(&schema.KeyValue{Key: []byte("key"), Value: []byte("value")})
+	require.NoError(t, err)
+	require.Equal(t, sum, same)
+
+	other, err := Checksum(&schema.KeyValue{Key: []byte("key"), Value: []byte("valuf")})
+	require.NoError(t, err)
+	require.NotEqual(t, sum, other)
+}
+
+func TestChecksumInterceptor(t *testing.T) {
+	s := DefaultServer()
+	info := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/Set"}
+	kv := &schema.KeyValue{Key: []byte("key"), Value: []byte("value")}
+	handled := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = true
+		return &schema.Index{Index: 1}, nil
+	}
+
+	_, err := s.ChecksumInterceptor(context.Background(), kv, info, handler)
+	require.NoError(t, err)
+	require.True(t, handled)
+
+	handled = false
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CHECKSUM_HEADER, "00000000"))
+	_, err = s.ChecksumInterceptor(ctx, kv, info, handler)
+	require.Equal(t, ErrChecksumMismatch, err)
+	require.False(t, handled)
+
+	s.Options = s.Options.WithRequireChecksums(true)
+	_, err = s.ChecksumInterceptor(context.Background(), kv, info, handler)
+	require.Equal(t, ErrChecksumRequired, err)
+	require.False(t, handled)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"fmt"
 	mrand "math/rand"
 	"sync"
 	"time"
@@ -37,6 +38,9 @@ type CCOptions struct {
 	singleiteration    bool
 	iterationSleepTime time.Duration
 	frequencySleepTime time.Duration
+	// onTamper is called with the name of the database and the details of a possible tampering, by default all
+	// traffic is refused
+	onTamper func(database string, reason string)
 }
 
 type corruptionChecker struct {
@@ -60,6 +64,9 @@ type CorruptionChecker interface {
 
 // NewCorruptionChecker returns new trust checker service
 func NewCorruptionChecker(opt CCOptions, d DatabaseList, l logger.Logger, rg RandomGenerator) CorruptionChecker {
+	if opt.onTamper == nil {
+		opt.onTamper = func(string, string) { auth.IsTampered = true }
+	}
 	return &corruptionChecker{
 		options:        opt,
 		dbList:         d,
@@ -132,9 +139,14 @@ func (s *corruptionChecker) checkLevel0(ctx context.Context) (err error) {
 					Index: r.GetIndex(),
 				},
 			}); err != nil {
+				if err == store.ErrEntryPruned {
+					// the value is gone, there's nothing left to check
+					err = nil
+					continue
+				}
 				if err == store.ErrInconsistentDigest {
-					auth.IsTampered = true
 					s.Logger.Errorf("insertion order index %d was tampered", id)
+					s.options.onTamper(db.options.GetDbName(), fmt.Sprintf("insertion order index %d was tampered", id))
 					return
 				}
 				s.Logger.Errorf("Error retrieving element at index %d: %s", id, err)
@@ -144,8 +156,8 @@ func (s *corruptionChecker) checkLevel0(ctx context.Context) (err error) {
 			s.Logger.Debugf("Item index %d, value %s, verified %t", item.Item.Index, item.Item.Value, verified)
 			if !verified {
 				s.Trusted = false
-				auth.IsTampered = true
 				s.Logger.Errorf(ErrConsistencyFail, item.Item.Index)
+				s.options.onTamper(db.options.GetDbName(), fmt.Sprintf(ErrConsistencyFail, item.Item.Index))
 				return
 			}
 			time.Sleep(s.options.frequencySleepTime)
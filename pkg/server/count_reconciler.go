@@ -0,0 +1,80 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+)
+
+// countReconciler periodically compares the number of data entries with the number of tree leaves of every
+// database and exposes the difference as a metric. A divergence means that the tree is corrupted or that the
+// store has a bug, which otherwise would go unnoticed until proofs start failing.
+type countReconciler struct {
+	dbList   DatabaseList
+	Logger   logger.Logger
+	interval time.Duration
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newCountReconciler(d DatabaseList, l logger.Logger, interval time.Duration) *countReconciler {
+	return &countReconciler{
+		dbList:   d,
+		Logger:   l,
+		interval: interval,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start runs the reconciliation loop in a new goroutine
+func (r *countReconciler) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.quit:
+				return
+			case <-ticker.C:
+				r.reconcile()
+			}
+		}
+	}()
+}
+
+// Stop terminates the reconciliation loop and waits for the current iteration, if any, to complete
+func (r *countReconciler) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *countReconciler) reconcile() {
+	for i := 0; i < r.dbList.Length(); i++ {
+		db := r.dbList.GetByIndex(int64(i))
+		entries, leaves := db.Store.CountEntriesAndLeaves()
+		divergence := float64(entries) - float64(leaves)
+		Metrics.IndexCountDivergenceGauges.WithLabelValues(db.options.GetDbName()).Set(divergence)
+		if divergence != 0 {
+			r.Logger.Warningf("index count divergence detected on database %s: %d data entries, %d tree leaves", db.options.GetDbName(), entries, leaves)
+		}
+	}
+}
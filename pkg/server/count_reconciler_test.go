@@ -0,0 +1,46 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountReconciler(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	for i := 0; i < 8; i++ {
+		_, err := db.Set(&schema.KeyValue{Key: []byte("key"), Value: []byte("value")})
+		assert.NoError(t, err)
+	}
+	dbList := NewDatabaseList()
+	dbList.Append(db)
+
+	gauge := Metrics.IndexCountDivergenceGauges.WithLabelValues(db.options.GetDbName())
+	gauge.Set(-1)
+
+	r := newCountReconciler(dbList, &mockLogger{}, time.Millisecond)
+	r.Start()
+	assert.Eventually(t, func() bool { return testutil.ToFloat64(gauge) == 0 }, time.Second, time.Millisecond)
+	r.Stop()
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package server
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -27,13 +30,17 @@ import (
 	"github.com/codenotary/immudb/pkg/store"
 	"github.com/dgraph-io/badger/v2/pb"
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 //Db database instance
 type Db struct {
-	Store   *store.Store
-	Logger  logger.Logger
-	options *DbOptions
+	Store       *store.Store
+	Logger      logger.Logger
+	options     *DbOptions
+	idempotency *idempotencyIndex
+	plugins     []Plugin
 }
 
 // OpenDb Opens an existing Database from disk
@@ -41,8 +48,10 @@ func OpenDb(op *DbOptions, log logger.Logger) (*Db, error) {
 	var err error
 
 	db := &Db{
-		Logger:  log,
-		options: op,
+		Logger:      log,
+		options:     op,
+		idempotency: newIdempotencyIndex(op.GetIdempotencyTTL()),
+		plugins:     registeredPlugins(),
 	}
 
 	dbDir := filepath.Join(op.GetDbRootPath(), op.GetDbName())
@@ -52,9 +61,22 @@ func OpenDb(op *DbOptions, log logger.Logger) (*Db, error) {
 		return nil, fmt.Errorf("Missing database directories")
 	}
 
-	db.Store, err = store.Open(store.DefaultOptions(dbDir, db.Logger))
+	storeOpts, badgerOpts := store.DefaultOptions(dbDir, db.Logger)
+	storeOpts = withStoreOptions(storeOpts, op)
+	if db.Store, err = store.Open(storeOpts, badgerOpts); err != nil {
+		return db, logErr(db.Logger, "Unable to open store: %s", err)
+	}
+	db.Logger.Infof("Database %s opened at boot epoch %d", op.GetDbName(), db.Store.BootEpoch())
+	db.checkReservedKeyCollisions()
 
-	return db, logErr(db.Logger, "Unable to open store: %s", err)
+	return db, nil
+}
+
+// withStoreOptions returns storeOpts set as op tells
+func withStoreOptions(storeOpts store.Options, op *DbOptions) store.Options {
+	return storeOpts.WithStrictAppendOnly(op.GetStrictAppendOnly()).WithSequencer(op.GetSequencer()).WithClock(op.GetClock()).
+		WithTreeCheckpointInterval(op.GetCheckpointInterval()).WithSyncWrites(op.GetSyncWrites()).WithTreeSync(op.GetTreeSync()).WithValueCompression(op.GetValueCompression()).
+		WithEncryptionKey(op.GetEncryptionKey()).WithDataKeyRotation(op.GetDataKeyRotation())
 }
 
 // NewDb Creates a new Database along with it's directories and files
@@ -62,13 +84,16 @@ func NewDb(op *DbOptions, log logger.Logger) (*Db, error) {
 	var err error
 
 	db := &Db{
-		Logger:  log,
-		options: op,
+		Logger:      log,
+		options:     op,
+		idempotency: newIdempotencyIndex(op.GetIdempotencyTTL()),
+		plugins:     registeredPlugins(),
 	}
 
 	if op.GetInMemoryStore() {
 		db.Logger.Infof("Starting with in memory store")
 		storeOpts, badgerOpts := store.DefaultOptions("", db.Logger)
+		storeOpts = withStoreOptions(storeOpts, op)
 		badgerOpts = badgerOpts.WithInMemory(true)
 		db.Store, err = store.Open(storeOpts, badgerOpts)
 		return db, logErr(db.Logger, "Unable to open store: %s", err)
@@ -84,13 +109,41 @@ func NewDb(op *DbOptions, log logger.Logger) (*Db, error) {
 		return nil, logErr(db.Logger, "Unable to create data folder: %s", err)
 	}
 
-	db.Store, err = store.Open(store.DefaultOptions(dbDir, db.Logger))
+	storeOpts, badgerOpts := store.DefaultOptions(dbDir, db.Logger)
+	storeOpts = withStoreOptions(storeOpts, op)
+	db.Store, err = store.Open(storeOpts, badgerOpts)
 	return db, logErr(db.Logger, "Unable to open store: %s", err)
 }
 
 //Set ...
 func (d *Db) Set(kv *schema.KeyValue) (*schema.Index, error) {
-	return d.Store.Set(*kv)
+	if err := checkKeyValues("", kv); err != nil {
+		return nil, err
+	}
+	var index *schema.Index
+	err := d.hooked(kvOps(kv), func() (uint64, error) {
+		var err error
+		if index, err = d.Store.Set(*kv); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+// IdempotentSet is like Set, but a request retried with the same idempotency key gets the original index back
+func (d *Db) IdempotentSet(idempotencyKey string, kv *schema.KeyValue) (*schema.Index, error) {
+	index, _, err := d.idempotency.run(idempotencyKey, digestKeyValue(kv), func() (uint64, error) {
+		index, err := d.Set(kv)
+		if err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schema.Index{Index: index}, nil
 }
 
 //Get ...
@@ -115,7 +168,40 @@ func (d *Db) CurrentRoot(e *empty.Empty) (*schema.Root, error) {
 
 //SafeSet ...
 func (d *Db) SafeSet(opts *schema.SafeSetOptions) (*schema.Proof, error) {
-	return d.Store.SafeSet(*opts)
+	if err := checkKeyValues("kv", opts.GetKv()); err != nil {
+		return nil, err
+	}
+	var proof *schema.Proof
+	err := d.hooked(kvOps(opts.Kv), func() (uint64, error) {
+		var err error
+		if proof, err = d.Store.SafeSet(*opts); err != nil {
+			return 0, err
+		}
+		return proof.Index, nil
+	})
+	return proof, err
+}
+
+// IdempotentSafeSet is like SafeSet, but a request retried with the same idempotency key gets back
+// the proof of the original entry against the current root
+func (d *Db) IdempotentSafeSet(idempotencyKey string, opts *schema.SafeSetOptions) (*schema.Proof, error) {
+	var proof *schema.Proof
+	index, written, err := d.idempotency.run(idempotencyKey, digestKeyValue(opts.Kv), func() (uint64, error) {
+		var err error
+		if proof, err = d.SafeSet(opts); err != nil {
+			return 0, err
+		}
+		return proof.Index, nil
+	})
+	if err != nil || written {
+		return proof, err
+	}
+
+	safeItem, err := d.Store.BySafeIndex(schema.SafeIndexOptions{Index: index, RootIndex: opts.RootIndex})
+	if err != nil {
+		return nil, err
+	}
+	return safeItem.Proof, nil
 }
 
 //SafeGet ...
@@ -123,9 +209,25 @@ func (d *Db) SafeGet(opts *schema.SafeGetOptions) (*schema.SafeItem, error) {
 	return d.Store.SafeGet(*opts)
 }
 
+// SafeGetBatch fetches the current entries of many keys with their inclusion proofs against the same root
+func (d *Db) SafeGetBatch(opts *schema.SafeGetBatchOptions) (*schema.SafeItemList, error) {
+	return d.Store.SafeGetBatch(*opts)
+}
+
 // SetBatch ...
 func (d *Db) SetBatch(kvl *schema.KVList) (*schema.Index, error) {
-	return d.Store.SetBatch(*kvl)
+	if err := checkKeyValues("KVs", kvl.GetKVs()...); err != nil {
+		return nil, err
+	}
+	var index *schema.Index
+	err := d.hooked(kvOps(kvl.KVs...), func() (uint64, error) {
+		var err error
+		if index, err = d.Store.SetBatch(*kvl); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
 }
 
 //GetBatch ...
@@ -146,7 +248,70 @@ func (d *Db) GetBatch(kl *schema.KeyList) (*schema.ItemList, error) {
 
 // ExecAllOps ...
 func (d *Db) ExecAllOps(operations *schema.Ops) (*schema.Index, error) {
-	return d.Store.ExecAllOps(operations)
+	if err := checkOps(operations); err != nil {
+		return nil, err
+	}
+	ops := func() *schema.Ops { return operations }
+	var index *schema.Index
+	err := d.hooked(ops, func() (uint64, error) {
+		var err error
+		if index, err = d.Store.ExecAllOps(operations); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+// ExecAllTx is like ExecAllOps, with the operations committed as a single verifiable transaction
+func (d *Db) ExecAllTx(operations *schema.Ops) (*schema.Index, error) {
+	if err := checkOps(operations); err != nil {
+		return nil, err
+	}
+	ops := func() *schema.Ops { return operations }
+	var index *schema.Index
+	err := d.hooked(ops, func() (uint64, error) {
+		var err error
+		if index, err = d.Store.ExecAllTx(operations); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+// SafeExecAllTx ...
+func (d *Db) SafeExecAllTx(opts *schema.SafeExecAllTxOptions) (*schema.TxProof, error) {
+	if err := checkOps(opts.GetOps()); err != nil {
+		return nil, err
+	}
+	ops := func() *schema.Ops { return opts.Ops }
+	var txProof *schema.TxProof
+	err := d.hooked(ops, func() (uint64, error) {
+		var err error
+		if txProof, err = d.Store.SafeExecAllTx(*opts); err != nil {
+			return 0, err
+		}
+		return txProof.Commit.Index, nil
+	})
+	return txProof, err
+}
+
+// CompareAndExecAllTx is like ExecAllTx, provided that the expected keys are at the expected indexes
+func (d *Db) CompareAndExecAllTx(opts *schema.CompareAndExecAllTxOptions) (*schema.Index, error) {
+	if err := checkOps(opts.GetOps()); err != nil {
+		return nil, err
+	}
+	ops := func() *schema.Ops { return opts.Ops }
+	var index *schema.Index
+	err := d.hooked(ops, func() (uint64, error) {
+		var err error
+		if index, err = d.Store.CompareAndExecAllTx(*opts); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
 }
 
 //Count ...
@@ -154,6 +319,11 @@ func (d *Db) Count(prefix *schema.KeyPrefix) (*schema.ItemsCount, error) {
 	return d.Store.Count(*prefix)
 }
 
+// Stats returns the statistics of the keys having the provided prefix
+func (d *Db) Stats(prefix *schema.KeyPrefix) (*schema.PrefixStats, error) {
+	return d.Store.Stats(*prefix)
+}
+
 // CountAll ...
 func (d *Db) CountAll() *schema.ItemsCount {
 	return &schema.ItemsCount{Count: d.Store.CountAll()}
@@ -174,6 +344,16 @@ func (d *Db) ByIndex(index *schema.Index) (*schema.Item, error) {
 	return d.Store.ByIndex(*index)
 }
 
+// GetBySequence ...
+func (d *Db) GetBySequence(seq *schema.Sequence) (*schema.SequencedWrite, error) {
+	return d.Store.GetBySequence(*seq)
+}
+
+// VerifyValueHash ...
+func (d *Db) VerifyValueHash(opts *schema.ValueHashOptions) (*schema.ValueHashVerification, error) {
+	return d.Store.VerifyValueHash(*opts)
+}
+
 //BySafeIndex ...
 func (d *Db) BySafeIndex(sio *schema.SafeIndexOptions) (*schema.SafeItem, error) {
 	return d.Store.BySafeIndex(*sio)
@@ -184,6 +364,61 @@ func (d *Db) History(options *schema.HistoryOptions) (*schema.ItemList, error) {
 	return d.Store.History(options)
 }
 
+//DumpKeyHistory ...
+func (d *Db) DumpKeyHistory(k *schema.Key) (*schema.KeyHistoryDump, error) {
+	return d.Store.KeyHistoryDump(*k)
+}
+
+// GetAt fetches the entry a key had at an index of the tree with its proof against the root at that index
+func (d *Db) GetAt(opts *schema.GetAtOptions) (*schema.HistoricalItem, error) {
+	return d.Store.GetAt(*opts)
+}
+
+// GetAsOf fetches the entry a key had at a point in time with its proof against the root at that time
+func (d *Db) GetAsOf(opts *schema.GetAsOfOptions) (*schema.HistoricalItem, error) {
+	return d.Store.GetAsOf(*opts)
+}
+
+//SampleKeys ...
+func (d *Db) SampleKeys(options *schema.SampleOptions) (*schema.KeySample, error) {
+	return d.Store.SampleKeys(*options)
+}
+
+//Delete ...
+func (d *Db) Delete(key *schema.Key) (index *schema.Index, err error) {
+	if err = checkReserved("key", key.GetKey()); err != nil {
+		return nil, err
+	}
+	err = d.hooked(deleteOps(key), func() (uint64, error) {
+		if index, err = d.Store.Delete(*key); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+//FreezePrefix ...
+func (d *Db) FreezePrefix(prefix *schema.KeyPrefix) (index *schema.Index, err error) {
+	err = d.hooked(freezeOps(prefix), func() (uint64, error) {
+		if index, err = d.Store.FreezePrefix(*prefix); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+//AnalyzeStorage ...
+func (d *Db) AnalyzeStorage(*empty.Empty) (*schema.StorageReport, error) {
+	report, err := d.Store.StorageReport()
+	if err != nil {
+		return nil, err
+	}
+	report.Database = d.options.GetDbName()
+	return report, nil
+}
+
 //Health ...
 func (d *Db) Health(*empty.Empty) (*schema.HealthResponse, error) {
 	health := d.Store.HealthCheck()
@@ -192,8 +427,42 @@ func (d *Db) Health(*empty.Empty) (*schema.HealthResponse, error) {
 
 //Reference ...
 func (d *Db) Reference(refOpts *schema.ReferenceOptions) (index *schema.Index, err error) {
+	if err = checkReferenceOptions("", refOpts); err != nil {
+		return nil, err
+	}
 	d.Logger.Debugf("reference options: %v", refOpts)
-	return d.Store.Reference(refOpts)
+	err = d.hooked(referenceOps(refOpts), func() (uint64, error) {
+		if index, err = d.Store.Reference(refOpts); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+//CompareAndReference ...
+func (d *Db) CompareAndReference(carOpts *schema.CompareAndReferenceOptions) (index *schema.Index, err error) {
+	if err = checkKeyValues("kv", carOpts.GetKv()); err != nil {
+		return nil, err
+	}
+	if err = checkReserved("reference", carOpts.GetReference()); err != nil {
+		return nil, err
+	}
+	ops := func() *schema.Ops {
+		o := kvOps(carOpts.Kv)()
+		o.Operations = append(o.Operations, referenceOps(&schema.ReferenceOptions{
+			Reference: carOpts.Reference,
+			Key:       carOpts.GetKv().GetKey(),
+		})().Operations...)
+		return o
+	}
+	err = d.hooked(ops, func() (uint64, error) {
+		if index, err = d.Store.CompareAndReference(carOpts); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
 }
 
 //Reference ...
@@ -202,14 +471,54 @@ func (d *Db) GetReference(refOpts *schema.Key) (index *schema.Item, err error) {
 	return d.Store.GetReference(*refOpts)
 }
 
+//Unreference ...
+func (d *Db) Unreference(key *schema.Key) (index *schema.Index, err error) {
+	if err = checkReserved("reference", key.GetKey()); err != nil {
+		return nil, err
+	}
+	err = d.hooked(unreferenceOps(key), func() (uint64, error) {
+		if index, err = d.Store.Unreference(*key); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
+}
+
+// GetReferences ...
+func (d *Db) GetReferences(options *schema.ReferencesOptions) (*schema.ReferenceList, error) {
+	d.Logger.Debugf("getReferences options: %v", options)
+	return d.Store.GetReferences(*options)
+}
+
 //SafeReference ...
 func (d *Db) SafeReference(safeRefOpts *schema.SafeReferenceOptions) (proof *schema.Proof, err error) {
-	return d.Store.SafeReference(*safeRefOpts)
+	if err = checkReferenceOptions("ro.", safeRefOpts.GetRo()); err != nil {
+		return nil, err
+	}
+	err = d.hooked(referenceOps(safeRefOpts.Ro), func() (uint64, error) {
+		if proof, err = d.Store.SafeReference(*safeRefOpts); err != nil {
+			return 0, err
+		}
+		return proof.Index, nil
+	})
+	return proof, err
 }
 
 //ZAdd ...
 func (d *Db) ZAdd(opts *schema.ZAddOptions) (*schema.Index, error) {
-	return d.Store.ZAdd(*opts)
+	if err := checkZAddOptions("", opts); err != nil {
+		return nil, err
+	}
+	var index *schema.Index
+	err := d.hooked(zAddOps(opts), func() (uint64, error) {
+		var err error
+		if index, err = d.Store.ZAdd(*opts); err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+	return index, err
 }
 
 // ZScan ...
@@ -217,9 +526,25 @@ func (d *Db) ZScan(opts *schema.ZScanOptions) (*schema.ZItemList, error) {
 	return d.Store.ZScan(*opts)
 }
 
+// SafeZScan scans a sorted set along with the proofs of its entries and of the items they reference
+func (d *Db) SafeZScan(opts *schema.SafeZScanOptions) (*schema.SafeZItemList, error) {
+	return d.Store.SafeZScan(*opts)
+}
+
 //SafeZAdd ...
 func (d *Db) SafeZAdd(opts *schema.SafeZAddOptions) (*schema.Proof, error) {
-	return d.Store.SafeZAdd(*opts)
+	if err := checkZAddOptions("zopts.", opts.GetZopts()); err != nil {
+		return nil, err
+	}
+	var proof *schema.Proof
+	err := d.hooked(zAddOps(opts.Zopts), func() (uint64, error) {
+		var err error
+		if proof, err = d.Store.SafeZAdd(*opts); err != nil {
+			return 0, err
+		}
+		return proof.Index, nil
+	})
+	return proof, err
 }
 
 //Scan ...
@@ -257,6 +582,146 @@ func (d *Db) Dump(in *empty.Empty, stream schema.ImmuService_DumpServer) error {
 	return err
 }
 
+// Backup writes a consistent snapshot of the database into a file of dir/<database name>, without stopping writes.
+// The snapshot is written to a temporary file first, so that an interrupted backup never leaves a partial snapshot.
+func (d *Db) Backup(dir string) (*schema.BackupInfo, error) {
+	dbDir := filepath.Join(dir, d.options.GetDbName())
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := ioutil.TempFile(dbDir, ".snapshot-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	header, err := d.Store.Backup(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(dbDir, fmt.Sprintf("%s_%d.snapshot", header.CreatedAt.Format("20060102T150405Z"), header.Index))
+	if err = os.Rename(f.Name(), file); err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	root := schema.NewRoot()
+	root.SetIndex(header.Index)
+	root.SetRoot(header.Root)
+	return &schema.BackupInfo{
+		DatabaseName: d.options.GetDbName(),
+		File:         file,
+		Root:         root,
+		Timestamp:    header.CreatedAt.UnixNano(),
+		Size:         uint64(fi.Size()),
+	}, nil
+}
+
+// RestoreDb creates a new database, along with its directories, from a snapshot written by Backup. The tree is
+// derived again from the entries of the snapshot, and the database is returned only if its root matches the one
+// recorded in the snapshot, otherwise the directories are removed.
+func RestoreDb(op *DbOptions, file string, log logger.Logger) (*Db, *schema.BackupInfo, error) {
+	db := &Db{
+		Logger:      log,
+		options:     op,
+		idempotency: newIdempotencyIndex(op.GetIdempotencyTTL()),
+		plugins:     registeredPlugins(),
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dbDir := filepath.Join(op.GetDbRootPath(), op.GetDbName())
+	if _, dbErr := os.Stat(dbDir); dbErr == nil {
+		return nil, nil, fmt.Errorf("Database directories already exist")
+	}
+	if err = os.MkdirAll(dbDir, os.ModePerm); err != nil {
+		return nil, nil, logErr(db.Logger, "Unable to create data folder: %s", err)
+	}
+
+	storeOpts, badgerOpts := store.DefaultOptions(dbDir, db.Logger)
+	storeOpts = withStoreOptions(storeOpts, op)
+	st, header, err := store.RestoreBackup(bufio.NewReader(f), storeOpts, badgerOpts)
+	if err != nil {
+		os.RemoveAll(dbDir)
+		return nil, nil, logErr(db.Logger, "Unable to restore backup: %s", err)
+	}
+	db.Store = st
+
+	root := schema.NewRoot()
+	root.SetIndex(header.Index)
+	root.SetRoot(header.Root)
+	return db, &schema.BackupInfo{
+		DatabaseName: op.GetDbName(),
+		File:         file,
+		Root:         root,
+		Timestamp:    header.CreatedAt.UnixNano(),
+		Size:         uint64(fi.Size()),
+	}, nil
+}
+
+// Subscribe streams the changes of the key, or of the keys starting with the prefix, selected by req until the stream
+// is closed. Only the changes committed once the subscription is established are sent.
+func (d *Db) Subscribe(req *schema.SubscribeRequest, stream schema.ImmuService_SubscribeServer) error {
+	if len(req.Key) > 0 && len(req.Prefix) > 0 {
+		return status.New(codes.InvalidArgument, "either a key or a prefix can be subscribed to").Err()
+	}
+	prefix := req.Prefix
+	if len(req.Key) > 0 {
+		prefix = req.Key
+	}
+	return d.Store.Subscribe(stream.Context(), prefix, func(item *schema.Item) error {
+		if len(req.Key) > 0 && !bytes.Equal(item.Key, req.Key) {
+			return nil
+		}
+		change := &schema.KeyChange{Item: item}
+		if req.Proof {
+			proof, err := d.Store.InclusionProof(schema.Index{Index: item.Index})
+			if err != nil {
+				return err
+			}
+			change.Proof = proof
+		}
+		return stream.Send(change)
+	})
+}
+
+// Tail streams every entry from the index selected by req on, in index order, including the entries appended while
+// streaming, until the stream is closed. Every entry is proven against the current root, consistent with the root of
+// the previous entry, so that a client can verify the whole feed from a single trusted root.
+func (d *Db) Tail(req *schema.TailRequest, stream schema.ImmuService_TailServer) error {
+	prev := req.GetRootIndex()
+	return d.Store.Tail(stream.Context(), req.GetFrom(), func(e *store.TailEntry) error {
+		proof, err := d.Store.ProofAt(e.Item.Index, prev)
+		if err != nil {
+			return err
+		}
+		prev = &schema.Index{Index: proof.At}
+		return stream.Send(&schema.TailItem{Item: e.Item, Proof: proof, Pruned: e.Type == store.EntryPruned})
+	})
+}
+
 // PrintTree ...
 func (d *Db) PrintTree() *schema.Tree {
 	return d.Store.GetTree()
@@ -16,13 +16,32 @@ limitations under the License.
 
 package server
 
-//DbOptions database instance options
+import (
+	"time"
+
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/codenotary/immudb/pkg/store"
+)
+
+// DbOptions database instance options
 type DbOptions struct {
 	//	dbDir             string
 	dbName            string
 	dbRootPath        string
 	corruptionChecker bool
 	inMemoryStore     bool
+	strictAppendOnly  bool
+	sequencer         bool
+	idempotencyTTL    time.Duration
+	clock             clock.Clock
+
+	// checkpointInterval is the number of entries after which the tree is checkpointed, zero for the store default
+	checkpointInterval uint64
+	syncWrites         bool
+	treeSync           bool
+	valueCompression   store.ValueCompression
+	encryptionKey      []byte
+	dataKeyRotation    time.Duration
 }
 
 // DefaultOption Initialise Db Optionts to default values
@@ -33,6 +52,8 @@ func DefaultOption() *DbOptions {
 		dbRootPath:        DefaultOptions().Dir,
 		corruptionChecker: true,
 		inMemoryStore:     false,
+		strictAppendOnly:  false,
+		idempotencyTTL:    5 * time.Minute,
 	}
 }
 
@@ -75,7 +96,117 @@ func (o *DbOptions) WithInMemoryStore(inmemory bool) *DbOptions {
 	return o
 }
 
-//GetInMemoryStore returns if we use in memory database without persistence
+// GetInMemoryStore returns if we use in memory database without persistence
 func (o *DbOptions) GetInMemoryStore() bool {
 	return o.inMemoryStore
 }
+
+// WithStrictAppendOnly sets if the database rejects operations that could alter the appearance of history order
+func (o *DbOptions) WithStrictAppendOnly(strict bool) *DbOptions {
+	o.strictAppendOnly = strict
+	return o
+}
+
+// GetStrictAppendOnly returns if the database runs in strict append-only mode
+func (o *DbOptions) GetStrictAppendOnly() bool {
+	return o.strictAppendOnly
+}
+
+// WithSequencer sets if every write to the database is assigned a gap-free sequence number
+func (o *DbOptions) WithSequencer(sequencer bool) *DbOptions {
+	o.sequencer = sequencer
+	return o
+}
+
+// GetSequencer returns if the database runs in sequencer mode
+func (o *DbOptions) GetSequencer() bool {
+	return o.sequencer
+}
+
+// WithCheckpointInterval sets the number of entries after which the Merkle tree of the database is checkpointed
+func (o *DbOptions) WithCheckpointInterval(entries uint64) *DbOptions {
+	o.checkpointInterval = entries
+	return o
+}
+
+// GetCheckpointInterval returns the number of entries after which the Merkle tree of the database is checkpointed
+func (o *DbOptions) GetCheckpointInterval() uint64 {
+	return o.checkpointInterval
+}
+
+// WithSyncWrites sets if every write to the database waits for the data to be synced to disk
+func (o *DbOptions) WithSyncWrites(syncWrites bool) *DbOptions {
+	o.syncWrites = syncWrites
+	return o
+}
+
+// GetSyncWrites returns if every write to the database waits for the data to be synced to disk
+func (o *DbOptions) GetSyncWrites() bool {
+	return o.syncWrites
+}
+
+// WithTreeSync sets if every checkpoint of the Merkle tree of the database is synced to disk
+func (o *DbOptions) WithTreeSync(treeSync bool) *DbOptions {
+	o.treeSync = treeSync
+	return o
+}
+
+// GetTreeSync returns if every checkpoint of the Merkle tree of the database is synced to disk
+func (o *DbOptions) GetTreeSync() bool {
+	return o.treeSync
+}
+
+// WithValueCompression sets the algorithm compressing the values of the database before they are persisted
+func (o *DbOptions) WithValueCompression(c store.ValueCompression) *DbOptions {
+	o.valueCompression = c
+	return o
+}
+
+// GetValueCompression returns the algorithm compressing the values of the database before they are persisted
+func (o *DbOptions) GetValueCompression() store.ValueCompression {
+	return o.valueCompression
+}
+
+// WithEncryptionKey sets the key encrypting the data of the database at rest, nil for no encryption
+func (o *DbOptions) WithEncryptionKey(key []byte) *DbOptions {
+	o.encryptionKey = key
+	return o
+}
+
+// GetEncryptionKey returns the key encrypting the data of the database at rest, nil if it's not encrypted
+func (o *DbOptions) GetEncryptionKey() []byte {
+	return o.encryptionKey
+}
+
+// WithDataKeyRotation sets how often the data keys of an encrypted database are rotated, zero for the store default
+func (o *DbOptions) WithDataKeyRotation(d time.Duration) *DbOptions {
+	o.dataKeyRotation = d
+	return o
+}
+
+// GetDataKeyRotation returns how often the data keys of an encrypted database are rotated
+func (o *DbOptions) GetDataKeyRotation() time.Duration {
+	return o.dataKeyRotation
+}
+
+// WithIdempotencyTTL sets for how long idempotency keys of writes are remembered. Zero disables deduplication
+func (o *DbOptions) WithIdempotencyTTL(ttl time.Duration) *DbOptions {
+	o.idempotencyTTL = ttl
+	return o
+}
+
+// GetIdempotencyTTL returns for how long idempotency keys of writes are remembered
+func (o *DbOptions) GetIdempotencyTTL() time.Duration {
+	return o.idempotencyTTL
+}
+
+// WithClock sets the clock used to timestamp the entries of the database
+func (o *DbOptions) WithClock(c clock.Clock) *DbOptions {
+	o.clock = c
+	return o
+}
+
+// GetClock returns the clock used to timestamp the entries of the database, nil meaning the system one
+func (o *DbOptions) GetClock() clock.Clock {
+	return o.clock
+}
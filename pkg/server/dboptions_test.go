@@ -18,6 +18,9 @@ package server
 
 import (
 	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/clock"
 )
 
 func TestDefaultOptions(t *testing.T) {
@@ -34,11 +37,24 @@ func TestDefaultOptions(t *testing.T) {
 	if op.GetInMemoryStore() {
 		t.Errorf("default in memory store not what expected")
 	}
+	if op.GetStrictAppendOnly() {
+		t.Errorf("default strict append-only not what expected")
+	}
+	if op.GetSequencer() {
+		t.Errorf("default sequencer not what expected")
+	}
+	if op.GetIdempotencyTTL() != 5*time.Minute {
+		t.Errorf("default idempotency ttl not what expected")
+	}
+	if op.GetClock() != nil {
+		t.Errorf("default clock not what expected")
+	}
 
 	DbName := "Charles_Aznavour"
 	rootpath := "rootpath"
 	op = DefaultOption().WithDbName(DbName).
-		WithDbRootPath(rootpath).WithCorruptionChecker(false).WithInMemoryStore(true)
+		WithDbRootPath(rootpath).WithCorruptionChecker(false).WithInMemoryStore(true).WithStrictAppendOnly(true).WithSequencer(true).
+		WithCheckpointInterval(1000).WithSyncWrites(true).WithTreeSync(true).WithIdempotencyTTL(time.Second).WithClock(clock.System())
 	if op.GetDbName() != DbName {
 		t.Errorf("db name not set correctly , expected %s got %s", DbName, op.GetDbName())
 	}
@@ -52,4 +68,22 @@ func TestDefaultOptions(t *testing.T) {
 	if !op.GetInMemoryStore() {
 		t.Errorf("in  memory store not set correctly , expected %v got %v", false, op.GetInMemoryStore())
 	}
+	if !op.GetStrictAppendOnly() {
+		t.Errorf("strict append-only not set correctly , expected %v got %v", true, op.GetStrictAppendOnly())
+	}
+	if !op.GetSequencer() {
+		t.Errorf("sequencer not set correctly , expected %v got %v", true, op.GetSequencer())
+	}
+	if op.GetCheckpointInterval() != 1000 {
+		t.Errorf("checkpoint interval not set correctly , expected %v got %v", 1000, op.GetCheckpointInterval())
+	}
+	if !op.GetSyncWrites() || !op.GetTreeSync() {
+		t.Errorf("sync not set correctly , expected %v got %v %v", true, op.GetSyncWrites(), op.GetTreeSync())
+	}
+	if op.GetIdempotencyTTL() != time.Second {
+		t.Errorf("idempotency ttl not set correctly , expected %v got %v", time.Second, op.GetIdempotencyTTL())
+	}
+	if op.GetClock() != clock.System() {
+		t.Errorf("clock not set correctly , expected %v got %v", clock.System(), op.GetClock())
+	}
 }
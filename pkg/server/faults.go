@@ -0,0 +1,105 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// FaultInjection configures the failures simulated by the server, so that client retry logic, auditors and monitoring
+// can be tested against them. Faults are only injected by builds with the faultinjection tag: other builds refuse to
+// start if any fault is configured.
+type FaultInjection struct {
+	// MaxCommitDelay is the upper bound of the random delay added to every write before it is committed, 0 disables it
+	MaxCommitDelay time.Duration
+	// DropSyncs makes the databases skip every sync to disk while still acknowledging the writes, as a disk lying
+	// about its write cache would do, regardless of the sync writes and sync tree options
+	DropSyncs bool
+	// ProofCorruptionRate is the probability, between 0 and 1, that the proof carried by a response is corrupted
+	ProofCorruptionRate float64
+	// Seed of the random generator choosing the delays and the corrupted responses, 0 seeds it with the current time
+	Seed int64
+}
+
+// ErrFaultInjectionUnavailable is returned when faults are configured on a build without the faultinjection tag
+var ErrFaultInjectionUnavailable = errors.New("fault injection is only available in builds with the faultinjection tag")
+
+// Enabled tells whether any fault is configured
+func (f FaultInjection) Enabled() bool {
+	return f.MaxCommitDelay > 0 || f.DropSyncs || f.ProofCorruptionRate > 0
+}
+
+// Validate checks that the configured faults are within range
+func (f FaultInjection) Validate() error {
+	if f.MaxCommitDelay < 0 {
+		return fmt.Errorf("invalid fault injection commit delay %s: must not be negative", f.MaxCommitDelay)
+	}
+	if f.ProofCorruptionRate < 0 || f.ProofCorruptionRate > 1 {
+		return fmt.Errorf("invalid fault injection proof corruption rate %v: must be between 0 and 1", f.ProofCorruptionRate)
+	}
+	return nil
+}
+
+func (f FaultInjection) String() string {
+	if !f.Enabled() {
+		return "none"
+	}
+	var faults []string
+	if f.MaxCommitDelay > 0 {
+		faults = append(faults, fmt.Sprintf("commit delays up to %s", f.MaxCommitDelay))
+	}
+	if f.DropSyncs {
+		faults = append(faults, "dropped syncs")
+	}
+	if f.ProofCorruptionRate > 0 {
+		faults = append(faults, fmt.Sprintf("%v%% corrupted proofs", f.ProofCorruptionRate*100))
+	}
+	return strings.Join(faults, ", ")
+}
+
+// setUpFaultInjection creates the injector of the configured faults, it must run before the databases are loaded
+// as dropped syncs apply to the stores as they are opened
+func (s *ImmuServer) setUpFaultInjection() (err error) {
+	if err = s.Options.FaultInjection.Validate(); err != nil {
+		return err
+	}
+	s.faults, err = newFaultInjector(s.Options.FaultInjection, s.componentLogger("fault-injection"))
+	if err != nil || s.faults == nil {
+		return err
+	}
+	s.Logger.Warningf("fault injection enabled: %s. Never run this build in production", s.Options.FaultInjection)
+	if s.Options.FaultInjection.DropSyncs {
+		s.Options.SyncWrites = false
+		s.Options.TreeSync = false
+	}
+	return nil
+}
+
+// FaultInterceptor delays the writes and corrupts the proofs of the responses as configured in the fault injection
+// options, it lets everything through unless the server is built with the faultinjection tag
+func (s *ImmuServer) FaultInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.faults == nil {
+		return handler(ctx, req)
+	}
+	return s.faults.intercept(ctx, req, info, handler)
+}
@@ -0,0 +1,40 @@
+// +build !faultinjection
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// faultInjector injects nothing in builds without the faultinjection tag
+type faultInjector struct{}
+
+func newFaultInjector(f FaultInjection, l logger.Logger) (*faultInjector, error) {
+	if f.Enabled() {
+		return nil, ErrFaultInjectionUnavailable
+	}
+	return nil, nil
+}
+
+func (fi *faultInjector) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(ctx, req)
+}
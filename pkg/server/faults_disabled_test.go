@@ -0,0 +1,33 @@
+// +build !faultinjection
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectionUnavailable(t *testing.T) {
+	s := DefaultServer()
+	s.Options = s.Options.WithFaultInjection(FaultInjection{DropSyncs: true}).WithSyncWrites(true)
+	require.Equal(t, ErrFaultInjectionUnavailable, s.setUpFaultInjection())
+	require.Nil(t, s.faults)
+	require.True(t, s.Options.SyncWrites)
+}
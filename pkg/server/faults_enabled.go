@@ -0,0 +1,121 @@
+// +build faultinjection
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"math/rand"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"google.golang.org/grpc"
+)
+
+// faultInjector injects the configured faults. It's only available in builds with the faultinjection tag.
+type faultInjector struct {
+	faults FaultInjection
+	Logger logger.Logger
+	mu     sync.Mutex
+	rnd    *rand.Rand
+}
+
+func newFaultInjector(f FaultInjection, l logger.Logger) (*faultInjector, error) {
+	if !f.Enabled() {
+		return nil, nil
+	}
+	seed := f.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &faultInjector{faults: f, Logger: l, rnd: rand.New(rand.NewSource(seed))}, nil
+}
+
+func (fi *faultInjector) intercept(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method := path.Base(info.FullMethod)
+	if fi.faults.MaxCommitDelay > 0 && usageWriteMethods[method] {
+		delay := time.Duration(fi.int63n(int64(fi.faults.MaxCommitDelay)))
+		fi.Logger.Debugf("delaying %s by %s", method, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	resp, err := handler(ctx, req)
+	if err != nil || fi.faults.ProofCorruptionRate == 0 || fi.float64() >= fi.faults.ProofCorruptionRate {
+		return resp, err
+	}
+	if corruptProof(resp) {
+		fi.Logger.Debugf("corrupted the proof of the %s response", method)
+	}
+	return resp, err
+}
+
+func (fi *faultInjector) int63n(n int64) int64 {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.rnd.Int63n(n)
+}
+
+func (fi *faultInjector) float64() float64 {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	return fi.rnd.Float64()
+}
+
+// corruptProof flips a bit of the root the proof carried by resp leads to, so that verifying it fails, and tells
+// whether resp carries a proof. The root is copied first as it may be shared with the server state.
+func corruptProof(resp interface{}) bool {
+	switch r := resp.(type) {
+	case *schema.Proof:
+		return r != nil && flipBit(&r.Root)
+	case *schema.SafeItem:
+		return r != nil && r.Proof != nil && flipBit(&r.Proof.Root)
+	case *schema.SafeStructuredItem:
+		return r != nil && r.Proof != nil && flipBit(&r.Proof.Root)
+	case *schema.SafeItemList:
+		if r == nil || len(r.Items) == 0 || r.Items[0].Proof == nil {
+			return false
+		}
+		return flipBit(&r.Items[0].Proof.Root)
+	case *schema.SafeZItemList:
+		return r != nil && r.MultiProof != nil && flipBit(&r.MultiProof.Root)
+	case *schema.TxProof:
+		return r != nil && r.Proof != nil && flipBit(&r.Proof.Root)
+	case *schema.InclusionProof:
+		return r != nil && flipBit(&r.Root)
+	case *schema.ConsistencyProof:
+		return r != nil && flipBit(&r.SecondRoot)
+	}
+	return false
+}
+
+func flipBit(b *[]byte) bool {
+	if len(*b) == 0 {
+		return false
+	}
+	c := make([]byte, len(*b))
+	copy(c, *b)
+	c[0] ^= 1
+	*b = c
+	return true
+}
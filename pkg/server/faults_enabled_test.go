@@ -0,0 +1,70 @@
+// +build faultinjection
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestFaultInjection(t *testing.T) {
+	s := DefaultServer()
+	s.Options = s.Options.WithFaultInjection(FaultInjection{DropSyncs: true}).WithSyncWrites(true).WithTreeSync(true)
+	require.NoError(t, s.setUpFaultInjection())
+	require.NotNil(t, s.faults)
+	require.False(t, s.Options.SyncWrites)
+	require.False(t, s.Options.TreeSync)
+
+	s = DefaultServer()
+	s.Options = s.Options.WithFaultInjection(FaultInjection{ProofCorruptionRate: 2})
+	require.Error(t, s.setUpFaultInjection())
+
+	root := []byte{1, 2, 3}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &schema.SafeItem{Item: &schema.Item{}, Proof: &schema.Proof{Root: root}}, nil
+	}
+	safeGet := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/SafeGet"}
+	safeSet := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/SafeSet"}
+
+	s = DefaultServer()
+	s.Options = s.Options.WithFaultInjection(FaultInjection{ProofCorruptionRate: 1, MaxCommitDelay: 50 * time.Millisecond, Seed: 1})
+	require.NoError(t, s.setUpFaultInjection())
+	resp, err := s.FaultInterceptor(context.TODO(), nil, safeGet, handler)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 2, 3}, resp.(*schema.SafeItem).Proof.Root)
+	// the root shared with the server state is left untouched
+	require.Equal(t, []byte{1, 2, 3}, root)
+
+	// writes are delayed, unless the request is cancelled meanwhile
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err = s.FaultInterceptor(ctx, nil, safeSet, handler)
+	require.Equal(t, context.Canceled, err)
+
+	require.False(t, corruptProof(&schema.Item{}))
+	require.False(t, corruptProof(&schema.SafeItemList{}))
+	require.False(t, corruptProof(&schema.SafeZItemList{}))
+	require.True(t, corruptProof(&schema.SafeZItemList{MultiProof: &schema.MultiProof{Root: []byte{1}}}))
+	require.True(t, corruptProof(&schema.ConsistencyProof{SecondRoot: []byte{1}}))
+}
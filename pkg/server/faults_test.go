@@ -0,0 +1,56 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestFaultInjectionOptions(t *testing.T) {
+	require.False(t, DefaultOptions().FaultInjection.Enabled())
+	require.NoError(t, DefaultOptions().FaultInjection.Validate())
+	require.Equal(t, "none", DefaultOptions().FaultInjection.String())
+
+	faults := FaultInjection{MaxCommitDelay: time.Second, DropSyncs: true, ProofCorruptionRate: 0.5}
+	require.True(t, faults.Enabled())
+	require.NoError(t, faults.Validate())
+	require.Equal(t, "commit delays up to 1s, dropped syncs, 50% corrupted proofs", faults.String())
+	require.Contains(t, DefaultOptions().WithFaultInjection(faults).String(), "Fault injection")
+
+	require.Error(t, FaultInjection{MaxCommitDelay: -time.Second}.Validate())
+	require.Error(t, FaultInjection{ProofCorruptionRate: 1.5}.Validate())
+	require.Error(t, FaultInjection{ProofCorruptionRate: -0.5}.Validate())
+}
+
+func TestFaultInterceptorWithoutFaults(t *testing.T) {
+	s := DefaultServer()
+	require.NoError(t, s.setUpFaultInjection())
+	require.Nil(t, s.faults)
+
+	proof := &schema.Proof{Root: []byte{1, 2, 3}}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return proof, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/SafeSet"}
+	resp, err := s.FaultInterceptor(context.TODO(), nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, resp.(*schema.Proof).Root)
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// IDEMPOTENCY_KEY_HEADER is the request metadata carrying the idempotency key of a write
+const IDEMPOTENCY_KEY_HEADER = "immudb-idempotency-key"
+
+// ErrIdempotencyKeyReused happens when an idempotency key is sent again together with a different payload
+var ErrIdempotencyKeyReused = status.New(codes.FailedPrecondition, "idempotency key already used for a different request").Err()
+
+// errIdempotentWriteFailed marks the entries whose write has not completed successfully
+var errIdempotentWriteFailed = errors.New("idempotent write failed")
+
+type idempotencyEntry struct {
+	key       string
+	digest    [sha256.Size]byte
+	index     uint64
+	expiresAt time.Time
+	// done is closed once the write of the entry has completed, successfully or not
+	done chan struct{}
+	err  error
+}
+
+// idempotencyIndex is a short-lived, in memory dedup index mapping idempotency keys to the index
+// assigned to the first request carrying them. Retried requests get the original index back
+// instead of appending a duplicate entry.
+type idempotencyIndex struct {
+	ttl     time.Duration
+	entries map[string]*idempotencyEntry
+	// queue keeps completed entries in completion order, which is also expiration order since the ttl is fixed
+	queue []*idempotencyEntry
+	now   func() time.Time
+	sync.Mutex
+}
+
+func newIdempotencyIndex(ttl time.Duration) *idempotencyIndex {
+	return &idempotencyIndex{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// run executes write unless a non expired entry for key exists, in which case the original index is returned.
+// Requests carrying the same key while its write is in flight wait for it, so that concurrent retries cannot both
+// write, and retry it if it fails. Requests carrying different keys don't wait for each other.
+// The returned bool tells if write has been executed.
+func (ii *idempotencyIndex) run(key string, digest [sha256.Size]byte, write func() (uint64, error)) (uint64, bool, error) {
+	if ii == nil || ii.ttl <= 0 {
+		index, err := write()
+		return index, err == nil, err
+	}
+
+	for {
+		ii.Lock()
+		ii.evict(ii.now())
+		e, ok := ii.entries[key]
+		if !ok {
+			break
+		}
+		ii.Unlock()
+		if e.digest != digest {
+			return 0, false, ErrIdempotencyKeyReused
+		}
+		<-e.done
+		if e.err == nil {
+			return e.index, false, nil
+		}
+	}
+
+	e := &idempotencyEntry{key: key, digest: digest, done: make(chan struct{}), err: errIdempotentWriteFailed}
+	ii.entries[key] = e
+	ii.Unlock()
+
+	// the entry is completed even if write panics, so that no request waits for it forever
+	defer func() {
+		ii.Lock()
+		defer ii.Unlock()
+		if e.err == nil {
+			e.expiresAt = ii.now().Add(ii.ttl)
+			ii.queue = append(ii.queue, e)
+		} else {
+			delete(ii.entries, key)
+		}
+		close(e.done)
+	}()
+
+	index, err := write()
+	if err != nil {
+		e.err = err
+		return 0, false, err
+	}
+	e.index, e.err = index, nil
+
+	return index, true, nil
+}
+
+func (ii *idempotencyIndex) evict(now time.Time) {
+	i := 0
+	for ; i < len(ii.queue) && !now.Before(ii.queue[i].expiresAt); i++ {
+		delete(ii.entries, ii.queue[i].key)
+	}
+	ii.queue = ii.queue[i:]
+}
+
+// idempotencyKeyFromContext returns the idempotency key sent along with the request, if any
+func idempotencyKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if keys := md.Get(IDEMPOTENCY_KEY_HEADER); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+func digestKeyValue(kv *schema.KeyValue) [sha256.Size]byte {
+	var kl [8]byte
+	binary.BigEndian.PutUint64(kl[:], uint64(len(kv.GetKey())))
+	h := sha256.New()
+	h.Write(kl[:])
+	h.Write(kv.GetKey())
+	h.Write(kv.GetValue())
+	var d [sha256.Size]byte
+	copy(d[:], h.Sum(nil))
+	return d
+}
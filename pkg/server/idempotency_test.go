@@ -0,0 +1,146 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIdempotencyIndex(t *testing.T) {
+	now := time.Now()
+	ii := newIdempotencyIndex(time.Minute)
+	ii.now = func() time.Time { return now }
+
+	writes := uint64(0)
+	write := func() (uint64, error) {
+		writes++
+		return writes, nil
+	}
+	d1 := digestKeyValue(&schema.KeyValue{Key: []byte("k"), Value: []byte("v1")})
+	d2 := digestKeyValue(&schema.KeyValue{Key: []byte("k"), Value: []byte("v2")})
+
+	index, written, err := ii.run("key", d1, write)
+	assert.NoError(t, err)
+	assert.True(t, written)
+	assert.Equal(t, uint64(1), index)
+
+	index, written, err = ii.run("key", d1, write)
+	assert.NoError(t, err)
+	assert.False(t, written)
+	assert.Equal(t, uint64(1), index)
+
+	_, _, err = ii.run("key", d2, write)
+	assert.Equal(t, ErrIdempotencyKeyReused, err)
+
+	now = now.Add(time.Minute)
+	index, written, err = ii.run("key", d1, write)
+	assert.NoError(t, err)
+	assert.True(t, written)
+	assert.Equal(t, uint64(2), index)
+	assert.Len(t, ii.queue, 1)
+
+	disabled := newIdempotencyIndex(0)
+	_, written, err = disabled.run("key", d1, write)
+	assert.NoError(t, err)
+	assert.True(t, written)
+	_, written, err = disabled.run("key", d1, write)
+	assert.NoError(t, err)
+	assert.True(t, written)
+}
+
+func TestIdempotencyIndexInFlight(t *testing.T) {
+	ii := newIdempotencyIndex(time.Minute)
+	d := digestKeyValue(&schema.KeyValue{Key: []byte("k"), Value: []byte("v")})
+
+	// a write in flight doesn't hold back the writes of other keys
+	started, release := make(chan struct{}), make(chan struct{})
+	type result struct {
+		index   uint64
+		written bool
+		err     error
+	}
+	first := make(chan result)
+	go func() {
+		index, written, err := ii.run("key1", d, func() (uint64, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		first <- result{index, written, err}
+	}()
+	<-started
+	index, written, err := ii.run("key2", d, func() (uint64, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.True(t, written)
+	assert.Equal(t, uint64(2), index)
+
+	// retries of a write in flight wait for it and get its index back
+	retried := make(chan result)
+	go func() {
+		index, written, err := ii.run("key1", d, func() (uint64, error) { return 3, nil })
+		retried <- result{index, written, err}
+	}()
+	close(release)
+	assert.Equal(t, result{1, true, nil}, <-first)
+	assert.Equal(t, result{1, false, nil}, <-retried)
+
+	// failed writes are retried
+	_, _, err = ii.run("key3", d, func() (uint64, error) { return 0, errors.New("failed") })
+	assert.Error(t, err)
+	index, written, err = ii.run("key3", d, func() (uint64, error) { return 4, nil })
+	require.NoError(t, err)
+	assert.True(t, written)
+	assert.Equal(t, uint64(4), index)
+}
+
+func TestDbIdempotentWrites(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	kv := &schema.KeyValue{Key: []byte("key"), Value: []byte("value")}
+	index, err := db.IdempotentSet("set", kv)
+	require.NoError(t, err)
+	retried, err := db.IdempotentSet("set", kv)
+	require.NoError(t, err)
+	assert.Equal(t, index.Index, retried.Index)
+
+	opts := &schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte("safe"), Value: []byte("value")}}
+	proof, err := db.IdempotentSafeSet("safeset", opts)
+	require.NoError(t, err)
+	retriedProof, err := db.IdempotentSafeSet("safeset", opts)
+	require.NoError(t, err)
+	assert.Equal(t, proof.Index, retriedProof.Index)
+	assert.Equal(t, proof.Leaf, retriedProof.Leaf)
+
+	count, err := db.Store.Count(schema.KeyPrefix{Prefix: []byte("key")})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), count.Count)
+}
+
+func TestIdempotencyKeyFromContext(t *testing.T) {
+	assert.Equal(t, "", idempotencyKeyFromContext(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IDEMPOTENCY_KEY_HEADER, "key"))
+	assert.Equal(t, "key", idempotencyKeyFromContext(ctx))
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"github.com/codenotary/immudb/pkg/store"
+	"google.golang.org/grpc"
+)
+
+// KeyInterceptor rewrites the keys of the requests and of the responses with the key interceptor of the options,
+// after authentication so that the logged in user can be read from ctx
+func (s *ImmuServer) KeyInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := store.InterceptRequest(ctx, s.Options.KeyInterceptor, req); err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = store.InterceptResponse(ctx, s.Options.KeyInterceptor, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestKeyInterceptor(t *testing.T) {
+	s := DefaultServer().WithOptions(DefaultOptions().WithKeyInterceptor(
+		store.NewPrefixKeyInterceptor(func(ctx context.Context) ([]byte, error) { return []byte("tenant/"), nil }))).(*ImmuServer)
+	info := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/Scan"}
+
+	var received *schema.ScanOptions
+	resp, err := s.KeyInterceptor(context.Background(), &schema.ScanOptions{Prefix: []byte("k")}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			received = req.(*schema.ScanOptions)
+			return &schema.ItemList{Items: []*schema.Item{
+				{Key: []byte("tenant/k1")},
+				{Key: []byte("other/k1")},
+			}}, nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, []byte("tenant/k"), received.Prefix)
+	require.Equal(t, []*schema.Item{{Key: []byte("k1")}}, resp.(*schema.ItemList).Items)
+
+	_, err = s.KeyInterceptor(context.Background(), &schema.Key{Key: []byte("k")}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &schema.Item{Key: []byte("other/k")}, nil
+		})
+	require.Equal(t, store.ErrKeyNotFound, err)
+
+	handlerErr := errors.New("some error")
+	_, err = s.KeyInterceptor(context.Background(), &schema.Key{Key: []byte("k")}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, handlerErr
+		})
+	require.Equal(t, handlerErr, err)
+}
@@ -36,6 +36,13 @@ type MetricsCollection struct {
 	UptimeCounter                prometheus.CounterFunc
 	RPCsPerClientCounters        *prometheus.CounterVec
 	LastMessageAtPerClientGauges *prometheus.GaugeVec
+	IndexCountDivergenceGauges   *prometheus.GaugeVec
+	TamperDetectionsCounters     *prometheus.CounterVec
+	// usage metrics, meant for billing, are labeled with the database, the user (only if per-user usage
+	// is enabled, empty otherwise) and, for operations, their kind (read, write or admin): their cardinality
+	// is bounded by the number of databases and users, never by request contents or client addresses
+	OperationsPerDatabaseCounters   *prometheus.CounterVec
+	WrittenBytesPerDatabaseCounters *prometheus.CounterVec
 }
 
 var metricsNamespace = "immudb"
@@ -94,6 +101,38 @@ var Metrics = MetricsCollection{
 		},
 		[]string{"ip"},
 	),
+	IndexCountDivergenceGauges: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "index_count_divergence",
+			Help:      "Difference between the number of data entries and the number of tree leaves, per database. Any value other than zero indicates a corrupted tree.",
+		},
+		[]string{"database"},
+	),
+	TamperDetectionsCounters: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "tamper_detections_total",
+			Help:      "Number of possible tamperings detected by the server own checks, per database and source (startup-check or consistency-checker).",
+		},
+		[]string{"database", "source"},
+	),
+	OperationsPerDatabaseCounters: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "number_of_operations_per_database",
+			Help:      "Number of successful operations per database, user (if per-user usage is enabled) and kind (read, write or admin).",
+		},
+		[]string{"database", "user", "kind"},
+	),
+	WrittenBytesPerDatabaseCounters: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "written_bytes_per_database",
+			Help:      "Size in bytes of the successful write requests per database and user (if per-user usage is enabled).",
+		},
+		[]string{"database", "user"},
+	),
 }
 
 func init() {
@@ -124,25 +163,58 @@ func StartMetrics(
 	recordsCounter func() float64,
 	uptimeCounter func() float64,
 ) *http.Server {
+	// without TLS nothing can fail before listening
+	server, _ := StartSecureMetrics(addr, l, recordsCounter, uptimeCounter, MetricsAuthOptions{})
+	return server
+}
+
+// StartSecureMetrics is like StartMetrics, with the endpoints protected according to auth.
+// An error is returned if the TLS certificate cannot be loaded.
+func StartSecureMetrics(
+	addr string,
+	l logger.Logger,
+	recordsCounter func() float64,
+	uptimeCounter func() float64,
+	auth MetricsAuthOptions,
+) (*http.Server, error) {
+	server, err := newMetricsServer(addr, auth)
+	if err != nil {
+		return nil, err
+	}
 	Metrics.WithRecordsCounter(recordsCounter)
 	Metrics.WithUptimeCounter(uptimeCounter)
+	go serveMetrics(server, l)
+
+	return server, nil
+}
+
+func newMetricsServer(addr string, auth MetricsAuthOptions) (*http.Server, error) {
+	tlsConfig, err := auth.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 	// expvar package adds a handler in to the default HTTP server (which has to be started explicitly),
 	// and serves up the metrics at the /debug/vars endpoint.
 	// Here we're registering both expvar and promhttp handlers in our custom server.
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	mux.Handle("/debug/vars", expvar.Handler())
-	server := &http.Server{Addr: addr, Handler: mux}
-	go func() {
-		if err := server.ListenAndServe(); err != nil {
-			if err == http.ErrServerClosed {
-				l.Debugf("Metrics http server closed")
-			} else {
-				l.Errorf("Metrics error: %s", err)
-			}
+	return &http.Server{Addr: addr, Handler: auth.handler(mux), TLSConfig: tlsConfig}, nil
+}
 
+func serveMetrics(server *http.Server, l logger.Logger) {
+	var err error
+	if server.TLSConfig != nil {
+		// the certificate is already in TLSConfig
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil {
+		if err == http.ErrServerClosed {
+			l.Debugf("Metrics http server closed")
+		} else {
+			l.Errorf("Metrics error: %s", err)
 		}
-	}()
-
-	return server
+	}
 }
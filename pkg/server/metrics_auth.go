@@ -0,0 +1,100 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"net/http"
+	"strings"
+)
+
+// MetricsAuthOptions protects the metrics and diagnostics endpoints, which are served in plain HTTP and
+// without authentication if left empty
+type MetricsAuthOptions struct {
+	// Certificate and Pkey are the paths of the PEM encoded certificate and private key the endpoints are
+	// served in HTTPS with
+	Certificate string
+	Pkey        string
+	// Username and Password, if set, are required with the HTTP basic authentication
+	Username string
+	Password string `json:"-"`
+	// BearerToken, if set, is required in the Authorization header, e.g. as Prometheus bearer_token.
+	// If basic authentication is also enabled, either of them is accepted.
+	BearerToken string `json:"-"`
+}
+
+// TLS tells if the endpoints are served in HTTPS
+func (o MetricsAuthOptions) TLS() bool {
+	return len(o.Certificate) > 0 || len(o.Pkey) > 0
+}
+
+// Authenticated tells if the endpoints require credentials
+func (o MetricsAuthOptions) Authenticated() bool {
+	return len(o.Username) > 0 || len(o.Password) > 0 || len(o.BearerToken) > 0
+}
+
+func (o MetricsAuthOptions) tlsConfig() (*tls.Config, error) {
+	if !o.TLS() {
+		return nil, nil
+	}
+	certificate, err := tls.LoadX509KeyPair(o.Certificate, o.Pkey)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}, nil
+}
+
+// authorized tells if r carries valid credentials. The comparisons take the same time whatever the credentials,
+// not to let their length or prefix be guessed.
+func (o MetricsAuthOptions) authorized(r *http.Request) bool {
+	if len(o.BearerToken) > 0 {
+		header := r.Header.Get("Authorization")
+		if strings.HasPrefix(header, "Bearer ") &&
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, "Bearer ")), []byte(o.BearerToken)) == 1 {
+			return true
+		}
+	}
+	if len(o.Username) > 0 || len(o.Password) > 0 {
+		username, password, ok := r.BasicAuth()
+		validUsername := subtle.ConstantTimeCompare([]byte(username), []byte(o.Username)) == 1
+		validPassword := subtle.ConstantTimeCompare([]byte(password), []byte(o.Password)) == 1
+		if ok && validUsername && validPassword {
+			return true
+		}
+	}
+	return false
+}
+
+// handler wraps h to reject the requests without valid credentials, if any are required
+func (o MetricsAuthOptions) handler(h http.Handler) http.Handler {
+	if !o.Authenticated() {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !o.authorized(r) {
+			if len(o.Username) > 0 || len(o.Password) > 0 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="immudb metrics"`)
+			} else {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="immudb metrics"`)
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
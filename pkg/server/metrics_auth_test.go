@@ -0,0 +1,163 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsAuthHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	status := func(auth MetricsAuthOptions, setup func(r *http.Request)) (int, string) {
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		setup(r)
+		w := httptest.NewRecorder()
+		auth.handler(ok).ServeHTTP(w, r)
+		return w.Code, w.Header().Get("WWW-Authenticate")
+	}
+	anonymous := func(r *http.Request) {}
+	basic := func(username, password string) func(r *http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth(username, password) }
+	}
+	bearer := func(token string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	code, _ := status(MetricsAuthOptions{}, anonymous)
+	require.Equal(t, http.StatusOK, code)
+
+	basicAuth := MetricsAuthOptions{Username: "prometheus", Password: "secret"}
+	code, challenge := status(basicAuth, anonymous)
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Contains(t, challenge, "Basic")
+	code, _ = status(basicAuth, basic("prometheus", "wrong"))
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = status(basicAuth, basic("prometheus", "secret"))
+	require.Equal(t, http.StatusOK, code)
+
+	bearerAuth := MetricsAuthOptions{BearerToken: "token"}
+	code, challenge = status(bearerAuth, bearer("other"))
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Contains(t, challenge, "Bearer")
+	code, _ = status(bearerAuth, basic("", "token"))
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = status(bearerAuth, bearer("token"))
+	require.Equal(t, http.StatusOK, code)
+
+	// with both, either credential is accepted
+	bothAuth := MetricsAuthOptions{Username: "prometheus", Password: "secret", BearerToken: "token"}
+	code, _ = status(bothAuth, bearer("token"))
+	require.Equal(t, http.StatusOK, code)
+	code, _ = status(bothAuth, basic("prometheus", "secret"))
+	require.Equal(t, http.StatusOK, code)
+	code, _ = status(bothAuth, basic("prometheus", "token"))
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestSecureMetricsServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics_tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certificate, pkey := writeSelfSignedCertificate(t, dir)
+
+	_, err = StartSecureMetrics("127.0.0.1:9996", &mockLogger{}, func() float64 { return 0 }, func() float64 { return 0 },
+		MetricsAuthOptions{Certificate: filepath.Join(dir, "missing.pem"), Pkey: pkey})
+	require.Error(t, err)
+
+	// the counters are registered once per process, by TestStartMetrics
+	server, err := newMetricsServer("127.0.0.1:9996", MetricsAuthOptions{Certificate: certificate, Pkey: pkey, BearerToken: "token"})
+	require.NoError(t, err)
+	go serveMetrics(server, &mockLogger{})
+	defer server.Close()
+
+	client := &http.Client{
+		Timeout:   time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	get := func(url string, token string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		if len(token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return client.Do(req)
+	}
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = get("https://127.0.0.1:9996/metrics", "token"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = get("https://127.0.0.1:9996/debug/vars", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// plain HTTP is not served
+	resp, err = get("http://127.0.0.1:9996/metrics", "token")
+	if err == nil {
+		resp.Body.Close()
+		require.NotEqual(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+// writeSelfSignedCertificate writes in dir a self-signed certificate for 127.0.0.1 and its private key
+func writeSelfSignedCertificate(t *testing.T, dir string) (certificate string, pkey string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "immudb metrics"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certificate = filepath.Join(dir, "cert.pem")
+	pkey = filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certificate, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(pkey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certificate, pkey
+}
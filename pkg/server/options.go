@@ -21,8 +21,11 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/codenotary/immudb/pkg/store"
 )
 
 const SystemdbName = "systemdb"
@@ -45,6 +48,8 @@ type Options struct {
 	NoHistograms        bool
 	Detached            bool
 	CorruptionCheck     bool
+	TamperPolicy        TamperPolicy
+	FaultInjection      FaultInjection
 	MetricsServer       bool
 	DevMode             bool
 	AdminPassword       string `json:"-"`
@@ -55,6 +60,28 @@ type Options struct {
 	usingCustomListener bool
 	maintenance         bool
 	SigningKey          string
+	StrictAppendOnly    bool
+	Sequencer           bool
+	CheckpointInterval  uint64
+	SyncWrites          bool
+	TreeSync            bool
+	ValueCompression    store.ValueCompression
+	EncryptionKey       string
+	DataKeyRotation     time.Duration
+	ReconcileInterval   time.Duration
+	Retention           map[string]store.RetentionPolicy
+	RetentionInterval   time.Duration
+	TieringDir          string
+	TieringMinAge       time.Duration
+	TieringInterval     time.Duration
+	BackupDir           string
+	Clock               clock.Clock
+	NTPServer           string
+	AlertNewTokenIP     bool
+	UsagePerUser        bool
+	RequireChecksums    bool
+	MetricsAuth         MetricsAuthOptions
+	KeyInterceptor      store.KeyInterceptor `json:"-"`
 }
 
 // DefaultOptions returns default server options
@@ -74,6 +101,7 @@ func DefaultOptions() Options {
 		NoHistograms:        false,
 		Detached:            false,
 		CorruptionCheck:     true,
+		TamperPolicy:        TamperPolicyRefuse,
 		MetricsServer:       true,
 		DevMode:             false,
 		AdminPassword:       auth.SysAdminPassword,
@@ -82,6 +110,12 @@ func DefaultOptions() Options {
 		inMemoryStore:       false,
 		usingCustomListener: false,
 		maintenance:         false,
+		StrictAppendOnly:    false,
+		Sequencer:           false,
+		ReconcileInterval:   0,
+		RetentionInterval:   time.Hour,
+		TieringMinAge:       30 * 24 * time.Hour,
+		TieringInterval:     time.Hour,
 	}
 }
 
@@ -178,6 +212,165 @@ func (o Options) WithCorruptionCheck(corruptionCheck bool) Options {
 	return o
 }
 
+// WithTamperPolicy sets what the server does once the startup check or the consistency checker detect a possible
+// tampering
+func (o Options) WithTamperPolicy(policy TamperPolicy) Options {
+	o.TamperPolicy = policy
+	return o
+}
+
+// WithFaultInjection sets the failures simulated by the server for resilience testing, only builds with the
+// faultinjection tag inject them
+func (o Options) WithFaultInjection(faults FaultInjection) Options {
+	o.FaultInjection = faults
+	return o
+}
+
+// WithClock sets the clock used to timestamp entries, users and log records, the system clock is used by default
+func (o Options) WithClock(c clock.Clock) Options {
+	o.Clock = c
+	return o
+}
+
+// WithNTPServer sets the NTP server (host:port) disciplining the server clock, when no clock is set explicitly
+func (o Options) WithNTPServer(address string) Options {
+	o.NTPServer = address
+	return o
+}
+
+// WithAlertNewTokenIP makes the server log a warning whenever a token is used from an address
+// different from the ones it was used from before, as it may have been leaked
+func (o Options) WithAlertNewTokenIP(alert bool) Options {
+	o.AlertNewTokenIP = alert
+	return o
+}
+
+// WithReconcileInterval sets the period between index count reconciliations. Zero, the default, disables them.
+// Each reconciliation reads every version of every key of every database, so it takes time proportional to the
+// total number of stored entries.
+func (o Options) WithReconcileInterval(interval time.Duration) Options {
+	o.ReconcileInterval = interval
+	return o
+}
+
+// WithRetention sets the retention policies of the databases by name, see ParseRetentionPolicies
+func (o Options) WithRetention(policies map[string]store.RetentionPolicy) Options {
+	o.Retention = policies
+	return o
+}
+
+// WithRetentionInterval sets the period between enforcements of the retention policies. Zero disables them
+func (o Options) WithRetentionInterval(interval time.Duration) Options {
+	o.RetentionInterval = interval
+	return o
+}
+
+// WithTieringDir sets the cold storage directory the old value log segments are moved to. Empty disables tiering
+func (o Options) WithTieringDir(dir string) Options {
+	o.TieringDir = dir
+	return o
+}
+
+// WithTieringMinAge sets how long after their last write the value log segments are moved to cold storage
+func (o Options) WithTieringMinAge(age time.Duration) Options {
+	o.TieringMinAge = age
+	return o
+}
+
+// WithTieringInterval sets the period between moves of the value log segments to cold storage
+func (o Options) WithTieringInterval(interval time.Duration) Options {
+	o.TieringInterval = interval
+	return o
+}
+
+// WithBackupDir sets the directory the snapshots taken by Backup are written into, it must not be inside the data
+// directory. Empty disables backups
+func (o Options) WithBackupDir(dir string) Options {
+	o.BackupDir = dir
+	return o
+}
+
+// WithStrictAppendOnly enables strict append-only mode on all databases
+func (o Options) WithStrictAppendOnly(strictAppendOnly bool) Options {
+	o.StrictAppendOnly = strictAppendOnly
+	return o
+}
+
+// WithSequencer enables the sequencer mode on all databases: every write is assigned a gap-free sequence number
+func (o Options) WithSequencer(sequencer bool) Options {
+	o.Sequencer = sequencer
+	return o
+}
+
+// WithCheckpointInterval sets the number of entries after which the Merkle tree of every database is checkpointed,
+// bounding the entries replayed at startup after a crash. Zero selects the default interval of the store.
+func (o Options) WithCheckpointInterval(entries uint64) Options {
+	o.CheckpointInterval = entries
+	return o
+}
+
+// WithSyncWrites makes every write to the databases wait for the data to be synced to disk
+func (o Options) WithSyncWrites(syncWrites bool) Options {
+	o.SyncWrites = syncWrites
+	return o
+}
+
+// WithTreeSync makes every checkpoint of the Merkle trees be synced to disk, see WithCheckpointInterval.
+// Since the tree is rebuilt from the data at startup, the tree can be left unsynced even if writes are synced:
+// a crash then costs the replay of the entries committed after the last durable checkpoint, not data.
+func (o Options) WithTreeSync(treeSync bool) Options {
+	o.TreeSync = treeSync
+	return o
+}
+
+// WithValueCompression sets the algorithm compressing the values of every database before they are persisted,
+// see store.Options.WithValueCompression. Changing it never makes the values written before unreadable.
+func (o Options) WithValueCompression(c store.ValueCompression) Options {
+	o.ValueCompression = c
+	return o
+}
+
+// WithEncryptionKey sets the file of the key encrypting the data of every database at rest, see
+// store.Options.WithEncryptionKey. The databases written with a key can only be opened with it, while
+// store.RotateEncryptionKey replaces it offline.
+func (o Options) WithEncryptionKey(keyFile string) Options {
+	o.EncryptionKey = keyFile
+	return o
+}
+
+// WithDataKeyRotation sets how often the data keys of the encrypted databases are rotated, see
+// store.Options.WithDataKeyRotation
+func (o Options) WithDataKeyRotation(d time.Duration) Options {
+	o.DataKeyRotation = d
+	return o
+}
+
+// WithUsagePerUser sets if the usage of the databases is also recorded per user, by default only per database
+func (o Options) WithUsagePerUser(perUser bool) Options {
+	o.UsagePerUser = perUser
+	return o
+}
+
+// WithRequireChecksums makes the server refuse the requests without checksum, see ChecksumInterceptor.
+// It protects the payloads in transit between a proxy terminating TLS and the server from silent corruption.
+func (o Options) WithRequireChecksums(require bool) Options {
+	o.RequireChecksums = require
+	return o
+}
+
+// WithMetricsAuth sets the TLS certificate and the credentials protecting the metrics and diagnostics endpoints
+func (o Options) WithMetricsAuth(metricsAuth MetricsAuthOptions) Options {
+	o.MetricsAuth = metricsAuth
+	return o
+}
+
+// WithKeyInterceptor sets the interceptor rewriting the keys of every request and response of the key-value API,
+// e.g. to confine every tenant to its own key prefix
+func (o Options) WithKeyInterceptor(interceptor store.KeyInterceptor) Options {
+	o.KeyInterceptor = interceptor
+	return o
+}
+
 // Bind returns bind address
 func (o Options) Bind() string {
 	return o.Address + ":" + strconv.Itoa(o.Port)
@@ -193,12 +386,14 @@ func (o Options) String() string {
 	rightPad := func(k string, v interface{}) string {
 		return fmt.Sprintf("%-17s: %v", k, v)
 	}
-	opts := make([]string, 0, 17)
+	opts := make([]string, 0, 18)
 	opts = append(opts, "================ Config ================")
 	opts = append(opts, rightPad("Data dir", o.Dir))
 	opts = append(opts, rightPad("Address", fmt.Sprintf("%s:%d", o.Address, o.Port)))
 	if o.MetricsServer {
 		opts = append(opts, rightPad("Metrics address", fmt.Sprintf("%s:%d/metrics", o.Address, o.MetricsPort)))
+		opts = append(opts, rightPad("Metrics TLS", o.MetricsAuth.TLS()))
+		opts = append(opts, rightPad("Metrics auth", o.MetricsAuth.Authenticated()))
 	}
 	if o.Config != "" {
 		opts = append(opts, rightPad("Config file", o.Config))
@@ -215,6 +410,37 @@ func (o Options) String() string {
 	opts = append(opts, rightPad("Dev mode", o.DevMode))
 	opts = append(opts, rightPad("Default database", o.defaultDbName))
 	opts = append(opts, rightPad("Maintenance mode", o.maintenance))
+	opts = append(opts, rightPad("Append-only mode", o.StrictAppendOnly))
+	opts = append(opts, rightPad("Sequencer mode", o.Sequencer))
+	opts = append(opts, rightPad("Tamper policy", o.TamperPolicy))
+	if o.FaultInjection.Enabled() {
+		opts = append(opts, rightPad("Fault injection", o.FaultInjection))
+	}
+	if o.CheckpointInterval > 0 {
+		opts = append(opts, rightPad("Tree checkpoint", fmt.Sprintf("every %d entries", o.CheckpointInterval)))
+	}
+	opts = append(opts, rightPad("Sync writes", o.SyncWrites))
+	opts = append(opts, rightPad("Sync tree", o.TreeSync))
+	opts = append(opts, rightPad("Value compression", o.ValueCompression))
+	opts = append(opts, rightPad("Encryption", o.EncryptionKey != ""))
+	if o.ReconcileInterval > 0 {
+		opts = append(opts, rightPad("Reconcile every", o.ReconcileInterval))
+	}
+	if len(o.Retention) > 0 {
+		opts = append(opts, rightPad("Retention every", o.RetentionInterval))
+	}
+	if o.TieringDir != "" {
+		opts = append(opts, rightPad("Cold storage", o.TieringDir))
+	}
+	if o.BackupDir != "" {
+		opts = append(opts, rightPad("Backup dir", o.BackupDir))
+	}
+	if o.NTPServer != "" {
+		opts = append(opts, rightPad("NTP server", o.NTPServer))
+	}
+	opts = append(opts, rightPad("Token IP alerts", o.AlertNewTokenIP))
+	opts = append(opts, rightPad("Per-user usage", o.UsagePerUser))
+	opts = append(opts, rightPad("Require checksums", o.RequireChecksums))
 	opts = append(opts, "----------------------------------------")
 	opts = append(opts, "Superadmin default credentials")
 	opts = append(opts, rightPad("   Username", auth.SysAdminUsername))
@@ -241,12 +467,12 @@ func (o Options) WithAdminPassword(adminPassword string) Options {
 	return o
 }
 
-//GetSystemAdminDbName returns the System database name
+// GetSystemAdminDbName returns the System database name
 func (o Options) GetSystemAdminDbName() string {
 	return o.systemAdminDbName
 }
 
-//GetDefaultDbName returns the default database name
+// GetDefaultDbName returns the default database name
 func (o Options) GetDefaultDbName() string {
 	return o.defaultDbName
 }
@@ -257,7 +483,7 @@ func (o Options) WithInMemoryStore(inmemory bool) Options {
 	return o
 }
 
-//GetInMemoryStore returns if we use in memory database without persistence , used for tests
+// GetInMemoryStore returns if we use in memory database without persistence , used for tests
 func (o Options) GetInMemoryStore() bool {
 	return o.inMemoryStore
 }
@@ -18,8 +18,10 @@ package server
 
 import (
 	"testing"
+	"time"
 
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/clock"
 )
 
 func TestOptions(t *testing.T) {
@@ -42,7 +44,15 @@ func TestOptions(t *testing.T) {
 		op.MetricsPort != 9497 ||
 		op.Config != "configs/immudb.toml" ||
 		op.Pidfile != "" ||
-		op.Logfile != "" {
+		op.Logfile != "" ||
+		op.StrictAppendOnly != false ||
+		op.Sequencer != false ||
+		op.CheckpointInterval != 0 ||
+		op.SyncWrites != false ||
+		op.TreeSync != false ||
+		op.ReconcileInterval != 0 ||
+		op.Clock != nil ||
+		op.NTPServer != "" {
 		t.Errorf("database default options mismatch")
 	}
 }
@@ -51,7 +61,9 @@ func TestSetOptions(t *testing.T) {
 		WithAddress("localhost").WithPort(2048).
 		WithPidfile("immu.pid").WithMTLs(true).WithAuth(false).
 		WithDetached(true).WithNoHistograms(true).WithMetricsServer(false).
-		WithDevMode(true).WithLogfile("logfile").WithAdminPassword("admin")
+		WithDevMode(true).WithLogfile("logfile").WithAdminPassword("admin").
+		WithStrictAppendOnly(true).WithSequencer(true).WithCheckpointInterval(1000).WithSyncWrites(true).WithTreeSync(true).WithReconcileInterval(time.Second).
+		WithClock(clock.System()).WithNTPServer("localhost:123")
 	if op.GetAuth() != false ||
 		op.Dir != "immudb_dir" ||
 		op.Network != "udp" ||
@@ -67,6 +79,14 @@ func TestSetOptions(t *testing.T) {
 		op.DevMode != true ||
 		op.Logfile != "logfile" ||
 		op.AdminPassword != "admin" ||
+		op.StrictAppendOnly != true ||
+		op.Sequencer != true ||
+		op.CheckpointInterval != 1000 ||
+		op.SyncWrites != true ||
+		op.TreeSync != true ||
+		op.ReconcileInterval != time.Second ||
+		op.Clock != clock.System() ||
+		op.NTPServer != "localhost:123" ||
 		op.Bind() != "localhost:2048" {
 		t.Errorf("database default options mismatch")
 	}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"sync"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Plugin is an extension compiled into the server which takes part in every write applied to a database.
+// Plugins are registered by RegisterPlugin, typically from the init function of the package implementing them.
+// Values pruned by the retention policies are not reported as writes of their own, but by the RetentionEvent
+// listing them, which is committed through the plugins before they are pruned.
+type Plugin interface {
+	// Name identifies the plugin, it must be unique among the registered ones
+	Name() string
+	// BeforeCommit is called before ops are written into db. Returning an error rejects the write:
+	// gRPC status errors are returned to the client as they are, other errors as FailedPrecondition.
+	BeforeCommit(db string, ops *schema.Ops) error
+	// AfterCommit is called once ops have been committed into db, index being the index of the last written entry
+	AfterCommit(db string, ops *schema.Ops, index *schema.Index)
+}
+
+var plugins struct {
+	list []Plugin
+	sync.RWMutex
+}
+
+// RegisterPlugin makes the plugin available to every database opened afterwards.
+// It panics if p is nil or if a plugin with the same name has already been registered.
+func RegisterPlugin(p Plugin) {
+	if p == nil {
+		panic("server: RegisterPlugin plugin is nil")
+	}
+	plugins.Lock()
+	defer plugins.Unlock()
+	for _, registered := range plugins.list {
+		if registered.Name() == p.Name() {
+			panic("server: RegisterPlugin called twice for plugin " + p.Name())
+		}
+	}
+	plugins.list = append(plugins.list, p)
+}
+
+func registeredPlugins() []Plugin {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	return append([]Plugin(nil), plugins.list...)
+}
+
+// hooked runs write between the BeforeCommit and AfterCommit hooks of the database plugins.
+// ops is built lazily, so that writes are not slowed down when no plugin is registered.
+func (d *Db) hooked(ops func() *schema.Ops, write func() (uint64, error)) error {
+	if len(d.plugins) == 0 {
+		_, err := write()
+		return err
+	}
+
+	dbName := d.options.GetDbName()
+	o := ops()
+	for _, p := range d.plugins {
+		if err := p.BeforeCommit(dbName, o); err != nil {
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
+			return status.Errorf(codes.FailedPrecondition, "write rejected by plugin %s: %v", p.Name(), err)
+		}
+	}
+
+	index, err := write()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range d.plugins {
+		p.AfterCommit(dbName, o, &schema.Index{Index: index})
+	}
+	return nil
+}
+
+func kvOps(kvs ...*schema.KeyValue) func() *schema.Ops {
+	return func() *schema.Ops {
+		ops := &schema.Ops{Operations: make([]*schema.Op, len(kvs))}
+		for i, kv := range kvs {
+			ops.Operations[i] = &schema.Op{Operation: &schema.Op_KVs{KVs: kv}}
+		}
+		return ops
+	}
+}
+
+func referenceOps(refOpts *schema.ReferenceOptions) func() *schema.Ops {
+	return func() *schema.Ops {
+		return &schema.Ops{Operations: []*schema.Op{{Operation: &schema.Op_ROpts{ROpts: refOpts}}}}
+	}
+}
+
+func zAddOps(zOpts *schema.ZAddOptions) func() *schema.Ops {
+	return func() *schema.Ops {
+		return &schema.Ops{Operations: []*schema.Op{{Operation: &schema.Op_ZOpts{ZOpts: zOpts}}}}
+	}
+}
+
+func deleteOps(key *schema.Key) func() *schema.Ops {
+	return func() *schema.Ops {
+		return &schema.Ops{Operations: []*schema.Op{{Operation: &schema.Op_Delete{Delete: key}}}}
+	}
+}
+
+func unreferenceOps(key *schema.Key) func() *schema.Ops {
+	return func() *schema.Ops {
+		return &schema.Ops{Operations: []*schema.Op{{Operation: &schema.Op_Unreference{Unreference: key}}}}
+	}
+}
+
+func freezeOps(prefix *schema.KeyPrefix) func() *schema.Ops {
+	return func() *schema.Ops {
+		return &schema.Ops{Operations: []*schema.Op{{Operation: &schema.Op_Freeze{Freeze: prefix}}}}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type recordingPlugin struct {
+	name    string
+	reject  error
+	before  []*schema.Ops
+	after   []*schema.Ops
+	indexes []uint64
+}
+
+func (p *recordingPlugin) Name() string {
+	return p.name
+}
+
+func (p *recordingPlugin) BeforeCommit(db string, ops *schema.Ops) error {
+	p.before = append(p.before, ops)
+	return p.reject
+}
+
+func (p *recordingPlugin) AfterCommit(db string, ops *schema.Ops, index *schema.Index) {
+	p.after = append(p.after, ops)
+	p.indexes = append(p.indexes, index.Index)
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	p := &recordingPlugin{name: "test-register-plugin"}
+	RegisterPlugin(p)
+	defer func() {
+		plugins.Lock()
+		plugins.list = plugins.list[:len(plugins.list)-1]
+		plugins.Unlock()
+	}()
+
+	assert.Contains(t, registeredPlugins(), p)
+	assert.Panics(t, func() { RegisterPlugin(&recordingPlugin{name: "test-register-plugin"}) })
+	assert.Panics(t, func() { RegisterPlugin(nil) })
+
+	db, closer := makeDb()
+	defer closer()
+	assert.Contains(t, db.plugins, p)
+}
+
+func TestPluginHooks(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	p := &recordingPlugin{name: "recorder"}
+	db.plugins = []Plugin{p}
+
+	index, err := db.Set(&schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	require.Len(t, p.after, 1)
+	assert.Equal(t, []byte(`key`), p.after[0].Operations[0].GetKVs().Key)
+	assert.Equal(t, index.Index, p.indexes[0])
+
+	index, err = db.SetBatch(&schema.KVList{KVs: []*schema.KeyValue{
+		{Key: []byte(`key1`), Value: []byte(`value1`)},
+		{Key: []byte(`key2`), Value: []byte(`value2`)},
+	}})
+	require.NoError(t, err)
+	assert.Len(t, p.after[1].Operations, 2)
+	assert.Equal(t, index.Index, p.indexes[1])
+
+	_, err = db.ZAdd(&schema.ZAddOptions{Set: []byte(`set`), Score: &schema.Score{Score: 1}, Key: []byte(`key`)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`set`), p.after[2].Operations[0].GetZOpts().Set)
+
+	_, err = db.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`key`)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`ref`), p.after[3].Operations[0].GetROpts().Reference)
+
+	proof, err := db.SafeSet(&schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`safe`), Value: []byte(`value`)}})
+	require.NoError(t, err)
+	assert.Equal(t, proof.Index, p.indexes[4])
+	assert.Len(t, p.before, 5)
+
+	p.reject = errors.New("read only")
+	_, err = db.Set(&schema.KeyValue{Key: []byte(`rejected`), Value: []byte(`value`)})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = db.Get(&schema.Key{Key: []byte(`rejected`)})
+	assert.Error(t, err)
+	assert.Len(t, p.after, 5)
+
+	p.reject = status.Error(codes.PermissionDenied, "denied")
+	_, err = db.Set(&schema.KeyValue{Key: []byte(`rejected`), Value: []byte(`value`)})
+	assert.Equal(t, p.reject, err)
+
+	p.reject = nil
+	_, err = db.Set(&schema.KeyValue{Key: []byte{}, Value: []byte(`value`)})
+	assert.Error(t, err)
+	assert.Len(t, p.after, 5)
+
+	index, err = db.Delete(&schema.Key{Key: []byte(`key1`)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`key1`), p.after[5].Operations[0].GetDelete().Key)
+	assert.Equal(t, index.Index, p.indexes[5])
+
+	index, err = db.Unreference(&schema.Key{Key: []byte(`ref`)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`ref`), p.after[6].Operations[0].GetUnreference().Key)
+	assert.Equal(t, index.Index, p.indexes[6])
+
+	index, err = db.FreezePrefix(&schema.KeyPrefix{Prefix: []byte(`key`)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`key`), p.after[7].Operations[0].GetFreeze().Prefix)
+	assert.Equal(t, index.Index, p.indexes[7])
+}
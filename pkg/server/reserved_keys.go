@@ -0,0 +1,116 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxReportedCollisions is the number of colliding keys logged by checkReservedKeyCollisions
+const maxReportedCollisions = 10
+
+// checkReserved rejects a key, reference or set written by a user into a namespace reserved by the store.
+// The InvalidArgument error names the offending field in a BadRequest detail, so clients can tell it apart
+// from other invalid requests.
+func checkReserved(field string, key []byte) error {
+	namespace, reserved := store.ReservedNamespace(key)
+	if !reserved {
+		return nil
+	}
+	description := fmt.Sprintf("%q is in the reserved %s namespace", key, namespace)
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid %s: %s", field, description))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}},
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+func checkKeyValues(field string, kvs ...*schema.KeyValue) error {
+	for i, kv := range kvs {
+		f := field
+		if len(kvs) > 1 {
+			f = fmt.Sprintf("%s[%d]", field, i)
+		}
+		if f != "" {
+			f += "."
+		}
+		if err := checkReserved(f+"key", kv.GetKey()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkReferenceOptions checks the reference and the referenced key, unless the key is resolved by index
+func checkReferenceOptions(field string, refOpts *schema.ReferenceOptions) error {
+	if err := checkReserved(field+"reference", refOpts.GetReference()); err != nil {
+		return err
+	}
+	if refOpts.GetIndex() != nil {
+		return nil
+	}
+	return checkReserved(field+"key", refOpts.GetKey())
+}
+
+// checkZAddOptions checks the set and the added key, unless the key is resolved by index
+func checkZAddOptions(field string, zOpts *schema.ZAddOptions) error {
+	if err := checkReserved(field+"set", zOpts.GetSet()); err != nil {
+		return err
+	}
+	if zOpts.GetIndex() != nil {
+		return nil
+	}
+	return checkReserved(field+"key", zOpts.GetKey())
+}
+
+func checkOps(ops *schema.Ops) error {
+	for i, op := range ops.GetOperations() {
+		field := fmt.Sprintf("Operations[%d].", i)
+		var err error
+		switch operation := op.Operation.(type) {
+		case *schema.Op_KVs:
+			err = checkKeyValues(field+"KVs", operation.KVs)
+		case *schema.Op_ROpts:
+			err = checkReferenceOptions(field+"ROpts.", operation.ROpts)
+		case *schema.Op_ZOpts:
+			err = checkZAddOptions(field+"ZOpts.", operation.ZOpts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkReservedKeyCollisions warns about the keys written by users into the reserved namespaces
+// before they were enforced: they should be rewritten under other keys, as they may corrupt the sorted sets
+func (d *Db) checkReservedKeyCollisions() {
+	count, keys := d.Store.ReservedKeyCollisions(maxReportedCollisions)
+	if count == 0 {
+		return
+	}
+	d.Logger.Warningf("database %s holds %d user key(s) in namespaces reserved by the store, e.g. %q: "+
+		"they should be written again under other keys", d.options.GetDbName(), count, keys)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// requireReserved checks that err rejects field for falling into a reserved namespace
+func requireReserved(t *testing.T, err error, field string) {
+	st, ok := status.FromError(err)
+	require.True(t, ok, "%v", err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Equal(t, field, badRequest.FieldViolations[0].Field)
+}
+
+func TestDbReservedKeys(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	treeKey := []byte{0, 1}
+	setKey := []byte("_~|IMMU|~_set")
+	metadataKey := []byte("IMMUDB.METADATA.custom")
+
+	_, err := db.Set(&schema.KeyValue{Key: treeKey, Value: []byte("v")})
+	requireReserved(t, err, "key")
+	require.Contains(t, err.Error(), "reserved tree namespace")
+
+	_, err = db.SafeSet(&schema.SafeSetOptions{Kv: &schema.KeyValue{Key: setKey, Value: []byte("v")}})
+	requireReserved(t, err, "kv.key")
+	require.Contains(t, err.Error(), "reserved sorted set namespace")
+
+	_, err = db.SetBatch(&schema.KVList{KVs: []*schema.KeyValue{
+		{Key: []byte("k"), Value: []byte("v")},
+		{Key: metadataKey, Value: []byte("v")},
+	}})
+	requireReserved(t, err, "KVs[1].key")
+	require.Contains(t, err.Error(), "reserved metadata namespace")
+
+	_, err = db.ExecAllOps(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte("k"), Value: []byte("v")}}},
+		{Operation: &schema.Op_ZOpts{ZOpts: &schema.ZAddOptions{Set: setKey, Key: []byte("k"), Score: &schema.Score{}}}},
+	}})
+	requireReserved(t, err, "Operations[1].ZOpts.set")
+
+	_, err = db.Reference(&schema.ReferenceOptions{Reference: metadataKey, Key: []byte("k")})
+	requireReserved(t, err, "reference")
+
+	_, err = db.CompareAndReference(&schema.CompareAndReferenceOptions{
+		Kv:        &schema.KeyValue{Key: []byte("k"), Value: []byte("v")},
+		Reference: treeKey,
+	})
+	requireReserved(t, err, "reference")
+
+	_, err = db.SafeReference(&schema.SafeReferenceOptions{Ro: &schema.ReferenceOptions{Reference: []byte("r"), Key: setKey}})
+	requireReserved(t, err, "ro.key")
+
+	_, err = db.ZAdd(&schema.ZAddOptions{Set: []byte("s"), Key: metadataKey, Score: &schema.Score{}})
+	requireReserved(t, err, "key")
+
+	_, err = db.SafeZAdd(&schema.SafeZAddOptions{Zopts: &schema.ZAddOptions{Set: treeKey, Key: []byte("k"), Score: &schema.Score{}}})
+	requireReserved(t, err, "zopts.set")
+}
+
+func TestOpenDbReservedKeyCollisions(t *testing.T) {
+	options := DefaultOption().WithDbName("reserved").WithDbRootPath("data_reserved_collisions")
+	defer os.RemoveAll(options.GetDbRootPath())
+
+	db, err := NewDb(options, logger.NewSimpleLogger("immudb ", os.Stderr))
+	require.NoError(t, err)
+	// written bypassing the checks of the server, as older versions did
+	_, err = db.Store.Set(schema.KeyValue{Key: []byte("IMMUDB.METADATA.custom"), Value: []byte("v")})
+	require.NoError(t, err)
+	require.NoError(t, db.Store.Close())
+
+	var out bytes.Buffer
+	db, err = OpenDb(options, logger.NewSimpleLogger("immudb ", &out))
+	require.NoError(t, err)
+	defer db.Store.Close()
+	require.Contains(t, out.String(), "holds 1 user key(s) in namespaces reserved by the store")
+	require.Contains(t, out.String(), "IMMUDB.METADATA.custom")
+}
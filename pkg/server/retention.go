@@ -0,0 +1,239 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/codenotary/immudb/pkg/store"
+)
+
+// maxPrunedPerEvent bounds the entries listed by a retention event, larger enforcements write more events
+const maxPrunedPerEvent = 1000
+
+// AllDatabases is the name matching every database without a retention policy of its own
+const AllDatabases = "*"
+
+// RetentionEvent is the record of an enforcement of the retention policy of a database, written to the database itself
+// under store.RetentionEventPrefix before the listed entries are pruned, so that the deletions are as verifiable as any
+// other entry. The event is written through the database plugins, which learn of the pruned entries from it: a plugin
+// rejecting the event postpones the pruning. When the server has a signing key the event is signed, see
+// VerifyRetentionEvent.
+type RetentionEvent struct {
+	Database  string                `json:"database"`
+	Time      time.Time             `json:"time"`
+	Policy    store.RetentionPolicy `json:"policy"`
+	Pruned    []store.PrunedEntry   `json:"pruned"`
+	Signature []byte                `json:"signature,omitempty"`
+	PublicKey []byte                `json:"public_key,omitempty"`
+}
+
+// signedPayload returns the encoding of the event signed by the server, that is the event without its signature
+func (e RetentionEvent) signedPayload() ([]byte, error) {
+	e.Signature, e.PublicKey = nil, nil
+	return json.Marshal(e)
+}
+
+// VerifyRetentionEvent tells if the event has been signed by the owner of publicKey, or by the server which wrote it
+// if publicKey is nil
+func VerifyRetentionEvent(event *RetentionEvent, publicKey []byte) (bool, error) {
+	if len(event.Signature) == 0 {
+		return false, nil
+	}
+	if publicKey == nil {
+		publicKey = event.PublicKey
+	}
+	payload, err := event.signedPayload()
+	if err != nil {
+		return false, err
+	}
+	return signer.Verify(payload, event.Signature, publicKey)
+}
+
+// ParseRetentionPolicies parses the retention policies of the databases from a list of policies separated by
+// semicolons, e.g. "defaultdb:max-age=720h,max-revisions=10;*:max-revisions=100", AllDatabases naming the policy of
+// the databases without one
+func ParseRetentionPolicies(spec string) (map[string]store.RetentionPolicy, error) {
+	policies := make(map[string]store.RetentionPolicy)
+	for _, p := range strings.Split(spec, ";") {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		i := strings.Index(p, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid retention policy %q: must be <database>:<limit>=<value>[,<limit>=<value>]", p)
+		}
+		db := strings.TrimSpace(p[:i])
+		if _, ok := policies[db]; ok || db == "" {
+			return nil, fmt.Errorf("invalid retention policy %q: missing or duplicate database", p)
+		}
+		var policy store.RetentionPolicy
+		for _, limit := range strings.Split(p[i+1:], ",") {
+			kv := strings.SplitN(strings.TrimSpace(limit), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid retention limit %q of database %s", limit, db)
+			}
+			var err error
+			switch kv[0] {
+			case store.PrunedByAge:
+				if policy.MaxAge, err = time.ParseDuration(kv[1]); err == nil && policy.MaxAge <= 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			case store.PrunedByRevisions:
+				if policy.MaxRevisions, err = strconv.ParseUint(kv[1], 10, 64); err == nil && policy.MaxRevisions == 0 {
+					err = fmt.Errorf("must be positive")
+				}
+			default:
+				err = fmt.Errorf("must be %s or %s", store.PrunedByAge, store.PrunedByRevisions)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid retention limit %q of database %s: %v", limit, db, err)
+			}
+		}
+		policies[db] = policy
+	}
+	return policies, nil
+}
+
+// retentionEnforcer periodically prunes the values the retention policies of the databases don't retain anymore,
+// recording what has been pruned, and why, in a retention event written to the database
+type retentionEnforcer struct {
+	dbList   DatabaseList
+	Logger   logger.Logger
+	interval time.Duration
+	policies map[string]store.RetentionPolicy
+	signer   signer.Signer
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newRetentionEnforcer(d DatabaseList, l logger.Logger, interval time.Duration, policies map[string]store.RetentionPolicy, s signer.Signer) *retentionEnforcer {
+	return &retentionEnforcer{
+		dbList:   d,
+		Logger:   l,
+		interval: interval,
+		policies: policies,
+		signer:   s,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start runs the enforcement loop in a new goroutine
+func (r *retentionEnforcer) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.quit:
+				return
+			case <-ticker.C:
+				r.enforce()
+			}
+		}
+	}()
+}
+
+// Stop terminates the enforcement loop and waits for the current iteration, if any, to complete
+func (r *retentionEnforcer) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *retentionEnforcer) enforce() {
+	for i := 0; i < r.dbList.Length(); i++ {
+		db := r.dbList.GetByIndex(int64(i))
+		policy, ok := r.policies[db.options.GetDbName()]
+		if !ok {
+			policy = r.policies[AllDatabases]
+		}
+		if !policy.Enabled() || db.options.GetDbName() == SystemdbName {
+			continue
+		}
+		if err := r.enforceOn(db, policy); err != nil {
+			r.Logger.Errorf("unable to enforce the retention policy of database %s: %v", db.options.GetDbName(), err)
+		}
+	}
+}
+
+// enforceOn prunes the values of db its policy doesn't retain, writing an event every maxPrunedPerEvent entries. The
+// event is written before the values are pruned, so that no value is ever gone without a record of it.
+func (r *retentionEnforcer) enforceOn(db *Db, policy store.RetentionPolicy) error {
+	c := db.options.GetClock()
+	if c == nil {
+		c = clock.System()
+	}
+	for {
+		candidates, err := db.Store.RetentionCandidates(policy, maxPrunedPerEvent)
+		if err != nil || len(candidates) == 0 {
+			return err
+		}
+		event := &RetentionEvent{
+			Database: db.options.GetDbName(),
+			Time:     c.Now().UTC(),
+			Policy:   policy,
+			Pruned:   candidates,
+		}
+		if err = r.writeEvent(db, event); err != nil {
+			return err
+		}
+		pruned, err := db.Store.Prune(candidates)
+		if len(pruned) < len(candidates) {
+			r.Logger.Warningf("pruned %d of the %d entries of database %s listed by the retention event", len(pruned), len(candidates), db.options.GetDbName())
+		} else {
+			r.Logger.Infof("pruned %d entries of database %s", len(pruned), db.options.GetDbName())
+		}
+		if err != nil || len(candidates) < maxPrunedPerEvent {
+			return err
+		}
+	}
+}
+
+func (r *retentionEnforcer) writeEvent(db *Db, event *RetentionEvent) error {
+	if r.signer != nil {
+		payload, err := event.signedPayload()
+		if err != nil {
+			return err
+		}
+		if event.Signature, event.PublicKey, err = r.signer.Sign(payload); err != nil {
+			return err
+		}
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	kv := &schema.KeyValue{Key: []byte(fmt.Sprintf("%s%020d", store.RetentionEventPrefix, event.Time.UnixNano())), Value: value}
+	return db.hooked(kvOps(kv), func() (uint64, error) {
+		index, err := db.Store.Set(*kv)
+		if err != nil {
+			return 0, err
+		}
+		return index.Index, nil
+	})
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/codenotary/immudb/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetentionPolicies(t *testing.T) {
+	policies, err := ParseRetentionPolicies("")
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	policies, err = ParseRetentionPolicies("defaultdb:max-age=720h,max-revisions=10; *:max-revisions=100;")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]store.RetentionPolicy{
+		"defaultdb":  {MaxAge: 720 * time.Hour, MaxRevisions: 10},
+		AllDatabases: {MaxRevisions: 100},
+	}, policies)
+
+	for _, spec := range []string{
+		"defaultdb",
+		":max-revisions=1",
+		"db:max-revisions=1;db:max-age=1h",
+		"db:max-revisions",
+		"db:max-revisions=0",
+		"db:max-revisions=-1",
+		"db:max-age=0s",
+		"db:max-age=forever",
+		"db:max-size=1",
+	} {
+		_, err = ParseRetentionPolicies(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRetentionEnforcer(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	var indexes []*schema.Index
+	for _, v := range []string{"v1", "v2", "v3"} {
+		index, err := db.Set(&schema.KeyValue{Key: []byte("key"), Value: []byte(v)})
+		require.NoError(t, err)
+		indexes = append(indexes, index)
+	}
+	dbList := NewDatabaseList()
+	dbList.Append(db)
+
+	s, err := signer.NewSigner("./../../test/signer/ec3.key")
+	require.NoError(t, err)
+	policy := store.RetentionPolicy{MaxRevisions: 1}
+	r := newRetentionEnforcer(dbList, &mockLogger{}, time.Millisecond, map[string]store.RetentionPolicy{AllDatabases: policy}, s)
+	r.Start()
+	var events *schema.ItemList
+	assert.Eventually(t, func() bool {
+		events, err = db.Store.Scan(schema.ScanOptions{Prefix: []byte(store.RetentionEventPrefix)})
+		return err == nil && len(events.Items) > 0
+	}, time.Second, time.Millisecond)
+	r.Stop()
+
+	require.Len(t, events.Items, 1)
+	var event RetentionEvent
+	require.NoError(t, json.Unmarshal(events.Items[0].Value, &event))
+	assert.Equal(t, db.options.GetDbName(), event.Database)
+	assert.Equal(t, policy, event.Policy)
+	assert.Equal(t, []store.PrunedEntry{
+		{Key: []byte("key"), Index: indexes[1].Index, Reason: store.PrunedByRevisions},
+		{Key: []byte("key"), Index: indexes[0].Index, Reason: store.PrunedByRevisions},
+	}, event.Pruned)
+
+	verified, err := VerifyRetentionEvent(&event, nil)
+	require.NoError(t, err)
+	assert.True(t, verified)
+	event.Pruned = event.Pruned[1:]
+	verified, err = VerifyRetentionEvent(&event, nil)
+	require.NoError(t, err)
+	assert.False(t, verified)
+
+	_, err = db.ByIndex(indexes[0])
+	assert.Equal(t, store.ErrEntryPruned, err)
+	item, err := db.Get(&schema.Key{Key: []byte("key")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v3"), item.Value)
+
+	// events can't be forged
+	_, err = db.Set(&schema.KeyValue{Key: []byte(store.RetentionEventPrefix + "1"), Value: []byte("{}")})
+	assert.Error(t, err)
+}
+
+func TestRetentionEnforcerPlugins(t *testing.T) {
+	db, closer := makeDb()
+	defer closer()
+
+	var indexes []*schema.Index
+	for _, v := range []string{"v1", "v2"} {
+		index, err := db.Set(&schema.KeyValue{Key: []byte("key"), Value: []byte(v)})
+		require.NoError(t, err)
+		indexes = append(indexes, index)
+	}
+	p := &recordingPlugin{name: "recorder", reject: errors.New("read only")}
+	db.plugins = []Plugin{p}
+
+	policy := store.RetentionPolicy{MaxRevisions: 1}
+	r := newRetentionEnforcer(NewDatabaseList(), &mockLogger{}, time.Millisecond, nil, nil)
+
+	// the values are not pruned as long as their event is rejected
+	assert.Error(t, r.enforceOn(db, policy))
+	assert.Empty(t, p.after)
+	_, err := db.ByIndex(indexes[0])
+	require.NoError(t, err)
+
+	p.reject = nil
+	require.NoError(t, r.enforceOn(db, policy))
+	require.Len(t, p.after, 1)
+	kv := p.after[0].Operations[0].GetKVs()
+	assert.True(t, bytes.HasPrefix(kv.Key, []byte(store.RetentionEventPrefix)))
+	var event RetentionEvent
+	require.NoError(t, json.Unmarshal(kv.Value, &event))
+	assert.Equal(t, []store.PrunedEntry{{Key: []byte("key"), Index: indexes[0].Index, Reason: store.PrunedByRevisions}}, event.Pruned)
+	_, err = db.ByIndex(indexes[0])
+	assert.Equal(t, store.ErrEntryPruned, err)
+}
@@ -41,6 +41,9 @@ import (
 	"github.com/codenotary/immudb/cmd/helper"
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/clock"
+	_ "github.com/codenotary/immudb/pkg/compression" // accept gzip and zstd compressed calls
+	"github.com/codenotary/immudb/pkg/store"
 	"github.com/codenotary/immudb/pkg/store/sysstore"
 	"github.com/golang/protobuf/ptypes/empty"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
@@ -53,8 +56,17 @@ import (
 
 var ErrEmptyAdminPassword = fmt.Errorf("Admin password cannot be empty")
 
+// ErrBackupDisabled is returned by Backup if the server has no backup directory, see Options.WithBackupDir
+var ErrBackupDisabled = status.New(codes.FailedPrecondition, "backups are disabled: the server has no backup directory").Err()
+
 var startedAt time.Time
 
+// period between offset measurements of the NTP disciplined clock, and timeout of each measurement
+const (
+	ntpSyncInterval = 10 * time.Minute
+	ntpTimeout      = 5 * time.Second
+)
+
 var immudbTextLogo = " _                               _ _     \n" +
 	"(_)                             | | |    \n" +
 	" _ _ __ ___  _ __ ___  _   _  __| | |__  \n" +
@@ -74,6 +86,20 @@ func (s *ImmuServer) Start() error {
 		s.Logger.Infof("\n%s\n%s\n\n", immudbTextLogo, s.Options)
 	}
 
+	if err = s.setUpClock(); err != nil {
+		return logErr(s.Logger, "Unable to set up clock: %v", err)
+	}
+
+	if err = s.setUpFaultInjection(); err != nil {
+		return logErr(s.Logger, "Unable to set up fault injection: %v", err)
+	}
+
+	if s.Options.EncryptionKey != "" {
+		if s.encryptionKey, err = store.ReadEncryptionKey(s.Options.EncryptionKey); err != nil {
+			return logErr(s.Logger, "Unable to read the encryption key: %v", err)
+		}
+	}
+
 	dataDir := s.Options.Dir
 	if err = s.loadDefaultDatabase(dataDir); err != nil {
 		return logErr(s.Logger, "Unable load default database: %v", err)
@@ -97,6 +123,9 @@ func (s *ImmuServer) Start() error {
 		return logErr(s.Logger, "Unable load databases: %v", err)
 	}
 
+	s.tamper = newTamperResponder(s.Options.TamperPolicy, s.componentLogger("tamper-policy"))
+	s.selfCheck()
+
 	s.multidbmode = s.mandatoryAuth()
 	if !s.Options.GetAuth() && s.multidbmode {
 		s.Logger.Infof("Authentication must be on.")
@@ -176,6 +205,13 @@ func (s *ImmuServer) Start() error {
 		uuidContext.UuidContextSetter,
 		grpc_prometheus.UnaryServerInterceptor,
 		auth.ServerUnaryInterceptor,
+		s.ChecksumInterceptor,
+		s.TamperInterceptor,
+		s.UsageInterceptor,
+		s.FaultInterceptor,
+	}
+	if s.Options.KeyInterceptor != nil {
+		uis = append(uis, s.KeyInterceptor)
 	}
 	sss := []grpc.StreamServerInterceptor{
 		uuidContext.UuidStreamContextSetter,
@@ -193,10 +229,13 @@ func (s *ImmuServer) Start() error {
 	schema.RegisterImmuServiceServer(s.GrpcServer, s)
 	grpc_prometheus.Register(s.GrpcServer)
 	s.startCorruptionChecker()
+	s.startCountReconciler()
+	s.startRetentionEnforcer()
+	s.startSegmentTierer()
 
 	go s.printUsageCallToAction()
 
-	startedAt = time.Now()
+	startedAt = s.now()
 
 	go func() {
 		if err := s.GrpcServer.Serve(listener); err != nil {
@@ -227,14 +266,15 @@ func (s *ImmuServer) setupPidFile() error {
 	return err
 }
 
-func (s *ImmuServer) setUpMetricsServer() error {
-	s.metricsServer = StartMetrics(
+func (s *ImmuServer) setUpMetricsServer() (err error) {
+	s.metricsServer, err = StartSecureMetrics(
 		s.Options.MetricsBind(),
 		s.Logger,
 		func() float64 { return float64(s.dbList.GetByIndex(DefaultDbIndex).Store.CountAll()) },
 		func() float64 { return time.Since(startedAt).Hours() },
+		s.Options.MetricsAuth,
 	)
-	return nil
+	return err
 }
 
 func (s *ImmuServer) setUpMTLS() ([]grpc.ServerOption, error) {
@@ -298,13 +338,9 @@ func (s *ImmuServer) loadSystemDatabase(dataDir string, adminPassword string) er
 	_, sysDbErr := s.OS.Stat(systemDbRootDir)
 	if s.OS.IsNotExist(sysDbErr) {
 		if s.Options.GetAuth() {
-			op := DefaultOption().
-				WithDbName(s.Options.GetSystemAdminDbName()).
-				WithDbRootPath(dataDir).
-				WithCorruptionChecker(s.Options.CorruptionCheck).
-				WithInMemoryStore(s.Options.GetInMemoryStore()).WithDbRootPath(s.Options.Dir)
+			op := s.newDatabaseOptions(s.Options.GetSystemAdminDbName())
 
-			db, err := NewDb(op, s.Logger)
+			db, err := NewDb(op, s.componentLogger(op.GetDbName()))
 			if err != nil {
 				return err
 			}
@@ -319,12 +355,9 @@ func (s *ImmuServer) loadSystemDatabase(dataDir string, adminPassword string) er
 			s.Logger.Infof("Admin user %s successfully created", adminUsername)
 		}
 	} else {
-		op := DefaultOption().
-			WithDbName(s.Options.GetSystemAdminDbName()).
-			WithDbRootPath(dataDir).
-			WithCorruptionChecker(s.Options.CorruptionCheck).WithDbRootPath(s.Options.Dir)
+		op := s.newDatabaseOptions(s.Options.GetSystemAdminDbName())
 
-		db, err := OpenDb(op, s.Logger)
+		db, err := OpenDb(op, s.componentLogger(op.GetDbName()))
 		if err != nil {
 			return err
 		}
@@ -345,13 +378,9 @@ func (s *ImmuServer) loadDefaultDatabase(dataDir string) error {
 
 	_, defaultDbErr := s.OS.Stat(defaultDbRootDir)
 	if s.OS.IsNotExist(defaultDbErr) {
-		op := DefaultOption().
-			WithDbName(s.Options.GetDefaultDbName()).
-			WithDbRootPath(dataDir).
-			WithCorruptionChecker(s.Options.CorruptionCheck).
-			WithInMemoryStore(s.Options.GetInMemoryStore()).WithDbRootPath(s.Options.Dir)
+		op := s.newDatabaseOptions(s.Options.GetDefaultDbName())
 
-		db, err := NewDb(op, s.Logger)
+		db, err := NewDb(op, s.componentLogger(op.GetDbName()))
 		if err != nil {
 			return err
 		}
@@ -359,12 +388,9 @@ func (s *ImmuServer) loadDefaultDatabase(dataDir string) error {
 		s.databasenameToIndex[s.Options.GetDefaultDbName()] = int64(s.dbList.Length())
 		s.dbList.Append(db)
 	} else {
-		op := DefaultOption().
-			WithDbName(s.Options.GetDefaultDbName()).
-			WithDbRootPath(dataDir).
-			WithCorruptionChecker(s.Options.CorruptionCheck).WithDbRootPath(s.Options.Dir)
+		op := s.newDatabaseOptions(s.Options.GetDefaultDbName())
 
-		db, err := OpenDb(op, s.Logger)
+		db, err := OpenDb(op, s.componentLogger(op.GetDbName()))
 		if err != nil {
 			return err
 		}
@@ -411,10 +437,9 @@ func (s *ImmuServer) loadUserDatabases(dataDir string) error {
 		pathparts := strings.Split(val, string(filepath.Separator))
 		dbname := pathparts[len(pathparts)-1]
 
-		op := DefaultOption().WithDbName(dbname).WithDbRootPath(dataDir).
-			WithCorruptionChecker(s.Options.CorruptionCheck).WithDbRootPath(s.Options.Dir)
+		op := s.newDatabaseOptions(dbname)
 
-		db, err := OpenDb(op, s.Logger)
+		db, err := OpenDb(op, s.componentLogger(op.GetDbName()))
 		if err != nil {
 			return err
 		}
@@ -427,6 +452,31 @@ func (s *ImmuServer) loadUserDatabases(dataDir string) error {
 	return nil
 }
 
+// setUpClock starts the NTP disciplined clock when an NTP server is configured and no clock is set explicitly,
+// and makes the log tail use the server clock
+func (s *ImmuServer) setUpClock() error {
+	if s.Options.Clock == nil && s.Options.NTPServer != "" {
+		ntp, err := clock.NewNTP(s.Options.NTPServer, ntpSyncInterval, ntpTimeout)
+		if err != nil {
+			return err
+		}
+		s.ntp = ntp
+		s.Options.Clock = ntp
+	}
+	if s.Options.Clock != nil && s.logTail != nil {
+		s.logTail.SetClock(s.Options.Clock)
+	}
+	return nil
+}
+
+// now returns the current time according to the configured clock
+func (s *ImmuServer) now() time.Time {
+	if s.Options.Clock == nil {
+		return time.Now()
+	}
+	return s.Options.Clock.Now()
+}
+
 // Stop stops the immudb server
 func (s *ImmuServer) Stop() error {
 	s.mux.Lock()
@@ -441,12 +491,19 @@ func (s *ImmuServer) Stop() error {
 		defer func() { s.GrpcServer = nil }()
 	}
 
+	if s.ntp != nil {
+		s.ntp.Stop()
+	}
+
 	return s.CloseDatabases()
 }
 
 //CloseDatabases closes all opened databases including the consinstency checker
 func (s *ImmuServer) CloseDatabases() error {
 	s.stopCorruptionChecker()
+	s.stopCountReconciler()
+	s.stopRetentionEnforcer()
+	s.stopSegmentTierer()
 
 	if s.sysDb != nil {
 		s.sysDb.Store.Close()
@@ -466,8 +523,11 @@ func (s *ImmuServer) startCorruptionChecker() {
 		cco.singleiteration = false
 		cco.iterationSleepTime = 5 * time.Second
 		cco.frequencySleepTime = 500 * time.Millisecond
+		cco.onTamper = func(database string, reason string) {
+			s.tamper.detected(tamperSourceChecker, database, reason)
+		}
 
-		s.Cc = NewCorruptionChecker(cco, s.dbList, s.Logger, randomGenerator{})
+		s.Cc = NewCorruptionChecker(cco, s.dbList, s.componentLogger("consistency-checker"), randomGenerator{})
 
 		go func() {
 			s.Logger.Infof("Starting consistency-checker")
@@ -480,12 +540,70 @@ func (s *ImmuServer) startCorruptionChecker() {
 
 //StopCorruptionChecker shutdown the corruption checkcer
 func (s *ImmuServer) stopCorruptionChecker() error {
-	if s.Options.CorruptionCheck {
+	// the checker isn't started if the server failed to start
+	if s.Options.CorruptionCheck && s.Cc != nil {
 		s.Cc.Stop()
 	}
 	return nil
 }
 
+// componentLogger returns the server logger tagging the entries published to the log tail with the given component
+func (s *ImmuServer) componentLogger(component string) logger.Logger {
+	if s.logTail == nil {
+		return s.Logger
+	}
+	return logger.NewTailLogger(s.Logger, component, s.logTail)
+}
+
+func (s *ImmuServer) startCountReconciler() {
+	if s.Options.ReconcileInterval > 0 {
+		s.Logger.Infof("Starting index count reconciliation every %s", s.Options.ReconcileInterval)
+		s.countReconciler = newCountReconciler(s.dbList, s.componentLogger("reconciler"), s.Options.ReconcileInterval)
+		s.countReconciler.Start()
+	}
+}
+
+func (s *ImmuServer) stopCountReconciler() {
+	if s.countReconciler != nil {
+		s.countReconciler.Stop()
+		s.countReconciler = nil
+	}
+}
+
+func (s *ImmuServer) startRetentionEnforcer() {
+	if s.Options.RetentionInterval > 0 && len(s.Options.Retention) > 0 {
+		s.Logger.Infof("Starting retention enforcement every %s", s.Options.RetentionInterval)
+		var sig signer.Signer
+		if rs, ok := s.RootSigner.(*rootSigner); ok {
+			sig = rs.Signer
+		}
+		s.retentionEnforcer = newRetentionEnforcer(s.dbList, s.componentLogger("retention"), s.Options.RetentionInterval, s.Options.Retention, sig)
+		s.retentionEnforcer.Start()
+	}
+}
+
+func (s *ImmuServer) stopRetentionEnforcer() {
+	if s.retentionEnforcer != nil {
+		s.retentionEnforcer.Stop()
+		s.retentionEnforcer = nil
+	}
+}
+
+func (s *ImmuServer) startSegmentTierer() {
+	if s.Options.TieringDir != "" && s.Options.TieringInterval > 0 {
+		s.Logger.Infof("Moving value log segments older than %s to %s every %s", s.Options.TieringMinAge, s.Options.TieringDir, s.Options.TieringInterval)
+		s.segmentTierer = newSegmentTierer(s.dbList, s.componentLogger("tiering"), s.Options.TieringInterval, s.Options.TieringDir, s.Options.TieringMinAge)
+		s.segmentTierer.Start()
+	}
+}
+
+func (s *ImmuServer) stopSegmentTierer() {
+	if s.segmentTierer != nil {
+		s.segmentTierer.Stop()
+		s.segmentTierer = nil
+	}
+}
+
 // Login ...
 func (s *ImmuServer) Login(ctx context.Context, r *schema.LoginRequest) (*schema.LoginResponse, error) {
 	if !s.Options.auth {
@@ -663,9 +781,23 @@ func (s *ImmuServer) Set(ctx context.Context, kv *schema.KeyValue) (*schema.Inde
 		return nil, err
 	}
 
+	if idempotencyKey := idempotencyKeyFromContext(ctx); idempotencyKey != "" {
+		return s.dbList.GetByIndex(ind).IdempotentSet(idempotencyKey, kv)
+	}
+
 	return s.dbList.GetByIndex(ind).Set(kv)
 }
 
+// Delete marks a key of the current database as deleted, the tombstone being kept in its history
+func (s *ImmuServer) Delete(ctx context.Context, key *schema.Key) (*schema.Index, error) {
+	s.Logger.Debugf("delete %s", key.Key)
+	ind, err := s.getDbIndexFromCtx(ctx, "Delete")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).Delete(key)
+}
+
 // SafeSet ...
 func (s *ImmuServer) SafeSet(ctx context.Context, opts *schema.SafeSetOptions) (*schema.Proof, error) {
 	s.Logger.Debugf("SafeSet %+v", opts)
@@ -675,6 +807,10 @@ func (s *ImmuServer) SafeSet(ctx context.Context, opts *schema.SafeSetOptions) (
 		return nil, err
 	}
 
+	if idempotencyKey := idempotencyKeyFromContext(ctx); idempotencyKey != "" {
+		return s.dbList.GetByIndex(ind).IdempotentSafeSet(idempotencyKey, opts)
+	}
+
 	return s.dbList.GetByIndex(ind).SafeSet(opts)
 }
 
@@ -721,6 +857,16 @@ func (s *ImmuServer) Count(ctx context.Context, prefix *schema.KeyPrefix) (*sche
 	return s.dbList.GetByIndex(ind).Count(prefix)
 }
 
+// Stats returns the number of live keys and of entries having the provided prefix, along with their size
+func (s *ImmuServer) Stats(ctx context.Context, prefix *schema.KeyPrefix) (*schema.PrefixStats, error) {
+	s.Logger.Debugf("stats %s", prefix.Prefix)
+	ind, err := s.getDbIndexFromCtx(ctx, "Stats")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).Stats(prefix)
+}
+
 // CountAll ...
 func (s *ImmuServer) CountAll(ctx context.Context, e *empty.Empty) (*schema.ItemsCount, error) {
 	ind, err := s.getDbIndexFromCtx(ctx, "CountAll")
@@ -763,6 +909,30 @@ func (s *ImmuServer) ByIndex(ctx context.Context, index *schema.Index) (*schema.
 	return s.dbList.GetByIndex(ind).ByIndex(index)
 }
 
+// GetBySequence returns the entries written by the write having the given sequence number
+func (s *ImmuServer) GetBySequence(ctx context.Context, seq *schema.Sequence) (*schema.SequencedWrite, error) {
+	s.Logger.Debugf("get by sequence %d ", seq.Sequence)
+
+	ind, err := s.getDbIndexFromCtx(ctx, "GetBySequence")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dbList.GetByIndex(ind).GetBySequence(seq)
+}
+
+// VerifyValueHash compares a locally computed SHA-256 of a value with the stored entry and returns its proof
+func (s *ImmuServer) VerifyValueHash(ctx context.Context, opts *schema.ValueHashOptions) (*schema.ValueHashVerification, error) {
+	s.Logger.Debugf("verify value hash %s", opts.Key)
+
+	ind, err := s.getDbIndexFromCtx(ctx, "VerifyValueHash")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dbList.GetByIndex(ind).VerifyValueHash(opts)
+}
+
 // BySafeIndex ...
 func (s *ImmuServer) BySafeIndex(ctx context.Context, sio *schema.SafeIndexOptions) (*schema.SafeItem, error) {
 	s.Logger.Debugf("get by safeIndex %d ", sio.Index)
@@ -783,6 +953,185 @@ func (s *ImmuServer) History(ctx context.Context, options *schema.HistoryOptions
 	return s.dbList.GetByIndex(ind).History(options)
 }
 
+// DumpKeyHistory returns all the revisions of a key along with their inclusion proofs against the same root
+func (s *ImmuServer) DumpKeyHistory(ctx context.Context, k *schema.Key) (*schema.KeyHistoryDump, error) {
+	s.Logger.Debugf("dump history for key %s ", string(k.Key))
+	ind, err := s.getDbIndexFromCtx(ctx, "DumpKeyHistory")
+	if err != nil {
+		return nil, err
+	}
+	dump, err := s.dbList.GetByIndex(ind).DumpKeyHistory(k)
+	if err != nil {
+		return nil, err
+	}
+	if s.Options.SigningKey != "" {
+		if dump.Root, err = s.RootSigner.Sign(dump.Root); err != nil {
+			return nil, err
+		}
+	}
+	return dump, nil
+}
+
+// GetAt returns the entry a key had at an index of the tree, with its inclusion proof against the root at that index
+func (s *ImmuServer) GetAt(ctx context.Context, opts *schema.GetAtOptions) (*schema.HistoricalItem, error) {
+	s.Logger.Debugf("get key %s at index %d", string(opts.Key), opts.Index)
+	ind, err := s.getDbIndexFromCtx(ctx, "GetAt")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).GetAt(opts)
+}
+
+// GetAsOf returns the entry a key had at a point in time, with its inclusion proof against the root at that time
+func (s *ImmuServer) GetAsOf(ctx context.Context, opts *schema.GetAsOfOptions) (*schema.HistoricalItem, error) {
+	s.Logger.Debugf("get key %s as of %d", string(opts.Key), opts.Timestamp)
+	ind, err := s.getDbIndexFromCtx(ctx, "GetAsOf")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).GetAsOf(opts)
+}
+
+// SampleKeys returns a reproducible pseudo-random sample of keys along with their inclusion proofs against the same root
+func (s *ImmuServer) SampleKeys(ctx context.Context, options *schema.SampleOptions) (*schema.KeySample, error) {
+	s.Logger.Debugf("sample %d keys with prefix %s and seed %d", options.Size, string(options.Prefix), options.Seed)
+	ind, err := s.getDbIndexFromCtx(ctx, "SampleKeys")
+	if err != nil {
+		return nil, err
+	}
+	sample, err := s.dbList.GetByIndex(ind).SampleKeys(options)
+	if err != nil {
+		return nil, err
+	}
+	if s.Options.SigningKey != "" {
+		if sample.Root, err = s.RootSigner.Sign(sample.Root); err != nil {
+			return nil, err
+		}
+	}
+	return sample, nil
+}
+
+// FreezePrefix seals a key prefix of the current database, no further writes are allowed under it.
+// The freeze is recorded as an entry whose index is returned, so that it can be verified.
+func (s *ImmuServer) FreezePrefix(ctx context.Context, prefix *schema.KeyPrefix) (*schema.Index, error) {
+	s.Logger.Debugf("freeze prefix %s", string(prefix.Prefix))
+	ind, err := s.getDbIndexFromCtx(ctx, "FreezePrefix")
+	if err != nil {
+		return nil, err
+	}
+	index, err := s.dbList.GetByIndex(ind).FreezePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	s.Logger.Infof("key prefix %s frozen at index %d", string(prefix.Prefix), index.Index)
+	return index, nil
+}
+
+// AnalyzeStorage returns the storage efficiency report of the current database
+func (s *ImmuServer) AnalyzeStorage(ctx context.Context, e *empty.Empty) (*schema.StorageReport, error) {
+	s.Logger.Debugf("analyze storage")
+	ind, err := s.getDbIndexFromCtx(ctx, "AnalyzeStorage")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).AnalyzeStorage(e)
+}
+
+// Backup writes a consistent snapshot of the current database into the backup directory of the server, without
+// stopping writes, and returns the root the tree had when it was taken, signed if the server has a signing key
+func (s *ImmuServer) Backup(ctx context.Context, e *empty.Empty) (*schema.BackupInfo, error) {
+	s.Logger.Debugf("backup")
+	ind, err := s.getDbIndexFromCtx(ctx, "Backup")
+	if err != nil {
+		return nil, err
+	}
+	if s.Options.BackupDir == "" {
+		return nil, ErrBackupDisabled
+	}
+	// the path of the snapshot is returned absolute, so that it can be given back to RestoreBackup as is
+	dir, err := filepath.Abs(s.Options.BackupDir)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.dbList.GetByIndex(ind).Backup(dir)
+	if err != nil {
+		return nil, err
+	}
+	if s.Options.SigningKey != "" {
+		if info.Root, err = s.RootSigner.Sign(info.Root); err != nil {
+			return nil, err
+		}
+	}
+	s.Logger.Infof("database %s backed up to %s at index %d", info.DatabaseName, info.File, info.Root.GetIndex())
+	return info, nil
+}
+
+// RestoreBackup creates a new database from a snapshot of the backup directory written by Backup. The database is
+// made available only once the root recomputed from the snapshot matches the one recorded in it.
+func (s *ImmuServer) RestoreBackup(ctx context.Context, req *schema.RestoreBackupRequest) (*schema.BackupInfo, error) {
+	s.Logger.Debugf("restore backup %+v", *req)
+
+	if !s.Options.GetAuth() {
+		return nil, fmt.Errorf("this command is available only with authentication on")
+	}
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get loggedin user data")
+	}
+	if !user.IsSysAdmin {
+		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+
+	if s.Options.BackupDir == "" {
+		return nil, ErrBackupDisabled
+	}
+	if s.Options.GetInMemoryStore() {
+		return nil, fmt.Errorf("backups cannot be restored into an in-memory store")
+	}
+	if err = s.checkNewDatabaseName(req.DatabaseName); err != nil {
+		return nil, err
+	}
+	file, err := s.backupFile(req.File)
+	if err != nil {
+		return nil, err
+	}
+
+	op := s.newDatabaseOptions(req.DatabaseName)
+	db, info, err := RestoreDb(op, file, s.componentLogger(op.GetDbName()))
+	if err != nil {
+		return nil, err
+	}
+
+	s.databasenameToIndex[req.DatabaseName] = int64(s.dbList.Length())
+	s.dbList.Append(db)
+	s.multidbmode = true
+
+	if s.Options.SigningKey != "" {
+		if info.Root, err = s.RootSigner.Sign(info.Root); err != nil {
+			return nil, err
+		}
+	}
+	s.Logger.Infof("database %s restored from %s at index %d", info.DatabaseName, info.File, info.Root.GetIndex())
+	return info, nil
+}
+
+// backupFile resolves the path of a snapshot, relative paths being relative to the backup directory. Snapshots out
+// of the backup directory are never read.
+func (s *ImmuServer) backupFile(file string) (string, error) {
+	dir, err := filepath.Abs(s.Options.BackupDir)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	file = filepath.Clean(file)
+	if rel, err := filepath.Rel(dir, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", status.New(codes.InvalidArgument, "the snapshot must be within the backup directory").Err()
+	}
+	return file, nil
+}
+
 // Health ...
 func (s *ImmuServer) Health(ctx context.Context, e *empty.Empty) (*schema.HealthResponse, error) {
 	ind, _ := s.getDbIndexFromCtx(ctx, "Health")
@@ -803,6 +1152,16 @@ func (s *ImmuServer) Reference(ctx context.Context, refOpts *schema.ReferenceOpt
 	return s.dbList.GetByIndex(ind).Reference(refOpts)
 }
 
+// CompareAndReference ...
+func (s *ImmuServer) CompareAndReference(ctx context.Context, carOpts *schema.CompareAndReferenceOptions) (index *schema.Index, err error) {
+	s.Logger.Debugf("compare and reference options: %v", carOpts)
+	ind, err := s.getDbIndexFromCtx(ctx, "CompareAndReference")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).CompareAndReference(carOpts)
+}
+
 // Reference ...
 func (s *ImmuServer) GetReference(ctx context.Context, refOpts *schema.Key) (index *schema.Item, err error) {
 	s.Logger.Debugf("getReference options: %v", refOpts)
@@ -813,6 +1172,26 @@ func (s *ImmuServer) GetReference(ctx context.Context, refOpts *schema.Key) (ind
 	return s.dbList.GetByIndex(ind).GetReference(refOpts)
 }
 
+// Unreference retires a reference of the current database, the tombstone being kept in its history
+func (s *ImmuServer) Unreference(ctx context.Context, key *schema.Key) (*schema.Index, error) {
+	s.Logger.Debugf("unreference %s", key.Key)
+	ind, err := s.getDbIndexFromCtx(ctx, "Unreference")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).Unreference(key)
+}
+
+// GetReferences lists the references resolving to a key of the current database
+func (s *ImmuServer) GetReferences(ctx context.Context, options *schema.ReferencesOptions) (*schema.ReferenceList, error) {
+	s.Logger.Debugf("getReferences options: %v", options)
+	ind, err := s.getDbIndexFromCtx(ctx, "GetReferences")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).GetReferences(options)
+}
+
 // SafeReference ...
 func (s *ImmuServer) SafeReference(ctx context.Context, safeRefOpts *schema.SafeReferenceOptions) (proof *schema.Proof, err error) {
 	s.Logger.Debugf("safe reference options: %v", safeRefOpts)
@@ -843,6 +1222,16 @@ func (s *ImmuServer) ZScan(ctx context.Context, opts *schema.ZScanOptions) (*sch
 	return s.dbList.GetByIndex(ind).ZScan(opts)
 }
 
+// SafeZScan scans a sorted set along with a multiproof of the inclusion of its entries and of the items they reference
+func (s *ImmuServer) SafeZScan(ctx context.Context, opts *schema.SafeZScanOptions) (*schema.SafeZItemList, error) {
+	s.Logger.Debugf("SafeZScan %+v", *opts)
+	ind, err := s.getDbIndexFromCtx(ctx, "SafeZScan")
+	if err != nil {
+		return nil, err
+	}
+	return s.dbList.GetByIndex(ind).SafeZScan(opts)
+}
+
 // SafeZAdd ...
 func (s *ImmuServer) SafeZAdd(ctx context.Context, opts *schema.SafeZAddOptions) (*schema.Proof, error) {
 	s.Logger.Debugf("SafeZAdd %+v", *opts)
@@ -874,6 +1263,90 @@ func (s *ImmuServer) Dump(in *empty.Empty, stream schema.ImmuService_DumpServer)
 	return err
 }
 
+// Subscribe streams the changes of the keys selected by req, see Db.Subscribe
+func (s *ImmuServer) Subscribe(req *schema.SubscribeRequest, stream schema.ImmuService_SubscribeServer) error {
+	ind, err := s.getDbIndexFromCtx(stream.Context(), "Subscribe")
+	if err != nil {
+		return err
+	}
+	err = s.dbList.GetByIndex(ind).Subscribe(req, stream)
+	s.Logger.Debugf("Subscribe stream complete")
+	return err
+}
+
+// Tail streams the entries of the database from the index selected by req on, see Db.Tail
+func (s *ImmuServer) Tail(req *schema.TailRequest, stream schema.ImmuService_TailServer) error {
+	ind, err := s.getDbIndexFromCtx(stream.Context(), "Tail")
+	if err != nil {
+		return err
+	}
+	err = s.dbList.GetByIndex(ind).Tail(req, stream)
+	s.Logger.Debugf("Tail stream complete")
+	return err
+}
+
+// Logs streams the recent server log entries matching the requested level and components.
+// If follow is set, the stream is kept open and new entries are sent as they are logged.
+func (s *ImmuServer) Logs(req *schema.LogRequest, stream schema.ImmuService_LogsServer) error {
+	if !s.Options.GetAuth() {
+		return fmt.Errorf("this command is available only with authentication on")
+	}
+	_, user, err := s.getLoggedInUserdataFromCtx(stream.Context())
+	if err != nil {
+		return fmt.Errorf("could not get loggedin user data")
+	}
+	if !user.IsSysAdmin {
+		return fmt.Errorf("Logged In user does not have permissions for this operation")
+	}
+	if s.logTail == nil {
+		return status.New(codes.Unavailable, "log tailing is not enabled").Err()
+	}
+
+	level := logger.LogDebug
+	if req.Level != "" {
+		if level, err = logger.ParseLogLevel(req.Level); err != nil {
+			return status.New(codes.InvalidArgument, err.Error()).Err()
+		}
+	}
+	components := make(map[string]bool, len(req.Components))
+	for _, c := range req.Components {
+		components[c] = true
+	}
+	send := func(e logger.Entry) error {
+		if e.Level < level || (len(components) > 0 && !components[e.Component]) {
+			return nil
+		}
+		return stream.Send(&schema.LogEntry{
+			Timestamp: e.Time.UnixNano(),
+			Level:     e.Level.String(),
+			Component: e.Component,
+			Message:   e.Message,
+		})
+	}
+
+	recent, entries, unsubscribe := s.logTail.Subscribe(logTailSize)
+	defer unsubscribe()
+
+	for _, e := range recent {
+		if err := send(e); err != nil {
+			return err
+		}
+	}
+	if !req.Follow {
+		return nil
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-entries:
+			if err := send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // todo(joe-dz): Enable restore when the feature is required again.
 // Also, make sure that the generated files are updated
 //func (s *ImmuServer) HotRestore(stream schema.ImmuService_RestoreServer) (err error) {
@@ -969,7 +1442,7 @@ func (s *ImmuServer) ChangePassword(ctx context.Context, r *schema.ChangePasswor
 	}
 
 	targetUser.CreatedBy = user.Username
-	targetUser.CreatedAt = time.Now()
+	targetUser.CreatedAt = s.now()
 	if err := s.saveUser(targetUser); err != nil {
 		return nil, err
 	}
@@ -999,31 +1472,13 @@ func (s *ImmuServer) CreateDatabase(ctx context.Context, newdb *schema.Database)
 		return nil, fmt.Errorf("Logged In user does not have permissions for this operation")
 	}
 
-	if newdb.Databasename == SystemdbName {
-		return nil, fmt.Errorf("this database name is reserved")
-	}
-	if strings.ToLower(newdb.Databasename) != newdb.Databasename {
-		return nil, fmt.Errorf("provide a lowercase database name")
-	}
-	newdb.Databasename = strings.ToLower(newdb.Databasename)
-	if err = IsAllowedDbName(newdb.Databasename); err != nil {
+	if err = s.checkNewDatabaseName(newdb.Databasename); err != nil {
 		return nil, err
 	}
 
-	//check if database exists
-	if _, ok := s.databasenameToIndex[newdb.GetDatabasename()]; ok {
-		return nil, fmt.Errorf("database %s already exists", newdb.GetDatabasename())
-	}
-
-	dataDir := s.Options.Dir
-
-	op := DefaultOption().
-		WithDbName(newdb.Databasename).
-		WithDbRootPath(dataDir).
-		WithCorruptionChecker(s.Options.CorruptionCheck).
-		WithInMemoryStore(s.Options.GetInMemoryStore()).WithDbRootPath(s.Options.Dir)
+	op := s.newDatabaseOptions(newdb.Databasename)
 
-	db, err := NewDb(op, s.Logger)
+	db, err := NewDb(op, s.componentLogger(op.GetDbName()))
 	if err != nil {
 		s.Logger.Errorf(err.Error())
 		return nil, err
@@ -1036,6 +1491,34 @@ func (s *ImmuServer) CreateDatabase(ctx context.Context, newdb *schema.Database)
 	return &empty.Empty{}, nil
 }
 
+// checkNewDatabaseName checks that a database can be created with the given name
+func (s *ImmuServer) checkNewDatabaseName(name string) error {
+	if name == SystemdbName {
+		return fmt.Errorf("this database name is reserved")
+	}
+	if strings.ToLower(name) != name {
+		return fmt.Errorf("provide a lowercase database name")
+	}
+	if err := IsAllowedDbName(name); err != nil {
+		return err
+	}
+
+	//check if database exists
+	if _, ok := s.databasenameToIndex[name]; ok {
+		return fmt.Errorf("database %s already exists", name)
+	}
+	return nil
+}
+
+// newDatabaseOptions returns the options of a new database of the server named name
+func (s *ImmuServer) newDatabaseOptions(name string) *DbOptions {
+	return DefaultOption().WithClock(s.Options.Clock).
+		WithDbName(name).
+		WithDbRootPath(s.Options.Dir).
+		WithCorruptionChecker(s.Options.CorruptionCheck).WithStrictAppendOnly(s.Options.StrictAppendOnly).WithSequencer(s.Options.Sequencer).WithCheckpointInterval(s.Options.CheckpointInterval).WithSyncWrites(s.Options.SyncWrites).WithTreeSync(s.Options.TreeSync).WithValueCompression(s.Options.ValueCompression).WithEncryptionKey(s.encryptionKey).WithDataKeyRotation(s.Options.DataKeyRotation).
+		WithInMemoryStore(s.Options.GetInMemoryStore())
+}
+
 // CreateUser Creates a new user
 func (s *ImmuServer) CreateUser(ctx context.Context, r *schema.CreateUserRequest) (*empty.Empty, error) {
 	s.Logger.Debugf("CreateUser %+v", *r)
@@ -1372,7 +1855,7 @@ func (s *ImmuServer) ChangePermission(ctx context.Context, r *schema.ChangePermi
 		targetUser.GrantPermission(r.Database, r.Permission)
 	}
 	targetUser.CreatedBy = user.Username
-	targetUser.CreatedAt = time.Now()
+	targetUser.CreatedAt = s.now()
 
 	if err := s.saveUser(targetUser); err != nil {
 		return nil, err
@@ -1423,7 +1906,7 @@ func (s *ImmuServer) SetActiveUser(ctx context.Context, r *schema.SetActiveUserR
 	}
 	targetUser.Active = r.Active
 	targetUser.CreatedBy = user.Username
-	targetUser.CreatedAt = time.Now()
+	targetUser.CreatedAt = s.now()
 	if err := s.saveUser(targetUser); err != nil {
 		return nil, err
 	}
@@ -1472,6 +1955,7 @@ func (s *ImmuServer) getLoggedInUserdataFromCtx(ctx context.Context) (int64, *au
 		}
 		return -1, nil, fmt.Errorf("could not get userdata from token")
 	}
+	s.trackSession(ctx, jsUser)
 	u, err := s.getLoggedInUserDataFromUsername(jsUser.Username)
 	return jsUser.DatabaseIndex, u, err
 }
@@ -1512,7 +1996,7 @@ func (s *ImmuServer) insertNewUser(username []byte, plainPassword []byte, permis
 	userdata.Username = string(username)
 	userdata.Permissions = append(userdata.Permissions, auth.Permission{Permission: permission, Database: database})
 	userdata.CreatedBy = createdBy
-	userdata.CreatedAt = time.Now()
+	userdata.CreatedAt = s.now()
 
 	if permission == auth.PermissionSysAdmin {
 		userdata.IsSysAdmin = true
@@ -19,6 +19,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -30,6 +31,7 @@ import (
 	"testing"
 	"time"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -39,8 +41,10 @@ import (
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/clock"
 	"github.com/codenotary/immudb/pkg/immuos"
 	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/store"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -316,6 +320,61 @@ func TestServerLoaduserDatabase(t *testing.T) {
 	}
 	require.Equal(t, errWalk, s.loadUserDatabases("loaduserdatabase"))
 }
+
+func TestServerRestoreBackup(t *testing.T) {
+	s := newAuthServer("restorebackup")
+	defer os.RemoveAll(s.Options.Dir)
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+
+	_, err = s.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: "snapshot", DatabaseName: "restored"})
+	require.Equal(t, ErrBackupDisabled, err)
+
+	dir, err := ioutil.TempDir("", "immudb_backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s.Options.BackupDir = dir
+
+	// the proof waits for the entry to be added to the tree
+	_, err = s.SafeSet(ctx, &schema.SafeSetOptions{Kv: &schema.KeyValue{Key: testKey, Value: testValue}})
+	require.NoError(t, err)
+	backup, err := s.Backup(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+
+	info, err := s.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: backup.File, DatabaseName: "restored"})
+	require.NoError(t, err)
+	require.Equal(t, "restored", info.DatabaseName)
+	require.Equal(t, backup.Root.GetIndex(), info.Root.GetIndex())
+	require.Equal(t, backup.Root.GetRoot(), info.Root.GetRoot())
+
+	restoredCtx, err := usedatabase(ctx, s, "restored")
+	require.NoError(t, err)
+	item, err := s.Get(restoredCtx, &schema.Key{Key: testKey})
+	require.NoError(t, err)
+	require.Equal(t, testValue, item.Value)
+	root, err := s.CurrentRoot(restoredCtx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, backup.Root.GetRoot(), root.GetRoot())
+
+	// paths are relative to the backup directory
+	rel, err := filepath.Rel(dir, backup.File)
+	require.NoError(t, err)
+	_, err = s.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: rel, DatabaseName: "restoredrel"})
+	require.NoError(t, err)
+
+	_, err = s.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: backup.File, DatabaseName: "restored"})
+	require.Error(t, err)
+	_, err = s.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: "../" + rel, DatabaseName: "outside"})
+	require.Error(t, err)
+	_, err = s.RestoreBackup(ctx, &schema.RestoreBackupRequest{File: "missing.snapshot", DatabaseName: "missing"})
+	require.Error(t, err)
+	_, err = os.Stat(filepath.Join(s.Options.Dir, "missing"))
+	require.True(t, os.IsNotExist(err))
+	_, err = s.RestoreBackup(context.Background(), &schema.RestoreBackupRequest{File: backup.File, DatabaseName: "anonymous"})
+	require.Error(t, err)
+
+	require.NoError(t, s.CloseDatabases())
+}
 func testServerCreateUser(ctx context.Context, s *ImmuServer, t *testing.T) {
 	newUser := &schema.CreateUserRequest{
 		User:       testUsername,
@@ -647,6 +706,33 @@ func testServerSetGetBatch(ctx context.Context, s *ImmuServer, t *testing.T) {
 			t.Fatalf("BatchSet value not equal to BatchGet value, expected %s, got %s", string(Skv.KVs[ind].Value), string(val.Value))
 		}
 	}
+
+	root, err := s.CurrentRoot(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	safeList, err := s.SafeGetBatch(ctx, &schema.SafeGetBatchOptions{
+		Keys:      []*schema.Key{{Key: []byte("Franz")}, {Key: []byte("Alberto")}},
+		RootIndex: &schema.Index{Index: root.GetIndex()},
+	})
+	require.NoError(t, err)
+	require.Len(t, safeList.Items, 2)
+	require.Equal(t, []byte("Clamer"), safeList.Items[0].Item.Value)
+	require.Equal(t, []byte("Tomba"), safeList.Items[1].Item.Value)
+	for _, safeItem := range safeList.Items {
+		require.True(t, safeItem.Proof.Verify(safeItem.Item.Hash(), *root))
+	}
+
+	safeList, err = s.SafeGetBatch(ctx, &schema.SafeGetBatchOptions{
+		Keys:       []*schema.Key{{Key: []byte("Franz")}, {Key: []byte("Alberto")}},
+		RootIndex:  &schema.Index{Index: root.GetIndex()},
+		MultiProof: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, safeList.Items, 2)
+	require.True(t, safeList.MultiProof.Verify(*root))
+	for _, safeItem := range safeList.Items {
+		require.Nil(t, safeItem.Proof)
+		require.True(t, safeList.MultiProof.Includes(safeItem.Item.Index, safeItem.Item.Hash()))
+	}
 }
 
 func testServerSetGetBatchError(ctx context.Context, s *ImmuServer, t *testing.T) {
@@ -672,6 +758,8 @@ func testServerSetGetBatchError(ctx context.Context, s *ImmuServer, t *testing.T
 	if err == nil {
 		t.Fatalf("GetBatch expected Error")
 	}
+	_, err = s.SafeGetBatch(context.Background(), &schema.SafeGetBatchOptions{Keys: []*schema.Key{{Key: []byte("Alberto")}}})
+	require.Error(t, err)
 }
 
 func testServerInclusion(ctx context.Context, s *ImmuServer, t *testing.T) {
@@ -774,6 +862,29 @@ func testServerBySafeIndex(ctx context.Context, s *ImmuServer, t *testing.T) {
 	}
 }
 
+func testServerVerifyValueHash(ctx context.Context, s *ImmuServer, t *testing.T) {
+	ind, err := s.Set(ctx, kv[0])
+	if err != nil {
+		t.Fatalf("Error Inserting to db %s", err)
+	}
+	h := sha256.Sum256(kv[0].Value)
+	v, err := s.VerifyValueHash(ctx, &schema.ValueHashOptions{Key: kv[0].Key, ValueHash: h[:], Index: ind})
+	if err != nil {
+		t.Fatalf("VerifyValueHash Error %s", err)
+	}
+	if !v.Matches || v.Proof.Index != ind.Index {
+		t.Fatalf("VerifyValueHash, unexpected verification %v", v)
+	}
+}
+
+func testServerVerifyValueHashError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	h := sha256.Sum256(kv[0].Value)
+	_, err := s.VerifyValueHash(context.Background(), &schema.ValueHashOptions{Key: kv[0].Key, ValueHash: h[:]})
+	if err == nil {
+		t.Fatalf("VerifyValueHash exptected error")
+	}
+}
+
 func testServerBySafeIndexError(ctx context.Context, s *ImmuServer, t *testing.T) {
 	_, err := s.BySafeIndex(context.Background(), &schema.SafeIndexOptions{Index: 0})
 	if err == nil {
@@ -804,6 +915,274 @@ func testServerHistoryError(ctx context.Context, s *ImmuServer, t *testing.T) {
 	}
 }
 
+func testServerDumpKeyHistory(ctx context.Context, s *ImmuServer, t *testing.T) {
+	dump, err := s.DumpKeyHistory(ctx, &schema.Key{Key: testKey})
+	if err != nil {
+		t.Fatalf("DumpKeyHistory Error %s", err)
+	}
+	if len(dump.Entries) == 0 {
+		t.Fatalf("DumpKeyHistory, expected at least one revision")
+	}
+	if err = dump.Verify(); err != nil {
+		t.Fatalf("DumpKeyHistory, verification failed %s", err)
+	}
+}
+
+func testServerDumpKeyHistoryError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.DumpKeyHistory(context.Background(), &schema.Key{Key: testKey})
+	if err == nil {
+		t.Fatalf("DumpKeyHistory exptected error")
+	}
+}
+
+func testServerGetAt(ctx context.Context, s *ImmuServer, t *testing.T) {
+	root, err := s.CurrentRoot(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("CurrentRoot Error %s", err)
+	}
+	item, err := s.GetAt(ctx, &schema.GetAtOptions{Key: testKey, Index: root.GetIndex(), RootIndex: &schema.Index{Index: root.GetIndex()}})
+	if err != nil {
+		t.Fatalf("GetAt Error %s", err)
+	}
+	if !bytes.Equal(item.Item.Value, testValue) || !item.Verify(*root) {
+		t.Fatalf("GetAt, expected verified %s, got %s", testValue, item.Item.Value)
+	}
+	item, err = s.GetAsOf(ctx, &schema.GetAsOfOptions{Key: testKey, Timestamp: time.Now().UnixNano()})
+	if err != nil {
+		t.Fatalf("GetAsOf Error %s", err)
+	}
+	if !bytes.Equal(item.Item.Value, testValue) || !item.Verify(schema.Root{}) {
+		t.Fatalf("GetAsOf, expected verified %s, got %s", testValue, item.Item.Value)
+	}
+	_, err = s.GetAt(context.Background(), &schema.GetAtOptions{Key: testKey})
+	if err == nil {
+		t.Fatalf("GetAt exptected error")
+	}
+}
+
+func testServerSampleKeys(ctx context.Context, s *ImmuServer, t *testing.T) {
+	sample, err := s.SampleKeys(ctx, &schema.SampleOptions{Size: 1, Prefix: testKey, Seed: 1})
+	if err != nil {
+		t.Fatalf("SampleKeys Error %s", err)
+	}
+	if len(sample.Entries) != 1 {
+		t.Fatalf("SampleKeys, expected one key, got %d", len(sample.Entries))
+	}
+	if err = sample.Verify(); err != nil {
+		t.Fatalf("SampleKeys, verification failed %s", err)
+	}
+}
+
+func testServerSampleKeysError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.SampleKeys(context.Background(), &schema.SampleOptions{Size: 1, Prefix: testKey})
+	if err == nil {
+		t.Fatalf("SampleKeys exptected error")
+	}
+}
+
+func testServerFreezePrefix(ctx context.Context, s *ImmuServer, t *testing.T) {
+	prefix := []byte("frozen")
+	if _, err := s.Set(ctx, &schema.KeyValue{Key: []byte("frozen1"), Value: testValue}); err != nil {
+		t.Fatalf("Set Error %s", err)
+	}
+	index, err := s.FreezePrefix(ctx, &schema.KeyPrefix{Prefix: prefix})
+	if err != nil {
+		t.Fatalf("FreezePrefix Error %s", err)
+	}
+	item, err := s.BySafeIndex(ctx, &schema.SafeIndexOptions{Index: index.Index})
+	if err != nil {
+		t.Fatalf("BySafeIndex Error %s", err)
+	}
+	if !bytes.Equal(item.Item.Value, prefix) {
+		t.Fatalf("FreezePrefix, expected freeze entry value %s, got %s", prefix, item.Item.Value)
+	}
+	if _, err = s.Set(ctx, &schema.KeyValue{Key: []byte("frozen1"), Value: testValue}); err != store.ErrPrefixFrozen {
+		t.Fatalf("Set under a frozen prefix, expected %v, got %v", store.ErrPrefixFrozen, err)
+	}
+}
+
+func testServerDelete(ctx context.Context, s *ImmuServer, t *testing.T) {
+	key := []byte("deleted1")
+	if _, err := s.Set(ctx, &schema.KeyValue{Key: key, Value: testValue}); err != nil {
+		t.Fatalf("Set Error %s", err)
+	}
+	index, err := s.Delete(ctx, &schema.Key{Key: key})
+	if err != nil {
+		t.Fatalf("Delete Error %s", err)
+	}
+	item, err := s.BySafeIndex(ctx, &schema.SafeIndexOptions{Index: index.Index})
+	if err != nil {
+		t.Fatalf("BySafeIndex Error %s", err)
+	}
+	if !item.Item.Deleted || !bytes.Equal(item.Item.Key, key) {
+		t.Fatalf("Delete, expected tombstone of %s, got %+v", key, item.Item)
+	}
+	if _, err = s.Get(ctx, &schema.Key{Key: key}); err == nil {
+		t.Fatalf("Get of a deleted key exptected error")
+	}
+}
+
+func testServerDeleteError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.Delete(context.Background(), &schema.Key{Key: []byte("deleted1")})
+	if err == nil {
+		t.Fatalf("Delete exptected error")
+	}
+	_, err = s.Delete(ctx, &schema.Key{Key: []byte("missing")})
+	if err == nil {
+		t.Fatalf("Delete exptected error")
+	}
+}
+
+func testServerUnreference(ctx context.Context, s *ImmuServer, t *testing.T) {
+	if _, err := s.Set(ctx, &schema.KeyValue{Key: []byte("tagged1"), Value: testValue}); err != nil {
+		t.Fatalf("Set Error %s", err)
+	}
+	if _, err := s.Reference(ctx, &schema.ReferenceOptions{Reference: []byte("tag1"), Key: []byte("tagged1")}); err != nil {
+		t.Fatalf("Reference Error %s", err)
+	}
+	index, err := s.Unreference(ctx, &schema.Key{Key: []byte("tag1")})
+	if err != nil {
+		t.Fatalf("Unreference Error %s", err)
+	}
+	item, err := s.BySafeIndex(ctx, &schema.SafeIndexOptions{Index: index.Index})
+	if err != nil {
+		t.Fatalf("BySafeIndex Error %s", err)
+	}
+	if !item.Item.Deleted || !bytes.Equal(item.Item.Key, []byte("tag1")) {
+		t.Fatalf("Unreference, expected tombstone of tag1, got %+v", item.Item)
+	}
+	if _, err = s.GetReference(ctx, &schema.Key{Key: []byte("tag1")}); err != store.ErrKeyNotFound {
+		t.Fatalf("GetReference of a retired reference, expected %v, got %v", store.ErrKeyNotFound, err)
+	}
+	if _, err = s.Unreference(ctx, &schema.Key{Key: []byte("tagged1")}); err != store.ErrNoReferenceProvided {
+		t.Fatalf("Unreference of a key, expected %v, got %v", store.ErrNoReferenceProvided, err)
+	}
+}
+
+func testServerGetReferences(ctx context.Context, s *ImmuServer, t *testing.T) {
+	if _, err := s.Set(ctx, &schema.KeyValue{Key: []byte("aliased"), Value: testValue}); err != nil {
+		t.Fatalf("Set Error %s", err)
+	}
+	for _, tag := range []string{"alias1", "alias2"} {
+		if _, err := s.Reference(ctx, &schema.ReferenceOptions{Reference: []byte(tag), Key: []byte("aliased")}); err != nil {
+			t.Fatalf("Reference Error %s", err)
+		}
+	}
+	list, err := s.GetReferences(ctx, &schema.ReferencesOptions{Key: []byte("aliased")})
+	if err != nil {
+		t.Fatalf("GetReferences Error %s", err)
+	}
+	if len(list.Items) != 2 || !bytes.Equal(list.Items[0].Reference.Reference, []byte("alias1")) ||
+		!bytes.Equal(list.Items[1].Reference.Reference, []byte("alias2")) {
+		t.Fatalf("GetReferences, expected alias1 and alias2, got %+v", list.Items)
+	}
+	if _, err = s.GetReferences(ctx, &schema.ReferencesOptions{}); err != store.ErrInvalidKey {
+		t.Fatalf("GetReferences without key, expected %v, got %v", store.ErrInvalidKey, err)
+	}
+}
+
+func testServerExecAllTx(ctx context.Context, s *ImmuServer, t *testing.T) {
+	ops := &schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte("tx1"), Value: testValue}}},
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte("tx2"), Value: testValue}}},
+	}}
+	if _, err := s.ExecAllTx(ctx, ops); err != nil {
+		t.Fatalf("ExecAllTx Error %s", err)
+	}
+	txProof, err := s.SafeExecAllTx(ctx, &schema.SafeExecAllTxOptions{Ops: ops})
+	if err != nil {
+		t.Fatalf("SafeExecAllTx Error %s", err)
+	}
+	if len(txProof.Entries) != 2 || !bytes.Equal(txProof.Entries[1].Key, []byte("tx2")) {
+		t.Fatalf("SafeExecAllTx, expected the transaction entries, got %+v", txProof.Entries)
+	}
+	if !txProof.Proof.Verify(txProof.Commit.Hash(), schema.Root{}) {
+		t.Fatalf("SafeExecAllTx, proof of the commit entry does not verify")
+	}
+}
+
+func testServerExecAllTxError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.ExecAllTx(context.Background(), &schema.Ops{})
+	if err == nil {
+		t.Fatalf("ExecAllTx exptected error")
+	}
+	_, err = s.ExecAllTx(ctx, &schema.Ops{})
+	if err == nil {
+		t.Fatalf("ExecAllTx exptected error")
+	}
+	_, err = s.SafeExecAllTx(ctx, &schema.SafeExecAllTxOptions{Ops: &schema.Ops{}})
+	if err == nil {
+		t.Fatalf("SafeExecAllTx exptected error")
+	}
+}
+
+func testServerFreezePrefixError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.FreezePrefix(context.Background(), &schema.KeyPrefix{Prefix: []byte("frozen")})
+	if err == nil {
+		t.Fatalf("FreezePrefix exptected error")
+	}
+	_, err = s.FreezePrefix(ctx, &schema.KeyPrefix{})
+	if err == nil {
+		t.Fatalf("FreezePrefix exptected error")
+	}
+}
+
+func testServerAnalyzeStorage(ctx context.Context, s *ImmuServer, t *testing.T) {
+	report, err := s.AnalyzeStorage(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("AnalyzeStorage Error %s", err)
+	}
+	if report.Database != s.Options.GetDefaultDbName() {
+		t.Fatalf("AnalyzeStorage, expected report of %s, got %s", s.Options.GetDefaultDbName(), report.Database)
+	}
+	if report.Entries == 0 || report.TreeLeaves == 0 {
+		t.Fatalf("AnalyzeStorage, expected entries and tree leaves, got %d and %d", report.Entries, report.TreeLeaves)
+	}
+}
+
+func testServerAnalyzeStorageError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.AnalyzeStorage(context.Background(), &emptypb.Empty{})
+	if err == nil {
+		t.Fatalf("AnalyzeStorage exptected error")
+	}
+}
+
+func testServerBackup(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.Backup(ctx, &emptypb.Empty{})
+	require.Equal(t, ErrBackupDisabled, err)
+
+	dir, err := ioutil.TempDir("", "immudb_backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s.Options.BackupDir = dir
+	defer func() { s.Options.BackupDir = "" }()
+
+	root, err := s.CurrentRoot(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	info, err := s.Backup(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, s.Options.GetDefaultDbName(), info.DatabaseName)
+	require.Equal(t, filepath.Join(dir, info.DatabaseName), filepath.Dir(info.File))
+	require.Equal(t, root.GetIndex(), info.Root.GetIndex())
+	require.Equal(t, root.GetRoot(), info.Root.GetRoot())
+
+	f, err := os.Open(info.File)
+	require.NoError(t, err)
+	defer f.Close()
+	fi, err := f.Stat()
+	require.NoError(t, err)
+	require.Equal(t, uint64(fi.Size()), info.Size)
+	header, err := store.ReadBackupHeader(f)
+	require.NoError(t, err)
+	require.Equal(t, info.Root.GetRoot(), header.Root)
+	require.Equal(t, info.Timestamp, header.CreatedAt.UnixNano())
+}
+
+func testServerBackupError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.Backup(context.Background(), &emptypb.Empty{})
+	require.Error(t, err)
+}
+
 func testServerHealth(ctx context.Context, s *ImmuServer, t *testing.T) {
 	h, err := s.Health(ctx, &emptypb.Empty{})
 	if err != nil {
@@ -874,6 +1253,48 @@ func testServerReferenceError(ctx context.Context, s *ImmuServer, t *testing.T)
 	}
 }
 
+func testServerCompareAndReference(ctx context.Context, s *ImmuServer, t *testing.T) {
+	idx, err := s.CompareAndReference(ctx, &schema.CompareAndReferenceOptions{
+		Kv:        kv[0],
+		Reference: []byte(`latestTag`),
+	})
+	if err != nil {
+		t.Fatalf("CompareAndReference error %s", err)
+	}
+	_, err = s.CompareAndReference(ctx, &schema.CompareAndReferenceOptions{
+		Kv:        kv[1],
+		Reference: []byte(`latestTag`),
+	})
+	if err == nil {
+		t.Fatalf("CompareAndReference expected error")
+	}
+	_, err = s.CompareAndReference(ctx, &schema.CompareAndReferenceOptions{
+		Kv:            kv[1],
+		Reference:     []byte(`latestTag`),
+		ExpectedIndex: idx,
+	})
+	if err != nil {
+		t.Fatalf("CompareAndReference error %s", err)
+	}
+	item, err := s.GetReference(ctx, &schema.Key{Key: []byte(`latestTag`)})
+	if err != nil {
+		t.Fatalf("CompareAndReference Get error %s", err)
+	}
+	if !bytes.Equal(item.Value, kv[1].Value) {
+		t.Fatalf("CompareAndReference, expected %v, got %v", string(kv[1].Value), string(item.Value))
+	}
+}
+
+func testServerCompareAndReferenceError(ctx context.Context, s *ImmuServer, t *testing.T) {
+	_, err := s.CompareAndReference(context.Background(), &schema.CompareAndReferenceOptions{
+		Kv:        kv[0],
+		Reference: []byte(`latestTag`),
+	})
+	if err == nil {
+		t.Fatalf("CompareAndReference exptected error")
+	}
+}
+
 func testServerZAdd(ctx context.Context, s *ImmuServer, t *testing.T) {
 	_, err := s.Set(ctx, kv[0])
 	if err != nil {
@@ -899,6 +1320,20 @@ func testServerZAdd(ctx context.Context, s *ImmuServer, t *testing.T) {
 	if !bytes.Equal(item.Items[0].Item.Value, kv[0].Value) {
 		t.Fatalf("Reference, expected %v, got %v", string(kv[0].Value), string(item.Items[0].Item.Value))
 	}
+
+	root, err := s.CurrentRoot(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	safeList, err := s.SafeZScan(ctx, &schema.SafeZScanOptions{
+		Zopts:     &schema.ZScanOptions{Set: kv[0].Value},
+		RootIndex: &schema.Index{Index: root.GetIndex()},
+	})
+	require.NoError(t, err)
+	require.Len(t, safeList.Items, 1)
+	require.True(t, safeList.MultiProof.Verify(*root))
+	leaf, err := store.SortedSetEntryHash(kv[0].Value, safeList.Items[0])
+	require.NoError(t, err)
+	require.True(t, safeList.MultiProof.Includes(safeList.Items[0].Index, leaf))
+	require.True(t, safeList.MultiProof.Includes(safeList.Items[0].Item.Index, safeList.Items[0].Item.Hash()))
 }
 func testServerZAddError(ctx context.Context, s *ImmuServer, t *testing.T) {
 	_, err := s.ZAdd(context.Background(), &schema.ZAddOptions{
@@ -918,6 +1353,8 @@ func testServerZAddError(ctx context.Context, s *ImmuServer, t *testing.T) {
 	if err == nil {
 		t.Fatalf("ZScan expected errr")
 	}
+	_, err = s.SafeZScan(context.Background(), &schema.SafeZScanOptions{Zopts: &schema.ZScanOptions{Set: kv[0].Value}})
+	require.Error(t, err)
 }
 
 func testServerScan(ctx context.Context, s *ImmuServer, t *testing.T) {
@@ -1068,6 +1505,21 @@ func testServerCountError(ctx context.Context, s *ImmuServer, t *testing.T) {
 	}
 }
 
+func testServerStats(ctx context.Context, s *ImmuServer, t *testing.T) {
+	for _, key := range []string{"stats/a", "stats/b", "stats/a"} {
+		_, err := s.Set(ctx, &schema.KeyValue{Key: []byte(key), Value: []byte("value")})
+		require.NoError(t, err)
+	}
+	stats, err := s.Stats(ctx, &schema.KeyPrefix{Prefix: []byte("stats/")})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), stats.LiveKeys)
+	require.Equal(t, uint64(3), stats.Entries)
+	require.True(t, stats.LiveSize < stats.EntriesSize)
+
+	_, err = s.Stats(context.Background(), &schema.KeyPrefix{Prefix: []byte("stats/")})
+	require.Error(t, err)
+}
+
 func testServerPrintTree(ctx context.Context, s *ImmuServer, t *testing.T) {
 	item, err := s.PrintTree(ctx, &emptypb.Empty{})
 	if err != nil {
@@ -1139,6 +1591,11 @@ func TestServerDbOperations(t *testing.T) {
 	testServerByIndexError(ctx, s, t)
 	testServerHistory(ctx, s, t)
 	testServerHistoryError(ctx, s, t)
+	testServerDumpKeyHistory(ctx, s, t)
+	testServerDumpKeyHistoryError(ctx, s, t)
+	testServerGetAt(ctx, s, t)
+	testServerSampleKeys(ctx, s, t)
+	testServerSampleKeysError(ctx, s, t)
 	testServerBySafeIndex(ctx, s, t)
 	testServerBySafeIndexError(ctx, s, t)
 	testServerHealth(ctx, s, t)
@@ -1155,6 +1612,23 @@ func TestServerDbOperations(t *testing.T) {
 	testServerSafeReferenceError(ctx, s, t)
 	testServerCount(ctx, s, t)
 	testServerCountError(ctx, s, t)
+	testServerStats(ctx, s, t)
+	testServerCompareAndReference(ctx, s, t)
+	testServerCompareAndReferenceError(ctx, s, t)
+	testServerDelete(ctx, s, t)
+	testServerDeleteError(ctx, s, t)
+	testServerUnreference(ctx, s, t)
+	testServerGetReferences(ctx, s, t)
+	testServerExecAllTx(ctx, s, t)
+	testServerExecAllTxError(ctx, s, t)
+	testServerFreezePrefix(ctx, s, t)
+	testServerFreezePrefixError(ctx, s, t)
+	testServerAnalyzeStorage(ctx, s, t)
+	testServerAnalyzeStorageError(ctx, s, t)
+	testServerBackup(ctx, s, t)
+	testServerBackupError(ctx, s, t)
+	testServerVerifyValueHash(ctx, s, t)
+	testServerVerifyValueHashError(ctx, s, t)
 }
 
 func TestServerUpdateConfigItem(t *testing.T) {
@@ -1800,3 +2274,287 @@ func TestServerLoginAttempWithEmptyPassword(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid user name or password")
 }
+
+type mockImmuService_LogsServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	entries []*schema.LogEntry
+}
+
+func (m *mockImmuService_LogsServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockImmuService_LogsServer) Send(e *schema.LogEntry) error {
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+type mockImmuService_LogsFollowServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	entries chan *schema.LogEntry
+}
+
+func (m *mockImmuService_LogsFollowServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockImmuService_LogsFollowServer) Send(e *schema.LogEntry) error {
+	select {
+	case m.entries <- e:
+	default:
+	}
+	return nil
+}
+
+func TestServerLogs(t *testing.T) {
+	s := newInmemoryAuthServer()
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+
+	s.Logger.Warningf("server warning")
+	s.componentLogger("mydb").Infof("database info")
+
+	stream := &mockImmuService_LogsServer{ctx: ctx}
+	require.NoError(t, s.Logs(&schema.LogRequest{Level: "warn"}, stream))
+	require.NotEmpty(t, stream.entries)
+	last := stream.entries[len(stream.entries)-1]
+	assert.Equal(t, "warn", last.Level)
+	assert.Equal(t, "server", last.Component)
+	assert.Equal(t, "server warning", last.Message)
+
+	stream = &mockImmuService_LogsServer{ctx: ctx}
+	require.NoError(t, s.Logs(&schema.LogRequest{Components: []string{"mydb"}}, stream))
+	require.Len(t, stream.entries, 1)
+	assert.Equal(t, "database info", stream.entries[0].Message)
+
+	err = s.Logs(&schema.LogRequest{Level: "verbose"}, &mockImmuService_LogsServer{ctx: ctx})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	followCtx, cancel := context.WithCancel(ctx)
+	followStream := &mockImmuService_LogsFollowServer{ctx: followCtx, entries: make(chan *schema.LogEntry, 1)}
+	done := make(chan error)
+	go func() {
+		done <- s.Logs(&schema.LogRequest{Components: []string{"follow"}, Follow: true}, followStream)
+	}()
+	// debug entries are published only once the stream is subscribed
+	assert.Eventually(t, func() bool {
+		s.componentLogger("follow").Debugf("followed entry")
+		select {
+		case e := <-followStream.entries:
+			return e.Message == "followed entry"
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	ctx, err = login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+	s.Options = s.Options.WithAuth(false)
+	assert.Error(t, s.Logs(&schema.LogRequest{}, &mockImmuService_LogsServer{ctx: ctx}))
+}
+
+type mockImmuService_SubscribeServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	changes chan *schema.KeyChange
+}
+
+func (m *mockImmuService_SubscribeServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockImmuService_SubscribeServer) Send(c *schema.KeyChange) error {
+	m.changes <- c
+	return nil
+}
+
+func TestServerSubscribe(t *testing.T) {
+	s := DefaultServer()
+	s = s.WithOptions(s.Options.WithAuth(true).WithInMemoryStore(true)).(*ImmuServer)
+	dbRootpath := DefaultOption().GetDbRootPath()
+	require.NoError(t, s.loadDefaultDatabase(dbRootpath))
+	require.NoError(t, s.loadSystemDatabase(dbRootpath, s.Options.AdminPassword))
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+
+	err = s.Subscribe(&schema.SubscribeRequest{Key: []byte(`k`), Prefix: []byte(`k`)}, &mockImmuService_SubscribeServer{ctx: ctx})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Error(t, s.Subscribe(&schema.SubscribeRequest{}, &mockImmuService_SubscribeServer{ctx: context.Background()}))
+
+	subCtx, cancel := context.WithCancel(ctx)
+	stream := &mockImmuService_SubscribeServer{ctx: subCtx, changes: make(chan *schema.KeyChange, 100)}
+	done := make(chan error)
+	go func() {
+		done <- s.Subscribe(&schema.SubscribeRequest{Key: []byte(`watched`), Proof: true}, stream)
+	}()
+	// changes are sent only once the subscription is established
+	assert.Eventually(t, func() bool {
+		if _, err := s.Set(ctx, &schema.KeyValue{Key: []byte(`watched`), Value: []byte(`v1`)}); err != nil {
+			return false
+		}
+		select {
+		case <-stream.changes:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, time.Millisecond)
+
+	_, err = s.Set(ctx, &schema.KeyValue{Key: []byte(`watched-not`), Value: []byte(`other`)})
+	require.NoError(t, err)
+	index, err := s.Set(ctx, &schema.KeyValue{Key: []byte(`watched`), Value: []byte(`v2`)})
+	require.NoError(t, err)
+	for {
+		change := <-stream.changes
+		require.Equal(t, []byte(`watched`), change.Item.Key)
+		require.True(t, change.Proof.Verify(change.Item.Index, change.Item.Hash()))
+		if change.Item.Index == index.Index {
+			require.Equal(t, []byte(`v2`), change.Item.Value)
+			break
+		}
+	}
+	cancel()
+	require.NoError(t, <-done)
+}
+
+type mockImmuService_TailServer struct {
+	grpc.ServerStream
+	ctx   context.Context
+	items chan *schema.TailItem
+}
+
+func (m *mockImmuService_TailServer) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockImmuService_TailServer) Send(item *schema.TailItem) error {
+	m.items <- item
+	return nil
+}
+
+func TestServerTail(t *testing.T) {
+	s := DefaultServer()
+	s = s.WithOptions(s.Options.WithAuth(true).WithInMemoryStore(true)).(*ImmuServer)
+	dbRootpath := DefaultOption().GetDbRootPath()
+	require.NoError(t, s.loadDefaultDatabase(dbRootpath))
+	require.NoError(t, s.loadSystemDatabase(dbRootpath, s.Options.AdminPassword))
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+	assert.Error(t, s.Tail(&schema.TailRequest{}, &mockImmuService_TailServer{ctx: context.Background()}))
+
+	for _, v := range []string{`v1`, `v2`} {
+		_, err = s.Set(ctx, &schema.KeyValue{Key: []byte(`k`), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	_, err = s.dbList.GetByIndex(DefaultDbIndex).Store.EnforceRetention(store.RetentionPolicy{MaxRevisions: 1}, 0)
+	require.NoError(t, err)
+
+	tailCtx, cancel := context.WithCancel(ctx)
+	stream := &mockImmuService_TailServer{ctx: tailCtx, items: make(chan *schema.TailItem, 100)}
+	done := make(chan error)
+	go func() {
+		done <- s.Tail(&schema.TailRequest{}, stream)
+	}()
+
+	// the pruned entry is proven by its leaf only, and every proof is consistent with the previous one
+	prev := schema.Root{}
+	for i := uint64(0); i < 2; i++ {
+		item := <-stream.items
+		require.Equal(t, i, item.Item.Index)
+		require.Equal(t, i == 0, item.Pruned)
+		leaf := item.Item.Hash()
+		if item.Pruned {
+			require.Nil(t, item.Item.Value)
+			leaf = item.Proof.Leaf
+		}
+		require.True(t, item.Proof.Verify(leaf, prev))
+		prev.SetIndex(item.Proof.At)
+		prev.SetRoot(item.Proof.Root)
+	}
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestServerClock(t *testing.T) {
+	at := time.Unix(1600000000, 0)
+	s := DefaultServer()
+	s = s.WithOptions(s.Options.WithInMemoryStore(true).WithClock(clock.Func(func() time.Time { return at }))).(*ImmuServer)
+	if err := s.setUpClock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.loadDefaultDatabase(DefaultOption().GetDbRootPath()); err != nil {
+		t.Fatal(err)
+	}
+	db := s.dbList.GetByIndex(DefaultDbIndex)
+	defer db.Store.Close()
+
+	index, err := db.Set(&schema.KeyValue{Key: []byte("key"), Value: []byte("value")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := db.Store.IndexTime(*index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ts.Equal(at) {
+		t.Fatalf("entry time expected %v got %v", at, ts)
+	}
+	if !s.now().Equal(at) {
+		t.Fatalf("server time expected %v got %v", at, s.now())
+	}
+	if s.setUpClock() != nil || s.ntp != nil {
+		t.Fatalf("an explicit clock must not be replaced")
+	}
+}
+
+func TestServerSequencer(t *testing.T) {
+	s := DefaultServer()
+	s = s.WithOptions(s.Options.WithAuth(true).WithInMemoryStore(true).WithSequencer(true)).(*ImmuServer)
+	dbRootpath := DefaultOption().GetDbRootPath()
+	if err := s.loadDefaultDatabase(dbRootpath); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.loadSystemDatabase(dbRootpath, s.Options.AdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := s.SetBatch(ctx, &schema.KVList{KVs: []*schema.KeyValue{kv[0], kv[1]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Sequence != 1 {
+		t.Fatalf("SetBatch, expected sequence %d, got %d", 1, index.Sequence)
+	}
+	proof, err := s.SafeSet(ctx, &schema.SafeSetOptions{Kv: kv[2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.Sequence != 2 {
+		t.Fatalf("SafeSet, expected sequence %d, got %d", 2, proof.Sequence)
+	}
+
+	write, err := s.GetBySequence(ctx, &schema.Sequence{Sequence: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if write.FirstIndex != 0 || write.LastIndex != 1 || len(write.Items) != 2 {
+		t.Fatalf("GetBySequence, unexpected write %v", write)
+	}
+	if !bytes.Equal(write.Items[1].Key, kv[1].Key) {
+		t.Fatalf("GetBySequence, expected key %s, got %s", kv[1].Key, write.Items[1].Key)
+	}
+	if _, err = s.GetBySequence(ctx, &schema.Sequence{Sequence: 3}); err != store.ErrSequenceNotFound {
+		t.Fatalf("GetBySequence, expected error %v, got %v", store.ErrSequenceNotFound, err)
+	}
+	if _, err = s.GetBySequence(context.Background(), &schema.Sequence{Sequence: 1}); err == nil {
+		t.Fatalf("GetBySequence exptected error")
+	}
+}
@@ -65,3 +65,9 @@ func (bs *bufconnServer) Start() {
 	}()
 	bs.Server.Start()
 }
+
+// Stop stops serving and closes the databases of the server
+func (bs *bufconnServer) Stop() error {
+	bs.GrpcServer.Stop()
+	return bs.Server.CloseDatabases()
+}
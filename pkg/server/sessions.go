@@ -0,0 +1,175 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// maxSessionAddresses is the number of distinct addresses remembered per token
+const maxSessionAddresses = 16
+
+// sessionSweepInterval is the period between removals of the sessions of expired tokens
+const sessionSweepInterval = time.Minute
+
+// sessionTracker records the usage of the authentication tokens: when, from where and for which RPC they were used.
+// Tokens themselves are never retained, sessions are identified by a digest of them.
+type sessionTracker struct {
+	sync.Mutex
+	sessions  map[string]*schema.Session
+	lastSweep time.Time
+}
+
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{sessions: make(map[string]*schema.Session)}
+}
+
+// track records a call authenticated with token. If the call came from an address the token was never used from,
+// while it had already been used from other ones, these are returned.
+func (t *sessionTracker) track(token string, jsToken *auth.JSONToken, address string, method string, now time.Time) (id string, previousAddresses []string) {
+	t.Lock()
+	defer t.Unlock()
+
+	// expired tokens can not be used anymore, so their sessions are not worth keeping
+	if now.Sub(t.lastSweep) >= sessionSweepInterval {
+		for id, s := range t.sessions {
+			if s.Expiration < now.Unix() {
+				delete(t.sessions, id)
+			}
+		}
+		t.lastSweep = now
+	}
+
+	id = sessionID(token)
+	s, ok := t.sessions[id]
+	if !ok {
+		s = &schema.Session{
+			Id:         id,
+			User:       jsToken.Username,
+			FirstSeen:  now.Unix(),
+			Expiration: jsToken.Expiration.Unix(),
+		}
+		t.sessions[id] = s
+	}
+	s.LastSeen = now.Unix()
+	s.LastAddress = address
+	s.LastMethod = method
+	s.Calls++
+
+	known := false
+	for _, a := range s.Addresses {
+		if a == address {
+			known = true
+			break
+		}
+	}
+	if !known {
+		previousAddresses = append(previousAddresses, s.Addresses...)
+		if len(s.Addresses) < maxSessionAddresses {
+			s.Addresses = append(s.Addresses, address)
+		}
+	}
+	return id, previousAddresses
+}
+
+// list returns the sessions still in use, the most recently seen first
+func (t *sessionTracker) list(now time.Time) []*schema.Session {
+	t.Lock()
+	defer t.Unlock()
+
+	sessions := make([]*schema.Session, 0, len(t.sessions))
+	for _, s := range t.sessions {
+		if s.Expiration < now.Unix() {
+			continue
+		}
+		sessions = append(sessions, proto.Clone(s).(*schema.Session))
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].LastSeen != sessions[j].LastSeen {
+			return sessions[i].LastSeen > sessions[j].LastSeen
+		}
+		return sessions[i].Id < sessions[j].Id
+	})
+	return sessions
+}
+
+func sessionID(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:8])
+}
+
+// trackSession records the usage of the token authenticating the request in ctx
+func (s *ImmuServer) trackSession(ctx context.Context, jsToken *auth.JSONToken) {
+	if s.sessions == nil {
+		return
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["authorization"]) == 0 {
+		return
+	}
+	token := strings.TrimPrefix(md["authorization"][0], "Bearer ")
+
+	address := "unknown"
+	if p, ok := peer.FromContext(ctx); ok && p != nil && p.Addr != nil {
+		address = p.Addr.String()
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			address = host
+		}
+	}
+	method, _ := grpc.Method(ctx)
+
+	id, previousAddresses := s.sessions.track(token, jsToken, address, method, s.now())
+	if len(previousAddresses) > 0 && s.Options.AlertNewTokenIP {
+		s.Logger.Warningf(
+			"token %s of user %s used from the new address %s by %s, it was used from %s before: it may have been leaked",
+			id, jsToken.Username, address, method, strings.Join(previousAddresses, ", "))
+	}
+}
+
+// ListSessions returns the usage of the authentication tokens in use: when, from where and for which RPC they were last used
+func (s *ImmuServer) ListSessions(ctx context.Context, req *empty.Empty) (*schema.SessionList, error) {
+	s.Logger.Debugf("ListSessions")
+	if !s.Options.GetAuth() {
+		return nil, fmt.Errorf("this command is available only with authentication on")
+	}
+	_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Please login")
+	}
+	if !user.IsSysAdmin {
+		return nil, status.Errorf(codes.PermissionDenied, "only the system admin can list sessions")
+	}
+	return &schema.SessionList{Sessions: s.sessions.list(s.now())}, nil
+}
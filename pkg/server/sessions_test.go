@@ -0,0 +1,91 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+)
+
+func TestSessionTracker(t *testing.T) {
+	tracker := newSessionTracker()
+	now := time.Unix(1000, 0)
+	jsToken := &auth.JSONToken{Username: "user1", Expiration: now.Add(time.Hour)}
+
+	id, previous := tracker.track("token1", jsToken, "10.0.0.1", "/immudb.schema.ImmuService/Set", now)
+	require.Equal(t, sessionID("token1"), id)
+	require.Empty(t, previous)
+	_, previous = tracker.track("token1", jsToken, "10.0.0.1", "/immudb.schema.ImmuService/Get", now.Add(time.Second))
+	require.Empty(t, previous)
+	_, previous = tracker.track("token1", jsToken, "10.0.0.2", "/immudb.schema.ImmuService/Get", now.Add(2*time.Second))
+	require.Equal(t, []string{"10.0.0.1"}, previous)
+	tracker.track("token2", &auth.JSONToken{Username: "user2", Expiration: now.Add(time.Minute)}, "10.0.0.3", "", now)
+
+	sessions := tracker.list(now.Add(3 * time.Second))
+	require.Len(t, sessions, 2)
+	require.Equal(t, "user1", sessions[0].User)
+	require.Equal(t, now.Unix(), sessions[0].FirstSeen)
+	require.Equal(t, now.Add(2*time.Second).Unix(), sessions[0].LastSeen)
+	require.Equal(t, "10.0.0.2", sessions[0].LastAddress)
+	require.Equal(t, "/immudb.schema.ImmuService/Get", sessions[0].LastMethod)
+	require.Equal(t, uint64(3), sessions[0].Calls)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, sessions[0].Addresses)
+	require.Equal(t, "user2", sessions[1].User)
+
+	// the sessions of expired tokens are not listed and are eventually dropped
+	require.Len(t, tracker.list(now.Add(2*time.Minute)), 1)
+	tracker.track("token1", jsToken, "10.0.0.2", "", now.Add(2*time.Minute))
+	require.Len(t, tracker.sessions, 1)
+}
+
+func TestServerListSessions(t *testing.T) {
+	var logs bytes.Buffer
+	s := newInmemoryAuthServer()
+	s.WithOptions(s.Options.WithAlertNewTokenIP(true))
+	s.WithLogger(logger.NewSimpleLogger("test", &logs))
+
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+
+	fromAddress := func(ip string) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 4000}})
+	}
+
+	sessions, err := s.ListSessions(fromAddress("10.0.0.1"), &empty.Empty{})
+	require.NoError(t, err)
+	require.Len(t, sessions.Sessions, 1)
+	require.Equal(t, auth.SysAdminUsername, sessions.Sessions[0].User)
+	require.Equal(t, "10.0.0.1", sessions.Sessions[0].LastAddress)
+	require.NotContains(t, logs.String(), "new address")
+
+	sessions, err = s.ListSessions(fromAddress("10.0.0.2"), &empty.Empty{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, sessions.Sessions[0].Addresses)
+	require.Contains(t, logs.String(), "used from the new address 10.0.0.2")
+
+	_, err = s.ListSessions(context.Background(), &empty.Empty{})
+	require.Error(t, err)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync/atomic"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TamperPolicy is what the server does once its own checks detect that a database may have been tampered with
+type TamperPolicy string
+
+// Tamper policies
+const (
+	// TamperPolicyRefuse refuses all traffic until the server is restarted
+	TamperPolicyRefuse TamperPolicy = "refuse"
+	// TamperPolicyReadOnly keeps serving reads, so that the data can still be inspected and audited, refusing writes
+	TamperPolicyReadOnly TamperPolicy = "read-only"
+	// TamperPolicyAlert only logs the detection and counts it in the tamper_detections_total metric
+	TamperPolicyAlert TamperPolicy = "alert"
+)
+
+// sources of the detections, the values of the source label of the tamper detections metric
+const (
+	tamperSourceStartup = "startup-check"
+	tamperSourceChecker = "consistency-checker"
+)
+
+// ParseTamperPolicy returns the tamper policy named policy
+func ParseTamperPolicy(policy string) (TamperPolicy, error) {
+	switch p := TamperPolicy(policy); p {
+	case TamperPolicyRefuse, TamperPolicyReadOnly, TamperPolicyAlert:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid tamper policy %q: must be one of %s, %s, %s",
+		policy, TamperPolicyRefuse, TamperPolicyReadOnly, TamperPolicyAlert)
+}
+
+// ErrTamperReadOnly is returned to writes once a possible tampering has been detected under the read-only policy
+var ErrTamperReadOnly = status.Error(codes.DataLoss,
+	"the database should be checked manually as we detected possible tampering: the server only serves reads")
+
+// tamperResponder applies the tamper policy to the detections of the startup check and of the consistency checker.
+// Detections are never cleared: the server must be restarted once the data has been checked.
+type tamperResponder struct {
+	policy   TamperPolicy
+	Logger   logger.Logger
+	readOnly int32
+}
+
+func newTamperResponder(policy TamperPolicy, l logger.Logger) *tamperResponder {
+	return &tamperResponder{policy: policy, Logger: l}
+}
+
+// detected applies the policy to a possible tampering of database found by source
+func (r *tamperResponder) detected(source string, database string, reason string) {
+	Metrics.TamperDetectionsCounters.WithLabelValues(database, source).Inc()
+	r.Logger.Errorf("possible tampering of database %s detected by %s: %s. Applying the %s tamper policy",
+		database, source, reason, r.policy)
+	switch r.policy {
+	case TamperPolicyReadOnly:
+		atomic.StoreInt32(&r.readOnly, 1)
+	case TamperPolicyAlert:
+	default:
+		auth.IsTampered = true
+	}
+}
+
+// refusesWrites tells whether writes are refused under the read-only policy
+func (r *tamperResponder) refusesWrites() bool {
+	return atomic.LoadInt32(&r.readOnly) == 1
+}
+
+// TamperInterceptor refuses the writes once a possible tampering has been detected under the read-only policy,
+// refusing all traffic under the refuse policy is up to auth.ServerUnaryInterceptor
+func (s *ImmuServer) TamperInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if s.tamper != nil && s.tamper.refusesWrites() && usageWriteMethods[path.Base(info.FullMethod)] {
+		return nil, ErrTamperReadOnly
+	}
+	return handler(ctx, req)
+}
+
+// selfCheck verifies, before serving any request, that the latest entry of every database is consistent with its
+// value and included in the current root, as tampering with the most recent data is the hardest to notice otherwise.
+// The whole history is then verified by the consistency checker, if enabled.
+func (s *ImmuServer) selfCheck() {
+	for i := 0; i < s.dbList.Length(); i++ {
+		db := s.dbList.GetByIndex(int64(i))
+		name := db.options.GetDbName()
+		root, err := db.Store.CurrentRoot()
+		if err != nil {
+			s.Logger.Errorf("startup check of database %s: unable to retrieve the root: %s", name, err)
+			continue
+		}
+		if root.GetRoot() == nil {
+			continue
+		}
+		item, err := db.Store.BySafeIndex(schema.SafeIndexOptions{
+			Index:     root.GetIndex(),
+			RootIndex: &schema.Index{Index: root.GetIndex()},
+		})
+		if err == store.ErrInconsistentDigest {
+			s.tamper.detected(tamperSourceStartup, name, fmt.Sprintf("insertion order index %d was tampered", root.GetIndex()))
+			continue
+		}
+		if err != nil {
+			s.Logger.Errorf("startup check of database %s: unable to retrieve the entry at index %d: %s", name, root.GetIndex(), err)
+			continue
+		}
+		if !item.Proof.Verify(item.Proof.Leaf, *root) {
+			s.tamper.detected(tamperSourceStartup, name, fmt.Sprintf(ErrConsistencyFail, item.Item.Index))
+		}
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestParseTamperPolicy(t *testing.T) {
+	for _, p := range []TamperPolicy{TamperPolicyRefuse, TamperPolicyReadOnly, TamperPolicyAlert} {
+		policy, err := ParseTamperPolicy(string(p))
+		require.NoError(t, err)
+		require.Equal(t, p, policy)
+	}
+	_, err := ParseTamperPolicy("shutdown")
+	require.Error(t, err)
+	require.Equal(t, TamperPolicyRefuse, DefaultOptions().TamperPolicy)
+}
+
+func TestTamperResponder(t *testing.T) {
+	// the corruption checker tests leave it set
+	auth.IsTampered = false
+	defer func() { auth.IsTampered = false }()
+
+	s := DefaultServer()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	set := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/Set"}
+	get := &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/Get"}
+
+	// the interceptor lets everything through before the server starts
+	_, err := s.TamperInterceptor(context.TODO(), nil, set, handler)
+	require.NoError(t, err)
+
+	s.tamper = newTamperResponder(TamperPolicyAlert, &mockLogger{})
+	s.tamper.detected(tamperSourceChecker, "db", "reason")
+	require.False(t, auth.IsTampered)
+	_, err = s.TamperInterceptor(context.TODO(), nil, set, handler)
+	require.NoError(t, err)
+
+	s.tamper = newTamperResponder(TamperPolicyReadOnly, &mockLogger{})
+	s.tamper.detected(tamperSourceChecker, "db", "reason")
+	require.False(t, auth.IsTampered)
+	_, err = s.TamperInterceptor(context.TODO(), nil, set, handler)
+	require.Equal(t, ErrTamperReadOnly, err)
+	resp, err := s.TamperInterceptor(context.TODO(), nil, get, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	s.tamper = newTamperResponder(TamperPolicyRefuse, &mockLogger{})
+	s.tamper.detected(tamperSourceChecker, "db", "reason")
+	require.True(t, auth.IsTampered)
+}
+
+func TestTamperPolicySelfCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tamper_policy_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	options := DefaultOption().WithDbName("tampered").WithDbRootPath(dir)
+	db, err := NewDb(options, &mockLogger{})
+	require.NoError(t, err)
+	key := []byte(`key`)
+	_, err = db.Set(&schema.KeyValue{Key: key, Value: []byte(`value`)})
+	require.NoError(t, err)
+
+	s := DefaultServer()
+	s.dbList.Append(db)
+	s.tamper = newTamperResponder(TamperPolicyReadOnly, &mockLogger{})
+	s.selfCheck()
+	require.False(t, s.tamper.refusesWrites())
+	db.Store.Close()
+
+	// the value of the entry is overwritten on disk, keeping its timestamp
+	bdb, err := badger.OpenManaged(badger.DefaultOptions(dir + "/tampered").WithLogger(nil))
+	require.NoError(t, err)
+	txn := bdb.NewTransactionAt(math.MaxUint64, true)
+	item, err := txn.Get(key)
+	require.NoError(t, err)
+	value, err := item.ValueCopy(nil)
+	require.NoError(t, err)
+	require.NoError(t, txn.Set(key, append(value[:8], `forged`...)))
+	require.NoError(t, txn.CommitAt(item.Version(), nil))
+	require.NoError(t, bdb.Close())
+
+	db, err = OpenDb(options, &mockLogger{})
+	require.NoError(t, err)
+	defer db.Store.Close()
+	s = DefaultServer()
+	s.dbList.Append(db)
+	s.tamper = newTamperResponder(TamperPolicyReadOnly, &mockLogger{})
+	s.selfCheck()
+	require.True(t, s.tamper.refusesWrites())
+
+	// the consistency checker detects it as well
+	s.tamper = newTamperResponder(TamperPolicyReadOnly, &mockLogger{})
+	cc := NewCorruptionChecker(CCOptions{
+		singleiteration:    true,
+		iterationSleepTime: time.Millisecond,
+		frequencySleepTime: time.Millisecond,
+		onTamper: func(database string, reason string) {
+			require.Equal(t, "tampered", database)
+			s.tamper.detected(tamperSourceChecker, database, reason)
+		},
+	}, s.dbList, &mockLogger{}, randomGenerator{})
+	require.Error(t, cc.Start(context.TODO()))
+	require.True(t, s.tamper.refusesWrites())
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/immudb/pkg/store"
+)
+
+// segmentTierer periodically moves the old value log segments of every database to cold storage, under a directory
+// named after the database, and deletes from there the segments collected since. Segments recalled with the
+// recall-segments command stay local for MinAge before being moved again.
+type segmentTierer struct {
+	dbList   DatabaseList
+	Logger   logger.Logger
+	interval time.Duration
+	coldDir  string
+	minAge   time.Duration
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newSegmentTierer(d DatabaseList, l logger.Logger, interval time.Duration, coldDir string, minAge time.Duration) *segmentTierer {
+	return &segmentTierer{
+		dbList:   d,
+		Logger:   l,
+		interval: interval,
+		coldDir:  coldDir,
+		minAge:   minAge,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start runs the tiering loop in a new goroutine
+func (r *segmentTierer) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.quit:
+				return
+			case <-ticker.C:
+				r.tier()
+			}
+		}
+	}()
+}
+
+// Stop terminates the tiering loop and waits for the current iteration, if any, to complete
+func (r *segmentTierer) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *segmentTierer) tier() {
+	for i := 0; i < r.dbList.Length(); i++ {
+		db := r.dbList.GetByIndex(int64(i))
+		released, err := db.Store.ReleaseSegments()
+		if err != nil {
+			r.Logger.Errorf("unable to release the collected segments of database %s: %v", db.options.GetDbName(), err)
+		}
+		for _, s := range released {
+			r.Logger.Infof("segment %s of database %s collected, deleted from %s", s.Name, db.options.GetDbName(), s.Path)
+		}
+		tiered, err := db.Store.TierSegments(store.TieringOptions{
+			ColdDir:   filepath.Join(r.coldDir, db.options.GetDbName()),
+			MinAge:    r.minAge,
+			RecallTTL: r.minAge,
+		})
+		if err != nil {
+			r.Logger.Errorf("unable to move the segments of database %s to cold storage: %v", db.options.GetDbName(), err)
+		}
+		for _, s := range tiered {
+			r.Logger.Infof("segment %s of database %s moved to %s", s.Name, db.options.GetDbName(), s.Path)
+		}
+	}
+}
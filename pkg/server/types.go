@@ -24,6 +24,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/clock"
 	"github.com/codenotary/immudb/pkg/immuos"
 	"github.com/codenotary/immudb/pkg/logger"
 )
@@ -57,23 +58,40 @@ type ImmuServer struct {
 	userdata            *usernameToUserdataMap
 	multidbmode         bool
 	Cc                  CorruptionChecker
+	countReconciler     *countReconciler
+	retentionEnforcer   *retentionEnforcer
+	segmentTierer       *segmentTierer
 	sysDb               *Db
 	metricsServer       *http.Server
 	mux                 sync.Mutex
 	RootSigner          RootSigner
+	logTail             *logger.Tail
+	ntp                 *clock.NTP
+	sessions            *sessionTracker
+	usage               *usageTracker
+	tamper              *tamperResponder
+	faults              *faultInjector
+	encryptionKey       []byte
 }
 
+// logTailSize is the number of recent log entries retained for remote tailing
+const logTailSize = 1000
+
 // DefaultServer ...
 func DefaultServer() *ImmuServer {
+	logTail := logger.NewTail(logTailSize)
 	return &ImmuServer{
 		OS:                  immuos.NewStandardOS(),
 		dbList:              NewDatabaseList(),
-		Logger:              logger.NewSimpleLogger("immudb ", os.Stderr),
+		Logger:              logger.NewTailLogger(logger.NewSimpleLogger("immudb ", os.Stderr), "server", logTail),
+		logTail:             logTail,
 		Options:             DefaultOptions(),
 		quit:                make(chan struct{}),
 		databasenameToIndex: make(map[string]int64),
 		userdata:            &usernameToUserdataMap{Userdata: make(map[string]*auth.User)},
 		GrpcServer:          grpc.NewServer(),
+		sessions:            newSessionTracker(),
+		usage:               newUsageTracker(),
 	}
 }
 
@@ -86,8 +104,11 @@ type ImmuServerIf interface {
 }
 
 // WithLogger ...
-func (s *ImmuServer) WithLogger(logger logger.Logger) ImmuServerIf {
-	s.Logger = logger
+func (s *ImmuServer) WithLogger(l logger.Logger) ImmuServerIf {
+	s.Logger = l
+	if s.logTail != nil {
+		s.Logger = logger.NewTailLogger(l, "server", s.logTail)
+	}
 	return s
 }
 
@@ -0,0 +1,249 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// usageGranularity is the time span of the usage buckets, usage windows are rounded to it
+const usageGranularity = time.Minute
+
+// usageRetention is for how long the recorded usage can be read back, billing pipelines are expected
+// to either poll it more frequently or scrape the usage metrics
+const usageRetention = 24 * time.Hour
+
+// usage kinds, the values of the kind label of the operations metric
+const (
+	usageRead  = "read"
+	usageWrite = "write"
+	usageAdmin = "admin"
+)
+
+// usageWriteMethods are the methods adding entries to a database
+var usageWriteMethods = map[string]bool{
+	"Set":                 true,
+	"SafeSet":             true,
+	"SetBatch":            true,
+	"ExecAllOps":          true,
+	"ExecAllTx":           true,
+	"SafeExecAllTx":       true,
+	"CompareAndExecAllTx": true,
+	"Delete":              true,
+	"Reference":           true,
+	"SafeReference":       true,
+	"CompareAndReference": true,
+	"Unreference":         true,
+	"ZAdd":                true,
+	"SafeZAdd":            true,
+	"FreezePrefix":        true,
+}
+
+// usageKind classifies method as a write, as a read if also allowed to users having the read-write
+// permission, or as an admin operation otherwise
+func usageKind(method string) string {
+	switch {
+	case usageWriteMethods[method]:
+		return usageWrite
+	case auth.HasPermissionForMethod(auth.PermissionRW, method):
+		return usageRead
+	default:
+		return usageAdmin
+	}
+}
+
+type usageKey struct {
+	database string
+	user     string
+}
+
+// usageTracker records the usage of the databases in buckets of usageGranularity, retained for usageRetention
+type usageTracker struct {
+	sync.Mutex
+	buckets map[int64]map[usageKey]*schema.Usage
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{buckets: make(map[int64]map[usageKey]*schema.Usage)}
+}
+
+func usageBucket(t time.Time) int64 {
+	return t.Truncate(usageGranularity).Unix()
+}
+
+func (t *usageTracker) record(now time.Time, database, user, kind string, writtenBytes int) {
+	t.Lock()
+	defer t.Unlock()
+
+	bucket := usageBucket(now)
+	usages, ok := t.buckets[bucket]
+	if !ok {
+		// a new bucket is started at most once per usageGranularity, the right time to drop the expired ones
+		for b := range t.buckets {
+			if b <= usageBucket(now.Add(-usageRetention)) {
+				delete(t.buckets, b)
+			}
+		}
+		usages = make(map[usageKey]*schema.Usage)
+		t.buckets[bucket] = usages
+	}
+	key := usageKey{database: database, user: user}
+	u, ok := usages[key]
+	if !ok {
+		u = &schema.Usage{Database: database, User: user}
+		usages[key] = u
+	}
+	switch kind {
+	case usageRead:
+		u.Reads++
+	case usageWrite:
+		u.Writes++
+		u.WrittenBytes += uint64(writtenBytes)
+	default:
+		u.Admin++
+	}
+}
+
+// report sums the usage of database (or of all the databases, if empty) recorded from since to until
+func (t *usageTracker) report(database string, since, until time.Time) *schema.UsageReport {
+	t.Lock()
+	defer t.Unlock()
+
+	from, to := usageBucket(since), usageBucket(until)
+	totals := make(map[usageKey]*schema.Usage)
+	for bucket, usages := range t.buckets {
+		if bucket < from || bucket > to {
+			continue
+		}
+		for key, u := range usages {
+			if database != "" && key.database != database {
+				continue
+			}
+			total, ok := totals[key]
+			if !ok {
+				total = &schema.Usage{Database: key.database, User: key.user}
+				totals[key] = total
+			}
+			total.Reads += u.Reads
+			total.Writes += u.Writes
+			total.Admin += u.Admin
+			total.WrittenBytes += u.WrittenBytes
+		}
+	}
+	report := &schema.UsageReport{
+		Since: from,
+		Until: to + int64(usageGranularity/time.Second),
+		Usage: make([]*schema.Usage, 0, len(totals)),
+	}
+	for _, u := range totals {
+		report.Usage = append(report.Usage, u)
+	}
+	sort.Slice(report.Usage, func(i, j int) bool {
+		if report.Usage[i].Database != report.Usage[j].Database {
+			return report.Usage[i].Database < report.Usage[j].Database
+		}
+		return report.Usage[i].User < report.Usage[j].User
+	})
+	return report
+}
+
+// UsageInterceptor records the usage of the databases made by the successful calls
+func (s *ImmuServer) UsageInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		s.recordUsage(ctx, path.Base(info.FullMethod), req)
+	}
+	return resp, err
+}
+
+func (s *ImmuServer) recordUsage(ctx context.Context, method string, req interface{}) {
+	if s.usage == nil {
+		return
+	}
+	ind := int64(DefaultDbIndex)
+	user := ""
+	if s.Options.GetAuth() {
+		jsToken, err := auth.GetLoggedInUser(ctx)
+		if err != nil {
+			// calls not bound to a database, like logins or health checks
+			return
+		}
+		ind, user = jsToken.DatabaseIndex, jsToken.Username
+	}
+	if ind < 0 || ind >= int64(s.dbList.Length()) {
+		return
+	}
+	if !s.Options.UsagePerUser {
+		user = ""
+	}
+	database := s.dbList.GetByIndex(ind).options.GetDbName()
+	kind := usageKind(method)
+	writtenBytes := 0
+	if msg, ok := req.(proto.Message); ok && kind == usageWrite {
+		writtenBytes = proto.Size(msg)
+	}
+
+	s.usage.record(s.now(), database, user, kind, writtenBytes)
+	Metrics.OperationsPerDatabaseCounters.WithLabelValues(database, user, kind).Inc()
+	if kind == usageWrite {
+		Metrics.WrittenBytesPerDatabaseCounters.WithLabelValues(database, user).Add(float64(writtenBytes))
+	}
+}
+
+// GetUsage returns the usage of the databases in a time window, by default the last usageRetention.
+// The system admin can read the usage of every database, admins only the one of the databases they administer.
+func (s *ImmuServer) GetUsage(ctx context.Context, req *schema.UsageRequest) (*schema.UsageReport, error) {
+	s.Logger.Debugf("GetUsage %s", req.Database)
+	if s.Options.GetAuth() {
+		_, user, err := s.getLoggedInUserdataFromCtx(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "Please login")
+		}
+		if !user.IsSysAdmin {
+			if req.Database == "" {
+				return nil, status.Errorf(codes.PermissionDenied, "only the system admin can read the usage of all databases")
+			}
+			if user.WhichPermission(req.Database) != auth.PermissionAdmin {
+				return nil, status.Errorf(codes.PermissionDenied, "you do not have permission to read the usage of database %s", req.Database)
+			}
+		}
+	}
+	now := s.now()
+	since, until := now.Add(-usageRetention), now
+	if req.Since != 0 {
+		since = time.Unix(req.Since, 0)
+	}
+	if req.Until != 0 {
+		until = time.Unix(req.Until, 0)
+	}
+	if until.Before(since) {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("usage window ends (%d) before it starts (%d)", until.Unix(), since.Unix()))
+	}
+	return s.usage.report(req.Database, since, until), nil
+}
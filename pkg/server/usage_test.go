@@ -0,0 +1,113 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/auth"
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestUsageTracker(t *testing.T) {
+	require.Equal(t, usageWrite, usageKind("SafeSet"))
+	require.Equal(t, usageRead, usageKind("GetBatch"))
+	require.Equal(t, usageAdmin, usageKind("CreateUser"))
+
+	tracker := newUsageTracker()
+	now := time.Unix(1600000000, 0)
+	tracker.record(now, "db1", "", usageWrite, 10)
+	tracker.record(now.Add(time.Minute), "db1", "", usageWrite, 5)
+	tracker.record(now.Add(time.Minute), "db1", "", usageRead, 0)
+	tracker.record(now.Add(time.Minute), "db2", "user", usageAdmin, 0)
+
+	report := tracker.report("", now, now.Add(time.Minute))
+	require.Equal(t, []*schema.Usage{
+		{Database: "db1", Reads: 1, Writes: 2, WrittenBytes: 15},
+		{Database: "db2", User: "user", Admin: 1},
+	}, report.Usage)
+	require.Equal(t, usageBucket(now), report.Since)
+	require.Equal(t, usageBucket(now)+120, report.Until)
+
+	report = tracker.report("db1", now.Add(time.Minute), now.Add(time.Hour))
+	require.Equal(t, []*schema.Usage{{Database: "db1", Reads: 1, Writes: 1, WrittenBytes: 5}}, report.Usage)
+
+	// expired buckets are dropped when a new one is started
+	tracker.record(now.Add(usageRetention+time.Minute), "db1", "", usageRead, 0)
+	require.Len(t, tracker.buckets, 1)
+	require.Empty(t, tracker.report("", now, now.Add(time.Minute)).Usage)
+}
+
+func TestServerGetUsage(t *testing.T) {
+	at := time.Unix(1600000000, 0)
+	s := newInmemoryAuthServer()
+	s.WithOptions(s.Options.WithUsagePerUser(true).WithClock(clock.Func(func() time.Time { return at })))
+
+	ctx, err := login(s, auth.SysAdminUsername, auth.SysAdminPassword)
+	require.NoError(t, err)
+	_, err = s.CreateUser(ctx, &schema.CreateUserRequest{
+		User:       []byte("rwuser"),
+		Password:   []byte("rwuserPas@1"),
+		Database:   DefaultdbName,
+		Permission: auth.PermissionRW,
+	})
+	require.NoError(t, err)
+	rwCtx, err := login(s, "rwuser", "rwuserPas@1")
+	require.NoError(t, err)
+	rwCtx, err = usedatabase(rwCtx, s, DefaultdbName)
+	require.NoError(t, err)
+
+	call := func(ctx context.Context, method string, req interface{}, handler grpc.UnaryHandler) {
+		_, err := s.UsageInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/" + method}, handler)
+		require.NoError(t, err)
+	}
+	kv := &schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)}
+	call(rwCtx, "Set", kv, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.Set(ctx, req.(*schema.KeyValue))
+	})
+	call(rwCtx, "Get", &schema.Key{Key: kv.Key}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.Get(ctx, req.(*schema.Key))
+	})
+	// failed calls are not accounted
+	_, err = s.UsageInterceptor(rwCtx, &schema.Key{Key: []byte(`missing`)}, &grpc.UnaryServerInfo{FullMethod: "/immudb.schema.ImmuService/Get"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.Get(ctx, req.(*schema.Key))
+		})
+	require.Error(t, err)
+
+	report, err := s.GetUsage(ctx, &schema.UsageRequest{Database: DefaultdbName})
+	require.NoError(t, err)
+	require.Equal(t, []*schema.Usage{
+		{Database: DefaultdbName, User: "rwuser", Reads: 1, Writes: 1, WrittenBytes: uint64(proto.Size(kv))},
+	}, report.Usage)
+
+	// usage can be read by the system admin and by the admins of the database only
+	_, err = s.GetUsage(rwCtx, &schema.UsageRequest{Database: DefaultdbName})
+	require.Error(t, err)
+	_, err = s.GetUsage(rwCtx, &schema.UsageRequest{})
+	require.Error(t, err)
+	_, err = s.GetUsage(context.Background(), &schema.UsageRequest{})
+	require.Error(t, err)
+	_, err = s.GetUsage(ctx, &schema.UsageRequest{Since: at.Unix(), Until: at.Add(-time.Hour).Unix()})
+	require.Error(t, err)
+}
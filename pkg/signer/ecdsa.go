@@ -57,6 +57,33 @@ func NewSigner(privateKeyPath string) (Signer, error) {
 	return signer{rand: rand.Reader, privateKey: privateKey}, nil
 }
 
+// ParsePublicKey returns the P-256 public key in a PEM encoded PKIX block, in the format roots are signed with.
+// To extract the public key of a signing key use openssl tool. Ex: openssl ec -in ec.key -pubout -out ec.pub
+func ParsePublicKey(pemBytes []byte) ([]byte, error) {
+	publicKeyBlock, _ := pem.Decode(pemBytes)
+	if publicKeyBlock == nil {
+		return nil, errors.New("no public key found in provided PEM data")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(publicKeyBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok || ecdsaKey.Curve != elliptic.P256() {
+		return nil, errors.New("public key is not an ecdsa P-256 key")
+	}
+	return elliptic.Marshal(ecdsaKey.Curve, ecdsaKey.X, ecdsaKey.Y), nil
+}
+
+// LoadPublicKey reads the PEM encoded public key file at publicKeyPath, see ParsePublicKey
+func LoadPublicKey(publicKeyPath string) ([]byte, error) {
+	pemBytes, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(pemBytes)
+}
+
 // NewSignerFromPKey returns a signer from a io.Reader and a *ecdsa.PrivateKey.
 func NewSignerFromPKey(r io.Reader, pk *ecdsa.PrivateKey) Signer {
 	return signer{rand: r, privateKey: pk}
@@ -117,3 +117,24 @@ func TestSignature_VerifyFalse(t *testing.T) {
 	assert.False(t, ok)
 	assert.NoError(t, err)
 }
+
+func TestLoadPublicKey(t *testing.T) {
+	publicKey, err := LoadPublicKey("./../../test/signer/ec3.pub")
+	assert.NoError(t, err)
+
+	s, err := NewSigner("./../../test/signer/ec3.key")
+	assert.NoError(t, err)
+	signature, signerPublicKey, err := s.Sign([]byte(`payload`))
+	assert.NoError(t, err)
+	assert.Equal(t, signerPublicKey, publicKey)
+	ok, err := Verify([]byte(`payload`), signature, publicKey)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = LoadPublicKey("./not_exists")
+	assert.Error(t, err)
+	_, err = LoadPublicKey("./../../test/signer/unparsable.key")
+	assert.Error(t, err)
+	_, err = LoadPublicKey("./../../test/signer/ec3.key")
+	assert.Error(t, err)
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/merkletree"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/pb"
+	"github.com/golang/protobuf/proto"
+)
+
+// backupMagic opens every snapshot written by Backup
+var backupMagic = []byte("IMMUBKP1")
+
+// maxBackupHeaderSize bounds the header read by ReadBackupHeader, so that a corrupted length is not allocated
+const maxBackupHeaderSize = 1 << 20
+
+// BackupHeader describes a snapshot written by Backup: the root the tree had when it was taken, which the entries
+// of the snapshot rebuild, and the manifest of the store it was taken from
+type BackupHeader struct {
+	Index     uint64    `json:"index"`
+	Root      []byte    `json:"root"`
+	CreatedAt time.Time `json:"createdAt"`
+	Manifest  Manifest  `json:"manifest"`
+}
+
+// Backup writes to w a consistent snapshot of the store, taken without stopping writes: the tree lock is only held
+// to capture the current root, then the entries and the tree nodes written up to its index are streamed from a
+// read snapshot of the underlying badger store, so that the entries written meanwhile are left out.
+// The snapshot is made of a BackupHeader followed by the entries in the badger backup format. Values are written
+// in plain text, even if the store is encrypted at rest.
+func (t *Store) Backup(w io.Writer) (*BackupHeader, error) {
+	t.tree.RLock()
+	width := t.tree.w
+	if width == 0 {
+		t.tree.RUnlock()
+		return nil, ErrEmptyBackup
+	}
+	root := merkletree.Root(t.tree)
+	t.tree.RUnlock()
+
+	header := &BackupHeader{
+		Index:     width - 1,
+		Root:      root[:],
+		CreatedAt: time.Now().UTC(),
+		Manifest:  t.manifest,
+	}
+	if err := writeBackupHeader(w, header); err != nil {
+		return nil, err
+	}
+
+	// every entry, as well as the tree nodes flushed along with it, is written at its index + 1
+	stream := t.db.NewStreamAt(width)
+	stream.NumGo = 16
+	stream.LogPrefix = "Badger.Backup"
+	// the boot epoch belongs to this store, restored elsewhere it would fence the writes of the restoring one
+	stream.ChooseKey = func(item *badger.Item) bool {
+		return !isBootEpochKey(item.Key())
+	}
+	if _, err := stream.Backup(w, 0); err != nil {
+		return nil, mapError(err)
+	}
+	return header, nil
+}
+
+func writeBackupHeader(w io.Writer, header *BackupHeader) error {
+	raw, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	buf.Write(backupMagic)
+	binary.Write(&buf, binary.BigEndian, uint32(len(raw)))
+	buf.Write(raw)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ReadBackupHeader reads the header of a snapshot written by Backup, leaving r at the beginning of its entries
+func ReadBackupHeader(r io.Reader) (*BackupHeader, error) {
+	magic := make([]byte, len(backupMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, backupMagic) {
+		return nil, ErrInvalidBackup
+	}
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil || size > maxBackupHeaderSize {
+		return nil, ErrInvalidBackup
+	}
+	raw := make([]byte, size)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, ErrInvalidBackup
+	}
+	header := &BackupHeader{}
+	if err := json.Unmarshal(raw, header); err != nil {
+		return nil, ErrInvalidBackup
+	}
+	return header, nil
+}
+
+// maxTreeLayer is the highest layer a tree of uint64 indexes can reach, the layers above it hold leaf metadata
+const maxTreeLayer = uint8(64)
+
+// isInnerTreeKey tells whether key holds a node of the tree above the leaves
+func isInnerTreeKey(key []byte) bool {
+	return len(key) == treeKeySize && key[0] == tsPrefix && key[1] > 0 && key[1] <= maxTreeLayer
+}
+
+// RestoreBackup rebuilds from a snapshot written by Backup a store in the data directory of badgerOptions, which
+// must be empty. Only the entries and the leaves of the snapshot are loaded, along with the metadata of the leaves:
+// the inner nodes of the tree are derived again from the leaves, then every entry is checked against its leaf and
+// the recomputed root against the one recorded in the header. The store is returned open once verified, otherwise
+// ErrBackupRootMismatch or ErrInconsistentDigest are returned and the data directory is left to the caller.
+func RestoreBackup(r io.Reader, options Options, badgerOptions badger.Options) (*Store, *BackupHeader, error) {
+	header, err := ReadBackupHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, err := Open(options, badgerOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if leaves, err := t.leafCount(); err != nil || leaves > 0 {
+		t.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrRestoreNotEmpty
+	}
+	if err = t.loadBackup(r); err != nil {
+		t.Close()
+		return nil, nil, err
+	}
+	if err = t.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	// no tree state has been loaded, so that the whole tree is replayed from the leaves
+	if t, err = Open(options, badgerOptions); err != nil {
+		return nil, nil, err
+	}
+	if err = t.verifyBackup(header); err != nil {
+		t.Close()
+		return nil, nil, err
+	}
+	return t, header, nil
+}
+
+// loadBackup writes the entries of a snapshot read from r, leaving out the state of the tree
+func (t *Store) loadBackup(r io.Reader) error {
+	br := bufio.NewReaderSize(r, 16<<10)
+	loader := t.db.NewKVLoader(16)
+	var buf []byte
+	for {
+		var size uint64
+		err := binary.Read(br, binary.LittleEndian, &size)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ErrInvalidBackup
+		}
+		if uint64(cap(buf)) < size {
+			buf = make([]byte, size)
+		}
+		if _, err = io.ReadFull(br, buf[:size]); err != nil {
+			return ErrInvalidBackup
+		}
+		list := &pb.KVList{}
+		if err = proto.Unmarshal(buf[:size], list); err != nil {
+			return ErrInvalidBackup
+		}
+		for _, kv := range list.Kv {
+			if isInnerTreeKey(kv.Key) || isBootEpochKey(kv.Key) ||
+				bytes.Equal(kv.Key, []byte(lastFlushedMetaKey)) || bytes.Equal(kv.Key, []byte(treeCheckpointMetaKey)) {
+				continue
+			}
+			if err = loader.Set(kv); err != nil {
+				return mapError(err)
+			}
+		}
+	}
+	return mapError(loader.Finish())
+}
+
+// verifyBackup checks the store restored from a snapshot against its header
+func (t *Store) verifyBackup(header *BackupHeader) error {
+	leaves, err := t.leafCount()
+	if err != nil {
+		return err
+	}
+	// waiting for the tree to be replayed up to a missing leaf would never return
+	if leaves != header.Index+1 {
+		return ErrBackupRootMismatch
+	}
+
+	t.tree.WaitUntil(header.Index)
+	root, err := t.CurrentRoot()
+	if err != nil {
+		return err
+	}
+	if root.GetIndex() != header.Index || !bytes.Equal(root.GetRoot(), header.Root) {
+		return ErrBackupRootMismatch
+	}
+
+	// the root only covers the leaves, the entries are checked against them
+	for i := uint64(0); i <= header.Index; i++ {
+		if _, err := t.ByIndex(schema.Index{Index: i}); err != nil && err != ErrEntryPruned {
+			return err
+		}
+	}
+	return nil
+}
+
+// leafCount returns the number of leaves written to disk, which the tree may not have been replayed up to yet
+func (t *Store) leafCount() (leaves uint64, err error) {
+	err = t.db.View(func(txn *badger.Txn) error {
+		leaves = treeLayerWidth(0, txn)
+		return nil
+	})
+	return leaves, mapError(err)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackup(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	st, err := Open(opts.WithTreeCheckpointInterval(16), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	var buf bytes.Buffer
+	_, err = st.Backup(&buf)
+	require.Equal(t, ErrEmptyBackup, err)
+
+	for i := 0; i < 100; i++ {
+		_, err = st.Set(schema.KeyValue{Key: []byte(fmt.Sprintf("key%d", i%10)), Value: []byte(fmt.Sprintf("value%d", i))})
+		require.NoError(t, err)
+	}
+	st.tree.WaitUntil(99)
+
+	// writes go on while the snapshot is taken
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 100; i < 200; i++ {
+			_, err := st.Set(schema.KeyValue{Key: []byte(fmt.Sprintf("key%d", i%10)), Value: []byte(fmt.Sprintf("value%d", i))})
+			assert.NoError(t, err)
+		}
+	}()
+	header, err := st.Backup(&buf)
+	require.NoError(t, err)
+	wg.Wait()
+
+	require.True(t, header.Index >= 99)
+	assert.Equal(t, st.Manifest(), header.Manifest)
+	assert.False(t, header.CreatedAt.IsZero())
+	st.tree.WaitUntil(199)
+	st.tree.RLock()
+	root := rootAt(st.tree, header.Index)
+	st.tree.RUnlock()
+	assert.Equal(t, root[:], header.Root)
+
+	read, err := ReadBackupHeader(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, header.Index, read.Index)
+	assert.Equal(t, header.Root, read.Root)
+
+	// the entries of the snapshot rebuild the tree up to the captured root, and nothing written later
+	restoreDir := tmpDir()
+	defer os.RemoveAll(restoreDir)
+	restoreOpts, restoreBadgerOpts := DefaultOptions(restoreDir, logger.NewSimpleLogger("test", os.Stderr))
+	restored, err := Open(restoreOpts, restoreBadgerOpts)
+	require.NoError(t, err)
+	require.NoError(t, restored.db.Load(&buf, 16))
+	require.NoError(t, restored.Close())
+
+	restored, err = Open(restoreOpts, restoreBadgerOpts)
+	require.NoError(t, err)
+	defer restored.Close()
+	// the entries written after the last flush of the tree are replayed in background
+	restored.tree.WaitUntil(header.Index)
+	restoredRoot, err := restored.CurrentRoot()
+	require.NoError(t, err)
+	assert.Equal(t, header.Index, restoredRoot.GetIndex())
+	assert.Equal(t, header.Root, restoredRoot.GetRoot())
+
+	item, err := restored.ByIndex(schema.Index{Index: header.Index})
+	require.NoError(t, err)
+	expected, err := st.ByIndex(schema.Index{Index: header.Index})
+	require.NoError(t, err)
+	assert.Equal(t, expected.Value, item.Value)
+	_, err = restored.ByIndex(schema.Index{Index: header.Index + 1})
+	assert.Error(t, err)
+
+	_, err = ReadBackupHeader(bytes.NewReader([]byte("not a backup")))
+	assert.Equal(t, ErrInvalidBackup, err)
+}
+
+func TestRestoreBackup(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	st, err := Open(opts.WithTreeCheckpointInterval(16), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	for i := 0; i < 100; i++ {
+		_, err = st.Set(schema.KeyValue{Key: []byte(fmt.Sprintf("key%d", i%10)), Value: []byte(fmt.Sprintf("value%d", i))})
+		require.NoError(t, err)
+	}
+	st.tree.WaitUntil(99)
+
+	var buf bytes.Buffer
+	header, err := st.Backup(&buf)
+	require.NoError(t, err)
+	snapshot := buf.Bytes()
+
+	restoreDir := tmpDir()
+	defer os.RemoveAll(restoreDir)
+	restoreOpts, restoreBadgerOpts := DefaultOptions(restoreDir, logger.NewSimpleLogger("test", os.Stderr))
+	restored, restoredHeader, err := RestoreBackup(bytes.NewReader(snapshot), restoreOpts, restoreBadgerOpts)
+	require.NoError(t, err)
+	assert.Equal(t, header.Index, restoredHeader.Index)
+
+	root, err := restored.CurrentRoot()
+	require.NoError(t, err)
+	assert.Equal(t, header.Index, root.GetIndex())
+	assert.Equal(t, header.Root, root.GetRoot())
+	item, err := restored.Get(schema.Key{Key: []byte("key9")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value99"), item.Value)
+
+	// the restored store accepts new writes
+	index, err := restored.Set(schema.KeyValue{Key: []byte("key0"), Value: []byte("value100")})
+	require.NoError(t, err)
+	assert.Equal(t, header.Index+1, index.GetIndex())
+	require.NoError(t, restored.Close())
+
+	// a store not empty is never overwritten
+	_, _, err = RestoreBackup(bytes.NewReader(snapshot), restoreOpts, restoreBadgerOpts)
+	assert.Equal(t, ErrRestoreNotEmpty, err)
+
+	// a snapshot not rebuilding the recorded root is rejected
+	read := bytes.NewReader(snapshot)
+	tampered, err := ReadBackupHeader(read)
+	require.NoError(t, err)
+	tampered.Root[0] ^= 0xff
+	var tamperedBuf bytes.Buffer
+	require.NoError(t, writeBackupHeader(&tamperedBuf, tampered))
+	_, err = read.WriteTo(&tamperedBuf)
+	require.NoError(t, err)
+
+	tamperedDir := tmpDir()
+	defer os.RemoveAll(tamperedDir)
+	tamperedOpts, tamperedBadgerOpts := DefaultOptions(tamperedDir, logger.NewSimpleLogger("test", os.Stderr))
+	_, _, err = RestoreBackup(&tamperedBuf, tamperedOpts, tamperedBadgerOpts)
+	assert.Equal(t, ErrBackupRootMismatch, err)
+
+	_, _, err = RestoreBackup(bytes.NewReader([]byte("not a backup")), tamperedOpts, tamperedBadgerOpts)
+	assert.Equal(t, ErrInvalidBackup, err)
+}
@@ -15,19 +15,35 @@ func (t *Store) SetBatch(list schema.KVList, options ...WriteOption) (index *sch
 	if err = list.Validate(); err != nil {
 		return nil, err
 	}
+	keys := make([][]byte, len(list.KVs))
+	for i, kv := range list.KVs {
+		keys[i] = kv.Key
+	}
+	release, err := t.frozen.guard(keys...)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	opts := makeWriteOptions(options...)
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	tsEntries := t.tree.NewBatch(&list)
 
 	for i, kv := range list.KVs {
 		if err = checkKey(kv.Key); err != nil {
 			return nil, err
 		}
+		value, compressed := t.compress(kv.Value)
 		if err = txn.SetEntry(&badger.Entry{
-			Key:   kv.Key,
-			Value: WrapValueWithTS(kv.Value, tsEntries[i].ts),
+			Key:      kv.Key,
+			Value:    wrapValue(value, tsEntries[i].ts),
+			UserMeta: bitChecksummedEntry | compressed,
 		}); err != nil {
 			return nil, mapError(err)
 		}
@@ -39,16 +55,19 @@ func (t *Store) SetBatch(list schema.KVList, options ...WriteOption) (index *sch
 	}
 
 	for _, leafEntry := range tsEntries {
-		if err = txn.SetEntry(&badger.Entry{
-			Key:      treeKey(uint8(0), leafEntry.ts-1),
-			Value:    refTreeKey(*leafEntry.h, *leafEntry.r),
-			UserMeta: bitTreeEntry,
-		}); err != nil {
+		if err = setLeafEntry(txn, leafEntry); err != nil {
 			return nil, mapError(err)
 		}
 	}
 
+	seq, sequenced, err := t.sequencer.sequence(txn, tsEntries[0].Index(), index.Index)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	index.Sequence = seq
+
 	cb := func(err error) {
+		sequenced(err)
 		if err == nil {
 			for _, entry := range tsEntries {
 				t.tree.Commit(entry)
@@ -78,13 +97,52 @@ func (t *Store) SetBatch(list schema.KVList, options ...WriteOption) (index *sch
 // The difference is that is possible to to specify a list of a mix of key value set and zAdd insertions.
 // If zAdd reference is not yet present on disk it's possible to add it as a regular key value and the reference is done onFly
 func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *schema.Index, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.execAllOps(ops, false, nil, options...)
+}
+
+// execAllOps writes ops atomically. If commitTx is set, the entry committing them as a transaction is appended,
+// see ExecAllTx. If any expected index is given, ops are written only if the keys are at the expected indexes and
+// are not modified until the write is committed, see CompareAndExecAllTx.
+// Callers hold the read lock of the store: operations may add references, so they cannot run while a
+// compare-and-reference, which holds the write lock, is in progress.
+func (t *Store) execAllOps(ops *schema.Ops, commitTx bool, expected []*schema.ExpectedIndex, options ...WriteOption) (index *schema.Index, err error) {
 	if err = ops.Validate(); err != nil {
 		return nil, err
 	}
+	var keys [][]byte
+	for _, op := range ops.Operations {
+		switch x := op.Operation.(type) {
+		case *schema.Op_KVs:
+			keys = append(keys, x.KVs.Key)
+		case *schema.Op_ROpts:
+			keys = append(keys, x.ROpts.Reference)
+		}
+	}
+	release, err := t.frozen.guard(keys...)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	opts := makeWriteOptions(options...)
-	txn := t.db.NewTransactionAt(math.MaxUint64, true)
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
+	var txn *badger.Txn
+	if len(expected) > 0 {
+		txn = t.snapshotTxn()
+	} else {
+		txn = t.db.NewTransactionAt(math.MaxUint64, true)
+	}
 	defer txn.Discard()
 
+	if err = t.checkExpected(txn, expected); err != nil {
+		return nil, err
+	}
+
 	var kvList schema.KVList
 	tsEntriesKv := make([]*treeStoreEntry, 0)
 
@@ -95,7 +153,11 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 	kmap := make(map[[32]byte]uint64)
 
 	// in order to get a monotone sequence of ts here is obtained a ts range
-	tsRange := t.tree.NewOpsTsRange(ops)
+	size := uint64(len(ops.Operations))
+	if commitTx {
+		size++
+	}
+	tsRange, now := t.tree.NewOpsTsRange(size)
 	for i, op := range ops.Operations {
 		ats := tsRange + uint64(i) + 1
 		switch x := op.Operation.(type) {
@@ -106,6 +168,7 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 				ts: ats,
 				h:  &h,
 				r:  &x.KVs.Key,
+				t:  now,
 			}
 
 			kmap[sha256.Sum256(x.KVs.Key)] = entry.Index()
@@ -114,6 +177,10 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 			// zAdd arguments are converted in regular key value items and then atomically inserted
 			skipPersistenceCheck := false
 			if idx, exists := kmap[sha256.Sum256(x.ZOpts.Key)]; exists {
+				// in strict append-only mode an externally supplied index must match the one assigned in this batch
+				if t.strictAppendOnly && x.ZOpts.Index != nil && x.ZOpts.Index.Index != idx {
+					return nil, ErrIndexKeyMismatch
+				}
 				skipPersistenceCheck = true
 				x.ZOpts.Index = &schema.Index{Index: idx}
 			} else if x.ZOpts.Index == nil {
@@ -136,12 +203,17 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 				ts: ats,
 				h:  &h,
 				r:  &kv.Key,
+				t:  now,
 			}
 			tsEntriesKv = append(tsEntriesKv, entry)
 		case *schema.Op_ROpts:
 			// reference arguments are converted in regular key value items and then atomically inserted
 			skipPersistenceCheck := false
 			if idx, exists := kmap[sha256.Sum256(x.ROpts.Key)]; exists {
+				// in strict append-only mode an externally supplied index must match the one assigned in this batch
+				if t.strictAppendOnly && x.ROpts.Index != nil && x.ROpts.Index.Index != idx {
+					return nil, ErrIndexKeyMismatch
+				}
 				skipPersistenceCheck = true
 				x.ROpts.Index = &schema.Index{Index: idx}
 			} else if x.ROpts.Index == nil {
@@ -164,6 +236,7 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 				ts: ats,
 				h:  &h,
 				r:  &kv.Key,
+				t:  now,
 			}
 			tsEntriesKv = append(tsEntriesKv, entry)
 
@@ -179,37 +252,57 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 		if err := checkKey(kv.Key); err != nil {
 			return nil, err
 		}
-		var userMeta byte
+		userMeta := bitChecksummedEntry
+		value := kv.Value
 		// if key is not present it means that current element is a zAdd type, then we need to flag it as a reference
 		if _, exists := kmap[sha256.Sum256(kv.Key)]; !exists {
 			// storing zAdd key value items in badger and flag them as reference
-			userMeta = bitReferenceEntry
+			userMeta |= bitReferenceEntry
+		} else {
+			var compressed byte
+			value, compressed = t.compress(value)
+			userMeta |= compressed
 		}
 		if err = txn.SetEntry(&badger.Entry{
 			Key:      kv.Key,
-			Value:    WrapValueWithTS(kv.Value, tsEntriesKv[i].ts),
+			Value:    wrapValue(value, tsEntriesKv[i].ts),
 			UserMeta: userMeta,
 		}); err != nil {
 			return nil, mapError(err)
 		}
 	}
 
+	if commitTx {
+		commitEntry, digest := newTxCommitEntry(tsRange+size, tsEntriesKv, now)
+		if err = txn.SetEntry(&badger.Entry{
+			Key:      *commitEntry.r,
+			Value:    wrapValue(digest, commitEntry.ts),
+			UserMeta: bitChecksummedEntry,
+		}); err != nil {
+			return nil, mapError(err)
+		}
+		tsEntriesKv = append(tsEntriesKv, commitEntry)
+	}
+
 	// merkle tree elements generation
 	ts := tsEntriesKv[len(tsEntriesKv)-1].ts
 	index = &schema.Index{
 		Index: ts - 1,
 	}
 	for _, leafEntry := range tsEntriesKv {
-		if err = txn.SetEntry(&badger.Entry{
-			Key:      treeKey(uint8(0), leafEntry.ts-1),
-			Value:    refTreeKey(*leafEntry.h, *leafEntry.r),
-			UserMeta: bitTreeEntry,
-		}); err != nil {
+		if err = setLeafEntry(txn, leafEntry); err != nil {
 			return nil, mapError(err)
 		}
 	}
 
+	seq, sequenced, err := t.sequencer.sequence(txn, tsEntriesKv[0].Index(), index.Index)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	index.Sequence = seq
+
 	cb := func(err error) {
+		sequenced(err)
 		if err == nil {
 			for _, entry := range tsEntriesKv {
 				t.tree.Commit(entry)
@@ -231,5 +324,9 @@ func (t *Store) ExecAllOps(ops *schema.Ops, options ...WriteOption) (index *sche
 		err = mapError(txn.CommitAt(ts, nil))
 		cb(err)
 	}
+	if err == ErrConflict && len(expected) > 0 {
+		// a key read by the transaction has been written after its snapshot
+		err = ErrTxConflict
+	}
 	return
 }
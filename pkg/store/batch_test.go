@@ -977,3 +977,22 @@ func TestOps_ReferenceIndexMissing(t *testing.T) {
 	_, err := st.ExecAllOps(aOps)
 	assert.Equal(t, ErrReferenceIndexMissing, err)
 }
+
+func BenchmarkStoreSetBatch(b *testing.B) {
+	st, closer := makeStore()
+	defer closer()
+
+	b.ReportAllocs()
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		list := schema.KVList{}
+		for j := 0; j < 16; j++ {
+			list.KVs = append(list.KVs, &schema.KeyValue{
+				Key:   []byte(strconv.FormatUint(uint64(i*16+j), 10)),
+				Value: []byte{0, 1, 3, 4, 5, 6, 7},
+			})
+		}
+		st.SetBatch(list)
+	}
+	b.StopTimer()
+}
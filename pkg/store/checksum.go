@@ -0,0 +1,69 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// bitChecksummedEntry flags the records whose value is followed by a checksum, see wrapValue.
+// Records written by previous versions have no checksum and are read as they are.
+const bitChecksummedEntry = byte(2)
+
+const checksumSize = crc32.Size
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// wrapValue prepends ts to v like WrapValueWithTS and appends the CRC-32 (Castagnoli) of both,
+// so that disk corruption is detected on read. The record must be flagged with bitChecksummedEntry.
+func wrapValue(v []byte, ts uint64) []byte {
+	tsv := make([]byte, 8+len(v)+checksumSize)
+	binary.BigEndian.PutUint64(tsv, ts)
+	copy(tsv[8:], v)
+	binary.BigEndian.PutUint32(tsv[8+len(v):], crc32.Checksum(tsv[:8+len(v)], checksumTable))
+	return tsv
+}
+
+// unwrapValue returns the value and the timestamp of a record, verifying its checksum if flagged by userMeta.
+// The deadline of expiring records is dropped, see recordExpiry, and compressed values are decompressed.
+// ErrCorruptedValue is returned if the checksum does not match.
+func unwrapValue(userMeta byte, tsv []byte) ([]byte, uint64, error) {
+	if userMeta&bitChecksummedEntry == bitChecksummedEntry {
+		l := len(tsv) - checksumSize
+		if l < 8 || binary.BigEndian.Uint32(tsv[l:]) != crc32.Checksum(tsv[:l], checksumTable) {
+			return nil, 0, ErrCorruptedValue
+		}
+		tsv = tsv[:l]
+	} else if len(tsv) < 8 {
+		return nil, 0, ErrCorruptedValue
+	}
+	if userMeta&bitExpiringEntry == bitExpiringEntry {
+		if len(tsv) < 8+expirySize {
+			return nil, 0, ErrCorruptedValue
+		}
+		tsv = tsv[:len(tsv)-expirySize]
+	}
+	v, ts := UnwrapValueWithTS(tsv)
+	if userMeta&bitCompressedEntry == bitCompressedEntry {
+		var err error
+		if v, err = decompressValue(v); err != nil {
+			return nil, 0, err
+		}
+	}
+	return v, ts, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapValue(t *testing.T) {
+	tsv := wrapValue([]byte("value"), 42)
+	v, ts, err := unwrapValue(bitChecksummedEntry, tsv)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), v)
+	assert.Equal(t, uint64(42), ts)
+
+	v, ts, err = unwrapValue(bitReferenceEntry|bitChecksummedEntry, wrapValue(nil, 1))
+	assert.NoError(t, err)
+	assert.Empty(t, v)
+	assert.Equal(t, uint64(1), ts)
+
+	// records written without checksum are still readable
+	v, ts, err = unwrapValue(0, WrapValueWithTS([]byte("value"), 42))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), v)
+	assert.Equal(t, uint64(42), ts)
+
+	for i := range tsv {
+		corrupted := append([]byte{}, tsv...)
+		corrupted[i] ^= 0x01
+		_, _, err = unwrapValue(bitChecksummedEntry, corrupted)
+		assert.Equal(t, ErrCorruptedValue, err, i)
+	}
+	_, _, err = unwrapValue(bitChecksummedEntry, tsv[:len(tsv)-1])
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, _, err = unwrapValue(bitChecksummedEntry, tsv[:4])
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, _, err = unwrapValue(0, tsv[:4])
+	assert.Equal(t, ErrCorruptedValue, err)
+}
+
+func TestStoreCorruptedValue(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	idx, err := st.Set(schema.KeyValue{Key: []byte("key"), Value: []byte("value")})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte("ref"), Key: []byte("key")})
+	assert.NoError(t, err)
+	st.tree.WaitUntil(idx.Index + 1)
+
+	item, err := st.Get(schema.Key{Key: []byte("ref")})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), item.Value)
+
+	// flip a bit of the value on disk, keeping the record flags
+	txn := st.db.NewTransactionAt(math.MaxUint64, true)
+	corrupted := wrapValue([]byte("value"), idx.Index+1)
+	corrupted[8] ^= 0x01
+	assert.NoError(t, txn.SetEntry(&badger.Entry{Key: []byte("key"), Value: corrupted, UserMeta: bitChecksummedEntry}))
+	assert.NoError(t, txn.CommitAt(idx.Index+1, nil))
+
+	_, err = st.Get(schema.Key{Key: []byte("key")})
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, err = st.Get(schema.Key{Key: []byte("ref")})
+	assert.Equal(t, ErrCorruptedValue, err)
+	// the corruption is reported before the digest of the value is checked against the tree
+	_, err = st.ByIndex(schema.Index{Index: idx.Index})
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, err = st.SafeGet(schema.SafeGetOptions{Key: []byte("key")})
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, err = st.Scan(schema.ScanOptions{Prefix: []byte("k")})
+	assert.Equal(t, ErrCorruptedValue, err)
+}
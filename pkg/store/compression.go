@@ -0,0 +1,138 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2/y"
+	"github.com/golang/snappy"
+)
+
+// bitCompressedEntry flags the records whose value is compressed, see compressValue. The compressed value is
+// preceded by the ValueCompression it has been compressed with, so that records compressed with any algorithm,
+// and records not compressed at all, stay readable whatever the compression of the store is.
+const bitCompressedEntry = byte(16)
+
+// ValueCompression is the algorithm compressing the values of the entries before they are persisted
+type ValueCompression byte
+
+const (
+	// NoCompression stores the values as they are
+	NoCompression ValueCompression = iota
+	// SnappyCompression compresses the values with snappy, fast with a moderate ratio
+	SnappyCompression
+	// ZstdCompression compresses the values with zstd, with a better ratio than snappy. Only available with cgo.
+	ZstdCompression
+)
+
+// minCompressedSize is the size from which values are worth compressing
+const minCompressedSize = 128
+
+// zstdLevel is the zstd compression level, a good compromise between speed and ratio
+const zstdLevel = 3
+
+// ParseValueCompression returns the ValueCompression named name: none (or the empty string), snappy or zstd
+func ParseValueCompression(name string) (ValueCompression, error) {
+	switch name {
+	case "", "none":
+		return NoCompression, nil
+	case "snappy":
+		return SnappyCompression, nil
+	case "zstd":
+		return ZstdCompression, nil
+	}
+	return NoCompression, fmt.Errorf("unsupported value compression %s, allowed values are none, snappy and zstd (only if built with cgo)", name)
+}
+
+func (c ValueCompression) String() string {
+	switch c {
+	case NoCompression:
+		return "none"
+	case SnappyCompression:
+		return "snappy"
+	case ZstdCompression:
+		return "zstd"
+	}
+	return fmt.Sprintf("unknown(%d)", byte(c))
+}
+
+// Validate returns an error if the compression is unknown or not available in this build
+func (c ValueCompression) Validate() error {
+	switch c {
+	case NoCompression, SnappyCompression:
+		return nil
+	case ZstdCompression:
+		// zstd is provided by badger, which requires cgo for it
+		if _, err := y.ZSTDCompress(nil, []byte{0}, zstdLevel); err != nil {
+			return fmt.Errorf("zstd value compression is only available if built with cgo: %v", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown value compression %d", byte(c))
+}
+
+// compressValue returns v compressed with c, preceded by c, and bitCompressedEntry. v is returned as it is, and
+// without flag, if it's too small to be worth compressing or if compressing it doesn't make it smaller.
+func compressValue(c ValueCompression, v []byte) ([]byte, byte) {
+	if c == NoCompression || len(v) < minCompressedSize {
+		return v, 0
+	}
+	var cv []byte
+	switch c {
+	case SnappyCompression:
+		cv = snappy.Encode(nil, v)
+	case ZstdCompression:
+		var err error
+		if cv, err = y.ZSTDCompress(nil, v, zstdLevel); err != nil {
+			return v, 0
+		}
+	default:
+		return v, 0
+	}
+	if 1+len(cv) >= len(v) {
+		return v, 0
+	}
+	return append([]byte{byte(c)}, cv...), bitCompressedEntry
+}
+
+// decompressValue returns the value compressed by compressValue as cv.
+// ErrCorruptedValue is returned if cv can't be decompressed.
+func decompressValue(cv []byte) ([]byte, error) {
+	if len(cv) == 0 {
+		return nil, ErrCorruptedValue
+	}
+	var v []byte
+	var err error
+	switch ValueCompression(cv[0]) {
+	case SnappyCompression:
+		v, err = snappy.Decode(nil, cv[1:])
+	case ZstdCompression:
+		v, err = y.ZSTDDecompress(nil, cv[1:])
+	default:
+		return nil, ErrCorruptedValue
+	}
+	if err != nil {
+		return nil, ErrCorruptedValue
+	}
+	return v, nil
+}
+
+// compress compresses v with the value compression of the store, see compressValue
+func (t *Store) compress(v []byte) ([]byte, byte) {
+	return compressValue(t.valueCompression, v)
+}
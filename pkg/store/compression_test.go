@@ -0,0 +1,144 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseValueCompression(t *testing.T) {
+	for _, c := range []ValueCompression{NoCompression, SnappyCompression, ZstdCompression} {
+		parsed, err := ParseValueCompression(c.String())
+		require.NoError(t, err)
+		assert.Equal(t, c, parsed)
+	}
+	c, err := ParseValueCompression("")
+	require.NoError(t, err)
+	assert.Equal(t, NoCompression, c)
+	_, err = ParseValueCompression("lz4")
+	assert.Error(t, err)
+	assert.Error(t, ValueCompression(42).Validate())
+}
+
+func TestCompressValue(t *testing.T) {
+	large := bytes.Repeat([]byte(`{"name":"immudb","tags":["a","b"]},`), 100)
+
+	cv, flag := compressValue(SnappyCompression, large)
+	assert.Equal(t, bitCompressedEntry, flag)
+	assert.Less(t, len(cv), len(large))
+	v, err := decompressValue(cv)
+	require.NoError(t, err)
+	assert.Equal(t, large, v)
+
+	// small and incompressible values are stored as they are
+	cv, flag = compressValue(SnappyCompression, []byte(`small`))
+	assert.Equal(t, byte(0), flag)
+	assert.Equal(t, []byte(`small`), cv)
+	random := make([]byte, 1024)
+	_, err = rand.Read(random)
+	require.NoError(t, err)
+	cv, flag = compressValue(SnappyCompression, random)
+	assert.Equal(t, byte(0), flag)
+	assert.Equal(t, random, cv)
+	cv, flag = compressValue(NoCompression, large)
+	assert.Equal(t, byte(0), flag)
+	assert.Equal(t, large, cv)
+
+	_, err = decompressValue(nil)
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, err = decompressValue([]byte{byte(SnappyCompression), 0xff, 0xff})
+	assert.Equal(t, ErrCorruptedValue, err)
+	_, err = decompressValue([]byte{42, 0})
+	assert.Equal(t, ErrCorruptedValue, err)
+
+	if ZstdCompression.Validate() == nil {
+		cv, flag = compressValue(ZstdCompression, large)
+		assert.Equal(t, bitCompressedEntry, flag)
+		v, err = decompressValue(cv)
+		require.NoError(t, err)
+		assert.Equal(t, large, v)
+	}
+}
+
+func TestStoreValueCompression(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	slog := logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogDebug)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+
+	large := bytes.Repeat([]byte(`{"name":"immudb","tags":["a","b"]},`), 100)
+	st, err := Open(opts, badgerOpts)
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`plain`), Value: large})
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	st, err = Open(opts.WithValueCompression(SnappyCompression), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	index, err := st.Set(schema.KeyValue{Key: []byte(`set`), Value: large})
+	require.NoError(t, err)
+	_, err = st.SafeSet(schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`safeset`), Value: large}})
+	require.NoError(t, err)
+	_, err = st.SetBatch(schema.KVList{KVs: []*schema.KeyValue{{Key: []byte(`batch`), Value: large}}})
+	require.NoError(t, err)
+	_, err = st.ExecAllOps(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`ops`), Value: large}}},
+		{Operation: &schema.Op_ZOpts{ZOpts: &schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`ops`), Score: &schema.Score{Score: 1}}}},
+	}})
+	require.NoError(t, err)
+
+	for _, key := range []string{`plain`, `set`, `safeset`, `batch`, `ops`} {
+		item, err := st.Get(schema.Key{Key: []byte(key)})
+		require.NoError(t, err)
+		assert.Equal(t, large, item.Value, key)
+	}
+	list, err := st.ZScan(schema.ZScanOptions{Set: []byte(`set`)})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, large, list.Items[0].Item.Value)
+
+	// only the values written with compression are compressed on disk
+	require.NoError(t, st.db.View(func(txn *badger.Txn) error {
+		for key, compressed := range map[string]bool{`plain`: false, `set`: true, `safeset`: true, `batch`: true, `ops`: true} {
+			i, err := txn.Get([]byte(key))
+			require.NoError(t, err)
+			assert.Equal(t, compressed, i.UserMeta()&bitCompressedEntry == bitCompressedEntry, key)
+			assert.Equal(t, compressed, i.ValueSize() < int64(len(large)), key)
+		}
+		return nil
+	}))
+
+	// leaves are hashed from the values as they have been written
+	safeItem, err := st.BySafeIndex(schema.SafeIndexOptions{Index: index.Index})
+	require.NoError(t, err)
+	assert.Equal(t, large, safeItem.Item.Value)
+	leaf := api.Digest(index.Index, safeItem.Item.Key, safeItem.Item.Value)
+	assert.True(t, safeItem.Proof.Verify(leaf[:], schema.Root{}))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// encryptionCacheSize is the size of the cache of decrypted blocks of encrypted stores, as recommended by badger
+const encryptionCacheSize = 64 << 20
+
+// ValidateEncryptionKey returns an error if key is not a valid AES-128, AES-192 or AES-256 key
+func ValidateEncryptionKey(key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	return fmt.Errorf("%w: got %d bytes, while 16, 24 or 32 are expected", ErrInvalidEncryptionKey, len(key))
+}
+
+// ReadEncryptionKey reads the encryption key stored in the file path, either as raw bytes or hex encoded
+func ReadEncryptionKey(path string) ([]byte, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ValidateEncryptionKey(raw) == nil {
+		return raw, nil
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is neither a raw nor a hex encoded key", ErrInvalidEncryptionKey, path)
+	}
+	if err = ValidateEncryptionKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// RotateEncryptionKey replaces the master key of the encrypted data directory dir, from oldKey to newKey.
+// Only the registry of the data keys, which are encrypted with the master key, is written again: the data itself
+// is encrypted with the data keys and stays as it is. The store must be closed.
+func RotateEncryptionKey(dir string, oldKey, newKey []byte) error {
+	if err := ValidateEncryptionKey(oldKey); err != nil {
+		return err
+	}
+	if err := ValidateEncryptionKey(newKey); err != nil {
+		return err
+	}
+	if m, err := ReadManifest(dir); err == nil && m.Options["encryption"] != "true" {
+		return fmt.Errorf("%s is not an encrypted data directory", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, badger.KeyRegistryFileName)); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "LOCK")); err == nil {
+		return fmt.Errorf("%s is in use: close the store first, or remove its LOCK file if it has not been closed cleanly", dir)
+	}
+	opts := badger.KeyRegistryOptions{
+		Dir:           dir,
+		ReadOnly:      true,
+		EncryptionKey: oldKey,
+	}
+	registry, err := badger.OpenKeyRegistry(opts)
+	if err != nil {
+		return mapError(err)
+	}
+	defer registry.Close()
+	opts.EncryptionKey = newKey
+	return mapError(badger.WriteKeyRegistry(registry, opts))
+}
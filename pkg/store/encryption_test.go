@@ -0,0 +1,120 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEncryptionKey(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	key := bytes.Repeat([]byte{7}, 32)
+	raw := filepath.Join(dir, "raw.key")
+	require.NoError(t, ioutil.WriteFile(raw, key, 0600))
+	read, err := ReadEncryptionKey(raw)
+	require.NoError(t, err)
+	assert.Equal(t, key, read)
+
+	encoded := filepath.Join(dir, "hex.key")
+	require.NoError(t, ioutil.WriteFile(encoded, []byte(hex.EncodeToString(key[:16])+"\n"), 0600))
+	read, err = ReadEncryptionKey(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, key[:16], read)
+
+	invalid := filepath.Join(dir, "invalid.key")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("not a key"), 0600))
+	_, err = ReadEncryptionKey(invalid)
+	assert.True(t, errors.Is(err, ErrInvalidEncryptionKey))
+	_, err = ReadEncryptionKey(filepath.Join(dir, "missing.key"))
+	assert.Error(t, err)
+}
+
+func TestStoreEncryption(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	slog := logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogDebug)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+	key := bytes.Repeat([]byte{1}, 32)
+	secret := bytes.Repeat([]byte(`top-secret-value`), 8)
+
+	_, err := Open(opts.WithEncryptionKey([]byte(`short`)), badgerOpts)
+	assert.True(t, errors.Is(err, ErrInvalidEncryptionKey))
+
+	st, err := Open(opts.WithEncryptionKey(key), badgerOpts)
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`secret`), Value: secret})
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	// the values never reach the disk in clear
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		content, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err)
+		assert.False(t, bytes.Contains(content, []byte(`top-secret-value`)), f.Name())
+	}
+
+	_, err = Open(opts, badgerOpts)
+	assert.True(t, errors.Is(err, ErrIncompatibleDataDir))
+	_, err = Open(opts.WithEncryptionKey(bytes.Repeat([]byte{2}, 32)), badgerOpts)
+	assert.Equal(t, ErrEncryptionKeyMismatch, err)
+
+	newKey := bytes.Repeat([]byte{3}, 16)
+	st, err = Open(opts.WithEncryptionKey(key), badgerOpts)
+	require.NoError(t, err)
+	assert.Error(t, RotateEncryptionKey(dir, key, newKey))
+	require.NoError(t, st.Close())
+	assert.Equal(t, ErrEncryptionKeyMismatch, RotateEncryptionKey(dir, newKey, key))
+	require.NoError(t, RotateEncryptionKey(dir, key, newKey))
+	_, err = Open(opts.WithEncryptionKey(key), badgerOpts)
+	assert.Equal(t, ErrEncryptionKeyMismatch, err)
+
+	st, err = Open(opts.WithEncryptionKey(newKey), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	item, err := st.Get(schema.Key{Key: []byte(`secret`)})
+	require.NoError(t, err)
+	assert.Equal(t, secret, item.Value)
+}
+
+func TestRotateEncryptionKeyNotEncrypted(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	st, err := Open(DefaultOptions(dir, logger.NewSimpleLogger("immudb ", os.Stderr)))
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	key := bytes.Repeat([]byte{1}, 32)
+	assert.EqualError(t, RotateEncryptionKey(dir, key, key), dir+" is not an encrypted data directory")
+	assert.True(t, errors.Is(RotateEncryptionKey(dir, key[:3], key), ErrInvalidEncryptionKey))
+}
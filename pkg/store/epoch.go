@@ -0,0 +1,98 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// bootEpochLayer prefixes the key holding the boot epoch, like timeLayer it's out of the range of the tree layers
+const bootEpochLayer = uint8(251)
+
+var bootEpochKey = []byte{tsPrefix, bootEpochLayer}
+
+func isBootEpochKey(key []byte) bool {
+	return len(key) == 2 && key[0] == tsPrefix && key[1] == bootEpochLayer
+}
+
+// nextBootEpoch persists and returns the boot epoch of a newly opened store, one more than the last persisted.
+// Each epoch is written at its own version, so that the latest epoch is always the one read.
+func nextBootEpoch(db *badger.DB) (uint64, error) {
+	txn := db.NewTransactionAt(math.MaxUint64, true)
+	defer txn.Discard()
+
+	last, err := readBootEpoch(txn)
+	if err != nil {
+		return 0, err
+	}
+	epoch := last + 1
+
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, epoch)
+	if err = txn.SetEntry(&badger.Entry{Key: bootEpochKey, Value: v, UserMeta: bitTreeEntry}); err != nil {
+		return 0, mapError(err)
+	}
+	if err = txn.CommitAt(epoch, nil); err != nil {
+		return 0, mapError(err)
+	}
+	// the epoch must survive a crash, otherwise the next boot would reuse it
+	return epoch, mapError(db.Sync())
+}
+
+func readBootEpoch(txn *badger.Txn) (uint64, error) {
+	i, err := txn.Get(bootEpochKey)
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, mapError(err)
+	}
+	v, err := i.ValueCopy(nil)
+	if err != nil {
+		return 0, mapError(err)
+	}
+	if len(v) != 8 {
+		return 0, ErrInconsistentState
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// fence fails a write with ErrFenced if the store has been opened again since it was opened by this process,
+// so that writes still in flight in a superseded instance, e.g. from async commits, can't append after the new one
+// resumed. It must be called before leasing indexes, so that a fenced write leaves no gap in the tree.
+// The epoch is read at the latest version, the write may be reading an older snapshot, see snapshotTxn.
+func (t *Store) fence() error {
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	epoch, err := readBootEpoch(txn)
+	if err != nil {
+		return err
+	}
+	if epoch != t.bootEpoch {
+		return ErrFenced
+	}
+	return nil
+}
+
+// BootEpoch returns the boot epoch of the store, it's incremented each time the store is opened
+func (t *Store) BootEpoch() uint64 {
+	return t.bootEpoch
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootEpoch(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	st, _ := makeStoreAt(dir)
+	require.Equal(t, uint64(1), st.BootEpoch())
+	_, err := st.Set(schema.KeyValue{Key: []byte(`key1`), Value: []byte(`value1`)})
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	st, _ = makeStoreAt(dir)
+	defer st.Close()
+	require.Equal(t, uint64(2), st.BootEpoch())
+	index, err := st.Set(schema.KeyValue{Key: []byte(`key2`), Value: []byte(`value2`)})
+	require.NoError(t, err)
+	st.tree.WaitUntil(index.Index)
+	// the boot epoch is neither an entry nor a leaf
+	entries, leaves := st.CountEntriesAndLeaves()
+	require.Equal(t, uint64(2), entries)
+	require.Equal(t, uint64(2), leaves)
+}
+
+func TestFence(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	_, err := st.Set(schema.KeyValue{Key: []byte(`key1`), Value: []byte(`value1`)})
+	require.NoError(t, err)
+
+	// a newer boot of the same store supersedes this one
+	epoch, err := nextBootEpoch(st.db)
+	require.NoError(t, err)
+	require.Equal(t, st.BootEpoch()+1, epoch)
+
+	_, err = st.Set(schema.KeyValue{Key: []byte(`key2`), Value: []byte(`value2`)}, WithAsyncCommit(true))
+	require.Equal(t, ErrFenced, err)
+	_, err = st.SetBatch(schema.KVList{KVs: []*schema.KeyValue{{Key: []byte(`key2`), Value: []byte(`value2`)}}})
+	require.Equal(t, ErrFenced, err)
+	_, err = st.ExecAllTx(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`key2`), Value: []byte(`value2`)}}},
+	}})
+	require.Equal(t, ErrFenced, err)
+	// the epoch is checked at the latest version, even by the writes reading an older snapshot
+	_, err = st.CompareAndExecAllTx(schema.CompareAndExecAllTxOptions{
+		Ops: &schema.Ops{Operations: []*schema.Op{
+			{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`key2`), Value: []byte(`value2`)}}},
+		}},
+		Expected: []*schema.ExpectedIndex{{Key: []byte(`key1`), Index: &schema.Index{Index: 0}}},
+	})
+	require.Equal(t, ErrFenced, err)
+	_, err = st.Delete(schema.Key{Key: []byte(`key1`)})
+	require.Equal(t, ErrFenced, err)
+	_, err = st.SafeSet(schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`key2`), Value: []byte(`value2`)}})
+	require.Equal(t, ErrFenced, err)
+
+	// fenced writes leave no trace and no gap in the tree
+	st.Wait()
+	_, err = st.Get(schema.Key{Key: []byte(`key2`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	require.Equal(t, uint64(0), st.tree.LastIndex())
+	item, err := st.Get(schema.Key{Key: []byte(`key1`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`value1`), item.Value)
+}
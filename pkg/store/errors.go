@@ -32,6 +32,8 @@ var (
 	ErrInvalidKeyPrefix      = status.New(codes.InvalidArgument, "invalid key prefix").Err()
 	ErrInvalidSet            = status.New(codes.InvalidArgument, "invalid set").Err()
 	ErrInvalidOffset         = status.New(codes.InvalidArgument, "invalid offset").Err()
+	ErrInvalidCursor         = status.New(codes.InvalidArgument, "invalid cursor: it must be returned by a scan having the same prefix and direction").Err()
+	ErrInvalidHistoryRange   = status.New(codes.InvalidArgument, "invalid history range: the lower bound must not exceed the upper one").Err()
 	ErrInvalidRootIndex      = status.New(codes.InvalidArgument, "invalid root index").Err()
 	ErrObsoleteDataFormat    = status.New(codes.Unknown, "data format in which elements are written on disk is not up to date to the current version of immudb server. Please upgrade to access to complete functionalities").Err()
 	ErrInconsistentDigest    = status.New(codes.Unknown, "insertion order index hash is not equal to the digest of the related value").Err()
@@ -39,6 +41,25 @@ var (
 	ErrZAddIndexMissing      = status.New(codes.InvalidArgument, "zAdd index not provided").Err()
 	ErrReferenceIndexMissing = status.New(codes.InvalidArgument, "reference index not provided").Err()
 	ErrNoReferenceProvided   = status.New(codes.InvalidArgument, "provided argument is not a reference").Err()
+	ErrReferenceMismatch     = status.New(codes.FailedPrecondition, "reference does not point to the expected index").Err()
+	ErrIndexNotCommitted     = status.New(codes.InvalidArgument, "provided index refers to an entry not yet committed").Err()
+	ErrRestoreNotAllowed     = status.New(codes.FailedPrecondition, "restoring entries with externally supplied indexes is not allowed in strict append-only mode").Err()
+	ErrInvalidSampleSize     = status.New(codes.InvalidArgument, "sample size must be greater than zero and not exceed the max batch count").Err()
+	ErrPrefixFrozen          = status.New(codes.FailedPrecondition, "key prefix is frozen").Err()
+	ErrSequenceNotFound      = status.New(codes.NotFound, "sequence not found").Err()
+	ErrCorruptedValue        = status.New(codes.DataLoss, "value record checksum mismatch: data on disk is corrupted").Err()
+	ErrInvalidValueHash      = status.New(codes.InvalidArgument, "value hash must be a SHA-256 digest").Err()
+	ErrNotStructuredValue    = status.New(codes.FailedPrecondition, "stored value is not a structured value").Err()
+	ErrFenced                = status.New(codes.Aborted, "write fenced: the store has been opened again by a newer boot").Err()
+	ErrTxConflict            = status.New(codes.Aborted, "transaction conflict: a key read by the transaction has been modified").Err()
+	ErrManifestNotFound      = status.New(codes.NotFound, "manifest not found").Err()
+	ErrIncompatibleDataDir   = status.New(codes.FailedPrecondition, "incompatible data directory").Err()
+	ErrEntryPruned           = status.New(codes.NotFound, "the value of the entry has been pruned by the retention policy").Err()
+	ErrSegmentUnavailable    = status.New(codes.Unavailable, "a value log segment moved to cold storage is not available").Err()
+	ErrEmptyBackup           = status.New(codes.FailedPrecondition, "nothing to back up: the store is empty").Err()
+	ErrInvalidBackup         = status.New(codes.InvalidArgument, "invalid backup: not a snapshot written by Backup").Err()
+	ErrRestoreNotEmpty       = status.New(codes.FailedPrecondition, "cannot restore a backup into a store that is not empty").Err()
+	ErrBackupRootMismatch    = status.New(codes.DataLoss, "backup verification failed: the recomputed root does not match the recorded one").Err()
 )
 
 // fixme(leogr): review codes and fix/remove errors which do not make sense in this context, finally correct comments accordingly.
@@ -0,0 +1,68 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// bitExpiringEntry flags the records whose value is followed by the deadline after which the entry can't be read
+// by key anymore, see wrapExpiringValue. The deadline is not part of the leaf, so proofs are not affected.
+const bitExpiringEntry = byte(4)
+
+const expirySize = 8
+
+// wrapExpiringValue is like wrapValue, with the deadline as unix nanoseconds between the value and the checksum.
+// The record must be flagged with bitChecksummedEntry and bitExpiringEntry.
+func wrapExpiringValue(v []byte, ts uint64, expiresAt time.Time) []byte {
+	ev := make([]byte, len(v)+expirySize)
+	copy(ev, v)
+	binary.BigEndian.PutUint64(ev[len(v):], uint64(expiresAt.UnixNano()))
+	return wrapValue(ev, ts)
+}
+
+// recordExpiry returns the deadline of a record flagged with bitExpiringEntry.
+// The record must have been verified by unwrapValue.
+func recordExpiry(tsv []byte) time.Time {
+	l := len(tsv) - checksumSize
+	return time.Unix(0, int64(binary.BigEndian.Uint64(tsv[l-expirySize:l])))
+}
+
+// expired tells if the entry read as i is past its deadline, entries written without a deadline never expire.
+// Entries pruned by the retention policy are expired as well.
+func (t *Store) expired(i *badger.Item) (bool, error) {
+	if i.UserMeta()&bitPrunedEntry == bitPrunedEntry {
+		return true, nil
+	}
+	if i.UserMeta()&bitExpiringEntry != bitExpiringEntry {
+		return false, nil
+	}
+	var expiresAt time.Time
+	if err := i.Value(func(val []byte) error {
+		if _, _, err := unwrapValue(i.UserMeta(), val); err != nil {
+			return err
+		}
+		expiresAt = recordExpiry(val)
+		return nil
+	}); err != nil {
+		return false, mapError(err)
+	}
+	return !t.tree.clock.Now().Before(expiresAt), nil
+}
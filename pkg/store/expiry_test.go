@@ -0,0 +1,92 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiresAt(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1000, 0).UnixNano()
+	c := clock.Func(func() time.Time { return time.Unix(0, atomic.LoadInt64(&now)) })
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	st, err := Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	_, err = st.Set(schema.KeyValue{Key: []byte(`personal`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+	expiring, err := st.Set(schema.KeyValue{Key: []byte(`personal`), Value: []byte(`v2`)},
+		WithExpiresAt(time.Unix(1060, 0)))
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`other`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`personal`)})
+	require.NoError(t, err)
+
+	// before the deadline the entry is read as usual, without its deadline
+	item, err := st.Get(schema.Key{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`v2`), item.Value)
+	require.Equal(t, expiring.Index, item.Index)
+	st.tree.WaitUntil(expiring.Index)
+	safeItem, err := st.SafeGet(schema.SafeGetOptions{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`v2`), safeItem.Item.Value)
+
+	atomic.StoreInt64(&now, time.Unix(1060, 0).UnixNano())
+
+	_, err = st.Get(schema.Key{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Get(schema.Key{Key: []byte(`ref`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.SafeGet(schema.SafeGetOptions{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+
+	history, err := st.History(&schema.HistoryOptions{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 1)
+	require.Equal(t, []byte(`v1`), history.Items[0].Value)
+
+	list, err := st.Scan(schema.ScanOptions{Prefix: []byte(`p`)})
+	require.NoError(t, err)
+	require.Empty(t, list.Items)
+	list, err = st.Scan(schema.ScanOptions{Prefix: []byte(`o`)})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	// the expired entry is kept in the tree and can still be read and verified by index
+	item, err = st.ByIndex(schema.Index{Index: expiring.Index})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`v2`), item.Value)
+	safeItem, err = st.BySafeIndex(schema.SafeIndexOptions{Index: expiring.Index})
+	require.NoError(t, err)
+	leaf := api.Digest(expiring.Index, []byte(`personal`), []byte(`v2`))
+	require.Equal(t, leaf[:], safeItem.Proof.Leaf)
+}
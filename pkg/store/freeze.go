@@ -0,0 +1,155 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"math"
+	"sync"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// frozenLayer prefixes the keys of the freeze entries, like timeLayer it's out of the range of the tree layers
+const frozenLayer = uint8(254)
+
+// frozenKey returns the key of the entry freezing prefix
+func frozenKey(prefix []byte) []byte {
+	k := make([]byte, 1+1+len(prefix))
+	k[0] = tsPrefix
+	k[1] = frozenLayer
+	copy(k[2:], prefix)
+	return k
+}
+
+func isFrozenKey(key []byte) bool {
+	return len(key) > 1+1 && key[0] == tsPrefix && key[1] == frozenLayer
+}
+
+// frozenPrefixes holds the key prefixes under which no further writes are allowed
+type frozenPrefixes struct {
+	sync.RWMutex
+	prefixes [][]byte
+}
+
+// covers tells if key falls under a frozen prefix, the caller must hold the lock
+func (f *frozenPrefixes) covers(key []byte) bool {
+	for _, p := range f.prefixes {
+		if bytes.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// guard returns ErrPrefixFrozen if any of keys falls under a frozen prefix.
+// Otherwise it keeps the prefixes locked until release is called, so that a write which passed the check
+// gets its index before any freeze entry committed in the meantime.
+func (f *frozenPrefixes) guard(keys ...[]byte) (release func(), err error) {
+	f.RLock()
+	for _, k := range keys {
+		if f.covers(k) {
+			f.RUnlock()
+			return nil, ErrPrefixFrozen
+		}
+	}
+	return f.RUnlock, nil
+}
+
+// loadFrozenPrefixes reads the prefixes frozen by previous FreezePrefix calls
+func loadFrozenPrefixes(db *badger.DB) *frozenPrefixes {
+	f := &frozenPrefixes{}
+	txn := db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	it := txn.NewIterator(badger.IteratorOptions{
+		Prefix: []byte{tsPrefix, frozenLayer},
+	})
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		f.prefixes = append(f.prefixes, it.Item().KeyCopy(nil)[2:])
+	}
+	return f
+}
+
+// FreezePrefix seals prefix: once it returns, every write of a key or reference having prefix fails with ErrPrefixFrozen.
+// The freeze is recorded as an entry of the tree, so its index can be verified like any other entry.
+// Sorted sets are not affected, as adding a score does not modify the scored key.
+func (t *Store) FreezePrefix(prefix schema.KeyPrefix) (index *schema.Index, err error) {
+	if len(prefix.Prefix) == 0 || isReservedKey(prefix.Prefix) {
+		return nil, ErrInvalidKeyPrefix
+	}
+
+	t.frozen.Lock()
+	defer t.frozen.Unlock()
+
+	if t.frozen.covers(prefix.Prefix) {
+		return nil, ErrPrefixFrozen
+	}
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, true)
+	defer txn.Discard()
+
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
+	key := frozenKey(prefix.Prefix)
+	tsEntry := t.tree.NewEntry(key, prefix.Prefix)
+
+	if err = txn.SetEntry(&badger.Entry{
+		Key:      key,
+		Value:    wrapValue(prefix.Prefix, tsEntry.ts),
+		UserMeta: bitChecksummedEntry,
+	}); err != nil {
+		return nil, mapError(err)
+	}
+
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, tsEntry.Index(), tsEntry.Index())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	err = txn.CommitAt(tsEntry.ts, nil)
+	sequenced(err)
+	if err != nil {
+		t.tree.Discard(tsEntry)
+		return nil, mapError(err)
+	}
+	t.tree.Commit(tsEntry)
+
+	t.frozen.prefixes = append(t.frozen.prefixes, append([]byte{}, prefix.Prefix...))
+
+	return &schema.Index{Index: tsEntry.Index(), Sequence: seq}, nil
+}
+
+// FrozenPrefixes returns the prefixes sealed by FreezePrefix
+func (t *Store) FrozenPrefixes() [][]byte {
+	t.frozen.RLock()
+	defer t.frozen.RUnlock()
+
+	prefixes := make([][]byte, len(t.frozen.prefixes))
+	for i, p := range t.frozen.prefixes {
+		prefixes[i] = append([]byte{}, p...)
+	}
+	return prefixes
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"math"
+	"os"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreFreezePrefix(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	_, err := st.Set(schema.KeyValue{Key: []byte(`sealed1`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+
+	index, err := st.FreezePrefix(schema.KeyPrefix{Prefix: []byte(`sealed`)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), index.Index)
+	assert.Equal(t, [][]byte{[]byte(`sealed`)}, st.FrozenPrefixes())
+
+	_, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte(`sealed2`)})
+	assert.Equal(t, ErrPrefixFrozen, err)
+	_, err = st.FreezePrefix(schema.KeyPrefix{})
+	assert.Equal(t, ErrInvalidKeyPrefix, err)
+	_, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte{tsPrefix}})
+	assert.Equal(t, ErrInvalidKeyPrefix, err)
+
+	// no write is allowed under the frozen prefix
+	_, err = st.Set(schema.KeyValue{Key: []byte(`sealed1`), Value: []byte(`v2`)})
+	assert.Equal(t, ErrPrefixFrozen, err)
+	_, err = st.SafeSet(schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`sealed2`), Value: []byte(`v`)}})
+	assert.Equal(t, ErrPrefixFrozen, err)
+	_, err = st.SetBatch(schema.KVList{KVs: []*schema.KeyValue{
+		{Key: []byte(`open`), Value: []byte(`v`)},
+		{Key: []byte(`sealed3`), Value: []byte(`v`)},
+	}})
+	assert.Equal(t, ErrPrefixFrozen, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`sealedRef`), Key: []byte(`sealed1`)})
+	assert.Equal(t, ErrPrefixFrozen, err)
+	_, err = st.SafeReference(schema.SafeReferenceOptions{Ro: &schema.ReferenceOptions{Reference: []byte(`sealedRef`), Key: []byte(`sealed1`)}})
+	assert.Equal(t, ErrPrefixFrozen, err)
+	_, err = st.ExecAllOps(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`sealed4`), Value: []byte(`v`)}}},
+	}})
+	assert.Equal(t, ErrPrefixFrozen, err)
+
+	// frozen entries are still readable and referenceable from outside the prefix
+	item, err := st.Get(schema.Key{Key: []byte(`sealed1`)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v1`), item.Value)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`sealed1`)})
+	assert.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`open`), Value: []byte(`v`)})
+	assert.NoError(t, err)
+
+	// the freeze entry is included in the tree
+	safeItem, err := st.BySafeIndex(schema.SafeIndexOptions{Index: index.Index})
+	require.NoError(t, err)
+	assert.Equal(t, frozenKey([]byte(`sealed`)), safeItem.Item.Key)
+	assert.Equal(t, []byte(`sealed`), safeItem.Item.Value)
+	leaf := api.Digest(index.Index, safeItem.Item.Key, safeItem.Item.Value)
+	assert.True(t, safeItem.Proof.Verify(leaf[:], schema.Root{}))
+
+	// and checksummed as any other record
+	txn := st.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+	record, err := txn.Get(frozenKey([]byte(`sealed`)))
+	require.NoError(t, err)
+	assert.Equal(t, bitChecksummedEntry, record.UserMeta()&bitChecksummedEntry)
+
+	entries, leaves := st.CountEntriesAndLeaves()
+	assert.Equal(t, leaves, entries)
+}
+
+func TestStoreFreezePrefixReopen(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+
+	st, err := Open(opts, badgerOpts)
+	require.NoError(t, err)
+	_, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte(`sealed`)})
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	assert.Equal(t, [][]byte{[]byte(`sealed`)}, st.FrozenPrefixes())
+	_, err = st.Set(schema.KeyValue{Key: []byte(`sealed1`), Value: []byte(`v`)})
+	assert.Equal(t, ErrPrefixFrozen, err)
+}
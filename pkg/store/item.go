@@ -30,12 +30,16 @@ func itemToSchema(key []byte, item *badger.Item) (*schema.Item, error) {
 		key = item.KeyCopy(key)
 	}
 
-	v, ts := UnwrapValueWithTS(value)
+	v, ts, err := unwrapValue(item.UserMeta(), value)
+	if err != nil {
+		return nil, err
+	}
 
 	return &schema.Item{
-		Key:   key,
-		Value: v,
-		Index: ts - 1,
+		Key:     key,
+		Value:   v,
+		Index:   ts - 1,
+		Deleted: item.UserMeta()&bitTombstoneEntry == bitTombstoneEntry,
 	}, nil
 }
 
@@ -0,0 +1,194 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// KeyInterceptor rewrites the keys crossing the store API, so that a middleware can confine every request
+// to a partition of the key space, e.g. by injecting the prefix of the tenant found in the request context.
+type KeyInterceptor interface {
+	// InKey maps a key received by the API to the key actually stored
+	InKey(ctx context.Context, key []byte) ([]byte, error)
+	// OutKey maps a stored key back to the key returned by the API. ok is false if the key is not visible to the request.
+	OutKey(ctx context.Context, key []byte) (out []byte, ok bool)
+}
+
+type prefixKeyInterceptor struct {
+	prefixOf func(ctx context.Context) ([]byte, error)
+}
+
+// NewPrefixKeyInterceptor returns a KeyInterceptor storing every key under the prefix returned by prefixOf
+// for the request, e.g. the tenant of the logged in user, and hiding the keys stored under other prefixes.
+// Keys are not rewritten for requests without a prefix.
+func NewPrefixKeyInterceptor(prefixOf func(ctx context.Context) ([]byte, error)) KeyInterceptor {
+	return &prefixKeyInterceptor{prefixOf: prefixOf}
+}
+
+func (p *prefixKeyInterceptor) InKey(ctx context.Context, key []byte) ([]byte, error) {
+	prefix, err := p.prefixOf(ctx)
+	if err != nil || len(prefix) == 0 {
+		return key, err
+	}
+	k := make([]byte, 0, len(prefix)+len(key))
+	return append(append(k, prefix...), key...), nil
+}
+
+func (p *prefixKeyInterceptor) OutKey(ctx context.Context, key []byte) ([]byte, bool) {
+	prefix, err := p.prefixOf(ctx)
+	if err != nil {
+		return nil, false
+	}
+	if !bytes.HasPrefix(key, prefix) {
+		return nil, false
+	}
+	return key[len(prefix):], true
+}
+
+// InterceptRequest rewrites in place, with ki, the keys, prefixes and set names of a request of the store API.
+// Requests without keys are left untouched.
+func InterceptRequest(ctx context.Context, ki KeyInterceptor, req interface{}) (err error) {
+	in := func(key *[]byte) {
+		if err == nil && *key != nil {
+			*key, err = ki.InKey(ctx, *key)
+		}
+	}
+	inReference := func(ro *schema.ReferenceOptions) {
+		if ro != nil {
+			in(&ro.Reference)
+			in(&ro.Key)
+		}
+	}
+	inZAdd := func(zo *schema.ZAddOptions) {
+		if zo != nil {
+			in(&zo.Set)
+			in(&zo.Key)
+		}
+	}
+	switch r := req.(type) {
+	case *schema.Key:
+		in(&r.Key)
+	case *schema.KeyValue:
+		in(&r.Key)
+	case *schema.SafeSetOptions:
+		if r.Kv != nil {
+			in(&r.Kv.Key)
+		}
+	case *schema.SafeGetOptions:
+		in(&r.Key)
+	case *schema.KVList:
+		for _, kv := range r.KVs {
+			in(&kv.Key)
+		}
+	case *schema.KeyList:
+		for _, k := range r.Keys {
+			in(&k.Key)
+		}
+	case *schema.Ops:
+		for _, op := range r.Operations {
+			switch o := op.Operation.(type) {
+			case *schema.Op_KVs:
+				in(&o.KVs.Key)
+			case *schema.Op_ZOpts:
+				inZAdd(o.ZOpts)
+			case *schema.Op_ROpts:
+				inReference(o.ROpts)
+			}
+		}
+	case *schema.ScanOptions:
+		// an empty prefix scans all the keys visible to the request
+		r.Prefix, err = ki.InKey(ctx, r.Prefix)
+		if len(r.Offset) > 0 {
+			in(&r.Offset)
+		}
+	case *schema.KeyPrefix:
+		r.Prefix, err = ki.InKey(ctx, r.Prefix)
+	case *schema.SampleOptions:
+		r.Prefix, err = ki.InKey(ctx, r.Prefix)
+	case *schema.HistoryOptions:
+		in(&r.Key)
+	case *schema.ValueHashOptions:
+		in(&r.Key)
+	case *schema.ReferenceOptions:
+		inReference(r)
+	case *schema.SafeReferenceOptions:
+		inReference(r.Ro)
+	case *schema.CompareAndReferenceOptions:
+		in(&r.Reference)
+		if r.Kv != nil {
+			in(&r.Kv.Key)
+		}
+	case *schema.ZAddOptions:
+		inZAdd(r)
+	case *schema.SafeZAddOptions:
+		inZAdd(r.Zopts)
+	case *schema.ZScanOptions:
+		in(&r.Set)
+	}
+	return err
+}
+
+// InterceptResponse rewrites in place, with ki, the keys of a response of the store API, dropping from lists
+// the entries not visible to the request. ErrKeyNotFound is returned for a single entry not visible to the request.
+// Responses carrying proofs are left untouched, since the proven leaves are computed over the stored keys.
+func InterceptResponse(ctx context.Context, ki KeyInterceptor, resp interface{}) error {
+	out := func(item *schema.Item) bool {
+		if item == nil {
+			return true
+		}
+		key, ok := ki.OutKey(ctx, item.Key)
+		if ok {
+			item.Key = key
+		}
+		return ok
+	}
+	switch r := resp.(type) {
+	case *schema.Item:
+		if !out(r) {
+			return ErrKeyNotFound
+		}
+	case *schema.ItemList:
+		items := r.Items[:0]
+		for _, item := range r.Items {
+			if out(item) {
+				items = append(items, item)
+			}
+		}
+		r.Items = items
+	case *schema.Page:
+		items := r.Items[:0]
+		for _, item := range r.Items {
+			if out(item) {
+				items = append(items, item)
+			}
+		}
+		r.Items = items
+	case *schema.ZItemList:
+		items := r.Items[:0]
+		for _, item := range r.Items {
+			if out(item.Item) {
+				items = append(items, item)
+			}
+		}
+		r.Items = items
+	}
+	return nil
+}
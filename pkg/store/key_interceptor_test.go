@@ -0,0 +1,108 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantKey struct{}
+
+func tenantPrefix(ctx context.Context) ([]byte, error) {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	if tenant == "invalid" {
+		return nil, errors.New("invalid tenant")
+	}
+	if tenant == "" {
+		return nil, nil
+	}
+	return []byte(tenant + "/"), nil
+}
+
+func TestPrefixKeyInterceptor(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	ki := NewPrefixKeyInterceptor(tenantPrefix)
+	acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+	globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+
+	set := func(ctx context.Context, key string, value string) {
+		kv := &schema.KeyValue{Key: []byte(key), Value: []byte(value)}
+		require.NoError(t, InterceptRequest(ctx, ki, kv))
+		_, err := st.Set(*kv)
+		require.NoError(t, err)
+	}
+	set(acme, "k1", "acme1")
+	set(acme, "k2", "acme2")
+	set(globex, "k1", "globex1")
+
+	// keys are stored under the prefix of the tenant
+	item, err := st.Get(schema.Key{Key: []byte("globex/k1")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("globex1"), item.Value)
+
+	key := &schema.Key{Key: []byte("k1")}
+	require.NoError(t, InterceptRequest(acme, ki, key))
+	item, err = st.Get(*key)
+	require.NoError(t, err)
+	require.NoError(t, InterceptResponse(acme, ki, item))
+	require.Equal(t, []byte("k1"), item.Key)
+	require.Equal(t, []byte("acme1"), item.Value)
+
+	// scans only see the keys of the tenant
+	scan := &schema.ScanOptions{}
+	require.NoError(t, InterceptRequest(globex, ki, scan))
+	list, err := st.Scan(*scan)
+	require.NoError(t, err)
+	require.NoError(t, InterceptResponse(globex, ki, list))
+	require.Len(t, list.Items, 1)
+	require.Equal(t, []byte("k1"), list.Items[0].Key)
+
+	// entries of other tenants are filtered out
+	page, err := st.IScan(schema.IScanOptions{PageSize: 10, PageNumber: 1})
+	require.NoError(t, err)
+	require.NoError(t, InterceptResponse(acme, ki, page))
+	require.Len(t, page.Items, 2)
+	item, err = st.ByIndex(schema.Index{Index: 2})
+	require.NoError(t, err)
+	require.Equal(t, ErrKeyNotFound, InterceptResponse(acme, ki, item))
+
+	// references and sorted sets are confined to the tenant as well
+	ref := &schema.ReferenceOptions{Reference: []byte("ref"), Key: []byte("k2")}
+	require.NoError(t, InterceptRequest(acme, ki, ref))
+	require.Equal(t, []byte("acme/ref"), ref.Reference)
+	require.Equal(t, []byte("acme/k2"), ref.Key)
+	zadd := &schema.SafeZAddOptions{Zopts: &schema.ZAddOptions{Set: []byte("set"), Key: []byte("k1")}}
+	require.NoError(t, InterceptRequest(acme, ki, zadd))
+	require.Equal(t, []byte("acme/set"), zadd.Zopts.Set)
+	require.Equal(t, []byte("acme/k1"), zadd.Zopts.Key)
+
+	// requests without tenant are not rewritten
+	key = &schema.Key{Key: []byte("acme/k1")}
+	require.NoError(t, InterceptRequest(context.Background(), ki, key))
+	require.Equal(t, []byte("acme/k1"), key.Key)
+
+	invalid := context.WithValue(context.Background(), tenantKey{}, "invalid")
+	require.Error(t, InterceptRequest(invalid, ki, &schema.Key{Key: []byte("k1")}))
+	require.Equal(t, ErrKeyNotFound, InterceptResponse(invalid, ki, &schema.Item{Key: []byte("acme/k1")}))
+}
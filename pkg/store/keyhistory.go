@@ -0,0 +1,83 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/merkletree"
+)
+
+// KeyHistoryDump returns all the revisions of the provided key along with their inclusion proofs.
+// All proofs are computed against the same root, which is included in the dump, so that the whole
+// history can be verified offline.
+func (t *Store) KeyHistoryDump(key schema.Key) (*schema.KeyHistoryDump, error) {
+	if err := checkKey(key.Key); err != nil {
+		return nil, err
+	}
+
+	list, err := t.History(&schema.HistoryOptions{Key: key.Key})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	// ensure the last revision has been included into the tree
+	var last uint64
+	for _, item := range list.Items {
+		if item.Index > last {
+			last = item.Index
+		}
+	}
+	t.tree.WaitUntil(last)
+
+	ts := t.tree
+	ts.RLock()
+	defer ts.RUnlock()
+
+	at := ts.w - 1
+
+	indexes := make([]uint64, len(list.Items))
+	for i, item := range list.Items {
+		indexes[i] = item.Index
+	}
+	proofs, err := inclusionProofs(ts, at, indexes)
+	if err != nil {
+		return nil, err
+	}
+	root := merkletree.Root(ts)
+
+	entries := make([]*schema.KeyHistoryEntry, len(list.Items))
+	for i, item := range list.Items {
+		entries[i] = &schema.KeyHistoryEntry{
+			Item:  item,
+			Proof: proofs[i],
+		}
+	}
+
+	return &schema.KeyHistoryDump{
+		Key:     key.Key,
+		Entries: entries,
+		Root: &schema.Root{
+			Payload: &schema.RootIndex{
+				Index: at,
+				Root:  root[:],
+			},
+		},
+	}, nil
+}
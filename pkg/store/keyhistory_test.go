@@ -0,0 +1,61 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreKeyHistoryDump(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	for _, v := range []string{`v1`, `v2`, `v3`} {
+		_, err := st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(v)})
+		require.NoError(t, err)
+		_, err = st.Set(schema.KeyValue{Key: []byte(`otherKey`), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	st.tree.WaitUntil(5)
+
+	dump, err := st.KeyHistoryDump(schema.Key{Key: []byte(`key`)})
+	require.NoError(t, err)
+	assert.Len(t, dump.Entries, 3)
+	assert.Equal(t, []byte(`key`), dump.Key)
+	assert.Equal(t, uint64(5), dump.Root.GetIndex())
+	assert.NoError(t, dump.Verify())
+
+	root, err := st.CurrentRoot()
+	require.NoError(t, err)
+	assert.Equal(t, root.GetRoot(), dump.Root.GetRoot())
+
+	dump.Entries[1].Item.Value = []byte(`tampered`)
+	assert.Equal(t, schema.ErrCorruptedKeyHistoryDump, dump.Verify())
+
+	_, err = st.KeyHistoryDump(schema.Key{Key: []byte(`missingKey`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, err = st.KeyHistoryDump(schema.Key{Key: []byte{}})
+	assert.Error(t, err)
+
+	var emptyDump *schema.KeyHistoryDump
+	assert.Equal(t, schema.ErrEmptyKeyHistoryDump, emptyDump.Verify())
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// ManifestFileName is the name of the manifest file written into the data directory of every store
+const ManifestFileName = "immudb.manifest"
+
+// FormatVersion is the version of the on-disk format written by this version of the store.
+// It's increased by every change of the format older versions can't read:
+//  1. the first format
+//  2. values may be compressed, see bitCompressedEntry
+//  3. values may be pruned, see bitPrunedEntry
+const FormatVersion = 3
+
+// minFormatVersion is the oldest format version this version of the store can read
+const minFormatVersion = 1
+
+// HashAlgorithm is the algorithm hashing the entries and the nodes of the Merkle tree
+const HashAlgorithm = "sha256"
+
+// Manifest describes the on-disk format of the data directory of a store, so that upgrades and tooling can tell
+// whether it can be opened without opening it. Options are the store options affecting how data is written to disk,
+// the directory can only be opened with the same ones.
+type Manifest struct {
+	FormatVersion      uint32            `json:"formatVersion"`
+	HashAlgorithm      string            `json:"hashAlgorithm"`
+	Options            map[string]string `json:"options"`
+	OptionsFingerprint string            `json:"optionsFingerprint"`
+}
+
+// newManifest returns the manifest of a data directory written with badgerOpts
+func newManifest(badgerOpts badger.Options) Manifest {
+	options := map[string]string{
+		"encryption": strconv.FormatBool(len(badgerOpts.EncryptionKey) > 0),
+	}
+	return Manifest{
+		FormatVersion:      FormatVersion,
+		HashAlgorithm:      HashAlgorithm,
+		Options:            options,
+		OptionsFingerprint: optionsFingerprint(options),
+	}
+}
+
+// optionsFingerprint hashes options regardless of their order
+func optionsFingerprint(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s=%s\n", name, options[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReadManifest reads the manifest of the data directory dir, without opening the store.
+// ErrManifestNotFound is returned for directories written by versions not writing manifests.
+func ReadManifest(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, ManifestFileName)
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrManifestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{}
+	if err = json.Unmarshal(raw, m); err != nil {
+		return nil, fmt.Errorf("%w: manifest %s is corrupted (%v): restore it from a backup, "+
+			"or remove it to have it written again on the next open if the directory is known to be compatible",
+			ErrIncompatibleDataDir, path, err)
+	}
+	return m, nil
+}
+
+// checkCompatible returns an error telling how to proceed if the data directory described by m can't be opened as
+// described by expected
+func (m *Manifest) checkCompatible(dir string, expected Manifest) error {
+	switch {
+	case m.FormatVersion > FormatVersion:
+		return fmt.Errorf("%w: %s has format version %d, newer than the latest version %d supported: upgrade immudb to open it",
+			ErrIncompatibleDataDir, dir, m.FormatVersion, FormatVersion)
+	case m.FormatVersion < minFormatVersion:
+		return fmt.Errorf("%w: %s has format version %d, older than the oldest version %d supported: "+
+			"open it with a previous version of immudb, dump it and restore the dump with this version",
+			ErrIncompatibleDataDir, dir, m.FormatVersion, minFormatVersion)
+	case m.HashAlgorithm != expected.HashAlgorithm:
+		return fmt.Errorf("%w: %s is hashed with %s, while only %s is supported",
+			ErrIncompatibleDataDir, dir, m.HashAlgorithm, expected.HashAlgorithm)
+	case m.OptionsFingerprint != expected.OptionsFingerprint:
+		var diffs []string
+		for name, value := range expected.Options {
+			if m.Options[name] != value {
+				diffs = append(diffs, fmt.Sprintf("%s=%s instead of %s", name, m.Options[name], value))
+			}
+		}
+		sort.Strings(diffs)
+		return fmt.Errorf("%w: %s has been written with different options (%s): open it with the options it has been written with",
+			ErrIncompatibleDataDir, dir, strings.Join(diffs, ", "))
+	}
+	return nil
+}
+
+// writeManifest atomically writes m into the data directory dir
+func writeManifest(dir string, m Manifest) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ManifestFileName)
+	if err = ioutil.WriteFile(path+".tmp", raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// openManifest checks the manifest of the data directory dir, if any, before the store is opened, and tells if the
+// manifest is up to date or has to be written
+func openManifest(dir string, badgerOpts badger.Options) (Manifest, bool, error) {
+	expected := newManifest(badgerOpts)
+	m, err := ReadManifest(dir)
+	if err == ErrManifestNotFound {
+		// a new directory, or one written by a version not writing manifests, whose format is the first one
+		return expected, false, nil
+	}
+	if err != nil {
+		return Manifest{}, false, err
+	}
+	if err = m.checkCompatible(dir, expected); err != nil {
+		return Manifest{}, false, err
+	}
+	if m.FormatVersion < FormatVersion {
+		// older formats are upgraded on open, so that older versions refuse the records they can't read
+		m.FormatVersion = FormatVersion
+		return *m, false, nil
+	}
+	return *m, true, nil
+}
+
+// Manifest returns the manifest describing the on-disk format of the store, for in-memory stores as well
+func (t *Store) Manifest() Manifest {
+	return t.manifest
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	slog := logger.NewSimpleLoggerWithLevel("manifest", os.Stderr, logger.LogError)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+	open := func() error {
+		st, err := Open(opts, badgerOpts)
+		if err == nil {
+			st.Close()
+		}
+		return err
+	}
+
+	st, err := Open(opts, badgerOpts)
+	require.NoError(t, err)
+	manifest := st.Manifest()
+	require.Equal(t, uint32(FormatVersion), manifest.FormatVersion)
+	require.Equal(t, HashAlgorithm, manifest.HashAlgorithm)
+	require.Equal(t, "false", manifest.Options["encryption"])
+	require.NoError(t, st.Close())
+
+	m, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, manifest, *m)
+	require.NoError(t, open())
+
+	// directories written by versions not writing manifests get one
+	require.NoError(t, os.Remove(filepath.Join(dir, ManifestFileName)))
+	_, err = ReadManifest(dir)
+	require.Equal(t, ErrManifestNotFound, err)
+	require.NoError(t, open())
+	m, err = ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, manifest, *m)
+
+	// older formats are upgraded
+	require.NoError(t, writeManifest(dir, Manifest{FormatVersion: 1, HashAlgorithm: HashAlgorithm, Options: m.Options, OptionsFingerprint: m.OptionsFingerprint}))
+	require.NoError(t, open())
+	m, err = ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, manifest, *m)
+
+	for _, c := range []struct {
+		manifest Manifest
+		message  string
+	}{
+		{Manifest{FormatVersion: FormatVersion + 1, HashAlgorithm: HashAlgorithm, Options: m.Options, OptionsFingerprint: m.OptionsFingerprint}, "upgrade immudb"},
+		{Manifest{FormatVersion: minFormatVersion - 1, HashAlgorithm: HashAlgorithm, Options: m.Options, OptionsFingerprint: m.OptionsFingerprint}, "dump it"},
+		{Manifest{FormatVersion: FormatVersion, HashAlgorithm: "blake2b", Options: m.Options, OptionsFingerprint: m.OptionsFingerprint}, "hashed with blake2b"},
+		{Manifest{FormatVersion: FormatVersion, HashAlgorithm: HashAlgorithm,
+			Options:            map[string]string{"encryption": "true"},
+			OptionsFingerprint: optionsFingerprint(map[string]string{"encryption": "true"})}, "encryption=true instead of false"},
+	} {
+		require.NoError(t, writeManifest(dir, c.manifest))
+		err = open()
+		require.True(t, errors.Is(err, ErrIncompatibleDataDir))
+		require.Contains(t, err.Error(), c.message)
+	}
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ManifestFileName), []byte(`{`), 0644))
+	err = open()
+	require.True(t, errors.Is(err, ErrIncompatibleDataDir))
+	require.Contains(t, err.Error(), "corrupted")
+
+	// in-memory stores have no manifest file
+	opts, badgerOpts = DefaultOptions("", slog)
+	st, err = Open(opts, badgerOpts.WithInMemory(true))
+	require.NoError(t, err)
+	defer st.Close()
+	require.Equal(t, manifest, st.Manifest())
+}
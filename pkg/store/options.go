@@ -17,16 +17,33 @@ limitations under the License.
 package store
 
 import (
+	"github.com/codenotary/immudb/pkg/clock"
 	"github.com/codenotary/immudb/pkg/logger"
 
 	"github.com/dgraph-io/badger/v2"
 
 	"runtime"
+	"time"
 )
 
 // Options ...
 type Options struct {
-	log logger.Logger
+	log              logger.Logger
+	strictAppendOnly bool
+	sequencer        bool
+	clock            clock.Clock
+
+	treeCheckpointInterval uint64
+	syncWrites             bool
+	treeSync               bool
+
+	valueCompression ValueCompression
+
+	encryptionKey   []byte
+	dataKeyRotation time.Duration
+
+	// commitGate is set with WithCommitScheduler, only available in builds with the storetest tag
+	commitGate commitGate
 }
 
 // DefaultOptions ...
@@ -39,12 +56,89 @@ func DefaultOptions(path string, log logger.Logger) (Options, badger.Options) {
 	if runtime.GOOS == "windows" {
 		badgerOptions.Truncate = true
 	}
-	return Options{log}, badgerOptions
+	return Options{log: log}, badgerOptions
+}
+
+// WithStrictAppendOnly enables the strict append-only mode. When enabled, any operation that could
+// create the appearance of out-of-order history (e.g. references to not yet committed indexes or
+// restores with externally supplied indexes) is rejected.
+func (o Options) WithStrictAppendOnly(strictAppendOnly bool) Options {
+	o.strictAppendOnly = strictAppendOnly
+	return o
+}
+
+// WithSequencer enables the sequencer mode. When enabled, every write is assigned a gap-free sequence number
+// in commit order, which can be used to read back the entries it wrote.
+func (o Options) WithSequencer(sequencer bool) Options {
+	o.sequencer = sequencer
+	return o
+}
+
+// WithClock sets the clock used to timestamp committed entries, the system clock is used by default.
+// Timestamps never decrease as indexes increase, even if the clock is stepped back.
+func (o Options) WithClock(c clock.Clock) Options {
+	o.clock = c
+	return o
+}
+
+// WithTreeCheckpointInterval sets the number of entries after which the tree is checkpointed, i.e. its
+// upper layers and frontier are persisted. A store which has not been closed cleanly appends again to the tree
+// only the entries after the last checkpoint at startup, while a cleanly closed store never replays any entry.
+// Smaller intervals make the startup after a crash faster at the cost of more frequent writes.
+// By default the tree is checkpointed every 375000 entries, which is also the largest interval: the nodes not yet
+// checkpointed must fit in the tree caches.
+func (o Options) WithTreeCheckpointInterval(entries uint64) Options {
+	o.treeCheckpointInterval = entries
+	return o
+}
+
+// WithSyncWrites makes every commit of entries wait for the data to be synced to disk, so that no acknowledged
+// write is lost on a crash. It does not affect the tree, which can be rebuilt from the data, see WithTreeSync.
+// By default writes are not synced.
+func (o Options) WithSyncWrites(syncWrites bool) Options {
+	o.syncWrites = syncWrites
+	return o
+}
+
+// WithTreeSync makes every checkpoint of the tree be synced to disk, see WithTreeCheckpointInterval.
+// Between two checkpoints the inner nodes of the tree are only kept in memory, and the entries committed after
+// the last durable checkpoint are appended again to the tree at startup: syncing the tree never protects data,
+// it bounds the replay after a crash. Checkpoints are always synced if writes are, see WithSyncWrites.
+func (o Options) WithTreeSync(treeSync bool) Options {
+	o.treeSync = treeSync
+	return o
+}
+
+// WithValueCompression sets the algorithm compressing the values before they are persisted, none by default.
+// Only values large enough to be worth compressing are compressed, and the leaves are hashed from the values as
+// they are written, so proofs are not affected. Every record tells whether and how it has been compressed, so the
+// compression can be changed at any time: records written before stay readable.
+func (o Options) WithValueCompression(c ValueCompression) Options {
+	o.valueCompression = c
+	return o
+}
+
+// WithEncryptionKey encrypts the data at rest with AES, the key being 16, 24 or 32 bytes long for AES-128, AES-192
+// or AES-256. The data is encrypted with data keys, themselves encrypted with key and rotated periodically, see
+// WithDataKeyRotation. A data directory written with a key can only be opened with it, until it's rotated with
+// RotateEncryptionKey, and a data directory written without key can't be opened with one.
+// Data is not encrypted by default.
+func (o Options) WithEncryptionKey(key []byte) Options {
+	o.encryptionKey = key
+	return o
+}
+
+// WithDataKeyRotation sets how often a new data key is generated to encrypt the data written from then on,
+// 10 days by default. It only applies if the store is encrypted, see WithEncryptionKey.
+func (o Options) WithDataKeyRotation(d time.Duration) Options {
+	o.dataKeyRotation = d
+	return o
 }
 
 // WriteOptions ...
 type WriteOptions struct {
 	asyncCommit bool
+	expiresAt   time.Time
 }
 
 func makeWriteOptions(opts ...WriteOption) *WriteOptions {
@@ -64,3 +158,14 @@ func WithAsyncCommit(async bool) WriteOption {
 		opts.asyncCommit = async
 	}
 }
+
+// WithExpiresAt sets the deadline after which the entry written by Set can't be read by key anymore:
+// it's neither returned by Get, SafeGet and History nor included in scans. The entry is kept in the tree, so that
+// the history stays verifiable, and it's still returned by the reads by index, e.g. ByIndex, IScan
+// and the references pinned to its index.
+// Only Set honors it, the zero time (default) never expires.
+func WithExpiresAt(expiresAt time.Time) WriteOption {
+	return func(opts *WriteOptions) {
+		opts.expiresAt = expiresAt
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"sync/atomic"
+)
+
+const (
+	// minScanPrefetch is the read-ahead used for scans expected to be short, it matches badger's own minimum
+	minScanPrefetch = 2
+	// defaultScanPrefetch is used until some scan has been observed, it matches badger's default
+	defaultScanPrefetch = 100
+	// maxScanPrefetch bounds the number of values loaded ahead by a single iterator, and therefore its memory usage
+	maxScanPrefetch = 1000
+)
+
+// scanPrefetcher tunes the iterator read-ahead of a kind of scan from the number of entries visited by the
+// recent ones: long sequential scans get a deep prefetch, so that value log reads are issued concurrently
+// and disk bandwidth is saturated, while short scans don't pay for values they'll never return.
+type scanPrefetcher struct {
+	// avg is an exponentially weighted moving average of the visited entries, scaled by ewmaScale
+	avg uint64
+}
+
+const (
+	ewmaScale = 1 << 10
+	// ewmaShift sets the weight of the last observation to 1/8
+	ewmaShift = 3
+)
+
+func newScanPrefetcher() *scanPrefetcher {
+	return &scanPrefetcher{}
+}
+
+// size returns the read-ahead to be used by a scan returning up to limit entries, 0 meaning no limit
+func (p *scanPrefetcher) size(limit uint64) int {
+	size := uint64(defaultScanPrefetch)
+	if avg := atomic.LoadUint64(&p.avg); avg > 0 {
+		// round up, so that any observed scan keeps a non-zero read-ahead
+		size = (avg + ewmaScale - 1) / ewmaScale
+	}
+	if limit > 0 && size > limit {
+		size = limit
+	}
+	if size < minScanPrefetch {
+		size = minScanPrefetch
+	}
+	if size > maxScanPrefetch {
+		size = maxScanPrefetch
+	}
+	return int(size)
+}
+
+// observe records the number of entries visited by a completed scan
+func (p *scanPrefetcher) observe(visited uint64) {
+	if visited > maxScanPrefetch {
+		visited = maxScanPrefetch
+	}
+	sample := visited * ewmaScale
+	for {
+		avg := atomic.LoadUint64(&p.avg)
+		next := sample
+		if avg > 0 {
+			next = avg - avg>>ewmaShift + sample>>ewmaShift
+		}
+		if atomic.CompareAndSwapUint64(&p.avg, avg, next) {
+			return
+		}
+	}
+}
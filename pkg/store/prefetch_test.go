@@ -0,0 +1,44 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanPrefetcher(t *testing.T) {
+	p := newScanPrefetcher()
+	assert.Equal(t, defaultScanPrefetch, p.size(0))
+	assert.Equal(t, 10, p.size(10))
+	assert.Equal(t, minScanPrefetch, p.size(1))
+
+	p.observe(5)
+	assert.Equal(t, 5, p.size(0))
+
+	for i := 0; i < 100; i++ {
+		p.observe(100000)
+	}
+	assert.Equal(t, maxScanPrefetch, p.size(0))
+	assert.Equal(t, 50, p.size(50))
+
+	for i := 0; i < 100; i++ {
+		p.observe(0)
+	}
+	assert.Equal(t, minScanPrefetch, p.size(0))
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// Stats returns the statistics of the keys having the specified prefix, read from a single snapshot: the number of
+// live keys, the ones neither deleted nor expired, and the number of entries of every version of the keys, along with
+// the size of their keys and values as stored. Reserved keys are left out.
+func (t *Store) Stats(prefix schema.KeyPrefix) (*schema.PrefixStats, error) {
+	if isReservedKey(prefix.Prefix) {
+		return nil, ErrInvalidKeyPrefix
+	}
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: false,
+		AllVersions:    true,
+		Prefix:         prefix.Prefix,
+	})
+	defer it.Close()
+
+	stats := &schema.PrefixStats{Prefix: prefix.Prefix}
+	var last []byte
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		key := item.Key()
+		if _, reserved := ReservedNamespace(key); reserved {
+			continue
+		}
+		size := uint64(len(key)) + uint64(item.ValueSize())
+		stats.Entries++
+		stats.EntriesSize += size
+
+		// versions are iterated latest first
+		if last != nil && bytes.Equal(key, last) {
+			continue
+		}
+		last = item.KeyCopy(last)
+		deleted, err := t.deleted(item)
+		if err != nil {
+			return nil, err
+		}
+		if !deleted {
+			stats.LiveKeys++
+			stats.LiveSize += size
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreStats(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	stats, err := st.Stats(schema.KeyPrefix{Prefix: nil})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), stats.Entries)
+
+	_, err = st.Set(schema.KeyValue{Key: []byte(`user/1`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`user/1`), Value: []byte(`v2`)})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`user/2`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+	_, err = st.Delete(schema.Key{Key: []byte(`user/2`)})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`group/1`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`users`), Score: &schema.Score{Score: 1}, Key: []byte(`user/1`)})
+	require.NoError(t, err)
+
+	stats, err = st.Stats(schema.KeyPrefix{Prefix: []byte(`user/`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`user/`), stats.Prefix)
+	require.Equal(t, uint64(1), stats.LiveKeys)
+	require.Equal(t, uint64(4), stats.Entries)
+	require.True(t, stats.LiveSize > 0)
+	require.True(t, stats.EntriesSize > 3*stats.LiveSize)
+
+	stats, err = st.Stats(schema.KeyPrefix{Prefix: []byte(`group/`)})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), stats.LiveKeys)
+	require.Equal(t, uint64(1), stats.Entries)
+	require.Equal(t, stats.LiveSize, stats.EntriesSize)
+
+	stats, err = st.Stats(schema.KeyPrefix{Prefix: []byte(`user/2`)})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), stats.LiveKeys)
+	require.Equal(t, uint64(2), stats.Entries)
+	require.Equal(t, uint64(0), stats.LiveSize)
+
+	// sorted set members and the tree are left out
+	stats, err = st.Stats(schema.KeyPrefix{Prefix: nil})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), stats.LiveKeys)
+	require.Equal(t, uint64(5), stats.Entries)
+
+	_, err = st.Stats(schema.KeyPrefix{Prefix: []byte{tsPrefix}})
+	require.Equal(t, ErrInvalidKeyPrefix, err)
+}
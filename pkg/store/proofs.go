@@ -17,10 +17,19 @@ limitations under the License.
 package store
 
 import (
+	"crypto/sha256"
+	"math/bits"
+	"runtime"
+	"sort"
+	"sync"
+
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/merkletree"
 )
 
+// maxProofWorkers bounds the number of goroutines used to compute inclusion proofs of a single batch
+var maxProofWorkers = runtime.NumCPU()
+
 // InclusionProof returns the inclusion proof of the specified index in the current tree
 func (s *Store) InclusionProof(index schema.Index) (*schema.InclusionProof, error) {
 
@@ -72,3 +81,218 @@ func (s *Store) ConsistencyProof(index schema.Index) (*schema.ConsistencyProof,
 		Path: path.ToSlice(),
 	}, nil
 }
+
+// InclusionProofs returns the inclusion proofs of the specified indexes, all computed against the current root.
+// Proofs are generated concurrently and share the upper tree nodes they have in common.
+func (s *Store) InclusionProofs(indexes []uint64) ([]*schema.InclusionProof, error) {
+
+	ts := s.tree
+	ts.RLock()
+	defer ts.RUnlock()
+
+	return inclusionProofs(ts, ts.w-1, indexes)
+}
+
+// inclusionProofs computes the inclusion proofs of indexes at the given tree width with a bounded worker pool.
+// The caller must hold the tree read lock for the whole call.
+func inclusionProofs(ts *treeStore, at uint64, indexes []uint64) ([]*schema.InclusionProof, error) {
+	proofs := make([]*schema.InclusionProof, len(indexes))
+	if len(indexes) == 0 {
+		return proofs, nil
+	}
+
+	nodes := newNodeCache(ts)
+	root := merkletree.Root(nodes)
+
+	workers := maxProofWorkers
+	if workers > len(indexes) {
+		workers = len(indexes)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var missing sync.Once
+	var err error
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				index := indexes[i]
+				leaf := nodes.Get(0, index)
+				if leaf == nil || index > at {
+					missing.Do(func() { err = ErrIndexNotFound })
+					continue
+				}
+				path := merkletree.InclusionProof(nodes, at, index)
+				proofs[i] = &schema.InclusionProof{
+					Index: index,
+					Leaf:  leaf[:],
+					Root:  root[:],
+					At:    at,
+					Path:  path.ToSlice(),
+				}
+			}
+		}()
+	}
+
+	for i := range indexes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+	return proofs, nil
+}
+
+// InclusionMultiProof returns a single proof of the inclusion of the entries at the specified indexes in the current
+// root. Unlike a proof per entry, the nodes the inclusion paths have in common are sent only once.
+func (s *Store) InclusionMultiProof(indexes []uint64) (*schema.MultiProof, error) {
+
+	ts := s.tree
+	ts.RLock()
+	defer ts.RUnlock()
+
+	return multiProof(ts, indexes)
+}
+
+// multiProof computes the multiproof of indexes against the current root, which may be listed in any order and
+// repeated. The caller must hold the tree read lock for the whole call.
+func multiProof(ts *treeStore, indexes []uint64) (*schema.MultiProof, error) {
+	if len(indexes) == 0 || ts.w == 0 {
+		return nil, ErrIndexNotFound
+	}
+
+	sorted := make([]uint64, len(indexes))
+	copy(sorted, indexes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	unique := sorted[:1]
+	for _, index := range sorted[1:] {
+		if index != unique[len(unique)-1] {
+			unique = append(unique, index)
+		}
+	}
+
+	nodes := newNodeCache(ts)
+	at := ts.w - 1
+	root := merkletree.Root(nodes)
+	proof := &schema.MultiProof{
+		At:      at,
+		Root:    root[:],
+		Indexes: unique,
+		Leaves:  make([][]byte, len(unique)),
+	}
+	for i, index := range unique {
+		leaf := nodes.Get(0, index)
+		if leaf == nil || index > at {
+			return nil, ErrIndexNotFound
+		}
+		proof.Leaves[i] = leaf[:]
+	}
+	multiProofNodes(nodes, 0, at, unique, proof)
+	return proof, nil
+}
+
+// multiProofNodes appends to proof the roots of the subtrees, within the one made of the leaves from l to r, holding
+// none of the proved indexes. Subtrees are split as by the Merkle Tree Hash of RFC 6962, and the nodes of the
+// current tree hold the root of every such subtree.
+func multiProofNodes(nodes merkletree.Storer, l, r uint64, indexes []uint64, proof *schema.MultiProof) {
+	if len(indexes) == 0 {
+		layer := uint8(bits.Len64(r - l))
+		proof.Nodes = append(proof.Nodes, nodes.Get(layer, l>>layer)[:])
+		return
+	}
+	if l == r {
+		return
+	}
+
+	k := uint64(1) << (bits.Len64(r-l) - 1)
+	split := sort.Search(len(indexes), func(i int) bool { return indexes[i] >= l+k })
+	multiProofNodes(nodes, l, l+k-1, indexes[:split], proof)
+	multiProofNodes(nodes, l+k, r, indexes[split:], proof)
+}
+
+// rootAt returns the root the tree had when its width was at+1.
+// The caller must hold the tree read lock for the whole call.
+func rootAt(ts *treeStore, at uint64) [sha256.Size]byte {
+	if at == ts.w-1 {
+		return merkletree.Root(ts)
+	}
+	return *mthAt(ts, 0, at)
+}
+
+// mthAt computes the Merkle Tree Hash of the leaves from l to r, as by RFC 6962. The roots of the complete subtrees
+// are frozen, so they're read from the nodes, the others are rebuilt since the nodes hold their current value only.
+func mthAt(nodes merkletree.Storer, l, r uint64) *[sha256.Size]byte {
+	if l == r {
+		return nodes.Get(0, l)
+	}
+
+	d := bits.Len64(r - l)
+	if r-l+1 == 1<<d {
+		return nodes.Get(uint8(d), l>>d)
+	}
+
+	k := uint64(1) << (d - 1)
+	c := [sha256.Size*2 + 1]byte{merkletree.NodePrefix}
+	copy(c[1:sha256.Size+1], mthAt(nodes, l, l+k-1)[:])
+	copy(c[sha256.Size+1:], mthAt(nodes, l+k, r)[:])
+	h := sha256.Sum256(c[:])
+	return &h
+}
+
+type nodeKey struct {
+	layer uint8
+	index uint64
+}
+
+// nodeCache is a read-only merkletree.Storer which memoizes the nodes fetched from the underlying tree,
+// so that nodes shared by many proofs (i.e. the upper layers) are read from storage only once.
+type nodeCache struct {
+	sync.RWMutex
+	ts    *treeStore
+	nodes map[nodeKey]*[sha256.Size]byte
+}
+
+func newNodeCache(ts *treeStore) *nodeCache {
+	return &nodeCache{
+		ts:    ts,
+		nodes: make(map[nodeKey]*[sha256.Size]byte),
+	}
+}
+
+func (c *nodeCache) Width() uint64 {
+	return c.ts.w
+}
+
+func (c *nodeCache) Set(layer uint8, index uint64, value [sha256.Size]byte) {
+	panic("store: nodeCache is read-only")
+}
+
+func (c *nodeCache) Get(layer uint8, index uint64) *[sha256.Size]byte {
+	k := nodeKey{layer: layer, index: index}
+
+	c.RLock()
+	v, ok := c.nodes[k]
+	c.RUnlock()
+	if ok {
+		return v
+	}
+
+	v = c.ts.Get(layer, index)
+	if v == nil {
+		return nil
+	}
+
+	c.Lock()
+	c.nodes[k] = v
+	c.Unlock()
+	return v
+}
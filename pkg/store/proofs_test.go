@@ -26,6 +26,7 @@ import (
 	"github.com/codenotary/immudb/pkg/api/schema"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInclusion(t *testing.T) {
@@ -101,3 +102,95 @@ func TestConsistency(t *testing.T) {
 	assert.True(t, proof.Verify(schema.Root{Payload: &schema.RootIndex{Index: index, Root: root5th[:]}}))
 	assert.Equal(t, root5th[:], proof.FirstRoot)
 }
+
+func TestInclusionProofs(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	var n uint64
+	for n = 0; n <= 64; n++ {
+		key := []byte(strconv.FormatUint(n, 10))
+		_, err := st.Set(schema.KeyValue{Key: key, Value: key})
+		assert.NoError(t, err, "n=%d", n)
+	}
+	st.tree.WaitUntil(n - 1)
+
+	indexes := []uint64{0, 5, 17, 31, 32, 63, 64, 5}
+	proofs, err := st.InclusionProofs(indexes)
+	assert.NoError(t, err)
+	assert.Len(t, proofs, len(indexes))
+
+	for i, index := range indexes {
+		expected, err := st.InclusionProof(schema.Index{Index: index})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, proofs[i], "index=%d", index)
+		assert.True(t, proofs[i].Verify(index, proofs[i].Leaf))
+	}
+
+	proofs, err = st.InclusionProofs(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, proofs)
+
+	_, err = st.InclusionProofs([]uint64{1, n + 10})
+	assert.Equal(t, ErrIndexNotFound, err)
+}
+
+func TestInclusionMultiProof(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	_, err := st.InclusionMultiProof([]uint64{0})
+	assert.Equal(t, ErrIndexNotFound, err)
+
+	var n uint64
+	for n = 0; n <= 64; n++ {
+		key := []byte(strconv.FormatUint(n, 10))
+		_, err := st.Set(schema.KeyValue{Key: key, Value: key})
+		assert.NoError(t, err, "n=%d", n)
+		st.tree.WaitUntil(n)
+
+		// every tree width, with leaves at both ends and in the middle
+		indexes := []uint64{n, 0, n / 2, n}
+		proof, err := st.InclusionMultiProof(indexes)
+		require.NoError(t, err)
+		assert.Equal(t, n, proof.At)
+		assert.True(t, proof.Verify(schema.Root{}), "n=%d", n)
+		for _, index := range indexes {
+			expected, err := st.InclusionProof(schema.Index{Index: index})
+			require.NoError(t, err)
+			assert.Equal(t, expected.Root, proof.Root)
+			assert.True(t, proof.Includes(index, expected.Leaf))
+		}
+	}
+	n--
+
+	indexes := []uint64{1, 5, 17, 31, 32, 63}
+	proof, err := st.InclusionMultiProof(indexes)
+	require.NoError(t, err)
+	assert.True(t, proof.Verify(schema.Root{}))
+
+	// the nodes shared by the inclusion paths are sent once
+	var paths int
+	for _, index := range indexes {
+		p, err := st.InclusionProof(schema.Index{Index: index})
+		require.NoError(t, err)
+		paths += len(p.Path)
+	}
+	assert.Less(t, len(proof.Nodes), paths)
+
+	leaf := proof.Leaves[2]
+	proof.Leaves[2] = proof.Leaves[1]
+	assert.False(t, proof.Verify(schema.Root{}))
+	proof.Leaves[2] = leaf
+	proof.Indexes[2]++
+	assert.False(t, proof.Verify(schema.Root{}))
+	proof.Indexes[2]--
+	proof.Nodes = proof.Nodes[1:]
+	assert.False(t, proof.Verify(schema.Root{}))
+	assert.False(t, proof.Includes(2, leaf))
+
+	_, err = st.InclusionMultiProof([]uint64{1, n + 10})
+	assert.Equal(t, ErrIndexNotFound, err)
+	_, err = st.InclusionMultiProof(nil)
+	assert.Equal(t, ErrIndexNotFound, err)
+}
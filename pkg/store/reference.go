@@ -1,6 +1,8 @@
 package store
 
 import (
+	"bytes"
+
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/dgraph-io/badger/v2"
 	"math"
@@ -10,6 +12,10 @@ import (
 // If the ReferenceOption.index is not provided the resolution will use only the key and last version of the item will be returned
 // If ReferenceOption.index is provided key is optional
 func (t *Store) Reference(refOpts *schema.ReferenceOptions, options ...WriteOption) (index *schema.Index, err error) {
+	// references can be concurrently added but not while a compare-and-reference is in progress
+	t.RLock()
+	defer t.RUnlock()
+
 	opts := makeWriteOptions(options...)
 	if isReservedKey(refOpts.Key) && refOpts.Index == nil {
 		return nil, ErrInvalidKey
@@ -17,10 +23,19 @@ func (t *Store) Reference(refOpts *schema.ReferenceOptions, options ...WriteOpti
 	if isReservedKey(refOpts.Reference) {
 		return nil, ErrInvalidReference
 	}
+	release, err := t.frozen.guard(refOpts.Reference)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	k, err := t.getReferenceVal(txn, refOpts, false)
 
 	if err != nil {
@@ -31,8 +46,8 @@ func (t *Store) Reference(refOpts *schema.ReferenceOptions, options ...WriteOpti
 
 	if err = txn.SetEntry(&badger.Entry{
 		Key:      refOpts.Reference,
-		Value:    WrapValueWithTS(k, tsEntry.ts),
-		UserMeta: bitReferenceEntry,
+		Value:    wrapValue(k, tsEntry.ts),
+		UserMeta: bitReferenceEntry | bitChecksummedEntry,
 	}); err != nil {
 		return nil, mapError(err)
 	}
@@ -41,15 +56,18 @@ func (t *Store) Reference(refOpts *schema.ReferenceOptions, options ...WriteOpti
 		Index: tsEntry.ts - 1,
 	}
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:      treeKey(uint8(0), tsEntry.ts-1),
-		Value:    refTreeKey(*tsEntry.h, *tsEntry.r),
-		UserMeta: bitTreeEntry,
-	}); err != nil {
+	if err = setLeafEntry(txn, tsEntry); err != nil {
 		return nil, mapError(err)
 	}
 
+	seq, sequenced, err := t.sequencer.sequence(txn, index.Index, index.Index)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	index.Sequence = seq
+
 	cb := func(err error) {
+		sequenced(err)
 		if err == nil {
 			t.tree.Commit(tsEntry)
 		} else {
@@ -71,7 +89,79 @@ func (t *Store) Reference(refOpts *schema.ReferenceOptions, options ...WriteOpti
 	return index, err
 }
 
-// GetReference fetches the reference having the specified key or index
+// CompareAndReference atomically adds the provided key-value entry and binds the reference to it,
+// only if the reference currently resolves to the expected index.
+// If the expected index is not provided the reference must not exist yet.
+// It returns the index of the newly added key-value entry.
+func (t *Store) CompareAndReference(carOpts *schema.CompareAndReferenceOptions, options ...WriteOption) (index *schema.Index, err error) {
+	if carOpts.Kv == nil {
+		return nil, ErrInvalidKey
+	}
+	if err = checkKey(carOpts.Kv.Key); err != nil {
+		return nil, err
+	}
+	if err = checkReference(carOpts.Reference); err != nil {
+		return nil, err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	current, err := t.GetReference(schema.Key{Key: carOpts.Reference})
+	if err != nil && err != ErrKeyNotFound {
+		return nil, err
+	}
+	if carOpts.ExpectedIndex == nil {
+		if current != nil {
+			return nil, ErrReferenceMismatch
+		}
+	} else if current == nil || current.Index != carOpts.ExpectedIndex.Index {
+		return nil, ErrReferenceMismatch
+	}
+
+	ops := &schema.Ops{
+		Operations: []*schema.Op{
+			{Operation: &schema.Op_KVs{KVs: carOpts.Kv}},
+			{Operation: &schema.Op_ROpts{ROpts: &schema.ReferenceOptions{Reference: carOpts.Reference, Key: carOpts.Kv.Key}}},
+		},
+	}
+	if index, err = t.execAllOps(ops, false, nil, options...); err != nil {
+		return nil, err
+	}
+
+	// the reference is the last operation, the key-value entry is the one before
+	return &schema.Index{Index: index.Index - 1}, nil
+}
+
+// Unreference retires the reference by appending a tombstone for it, the entries of the referenced key are left as
+// they are. Like a deleted key, the reference can't be resolved anymore while its history, tombstone included, can
+// still be read and verified by index. A reference can be re-pointed by binding it to another key with Reference,
+// without retiring it first.
+// ErrKeyNotFound is returned if the reference doesn't exist or is already retired, ErrNoReferenceProvided if the
+// key is not a reference.
+func (t *Store) Unreference(key schema.Key, options ...WriteOption) (index *schema.Index, err error) {
+	if err = checkReference(key.Key); err != nil {
+		return nil, err
+	}
+	return t.tombstone(key.Key, func(i *badger.Item) error {
+		if isTombstone(i) {
+			return ErrKeyNotFound
+		}
+		if i.UserMeta()&bitReferenceEntry != bitReferenceEntry {
+			return ErrNoReferenceProvided
+		}
+		return nil
+	}, options...)
+}
+
+// GetReference fetches the reference having the specified key or index.
+// References are resolved in this order:
+//  1. the latest entry written at the reference key decides: a tombstone, written by Unreference, resolves to
+//     ErrKeyNotFound, an entry which is not a reference to ErrNoReferenceProvided, so that the reference follows
+//     the last Reference or Unreference call;
+//  2. a reference bound to an index resolves to the entry at that index, even if its key has been written or
+//     deleted afterwards;
+//  3. any other reference resolves to the current entry of its key, ErrKeyNotFound if the key has been deleted.
 func (t *Store) GetReference(key schema.Key) (item *schema.Item, err error) {
 	if err = checkReference(key.Key); err != nil {
 		return nil, err
@@ -83,14 +173,20 @@ func (t *Store) GetReference(key schema.Key) (item *schema.Item, err error) {
 	if err != nil {
 		return nil, mapError(err)
 	}
+	if isTombstone(i) {
+		return nil, ErrKeyNotFound
+	}
 	if i.UserMeta()&bitReferenceEntry != bitReferenceEntry {
 		return nil, ErrNoReferenceProvided
 	}
 	var refKey []byte
-	err = i.Value(func(val []byte) error {
-		refKey, _ = UnwrapValueWithTS(val)
-		return nil
+	err = i.Value(func(val []byte) (err error) {
+		refKey, _, err = unwrapValue(i.UserMeta(), val)
+		return err
 	})
+	if err != nil {
+		return nil, mapError(err)
+	}
 
 	k, flag, refIndex := UnwrapZIndexReference(refKey)
 
@@ -102,7 +198,84 @@ func (t *Store) GetReference(key schema.Key) (item *schema.Item, err error) {
 		if err != nil {
 			return nil, mapError(err)
 		}
+		if err = t.checkDeleted(i); err != nil {
+			return nil, err
+		}
 	}
 
 	return itemToSchema(i.Key(), i)
 }
+
+// GetReferences returns the references currently resolving to the key, sorted by reference, along with the index of
+// the entry of each of them. References retired with Unreference or re-pointed to another key are not returned,
+// neither are the ones following a deleted key, as they can't be resolved.
+// If options.Index is provided only the references resolving to the entry at that index are returned: the ones
+// bound to it and, if it's the current entry of the key, the ones following the key.
+func (t *Store) GetReferences(options schema.ReferencesOptions) (list *schema.ReferenceList, err error) {
+	if err = checkKey(options.Key); err != nil {
+		return nil, err
+	}
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	// the current entry of the key, resolving the references which are not bound to an index
+	var current *schema.Index
+	i, err := txn.Get(options.Key)
+	if err != nil && err != badger.ErrKeyNotFound {
+		return nil, mapError(err)
+	}
+	if err == nil {
+		if err = t.checkDeleted(i); err != nil && err != ErrKeyNotFound {
+			return nil, err
+		}
+		if err == nil {
+			current = &schema.Index{Index: i.Version() - 1}
+		}
+	}
+	if options.Index != nil && current != nil && current.Index != options.Index.Index {
+		current = nil
+	}
+
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: false,
+	})
+	defer it.Close()
+
+	list = &schema.ReferenceList{}
+	// the tree namespace comes first, user keys follow it
+	for it.Seek([]byte{tsPrefix + 1}); it.Valid(); it.Next() {
+		item := it.Item()
+		if item.UserMeta()&bitReferenceEntry != bitReferenceEntry {
+			continue
+		}
+		// sorted set members are references too
+		if _, reserved := ReservedNamespace(item.Key()); reserved {
+			continue
+		}
+		var refVal []byte
+		var ts uint64
+		err = item.Value(func(val []byte) (err error) {
+			refVal, ts, err = unwrapValue(item.UserMeta(), val)
+			return err
+		})
+		if err != nil {
+			return nil, mapError(err)
+		}
+		key, flag, refIndex := UnwrapZIndexReference(refVal)
+		if !bytes.Equal(key, options.Key) {
+			continue
+		}
+		ref := &schema.ReferenceOptions{Reference: item.KeyCopy(nil), Key: key}
+		if flag == byte(1) {
+			if options.Index != nil && refIndex != options.Index.Index {
+				continue
+			}
+			ref.Index = &schema.Index{Index: refIndex}
+		} else if current == nil {
+			continue
+		}
+		list.Items = append(list.Items, &schema.ReferenceItem{Reference: ref, Index: ts - 1})
+	}
+	return list, nil
+}
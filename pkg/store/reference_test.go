@@ -17,10 +17,14 @@ limitations under the License.
 package store
 
 import (
-	"github.com/codenotary/immudb/pkg/api/schema"
-	"github.com/stretchr/testify/assert"
+	"os"
 	"strconv"
 	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/dgraph-io/badger/v2/pb"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStoreReference(t *testing.T) {
@@ -288,3 +292,212 @@ func TestStore_ReferenceWrongReference(t *testing.T) {
 	_, err := st.Reference(&schema.ReferenceOptions{Key: []byte(`aaa`), Reference: []byte{tsPrefix}})
 	assert.Equal(t, err, ErrInvalidReference)
 }
+
+func TestStoreReferenceStrictAppendOnly(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("immudb ", os.Stderr))
+	st, err := Open(opts.WithStrictAppendOnly(true), badgerOpts)
+	assert.NoError(t, err)
+	defer st.Close()
+
+	idx, err := st.Set(schema.KeyValue{Key: []byte(`firstKey`), Value: []byte(`firstValue`)})
+	assert.NoError(t, err)
+
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`myTag`), Key: []byte(`firstKey`), Index: idx})
+	assert.NoError(t, err)
+
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`myTag`), Key: []byte(`firstKey`), Index: &schema.Index{Index: 100}})
+	assert.Equal(t, ErrIndexNotCommitted, err)
+
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`mySet`), Key: []byte(`firstKey`), Score: &schema.Score{Score: 1}, Index: &schema.Index{Index: 100}})
+	assert.Equal(t, ErrIndexNotCommitted, err)
+
+	_, err = st.ExecAllOps(&schema.Ops{
+		Operations: []*schema.Op{
+			{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`batchKey`), Value: []byte(`batchValue`)}}},
+			{Operation: &schema.Op_ROpts{ROpts: &schema.ReferenceOptions{Reference: []byte(`batchTag`), Key: []byte(`batchKey`), Index: &schema.Index{Index: 100}}}},
+		},
+	})
+	assert.Equal(t, ErrIndexKeyMismatch, err)
+
+	_, err = st.Restore(make(chan *pb.KVList))
+	assert.Equal(t, ErrRestoreNotAllowed, err)
+}
+
+func TestStoreCompareAndReference(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	idx1, err := st.CompareAndReference(&schema.CompareAndReferenceOptions{
+		Kv:        &schema.KeyValue{Key: []byte(`doc_v1`), Value: []byte(`first`)},
+		Reference: []byte(`latest`),
+	})
+	assert.NoError(t, err)
+
+	item, err := st.GetReference(schema.Key{Key: []byte(`latest`)})
+	assert.NoError(t, err)
+	assert.Equal(t, idx1.Index, item.Index)
+	assert.Equal(t, []byte(`first`), item.Value)
+
+	// the reference already exists
+	_, err = st.CompareAndReference(&schema.CompareAndReferenceOptions{
+		Kv:        &schema.KeyValue{Key: []byte(`doc_v2`), Value: []byte(`second`)},
+		Reference: []byte(`latest`),
+	})
+	assert.Equal(t, ErrReferenceMismatch, err)
+
+	// the reference points to a different index
+	_, err = st.CompareAndReference(&schema.CompareAndReferenceOptions{
+		Kv:            &schema.KeyValue{Key: []byte(`doc_v2`), Value: []byte(`second`)},
+		Reference:     []byte(`latest`),
+		ExpectedIndex: &schema.Index{Index: idx1.Index + 100},
+	})
+	assert.Equal(t, ErrReferenceMismatch, err)
+
+	idx2, err := st.CompareAndReference(&schema.CompareAndReferenceOptions{
+		Kv:            &schema.KeyValue{Key: []byte(`doc_v2`), Value: []byte(`second`)},
+		Reference:     []byte(`latest`),
+		ExpectedIndex: idx1,
+	})
+	assert.NoError(t, err)
+
+	item, err = st.GetReference(schema.Key{Key: []byte(`latest`)})
+	assert.NoError(t, err)
+	assert.Equal(t, idx2.Index, item.Index)
+	assert.Equal(t, []byte(`second`), item.Value)
+
+	_, err = st.CompareAndReference(&schema.CompareAndReferenceOptions{Reference: []byte(`latest`)})
+	assert.Equal(t, ErrInvalidKey, err)
+
+	_, err = st.CompareAndReference(&schema.CompareAndReferenceOptions{
+		Kv: &schema.KeyValue{Key: []byte(`doc_v3`), Value: []byte(`third`)},
+	})
+	assert.Equal(t, ErrInvalidReference, err)
+}
+
+func TestStoreUnreference(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	idx1, err := st.Set(schema.KeyValue{Key: []byte(`v1`), Value: []byte(`first`)})
+	assert.NoError(t, err)
+	idx2, err := st.Set(schema.KeyValue{Key: []byte(`v2`), Value: []byte(`second`)})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`stable`), Key: []byte(`v1`)})
+	assert.NoError(t, err)
+
+	// re-pointing the tag
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`stable`), Key: []byte(`v2`)})
+	assert.NoError(t, err)
+	item, err := st.GetReference(schema.Key{Key: []byte(`stable`)})
+	assert.NoError(t, err)
+	assert.Equal(t, idx2.Index, item.Index)
+
+	// retiring the tag
+	index, err := st.Unreference(schema.Key{Key: []byte(`stable`)})
+	assert.NoError(t, err)
+	_, err = st.GetReference(schema.Key{Key: []byte(`stable`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Get(schema.Key{Key: []byte(`stable`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Unreference(schema.Key{Key: []byte(`stable`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	// the referenced key is left as it is, the tombstone is in the history of the tag
+	item, err = st.Get(schema.Key{Key: []byte(`v2`)})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`second`), item.Value)
+	tombstone, err := st.ByIndex(*index)
+	assert.NoError(t, err)
+	assert.True(t, tombstone.Deleted)
+	assert.Equal(t, []byte(`stable`), tombstone.Key)
+
+	// the tag can be bound again
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`stable`), Key: []byte(`v1`)})
+	assert.NoError(t, err)
+	item, err = st.GetReference(schema.Key{Key: []byte(`stable`)})
+	assert.NoError(t, err)
+	assert.Equal(t, idx1.Index, item.Index)
+
+	// a reference to a deleted key can't be resolved
+	_, err = st.Delete(schema.Key{Key: []byte(`v1`)})
+	assert.NoError(t, err)
+	_, err = st.GetReference(schema.Key{Key: []byte(`stable`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, err = st.Unreference(schema.Key{Key: []byte(`v2`)})
+	assert.Equal(t, ErrNoReferenceProvided, err)
+	_, err = st.Unreference(schema.Key{Key: []byte(`missing`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Unreference(schema.Key{Key: []byte{tsPrefix}})
+	assert.Equal(t, ErrInvalidReference, err)
+}
+
+func TestStoreGetReferences(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	idx1, err := st.Set(schema.KeyValue{Key: []byte(`record`), Value: []byte(`v1`)})
+	assert.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`other`), Value: []byte(`v1`)})
+	assert.NoError(t, err)
+
+	tag1, err := st.Reference(&schema.ReferenceOptions{Reference: []byte(`alias`), Key: []byte(`record`)})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`pinned`), Key: []byte(`record`), Index: idx1})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`otherAlias`), Key: []byte(`other`)})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`retired`), Key: []byte(`record`)})
+	assert.NoError(t, err)
+	_, err = st.Unreference(schema.Key{Key: []byte(`retired`)})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`moved`), Key: []byte(`record`)})
+	assert.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`moved`), Key: []byte(`other`)})
+	assert.NoError(t, err)
+	// sorted set members are not references to be listed
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`record`), Score: &schema.Score{Score: 1}})
+	assert.NoError(t, err)
+
+	list, err := st.GetReferences(schema.ReferencesOptions{Key: []byte(`record`)})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+	assert.Equal(t, []byte(`alias`), list.Items[0].Reference.Reference)
+	assert.Equal(t, []byte(`record`), list.Items[0].Reference.Key)
+	assert.Nil(t, list.Items[0].Reference.Index)
+	assert.Equal(t, tag1.Index, list.Items[0].Index)
+	assert.Equal(t, []byte(`pinned`), list.Items[1].Reference.Reference)
+	assert.Equal(t, idx1.Index, list.Items[1].Reference.Index.Index)
+
+	// a new entry of the key
+	idx2, err := st.Set(schema.KeyValue{Key: []byte(`record`), Value: []byte(`v2`)})
+	assert.NoError(t, err)
+
+	list, err = st.GetReferences(schema.ReferencesOptions{Key: []byte(`record`), Index: idx1})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, []byte(`pinned`), list.Items[0].Reference.Reference)
+
+	list, err = st.GetReferences(schema.ReferencesOptions{Key: []byte(`record`), Index: idx2})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, []byte(`alias`), list.Items[0].Reference.Reference)
+
+	// references following a deleted key can't be resolved, the pinned ones still can
+	_, err = st.Delete(schema.Key{Key: []byte(`record`)})
+	assert.NoError(t, err)
+	list, err = st.GetReferences(schema.ReferencesOptions{Key: []byte(`record`)})
+	assert.NoError(t, err)
+	assert.Len(t, list.Items, 1)
+	assert.Equal(t, []byte(`pinned`), list.Items[0].Reference.Reference)
+
+	list, err = st.GetReferences(schema.ReferencesOptions{Key: []byte(`missing`)})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Items)
+
+	_, err = st.GetReferences(schema.ReferencesOptions{Key: []byte{tsPrefix}})
+	assert.Equal(t, ErrInvalidKey, err)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// Namespaces of the key space the store keeps for internal use. The keys, references and sets written by users
+// must not fall into any of them, otherwise they could be mistaken for internal entries and corrupt the indexes.
+const (
+	// TreeNamespace holds the nodes of the Merkle tree and the metadata of its leaves (commit times, sequence
+	// numbers and frozen prefixes), under keys starting with the 0x00 byte
+	TreeNamespace = "tree"
+	// MetadataNamespace holds the state of the store, e.g. the last leaf flushed to disk, under the IMMUDB.METADATA. prefix
+	MetadataNamespace = "metadata"
+	// SortedSetNamespace holds the members of the sorted sets, under the _~|IMMU|~_ prefix
+	SortedSetNamespace = "sorted set"
+)
+
+const metadataPrefix = "IMMUDB.METADATA."
+
+// ReservedNamespace returns the internal namespace key falls into, if any
+func ReservedNamespace(key []byte) (string, bool) {
+	switch {
+	case len(key) > 0 && key[0] == tsPrefix:
+		return TreeNamespace, true
+	case bytes.HasPrefix(key, []byte(metadataPrefix)):
+		return MetadataNamespace, true
+	case bytes.HasPrefix(key, _SetSeparator):
+		return SortedSetNamespace, true
+	}
+	return "", false
+}
+
+// ReservedKeyCollisions looks for entries written by users, before reserved namespaces were enforced, in the
+// namespaces not validated by older versions of the store: metadata keys unknown to the store and sorted set keys
+// not written by ZAdd. It returns how many were found, along with the first ones up to max.
+func (t *Store) ReservedKeyCollisions(max int) (count int, keys [][]byte) {
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: false})
+	defer it.Close()
+
+	collision := func(item *badger.Item) {
+		count++
+		if len(keys) < max {
+			keys = append(keys, item.KeyCopy(nil))
+		}
+	}
+
+	for it.Seek([]byte(metadataPrefix)); it.ValidForPrefix([]byte(metadataPrefix)); it.Next() {
+		if !isReservedKey(it.Item().Key()) && !bytes.HasPrefix(it.Item().Key(), []byte(RetentionEventPrefix)) {
+			collision(it.Item())
+		}
+	}
+	// sorted set members are always written as references
+	for it.Seek(_SetSeparator); it.ValidForPrefix(_SetSeparator); it.Next() {
+		if it.Item().UserMeta()&bitReferenceEntry != bitReferenceEntry {
+			collision(it.Item())
+		}
+	}
+	return count, keys
+}
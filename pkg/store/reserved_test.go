@@ -0,0 +1,70 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservedNamespace(t *testing.T) {
+	for key, namespace := range map[string]string{
+		string(treeKey(0, 1)):         TreeNamespace,
+		string(timeKey(1)):            TreeNamespace,
+		lastFlushedMetaKey:            MetadataNamespace,
+		treeCheckpointMetaKey:         MetadataNamespace,
+		"IMMUDB.METADATA.OTHER":       MetadataNamespace,
+		string(_SetSeparator) + "set": SortedSetNamespace,
+	} {
+		ns, reserved := ReservedNamespace([]byte(key))
+		require.True(t, reserved, key)
+		require.Equal(t, namespace, ns, key)
+	}
+
+	for _, key := range []string{"", "key", "IMMUDB.METADATA", "_~|IMMU", "set_~|IMMU|~_"} {
+		_, reserved := ReservedNamespace([]byte(key))
+		require.False(t, reserved, key)
+	}
+}
+
+func TestReservedKeyCollisions(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	idx, err := st.Set(schema.KeyValue{Key: []byte("key"), Value: []byte("value")})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte("set"), Score: &schema.Score{Score: 1}, Key: []byte("key"), Index: idx})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(RetentionEventPrefix + "1"), Value: []byte("{}")})
+	require.NoError(t, err)
+
+	count, keys := st.ReservedKeyCollisions(1)
+	require.Equal(t, 0, count)
+	require.Empty(t, keys)
+
+	// written before the reserved namespaces were enforced by the server
+	for _, key := range [][]byte{[]byte("IMMUDB.METADATA.custom"), append(append([]byte{}, _SetSeparator...), "fake"...)} {
+		_, err = st.Set(schema.KeyValue{Key: key, Value: []byte("value")})
+		require.NoError(t, err)
+	}
+
+	count, keys = st.ReservedKeyCollisions(1)
+	require.Equal(t, 2, count)
+	require.Equal(t, [][]byte{[]byte("IMMUDB.METADATA.custom")}, keys)
+}
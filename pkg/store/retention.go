@@ -0,0 +1,195 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"math"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+)
+
+// bitPrunedEntry flags the records whose value has been pruned by Prune: the record keeps its key and
+// timestamp only. The leaf of the entry stays in the tree, so that the proofs of the other entries are not affected.
+const bitPrunedEntry = byte(32)
+
+// Reasons of the pruning of an entry
+const (
+	PrunedByAge       = "max-age"
+	PrunedByRevisions = "max-revisions"
+)
+
+// RetentionEventPrefix is the prefix of the keys of the records of the enforcements of the retention policies. It falls
+// into the metadata namespace, so that users can't write events of their own, and its values are always retained.
+const RetentionEventPrefix = metadataPrefix + "RETENTION."
+
+// RetentionPolicy tells which values of the keys are retained, zero fields don't limit the retention
+type RetentionPolicy struct {
+	// MaxAge is how long values are retained once written
+	MaxAge time.Duration `json:"max_age,omitempty"`
+	// MaxRevisions is how many of the latest values of every key are retained
+	MaxRevisions uint64 `json:"max_revisions,omitempty"`
+}
+
+// Enabled tells if the policy prunes any value
+func (p RetentionPolicy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxRevisions > 0
+}
+
+// PrunedEntry is an entry whose value has been, or is to be, pruned by the retention policy, and why
+type PrunedEntry struct {
+	Key    []byte `json:"key"`
+	Index  uint64 `json:"index"`
+	Reason string `json:"reason"`
+}
+
+// EnforceRetention prunes the values of the entries returned by RetentionCandidates and returns the ones pruned, see
+// Prune.
+func (t *Store) EnforceRetention(policy RetentionPolicy, max int) ([]PrunedEntry, error) {
+	candidates, err := t.RetentionCandidates(policy, max)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+	return t.Prune(candidates)
+}
+
+// RetentionCandidates returns the entries set by Set, SetBatch or ExecAllOps whose values policy doesn't retain
+// anymore, up to max entries if max is greater than zero. Keys, references, sorted set members and tombstones are
+// always retained, as well as the values of the keys under frozen prefixes or RetentionEventPrefix and the ones written
+// with no commit time when pruning by age.
+func (t *Store) RetentionCandidates(policy RetentionPolicy, max int) ([]PrunedEntry, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	return t.retentionCandidates(policy, max)
+}
+
+// Prune prunes the values of entries, as returned by RetentionCandidates, and returns the ones actually pruned: the
+// entries under prefixes frozen in the meantime, and the ones not found at their index, are left untouched. Pruned
+// values can't be read anymore, neither by key, as if they had expired, nor by index, which returns ErrEntryPruned.
+// Values are removed from disk as the underlying storage compacts.
+func (t *Store) Prune(entries []PrunedEntry) (pruned []PrunedEntry, err error) {
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
+	// freezing a prefix waits for the values under it to be pruned, or not at all
+	t.frozen.RLock()
+	defer t.frozen.RUnlock()
+
+	for _, p := range entries {
+		if _, reserved := ReservedNamespace(p.Key); reserved || t.frozen.covers(p.Key) {
+			continue
+		}
+		found, err := t.hasVersion(p.Key, p.Index+1)
+		if err != nil {
+			return pruned, err
+		}
+		if !found {
+			continue
+		}
+		// the record is replaced by writing it again with the same version
+		txn := t.db.NewTransactionAt(math.MaxUint64, true)
+		err = txn.SetEntry(&badger.Entry{
+			Key:      p.Key,
+			Value:    wrapValue(nil, p.Index+1),
+			UserMeta: bitChecksummedEntry | bitPrunedEntry,
+		})
+		if err == nil {
+			err = txn.CommitAt(p.Index+1, nil)
+		}
+		txn.Discard()
+		if err != nil {
+			return pruned, mapError(err)
+		}
+		pruned = append(pruned, p)
+	}
+	return pruned, nil
+}
+
+// hasVersion tells if key has a value, rather than a reference or a tombstone, written at version
+func (t *Store) hasVersion(key []byte, version uint64) (bool, error) {
+	txn := t.db.NewTransactionAt(version, false)
+	defer txn.Discard()
+	item, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, mapError(err)
+	}
+	return item.Version() == version && item.UserMeta()&(bitReferenceEntry|bitTombstoneEntry) == 0, nil
+}
+
+func (t *Store) retentionCandidates(policy RetentionPolicy, max int) (pruned []PrunedEntry, err error) {
+	var deadline int64
+	if policy.MaxAge > 0 {
+		deadline = t.tree.clock.Now().Add(-policy.MaxAge).UnixNano()
+	}
+
+	t.frozen.RLock()
+	defer t.frozen.RUnlock()
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	it := txn.NewIterator(badger.IteratorOptions{AllVersions: true})
+	defer it.Close()
+
+	var key []byte
+	var revisions uint64
+	var skip bool
+	// the tree namespace is skipped at once, as it holds most of the keys
+	for it.Seek([]byte{tsPrefix + 1}); it.Valid(); it.Next() {
+		item := it.Item()
+		if key == nil || string(item.Key()) != string(key) {
+			key, revisions = item.KeyCopy(nil), 0
+			_, reserved := ReservedNamespace(key)
+			skip = reserved || t.frozen.covers(key)
+		}
+		meta := item.UserMeta()
+		if skip || meta&(bitReferenceEntry|bitTombstoneEntry) != 0 {
+			continue
+		}
+		revisions++
+		if meta&bitPrunedEntry == bitPrunedEntry {
+			continue
+		}
+
+		index := item.Version() - 1
+		reason := ""
+		if policy.MaxRevisions > 0 && revisions > policy.MaxRevisions {
+			reason = PrunedByRevisions
+		} else if policy.MaxAge > 0 {
+			ts, err := indexTime(txn, index)
+			if err != nil && err != ErrIndexNotFound {
+				return nil, err
+			}
+			if err == nil && ts < deadline {
+				reason = PrunedByAge
+			}
+		}
+		if reason == "" {
+			continue
+		}
+		pruned = append(pruned, PrunedEntry{Key: key, Index: index, Reason: reason})
+		if max > 0 && len(pruned) == max {
+			break
+		}
+	}
+	return pruned, nil
+}
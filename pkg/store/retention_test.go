@@ -0,0 +1,157 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnforceRetention(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1000, 0).UnixNano()
+	c := clock.Func(func() time.Time { return time.Unix(0, atomic.LoadInt64(&now)) })
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	st, err := Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+
+	var revisions []*schema.Index
+	for _, v := range []string{`v1`, `v2`, `v3`} {
+		index, err := st.Set(schema.KeyValue{Key: []byte(`personal`), Value: []byte(v)})
+		require.NoError(t, err)
+		revisions = append(revisions, index)
+	}
+	for _, v := range []string{`v1`, `v2`} {
+		_, err = st.Set(schema.KeyValue{Key: []byte(`legal/hold`), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	for _, v := range []string{`e1`, `e2`} {
+		_, err = st.Set(schema.KeyValue{Key: []byte(RetentionEventPrefix + `1`), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	_, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte(`legal/`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`personal`)})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`personal`), Score: &schema.Score{Score: 1}, Index: revisions[0]})
+	require.NoError(t, err)
+	atomic.StoreInt64(&now, time.Unix(1100, 0).UnixNano())
+	fresh, err := st.Set(schema.KeyValue{Key: []byte(`fresh`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	st.tree.WaitUntil(fresh.Index)
+	root, err := st.CurrentRoot()
+	require.NoError(t, err)
+
+	pruned, err := st.EnforceRetention(RetentionPolicy{}, 0)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+
+	// the older revisions go first, up to max at a time
+	pruned, err = st.EnforceRetention(RetentionPolicy{MaxRevisions: 1}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []PrunedEntry{{Key: []byte(`personal`), Index: revisions[1].Index, Reason: PrunedByRevisions}}, pruned)
+	pruned, err = st.EnforceRetention(RetentionPolicy{MaxRevisions: 1}, 0)
+	require.NoError(t, err)
+	require.Equal(t, []PrunedEntry{{Key: []byte(`personal`), Index: revisions[0].Index, Reason: PrunedByRevisions}}, pruned)
+
+	_, err = st.ByIndex(*revisions[0])
+	require.Equal(t, ErrEntryPruned, err)
+	item, err := st.Get(schema.Key{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`v3`), item.Value)
+	history, err := st.History(&schema.HistoryOptions{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 1)
+	zitems, err := st.ZScan(schema.ZScanOptions{Set: []byte(`set`)})
+	require.NoError(t, err)
+	require.Empty(t, zitems.Items)
+
+	// the tree is left untouched, so that the entries still prove
+	current, err := st.CurrentRoot()
+	require.NoError(t, err)
+	require.Equal(t, root, current)
+	_, err = st.InclusionProof(*revisions[0])
+	require.NoError(t, err)
+	_, err = st.SafeGet(schema.SafeGetOptions{Key: []byte(`personal`)})
+	require.NoError(t, err)
+
+	pruned, err = st.EnforceRetention(RetentionPolicy{MaxAge: time.Minute}, 0)
+	require.NoError(t, err)
+	require.Equal(t, []PrunedEntry{{Key: []byte(`personal`), Index: revisions[2].Index, Reason: PrunedByAge}}, pruned)
+	_, err = st.Get(schema.Key{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Get(schema.Key{Key: []byte(`ref`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	require.NoError(t, st.Close())
+
+	// pruning survives reopening the store
+	st, err = Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	_, err = st.ByIndex(*revisions[2])
+	require.Equal(t, ErrEntryPruned, err)
+	_, err = st.Get(schema.Key{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	item, err = st.Get(schema.Key{Key: []byte(`fresh`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`value`), item.Value)
+	history, err = st.History(&schema.HistoryOptions{Key: []byte(`legal/hold`)})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 2)
+	history, err = st.History(&schema.HistoryOptions{Key: []byte(RetentionEventPrefix + `1`)})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 2)
+}
+
+func TestPruneFrozenCandidates(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	for _, key := range []string{`legal/hold`, `personal`} {
+		for _, v := range []string{`v1`, `v2`} {
+			_, err := st.Set(schema.KeyValue{Key: []byte(key), Value: []byte(v)})
+			require.NoError(t, err)
+		}
+	}
+	candidates, err := st.RetentionCandidates(RetentionPolicy{MaxRevisions: 1}, 0)
+	require.NoError(t, err)
+	require.Len(t, candidates, 2)
+
+	// the prefix frozen after the candidates are selected is honored all the same
+	_, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte(`legal/`)})
+	require.NoError(t, err)
+	pruned, err := st.Prune(candidates)
+	require.NoError(t, err)
+	require.Equal(t, []PrunedEntry{{Key: []byte(`personal`), Index: 2, Reason: PrunedByRevisions}}, pruned)
+	history, err := st.History(&schema.HistoryOptions{Key: []byte(`legal/hold`)})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 2)
+
+	// entries not found at their index are not pruned
+	pruned, err = st.Prune([]PrunedEntry{{Key: []byte(`personal`), Index: 0, Reason: PrunedByRevisions}})
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package store
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"math"
+	"sync/atomic"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/merkletree"
@@ -43,6 +46,11 @@ func (t *Store) SafeSet(options schema.SafeSetOptions) (proof *schema.Proof, err
 	if err = checkKey(kv.Key); err != nil {
 		return nil, err
 	}
+	release, err := t.frozen.guard(kv.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
 	if err != nil {
@@ -52,11 +60,17 @@ func (t *Store) SafeSet(options schema.SafeSetOptions) (proof *schema.Proof, err
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	tsEntry := t.tree.NewEntry(kv.Key, kv.Value)
 
+	value, compressed := t.compress(kv.Value)
 	if err = txn.SetEntry(&badger.Entry{
-		Key:   kv.Key,
-		Value: WrapValueWithTS(kv.Value, tsEntry.ts),
+		Key:      kv.Key,
+		Value:    wrapValue(value, tsEntry.ts),
+		UserMeta: bitChecksummedEntry | compressed,
 	}); err != nil {
 		return nil, mapError(err)
 	}
@@ -64,15 +78,17 @@ func (t *Store) SafeSet(options schema.SafeSetOptions) (proof *schema.Proof, err
 	index := tsEntry.Index()
 	leaf := tsEntry.HashCopy()
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:      treeKey(uint8(0), tsEntry.ts-1),
-		Value:    refTreeKey(*tsEntry.h, *tsEntry.r),
-		UserMeta: bitTreeEntry,
-	}); err != nil {
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, index, index)
+	if err != nil {
 		return nil, mapError(err)
 	}
 
 	err = txn.CommitAt(tsEntry.ts, nil)
+	sequenced(err)
 	if err != nil {
 		t.tree.Discard(tsEntry)
 		return nil, mapError(err)
@@ -94,6 +110,7 @@ func (t *Store) SafeSet(options schema.SafeSetOptions) (proof *schema.Proof, err
 		At:              at,
 		InclusionPath:   merkletree.InclusionProof(t.tree, at, index).ToSlice(),
 		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+		Sequence:        seq,
 	}
 
 	return
@@ -103,7 +120,6 @@ func (t *Store) SafeSet(options schema.SafeSetOptions) (proof *schema.Proof, err
 // for it and the consistency proof for the current root
 func (t *Store) SafeGet(options schema.SafeGetOptions) (safeItem *schema.SafeItem, err error) {
 	var item *schema.Item
-	var i *badger.Item
 	key := options.Key
 
 	if err = checkKey(key); err != nil {
@@ -118,17 +134,49 @@ func (t *Store) SafeGet(options schema.SafeGetOptions) (safeItem *schema.SafeIte
 	txn := t.db.NewTransactionAt(math.MaxUint64, false)
 	defer txn.Discard()
 
-	i, err = txn.Get(key)
+	if item, err = t.getResolved(txn, key); err != nil {
+		return nil, err
+	}
+
+	safeItem = &schema.SafeItem{
+		Item: item,
+	}
+
+	t.tree.WaitUntil(item.Index)
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+
+	at := t.tree.w - 1
+	root := merkletree.Root(t.tree)
+
+	safeItem.Proof = &schema.Proof{
+		Leaf:            item.Hash(),
+		Index:           item.Index,
+		Root:            root[:],
+		At:              at,
+		InclusionPath:   merkletree.InclusionProof(t.tree, at, item.Index).ToSlice(),
+		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+	}
+
+	return
+}
+
+// getResolved reads the current entry of key, following it if it's a reference
+func (t *Store) getResolved(txn *badger.Txn, key []byte) (*schema.Item, error) {
+	i, err := txn.Get(key)
 	if err != nil {
 		return nil, mapError(err)
 	}
 
 	if i.UserMeta()&bitReferenceEntry == bitReferenceEntry {
 		var refKey []byte
-		err = i.Value(func(val []byte) error {
-			refKey, _ = UnwrapValueWithTS(val)
-			return nil
+		err = i.Value(func(val []byte) (err error) {
+			refKey, _, err = unwrapValue(i.UserMeta(), val)
+			return err
 		})
+		if err != nil {
+			return nil, mapError(err)
+		}
 
 		k, _, _ := UnwrapZIndexReference(refKey)
 
@@ -136,39 +184,92 @@ func (t *Store) SafeGet(options schema.SafeGetOptions) (safeItem *schema.SafeIte
 		if err != nil {
 			return nil, mapError(err)
 		}
-		item, err = itemToSchema(i.Key(), i)
-		if err != nil {
+		if err = t.checkDeleted(i); err != nil {
 			return nil, err
 		}
+		return itemToSchema(i.Key(), i)
+	}
 
-	} else {
-		item, err = itemToSchema(key, i)
-		if err != nil {
+	if err = t.checkDeleted(i); err != nil {
+		return nil, err
+	}
+	return itemToSchema(key, i)
+}
+
+// SafeGetBatch fetches the current entries of many keys, read from a single snapshot, together with their inclusion
+// proofs, all of them against the same root, and the consistency proof for that root. References are resolved as by
+// SafeGet, while missing and deleted keys are left out, as by GetBatch. If a multiproof is requested, the items carry
+// no proof, and a single multiproof of all of them is returned instead. Proofs are computed concurrently, as by
+// InclusionProofs.
+func (t *Store) SafeGetBatch(options schema.SafeGetBatchOptions) (list *schema.SafeItemList, err error) {
+	for _, key := range options.Keys {
+		if err = checkKey(key.GetKey()); err != nil {
 			return nil, err
 		}
 	}
 
-	safeItem = &schema.SafeItem{
-		Item: item,
+	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
+	if err != nil {
+		return nil, err
 	}
 
-	t.tree.WaitUntil(item.Index)
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	list = &schema.SafeItemList{}
+	var last uint64
+	for _, key := range options.Keys {
+		item, err := t.getResolved(txn, key.Key)
+		if err == ErrKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if item.Index > last {
+			last = item.Index
+		}
+		list.Items = append(list.Items, &schema.SafeItem{Item: item})
+	}
+	if len(list.Items) == 0 {
+		return list, nil
+	}
+
+	t.tree.WaitUntil(last)
 	t.tree.RLock()
 	defer t.tree.RUnlock()
 
 	at := t.tree.w - 1
 	root := merkletree.Root(t.tree)
+	consistency := merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice()
 
-	safeItem.Proof = &schema.Proof{
-		Leaf:            item.Hash(),
-		Index:           item.Index,
-		Root:            root[:],
-		At:              at,
-		InclusionPath:   merkletree.InclusionProof(t.tree, at, item.Index).ToSlice(),
-		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+	indexes := make([]uint64, len(list.Items))
+	for i, safeItem := range list.Items {
+		indexes[i] = safeItem.Item.Index
+	}
+	if options.MultiProof {
+		if list.MultiProof, err = multiProof(t.tree, indexes); err != nil {
+			return nil, err
+		}
+		list.MultiProof.ConsistencyPath = consistency
+		return list, nil
 	}
 
-	return
+	proofs, err := inclusionProofs(t.tree, at, indexes)
+	if err != nil {
+		return nil, err
+	}
+	for i, safeItem := range list.Items {
+		safeItem.Proof = &schema.Proof{
+			Leaf:            safeItem.Item.Hash(),
+			Index:           safeItem.Item.Index,
+			Root:            root[:],
+			At:              at,
+			InclusionPath:   proofs[i].Path,
+			ConsistencyPath: consistency,
+		}
+	}
+	return list, nil
 }
 
 // SafeGetReference fetches the reference having the specified key or index together with the inclusion proof
@@ -200,10 +301,13 @@ func (t *Store) SafeGetReference(options schema.SafeGetOptions) (safeItem *schem
 	}
 
 	var refKey []byte
-	err = i.Value(func(val []byte) error {
-		refKey, _ = UnwrapValueWithTS(val)
-		return nil
+	err = i.Value(func(val []byte) (err error) {
+		refKey, _, err = unwrapValue(i.UserMeta(), val)
+		return err
 	})
+	if err != nil {
+		return nil, mapError(err)
+	}
 
 	k, flag, refIndex := UnwrapZIndexReference(refKey)
 
@@ -250,6 +354,9 @@ func (t *Store) SafeGetReference(options schema.SafeGetOptions) (safeItem *schem
 // SafeReference adds a reference entry to an existing key and returns the
 // inclusion proof for it and the consistency proof for the previous root
 func (t *Store) SafeReference(options schema.SafeReferenceOptions) (proof *schema.Proof, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
 	ro := options.Ro
 	if err = checkKey(ro.Key); err != nil && options.Ro.Index == nil {
 		return nil, err
@@ -257,6 +364,11 @@ func (t *Store) SafeReference(options schema.SafeReferenceOptions) (proof *schem
 	if err = checkReference(ro.Reference); err != nil {
 		return nil, err
 	}
+	release, err := t.frozen.guard(ro.Reference)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
 	if err != nil {
@@ -266,6 +378,10 @@ func (t *Store) SafeReference(options schema.SafeReferenceOptions) (proof *schem
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	k, err := t.getReferenceVal(txn, options.Ro, false)
 	if err != nil {
 		return nil, mapError(err)
@@ -275,8 +391,8 @@ func (t *Store) SafeReference(options schema.SafeReferenceOptions) (proof *schem
 
 	if err = txn.SetEntry(&badger.Entry{
 		Key:      ro.Reference,
-		Value:    WrapValueWithTS(k, tsEntry.ts),
-		UserMeta: bitReferenceEntry,
+		Value:    wrapValue(k, tsEntry.ts),
+		UserMeta: bitReferenceEntry | bitChecksummedEntry,
 	}); err != nil {
 		return nil, mapError(err)
 	}
@@ -284,15 +400,17 @@ func (t *Store) SafeReference(options schema.SafeReferenceOptions) (proof *schem
 	index := tsEntry.Index()
 	leaf := tsEntry.HashCopy()
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:      treeKey(uint8(0), tsEntry.ts-1),
-		Value:    refTreeKey(*tsEntry.h, *tsEntry.r),
-		UserMeta: bitTreeEntry,
-	}); err != nil {
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, index, index)
+	if err != nil {
 		return nil, mapError(err)
 	}
 
 	err = txn.CommitAt(tsEntry.ts, nil)
+	sequenced(err)
 	if err != nil {
 		t.tree.Discard(tsEntry)
 		return nil, mapError(err)
@@ -314,6 +432,7 @@ func (t *Store) SafeReference(options schema.SafeReferenceOptions) (proof *schem
 		At:              at,
 		InclusionPath:   merkletree.InclusionProof(t.tree, at, index).ToSlice(),
 		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+		Sequence:        seq,
 	}
 
 	return
@@ -339,6 +458,10 @@ func (t *Store) SafeZAdd(options schema.SafeZAddOptions) (proof *schema.Proof, e
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	ik, referenceValue, err := t.getSortedSetKeyVal(txn, options.Zopts, false)
 	if err != nil {
 		return nil, err
@@ -348,8 +471,8 @@ func (t *Store) SafeZAdd(options schema.SafeZAddOptions) (proof *schema.Proof, e
 
 	if err = txn.SetEntry(&badger.Entry{
 		Key:      ik,
-		Value:    WrapValueWithTS(referenceValue, tsEntry.ts),
-		UserMeta: bitReferenceEntry,
+		Value:    wrapValue(referenceValue, tsEntry.ts),
+		UserMeta: bitReferenceEntry | bitChecksummedEntry,
 	}); err != nil {
 		return nil, mapError(err)
 	}
@@ -357,15 +480,17 @@ func (t *Store) SafeZAdd(options schema.SafeZAddOptions) (proof *schema.Proof, e
 	idx := tsEntry.Index()
 	leaf := tsEntry.HashCopy()
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:      treeKey(uint8(0), tsEntry.ts-1),
-		Value:    refTreeKey(*tsEntry.h, *tsEntry.r),
-		UserMeta: bitTreeEntry,
-	}); err != nil {
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, idx, idx)
+	if err != nil {
 		return nil, mapError(err)
 	}
 
 	err = txn.CommitAt(tsEntry.ts, nil)
+	sequenced(err)
 	if err != nil {
 		t.tree.Discard(tsEntry)
 		return nil, mapError(err)
@@ -387,24 +512,65 @@ func (t *Store) SafeZAdd(options schema.SafeZAddOptions) (proof *schema.Proof, e
 		At:              at,
 		InclusionPath:   merkletree.InclusionProof(t.tree, at, idx).ToSlice(),
 		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+		Sequence:        seq,
 	}
 
 	return
 }
 
+// SafeZScan scans the sorted set as ZScan does, along with a multiproof of the inclusion of both the entries of the
+// sorted set and the items they reference and the consistency proof for the previous root, so that the secondary
+// indexes built with ZAdd are verified as the reads by key are. See SortedSetEntryHash for the leaves of the entries.
+func (t *Store) SafeZScan(options schema.SafeZScanOptions) (list *schema.SafeZItemList, err error) {
+	if options.Zopts == nil {
+		return nil, ErrInvalidSet
+	}
+	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	zlist, err := t.ZScan(*options.Zopts)
+	if err != nil {
+		return nil, err
+	}
+	list = &schema.SafeZItemList{Items: zlist.Items}
+	if len(list.Items) == 0 {
+		return list, nil
+	}
+
+	indexes := make([]uint64, 0, 2*len(list.Items))
+	var last uint64
+	for _, zitem := range list.Items {
+		indexes = append(indexes, zitem.Index, zitem.Item.Index)
+		if zitem.Index > last {
+			last = zitem.Index
+		}
+		if zitem.Item.Index > last {
+			last = zitem.Item.Index
+		}
+	}
+
+	t.tree.WaitUntil(last)
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+
+	if list.MultiProof, err = multiProof(t.tree, indexes); err != nil {
+		return nil, err
+	}
+	list.MultiProof.ConsistencyPath = merkletree.ConsistencyProof(t.tree, list.MultiProof.At, prevRootIdx).ToSlice()
+	return list, nil
+}
+
 // BySafeIndex fetches the entry at the specified index together with the inclusion proof
 // for it and the consistency proof for the current root
 func (t *Store) BySafeIndex(options schema.SafeIndexOptions) (safeitem *schema.SafeItem, err error) {
 
-	var item *schema.Item
-
-	idx, key, value, err := t.itemAt(options.Index + 1)
+	item, err := t.itemAt(options.Index + 1)
 	if err != nil {
 		return nil, err
 	}
 
-	item = &schema.Item{Key: key, Value: value, Index: idx}
-
 	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
 	if err != nil {
 		return
@@ -432,3 +598,99 @@ func (t *Store) BySafeIndex(options schema.SafeIndexOptions) (safeitem *schema.S
 
 	return safeItem, err
 }
+
+// ProofAt returns the proof of the inclusion of the entry at index in the current root, along with the consistency
+// proof of that root with the one at the root index, if any. Unlike BySafeIndex, the leaf is read from the tree rather
+// than computed from the entry, so that the entries whose value has been pruned can be proven as well.
+func (t *Store) ProofAt(index uint64, rootIndex *schema.Index) (*schema.Proof, error) {
+	if index >= atomic.LoadUint64(&t.tree.ts) {
+		return nil, ErrIndexNotFound
+	}
+	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), rootIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	t.tree.WaitUntil(index)
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+
+	leaf := t.tree.Get(0, index)
+	if leaf == nil {
+		return nil, ErrIndexNotFound
+	}
+	at := t.tree.w - 1
+	root := merkletree.Root(t.tree)
+	return &schema.Proof{
+		Leaf:            leaf[:],
+		Index:           index,
+		Root:            root[:],
+		At:              at,
+		InclusionPath:   merkletree.InclusionProof(t.tree, at, index).ToSlice(),
+		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+	}, nil
+}
+
+// VerifyValueHash compares the provided SHA-256 of a value with the one of the stored entry and returns
+// the inclusion and consistency proof of that entry, so that clients can check content integrity without
+// downloading the value.
+// The entry at options.Index is checked when provided, the current entry of options.Key otherwise.
+// When options.Structured is set the hash is compared with the one of the payload of the structured value.
+func (t *Store) VerifyValueHash(options schema.ValueHashOptions) (*schema.ValueHashVerification, error) {
+	if err := checkKey(options.Key); err != nil {
+		return nil, err
+	}
+	if len(options.ValueHash) != sha256.Size {
+		return nil, ErrInvalidValueHash
+	}
+
+	var item *schema.Item
+	if options.Index != nil {
+		var err error
+		if item, err = t.itemAt(options.Index.Index + 1); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(item.Key, options.Key) {
+			return nil, ErrIndexKeyMismatch
+		}
+	} else {
+		var err error
+		if item, err = t.Get(schema.Key{Key: options.Key}); err != nil {
+			return nil, err
+		}
+	}
+
+	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	value := item.Value
+	if options.Structured {
+		sitem, err := item.ToSItem()
+		if err != nil {
+			return nil, ErrNotStructuredValue
+		}
+		value = sitem.Value.Payload
+	}
+	valueHash := sha256.Sum256(value)
+
+	t.tree.WaitUntil(item.Index)
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+
+	at := t.tree.w - 1
+	root := merkletree.Root(t.tree)
+
+	return &schema.ValueHashVerification{
+		Matches: bytes.Equal(valueHash[:], options.ValueHash),
+		Proof: &schema.Proof{
+			Leaf:            item.Hash(),
+			Index:           item.Index,
+			Root:            root[:],
+			At:              at,
+			InclusionPath:   merkletree.InclusionProof(t.tree, at, item.Index).ToSlice(),
+			ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+		},
+	}, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package store
 
 import (
+	"crypto/sha256"
 	"strconv"
 	"sync"
 	"testing"
@@ -25,6 +26,7 @@ import (
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/merkletree"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStoreSafeSet(t *testing.T) {
@@ -230,6 +232,64 @@ func BenchmarkStoreSafeSet(b *testing.B) {
 	b.StopTimer()
 }
 
+func TestStoreSafeGetBatch(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	first, err := st.Set(schema.KeyValue{Key: []byte(`first`), Value: []byte(`firstValue`)})
+	require.NoError(t, err)
+	safeItem, err := st.SafeGet(schema.SafeGetOptions{Key: []byte(`first`)})
+	require.NoError(t, err)
+	prevRoot := safeItem.Proof.NewRoot()
+
+	second, err := st.Set(schema.KeyValue{Key: []byte(`second`), Value: []byte(`secondValue`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`first`)})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`deleted`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	_, err = st.Delete(schema.Key{Key: []byte(`deleted`)})
+	require.NoError(t, err)
+
+	list, err := st.SafeGetBatch(schema.SafeGetBatchOptions{
+		Keys: []*schema.Key{
+			{Key: []byte(`second`)}, {Key: []byte(`missing`)}, {Key: []byte(`ref`)}, {Key: []byte(`deleted`)},
+		},
+		RootIndex: &schema.Index{Index: prevRoot.GetIndex()},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, second.Index, list.Items[0].Item.Index)
+	assert.Equal(t, []byte(`secondValue`), list.Items[0].Item.Value)
+	assert.Equal(t, first.Index, list.Items[1].Item.Index)
+	assert.Equal(t, []byte(`first`), list.Items[1].Item.Key)
+	for _, safeItem := range list.Items {
+		assert.Equal(t, list.Items[0].Proof.At, safeItem.Proof.At)
+		assert.True(t, safeItem.Proof.Verify(safeItem.Item.Hash(), *prevRoot))
+	}
+
+	list, err = st.SafeGetBatch(schema.SafeGetBatchOptions{
+		Keys:       []*schema.Key{{Key: []byte(`second`)}, {Key: []byte(`ref`)}, {Key: []byte(`first`)}},
+		RootIndex:  &schema.Index{Index: prevRoot.GetIndex()},
+		MultiProof: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 3)
+	assert.True(t, list.MultiProof.Verify(*prevRoot))
+	assert.Equal(t, []uint64{first.Index, second.Index}, list.MultiProof.Indexes)
+	for _, safeItem := range list.Items {
+		assert.Nil(t, safeItem.Proof)
+		assert.True(t, list.MultiProof.Includes(safeItem.Item.Index, safeItem.Item.Hash()))
+	}
+
+	list, err = st.SafeGetBatch(schema.SafeGetBatchOptions{Keys: []*schema.Key{{Key: []byte(`missing`)}}})
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 0)
+
+	_, err = st.SafeGetBatch(schema.SafeGetBatchOptions{Keys: []*schema.Key{{Key: []byte{tsPrefix}}}})
+	assert.Equal(t, ErrInvalidKey, err)
+}
+
 func TestStoreSafeReference(t *testing.T) {
 	st, closer := makeStore()
 	defer closer()
@@ -422,6 +482,66 @@ func TestStoreSafeZAdd(t *testing.T) {
 	assert.True(t, verified2)
 }
 
+func TestStoreSafeZScan(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	first, err := st.Set(schema.KeyValue{Key: []byte(`first`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`second`), Value: []byte(`v2`)})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`second`), Score: &schema.Score{Score: 2}})
+	require.NoError(t, err)
+	// the entry is pinned to the first revision of the key, which is updated afterwards
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`first`), Score: &schema.Score{Score: 1}, Index: first})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`first`), Value: []byte(`v1 updated`)})
+	require.NoError(t, err)
+	prevRoot, err := st.CurrentRoot()
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`other`), Value: []byte(`v3`)})
+	require.NoError(t, err)
+
+	list, err := st.SafeZScan(schema.SafeZScanOptions{
+		Zopts:     &schema.ZScanOptions{Set: []byte(`set`)},
+		RootIndex: &schema.Index{Index: prevRoot.GetIndex()},
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, []byte(`v1`), list.Items[0].Item.Value)
+	assert.Equal(t, []byte(`v2`), list.Items[1].Item.Value)
+	assert.True(t, list.MultiProof.Verify(*prevRoot))
+	assert.Len(t, list.MultiProof.Indexes, 4)
+	for _, zitem := range list.Items {
+		leaf, err := SortedSetEntryHash([]byte(`set`), zitem)
+		require.NoError(t, err)
+		assert.True(t, list.MultiProof.Includes(zitem.Index, leaf))
+		assert.True(t, list.MultiProof.Includes(zitem.Item.Index, zitem.Item.Hash()))
+	}
+
+	// an entry can't be passed off as indexing another item, or with another score
+	forged := *list.Items[1]
+	forged.Item = list.Items[0].Item
+	_, err = SortedSetEntryHash([]byte(`set`), &forged)
+	assert.Equal(t, ErrInvalidReference, err)
+	forged = *list.Items[1]
+	forged.Score = 3
+	_, err = SortedSetEntryHash([]byte(`set`), &forged)
+	assert.Equal(t, ErrInvalidReference, err)
+	_, err = SortedSetEntryHash([]byte(`other`), list.Items[1])
+	assert.Equal(t, ErrInvalidReference, err)
+
+	list, err = st.SafeZScan(schema.SafeZScanOptions{Zopts: &schema.ZScanOptions{Set: []byte(`empty`)}})
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+	assert.Nil(t, list.MultiProof)
+
+	_, err = st.SafeZScan(schema.SafeZScanOptions{})
+	assert.Equal(t, ErrInvalidSet, err)
+	_, err = st.SafeZScan(schema.SafeZScanOptions{Zopts: &schema.ZScanOptions{Set: []byte(`set`)}, RootIndex: &schema.Index{Index: 100}})
+	assert.Equal(t, ErrInvalidRootIndex, err)
+}
+
 func TestStoreBySafeIndex(t *testing.T) {
 	st, closer := makeStore()
 	defer closer()
@@ -855,3 +975,75 @@ func TestStore_SafeGetReferenceKeyNotFound(t *testing.T) {
 
 	assert.Equal(t, err, ErrKeyNotFound)
 }
+
+func TestStoreVerifyValueHash(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	key := []byte(`myKey`)
+	_, err := st.Set(schema.KeyValue{Key: key, Value: []byte(`firstValue`)})
+	assert.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: key, Value: []byte(`secondValue`)})
+	assert.NoError(t, err)
+
+	first := sha256.Sum256([]byte(`firstValue`))
+	second := sha256.Sum256([]byte(`secondValue`))
+
+	st.tree.WaitUntil(1)
+
+	// current entry
+	v, err := st.VerifyValueHash(schema.ValueHashOptions{Key: key, ValueHash: second[:]})
+	assert.NoError(t, err)
+	assert.True(t, v.Matches)
+	assert.Equal(t, uint64(1), v.Proof.Index)
+	assert.Equal(t, api.Digest(1, key, []byte(`secondValue`)), toArray(v.Proof.Leaf))
+	assert.True(t, v.Proof.Verify(v.Proof.Leaf, *schema.NewRoot()))
+
+	v, err = st.VerifyValueHash(schema.ValueHashOptions{Key: key, ValueHash: first[:]})
+	assert.NoError(t, err)
+	assert.False(t, v.Matches)
+
+	// entry at a given index, consistent with a previous root
+	prevRoot := v.Proof.NewRoot()
+	v, err = st.VerifyValueHash(schema.ValueHashOptions{
+		Key:       key,
+		ValueHash: first[:],
+		Index:     &schema.Index{Index: 0},
+		RootIndex: &schema.Index{Index: prevRoot.GetIndex()},
+	})
+	assert.NoError(t, err)
+	assert.True(t, v.Matches)
+	assert.Equal(t, uint64(0), v.Proof.Index)
+	assert.True(t, v.Proof.Verify(v.Proof.Leaf, *prevRoot))
+
+	// structured value
+	sitem := schema.StructuredItem{Key: []byte(`myStructuredKey`), Value: &schema.Content{Timestamp: 1, Payload: []byte(`payload`)}}
+	sv, err := sitem.ToItem()
+	assert.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: sv.Key, Value: sv.Value})
+	assert.NoError(t, err)
+	payload := sha256.Sum256([]byte(`payload`))
+	v, err = st.VerifyValueHash(schema.ValueHashOptions{Key: sitem.Key, ValueHash: payload[:], Structured: true})
+	assert.NoError(t, err)
+	assert.True(t, v.Matches)
+	v, err = st.VerifyValueHash(schema.ValueHashOptions{Key: sitem.Key, ValueHash: payload[:]})
+	assert.NoError(t, err)
+	assert.False(t, v.Matches)
+
+	_, err = st.VerifyValueHash(schema.ValueHashOptions{Key: []byte(`otherKey`), ValueHash: first[:], Index: &schema.Index{Index: 0}})
+	assert.Equal(t, ErrIndexKeyMismatch, err)
+
+	_, err = st.VerifyValueHash(schema.ValueHashOptions{Key: []byte(`otherKey`), ValueHash: first[:]})
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, err = st.VerifyValueHash(schema.ValueHashOptions{Key: key, ValueHash: first[:8]})
+	assert.Equal(t, ErrInvalidValueHash, err)
+
+	_, err = st.VerifyValueHash(schema.ValueHashOptions{ValueHash: first[:]})
+	assert.Equal(t, ErrInvalidKey, err)
+}
+
+func toArray(b []byte) (a [sha256.Size]byte) {
+	copy(a[:], b)
+	return
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"container/heap"
+	"math"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/merkletree"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// SampleKeys draws a reproducible pseudo-random sample of options.Size keys having options.Prefix and returns
+// their current entries along with inclusion proofs against the same root.
+// Keys are selected by their schema.SampleRank for options.Seed, so that the same seed on the same keys always
+// yields the same sample, regardless of the key order. References are not sampled.
+func (t *Store) SampleKeys(options schema.SampleOptions) (*schema.KeySample, error) {
+	if isReservedKey(options.Prefix) {
+		return nil, ErrInvalidKeyPrefix
+	}
+	if options.Size == 0 || options.Size > uint64(t.db.MaxBatchCount()) {
+		return nil, ErrInvalidSampleSize
+	}
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: false,
+		Prefix:         options.Prefix,
+	})
+	defer it.Close()
+
+	var population uint64
+	sample := &rankedKeys{}
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		if isReservedKey(item.Key()) || item.UserMeta()&(bitReferenceEntry|bitPrunedEntry) != 0 {
+			continue
+		}
+		population++
+		rank := schema.SampleRank(options.Seed, item.Key())
+		if uint64(sample.Len()) < options.Size {
+			heap.Push(sample, rankedKey{rank: rank, key: item.KeyCopy(nil)})
+		} else if bytes.Compare(rank, (*sample)[0].rank) < 0 {
+			(*sample)[0] = rankedKey{rank: rank, key: item.KeyCopy(nil)}
+			heap.Fix(sample, 0)
+		}
+	}
+	if population == 0 {
+		return nil, ErrKeyNotFound
+	}
+
+	// pop from the max-heap to get the sampled keys sorted by decreasing rank
+	items := make([]*schema.Item, sample.Len())
+	var last uint64
+	for i := len(items) - 1; i >= 0; i-- {
+		key := heap.Pop(sample).(rankedKey).key
+		bi, err := txn.Get(key)
+		if err != nil {
+			return nil, mapError(err)
+		}
+		item, err := itemToSchema(key, bi)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+		if item.Index > last {
+			last = item.Index
+		}
+	}
+
+	// ensure all the sampled entries have been included into the tree
+	t.tree.WaitUntil(last)
+
+	ts := t.tree
+	ts.RLock()
+	defer ts.RUnlock()
+
+	at := ts.w - 1
+
+	indexes := make([]uint64, len(items))
+	for i, item := range items {
+		indexes[i] = item.Index
+	}
+	proofs, err := inclusionProofs(ts, at, indexes)
+	if err != nil {
+		return nil, err
+	}
+	root := merkletree.Root(ts)
+
+	entries := make([]*schema.KeyHistoryEntry, len(items))
+	for i, item := range items {
+		entries[i] = &schema.KeyHistoryEntry{
+			Item:  item,
+			Proof: proofs[i],
+		}
+	}
+
+	return &schema.KeySample{
+		Seed:       options.Seed,
+		Prefix:     options.Prefix,
+		Population: population,
+		Entries:    entries,
+		Root: &schema.Root{
+			Payload: &schema.RootIndex{
+				Index: at,
+				Root:  root[:],
+			},
+		},
+	}, nil
+}
+
+type rankedKey struct {
+	rank []byte
+	key  []byte
+}
+
+// rankedKeys is a max-heap of keys ordered by rank, holding the lowest ranked keys seen so far
+type rankedKeys []rankedKey
+
+func (h rankedKeys) Len() int           { return len(h) }
+func (h rankedKeys) Less(i, j int) bool { return bytes.Compare(h[i].rank, h[j].rank) > 0 }
+func (h rankedKeys) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *rankedKeys) Push(x interface{}) {
+	*h = append(*h, x.(rankedKey))
+}
+
+func (h *rankedKeys) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSampleKeys(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	var keys [][]byte
+	for i := 0; i < 50; i++ {
+		key := []byte(`sample` + strconv.Itoa(i))
+		keys = append(keys, key)
+		_, err := st.Set(schema.KeyValue{Key: key, Value: []byte(`v1`)})
+		require.NoError(t, err)
+		_, err = st.Set(schema.KeyValue{Key: key, Value: []byte(`v2`)})
+		require.NoError(t, err)
+	}
+	_, err := st.Set(schema.KeyValue{Key: []byte(`other`), Value: []byte(`v`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`sampleRef`), Key: []byte(`sample0`)})
+	require.NoError(t, err)
+
+	sample, err := st.SampleKeys(schema.SampleOptions{Size: 10, Prefix: []byte(`sample`), Seed: 42})
+	require.NoError(t, err)
+	require.Len(t, sample.Entries, 10)
+	assert.Equal(t, uint64(50), sample.Population)
+	assert.NoError(t, sample.Verify())
+
+	// the sample is made of the lowest ranked keys, with their latest value
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(schema.SampleRank(42, keys[i]), schema.SampleRank(42, keys[j])) < 0
+	})
+	for i, e := range sample.Entries {
+		assert.Equal(t, keys[i], e.Item.Key)
+		assert.Equal(t, []byte(`v2`), e.Item.Value)
+	}
+
+	root, err := st.CurrentRoot()
+	require.NoError(t, err)
+	assert.Equal(t, root.GetRoot(), sample.Root.GetRoot())
+
+	again, err := st.SampleKeys(schema.SampleOptions{Size: 10, Prefix: []byte(`sample`), Seed: 42})
+	require.NoError(t, err)
+	for i := range sample.Entries {
+		assert.Equal(t, sample.Entries[i].Item.Key, again.Entries[i].Item.Key)
+	}
+
+	other, err := st.SampleKeys(schema.SampleOptions{Size: 10, Prefix: []byte(`sample`), Seed: 7})
+	require.NoError(t, err)
+	assert.NotEqual(t, sample.Entries[0].Item.Key, other.Entries[0].Item.Key)
+
+	all, err := st.SampleKeys(schema.SampleOptions{Size: 100, Prefix: []byte(`sample`), Seed: 42})
+	require.NoError(t, err)
+	assert.Len(t, all.Entries, 50)
+
+	sample.Entries[0], sample.Entries[1] = sample.Entries[1], sample.Entries[0]
+	assert.Equal(t, schema.ErrCorruptedKeySample, sample.Verify())
+	sample.Entries[0], sample.Entries[1] = sample.Entries[1], sample.Entries[0]
+	sample.Entries[1].Item.Value = []byte(`tampered`)
+	assert.Equal(t, schema.ErrCorruptedKeySample, sample.Verify())
+
+	_, err = st.SampleKeys(schema.SampleOptions{Size: 0, Prefix: []byte(`sample`)})
+	assert.Equal(t, ErrInvalidSampleSize, err)
+	_, err = st.SampleKeys(schema.SampleOptions{Size: 1, Prefix: []byte(`missing`)})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.SampleKeys(schema.SampleOptions{Size: 1, Prefix: []byte{tsPrefix}})
+	assert.Equal(t, ErrInvalidKeyPrefix, err)
+
+	var empty *schema.KeySample
+	assert.Equal(t, schema.ErrEmptyKeySample, empty.Verify())
+}
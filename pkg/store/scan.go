@@ -17,13 +17,19 @@ limitations under the License.
 package store
 
 import (
+	"bytes"
 	"math"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/dgraph-io/badger/v2"
 )
 
-// Scan fetch the entries having the specified key prefix
+// Scan fetch the entries having the specified key prefix, in key order or, if reversed, backwards from the last key
+// having the prefix. Entries are read until the limit is reached, so that the first or latest keys of a large
+// keyspace are fetched without going through the others.
+// The list has a cursor only if more entries follow, which resumes the scan in the same direction after the last
+// entry returned when set in options: unlike the offset, it's a position in the keyspace, so that deleted entries and
+// references resolved by a deep scan neither repeat nor skip entries across pages.
 func (t *Store) Scan(options schema.ScanOptions) (list *schema.ItemList, err error) {
 	if isReservedKey(options.Prefix) {
 		return nil, ErrInvalidKeyPrefix
@@ -33,31 +39,56 @@ func (t *Store) Scan(options schema.ScanOptions) (list *schema.ItemList, err err
 		return nil, ErrInvalidOffset
 	}
 
+	var resumeAt []byte
+	if len(options.Cursor) > 0 {
+		if resumeAt, err = decodeScanCursor(options.Cursor, options.Prefix, options.Reverse); err != nil {
+			return nil, err
+		}
+	}
+
 	txn := t.db.NewTransactionAt(math.MaxUint64, false)
 	defer txn.Discard()
 
-	it := txn.NewIterator(badger.IteratorOptions{
+	iteratorOptions := badger.IteratorOptions{
 		PrefetchValues: true,
-		PrefetchSize:   int(options.Limit),
+		PrefetchSize:   t.scanPrefetch.size(options.Limit),
 		Prefix:         options.Prefix,
 		Reverse:        options.Reverse,
-	})
-	defer it.Close()
-
-	offsettedKey := options.Prefix
-
-	if len(options.Offset) > 0 {
-		offsettedKey = options.Offset
 	}
-
 	if options.Reverse {
-		offsettedKey = append(offsettedKey, 0xFF)
+		// a reverse scan starts from the end of the prefix, which the iterator couldn't be positioned at if it was
+		// bound to the prefix: the prefix is checked while iterating instead
+		iteratorOptions.Prefix = nil
 	}
+	it := txn.NewIterator(iteratorOptions)
+	defer it.Close()
 
-	it.Seek(offsettedKey)
+	if resumeAt != nil {
+		it.Seek(resumeAt)
+		if it.Valid() && bytes.Equal(it.Item().Key(), resumeAt) {
+			it.Next()
+		}
+	} else if len(options.Offset) > 0 {
+		offsettedKey := options.Offset
+		if options.Reverse {
+			offsettedKey = append(append([]byte(nil), options.Offset...), 0xFF)
+		}
 
-	if len(options.Offset) > 0 && it.Valid() {
-		it.Next() // skip the offset item
+		it.Seek(offsettedKey)
+
+		if it.Valid() {
+			it.Next() // skip the offset item
+		}
+	} else if options.Reverse {
+		// the entries are read backwards from the last key having the prefix, so that the latest keys of a large
+		// keyspace are reached without going through the previous ones
+		end := prefixEnd(options.Prefix)
+		it.Seek(end)
+		if end != nil && it.Valid() && bytes.Equal(it.Item().Key(), end) {
+			it.Next()
+		}
+	} else {
+		it.Seek(options.Prefix)
 	}
 
 	var limit = options.Limit
@@ -67,65 +98,118 @@ func (t *Store) Scan(options schema.ScanOptions) (list *schema.ItemList, err err
 	}
 
 	var items []*schema.Item
-	i := uint64(0)
+	var last []byte
 
-	for ; it.Valid(); it.Next() {
-		var item *schema.Item
+	visited := uint64(0)
+	defer func() { t.scanPrefetch.observe(visited) }()
 
-		if it.Item().UserMeta()&bitReferenceEntry == bitReferenceEntry {
-			if !options.Deep {
-				continue
-			}
-			var refKey []byte
+	for ; it.ValidForPrefix(options.Prefix); it.Next() {
+		visited++
+		item, err := t.scanItem(txn, it.Item(), options.Deep)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			continue
+		}
+		if uint64(len(items)) == limit {
+			// an entry follows the last one returned
+			return &schema.ItemList{Items: items, Cursor: encodeScanCursor(last, options.Reverse)}, nil
+		}
+		items = append(items, item)
+		last = it.Item().KeyCopy(last[:0])
+	}
 
-			err = it.Item().Value(func(val []byte) error {
-				refKey, _ = UnwrapValueWithTS(val)
-				return nil
-			})
-			if err != nil {
-				return nil, err
-			}
+	list = &schema.ItemList{
+		Items: items,
+	}
 
-			refKey, flag, refIndex := UnwrapZIndexReference(refKey)
+	return
+}
 
-			// here check for index reference, if present we resolve reference with itemAt
-			if flag == byte(1) {
-				idx, key, val, err := t.itemAt(refIndex + 1) // itemAt returns index
-				if err != nil {
-					return nil, err
-				}
+// prefixEnd returns the first key following all the keys having prefix, nil if no key follows them
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xFF {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
 
-				item = &schema.Item{
-					Key:   key,
-					Value: val,
-					Index: idx,
-				}
-			} else {
-				if ref, err := txn.Get(refKey); err == nil {
-					item, err = itemToSchema(refKey, ref)
-					if err != nil {
-						return nil, err
-					}
-				}
-			}
-		} else {
-			item, err = itemToSchema(nil, it.Item())
-			if err != nil {
-				return nil, err
-			}
+// scanItem returns the item of the entry as returned by Scan, nil if the entry is skipped: a deleted key, a
+// reference in a scan which is not deep or a reference which can't be resolved
+func (t *Store) scanItem(txn *badger.Txn, entry *badger.Item, deep bool) (*schema.Item, error) {
+	if entry.UserMeta()&bitReferenceEntry != bitReferenceEntry {
+		deleted, err := t.deleted(entry)
+		if err != nil || deleted {
+			return nil, err
 		}
+		return itemToSchema(nil, entry)
+	}
+	if !deep {
+		return nil, nil
+	}
+	var refKey []byte
+	err := entry.Value(func(val []byte) (err error) {
+		refKey, _, err = unwrapValue(entry.UserMeta(), val)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		items = append(items, item)
-		if i++; i == limit {
-			break
+	refKey, flag, refIndex := UnwrapZIndexReference(refKey)
+
+	// here check for index reference, if present we resolve reference with itemAt
+	if flag == byte(1) {
+		item, err := t.itemAt(refIndex + 1)
+		if err == ErrEntryPruned {
+			return nil, nil
 		}
+		return item, err
 	}
+	ref, err := txn.Get(refKey)
+	if err != nil {
+		return nil, nil
+	}
+	deleted, err := t.deleted(ref)
+	if err != nil || deleted {
+		return nil, err
+	}
+	return itemToSchema(refKey, ref)
+}
 
-	list = &schema.ItemList{
-		Items: items,
+// scan cursor versions, telling also the direction of the scan
+const (
+	scanCursorForward byte = 1
+	scanCursorReverse byte = 2
+)
+
+// encodeScanCursor returns the cursor resuming a scan after the entry at key
+func encodeScanCursor(key []byte, reverse bool) []byte {
+	c := make([]byte, 1+len(key))
+	c[0] = scanCursorForward
+	if reverse {
+		c[0] = scanCursorReverse
 	}
+	copy(c[1:], key)
+	return c
+}
 
-	return
+// decodeScanCursor returns the key of the entry the scan is resumed after, checking that the cursor has been
+// returned by a scan having the same prefix and direction
+func decodeScanCursor(cursor []byte, prefix []byte, reverse bool) ([]byte, error) {
+	version := scanCursorForward
+	if reverse {
+		version = scanCursorReverse
+	}
+	if len(cursor) < 2 || cursor[0] != version || !bytes.HasPrefix(cursor[1:], prefix) || isReservedKey(cursor[1:]) {
+		return nil, ErrInvalidCursor
+	}
+	return cursor[1:], nil
 }
 
 // ZScan The SCAN command is used in order to incrementally iterate over a collection of elements.
@@ -147,7 +231,7 @@ func (t *Store) ZScan(options schema.ZScanOptions) (list *schema.ZItemList, err
 
 	it := txn.NewIterator(badger.IteratorOptions{
 		PrefetchValues: true,
-		PrefetchSize:   int(options.Limit),
+		PrefetchSize:   t.zScanPrefetch.size(options.Limit),
 		Prefix:         set,
 		Reverse:        options.Reverse,
 	})
@@ -185,7 +269,11 @@ func (t *Store) ZScan(options schema.ZScanOptions) (list *schema.ZItemList, err
 	var items []*schema.ZItem
 	i := uint64(0)
 
+	visited := uint64(0)
+	defer func() { t.zScanPrefetch.observe(visited) }()
+
 	for ; it.Valid(); it.Next() {
+		visited++
 
 		var zitem *schema.ZItem
 		var item *schema.Item
@@ -195,11 +283,11 @@ func (t *Store) ZScan(options schema.ZScanOptions) (list *schema.ZItemList, err
 		if it.Item().UserMeta()&bitReferenceEntry == bitReferenceEntry {
 			var refKey []byte
 
-			err = it.Item().Value(func(val []byte) error {
-				refKey, _ = UnwrapValueWithTS(val)
+			err = it.Item().Value(func(val []byte) (err error) {
+				refKey, _, err = unwrapValue(it.Item().UserMeta(), val)
 				sortedSetItemKey = it.Item().KeyCopy(nil)
 				sortedSetItemIndex = it.Item().Version() - 1
-				return nil
+				return err
 			})
 			if err != nil {
 				return nil, err
@@ -209,18 +297,21 @@ func (t *Store) ZScan(options schema.ZScanOptions) (list *schema.ZItemList, err
 
 			// here check for index reference, if present we resolve reference with itemAt
 			if flag == byte(1) {
-				idx, key, val, err := t.itemAt(refIndex + 1) // itemAt returns index
-				if err != nil {
+				var err error
+				if item, err = t.itemAt(refIndex + 1); err == ErrEntryPruned {
+					continue
+				} else if err != nil {
 					return nil, err
 				}
-
-				item = &schema.Item{
-					Key:   key,
-					Value: val,
-					Index: idx,
-				}
 			} else {
 				if ref, err := txn.Get(refKey); err == nil {
+					deleted, err := t.deleted(ref)
+					if err != nil {
+						return nil, err
+					}
+					if deleted {
+						continue
+					}
 					item, err = itemToSchema(refKey, ref)
 					if err != nil {
 						return nil, err
@@ -17,6 +17,7 @@ limitations under the License.
 package store
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -463,3 +464,121 @@ func TestStoreIndexReferenceScan(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Exactly(t, 0, len(list.Items))
 }
+
+func BenchmarkStoreScan(b *testing.B) {
+	st, closer := makeStore()
+	defer closer()
+
+	// values are large enough to be stored into the value log, where read-ahead matters
+	value := make([]byte, 4096)
+	for i := 0; i < 10000; i++ {
+		_, err := st.Set(schema.KeyValue{Key: []byte("key" + strconv.Itoa(i)), Value: value})
+		require.NoError(b, err)
+	}
+
+	for _, limit := range []uint64{10, 1000, 0} {
+		b.Run("limit="+strconv.FormatUint(limit, 10), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := st.Scan(schema.ScanOptions{Prefix: []byte("key"), Limit: limit}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreScanCursor(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	for _, k := range []string{`event1`, `event2`, `event3`, `event4`, `event5`, `other`} {
+		_, err := st.Set(schema.KeyValue{Key: []byte(k), Value: []byte(k)})
+		require.NoError(t, err)
+	}
+	_, err := st.Delete(schema.Key{Key: []byte(`event3`)})
+	require.NoError(t, err)
+	// the reference resolves to a key out of the prefix
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`event6`), Key: []byte(`other`)})
+	require.NoError(t, err)
+
+	page := func(options schema.ScanOptions) (keys []string) {
+		for {
+			list, err := st.Scan(options)
+			require.NoError(t, err)
+			require.True(t, len(list.Items) <= int(options.Limit))
+			for _, item := range list.Items {
+				keys = append(keys, string(item.Key))
+			}
+			if len(list.Cursor) == 0 {
+				return keys
+			}
+			options.Cursor = list.Cursor
+		}
+	}
+
+	require.Equal(t, []string{`event1`, `event2`, `event4`, `event5`},
+		page(schema.ScanOptions{Prefix: []byte(`event`), Limit: 2}))
+	require.Equal(t, []string{`event5`, `event4`, `event2`, `event1`},
+		page(schema.ScanOptions{Prefix: []byte(`event`), Limit: 2, Reverse: true}))
+	require.Equal(t, []string{`other`, `event5`, `event4`, `event2`, `event1`},
+		page(schema.ScanOptions{Prefix: []byte(`event`), Limit: 2, Reverse: true, Deep: true}))
+	require.Equal(t, []string{`event1`, `event2`, `event4`, `event5`, `other`},
+		page(schema.ScanOptions{Prefix: []byte(`event`), Limit: 1, Deep: true}))
+
+	// no cursor on the last page, even if full
+	list, err := st.Scan(schema.ScanOptions{Prefix: []byte(`event`), Limit: 4})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 4)
+	require.Empty(t, list.Cursor)
+
+	list, err = st.Scan(schema.ScanOptions{Prefix: []byte(`event`), Limit: 1, Reverse: true})
+	require.NoError(t, err)
+	_, err = st.Scan(schema.ScanOptions{Prefix: []byte(`event`), Limit: 1, Cursor: list.Cursor})
+	require.Equal(t, ErrInvalidCursor, err)
+	_, err = st.Scan(schema.ScanOptions{Prefix: []byte(`other`), Limit: 1, Reverse: true, Cursor: list.Cursor})
+	require.Equal(t, ErrInvalidCursor, err)
+	_, err = st.Scan(schema.ScanOptions{Limit: 1, Cursor: []byte{scanCursorForward}})
+	require.Equal(t, ErrInvalidCursor, err)
+}
+
+func TestStoreScanReverseFromPrefixEnd(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	for _, key := range [][]byte{
+		[]byte("event\x00\x01"),
+		[]byte("event\x00\xff"),
+		[]byte("event\xff"),
+		[]byte("event\xff\xff\x01"),
+		// the first key following the prefix
+		[]byte("evenu"),
+	} {
+		_, err := st.Set(schema.KeyValue{Key: key, Value: []byte(`value`)})
+		require.NoError(t, err)
+	}
+
+	// the prefix must not be written to, even if it has room for the end of the prefix
+	prefix := make([]byte, 5, 16)
+	copy(prefix, "event")
+	list, err := st.Scan(schema.ScanOptions{Prefix: prefix, Limit: 2, Reverse: true})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, []byte("event\xff\xff\x01"), list.Items[0].Key)
+	assert.Equal(t, []byte("event\xff"), list.Items[1].Key)
+	assert.Equal(t, make([]byte, 11), prefix[5:cap(prefix)])
+
+	list, err = st.Scan(schema.ScanOptions{Prefix: prefix, Limit: 2, Reverse: true, Cursor: list.Cursor})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 2)
+	assert.Equal(t, []byte("event\x00\xff"), list.Items[0].Key)
+	assert.Equal(t, []byte("event\x00\x01"), list.Items[1].Key)
+	assert.Empty(t, list.Cursor)
+
+	// a prefix no key follows
+	_, err = st.Set(schema.KeyValue{Key: []byte("\xff\xff\x01"), Value: []byte(`value`)})
+	require.NoError(t, err)
+	list, err = st.Scan(schema.ScanOptions{Prefix: []byte("\xff\xff"), Reverse: true})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, []byte("\xff\xff\x01"), list.Items[0].Key)
+}
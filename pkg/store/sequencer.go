@@ -0,0 +1,138 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// sequenceLayer prefixes the keys mapping sequence numbers to the indexes of the sequenced writes,
+// like timeLayer it's out of the range of the tree layers
+const sequenceLayer = uint8(253)
+
+func sequenceKey(seq uint64) []byte {
+	return treeKey(sequenceLayer, seq)
+}
+
+func isSequenceKey(key []byte) bool {
+	return len(key) == 1+1+8 && key[0] == tsPrefix && key[1] == sequenceLayer
+}
+
+// sequencer assigns gap-free sequence numbers to the writes, in the order they are committed.
+// Sequenced commits are serialized, so that a sequence number is consumed only by a successful commit.
+type sequencer struct {
+	sync.Mutex
+	enabled bool
+	last    uint64
+}
+
+func loadSequencer(db *badger.DB, enabled bool) *sequencer {
+	return &sequencer{enabled: enabled, last: lastSequence(db)}
+}
+
+// lastSequence reads the last sequence number assigned, sequence numbers start from 1
+func lastSequence(db *badger.DB) uint64 {
+	txn := db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	prefix := []byte{tsPrefix, sequenceLayer}
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: false,
+		Reverse:        true,
+		Prefix:         prefix,
+	})
+	defer it.Close()
+	it.Seek(sequenceKey(math.MaxUint64))
+	if !it.ValidForPrefix(prefix) {
+		return 0
+	}
+	return binary.BigEndian.Uint64(it.Item().Key()[2:])
+}
+
+// reload reads again the last sequence number assigned, after the store content has been replaced
+func (s *sequencer) reload(db *badger.DB) {
+	s.Lock()
+	defer s.Unlock()
+	s.last = lastSequence(db)
+}
+
+// sequence assigns the next sequence number to the write of the entries from first to last index, to be committed by txn.
+// It returns 0 if the sequencer is disabled. Otherwise, done must be called with the outcome of the commit:
+// until then no other write can be sequenced.
+func (s *sequencer) sequence(txn *badger.Txn, first, last uint64) (seq uint64, done func(error), err error) {
+	if !s.enabled {
+		return 0, func(error) {}, nil
+	}
+	s.Lock()
+	seq = s.last + 1
+	v := make([]byte, 16)
+	binary.BigEndian.PutUint64(v, first)
+	binary.BigEndian.PutUint64(v[8:], last)
+	if err = txn.SetEntry(&badger.Entry{Key: sequenceKey(seq), Value: v}); err != nil {
+		s.Unlock()
+		return 0, nil, err
+	}
+	return seq, func(err error) {
+		if err == nil {
+			s.last = seq
+		}
+		s.Unlock()
+	}, nil
+}
+
+// LastSequence returns the sequence number of the last sequenced write, 0 if none
+func (t *Store) LastSequence() uint64 {
+	t.sequencer.Lock()
+	defer t.sequencer.Unlock()
+	return t.sequencer.last
+}
+
+// GetBySequence returns the entries written by the write having the given sequence number
+func (t *Store) GetBySequence(seq schema.Sequence) (*schema.SequencedWrite, error) {
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	item, err := txn.Get(sequenceKey(seq.Sequence))
+	if err == badger.ErrKeyNotFound {
+		return nil, ErrSequenceNotFound
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+	v, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	write := &schema.SequencedWrite{
+		Sequence:   seq.Sequence,
+		FirstIndex: binary.BigEndian.Uint64(v),
+		LastIndex:  binary.BigEndian.Uint64(v[8:]),
+	}
+	for index := write.FirstIndex; index <= write.LastIndex; index++ {
+		item, err := t.ByIndex(schema.Index{Index: index})
+		if err != nil {
+			return nil, err
+		}
+		write.Items = append(write.Items, item)
+	}
+	return write, nil
+}
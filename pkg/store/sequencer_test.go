@@ -0,0 +1,144 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSequencer(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	slog := logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogDebug)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+	st, err := Open(opts.WithSequencer(true), badgerOpts)
+	require.NoError(t, err)
+
+	index, err := st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), index.Sequence)
+
+	index, err = st.SetBatch(schema.KVList{KVs: []*schema.KeyValue{
+		{Key: []byte(`key1`), Value: []byte(`value1`)},
+		{Key: []byte(`key2`), Value: []byte(`value2`)},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), index.Sequence)
+
+	// a rejected write does not consume a sequence number
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`missing`)})
+	require.Error(t, err)
+
+	proof, err := st.SafeSet(schema.SafeSetOptions{Kv: &schema.KeyValue{Key: []byte(`key3`), Value: []byte(`value3`)}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), proof.Sequence)
+
+	write, err := st.GetBySequence(schema.Sequence{Sequence: 2})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), write.FirstIndex)
+	assert.Equal(t, uint64(2), write.LastIndex)
+	require.Len(t, write.Items, 2)
+	assert.Equal(t, []byte(`key1`), write.Items[0].Key)
+	assert.Equal(t, []byte(`value2`), write.Items[1].Value)
+
+	_, err = st.GetBySequence(schema.Sequence{Sequence: 4})
+	assert.Equal(t, ErrSequenceNotFound, err)
+
+	// sequence numbers are metadata, not entries: only the 4 entries and their leaves are counted
+	assert.Equal(t, uint64(8), st.CountAll())
+	entries, leaves := st.CountEntriesAndLeaves()
+	assert.Equal(t, entries, leaves)
+	require.NoError(t, st.Close())
+
+	// the sequence survives a restart
+	st, err = Open(opts.WithSequencer(true), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	assert.Equal(t, uint64(3), st.LastSequence())
+	index, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte(`frozen`)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), index.Sequence)
+}
+
+func TestStoreSequencerConcurrentWriters(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	slog := logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogDebug)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+	st, err := Open(opts.WithSequencer(true), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	const writers, writes = 8, 25
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sequences := make([]uint64, 0, writers*writes)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < writes; i++ {
+				key := []byte(fmt.Sprintf("key-%d-%d", w, i))
+				index, err := st.Set(schema.KeyValue{Key: key, Value: key}, WithAsyncCommit(i%2 == 0))
+				if !assert.NoError(t, err) {
+					return
+				}
+				mu.Lock()
+				sequences = append(sequences, index.Sequence)
+				mu.Unlock()
+			}
+		}(w)
+	}
+	wg.Wait()
+	st.Wait()
+
+	// sequence numbers are unique and gap-free
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	for i, seq := range sequences {
+		require.Equal(t, uint64(i+1), seq)
+	}
+	for _, seq := range sequences {
+		write, err := st.GetBySequence(schema.Sequence{Sequence: seq})
+		require.NoError(t, err)
+		require.Len(t, write.Items, 1)
+		assert.Equal(t, write.Items[0].Key, write.Items[0].Value)
+	}
+}
+
+func TestStoreSequencerDisabled(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	index, err := st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), index.Sequence)
+	assert.Equal(t, uint64(0), st.LastSequence())
+
+	_, err = st.GetBySequence(schema.Sequence{Sequence: 1})
+	assert.Equal(t, ErrSequenceNotFound, err)
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package store
 
 import (
+	"bytes"
 	"encoding/binary"
-	"github.com/codenotary/immudb/pkg/api/schema"
 	"math"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
 )
 
 var _SetSeparator = []byte(`_~|IMMU|~_`)
@@ -116,3 +119,24 @@ func UnwrapZIndexReference(reference []byte) (key []byte, flag byte, idx uint64)
 	}
 	return
 }
+
+// SortedSetEntryHash returns the leaf of the entry zitem of the sorted set set, as returned by ZScan, after checking
+// that the entry references zitem.Item with the score of zitem: once the leaves of both the entry and the item are
+// proved, the item is proved to be indexed in the sorted set.
+// ErrInvalidReference is returned if the entry doesn't reference the item.
+func SortedSetEntryHash(set []byte, zitem *schema.ZItem) ([]byte, error) {
+	item := zitem.GetItem()
+	offset := zitem.GetCurrentOffset()
+	if item == nil || len(offset) < 8+1 {
+		return nil, ErrInvalidReference
+	}
+	var index *schema.Index
+	if offset[len(offset)-1] == byte(1) {
+		index = &schema.Index{Index: item.Index}
+	}
+	if !bytes.Equal(BuildSetKey(item.Key, set, zitem.Score, index), offset) {
+		return nil, ErrInvalidReference
+	}
+	d := api.Digest(zitem.Index, offset, WrapZIndexReference(item.Key, index))
+	return d[:], nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/options"
+)
+
+// thresholds of the storage report recommendations
+const (
+	// below this share of live values, garbage collecting the value log is recommended
+	minVlogUtilization = 0.5
+	// entries at least this large on average are worth compressing
+	compressionCandidateSize = 128
+	// above this ratio of the disk usage to the size of the entries and of the tree, the store is considered oversized
+	maxSpaceAmplification = 3.0
+	// above this ratio of the size of the tree to the size of the entries, the tree overhead is considered high
+	maxTreeAmplification = 2.0
+)
+
+// StorageReport analyzes how the data entries and the Merkle tree are laid out in the underlying badger store
+// (LSM levels, value log utilization, tree node counts) and recommends maintenance actions.
+// The analysis scans the whole store, so it's meant for occasional administrative use.
+func (t *Store) StorageReport() (*schema.StorageReport, error) {
+	report := &schema.StorageReport{}
+	report.LsmSize, report.VlogSize = t.db.Size()
+
+	levels := map[int]*schema.StorageLevel{}
+	for _, table := range t.db.Tables(true) {
+		l, ok := levels[table.Level]
+		if !ok {
+			l = &schema.StorageLevel{Level: uint32(table.Level)}
+			levels[table.Level] = l
+		}
+		l.Tables++
+		l.Keys += table.KeyCount
+		l.Size += table.EstimatedSz
+	}
+	for _, l := range levels {
+		report.Levels = append(report.Levels, l)
+	}
+	sort.Slice(report.Levels, func(i, j int) bool { return report.Levels[i].Level < report.Levels[j].Level })
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: false,
+		AllVersions:    true,
+	})
+	defer it.Close()
+	var lastTreeKey []byte
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		key := item.Key()
+		size := uint64(len(key)) + uint64(item.ValueSize())
+		// values not smaller than the threshold are kept in the value log, along with their key
+		if item.ValueSize() >= int64(t.badgerOpts.ValueThreshold) {
+			report.VlogLiveSize += uint64(item.EstimatedSize())
+		}
+		switch {
+		case !isReservedKey(key) || isFrozenKey(key) || isTxKey(key):
+			report.Entries++
+			report.EntriesSize += size
+		case len(key) == 1+1+8 && key[0] == tsPrefix && key[1] < sequenceLayer:
+			// overwritten versions of the tree nodes take space until they are compacted away
+			if !bytes.Equal(key, lastTreeKey) {
+				report.TreeNodes++
+				if key[1] == 0 {
+					report.TreeLeaves++
+				}
+				lastTreeKey = item.KeyCopy(lastTreeKey)
+			}
+			report.TreeSize += size
+		default:
+			// commit times, write sequence numbers and the other tree metadata
+			report.TreeSize += size
+		}
+	}
+
+	if report.VlogSize > 0 {
+		report.VlogUtilization = math.Min(1, float64(report.VlogLiveSize)/float64(report.VlogSize))
+	}
+	if logicalSize := report.EntriesSize + report.TreeSize; logicalSize > 0 {
+		report.SpaceAmplification = float64(report.LsmSize+report.VlogSize) / float64(logicalSize)
+	}
+	report.Recommendations = t.storageRecommendations(report)
+	return report, nil
+}
+
+func (t *Store) storageRecommendations(report *schema.StorageReport) []string {
+	var recommendations []string
+	if report.VlogSize > 0 && report.VlogUtilization < minVlogUtilization {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"only %.0f%% of the value log holds live values: running the value log garbage collection could reclaim up to %d bytes",
+			report.VlogUtilization*100, report.VlogSize-int64(report.VlogLiveSize)))
+	}
+	for _, l := range report.Levels {
+		if l.Level == 0 && int(l.Tables) > t.badgerOpts.NumLevelZeroTables {
+			recommendations = append(recommendations, fmt.Sprintf(
+				"%d tables are waiting in level 0 to be compacted: writes are coming faster than compactions can keep up",
+				l.Tables))
+		}
+	}
+	if report.Entries > 0 {
+		avgSize := report.EntriesSize / report.Entries
+		if t.badgerOpts.Compression == options.None && avgSize >= compressionCandidateSize {
+			recommendations = append(recommendations, fmt.Sprintf(
+				"data entries average %d bytes and the store is not compressed: they are good compression candidates",
+				avgSize))
+		}
+		if treeAmplification := float64(report.TreeSize) / float64(report.EntriesSize); treeAmplification > maxTreeAmplification {
+			recommendations = append(recommendations, fmt.Sprintf(
+				"the Merkle tree takes %.1f times the size of the data entries: small entries have a high tree overhead, "+
+					"consider grouping them into larger values",
+				treeAmplification))
+		}
+	}
+	if report.SpaceAmplification > maxSpaceAmplification {
+		recommendations = append(recommendations, fmt.Sprintf(
+			"the store takes %.1f times the size of its data entries and tree on disk: obsolete data is yet to be reclaimed by compactions and value log garbage collection",
+			report.SpaceAmplification))
+	}
+	return recommendations
+}
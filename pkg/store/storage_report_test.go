@@ -0,0 +1,96 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageReport(t *testing.T) {
+	dir := tmpDir()
+	st, _ := makeStoreAt(dir)
+
+	report, err := st.StorageReport()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), report.Entries)
+	require.Equal(t, uint64(0), report.TreeLeaves)
+
+	value := bytes.Repeat([]byte{'v'}, 200)
+	for i := 0; i < 16; i++ {
+		_, err := st.Set(schema.KeyValue{Key: []byte(fmt.Sprintf("key%d", i)), Value: value})
+		require.NoError(t, err)
+	}
+	_, err = st.FreezePrefix(schema.KeyPrefix{Prefix: []byte("frozen")})
+	require.NoError(t, err)
+	st.tree.WaitUntil(16)
+
+	report, err = st.StorageReport()
+	require.NoError(t, err)
+	require.Equal(t, uint64(17), report.Entries)
+	require.Equal(t, uint64(17), report.TreeLeaves)
+	require.True(t, report.EntriesSize > 16*200)
+	// leaves are stored along with the entries, the inner nodes are flushed to disk later on
+	require.Equal(t, uint64(17), report.TreeNodes)
+	require.Contains(t, report.Recommendations[0], "good compression candidates")
+
+	require.NoError(t, st.Close())
+	st, closer := makeStoreAt(dir)
+	defer closer()
+
+	report, err = st.StorageReport()
+	require.NoError(t, err)
+	require.Equal(t, uint64(17), report.Entries)
+	require.Equal(t, uint64(17), report.TreeLeaves)
+	// at least 8 + 4 + 2 + 1 inner nodes over the first 16 leaves
+	require.True(t, report.TreeNodes >= 17+15)
+	require.True(t, report.TreeSize > 32*sha256.Size)
+	require.True(t, report.LsmSize > 0 || report.VlogSize > 0)
+	require.True(t, report.SpaceAmplification > 0)
+	for i := 1; i < len(report.Levels); i++ {
+		require.True(t, report.Levels[i-1].Level < report.Levels[i].Level)
+	}
+}
+
+func TestStorageRecommendations(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	require.Empty(t, st.storageRecommendations(&schema.StorageReport{}))
+
+	recommendations := st.storageRecommendations(&schema.StorageReport{
+		VlogSize:           1000,
+		VlogLiveSize:       100,
+		VlogUtilization:    0.1,
+		Levels:             []*schema.StorageLevel{{Level: 0, Tables: 100}, {Level: 1, Tables: 100}},
+		Entries:            10,
+		EntriesSize:        100,
+		TreeSize:           1000,
+		SpaceAmplification: 10,
+	})
+	require.Len(t, recommendations, 4)
+	require.Contains(t, recommendations[0], "only 10% of the value log holds live values")
+	require.Contains(t, recommendations[0], "up to 900 bytes")
+	require.Contains(t, recommendations[1], "100 tables are waiting in level 0")
+	require.Contains(t, recommendations[2], "the Merkle tree takes 10.0 times")
+	require.Contains(t, recommendations[3], "the store takes 10.0 times")
+}
@@ -22,8 +22,9 @@ import (
 	"crypto/sha256"
 	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"github.com/codenotary/immudb/pkg/api"
 	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/merkletree"
 
@@ -36,33 +37,111 @@ import (
 // Store ...
 type Store struct {
 	sync.RWMutex
-	db   *badger.DB
-	tree *treeStore
-	wg   sync.WaitGroup
-	log  logger.Logger
+	db               *badger.DB
+	tree             *treeStore
+	wg               sync.WaitGroup
+	log              logger.Logger
+	strictAppendOnly bool
+	scanPrefetch     *scanPrefetcher
+	zScanPrefetch    *scanPrefetcher
+	frozen           *frozenPrefixes
+	sequencer        *sequencer
+	// badgerOpts are the options the underlying badger store has been opened with
+	badgerOpts badger.Options
+	// bootEpoch fences the writes of the store, see fence
+	bootEpoch uint64
+	// manifest describes the on-disk format of the store, see Manifest
+	manifest Manifest
+	// valueCompression compresses the values written, see WithValueCompression
+	valueCompression ValueCompression
 }
 
 // Open opens the store with the specified options
 func Open(options Options, badgerOptions badger.Options) (*Store, error) {
+	if err := options.valueCompression.Validate(); err != nil {
+		return nil, err
+	}
+
 	badgerOpts := badgerOptions
 	badgerOpts.ValueDir = badgerOptions.Dir
+	if len(options.encryptionKey) > 0 {
+		if err := ValidateEncryptionKey(options.encryptionKey); err != nil {
+			return nil, err
+		}
+		badgerOpts.EncryptionKey = options.encryptionKey
+		if options.dataKeyRotation > 0 {
+			badgerOpts.EncryptionKeyRotationDuration = options.dataKeyRotation
+		}
+		if badgerOpts.MaxCacheSize == 0 {
+			badgerOpts.MaxCacheSize = encryptionCacheSize
+		}
+	}
 	badgerOpts.NumVersionsToKeep = math.MaxInt64 // immutability, always keep all data
+	if options.syncWrites {
+		badgerOpts.SyncWrites = true
+	}
+
+	persistent := !badgerOpts.InMemory && badgerOpts.Dir != ""
+	manifest, upToDate := newManifest(badgerOpts), false
+	if persistent {
+		var err error
+		if manifest, upToDate, err = openManifest(badgerOpts.Dir, badgerOpts); err != nil {
+			return nil, err
+		}
+		if err = checkTieredSegments(badgerOpts.Dir); err != nil {
+			return nil, err
+		}
+	}
 
 	db, err := badger.OpenManaged(badgerOpts)
 	if err != nil {
 		return nil, mapError(err)
 	}
+	if persistent && !upToDate {
+		if err = writeManifest(badgerOpts.Dir, manifest); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	bootEpoch, err := nextBootEpoch(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
 
 	// fixme(leogr): cache size could be calculated using db.MaxBatchCount()
 	tstore, err := newTreeStore(db, 750_000, false, options.log)
 	if err != nil {
 		return nil, err
 	}
+	if options.clock != nil {
+		tstore.clock = options.clock
+	}
+	if options.treeCheckpointInterval > 0 {
+		if interval := tstore.setCheckpointInterval(options.treeCheckpointInterval); interval != options.treeCheckpointInterval {
+			options.log.Warningf("Tree checkpoint interval %d exceeds the tree caches, using %d", options.treeCheckpointInterval, interval)
+		}
+	}
+	tstore.syncOnFlush = options.treeSync
+	if options.commitGate != nil {
+		options.commitGate.attach(tstore)
+		tstore.gate = options.commitGate
+	}
 
 	t := &Store{
-		db:   db,
-		tree: tstore,
-		log:  options.log,
+		db:               db,
+		tree:             tstore,
+		log:              options.log,
+		strictAppendOnly: options.strictAppendOnly,
+		scanPrefetch:     newScanPrefetcher(),
+		zScanPrefetch:    newScanPrefetcher(),
+		frozen:           loadFrozenPrefixes(db),
+		sequencer:        loadSequencer(db, options.sequencer),
+		badgerOpts:       badgerOpts,
+		bootEpoch:        bootEpoch,
+		manifest:         manifest,
+		valueCompression: options.valueCompression,
 	}
 
 	if t.tree.lastFlushed < t.tree.w {
@@ -78,24 +157,42 @@ func Open(options Options, badgerOptions badger.Options) (*Store, error) {
 	return t, nil
 }
 
+// commitPendingTreeEntries appends to the tree the leaves committed after the last flush.
+// Leaves are written along with their entries, so they are read in a single scan without reading the entries.
 func (t *Store) commitPendingTreeEntries() error {
 	w := t.tree.w
 	t.tree.w = t.tree.lastFlushed
 
-	for i := t.tree.lastFlushed; i < w; i++ {
-		idx, key, value, err := t.itemAt(i + 1)
-		if err != nil {
-			return err
+	return t.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		i := t.tree.lastFlushed
+		leafPrefix := []byte{tsPrefix, 0}
+		for it.Seek(treeKey(0, i)); i < w && it.ValidForPrefix(leafPrefix); it.Next() {
+			if _, index := decodeTreeKey(it.Item().Key()); index != i {
+				return ErrInconsistentState
+			}
+			refkey, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			h, key, err := decodeRefTreeKey(refkey)
+			if err != nil {
+				return err
+			}
+			t.tree.Commit(&treeStoreEntry{
+				ts: i + 1,
+				h:  &h,
+				r:  &key,
+			})
+			i++
 		}
-		h := api.Digest(idx, key, value)
-		tsEntry := &treeStoreEntry{
-			ts: i + 1,
-			h:  &h,
-			r:  &key,
+		if i < w {
+			return ErrIndexNotFound
 		}
-		t.tree.Commit(tsEntry)
-	}
-	return nil
+		return nil
+	})
 }
 
 // Close closes the store
@@ -133,15 +230,32 @@ func (t *Store) Set(kv schema.KeyValue, options ...WriteOption) (index *schema.I
 	if err = checkKey(kv.Key); err != nil {
 		return nil, err
 	}
+	release, err := t.frozen.guard(kv.Key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	tsEntry := t.tree.NewEntry(kv.Key, kv.Value)
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:   kv.Key,
-		Value: WrapValueWithTS(kv.Value, tsEntry.ts),
-	}); err != nil {
+	value, compressed := t.compress(kv.Value)
+	entry := &badger.Entry{
+		Key:      kv.Key,
+		Value:    wrapValue(value, tsEntry.ts),
+		UserMeta: bitChecksummedEntry | compressed,
+	}
+	if !opts.expiresAt.IsZero() {
+		entry.Value = wrapExpiringValue(value, tsEntry.ts, opts.expiresAt)
+		entry.UserMeta |= bitExpiringEntry
+	}
+	if err = txn.SetEntry(entry); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -149,15 +263,18 @@ func (t *Store) Set(kv schema.KeyValue, options ...WriteOption) (index *schema.I
 		Index: tsEntry.ts - 1,
 	}
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:      treeKey(uint8(0), tsEntry.ts-1),
-		Value:    refTreeKey(*tsEntry.h, *tsEntry.r),
-		UserMeta: bitTreeEntry,
-	}); err != nil {
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, index.Index, index.Index)
+	if err != nil {
 		return nil, mapError(err)
 	}
+	index.Sequence = seq
 
 	cb := func(err error) {
+		sequenced(err)
 		if err == nil {
 			t.tree.Commit(tsEntry)
 		} else {
@@ -186,25 +303,40 @@ func (t *Store) Get(key schema.Key) (item *schema.Item, err error) {
 	}
 	txn := t.db.NewTransactionAt(math.MaxUint64, false)
 	defer txn.Discard()
-	i, err := txn.Get(key.Key)
+	i, err := t.get(txn, key.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemToSchema(i.Key(), i)
+}
+
+// get reads with txn the entry having the specified key, resolving references like Get
+func (t *Store) get(txn *badger.Txn, key []byte) (*badger.Item, error) {
+	i, err := txn.Get(key)
 	if err != nil {
 		return nil, mapError(err)
 	}
 
 	if i.UserMeta()&bitReferenceEntry == bitReferenceEntry {
 		var refKey []byte
-		err = i.Value(func(val []byte) error {
-			refKey, _ = UnwrapValueWithTS(val)
-			return nil
+		err = i.Value(func(val []byte) (err error) {
+			refKey, _, err = unwrapValue(i.UserMeta(), val)
+			return err
 		})
+		if err != nil {
+			return nil, mapError(err)
+		}
 		k, _, _ := UnwrapZIndexReference(refKey)
 		i, err = txn.Get(k)
 		if err != nil {
 			return nil, mapError(err)
 		}
 	}
-
-	return itemToSchema(i.Key(), i)
+	if err = t.checkDeleted(i); err != nil {
+		return nil, err
+	}
+	return i, nil
 }
 
 // CountAll returns the total number of entries
@@ -216,11 +348,43 @@ func (t *Store) CountAll() (count uint64) {
 	})
 	defer it.Close()
 	for it.Rewind(); it.Valid(); it.Next() {
+		// commit times and write sequence numbers are metadata of the leaves, not entries on their own,
+		// the boot epoch is metadata of the store
+		if isTimeKey(it.Item().Key()) || isSequenceKey(it.Item().Key()) || isBootEpochKey(it.Item().Key()) {
+			continue
+		}
 		count++
 	}
 	return
 }
 
+// CountEntriesAndLeaves returns the number of data entries (every version of every non reserved key, every freeze
+// entry and every transaction commit entry) and the number of tree leaves, both read from the same committed snapshot.
+// The two values are expected to match: a divergence indicates a corrupted tree or a store bug. Every version of every
+// key is read, so the count takes time proportional to the total number of entries of the store.
+func (t *Store) CountEntriesAndLeaves() (entries uint64, leaves uint64) {
+	t.tree.RLock()
+	leaves = t.tree.w
+	t.tree.RUnlock()
+
+	// each leaf at index i is committed together with a data entry at version i+1
+	txn := t.db.NewTransactionAt(leaves, false)
+	defer txn.Discard()
+	it := txn.NewIterator(badger.IteratorOptions{
+		PrefetchValues: false,
+		AllVersions:    true,
+	})
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		// freeze and transaction commit entries are the only reserved keys having a leaf
+		if isReservedKey(it.Item().Key()) && !isFrozenKey(it.Item().Key()) && !isTxKey(it.Item().Key()) {
+			continue
+		}
+		entries++
+	}
+	return
+}
+
 // Count returns the number of entris having the specified key prefix
 func (t *Store) Count(prefix schema.KeyPrefix) (count *schema.ItemsCount, err error) {
 	if isReservedKey(prefix.Prefix) {
@@ -241,8 +405,18 @@ func (t *Store) Count(prefix schema.KeyPrefix) (count *schema.ItemsCount, err er
 	return
 }
 
-func (t *Store) itemAt(readTs uint64) (index uint64, key, value []byte, err error) {
-	index = readTs - 1
+func (t *Store) itemAt(readTs uint64) (*schema.Item, error) {
+	item, _, err := t.entryAt(readTs)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// entryAt is like itemAt, returning also the user meta of the entry, which tells how the entry has been written.
+// The entries whose value has been pruned are returned without value, along with ErrEntryPruned.
+func (t *Store) entryAt(readTs uint64) (*schema.Item, byte, error) {
+	index := readTs - 1
 	var refkey []byte
 
 	// cache reference lookup
@@ -255,7 +429,7 @@ func (t *Store) itemAt(readTs uint64) (index uint64, key, value []byte, err erro
 
 	// disk reference lookup
 	if refkey == nil {
-		if err = t.db.View(func(txn *badger.Txn) error {
+		if err := t.db.View(func(txn *badger.Txn) error {
 			item, err := txn.Get(treeKey(0, index))
 			if err != nil {
 				return err
@@ -268,19 +442,19 @@ func (t *Store) itemAt(readTs uint64) (index uint64, key, value []byte, err erro
 			if err == badger.ErrKeyNotFound {
 				err = ErrIndexNotFound
 			}
-			return 0, nil, nil, err
+			return nil, 0, err
 		}
 	}
 
-	var hash [sha256.Size]byte
 	// reference parsing
-	if hash, key, err = decodeRefTreeKey(refkey); err != nil {
-		return 0, nil, nil, err
+	hash, key, err := decodeRefTreeKey(refkey)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	if key == nil {
 		// this shouldn't happen
-		return 0, nil, nil, ErrObsoleteDataFormat
+		return nil, 0, ErrObsoleteDataFormat
 	}
 
 	// disk value lookup
@@ -291,50 +465,102 @@ func (t *Store) itemAt(readTs uint64) (index uint64, key, value []byte, err erro
 	defer it.Close()
 
 	var item *schema.Item
+	var meta byte
 	for it.Rewind(); it.Valid(); it.Next() {
 		i, err := itemToSchema(key, it.Item())
 		if err != nil {
-			return 0, nil, nil, err
+			return nil, 0, err
 		}
 		// there are multiple possible versions of a key. Choosing the one with the correct timestamp
 		if i.Index == index {
-			item = i
+			item, meta = i, it.Item().UserMeta()
 			break
 		}
 	}
 
 	if item == nil {
 		// this shouldn't happen
-		return 0, nil, nil, ErrKeyNotFound
+		return nil, 0, ErrKeyNotFound
+	}
+	if meta&bitPrunedEntry == bitPrunedEntry {
+		return &schema.Item{Key: item.Key, Index: item.Index}, meta, ErrEntryPruned
 	}
 
 	// this guard ensure that the insertion order index was not tampered.
-	realHash := api.Digest(index, key, item.Value)
-	if hash != realHash {
-		return 0, nil, nil, ErrInconsistentDigest
+	if !bytes.Equal(hash[:], item.Hash()) {
+		return nil, 0, ErrInconsistentDigest
 	}
-	return index, item.Key, item.Value, nil
+	return item, meta, nil
 }
 
-// ByIndex fetches the entry at the specified index
+// ByIndex fetches the entry at the specified index, tombstones included
 func (t *Store) ByIndex(index schema.Index) (item *schema.Item, err error) {
-	idx, key, value, err := t.itemAt(index.Index + 1)
+	return t.itemAt(index.Index + 1)
+}
+
+// IndexTime returns the commit time of the entry at the specified index.
+// Entries written by versions not recording commit times have none, so ErrIndexNotFound is returned for them.
+func (t *Store) IndexTime(index schema.Index) (time.Time, error) {
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	ts, err := indexTime(txn, index.Index)
 	if err != nil {
-		return nil, err
+		return time.Time{}, err
 	}
-	if err == nil {
-		item = &schema.Item{Key: key, Value: value, Index: idx}
+	return time.Unix(0, ts), nil
+}
+
+// indexTime reads the commit time, in unix nanoseconds, of the entry at index
+func indexTime(txn *badger.Txn, index uint64) (ts int64, err error) {
+	item, err := txn.Get(timeKey(index))
+	if err == badger.ErrKeyNotFound {
+		return 0, ErrIndexNotFound
 	}
-	return
+	if err != nil {
+		return 0, mapError(err)
+	}
+	if err = item.Value(func(v []byte) error {
+		ts = decodeTime(v)
+		return nil
+	}); err != nil {
+		return 0, mapError(err)
+	}
+	return ts, nil
 }
 
-// History fetches the complete history of entries for the specified key
+// History fetches the history of entries for the specified key, but the expired ones, latest first unless reversed.
+// Deletions are listed as the tombstone items having Deleted set.
+// Entries can be restricted to an inclusive range of indexes and to a window of commit times, the entries having no
+// recorded commit time being left out when a time bound is set. Offset is the index of the last entry of the previous
+// page and Limit the maximum number of entries returned.
+// Latest first, the read starts at the last entry before the upper bound of the range and the offset, while entries
+// are read from the first one of the key when reversed; in both cases the reading stops once the limit is reached.
 func (t *Store) History(options *schema.HistoryOptions) (list *schema.ItemList, err error) {
 	if isReservedKey(options.Key) {
 		err = ErrInvalidKey
 		return
 	}
-	txn := t.db.NewTransactionAt(math.MaxInt64, false)
+	if (options.ToIndex != 0 && options.FromIndex > options.ToIndex) ||
+		(options.Since != 0 && options.Until != 0 && options.Since > options.Until) {
+		err = ErrInvalidHistoryRange
+		return
+	}
+	timed := options.Since != 0 || options.Until != 0
+
+	// the entry at index is written at version index + 1: latest first, the entries following the range and the
+	// offset are left out by reading at the version of the last entry before them
+	readTs := uint64(math.MaxInt64)
+	if !options.Reverse {
+		if options.ToIndex != 0 && options.ToIndex < readTs-1 {
+			readTs = options.ToIndex + 1
+		}
+		if options.Offset != 0 && options.Offset < readTs {
+			readTs = options.Offset
+		}
+	}
+
+	txn := t.db.NewTransactionAt(readTs, false)
 	defer txn.Discard()
 
 	it := txn.NewKeyIterator(options.Key, badger.IteratorOptions{
@@ -344,22 +570,43 @@ func (t *Store) History(options *schema.HistoryOptions) (list *schema.ItemList,
 
 	var items []*schema.Item
 	for it.Rewind(); it.Valid(); it.Next() {
-		item, err := itemToSchema(options.Key, it.Item())
-		if err != nil {
-			return nil, err
+		if options.Limit != 0 && uint64(len(items)) == options.Limit {
+			break
+		}
+		index := it.Item().Version() - 1
+		// past the range, the following entries are out of it as well
+		if (options.Reverse && options.ToIndex != 0 && index > options.ToIndex) ||
+			(!options.Reverse && index < options.FromIndex) {
+			break
+		}
+		// versions can't be sought in reverse, the entries before the range and the offset are skipped
+		if options.Reverse && (index < options.FromIndex || (options.Offset != 0 && index <= options.Offset)) {
+			continue
 		}
-		if options.Reverse {
-			if options.Offset != 0 && options.Offset >= item.Index {
+		if timed {
+			ts, err := indexTime(txn, index)
+			if err == ErrIndexNotFound {
 				continue
 			}
-		} else {
-			if options.Offset != 0 && options.Offset <= item.Index {
+			if err != nil {
+				return nil, err
+			}
+			if (options.Since != 0 && ts < time.Unix(options.Since, 0).UnixNano()) ||
+				(options.Until != 0 && ts >= time.Unix(options.Until, 0).UnixNano()) {
 				continue
 			}
 		}
 
-		if items != nil && uint64(len(items)) == options.Limit {
-			break
+		expired, err := t.expired(it.Item())
+		if err != nil {
+			return nil, err
+		}
+		if expired {
+			continue
+		}
+		item, err := itemToSchema(options.Key, it.Item())
+		if err != nil {
+			return nil, err
 		}
 		items = append(items, item)
 	}
@@ -377,6 +624,10 @@ func (t *Store) ZAdd(zaddOpts schema.ZAddOptions, options ...WriteOption) (index
 	txn := t.db.NewTransactionAt(math.MaxUint64, true)
 	defer txn.Discard()
 
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
 	opts := makeWriteOptions(options...)
 	if err = checkKey(zaddOpts.Key); err != nil && zaddOpts.Index == nil {
 		return nil, err
@@ -394,8 +645,8 @@ func (t *Store) ZAdd(zaddOpts schema.ZAddOptions, options ...WriteOption) (index
 
 	if err = txn.SetEntry(&badger.Entry{
 		Key:      ik,
-		Value:    WrapValueWithTS(referenceValue, tsEntry.ts),
-		UserMeta: bitReferenceEntry,
+		Value:    wrapValue(referenceValue, tsEntry.ts),
+		UserMeta: bitReferenceEntry | bitChecksummedEntry,
 	}); err != nil {
 		return nil, mapError(err)
 	}
@@ -404,15 +655,18 @@ func (t *Store) ZAdd(zaddOpts schema.ZAddOptions, options ...WriteOption) (index
 		Index: tsEntry.ts - 1,
 	}
 
-	if err = txn.SetEntry(&badger.Entry{
-		Key:      treeKey(uint8(0), tsEntry.ts-1),
-		Value:    refTreeKey(*tsEntry.h, *tsEntry.r),
-		UserMeta: bitTreeEntry,
-	}); err != nil {
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, index.Index, index.Index)
+	if err != nil {
 		return nil, mapError(err)
 	}
+	index.Sequence = seq
 
 	cb := func(err error) {
+		sequenced(err)
 		if err == nil {
 			t.tree.Commit(tsEntry)
 		} else {
@@ -443,11 +697,18 @@ func (t *Store) getSortedSetKeyVal(txn *badger.Txn, zaddOpts *schema.ZAddOptions
 	var key []byte
 	if zaddOpts.Index != nil {
 		if !skipPersistenceCheck {
+			if err = t.checkCommittedIndex(zaddOpts.Index); err != nil {
+				return nil, nil, err
+			}
 			// convert to internal timestamp for itemAt, that returns the index
-			_, key, _, err = t.itemAt(zaddOpts.Index.Index + 1)
+			item, err := t.itemAt(zaddOpts.Index.Index + 1)
 			if err != nil {
 				return nil, nil, mapError(err)
 			}
+			if item.Deleted {
+				return nil, nil, ErrKeyNotFound
+			}
+			key = item.Key
 			if len(zaddOpts.Key) > 0 && bytes.Compare(key, zaddOpts.Key) != 0 {
 				return nil, nil, ErrIndexKeyMismatch
 			}
@@ -463,6 +724,9 @@ func (t *Store) getSortedSetKeyVal(txn *badger.Txn, zaddOpts *schema.ZAddOptions
 		if err != nil {
 			return nil, nil, mapError(err)
 		}
+		if isTombstone(i) {
+			return nil, nil, ErrKeyNotFound
+		}
 		key = i.KeyCopy(nil)
 		if bytes.Compare(key, zaddOpts.Key) != 0 {
 			return nil, nil, ErrIndexKeyMismatch
@@ -485,11 +749,18 @@ func (t *Store) getReferenceVal(txn *badger.Txn, rOpts *schema.ReferenceOptions,
 	var key []byte
 	if rOpts.Index != nil {
 		if !skipPersistenceCheck {
+			if err = t.checkCommittedIndex(rOpts.Index); err != nil {
+				return nil, err
+			}
 			// convert to internal timestamp for itemAt, that returns the index
-			_, key, _, err = t.itemAt(rOpts.Index.Index + 1)
+			item, err := t.itemAt(rOpts.Index.Index + 1)
 			if err != nil {
 				return nil, mapError(err)
 			}
+			if item.Deleted {
+				return nil, ErrKeyNotFound
+			}
+			key = item.Key
 			if len(rOpts.Key) > 0 && bytes.Compare(key, rOpts.Key) != 0 {
 				return nil, ErrIndexKeyMismatch
 			}
@@ -505,6 +776,9 @@ func (t *Store) getReferenceVal(txn *badger.Txn, rOpts *schema.ReferenceOptions,
 		if err != nil {
 			return nil, mapError(err)
 		}
+		if isTombstone(i) {
+			return nil, ErrKeyNotFound
+		}
 		key = i.KeyCopy(nil)
 		if bytes.Compare(key, rOpts.Key) != 0 {
 			return nil, ErrIndexKeyMismatch
@@ -520,6 +794,18 @@ func (t *Store) getReferenceVal(txn *badger.Txn, rOpts *schema.ReferenceOptions,
 	return v, err
 }
 
+// checkCommittedIndex ensures that the provided index does not refer to a future entry.
+// The check is enforced only in strict append-only mode.
+func (t *Store) checkCommittedIndex(index *schema.Index) error {
+	if !t.strictAppendOnly || index == nil {
+		return nil
+	}
+	if index.Index >= atomic.LoadUint64(&t.tree.ts) {
+		return ErrIndexNotCommitted
+	}
+	return nil
+}
+
 // FlushToDisk flushes cached data from memory to disk
 func (t *Store) FlushToDisk() {
 	defer t.tree.Unlock()
@@ -544,6 +830,10 @@ func (t *Store) Dump(kvChan chan *pb.KVList) (err error) {
 	stream := t.db.NewStreamAt(t.tree.w)
 	stream.NumGo = 16
 	stream.LogPrefix = "Badger.Streaming"
+	// the boot epoch belongs to this store, restored elsewhere it would fence the writes of the restoring one
+	stream.ChooseKey = func(item *badger.Item) bool {
+		return !isBootEpochKey(item.Key())
+	}
 
 	stream.Send = func(list *pb.KVList) error {
 		kvChan <- list
@@ -555,7 +845,11 @@ func (t *Store) Dump(kvChan chan *pb.KVList) (err error) {
 }
 
 // Restore restores a database
+// Since restored entries carry their own indexes, restore is not allowed in strict append-only mode.
 func (t *Store) Restore(kvChan chan *pb.KVList) (i uint64, err error) {
+	if t.strictAppendOnly {
+		return 0, ErrRestoreNotAllowed
+	}
 	defer t.tree.Unlock()
 	t.tree.Lock()
 	ldr := t.db.NewKVLoader(16)
@@ -573,6 +867,7 @@ func (t *Store) Restore(kvChan chan *pb.KVList) (i uint64, err error) {
 				return i, err
 			}
 			t.tree.loadTreeState()
+			t.sequencer.reload(t.db)
 			return t.tree.ts, err
 		} else {
 			err = ldr.Finish()
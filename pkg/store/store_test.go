@@ -25,8 +25,11 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/codenotary/immudb/pkg/clock"
 	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/dgraph-io/badger/v2"
 	"github.com/dgraph-io/badger/v2/pb"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -126,6 +129,39 @@ func TestStore(t *testing.T) {
 	assert.True(t, st.HealthCheck())
 }
 
+func TestStoreCountEntriesAndLeaves(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	entries, leaves := st.CountEntriesAndLeaves()
+	assert.Equal(t, uint64(0), entries)
+	assert.Equal(t, uint64(0), leaves)
+
+	for n := uint64(0); n < 16; n++ {
+		// same key is overwritten in order to get many versions
+		_, err := st.Set(schema.KeyValue{Key: []byte(strconv.FormatUint(n%4, 10)), Value: []byte("value")})
+		assert.NoError(t, err)
+	}
+	_, err := st.Reference(&schema.ReferenceOptions{Reference: []byte("ref"), Key: []byte("1")})
+	assert.NoError(t, err)
+	idx, err := st.ZAdd(schema.ZAddOptions{Set: []byte("set"), Score: &schema.Score{Score: 1}, Key: []byte("2")})
+	assert.NoError(t, err)
+	st.tree.WaitUntil(idx.Index)
+
+	entries, leaves = st.CountEntriesAndLeaves()
+	assert.Equal(t, uint64(18), leaves)
+	assert.Equal(t, leaves, entries)
+
+	// an entry written bypassing the tree makes the counts diverge
+	txn := st.db.NewTransactionAt(math.MaxUint64, true)
+	assert.NoError(t, txn.SetEntry(badger.NewEntry([]byte("orphan"), WrapValueWithTS([]byte("value"), 1))))
+	assert.NoError(t, txn.CommitAt(1, nil))
+
+	entries, leaves = st.CountEntriesAndLeaves()
+	assert.Equal(t, uint64(18), leaves)
+	assert.Equal(t, uint64(19), entries)
+}
+
 func TestStoreMissingEntriesReplay(t *testing.T) {
 	dbDir := tmpDir()
 
@@ -259,7 +295,8 @@ func TestDump(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(lists))
-	assert.Equal(t, 18, len(lists[0].Kv), "All keys was retrieved")
+	// entries, tree nodes, commit times, last flushed leaf and tree checkpoint
+	assert.Equal(t, 25, len(lists[0].Kv), "All keys was retrieved")
 }
 
 func TestDumpEmptyDB(t *testing.T) {
@@ -346,7 +383,7 @@ func TestDumpAfterOpening(t *testing.T) {
 
 	require.NoError(t, err)
 	require.Equal(t, 1, len(lists))
-	require.Equal(t, 18, len(lists[0].Kv), "All keys was retrieved")
+	require.Equal(t, 25, len(lists[0].Kv), "All keys was retrieved")
 }
 
 func TestLargeDump(t *testing.T) {
@@ -672,6 +709,79 @@ func TestStore_HistoryInvalidKey(t *testing.T) {
 	assert.Error(t, err, ErrInvalidKey)
 }
 
+func TestStore_HistoryRanges(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1000, 0)
+	c := clock.Func(func() time.Time { return now })
+
+	slog := logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogDebug)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+	st, err := Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	// val1..val5 are committed at 1000..5000 and written at indexes 0, 2, 4, 6, 8
+	for i := 1; i <= 5; i++ {
+		now = time.Unix(int64(i*1000), 0)
+		_, err = st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(fmt.Sprintf("val%d", i))})
+		require.NoError(t, err)
+		_, err = st.Set(schema.KeyValue{Key: []byte(`other`), Value: []byte(`value`)})
+		require.NoError(t, err)
+	}
+
+	values := func(list *schema.ItemList) (vs []string) {
+		for _, item := range list.Items {
+			vs = append(vs, string(item.Value))
+		}
+		return
+	}
+
+	list, err := st.History(&schema.HistoryOptions{Key: []byte(`key`), FromIndex: 2, ToIndex: 6})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val4", "val3", "val2"}, values(list))
+
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), FromIndex: 3, Reverse: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val3", "val4", "val5"}, values(list))
+
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), Since: 2000, Until: 4000})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val3", "val2"}, values(list))
+
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), Since: 4000, Reverse: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val4", "val5"}, values(list))
+
+	// the filters apply to every page
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), ToIndex: 6, Until: 5000, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val4", "val3"}, values(list))
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), ToIndex: 6, Until: 5000, Limit: 2, Offset: list.Items[1].Index})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val2", "val1"}, values(list))
+
+	// latest first, the read starts below both the upper bound and the offset, which need not be indexes of the key
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), ToIndex: 7, Offset: 5, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val3", "val2"}, values(list))
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), ToIndex: 3, Offset: 8, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val2"}, values(list))
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val1"}, values(list))
+	list, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), Offset: 5, Limit: 1, Reverse: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"val4"}, values(list))
+
+	_, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), FromIndex: 6, ToIndex: 2})
+	assert.Equal(t, ErrInvalidHistoryRange, err)
+	_, err = st.History(&schema.HistoryOptions{Key: []byte(`key`), Since: 4000, Until: 2000})
+	assert.Equal(t, ErrInvalidHistoryRange, err)
+}
+
 func TestInsertionOrderIndex(t *testing.T) {
 	st, closer := makeStore()
 	defer closer()
@@ -928,6 +1038,7 @@ func BenchmarkStoreSet(b *testing.B) {
 	st, closer := makeStore()
 	defer closer()
 
+	b.ReportAllocs()
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
 		kv := schema.KeyValue{
@@ -1024,3 +1135,131 @@ Cras ut magna at tortor vehicula ultrices. Sed lacus metus, tristique sit amet d
 Nulla a dolor in nibh tincidunt blandit. Donec congue, nisl in dictum semper, nunc lectus accumsan dolor, eu consequat velit erat ac libero. Integer ultricies felis purus, vitae sagittis sapien malesuada a. Quisque sed pretium mi. In accumsan enim at urna suscipit ornare. Nunc rhoncus varius diam, nec finibus nunc congue vel. Sed risus urna, pellentesque ut tortor vel, semper lacinia massa. Sed molestie convallis tristique.
 Aenean porta vehicula turpis eget condimentum. Aenean finibus justo vel nisi vestibulum, id placerat leo luctus. Lorem ipsum dolor sit amet, consectetur adipiscing elit. Maecenas a risus et mauris luctus vehicula id vitae lectus. Sed molestie bibendum risus non pretium. Sed a posuere mauris, vitae ornare diam. Praesent ac quam egestas, molestie arcu nec, volutpat lacus. Nulla at sagittis mi. Integer id justo ante. Nulla et metus id mauris finibus volutpat eget sed nisi. Maecenas ac gravida lacus, id feugiat neque. Nullam auctor purus ut dolor euismod, nec congue ante placerat. Donec fermentum orci quis aliquam congue.
 Lorem ipsum dolor sit amet, consectetur adipiscing elit. Nullam tincidunt viverra orci eget ornare. Nam mattis nunc a gravida scelerisque. Phasellus ullamcorper tellus nec tincidunt rhoncus. Nunc ac risus orci. Ut bibendum pharetra neque eu semper. Pellentesque habitant morbi tristique senectus et netus et malesuada fames ac turpis egestas. Etiam convallis lectus non pharetra commodo.`)
+
+func TestStoreIndexTime(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1000, 0)
+	c := clock.Func(func() time.Time { return now })
+
+	slog := logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogDebug)
+	opts, badgerOpts := DefaultOptions(dir, slog)
+	st, err := Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+
+	_, err = st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)})
+	require.NoError(t, err)
+
+	// the clock is stepped back, committed entries must not go back in time
+	now = time.Unix(500, 0)
+	_, err = st.SetBatch(schema.KVList{KVs: []*schema.KeyValue{
+		{Key: []byte(`key1`), Value: []byte(`value1`)},
+		{Key: []byte(`key2`), Value: []byte(`value2`)},
+	}})
+	require.NoError(t, err)
+
+	now = time.Unix(2000, 0)
+	_, err = st.ExecAllOps(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`key3`), Value: []byte(`value3`)}}},
+	}})
+	require.NoError(t, err)
+
+	for index, expected := range []int64{1000, 1000, 1000, 2000} {
+		ts, err := st.IndexTime(schema.Index{Index: uint64(index)})
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(expected, 0).UnixNano(), ts.UnixNano(), "index=%d", index)
+	}
+	_, err = st.IndexTime(schema.Index{Index: 4})
+	assert.Equal(t, ErrIndexNotFound, err)
+	require.NoError(t, st.Close())
+
+	// the last commit time survives a restart
+	now = time.Unix(1500, 0)
+	st, err = Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	index, err := st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	ts, err := st.IndexTime(*index)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(2000, 0).UnixNano(), ts.UnixNano())
+}
+
+// BenchmarkStoreOpenAfterCrash measures the startup of a store which has not been closed cleanly,
+// with 100000 entries committed after the last tree checkpoint. Replaying the leaves instead of
+// reading and hashing again the entries brought it from about 1.5s to 0.45s. Since only the entries
+// after the last checkpoint are replayed, the startup time does not grow linearly with the size of the
+// store, see BenchmarkStoreOpenAfterCrashLargeStore.
+func BenchmarkStoreOpenAfterCrash(b *testing.B) {
+	benchmarkStoreOpenAfterCrash(b, 0)
+}
+
+// BenchmarkStoreOpenAfterCrashLargeStore is like BenchmarkStoreOpenAfterCrash, the 100000 entries following
+// the ones already checkpointed, 1000000 by default or IMMUDB_BENCH_ENTRIES. With 1000000 and 5000000 entries
+// the startup took about 1.1s and 1.5s: the entries replayed are bounded by the checkpoint interval, the tree
+// nodes read from the larger badger tables making the replay slower.
+func BenchmarkStoreOpenAfterCrashLargeStore(b *testing.B) {
+	entries := 1_000_000
+	if v, ok := os.LookupEnv("IMMUDB_BENCH_ENTRIES"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			b.Fatal(err)
+		}
+		entries = n
+	}
+	benchmarkStoreOpenAfterCrash(b, entries/1000*1000)
+}
+
+// benchmarkStoreOpenAfterCrash measures the startup of a store having checkpointed entries, followed by 100000
+// entries committed after the last tree checkpoint
+func benchmarkStoreOpenAfterCrash(b *testing.B, checkpointed int) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLoggerWithLevel("bm(immudb)", os.Stderr, logger.LogError))
+
+	set := func(st *Store, from, to int) {
+		for i := from; i < to; i += 1000 {
+			kvList := &schema.KVList{}
+			for j := 0; j < 1000; j++ {
+				key := []byte(strconv.Itoa(i + j))
+				kvList.KVs = append(kvList.KVs, &schema.KeyValue{Key: key, Value: key})
+			}
+			if _, err := st.SetBatch(*kvList); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	st, err := Open(opts, badgerOpts)
+	if err != nil {
+		b.Fatal(err)
+	}
+	set(st, 0, checkpointed)
+	// a clean close checkpoints the tree
+	st.Close()
+
+	st, err = Open(opts, badgerOpts)
+	if err != nil {
+		b.Fatal(err)
+	}
+	last := uint64(checkpointed + 100000 - 1)
+	set(st, checkpointed, checkpointed+100000)
+	st.tree.WaitUntil(last)
+	st.tree.close(false)
+	st.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		st, err := Open(opts, badgerOpts)
+		if err != nil {
+			b.Fatal(err)
+		}
+		st.tree.WaitUntil(last)
+		// closing compacts the tables of badger, which is not part of the startup
+		b.StopTimer()
+		st.tree.close(false)
+		st.Close()
+		b.StartTimer()
+	}
+}
@@ -0,0 +1,179 @@
+// +build storetest
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CommitScheduler controls when the entries committed to a store are added to its tree, reproducing deterministically
+// in tests the lag of the tree behind the committed entries. Committed entries are added to the tree right away
+// until Pause is called. Calls waiting for the tree, e.g. SafeSet or SafeGet, block while the entries they wait for are held.
+// It's only available in builds with the storetest tag.
+type CommitScheduler struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	tree   *treeStore
+	paused bool
+	held   []*treeStoreEntry
+}
+
+// NewCommitScheduler creates a scheduler to be set on a single store with WithCommitScheduler
+func NewCommitScheduler() *CommitScheduler {
+	s := &CommitScheduler{}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// WithCommitScheduler makes the store add the committed entries to its tree as allowed by s
+func (o Options) WithCommitScheduler(s *CommitScheduler) Options {
+	o.commitGate = s
+	return o
+}
+
+func (s *CommitScheduler) attach(t *treeStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tree = t
+}
+
+func (s *CommitScheduler) hold(entry *treeStoreEntry) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.paused {
+		return false
+	}
+	s.held = append(s.held, entry)
+	s.cond.Broadcast()
+	return true
+}
+
+func (s *CommitScheduler) releaseAll() {
+	s.Resume()
+}
+
+// Pause holds the entries committed from now on, until released by Step or Resume
+func (s *CommitScheduler) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = true
+}
+
+// Resume adds all the held entries to the tree and stops holding the committed ones
+func (s *CommitScheduler) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = false
+	s.release(len(s.held))
+}
+
+// Step adds to the tree the n held entries having the lowest indexes and returns the number of entries released.
+// The tree grows only up to the first index which is not committed yet.
+func (s *CommitScheduler) Step(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > len(s.held) {
+		n = len(s.held)
+	}
+	s.release(n)
+	return n
+}
+
+// release sends to the tree the n held entries having the lowest indexes, s.mu must be held
+func (s *CommitScheduler) release(n int) {
+	sort.Slice(s.held, func(i, j int) bool { return s.held[i].ts < s.held[j].ts })
+	for _, entry := range s.held[:n] {
+		s.tree.c <- entry
+	}
+	s.held = s.held[n:]
+}
+
+// Held returns the indexes of the held entries, in increasing order
+func (s *CommitScheduler) Held() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	indexes := make([]uint64, 0, len(s.held))
+	for _, entry := range s.held {
+		indexes = append(indexes, entry.ts-1)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes
+}
+
+// WaitHeld waits until at least n entries are held, e.g. after writes committed asynchronously
+func (s *CommitScheduler) WaitHeld(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.held) < n {
+		s.cond.Wait()
+	}
+}
+
+// TreeWidth returns the number of entries added to the tree, which lags behind the committed ones
+func (t *Store) TreeWidth() uint64 {
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+	return t.tree.w
+}
+
+// TestClock is a deterministic clock for tests, to be set with WithClock. Every reading returns the time
+// of the current generation and moves to the next one, generation g being at start + g*step.
+// Since the store reads the clock once per write, the commit time of every write is known in advance.
+// It's only available in builds with the storetest tag.
+type TestClock struct {
+	mu         sync.Mutex
+	start      time.Time
+	step       time.Duration
+	generation uint64
+}
+
+// NewTestClock creates a clock starting at start and advancing by step at every reading
+func NewTestClock(start time.Time, step time.Duration) *TestClock {
+	return &TestClock{start: start, step: step}
+}
+
+// Now returns the time of the current generation and moves to the next one
+func (c *TestClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.At(c.generation)
+	c.generation++
+	return now
+}
+
+// At returns the time of generation g
+func (c *TestClock) At(g uint64) time.Time {
+	return c.start.Add(time.Duration(g) * c.step)
+}
+
+// Generation returns the generation the next reading will return the time of
+func (c *TestClock) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Set moves the clock to generation g, a lower generation than the current one simulates a clock stepped back
+func (c *TestClock) Set(g uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation = g
+}
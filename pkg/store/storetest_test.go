@@ -0,0 +1,104 @@
+// +build storetest
+
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitScheduler(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	start := time.Date(2020, 11, 13, 0, 0, 0, 0, time.UTC)
+	clk := NewTestClock(start, time.Second)
+	scheduler := NewCommitScheduler()
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("storetest", os.Stderr))
+	st, err := Open(opts.WithClock(clk).WithCommitScheduler(scheduler), badgerOpts)
+	require.NoError(t, err)
+
+	_, err = st.Set(schema.KeyValue{Key: []byte(`key0`), Value: []byte(`val0`)})
+	require.NoError(t, err)
+	st.tree.WaitUntil(0)
+
+	scheduler.Pause()
+	for _, k := range []string{`key1`, `key2`} {
+		_, err = st.Set(schema.KeyValue{Key: []byte(k), Value: []byte(k)})
+		require.NoError(t, err)
+	}
+	_, err = st.Set(schema.KeyValue{Key: []byte(`key3`), Value: []byte(`key3`)}, WithAsyncCommit(true))
+	require.NoError(t, err)
+	scheduler.WaitHeld(3)
+	assert.Equal(t, []uint64{1, 2, 3}, scheduler.Held())
+	assert.Equal(t, uint64(1), st.TreeWidth())
+
+	// committed entries are readable while the tree lags behind
+	item, err := st.Get(schema.Key{Key: []byte(`key2`)})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), item.Index)
+	root, err := st.CurrentRoot()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), root.GetIndex())
+
+	assert.Equal(t, 1, scheduler.Step(1))
+	st.tree.WaitUntil(1)
+	assert.Equal(t, uint64(2), st.TreeWidth())
+	assert.Equal(t, []uint64{2, 3}, scheduler.Held())
+
+	scheduler.Resume()
+	st.tree.WaitUntil(3)
+	assert.Empty(t, scheduler.Held())
+	assert.Equal(t, 0, scheduler.Step(1))
+
+	// every write reads the clock once
+	assert.Equal(t, uint64(4), clk.Generation())
+	for i := uint64(0); i < 4; i++ {
+		at, err := st.IndexTime(schema.Index{Index: i})
+		require.NoError(t, err)
+		assert.Equal(t, clk.At(i), at.UTC())
+	}
+
+	// entries still held are added to the tree on close
+	scheduler.Pause()
+	_, err = st.Set(schema.KeyValue{Key: []byte(`key4`), Value: []byte(`key4`)})
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), st.TreeWidth())
+	require.NoError(t, st.Close())
+}
+
+func TestTestClock(t *testing.T) {
+	start := time.Unix(100, 0)
+	clk := NewTestClock(start, time.Millisecond)
+	assert.Equal(t, start, clk.Now())
+	assert.Equal(t, start.Add(time.Millisecond), clk.Now())
+	assert.Equal(t, uint64(2), clk.Generation())
+	clk.Set(0)
+	assert.Equal(t, start, clk.Now())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// Subscribe calls cb with the items written to the keys starting with prefix, in the order they are committed, until
+// ctx is done, the store is closed or cb returns an error, which is then returned. An empty prefix watches every key.
+// Deletions are notified as items flagged as deleted, while references and sorted set members are not notified, as
+// they don't change the value of any key.
+// Items are notified once added to the tree, so that their inclusion can be proven right away.
+func (t *Store) Subscribe(ctx context.Context, prefix []byte, cb func(item *schema.Item) error) error {
+	if cb == nil {
+		return ErrNilCallback
+	}
+	err := t.db.Subscribe(ctx, func(kvs *badger.KVList) error {
+		for _, kv := range kvs.Kv {
+			if _, reserved := ReservedNamespace(kv.Key); reserved || len(kv.Meta) == 0 ||
+				kv.Meta[0]&bitReferenceEntry == bitReferenceEntry {
+				continue
+			}
+			value, ts, err := unwrapValue(kv.Meta[0], kv.Value)
+			if err != nil {
+				return err
+			}
+			t.tree.WaitUntil(ts - 1)
+			err = cb(&schema.Item{
+				Key:     kv.Key,
+				Value:   value,
+				Index:   ts - 1,
+				Deleted: kv.Meta[0]&bitTombstoneEntry == bitTombstoneEntry,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}, prefix)
+	if err == ctx.Err() {
+		return nil
+	}
+	return mapError(err)
+}
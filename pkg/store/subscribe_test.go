@@ -0,0 +1,96 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	require.Equal(t, ErrNilCallback, st.Subscribe(context.TODO(), nil, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	items := make(chan *schema.Item, 100)
+	done := make(chan error)
+	go func() {
+		done <- st.Subscribe(ctx, []byte(`watch/`), func(item *schema.Item) error {
+			items <- item
+			return nil
+		})
+	}()
+
+	// the subscription is established asynchronously, so the first changes may be missed
+	for ready := false; !ready; {
+		_, err := st.Set(schema.KeyValue{Key: []byte(`watch/ready`), Value: []byte(`ready`)})
+		require.NoError(t, err)
+		select {
+		case <-items:
+			ready = true
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	value := bytes.Repeat([]byte(`value`), 100)
+	_, err := st.Set(schema.KeyValue{Key: []byte(`other`), Value: value})
+	require.NoError(t, err)
+	set, err := st.Set(schema.KeyValue{Key: []byte(`watch/key`), Value: value})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`watch/ref`), Key: []byte(`watch/key`)})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`watch/set`), Key: []byte(`watch/key`), Score: &schema.Score{Score: 1}})
+	require.NoError(t, err)
+	deleted, err := st.Delete(schema.Key{Key: []byte(`watch/key`)})
+	require.NoError(t, err)
+
+	next := func() *schema.Item {
+		for {
+			select {
+			case item := <-items:
+				if !bytes.Equal(item.Key, []byte(`watch/ready`)) {
+					return item
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no change notified")
+			}
+		}
+	}
+	item := next()
+	require.Equal(t, []byte(`watch/key`), item.Key)
+	require.Equal(t, value, item.Value)
+	require.Equal(t, set.Index, item.Index)
+	require.False(t, item.Deleted)
+	proof, err := st.InclusionProof(schema.Index{Index: item.Index})
+	require.NoError(t, err)
+	require.Equal(t, item.Hash(), proof.Leaf)
+
+	item = next()
+	require.Equal(t, []byte(`watch/key`), item.Key)
+	require.Equal(t, deleted.Index, item.Index)
+	require.True(t, item.Deleted)
+
+	cancel()
+	require.NoError(t, <-done)
+}
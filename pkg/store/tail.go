@@ -0,0 +1,161 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// EntryType is the kind of write an entry of the store has been appended by
+type EntryType uint8
+
+const (
+	// EntrySet is a key set to a value
+	EntrySet EntryType = iota + 1
+	// EntryReference is a reference set to a key
+	EntryReference
+	// EntryZAdd is a key added to a sorted set
+	EntryZAdd
+	// EntryDelete is a tombstone, deleting a key or a reference
+	EntryDelete
+	// EntryPruned is a key set to a value since pruned by the retention policy
+	EntryPruned
+)
+
+func (t EntryType) String() string {
+	switch t {
+	case EntrySet:
+		return "set"
+	case EntryReference:
+		return "reference"
+	case EntryZAdd:
+		return "zadd"
+	case EntryDelete:
+		return "delete"
+	case EntryPruned:
+		return "pruned"
+	}
+	return "unknown"
+}
+
+// TailEntry is an entry of the store streamed by Tail, decoded according to the write it has been appended by
+type TailEntry struct {
+	Type EntryType
+	// Item is the entry as returned by ByIndex, without value if pruned
+	Item *schema.Item
+	// Key is the key set or deleted, the key referenced by a reference or the key added to a sorted set
+	Key []byte
+	// Value is the value a key is set to
+	Value []byte
+	// Reference is the key of a reference
+	Reference []byte
+	// Set and Score are the sorted set a key is added to and its score
+	Set   []byte
+	Score float64
+	// AtIndex is the index of the entry of Key a reference or a sorted set member is bound to, nil if it's bound to
+	// the latest entry of Key
+	AtIndex *schema.Index
+}
+
+// tailPollInterval is how often Tail looks for new entries once it has caught up with the store
+var tailPollInterval = 10 * time.Millisecond
+
+// Tail calls cb with the entries of the store in index order, starting from index from, then with the entries
+// appended afterwards as they are committed, until ctx is done or cb returns an error, which is then returned.
+// It's the change data capture feed of the store: replaying the entries from index 0 rebuilds its content, and a
+// consumer can resume from the index following the last entry it has processed. ctx must be done before the store is
+// closed. The indexes of failed writes, which hold no entry, are skipped, while the entries pruned by the retention
+// policy are passed as EntryPruned, with their key and index only.
+func (t *Store) Tail(ctx context.Context, from uint64, cb func(e *TailEntry) error) error {
+	for index := from; ; index++ {
+		if t.waitForIndex(ctx, index) != nil {
+			return nil
+		}
+		item, meta, err := t.entryAt(index + 1)
+		if err == ErrKeyNotFound {
+			continue
+		}
+		var e *TailEntry
+		switch {
+		case err == ErrEntryPruned:
+			e = &TailEntry{Type: EntryPruned, Item: item, Key: item.Key}
+		case err != nil:
+			return err
+		default:
+			if e, err = decodeTailEntry(item, meta); err != nil {
+				return err
+			}
+		}
+		if err = cb(e); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForIndex waits until the entry at index is added to the tree, or ctx is done
+func (t *Store) waitForIndex(ctx context.Context, index uint64) error {
+	for {
+		t.tree.RLock()
+		w := t.tree.w
+		t.tree.RUnlock()
+		if w > index {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(tailPollInterval):
+		}
+	}
+}
+
+func decodeTailEntry(item *schema.Item, meta byte) (*TailEntry, error) {
+	e := &TailEntry{Item: item}
+	switch {
+	case item.Deleted:
+		e.Type, e.Key = EntryDelete, item.Key
+	case meta&bitReferenceEntry != bitReferenceEntry:
+		e.Type, e.Key, e.Value = EntrySet, item.Key, item.Value
+	default:
+		if len(item.Value) < 8+1 {
+			return nil, ErrCorruptedValue
+		}
+		key, flag, index := UnwrapZIndexReference(item.Value)
+		e.Key = key
+		if flag == byte(1) {
+			e.AtIndex = &schema.Index{Index: index}
+		}
+		if !bytes.HasPrefix(item.Key, _SetSeparator) {
+			e.Type, e.Reference = EntryReference, item.Key
+			break
+		}
+		// {separator}{set}{separator}{score}{key}{bit index presence flag}{index}
+		sl := len(_SetSeparator)
+		scoreAt := len(item.Key) - len(key) - 8 - 1 - 8
+		if scoreAt < sl+sl {
+			return nil, ErrCorruptedValue
+		}
+		e.Type = EntryZAdd
+		e.Set = append([]byte{}, item.Key[sl:scoreAt-sl]...)
+		e.Score = Bytes2float(item.Key[scoreAt : scoreAt+8])
+	}
+	return e, nil
+}
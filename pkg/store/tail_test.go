@@ -0,0 +1,151 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTail(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	set, err := st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`key`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`pinned`), Key: []byte(`key`), Index: set})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`key`), Score: &schema.Score{Score: 1.5}})
+	require.NoError(t, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`key`), Score: &schema.Score{Score: 2}, Index: set})
+	require.NoError(t, err)
+	_, err = st.Unreference(schema.Key{Key: []byte(`ref`)})
+	require.NoError(t, err)
+	last, err := st.Delete(schema.Key{Key: []byte(`key`)})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var entries []*TailEntry
+	require.NoError(t, st.Tail(ctx, 0, func(e *TailEntry) error {
+		entries = append(entries, e)
+		if e.Item.Index == last.Index {
+			cancel()
+		}
+		return nil
+	}))
+	require.Len(t, entries, 7)
+
+	require.Equal(t, EntrySet, entries[0].Type)
+	require.Equal(t, []byte(`key`), entries[0].Key)
+	require.Equal(t, []byte(`value`), entries[0].Value)
+
+	require.Equal(t, EntryReference, entries[1].Type)
+	require.Equal(t, []byte(`ref`), entries[1].Reference)
+	require.Equal(t, []byte(`key`), entries[1].Key)
+	require.Nil(t, entries[1].AtIndex)
+	require.Equal(t, EntryReference, entries[2].Type)
+	require.Equal(t, set.Index, entries[2].AtIndex.Index)
+
+	require.Equal(t, EntryZAdd, entries[3].Type)
+	require.Equal(t, []byte(`set`), entries[3].Set)
+	require.Equal(t, 1.5, entries[3].Score)
+	require.Equal(t, []byte(`key`), entries[3].Key)
+	require.Nil(t, entries[3].AtIndex)
+	require.Equal(t, float64(2), entries[4].Score)
+	require.Equal(t, set.Index, entries[4].AtIndex.Index)
+
+	require.Equal(t, EntryDelete, entries[5].Type)
+	require.Equal(t, []byte(`ref`), entries[5].Key)
+	require.Equal(t, EntryDelete, entries[6].Type)
+	require.Equal(t, []byte(`key`), entries[6].Key)
+	for i, e := range entries {
+		require.Equal(t, uint64(i), e.Item.Index)
+	}
+	require.Equal(t, "zadd", EntryZAdd.String())
+
+	// following the entries appended once caught up, until cb fails
+	stop := errors.New("stop")
+	done := make(chan error)
+	followed := make(chan *TailEntry, 10)
+	go func() {
+		done <- st.Tail(context.Background(), last.Index, func(e *TailEntry) error {
+			followed <- e
+			if e.Type == EntrySet {
+				return stop
+			}
+			return nil
+		})
+	}()
+	require.Equal(t, last.Index, (<-followed).Item.Index)
+	appended, err := st.Set(schema.KeyValue{Key: []byte(`new`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	select {
+	case e := <-followed:
+		require.Equal(t, appended.Index, e.Item.Index)
+	case <-time.After(5 * time.Second):
+		t.Fatal("appended entry not tailed")
+	}
+	require.Equal(t, stop, <-done)
+}
+
+func TestTailPruned(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	for _, v := range []string{`v1`, `v2`} {
+		_, err := st.Set(schema.KeyValue{Key: []byte(`key`), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	_, err := st.EnforceRetention(RetentionPolicy{MaxRevisions: 1}, 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var entries []*TailEntry
+	require.NoError(t, st.Tail(ctx, 0, func(e *TailEntry) error {
+		entries = append(entries, e)
+		if len(entries) == 2 {
+			cancel()
+		}
+		return nil
+	}))
+	require.Equal(t, EntryPruned, entries[0].Type)
+	require.Equal(t, []byte(`key`), entries[0].Key)
+	require.Equal(t, &schema.Item{Key: []byte(`key`), Index: 0}, entries[0].Item)
+	require.Equal(t, EntrySet, entries[1].Type)
+	require.Equal(t, []byte(`v2`), entries[1].Value)
+
+	// pruned entries are still proven by their leaf
+	proof, err := st.ProofAt(0, nil)
+	require.NoError(t, err)
+	require.True(t, proof.Verify(proof.Leaf, schema.Root{}))
+	safeItem, err := st.BySafeIndex(schema.SafeIndexOptions{Index: 1})
+	require.NoError(t, err)
+	proof, err = st.ProofAt(1, nil)
+	require.NoError(t, err)
+	require.Equal(t, safeItem.Proof, proof)
+	_, err = st.ProofAt(2, nil)
+	require.Equal(t, ErrIndexNotFound, err)
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TieringFileName is the name of the file listing the value log segments moved to cold storage, written into the
+// data directory of the store
+const TieringFileName = "immudb.tiering"
+
+// minKeptSegments are the latest value log segments never moved, the last one being written and the one before it
+// possibly replayed on open
+const minKeptSegments = 2
+
+// TieringOptions tells which value log segments are moved to cold storage, and where
+type TieringOptions struct {
+	// ColdDir is where the segments are moved, e.g. a cheaper disk or an object store mounted as a file system
+	ColdDir string
+	// MinAge is how long after their last write segments are moved
+	MinAge time.Duration
+	// KeepSegments is the number of the latest segments which are never moved, at least 2
+	KeepSegments int
+	// RecallTTL is how long the segments recalled by RecallSegments stay local before they are moved again
+	RecallTTL time.Duration
+}
+
+// TieredSegment is a value log segment moved to cold storage. The segment is replaced by a link to its copy in cold
+// storage, so that it's read from there, transparently, once the store is opened again. The segment is read in place:
+// nothing is fetched back on access nor cached locally, beyond the page cache of the operating system, the segments
+// read often are to be recalled, see RecallSegments.
+type TieredSegment struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	Hash       string    `json:"hash"`
+	TieredAt   time.Time `json:"tiered_at"`
+	RecalledAt time.Time `json:"recalled_at,omitempty"`
+}
+
+// Local tells if the segment has been recalled, and it's read from local disk
+func (s TieredSegment) Local() bool {
+	return !s.RecalledAt.IsZero()
+}
+
+// ReadTieredSegments returns the segments of the data directory dir which have been moved to cold storage, by name
+func ReadTieredSegments(dir string) (map[string]TieredSegment, error) {
+	segments := make(map[string]TieredSegment)
+	raw, err := ioutil.ReadFile(filepath.Join(dir, TieringFileName))
+	if os.IsNotExist(err) {
+		return segments, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(raw, &segments)
+	}
+	return segments, err
+}
+
+// writeTieredSegments atomically writes the list of the tiered segments into the data directory dir
+func writeTieredSegments(dir string, segments map[string]TieredSegment) error {
+	raw, err := json.MarshalIndent(segments, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, TieringFileName)
+	if err = ioutil.WriteFile(path+".tmp", raw, 0644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// checkTieredSegments returns ErrSegmentUnavailable if a segment of dir moved to cold storage can't be read, as the
+// underlying storage would fail to open with a less helpful error
+func checkTieredSegments(dir string) error {
+	segments, err := ReadTieredSegments(dir)
+	if err != nil {
+		return err
+	}
+	for name, s := range segments {
+		if s.Local() {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			// the link has been removed along with the segment
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("%w: %s has been moved to %s (%v): make the cold storage available, "+
+				"or copy the segment back in place", ErrSegmentUnavailable, name, s.Path, err)
+		}
+	}
+	return nil
+}
+
+// TierSegments moves the value log segments the options tell to cold storage, and returns them. Every segment is
+// copied and verified before it's replaced by a link to the copy: the running store keeps reading the local segment
+// until it's closed, the disk space being freed then.
+func (t *Store) TierSegments(opts TieringOptions) ([]TieredSegment, error) {
+	if t.badgerOpts.InMemory || t.badgerOpts.ValueDir == "" {
+		return nil, nil
+	}
+	return tierSegments(t.badgerOpts.ValueDir, opts, t.tree.clock.Now())
+}
+
+// ReleaseSegments deletes from cold storage the segments deleted by the value log garbage collection, which only
+// removes their links, or their local copies if they have been recalled, and returns them
+func (t *Store) ReleaseSegments() ([]TieredSegment, error) {
+	if t.badgerOpts.InMemory || t.badgerOpts.ValueDir == "" {
+		return nil, nil
+	}
+	return releaseSegments(t.badgerOpts.ValueDir)
+}
+
+func releaseSegments(dir string) ([]TieredSegment, error) {
+	segments, err := ReadTieredSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range segments {
+		if _, err := os.Lstat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var released []TieredSegment
+	for _, name := range names {
+		s := segments[name]
+		// the segment is unlisted first, so that a crash never leaves a listed segment without its copy behind
+		delete(segments, name)
+		if err = writeTieredSegments(dir, segments); err != nil {
+			return released, err
+		}
+		if err = os.Remove(s.Path); err != nil && !os.IsNotExist(err) {
+			return released, err
+		}
+		released = append(released, s)
+	}
+	return released, nil
+}
+
+func tierSegments(dir string, opts TieringOptions, now time.Time) ([]TieredSegment, error) {
+	if opts.ColdDir == "" {
+		return nil, fmt.Errorf("no cold storage directory set")
+	}
+	if opts.KeepSegments < minKeptSegments {
+		opts.KeepSegments = minKeptSegments
+	}
+	coldDir, err := filepath.Abs(opts.ColdDir)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(coldDir, 0755); err != nil {
+		return nil, err
+	}
+	segments, err := ReadTieredSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	names, err := valueLogSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) <= opts.KeepSegments {
+		return nil, nil
+	}
+
+	var tiered []TieredSegment
+	for _, name := range names[:len(names)-opts.KeepSegments] {
+		path := filepath.Join(dir, name)
+		fi, err := os.Lstat(path)
+		if err != nil {
+			return tiered, err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 || now.Sub(fi.ModTime()) < opts.MinAge {
+			continue
+		}
+		s, ok := segments[name]
+		if ok && s.Local() && now.Sub(s.RecalledAt) < opts.RecallTTL {
+			continue
+		}
+
+		hash, err := fileHash(path)
+		if err != nil {
+			return tiered, err
+		}
+		s = TieredSegment{Name: name, Path: filepath.Join(coldDir, name), Size: fi.Size(), Hash: hash, TieredAt: now}
+		// a copy left by a previous move, e.g. of a recalled segment, is reused
+		if coldHash, err := fileHash(s.Path); err != nil || coldHash != hash {
+			if err = copySegment(path, s.Path, hash); err != nil {
+				return tiered, fmt.Errorf("moving segment %s to %s: %v", name, coldDir, err)
+			}
+		}
+		// the list is written before the segment is replaced, so that a crash never leaves an unlisted link behind
+		segments[name] = s
+		if err = writeTieredSegments(dir, segments); err != nil {
+			return tiered, err
+		}
+		if err = os.Symlink(s.Path, path+".tier"); err != nil {
+			return tiered, err
+		}
+		if err = os.Rename(path+".tier", path); err != nil {
+			return tiered, err
+		}
+		tiered = append(tiered, s)
+	}
+	return tiered, nil
+}
+
+// RecallSegments copies back to the data directory dir the segments moved to cold storage named in names, or all of
+// them if names is empty, and returns them. The copies in cold storage are kept, so that the segments are moved
+// again at no cost once RecallTTL elapsed. The store must not be open.
+func RecallSegments(dir string, names ...string) ([]TieredSegment, error) {
+	segments, err := ReadTieredSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		for name, s := range segments {
+			if !s.Local() {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	var recalled []TieredSegment
+	for _, name := range names {
+		s, ok := segments[name]
+		if !ok {
+			return recalled, fmt.Errorf("segment %s has not been moved to cold storage", name)
+		}
+		if s.Local() {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if err = copySegment(s.Path, path+".recall", s.Hash); err != nil {
+			return recalled, fmt.Errorf("%w: recalling %s from %s: %v", ErrSegmentUnavailable, name, s.Path, err)
+		}
+		if err = os.Rename(path+".recall", path); err != nil {
+			return recalled, err
+		}
+		s.RecalledAt = time.Now()
+		segments[name] = s
+		if err = writeTieredSegments(dir, segments); err != nil {
+			return recalled, err
+		}
+		recalled = append(recalled, s)
+	}
+	return recalled, nil
+}
+
+// valueLogSegments returns the names of the value log segments of dir, in write order
+func valueLogSegments(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var fids []uint64
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".vlog") {
+			continue
+		}
+		fid, err := strconv.ParseUint(strings.TrimSuffix(e.Name(), ".vlog"), 10, 32)
+		if err != nil {
+			continue
+		}
+		fids = append(fids, fid)
+	}
+	sort.Slice(fids, func(i, j int) bool { return fids[i] < fids[j] })
+	names := make([]string, len(fids))
+	for i, fid := range fids {
+		names[i] = fmt.Sprintf("%06d.vlog", fid)
+	}
+	return names, nil
+}
+
+// copySegment copies src to dst through a temporary file, verifying that the copy has the expected hash
+func copySegment(src, dst, hash string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(out, h), in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && hex.EncodeToString(h.Sum(nil)) != hash {
+		err = fmt.Errorf("the copy of %s doesn't match its hash", src)
+	}
+	if err != nil {
+		os.Remove(dst + ".tmp")
+		return err
+	}
+	return os.Rename(dst+".tmp", dst)
+}
+
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
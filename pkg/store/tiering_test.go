@@ -0,0 +1,163 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTierSegments(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+	coldDir := tmpDir()
+	defer os.RemoveAll(coldDir)
+
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	badgerOpts = badgerOpts.WithValueLogFileSize(1 << 20).WithValueThreshold(1024)
+	st, err := Open(opts, badgerOpts)
+	require.NoError(t, err)
+
+	value := bytes.Repeat([]byte{'v'}, 64*1024)
+	var indexes []*schema.Index
+	for i := 0; i < 80; i++ {
+		index, err := st.Set(schema.KeyValue{Key: []byte{'k', byte(i)}, Value: value})
+		require.NoError(t, err)
+		indexes = append(indexes, index)
+	}
+	names, err := valueLogSegments(dir)
+	require.NoError(t, err)
+	require.True(t, len(names) > 3, "%d segments", len(names))
+
+	tiering := TieringOptions{ColdDir: coldDir, KeepSegments: 1, RecallTTL: time.Hour}
+	tiered, err := st.TierSegments(TieringOptions{ColdDir: coldDir, MinAge: time.Hour})
+	require.NoError(t, err)
+	require.Empty(t, tiered)
+	tiered, err = st.TierSegments(tiering)
+	require.NoError(t, err)
+	require.Len(t, tiered, len(names)-minKeptSegments)
+	again, err := st.TierSegments(tiering)
+	require.NoError(t, err)
+	require.Empty(t, again)
+
+	// the running store keeps reading the segments it opened
+	item, err := st.ByIndex(*indexes[0])
+	require.NoError(t, err)
+	require.Equal(t, value, item.Value)
+	require.NoError(t, st.Close())
+
+	for _, name := range names[:len(names)-minKeptSegments] {
+		fi, err := os.Lstat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.True(t, fi.Mode()&os.ModeSymlink != 0)
+	}
+	segments, err := ReadTieredSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, segments, len(tiered))
+
+	// the moved segments are read from cold storage
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	for _, index := range indexes {
+		item, err := st.ByIndex(*index)
+		require.NoError(t, err)
+		require.Equal(t, value, item.Value)
+	}
+	require.NoError(t, st.Close())
+
+	require.NoError(t, os.Rename(coldDir, coldDir+".offline"))
+	_, err = Open(opts, badgerOpts)
+	require.True(t, errors.Is(err, ErrSegmentUnavailable), "%v", err)
+	_, err = RecallSegments(dir)
+	require.True(t, errors.Is(err, ErrSegmentUnavailable), "%v", err)
+	require.NoError(t, os.Rename(coldDir+".offline", coldDir))
+
+	_, err = RecallSegments(dir, "999999.vlog")
+	require.Error(t, err)
+	recalled, err := RecallSegments(dir, tiered[0].Name)
+	require.NoError(t, err)
+	require.Len(t, recalled, 1)
+	require.True(t, recalled[0].Local())
+	recalled, err = RecallSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, recalled, len(tiered)-1)
+	for _, name := range names {
+		fi, err := os.Lstat(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.True(t, fi.Mode().IsRegular())
+	}
+
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	item, err = st.ByIndex(*indexes[0])
+	require.NoError(t, err)
+	require.Equal(t, value, item.Value)
+
+	// recalled segments stay local until RecallTTL elapsed
+	tiered, err = st.TierSegments(tiering)
+	require.NoError(t, err)
+	require.Empty(t, tiered)
+	tiering.RecallTTL = 0
+	tiered, err = st.TierSegments(tiering)
+	require.NoError(t, err)
+	require.Len(t, tiered, len(names)-minKeptSegments)
+}
+
+func TestReleaseSegments(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+	coldDir := tmpDir()
+	defer os.RemoveAll(coldDir)
+
+	for fid := 0; fid < 4; fid++ {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("%06d.vlog", fid)), []byte{byte(fid)}, 0644))
+	}
+	tiered, err := tierSegments(dir, TieringOptions{ColdDir: coldDir}, time.Now())
+	require.NoError(t, err)
+	require.Len(t, tiered, 2)
+	released, err := releaseSegments(dir)
+	require.NoError(t, err)
+	require.Empty(t, released)
+
+	// the garbage collection of the value log removes the link only, or the local copy of a recalled segment
+	_, err = RecallSegments(dir, tiered[1].Name)
+	require.NoError(t, err)
+	for _, s := range tiered {
+		require.NoError(t, os.Remove(filepath.Join(dir, s.Name)))
+	}
+	released, err = releaseSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, released, 2)
+	for _, s := range tiered {
+		_, err = os.Stat(s.Path)
+		require.True(t, os.IsNotExist(err))
+	}
+	segments, err := ReadTieredSegments(dir)
+	require.NoError(t, err)
+	require.Empty(t, segments)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/merkletree"
+)
+
+// GetAt fetches the entry the specified key had at the specified index of the tree, resolving references as by Get,
+// together with its inclusion proof against the root the tree had at that index. If a root index is provided, the
+// consistency proof linking the two roots, in whichever order they come, is returned as well.
+func (t *Store) GetAt(options schema.GetAtOptions) (*schema.HistoricalItem, error) {
+	if err := checkKey(options.Key); err != nil {
+		return nil, err
+	}
+	if options.Index >= atomic.LoadUint64(&t.tree.ts) {
+		return nil, ErrIndexNotFound
+	}
+	return t.historicalItem(options.Key, options.Index, options.RootIndex)
+}
+
+// GetAsOf is like GetAt, at the last index committed at or before the specified time.
+// Entries written by versions not recording commit times are deemed committed before any other.
+func (t *Store) GetAsOf(options schema.GetAsOfOptions) (*schema.HistoricalItem, error) {
+	if err := checkKey(options.Key); err != nil {
+		return nil, err
+	}
+	at, err := t.indexAsOf(options.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return t.historicalItem(options.Key, at, options.RootIndex)
+}
+
+// indexAsOf returns the last index included into the tree having a commit time not after ts, in unix nanoseconds.
+// Commit times never decrease, so the index is searched by bisection.
+func (t *Store) indexAsOf(ts int64) (uint64, error) {
+	t.tree.RLock()
+	w := t.tree.w
+	t.tree.RUnlock()
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+
+	var err error
+	n := sort.Search(int(w), func(i int) bool {
+		if err != nil {
+			return true
+		}
+		it, e := indexTime(txn, uint64(i))
+		if e == ErrIndexNotFound {
+			return false
+		}
+		if e != nil {
+			err = e
+			return true
+		}
+		return it > ts
+	})
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, ErrIndexNotFound
+	}
+	return uint64(n - 1), nil
+}
+
+// historicalItem reads key as it was at index at and proves it against the root of the tree at that index
+func (t *Store) historicalItem(key []byte, at uint64, rootIndex *schema.Index) (*schema.HistoricalItem, error) {
+	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), rootIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// the version of the entry at index is index+1, so reading at it hides the following entries
+	txn := t.db.NewTransactionAt(at+1, false)
+	defer txn.Discard()
+
+	item, err := t.getResolved(txn, key)
+	if err != nil {
+		return nil, err
+	}
+
+	t.tree.WaitUntil(at)
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+
+	if prevRootIdx >= t.tree.w {
+		return nil, ErrInvalidRootIndex
+	}
+
+	root := rootAt(t.tree, at)
+	historical := &schema.HistoricalItem{
+		Item: item,
+		Proof: &schema.InclusionProof{
+			At:    at,
+			Index: item.Index,
+			Root:  root[:],
+			Leaf:  item.Hash(),
+			Path:  merkletree.InclusionProof(t.tree, at, item.Index).ToSlice(),
+		},
+	}
+
+	switch {
+	case prevRootIdx == 0 || prevRootIdx == at:
+	case prevRootIdx < at:
+		prevRoot := rootAt(t.tree, prevRootIdx)
+		historical.ConsistencyProof = &schema.ConsistencyProof{
+			First:      prevRootIdx,
+			Second:     at,
+			FirstRoot:  prevRoot[:],
+			SecondRoot: root[:],
+			Path:       merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+		}
+	default:
+		prevRoot := rootAt(t.tree, prevRootIdx)
+		historical.ConsistencyProof = &schema.ConsistencyProof{
+			First:      at,
+			Second:     prevRootIdx,
+			FirstRoot:  root[:],
+			SecondRoot: prevRoot[:],
+			Path:       merkletree.ConsistencyProof(t.tree, prevRootIdx, at).ToSlice(),
+		}
+	}
+
+	return historical, nil
+}
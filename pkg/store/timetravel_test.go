@@ -0,0 +1,118 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/clock"
+	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAtAndGetAsOf(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+
+	now := time.Unix(1000, 0)
+	c := clock.Func(func() time.Time { return now })
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	st, err := Open(opts.WithClock(c), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+
+	// each entry is committed 10 seconds after the previous one, from 1000 to 1050
+	write := func(f func() (*schema.Index, error)) {
+		_, err := f()
+		require.NoError(t, err)
+		now = now.Add(10 * time.Second)
+	}
+	set := func(key, value string) func() (*schema.Index, error) {
+		return func() (*schema.Index, error) {
+			return st.Set(schema.KeyValue{Key: []byte(key), Value: []byte(value)})
+		}
+	}
+	write(set(`key`, `v1`))
+	write(set(`other`, `x`))
+	write(set(`key`, `v2`))
+	write(func() (*schema.Index, error) { return st.Delete(schema.Key{Key: []byte(`key`)}) })
+	write(set(`key`, `v3`))
+	write(set(`other`, `y`))
+	st.tree.WaitUntil(5)
+
+	current, err := st.CurrentRoot()
+	require.NoError(t, err)
+
+	item, err := st.GetAt(schema.GetAtOptions{Key: []byte(`key`), Index: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v1`), item.Item.Value)
+	assert.Equal(t, uint64(0), item.Item.Index)
+	assert.Equal(t, uint64(1), item.Proof.At)
+	assert.Nil(t, item.ConsistencyProof)
+	assert.True(t, item.Verify(schema.Root{}))
+	older := &schema.Root{Payload: &schema.RootIndex{Index: 1, Root: item.Proof.Root}}
+
+	for index, value := range map[uint64]string{0: `v1`, 2: `v2`, 4: `v3`, 5: `v3`} {
+		item, err = st.GetAt(schema.GetAtOptions{Key: []byte(`key`), Index: index, RootIndex: &schema.Index{Index: 5}})
+		require.NoError(t, err)
+		assert.Equal(t, []byte(value), item.Item.Value)
+		assert.Equal(t, index, item.Proof.At)
+		assert.True(t, item.Verify(*current), "index %d", index)
+		assert.False(t, item.Verify(*older), "index %d", index)
+	}
+
+	// the root known by the client may come before the historical one
+	item, err = st.GetAt(schema.GetAtOptions{Key: []byte(`key`), Index: 4, RootIndex: &schema.Index{Index: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), item.ConsistencyProof.First)
+	assert.True(t, item.Verify(*older))
+	assert.False(t, item.Verify(*current))
+
+	item.Item.Value = []byte(`tampered`)
+	assert.False(t, item.Verify(*older))
+
+	_, err = st.GetAt(schema.GetAtOptions{Key: []byte(`key`), Index: 3})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.GetAt(schema.GetAtOptions{Key: []byte(`other`), Index: 0})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.GetAt(schema.GetAtOptions{Key: []byte(`key`), Index: 6})
+	assert.Equal(t, ErrIndexNotFound, err)
+	_, err = st.GetAt(schema.GetAtOptions{Key: []byte(`key`), Index: 2, RootIndex: &schema.Index{Index: 6}})
+	assert.Equal(t, ErrInvalidRootIndex, err)
+
+	item, err = st.GetAsOf(schema.GetAsOfOptions{Key: []byte(`key`), Timestamp: time.Unix(1025, 0).UnixNano()})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`v2`), item.Item.Value)
+	assert.Equal(t, uint64(2), item.Proof.At)
+
+	item, err = st.GetAsOf(schema.GetAsOfOptions{Key: []byte(`other`), Timestamp: time.Unix(1050, 0).UnixNano(),
+		RootIndex: &schema.Index{Index: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`y`), item.Item.Value)
+	assert.Equal(t, uint64(5), item.Proof.At)
+	assert.Equal(t, current.GetRoot(), item.Proof.Root)
+	assert.True(t, item.Verify(*older))
+
+	_, err = st.GetAsOf(schema.GetAsOfOptions{Key: []byte(`key`), Timestamp: time.Unix(1030, 0).UnixNano()})
+	assert.Equal(t, ErrKeyNotFound, err)
+	_, err = st.GetAsOf(schema.GetAsOfOptions{Key: []byte(`key`), Timestamp: time.Unix(999, 0).UnixNano()})
+	assert.Equal(t, ErrIndexNotFound, err)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"math"
+
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// bitTombstoneEntry flags the records marking their key as deleted. They have no value and their leaf is the
+// tombstone digest of the key, so that they can't be mistaken for entries, see api.TombstoneDigest.
+const bitTombstoneEntry = byte(8)
+
+// Delete marks key as deleted by appending a tombstone for it: Get, SafeGet and Scan don't return the key anymore,
+// while History lists the tombstone, which can be read and verified by index as any other entry.
+// The key can be set again afterwards. ErrKeyNotFound is returned if the key doesn't exist or is already deleted.
+func (t *Store) Delete(key schema.Key, options ...WriteOption) (index *schema.Index, err error) {
+	if err = checkKey(key.Key); err != nil {
+		return nil, err
+	}
+	return t.tombstone(key.Key, t.checkDeleted, options...)
+}
+
+// tombstone appends a tombstone for key, provided that check accepts its current entry
+func (t *Store) tombstone(key []byte, check func(i *badger.Item) error, options ...WriteOption) (index *schema.Index, err error) {
+	opts := makeWriteOptions(options...)
+	release, err := t.frozen.guard(key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	txn := t.db.NewTransactionAt(math.MaxUint64, true)
+	defer txn.Discard()
+
+	if err = t.fence(); err != nil {
+		return nil, err
+	}
+
+	i, err := txn.Get(key)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err = check(i); err != nil {
+		return nil, err
+	}
+
+	tsEntry := t.tree.NewTombstoneEntry(key)
+
+	if err = txn.SetEntry(&badger.Entry{
+		Key:      key,
+		Value:    wrapValue(nil, tsEntry.ts),
+		UserMeta: bitChecksummedEntry | bitTombstoneEntry,
+	}); err != nil {
+		t.tree.Discard(tsEntry)
+		return nil, mapError(err)
+	}
+
+	index = &schema.Index{
+		Index: tsEntry.ts - 1,
+	}
+
+	if err = setLeafEntry(txn, tsEntry); err != nil {
+		t.tree.Discard(tsEntry)
+		return nil, mapError(err)
+	}
+
+	seq, sequenced, err := t.sequencer.sequence(txn, index.Index, index.Index)
+	if err != nil {
+		t.tree.Discard(tsEntry)
+		return nil, mapError(err)
+	}
+	index.Sequence = seq
+
+	cb := func(err error) {
+		sequenced(err)
+		if err == nil {
+			t.tree.Commit(tsEntry)
+		} else {
+			t.tree.Discard(tsEntry)
+		}
+		if opts.asyncCommit {
+			t.wg.Done()
+		}
+	}
+
+	if opts.asyncCommit {
+		t.wg.Add(1)
+		err = mapError(txn.CommitAt(tsEntry.ts, cb)) // cb will be executed in a new goroutine
+	} else {
+		err = mapError(txn.CommitAt(tsEntry.ts, nil))
+		cb(err)
+	}
+
+	return
+}
+
+// isTombstone tells if the entry read as i marks its key as deleted
+func isTombstone(i *badger.Item) bool {
+	return i.UserMeta()&bitTombstoneEntry == bitTombstoneEntry
+}
+
+// deleted tells if the key of the entry read as i can't be read anymore, the entry being a tombstone or expired
+func (t *Store) deleted(i *badger.Item) (bool, error) {
+	if isTombstone(i) {
+		return true, nil
+	}
+	return t.expired(i)
+}
+
+// checkDeleted returns ErrKeyNotFound if the key of the entry read as i can't be read anymore
+func (t *Store) checkDeleted(i *badger.Item) error {
+	deleted, err := t.deleted(i)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return ErrKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelete(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	_, err := st.Set(schema.KeyValue{Key: []byte(`personal`), Value: []byte(`v1`)})
+	require.NoError(t, err)
+	_, err = st.Set(schema.KeyValue{Key: []byte(`other`), Value: []byte(`value`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref`), Key: []byte(`personal`)})
+	require.NoError(t, err)
+
+	_, err = st.Delete(schema.Key{Key: []byte(`missing`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Delete(schema.Key{Key: []byte{tsPrefix}})
+	require.Equal(t, ErrInvalidKey, err)
+
+	deleted, err := st.Delete(schema.Key{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	st.tree.WaitUntil(deleted.Index)
+
+	_, err = st.Delete(schema.Key{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Get(schema.Key{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Get(schema.Key{Key: []byte(`ref`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.SafeGet(schema.SafeGetOptions{Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`ref2`), Key: []byte(`personal`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.ZAdd(schema.ZAddOptions{Set: []byte(`set`), Key: []byte(`personal`), Score: &schema.Score{Score: 1}})
+	require.Equal(t, ErrKeyNotFound, err)
+
+	list, err := st.Scan(schema.ScanOptions{Prefix: []byte(`p`)})
+	require.NoError(t, err)
+	require.Empty(t, list.Items)
+	list, err = st.Scan(schema.ScanOptions{Prefix: []byte(`o`)})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+
+	// the tombstone is listed in the history and can be read and verified by index
+	history, err := st.History(&schema.HistoryOptions{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Len(t, history.Items, 2)
+	require.True(t, history.Items[0].Deleted)
+	require.Equal(t, deleted.Index, history.Items[0].Index)
+	require.Empty(t, history.Items[0].Value)
+	require.False(t, history.Items[1].Deleted)
+
+	item, err := st.ByIndex(schema.Index{Index: deleted.Index})
+	require.NoError(t, err)
+	require.True(t, item.Deleted)
+	require.Equal(t, []byte(`personal`), item.Key)
+	safeItem, err := st.BySafeIndex(schema.SafeIndexOptions{Index: deleted.Index})
+	require.NoError(t, err)
+	require.True(t, safeItem.Item.Deleted)
+	leaf := api.TombstoneDigest(deleted.Index, []byte(`personal`))
+	require.Equal(t, leaf[:], safeItem.Proof.Leaf)
+	require.Equal(t, safeItem.Item.Hash(), safeItem.Proof.Leaf)
+	require.True(t, safeItem.Proof.Verify(safeItem.Item.Hash(), schema.Root{}))
+
+	// the key can be set again
+	_, err = st.Set(schema.KeyValue{Key: []byte(`personal`), Value: []byte(`v2`)})
+	require.NoError(t, err)
+	item, err = st.Get(schema.Key{Key: []byte(`personal`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`v2`), item.Value)
+	require.False(t, item.Deleted)
+}
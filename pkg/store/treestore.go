@@ -28,6 +28,7 @@ import (
 	"unsafe"
 
 	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/clock"
 
 	"github.com/codenotary/immudb/pkg/logger"
 
@@ -46,12 +47,35 @@ const bitTreeEntry = byte(255)
 
 const lastFlushedMetaKey = "IMMUDB.METADATA.LAST_FLUSHED_LEAF"
 
+// treeCheckpointMetaKey holds the checkpoint written with the last flush of the tree, see encodeTreeCheckpoint
+const treeCheckpointMetaKey = "IMMUDB.METADATA.TREE_CHECKPOINT"
+
 func isReservedKey(key []byte) bool {
-	return len(key) > 0 && (key[0] == tsPrefix || bytes.Equal(key, []byte(lastFlushedMetaKey)))
+	return len(key) > 0 && (key[0] == tsPrefix ||
+		bytes.Equal(key, []byte(lastFlushedMetaKey)) ||
+		bytes.Equal(key, []byte(treeCheckpointMetaKey)))
+}
+
+// timeLayer is the layer of the keys holding the commit time of the leaves, it's out of the range of the tree layers
+const timeLayer = uint8(255)
+
+// timeKey returns the key of the commit time of the leaf at index
+func timeKey(index uint64) []byte {
+	return treeKey(timeLayer, index)
+}
+
+func isTimeKey(key []byte) bool {
+	return len(key) == 1+1+8 && key[0] == tsPrefix && key[1] == timeLayer
 }
 
 func treeKey(layer uint8, index uint64) []byte {
-	k := make([]byte, 1+1+8)
+	return putTreeKey(make([]byte, treeKeySize), layer, index)
+}
+
+const treeKeySize = 1 + 1 + 8
+
+// putTreeKey encodes the key of a tree node into k, which must be treeKeySize long
+func putTreeKey(k []byte, layer uint8, index uint64) []byte {
 	k[0] = tsPrefix
 	k[1] = layer
 	binary.BigEndian.PutUint64(k[2:], index)
@@ -66,10 +90,13 @@ func decodeTreeKey(k []byte) (layer uint8, index uint64) {
 
 // refTreeKey appends a key of a badger value to an hash
 func refTreeKey(hash [sha256.Size]byte, reference []byte) []byte {
-	kl := len(reference)
-	c := make([]byte, sha256.Size+kl)
+	return putRefTreeKey(make([]byte, sha256.Size+len(reference)), hash, reference)
+}
+
+// putRefTreeKey is like refTreeKey, encoding into c, which must be sha256.Size+len(reference) long
+func putRefTreeKey(c []byte, hash [sha256.Size]byte, reference []byte) []byte {
 	copy(c[:sha256.Size], hash[:])
-	copy(c[sha256.Size:], reference[:])
+	copy(c[sha256.Size:], reference)
 	return c
 }
 
@@ -127,6 +154,8 @@ type treeStoreEntry struct {
 	ts uint64
 	h  *[sha256.Size]byte
 	r  *[]byte
+	// t is the commit time as unix nanoseconds
+	t int64
 }
 
 func (t treeStoreEntry) Index() uint64 {
@@ -154,6 +183,29 @@ type treeStore struct {
 	cSize        uint64
 	sync.RWMutex
 	closeOnce sync.Once
+
+	// leaseMu makes leased indexes and commit times increase together
+	leaseMu  sync.Mutex
+	clock    clock.Clock
+	lastTime int64
+
+	// checkpointInterval is the number of entries after which the tree is flushed, bounding the replay at startup
+	checkpointInterval uint64
+	// syncOnFlush makes every flush durable, even if writes are not synced
+	syncOnFlush bool
+
+	// gate, if set, can hold committed entries before they are added to the tree, see commitGate
+	gate commitGate
+}
+
+// commitGate controls when committed entries are added to the tree. It's only set by tests built with the storetest tag,
+// see CommitScheduler.
+type commitGate interface {
+	attach(t *treeStore)
+	// hold returns true if entry is held, to be sent to the tree later
+	hold(entry *treeStoreEntry) bool
+	// releaseAll sends all the held entries to the tree
+	releaseAll()
 }
 
 func newTreeStore(db *badger.DB, cacheSize uint64, flushLeaves bool, log logger.Logger) (*treeStore, error) {
@@ -167,7 +219,9 @@ func newTreeStore(db *badger.DB, cacheSize uint64, flushLeaves bool, log logger.
 		flushLeaves: flushLeaves,
 		cPos:        [256]uint64{},
 		cSize:       cacheSize,
+		clock:       clock.System(),
 	}
+	t.checkpointInterval = cacheSize / 2
 
 	t.makeCaches()
 
@@ -186,34 +240,154 @@ func newTreeStore(db *badger.DB, cacheSize uint64, flushLeaves bool, log logger.
 
 func (t *treeStore) loadTreeState() error {
 	return t.db.View(func(txn *badger.Txn) error {
-		for l := 0; l < 256; l++ {
-			w := treeLayerWidth(uint8(l), txn)
-			if w == 0 {
-				break
+		lastFlushed, err := loadLastFlushed(txn)
+		if err != nil {
+			return err
+		}
+		t.lastFlushed = lastFlushed
+
+		// the checkpoint spares seeking the width of every layer and reading the frontier from disk,
+		// it's ignored if it was not written along with the last flush (e.g. by an older version)
+		checkpoint, err := loadTreeCheckpoint(txn)
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil && checkpoint.widths[0] == lastFlushed {
+			for l, w := range checkpoint.widths {
+				t.cPos[l] = w
+				frontier := checkpoint.frontier[l]
+				t.caches[l].Set(w-1, &frontier)
+			}
+			// leaves are written along with their entries, there may be more than the flushed ones
+			t.cPos[0] = treeLayerWidth(0, txn)
+		} else {
+			for l := 0; l < 256; l++ {
+				w := treeLayerWidth(uint8(l), txn)
+				if w == 0 {
+					break
+				}
+				t.cPos[l] = w
 			}
-			t.cPos[l] = w
 		}
 		t.w = t.cPos[0]
 		t.ts = t.w
 
-		i, err := txn.Get([]byte(lastFlushedMetaKey))
-
-		if err == nil {
-			bs, err := i.ValueCopy(nil)
-			if err != nil {
+		if t.w > 0 {
+			i, err := txn.Get(timeKey(t.w - 1))
+			if err == nil {
+				if err = i.Value(func(v []byte) error {
+					t.lastTime = decodeTime(v)
+					return nil
+				}); err != nil {
+					return err
+				}
+			} else if err != badger.ErrKeyNotFound {
 				return err
 			}
-			t.lastFlushed = binary.BigEndian.Uint64(bs)
-			return nil
 		}
+		return nil
+	})
+}
+
+func loadLastFlushed(txn *badger.Txn) (uint64, error) {
+	i, err := txn.Get([]byte(lastFlushedMetaKey))
+	if err == badger.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	bs, err := i.ValueCopy(nil)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(bs), nil
+}
+
+// treeCheckpoint is the state of the tree at a flush: the width of every layer and its last node,
+// i.e. the frontier new leaves are appended to. Inner nodes are persisted by the flush itself.
+type treeCheckpoint struct {
+	widths   []uint64
+	frontier [][sha256.Size]byte
+}
+
+// encodeTreeCheckpoint encodes the checkpoint as the sequence of the width and the last node of every layer
+func encodeTreeCheckpoint(c *treeCheckpoint) []byte {
+	b := make([]byte, 0, len(c.widths)*(8+sha256.Size))
+	for l, w := range c.widths {
+		var bw [8]byte
+		binary.BigEndian.PutUint64(bw[:], w)
+		b = append(b, bw[:]...)
+		b = append(b, c.frontier[l][:]...)
+	}
+	return b
+}
+
+func decodeTreeCheckpoint(b []byte) (*treeCheckpoint, error) {
+	if len(b) == 0 || len(b)%(8+sha256.Size) != 0 {
+		return nil, ErrInconsistentState
+	}
+	c := &treeCheckpoint{}
+	for ; len(b) > 0; b = b[8+sha256.Size:] {
+		w := binary.BigEndian.Uint64(b)
+		if w == 0 {
+			return nil, ErrInconsistentState
+		}
+		var h [sha256.Size]byte
+		copy(h[:], b[8:8+sha256.Size])
+		c.widths = append(c.widths, w)
+		c.frontier = append(c.frontier, h)
+	}
+	return c, nil
+}
 
-		if err == badger.ErrKeyNotFound {
-			t.lastFlushed = 0
+// loadTreeCheckpoint returns the last checkpoint of the tree, or nil if none was written yet
+func loadTreeCheckpoint(txn *badger.Txn) (*treeCheckpoint, error) {
+	i, err := txn.Get([]byte(treeCheckpointMetaKey))
+	if err == badger.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, err := i.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTreeCheckpoint(b)
+}
+
+// checkpoint returns the current checkpoint of the tree, _t_ must be locked
+func (t *treeStore) checkpoint() *treeCheckpoint {
+	c := &treeCheckpoint{}
+	for l := range t.caches {
+		w := t.caches[l].Tail()
+		if w == 0 {
+			// the layer has not been changed since the tree has been loaded
+			w = t.cPos[l]
+		}
+		if w == 0 {
+			break
+		}
+		h := t.Get(uint8(l), w-1)
+		if h == nil {
 			return nil
 		}
+		c.widths = append(c.widths, w)
+		c.frontier = append(c.frontier, *h)
+	}
+	return c
+}
 
-		return err
-	})
+// setCheckpointInterval sets the number of entries after which the tree is flushed, returning the one set: it's
+// bounded by half the size of the caches, since the nodes evicted from the caches before being flushed would
+// never be persisted.
+func (t *treeStore) setCheckpointInterval(entries uint64) uint64 {
+	if max := t.cSize / 2; entries > max {
+		entries = max
+	}
+	t.checkpointInterval = entries
+	return entries
 }
 
 func (t *treeStore) makeCaches() {
@@ -241,6 +415,9 @@ func (t *treeStore) Close() {
 func (t *treeStore) close(flushPending bool) {
 	t.closeOnce.Do(func() {
 		if t.quit != nil {
+			if t.gate != nil {
+				t.gate.releaseAll()
+			}
 			t.flushOnClose = flushPending
 			close(t.c)
 			<-t.quit
@@ -274,12 +451,26 @@ func (t *treeStore) LastIndex() uint64 {
 // NewEntry acquires a lease for a new entry and returns it. The entry must be used with Commit() or Discard().
 // It's thread-safe.
 func (t *treeStore) NewEntry(key []byte, value []byte) *treeStoreEntry {
-	ts := atomic.AddUint64(&t.ts, 1)
+	ts, now := t.lease(1)
 	h := api.Digest(ts-1, key, value)
 	return &treeStoreEntry{
 		ts: ts,
 		h:  &h,
 		r:  &key,
+		t:  now,
+	}
+}
+
+// NewTombstoneEntry is like NewEntry, for the tombstone marking key as deleted.
+// It's thread-safe.
+func (t *treeStore) NewTombstoneEntry(key []byte) *treeStoreEntry {
+	ts, now := t.lease(1)
+	h := api.TombstoneDigest(ts-1, key)
+	return &treeStoreEntry{
+		ts: ts,
+		h:  &h,
+		r:  &key,
+		t:  now,
 	}
 }
 
@@ -288,21 +479,70 @@ func (t *treeStore) NewEntry(key []byte, value []byte) *treeStoreEntry {
 func (t *treeStore) NewBatch(kvPairs *schema.KVList) []*treeStoreEntry {
 	size := uint64(len(kvPairs.KVs))
 	batch := make([]*treeStoreEntry, 0, size)
-	lease := atomic.AddUint64(&t.ts, size)
+	// the entries and their hashes are allocated all at once
+	entries := make([]treeStoreEntry, size)
+	hashes := make([][sha256.Size]byte, size)
+	lease, now := t.lease(size)
 	for i, kv := range kvPairs.KVs {
 		ts := lease - size + uint64(i) + 1
-		h := api.Digest(ts-1, kv.Key, kv.Value)
-		batch = append(batch, &treeStoreEntry{ts, &h, &kv.Key})
+		hashes[i] = api.Digest(ts-1, kv.Key, kv.Value)
+		entries[i] = treeStoreEntry{ts, &hashes[i], &kv.Key, now}
+		batch = append(batch, &entries[i])
 	}
 	return batch
 }
 
-// NewOpsTsRange return the initial ts to be used inside batch ops
+// NewOpsTsRange reserves size timestamps for the entries of batch ops, returning the one preceding them along with
+// their commit time.
 // It's thread-safe.
-func (t *treeStore) NewOpsTsRange(ops *schema.Ops) uint64 {
-	size := uint64(len(ops.Operations))
-	lease := atomic.AddUint64(&t.ts, size)
-	return lease - size
+func (t *treeStore) NewOpsTsRange(size uint64) (uint64, int64) {
+	lease, now := t.lease(size)
+	return lease - size, now
+}
+
+// lease reserves n timestamps, returning the last one along with the commit time shared by all of them.
+// Commit times never decrease as timestamps increase, even if the clock is stepped back.
+func (t *treeStore) lease(n uint64) (uint64, int64) {
+	t.leaseMu.Lock()
+	defer t.leaseMu.Unlock()
+	lease := atomic.AddUint64(&t.ts, n)
+	now := t.clock.Now().UnixNano()
+	if now < t.lastTime {
+		now = t.lastTime
+	}
+	t.lastTime = now
+	return lease, now
+}
+
+// setLeafEntry writes the leaf of entry along with its commit time.
+// txn retains the entries until it's committed, so they can't be pooled: they are carved out of a single arena
+// instead of being allocated one by one, since every write sets them.
+func setLeafEntry(txn *badger.Txn, entry *treeStoreEntry) error {
+	rl := sha256.Size + len(*entry.r)
+	arena := make([]byte, treeKeySize+rl+treeKeySize+8)
+	entries := new([2]badger.Entry)
+
+	entries[0] = badger.Entry{
+		Key:      putTreeKey(arena[:treeKeySize], 0, entry.ts-1),
+		Value:    putRefTreeKey(arena[treeKeySize:treeKeySize+rl], *entry.h, *entry.r),
+		UserMeta: bitTreeEntry,
+	}
+	arena = arena[treeKeySize+rl:]
+	binary.BigEndian.PutUint64(arena[treeKeySize:], uint64(entry.t))
+	entries[1] = badger.Entry{
+		Key:      putTreeKey(arena[:treeKeySize], timeLayer, entry.ts-1),
+		Value:    arena[treeKeySize:],
+		UserMeta: bitTreeEntry,
+	}
+
+	if err := txn.SetEntry(&entries[0]); err != nil {
+		return err
+	}
+	return txn.SetEntry(&entries[1])
+}
+
+func decodeTime(v []byte) int64 {
+	return int64(binary.BigEndian.Uint64(v))
 }
 
 // Commit enqueues the given entry to be included in the merkletree.
@@ -310,6 +550,9 @@ func (t *treeStore) NewOpsTsRange(ops *schema.Ops) uint64 {
 // so it must not be used later.
 // It's thread-safe. Commit will fail if called after Close().
 func (t *treeStore) Commit(entry *treeStoreEntry) {
+	if t.gate != nil && t.gate.hold(entry) {
+		return
+	}
 	t.c <- entry
 }
 
@@ -320,6 +563,9 @@ func (t *treeStore) Commit(entry *treeStoreEntry) {
 func (t *treeStore) Discard(entry *treeStoreEntry) {
 	h := api.Digest(entry.ts, []byte{}, []byte{})
 	entry.h = &h
+	if t.gate != nil && t.gate.hold(entry) {
+		return
+	}
 	t.c <- entry
 }
 
@@ -340,7 +586,7 @@ func (t *treeStore) worker() {
 			t.rcache.Set(item.ts-1, c)
 
 			merkletree.AppendHash(t, item.h)
-			if t.w%2 == 0 && (t.w-t.lastFlushed) >= t.cSize/2 {
+			if t.w%2 == 0 && (t.w-t.lastFlushed) >= t.checkpointInterval {
 				t.flush()
 			}
 		}
@@ -382,6 +628,12 @@ func (t *treeStore) flush() {
 				t.log.Errorf("Tree flush error: %s", err)
 				return
 			}
+			if t.syncOnFlush {
+				// the flush has been written anyway, a failed sync only makes the replay at startup longer
+				if err = t.db.Sync(); err != nil {
+					t.log.Errorf("Tree sync error: %s", err)
+				}
+			}
 			advance()
 		}
 	}()
@@ -438,6 +690,22 @@ func (t *treeStore) flush() {
 			cancel = true
 			return
 		}
+
+		// the checkpoint is written in the same batch, so that it's always consistent with the last flushed leaf
+		if checkpoint := t.checkpoint(); checkpoint != nil {
+			entry := badger.Entry{
+				Key:   []byte(treeCheckpointMetaKey),
+				Value: encodeTreeCheckpoint(checkpoint),
+			}
+			entry.WithDiscard()
+
+			if err := wb.SetEntry(&entry); err != nil {
+				t.log.Errorf("Cannot flush tree checkpoint: %v", err)
+				t.log.Warningf("Tree flush canceled")
+				cancel = true
+				return
+			}
+		}
 	}
 }
 
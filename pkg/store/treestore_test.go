@@ -17,12 +17,19 @@ limitations under the License.
 package store
 
 import (
+	"crypto/sha256"
+	"math"
 	"os"
+	"strconv"
 	"testing"
 
+	"github.com/codenotary/immudb/pkg/api/schema"
 	"github.com/codenotary/immudb/pkg/logger"
+	"github.com/codenotary/merkletree"
+	"github.com/dgraph-io/badger/v2"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTreeStore(t *testing.T) {
@@ -54,3 +61,137 @@ func TestNewTreeStore(t *testing.T) {
 
 	ts.Close()
 }
+
+func TestTreeCheckpointIntervalBound(t *testing.T) {
+	db := makeBadger()
+	defer db.Close()
+	log := logger.NewSimpleLoggerWithLevel("test", os.Stderr, logger.LogDebug)
+
+	ts, err := newTreeStore(db.DB, 128, true, log)
+	require.NoError(t, err)
+	// a larger interval would let nodes be evicted from the caches before being flushed
+	require.Equal(t, uint64(64), ts.setCheckpointInterval(1000))
+
+	for i := 0; i < 1000; i++ {
+		ts.Commit(ts.NewEntry([]byte("key"), []byte(strconv.Itoa(i))))
+	}
+	ts.WaitUntil(999)
+	root := merkletree.Root(ts)
+	ts.Close()
+	db.Restart()
+
+	ts, err = newTreeStore(db.DB, 128, true, log)
+	require.NoError(t, err)
+	defer ts.Close()
+	require.Equal(t, uint64(1000), ts.Width())
+	require.Equal(t, root, merkletree.Root(ts))
+}
+
+func TestTreeCheckpointEncoding(t *testing.T) {
+	c := &treeCheckpoint{
+		widths:   []uint64{5, 2, 1},
+		frontier: [][sha256.Size]byte{{1}, {2}, {3}},
+	}
+	decoded, err := decodeTreeCheckpoint(encodeTreeCheckpoint(c))
+	require.NoError(t, err)
+	require.Equal(t, c, decoded)
+
+	_, err = decodeTreeCheckpoint(nil)
+	require.Equal(t, ErrInconsistentState, err)
+	_, err = decodeTreeCheckpoint(encodeTreeCheckpoint(c)[1:])
+	require.Equal(t, ErrInconsistentState, err)
+	c.widths[1] = 0
+	_, err = decodeTreeCheckpoint(encodeTreeCheckpoint(c))
+	require.Equal(t, ErrInconsistentState, err)
+}
+
+func TestTreeCheckpoint(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	opts = opts.WithTreeCheckpointInterval(16)
+
+	st, err := Open(opts, badgerOpts)
+	require.NoError(t, err)
+	for n := uint64(0); n <= 64; n++ {
+		key := []byte(strconv.FormatUint(n, 10))
+		_, err := st.Set(schema.KeyValue{Key: key, Value: key})
+		require.NoError(t, err)
+	}
+	st.tree.WaitUntil(64)
+	st.tree.RLock()
+	require.Equal(t, uint64(64), st.tree.lastFlushed)
+	st.tree.RUnlock()
+
+	// the last entry is replayed at startup, after a crash
+	st.tree.close(false)
+	require.NoError(t, st.Close())
+
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	st.tree.WaitUntil(64)
+	require.Equal(t, root64th, merkletree.Root(st.tree))
+
+	st.FlushToDisk()
+	var checkpoint *treeCheckpoint
+	require.NoError(t, st.db.View(func(txn *badger.Txn) error {
+		checkpoint, err = loadTreeCheckpoint(txn)
+		return err
+	}))
+	require.Equal(t, uint64(65), checkpoint.widths[0])
+	require.Len(t, checkpoint.widths, merkletree.Depth(st.tree)+1)
+	require.Equal(t, st.tree.checkpoint(), checkpoint)
+	require.Equal(t, root64th, checkpoint.frontier[len(checkpoint.frontier)-1])
+
+	// without a checkpoint, e.g. written by an older version, the tree is loaded from its layers
+	w := st.tree.w
+	require.NoError(t, st.Close())
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	txn := st.db.NewTransactionAt(math.MaxUint64, true)
+	require.NoError(t, txn.Delete([]byte(treeCheckpointMetaKey)))
+	require.NoError(t, txn.CommitAt(w+1, nil))
+	st.tree.close(false)
+	require.NoError(t, st.Close())
+
+	st, err = Open(opts, badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	require.Equal(t, w, st.tree.Width())
+	require.Equal(t, root64th, merkletree.Root(st.tree))
+}
+
+func TestTreeSync(t *testing.T) {
+	dir := tmpDir()
+	defer os.RemoveAll(dir)
+	opts, badgerOpts := DefaultOptions(dir, logger.NewSimpleLogger("test", os.Stderr))
+	opts = opts.WithTreeCheckpointInterval(16).WithTreeSync(true)
+
+	st, err := Open(opts, badgerOpts)
+	require.NoError(t, err)
+	// the tree is synced at every checkpoint, while writes are not
+	require.False(t, st.badgerOpts.SyncWrites)
+	require.True(t, st.tree.syncOnFlush)
+	for n := uint64(0); n <= 64; n++ {
+		key := []byte(strconv.FormatUint(n, 10))
+		_, err := st.Set(schema.KeyValue{Key: key, Value: key})
+		require.NoError(t, err)
+	}
+	st.tree.WaitUntil(64)
+	st.tree.RLock()
+	require.Equal(t, uint64(64), st.tree.lastFlushed)
+	st.tree.RUnlock()
+	st.tree.close(false)
+	require.NoError(t, st.Close())
+
+	// synced writes make the checkpoints durable as well
+	st, err = Open(opts.WithSyncWrites(true).WithTreeSync(false), badgerOpts)
+	require.NoError(t, err)
+	defer st.Close()
+	require.True(t, st.badgerOpts.SyncWrites)
+	require.False(t, st.tree.syncOnFlush)
+	st.tree.WaitUntil(64)
+	require.Equal(t, root64th, merkletree.Root(st.tree))
+	_, err = st.Set(schema.KeyValue{Key: []byte(`synced`), Value: []byte(`synced`)})
+	require.NoError(t, err)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/merkletree"
+	"github.com/dgraph-io/badger/v2"
+)
+
+// txLayer prefixes the keys of the entries committing transactions, like frozenLayer it's out of the range of the tree layers
+const txLayer = uint8(252)
+
+// txKey returns the key of the entry committing the transaction made of the entries from first to last index
+func txKey(first, last uint64) []byte {
+	k := make([]byte, 1+1+8+8)
+	k[0] = tsPrefix
+	k[1] = txLayer
+	binary.BigEndian.PutUint64(k[2:], first)
+	binary.BigEndian.PutUint64(k[2+8:], last)
+	return k
+}
+
+func isTxKey(key []byte) bool {
+	return len(key) == 1+1+8+8 && key[0] == tsPrefix && key[1] == txLayer
+}
+
+// TxRange returns the indexes of the first and last entries of the transaction committed by the entry having key
+func TxRange(key []byte) (first, last uint64, ok bool) {
+	if !isTxKey(key) {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint64(key[2:]), binary.BigEndian.Uint64(key[2+8:]), true
+}
+
+// newTxCommitEntry returns the entry at ts committing the transaction made of entries, along with its value:
+// the digest of their leaves
+func newTxCommitEntry(ts uint64, entries []*treeStoreEntry, now int64) (*treeStoreEntry, []byte) {
+	leaves := make([][sha256.Size]byte, len(entries))
+	for i, e := range entries {
+		leaves[i] = *e.h
+	}
+	digest := api.TxDigest(leaves)
+	key := txKey(entries[0].Index(), entries[len(entries)-1].Index())
+	h := api.Digest(ts-1, key, digest[:])
+	return &treeStoreEntry{ts: ts, h: &h, r: &key, t: now}, digest[:]
+}
+
+// ExecAllTx is like ExecAllOps, with the entries committed as a transaction: an entry appended after them holds the
+// digest of their leaves, so that the inclusion of the whole transaction is proven by the one of its commit entry.
+// The returned index is the one of the commit entry.
+func (t *Store) ExecAllTx(ops *schema.Ops, options ...WriteOption) (index *schema.Index, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.execAllOps(ops, true, nil, options...)
+}
+
+// CompareAndExecAllTx commits a transaction like ExecAllTx, provided that each of the expected keys is at the expected
+// index, resolving references like Get. It fails with ErrTxConflict if any of them is not, or if it's written by
+// another commit before the transaction is committed.
+func (t *Store) CompareAndExecAllTx(options schema.CompareAndExecAllTxOptions, opts ...WriteOption) (index *schema.Index, err error) {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.execAllOps(options.Ops, true, options.Expected, opts...)
+}
+
+// snapshotTxn returns a write transaction reading the store as it is at the last leased index, once the writes up to
+// it are done. Every later write commits at a greater version, so the commit of the transaction fails with
+// badger.ErrConflict if any of the keys it has read is written meanwhile.
+func (t *Store) snapshotTxn() *badger.Txn {
+	ts := atomic.LoadUint64(&t.tree.ts)
+	if ts > 0 {
+		t.tree.WaitUntil(ts - 1)
+	}
+	return t.db.NewTransactionAt(ts, true)
+}
+
+// checkExpected fails with ErrTxConflict unless each of the expected keys, read with txn, is at the expected index
+func (t *Store) checkExpected(txn *badger.Txn, expected []*schema.ExpectedIndex) error {
+	for _, e := range expected {
+		if err := checkKey(e.GetKey()); err != nil {
+			return err
+		}
+		i, err := t.get(txn, e.Key)
+		if err != nil && err != ErrKeyNotFound {
+			return err
+		}
+		if err == ErrKeyNotFound {
+			if e.Index != nil {
+				return ErrTxConflict
+			}
+			continue
+		}
+		if e.Index == nil {
+			return ErrTxConflict
+		}
+		// the entries written together share the same version, the index is the one in the value
+		item, err := itemToSchema(i.Key(), i)
+		if err != nil {
+			return err
+		}
+		if item.Index != e.Index.Index {
+			return ErrTxConflict
+		}
+	}
+	return nil
+}
+
+// SafeExecAllTx commits a transaction like ExecAllTx, returning its entries along with the commit entry,
+// the inclusion proof for it and the consistency proof for the previous root
+func (t *Store) SafeExecAllTx(options schema.SafeExecAllTxOptions) (txProof *schema.TxProof, err error) {
+	prevRootIdx, err := getPrevRootIdx(t.tree.LastIndex(), options.RootIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := t.ExecAllTx(options.Ops)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := t.itemAt(index.Index + 1)
+	if err != nil {
+		return nil, err
+	}
+	first, last, _ := TxRange(commit.Key)
+	txProof = &schema.TxProof{
+		Commit:  commit,
+		Entries: make([]*schema.Item, 0, last-first+1),
+	}
+	for i := first; i <= last; i++ {
+		item, err := t.itemAt(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		txProof.Entries = append(txProof.Entries, item)
+	}
+
+	t.tree.WaitUntil(index.Index)
+
+	t.tree.RLock()
+	defer t.tree.RUnlock()
+
+	at := t.tree.w - 1
+	root := merkletree.Root(t.tree)
+
+	txProof.Proof = &schema.Proof{
+		Leaf:            commit.Hash(),
+		Index:           index.Index,
+		Root:            root[:],
+		At:              at,
+		InclusionPath:   merkletree.InclusionProof(t.tree, at, index.Index).ToSlice(),
+		ConsistencyPath: merkletree.ConsistencyProof(t.tree, at, prevRootIdx).ToSlice(),
+		Sequence:        index.Sequence,
+	}
+
+	return txProof, nil
+}
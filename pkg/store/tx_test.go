@@ -0,0 +1,172 @@
+/*
+Copyright 2019-2020 vChain, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"crypto/sha256"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/codenotary/immudb/pkg/api"
+	"github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecAllTx(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	_, err := st.Set(schema.KeyValue{Key: []byte(`before`), Value: []byte(`value`)})
+	require.NoError(t, err)
+
+	ops := &schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`account1`), Value: []byte(`90`)}}},
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`account2`), Value: []byte(`110`)}}},
+		{Operation: &schema.Op_ROpts{ROpts: &schema.ReferenceOptions{Reference: []byte(`last`), Key: []byte(`account2`)}}},
+		{Operation: &schema.Op_ZOpts{ZOpts: &schema.ZAddOptions{Set: []byte(`accounts`), Score: &schema.Score{Score: 1}, Key: []byte(`account1`)}}},
+	}}
+	txProof, err := st.SafeExecAllTx(schema.SafeExecAllTxOptions{Ops: ops})
+	require.NoError(t, err)
+
+	// the operations take the indexes 1 to 4, the commit entry the following one
+	require.Equal(t, uint64(5), txProof.Proof.Index)
+	require.Equal(t, uint64(5), txProof.Commit.Index)
+	first, last, ok := TxRange(txProof.Commit.Key)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), first)
+	require.Equal(t, uint64(4), last)
+	require.Len(t, txProof.Entries, 4)
+	require.Equal(t, []byte(`account1`), txProof.Entries[0].Key)
+	require.Equal(t, []byte(`90`), txProof.Entries[0].Value)
+
+	// the single proof of the commit entry covers the whole transaction
+	leaves := make([][sha256.Size]byte, len(txProof.Entries))
+	for i, item := range txProof.Entries {
+		copy(leaves[i][:], item.Hash())
+	}
+	digest := api.TxDigest(leaves)
+	require.Equal(t, digest[:], txProof.Commit.Value)
+	require.Equal(t, txProof.Commit.Hash(), txProof.Proof.Leaf)
+	require.True(t, txProof.Proof.Verify(txProof.Commit.Hash(), schema.Root{}))
+
+	item, err := st.Get(schema.Key{Key: []byte(`last`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`110`), item.Value)
+	zList, err := st.ZScan(schema.ZScanOptions{Set: []byte(`accounts`)})
+	require.NoError(t, err)
+	require.Len(t, zList.Items, 1)
+
+	// the commit entry can be read by index like any other entry
+	commit, err := st.ByIndex(schema.Index{Index: txProof.Commit.Index})
+	require.NoError(t, err)
+	require.Equal(t, txProof.Commit, commit)
+	txn := st.db.NewTransactionAt(math.MaxUint64, false)
+	defer txn.Discard()
+	record, err := txn.Get(txProof.Commit.Key)
+	require.NoError(t, err)
+	require.Equal(t, bitChecksummedEntry, record.UserMeta()&bitChecksummedEntry)
+
+	entries, leafCount := st.CountEntriesAndLeaves()
+	require.Equal(t, leafCount, entries)
+
+	index, err := st.ExecAllTx(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`account1`), Value: []byte(`80`)}}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), index.Index)
+
+	// nothing is written if an operation fails
+	_, err = st.ExecAllTx(&schema.Ops{Operations: []*schema.Op{
+		{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`account3`), Value: []byte(`0`)}}},
+		{Operation: &schema.Op_ZOpts{ZOpts: &schema.ZAddOptions{Set: []byte(`accounts`), Score: &schema.Score{Score: 1}, Key: []byte(`missing`)}}},
+	}})
+	require.Error(t, err)
+	_, err = st.Get(schema.Key{Key: []byte(`account3`)})
+	require.Equal(t, ErrKeyNotFound, err)
+	_, err = st.ExecAllTx(&schema.Ops{})
+	require.Error(t, err)
+}
+
+func TestCompareAndExecAllTx(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	index, err := st.Set(schema.KeyValue{Key: []byte(`balance`), Value: []byte(`100`)})
+	require.NoError(t, err)
+	_, err = st.Reference(&schema.ReferenceOptions{Reference: []byte(`current`), Key: []byte(`balance`)})
+	require.NoError(t, err)
+
+	withdraw := func(value string, expected ...*schema.ExpectedIndex) (*schema.Index, error) {
+		return st.CompareAndExecAllTx(schema.CompareAndExecAllTxOptions{
+			Ops: &schema.Ops{Operations: []*schema.Op{
+				{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`balance`), Value: []byte(value)}}},
+				{Operation: &schema.Op_KVs{KVs: &schema.KeyValue{Key: []byte(`withdrawal`), Value: []byte(`10`)}}},
+			}},
+			Expected: expected,
+		})
+	}
+
+	// references are resolved, like Get does
+	commit, err := withdraw(`90`,
+		&schema.ExpectedIndex{Key: []byte(`current`), Index: index},
+		&schema.ExpectedIndex{Key: []byte(`withdrawal`)})
+	require.NoError(t, err)
+	item, err := st.ByIndex(*commit)
+	require.NoError(t, err)
+	first, last, ok := TxRange(item.Key)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), first)
+	require.Equal(t, uint64(3), last)
+
+	// the expected indexes are not the current ones anymore
+	_, err = withdraw(`80`, &schema.ExpectedIndex{Key: []byte(`balance`), Index: index})
+	require.Equal(t, ErrTxConflict, err)
+	_, err = withdraw(`80`, &schema.ExpectedIndex{Key: []byte(`withdrawal`)})
+	require.Equal(t, ErrTxConflict, err)
+	_, err = withdraw(`80`, &schema.ExpectedIndex{Key: []byte(`missing`), Index: &schema.Index{Index: 1}})
+	require.Equal(t, ErrTxConflict, err)
+	_, err = withdraw(`80`, &schema.ExpectedIndex{Key: []byte{tsPrefix}})
+	require.Equal(t, ErrInvalidKey, err)
+
+	item, err = st.Get(schema.Key{Key: []byte(`balance`)})
+	require.NoError(t, err)
+	require.Equal(t, []byte(`90`), item.Value)
+
+	_, err = withdraw(`80`, &schema.ExpectedIndex{Key: []byte(`balance`), Index: &schema.Index{Index: item.Index}})
+	require.NoError(t, err)
+}
+
+func TestSnapshotTxnConflict(t *testing.T) {
+	st, closer := makeStore()
+	defer closer()
+
+	index, err := st.Set(schema.KeyValue{Key: []byte(`key1`), Value: []byte(`value1`)})
+	require.NoError(t, err)
+
+	expected := []*schema.ExpectedIndex{{Key: []byte(`key1`), Index: index}}
+	txn := st.snapshotTxn()
+	defer txn.Discard()
+	require.NoError(t, st.checkExpected(txn, expected))
+
+	// a write committed after the snapshot fails the commit of the transaction
+	_, err = st.Set(schema.KeyValue{Key: []byte(`key1`), Value: []byte(`value2`)})
+	require.NoError(t, err)
+	require.NoError(t, txn.SetEntry(&badger.Entry{Key: []byte(`key2`), Value: []byte(`value`)}))
+	require.Equal(t, badger.ErrConflict, txn.CommitAt(atomic.LoadUint64(&st.tree.ts)+1, nil))
+}